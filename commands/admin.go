@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/boltdb"
+)
+
+var adminCmd = &cobra.Command{
+	Use:   "admin <username>",
+	Short: "Give a user access to the admin API",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		remove, _ := cmd.Flags().GetBool("remove")
+
+		db, err := boltdb.New(storage.Path.Database())
+		if err == boltdb.ErrLocked {
+			log.Fatal("dispatch is running, stop it first or use the admins config option")
+		} else if err != nil {
+			log.Fatal(err)
+		}
+		defer db.Close()
+
+		err = storage.SetUserAdmin(db, args[0], !remove)
+		if err == storage.ErrNotFound {
+			log.Println("No user named", args[0])
+		} else if err != nil {
+			log.Println(err)
+		} else if remove {
+			log.Println(args[0], "is no longer an admin")
+		} else {
+			log.Println(args[0], "is now an admin")
+		}
+	},
+}
+
+func init() {
+	adminCmd.Flags().Bool("remove", false, "remove admin access instead")
+}
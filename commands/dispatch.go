@@ -5,14 +5,18 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"os/signal"
 	"runtime"
+	"syscall"
 
 	"github.com/khlieng/dispatch/assets"
 	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/logging"
 	"github.com/khlieng/dispatch/server"
 	"github.com/khlieng/dispatch/storage"
 	"github.com/khlieng/dispatch/storage/bleve"
 	"github.com/khlieng/dispatch/storage/boltdb"
+	"github.com/khlieng/dispatch/storage/memory"
 	"github.com/khlieng/dispatch/version"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -60,23 +64,74 @@ var rootCmd = &cobra.Command{
 		}
 		log.Println("Storing data at", storage.Path.DataRoot())
 
-		db, err := boltdb.New(storage.Path.Database())
+		cfg, cfgUpdated := config.LoadConfig()
+
+		if err := cfg.Log.Configure(logging.Default()); err != nil {
+			log.Fatal(err)
+		}
+		log.SetFlags(0)
+		log.SetOutput(logging.Default().Writer(logging.LevelInfo))
+
+		db, err := openDatabase(cfg.Storage.Database)
 		if err != nil {
 			log.Fatal(err)
 		}
 		defer db.Close()
 
-		storage.GetMessageStore = func(user *storage.User) (storage.MessageStore, error) {
-			return boltdb.New(storage.Path.Log(user.Username))
-		}
+		storage.RegisterMessageStore("bolt", func(user *storage.User) (storage.MessageStore, error) {
+			store, err := boltdb.New(storage.Path.Log(user.Username))
+			if err != nil || !cfg.Encryption.Enabled {
+				return store, err
+			}
+
+			key, err := loadEncryptionKey(user, cfg)
+			if err != nil {
+				store.Close()
+				return nil, err
+			}
+			return storage.NewEncryptedMessageStore(store, key)
+		})
+
+		storage.RegisterMessageSearchProvider("bleve", func(user *storage.User) (storage.MessageSearchProvider, error) {
+			if !cfg.Encryption.Enabled {
+				return bleve.New(storage.Path.Index(user.Username))
+			}
+
+			key, err := loadEncryptionKey(user, cfg)
+			if err != nil {
+				return nil, err
+			}
+			return bleve.NewHashed(storage.Path.EncryptedIndex(user.Username), storage.IndexKey(key))
+		})
+
+		storage.RegisterMessageStore("memory", func(*storage.User) (storage.MessageStore, error) {
+			return memory.New(), nil
+		})
+		storage.RegisterMessageSearchProvider("memory", func(*storage.User) (storage.MessageSearchProvider, error) {
+			return memory.NewSearch(), nil
+		})
 
-		storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
-			return bleve.New(storage.Path.Index(user.Username))
+		if err := storage.UseMessageStore(cfg.Storage.Messages); err != nil {
+			log.Fatal(err)
+		}
+		if err := storage.UseMessageSearchProvider(cfg.Storage.Search); err != nil {
+			log.Fatal(err)
 		}
 
-		cfg, cfgUpdated := config.LoadConfig()
 		dispatch := server.New(cfg)
 
+		storage.GetLimits = func(user *storage.User) storage.Limits {
+			limits := dispatch.Config().Limits.ForUser(user.Username, user.IsAdmin())
+			return storage.Limits{
+				Servers:  limits.MaxServers,
+				Channels: limits.MaxChannels,
+			}
+		}
+
+		storage.GetMessageLimit = func() int {
+			return dispatch.Config().Messages.Limit()
+		}
+
 		go func() {
 			for {
 				dispatch.SetConfig(<-cfgUpdated)
@@ -87,15 +142,76 @@ var rootCmd = &cobra.Command{
 		dispatch.Store = db
 		dispatch.SessionStore = db
 
+		if _, err := cfg.Cookies.SameSiteMode(); err != nil {
+			log.Fatal(err)
+		}
+
+		dispatch.Filters, err = server.NewDropFilters(cfg.Filters.Drop)
+		if err != nil {
+			log.Fatal("Invalid drop filter: ", err)
+		}
+
+		go func() {
+			sig := make(chan os.Signal, 1)
+			signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+			<-sig
+
+			dispatch.Shutdown()
+			os.Exit(0)
+		}()
+
 		dispatch.Run()
 	},
 }
 
+type database interface {
+	storage.Store
+	storage.SessionStore
+	Close()
+}
+
+func openDatabase(name string) (database, error) {
+	switch name {
+	case "", "bolt":
+		return boltdb.New(storage.Path.Database())
+	case "memory":
+		// User IDs start over on every run, so the files of users go in a
+		// temporary data directory where they can't get handed to new users
+		dir, err := ioutil.TempDir("", "dispatch")
+		if err != nil {
+			return nil, err
+		}
+		storage.SetDataRoot(dir)
+
+		log.Println("Using in-memory database, nothing gets persisted")
+		log.Println("Storing user data at", dir)
+		return memoryDatabase{memory.New(), dir}, nil
+	}
+	return nil, fmt.Errorf("Unknown database %q, available: bolt, memory", name)
+}
+
+// memoryDatabase removes the temporary data directory when closed
+type memoryDatabase struct {
+	*memory.MemoryStore
+	dir string
+}
+
+func (db memoryDatabase) Close() {
+	db.MemoryStore.Close()
+	os.RemoveAll(db.dir)
+}
+
+func loadEncryptionKey(user *storage.User, cfg *config.Config) ([]byte, error) {
+	return storage.LoadEncryptionKey(user.Username,
+		cfg.Encryption.Passphrase, cfg.Encryption.PreviousPassphrase)
+}
+
 func Execute() {
 	rootCmd.Execute()
 }
 
 func init() {
+	rootCmd.AddCommand(adminCmd)
 	rootCmd.AddCommand(clearCmd)
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(versionCmd)
@@ -113,13 +229,48 @@ func init() {
 	viper.BindPFlags(rootCmd.Flags())
 
 	viper.SetDefault("verify_certificates", true)
+	viper.SetDefault("userlist_limit", 0)
 	viper.SetDefault("https.enabled", true)
 	viper.SetDefault("https.port", 443)
 	viper.SetDefault("auth.anonymous", true)
 	viper.SetDefault("auth.login", true)
 	viper.SetDefault("auth.registration", true)
+	viper.SetDefault("auth.landing", "app")
+	viper.SetDefault("auth.landing_url", "/login")
 	viper.SetDefault("dcc.enabled", true)
 	viper.SetDefault("dcc.autoget.delete", true)
+	viper.SetDefault("raw_log.max_size", 10)
+	viper.SetDefault("netsplit.window", "2s")
+	viper.SetDefault("netsplit.individual_events", true)
+	viper.SetDefault("messages.max_length", 16384)
+	viper.SetDefault("messages.policy", "truncate")
+	viper.SetDefault("messages.queue_size", 50)
+	viper.SetDefault("messages.paste.max_lines", 5)
+	viper.SetDefault("messages.paste.max_length", 1024)
+	viper.SetDefault("messages.paste.timeout", "10s")
+	viper.SetDefault("pastes.max_size", 524288)
+	viper.SetDefault("pastes.expire", "720h")
+	viper.SetDefault("avatars.url", "https://www.gravatar.com/avatar/{hash}?d=identicon")
+	viper.SetDefault("limits.connect_window", "1m")
+	viper.SetDefault("limits.session_policy", "evict")
+	viper.SetDefault("log.format", "text")
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("compression.assets", 9)
+	viper.SetDefault("compression.index", 6)
+	viper.SetDefault("compression.extensions", []string{".svg", ".ico", ".ttf", ".txt"})
+	viper.SetDefault("link_previews.timeout", "5s")
+	viper.SetDefault("link_previews.max_size", 1024*1024)
+	viper.SetDefault("link_previews.max_concurrent", 4)
+	viper.SetDefault("server_ports.plain", "6667")
+	viper.SetDefault("server_ports.tls", "6697")
+	viper.SetDefault("kill_cooldown", "5m")
+	viper.SetDefault("ignore_duplicate_joins", true)
+	viper.SetDefault("timeouts.dns", "10s")
+	viper.SetDefault("timeouts.connect", "10s")
+	viper.SetDefault("timeouts.tls", "10s")
+	viper.SetDefault("timeouts.registration", "30s")
+	viper.SetDefault("timeouts.ping_interval", "2m")
+	viper.SetDefault("timeouts.ping_timeout", "30s")
 }
 
 func initConfig(configPath string, overwrite bool) error {
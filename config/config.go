@@ -1,9 +1,16 @@
 package config
 
 import (
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/khlieng/dispatch/pkg/logging"
 	"github.com/khlieng/dispatch/storage"
 	"github.com/spf13/viper"
 )
@@ -14,12 +21,58 @@ type Config struct {
 	Dev                bool
 	HexIP              bool
 	VerifyCertificates bool `mapstructure:"verify_certificates"`
+	UserlistLimit      int  `mapstructure:"userlist_limit"`
 	Headers            map[string]string
 	Defaults           Defaults
 	HTTPS              HTTPS
 	LetsEncrypt        LetsEncrypt
 	Auth               Auth
 	DCC                DCC
+	RawLog             RawLog `mapstructure:"raw_log"`
+	Encryption         Encryption
+	Netsplit           Netsplit
+	Filters            Filters
+	LinkPreviews       LinkPreviews `mapstructure:"link_previews"`
+	Messages           Messages
+	WebIRC             []WebIRC `mapstructure:"webirc"`
+	BindAddress        string   `mapstructure:"bind_address"`
+	Bind               []Bind
+	FallbackDelay      time.Duration `mapstructure:"fallback_delay"`
+	PreferIPv4         bool          `mapstructure:"prefer_ipv4"`
+	SASLMechanisms     []string      `mapstructure:"sasl_mechanisms"`
+	Limits             Limits
+	Admins             []string
+	IdleDisconnect     time.Duration `mapstructure:"idle_disconnect"`
+	Proxy              Proxy
+	WebSocket          WebSocket
+	Storage            Storage
+	Cookies            Cookies
+	Compression        Compression
+	Log                Log
+	// IdleDisconnectUsers overrides IdleDisconnect for specific users,
+	// 0 keeps them connected
+	IdleDisconnectUsers map[string]time.Duration `mapstructure:"idle_disconnect_users"`
+	// ServerPorts are used for servers that get added without a port
+	ServerPorts ServerPorts `mapstructure:"server_ports"`
+	// Timeouts are for each phase of connecting to IRC servers
+	Timeouts Timeouts
+	// KillCooldown is how long to wait before reconnecting to a server
+	// after getting killed by an operator, negative turns it off
+	KillCooldown time.Duration `mapstructure:"kill_cooldown"`
+	// IgnoreDuplicateJoins keeps JOINs for channels the user is already
+	// in from sending the scrollback again and getting logged
+	IgnoreDuplicateJoins bool `mapstructure:"ignore_duplicate_joins"`
+	Pastes               Pastes
+	Avatars              Avatars
+}
+
+// IdleDisconnectFor returns how long username can be without sessions
+// before getting disconnected from IRC, 0 means never
+func (c *Config) IdleDisconnectFor(username string) time.Duration {
+	if timeout, ok := c.IdleDisconnectUsers[username]; ok {
+		return timeout
+	}
+	return c.IdleDisconnect
 }
 
 type Defaults struct {
@@ -33,6 +86,39 @@ type Defaults struct {
 	ShowDetails    bool `mapstructure:"show_details"`
 }
 
+type ServerPorts struct {
+	Plain string
+	TLS   string
+	// DetectTLS probes servers added without a port and without TLS
+	// for a TLS handshake on the TLS port
+	DetectTLS bool `mapstructure:"detect_tls"`
+}
+
+// PlainPort returns Plain, or 6667 when it is not set
+func (p ServerPorts) PlainPort() string {
+	if p.Plain == "" {
+		return "6667"
+	}
+	return p.Plain
+}
+
+// TLSPort returns TLS, or 6697 when it is not set
+func (p ServerPorts) TLSPort() string {
+	if p.TLS == "" {
+		return "6697"
+	}
+	return p.TLS
+}
+
+type Timeouts struct {
+	DNS          time.Duration
+	Connect      time.Duration
+	TLS          time.Duration
+	Registration time.Duration
+	PingInterval time.Duration `mapstructure:"ping_interval"`
+	PingTimeout  time.Duration `mapstructure:"ping_timeout"`
+}
+
 type HTTPS struct {
 	Enabled bool
 	Port    string
@@ -57,7 +143,12 @@ type Auth struct {
 	Anonymous    bool
 	Login        bool
 	Registration bool
-	Providers    map[string]Provider
+	// Landing is what visitors without a session get instead of the
+	// client, "app" serves the client anyway, "redirect" sends them to
+	// LandingURL and "page" serves a minimal page linking to it
+	Landing    string
+	LandingURL string `mapstructure:"landing_url"`
+	Providers  map[string]Provider
 }
 
 type Provider struct {
@@ -66,8 +157,296 @@ type Provider struct {
 }
 
 type DCC struct {
+	Enabled      bool
+	ExternalIP   string `mapstructure:"external_ip"`
+	PortRange    string `mapstructure:"port_range"`
+	MaxDownloads int    `mapstructure:"max_downloads"`
+	// MaxSpeed and MaxTotalSpeed are in KiB/s, 0 means unlimited
+	MaxSpeed      int    `mapstructure:"max_speed"`
+	MaxTotalSpeed int    `mapstructure:"max_total_speed"`
+	CacheControl  string `mapstructure:"cache_control"`
+	// DownloadsOrigin serves downloads from a separate origin, like
+	// https://downloads.example.com, that points to the same server
+	DownloadsOrigin string `mapstructure:"downloads_origin"`
+	Autoget         Autoget
+}
+
+// Ports parses PortRange, an empty range means any free port
+func (d DCC) Ports() (int, int, error) {
+	if d.PortRange == "" {
+		return 0, 0, nil
+	}
+
+	parts := strings.SplitN(d.PortRange, "-", 2)
+	low, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid DCC port range %s", d.PortRange)
+	}
+	high := low
+	if len(parts) == 2 {
+		high, err = strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return 0, 0, fmt.Errorf("Invalid DCC port range %s", d.PortRange)
+		}
+	}
+
+	if low < 1 || high > 65535 || low > high {
+		return 0, 0, fmt.Errorf("Invalid DCC port range %s", d.PortRange)
+	}
+
+	return low, high, nil
+}
+
+type RawLog struct {
 	Enabled bool
-	Autoget Autoget
+	// MaxSize is the size in megabytes at which a log gets rotated
+	MaxSize int `mapstructure:"max_size"`
+}
+
+// Storage selects the backends used for message logs and search by the
+// names they are registered with
+type Storage struct {
+	// Database stores users, their settings and sessions, "bolt" or "memory"
+	Database string
+	Messages string
+	Search   string
+	// CompactInterval is how often the bolt files get compacted,
+	// 0 disables it
+	CompactInterval time.Duration `mapstructure:"compact_interval"`
+}
+
+// Cookies sets attributes of the session and push cookies
+type Cookies struct {
+	Domain string
+	// SameSite is "lax", "strict" or "none"
+	SameSite string `mapstructure:"same_site"`
+}
+
+// SameSiteMode parses SameSite, it defaults to lax
+func (c Cookies) SameSiteMode() (http.SameSite, error) {
+	switch strings.ToLower(c.SameSite) {
+	case "", "lax":
+		return http.SameSiteLaxMode, nil
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	}
+	return http.SameSiteLaxMode, fmt.Errorf("Invalid cookie SameSite %s", c.SameSite)
+}
+
+// Compression sets the gzip levels used for the encoding done by the
+// server, Assets for the precomputed gzip versions of the assets and
+// Index for the index page. Assets that are not brotli compressed at
+// build time only get gzipped when their extension is in Extensions
+type Compression struct {
+	Assets     int
+	Index      int
+	Extensions []string
+}
+
+// Compressible returns true if the extension of name is in Extensions
+func (c Compression) Compressible(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range c.Extensions {
+		if strings.ToLower(e) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// AssetsLevel returns Assets clamped to a valid gzip level
+func (c Compression) AssetsLevel() int {
+	return clampCompressionLevel(c.Assets)
+}
+
+// IndexLevel returns Index clamped to a valid gzip level
+func (c Compression) IndexLevel() int {
+	return clampCompressionLevel(c.Index)
+}
+
+func clampCompressionLevel(level int) int {
+	if level < gzip.BestSpeed {
+		return gzip.BestSpeed
+	}
+	if level > gzip.BestCompression {
+		return gzip.BestCompression
+	}
+	return level
+}
+
+// Log sets how dispatch logs, Format is "text" or "json" and Level is
+// "trace", "debug", "info", "warn" or "error"
+type Log struct {
+	Format string
+	Level  string
+}
+
+// Configure applies the format and level to logger
+func (l Log) Configure(logger *logging.Logger) error {
+	level, err := logging.ParseLevel(l.Level)
+	if err != nil {
+		return err
+	}
+	if err := logger.SetFormat(l.Format); err != nil {
+		return err
+	}
+	logger.SetLevel(level)
+	return nil
+}
+
+type Encryption struct {
+	Enabled            bool
+	Passphrase         string
+	PreviousPassphrase string `mapstructure:"previous_passphrase"`
+}
+
+// Bind sets the local address used for connections to a specific IRC server
+type Bind struct {
+	Host    string
+	Address string
+}
+
+// Proxy configures which reverse proxies are trusted to report the real IP
+// of a client, Trusted holds IPs and CIDR ranges
+type Proxy struct {
+	Trusted []string
+	Headers []string
+}
+
+type WebSocket struct {
+	// PingInterval is how often sessions get pinged, sessions that have not
+	// answered within PingTimeout are closed
+	PingInterval time.Duration `mapstructure:"ping_interval"`
+	PingTimeout  time.Duration `mapstructure:"ping_timeout"`
+	// MaxMessageSize is the max size in KiB of messages from the client,
+	// sessions sending bigger messages get closed
+	MaxMessageSize int `mapstructure:"max_message_size"`
+}
+
+type WebIRC struct {
+	// Host is the IRC server this applies to
+	Host     string
+	Password string
+	Gateway  string
+}
+
+type Netsplit struct {
+	// Window is how long QUITs and JOINs are collected before the
+	// netsplit or netjoin event gets sent
+	Window time.Duration
+	// IndividualEvents turns off the coalescing
+	IndividualEvents bool `mapstructure:"individual_events"`
+}
+
+type Messages struct {
+	MaxLength int `mapstructure:"max_length"`
+	// Policy is "truncate" or "full"
+	Policy string
+	// QueueOffline keeps up to QueueSize messages per server that get sent
+	// while disconnected and sends them once reconnected, QueuePrefix adds
+	// the time they were written to them
+	QueueOffline bool `mapstructure:"queue_offline"`
+	QueueSize    int  `mapstructure:"queue_size"`
+	QueuePrefix  bool `mapstructure:"queue_prefix"`
+	Paste        Paste
+}
+
+// Paste replaces long messages sent by users with a link to a paste
+// of them, so they do not flood the channel
+type Paste struct {
+	Enabled bool
+	// Messages with more lines than MaxLines or more bytes than MaxLength
+	// get pasted, 0 turns off either check
+	MaxLines  int `mapstructure:"max_lines"`
+	MaxLength int `mapstructure:"max_length"`
+	// Service is the URL of an external paste service, the message is
+	// POSTed to it as text/plain and the body of the response has to be
+	// the link. Pastes are stored and served by dispatch when it is empty.
+	Service string
+	Timeout time.Duration
+}
+
+// Limit returns the length messages get truncated to, 0 means no limit
+func (m Messages) Limit() int {
+	if m.Policy == "full" {
+		return 0
+	}
+	return m.MaxLength
+}
+
+// Pastes are text snippets users share through links served by dispatch
+type Pastes struct {
+	// MaxSize is the largest paste in bytes
+	MaxSize int `mapstructure:"max_size"`
+	// Expire is how long pastes are kept, 0 keeps them forever
+	Expire time.Duration
+}
+
+// Avatars are resolved to URLs for the client to show, they are never
+// fetched by dispatch
+type Avatars struct {
+	// Provider is gravatar, metadata or static, empty turns avatars off
+	Provider string
+	// URL is used by the gravatar provider, {hash} is replaced with the
+	// SHA-256 hash of the lowercased account name. It is not an email hash,
+	// so only generated images like Gravatar's d=identicon show up
+	URL string
+	// Hosts are the ones the metadata provider accepts avatars from
+	Hosts []string
+	// Static maps lowercased account names to avatar URLs
+	Static map[string]string
+}
+
+type Filters struct {
+	// Drop holds regular expressions matched against message text
+	Drop []string
+}
+
+type LinkPreviews struct {
+	Enabled       bool
+	Timeout       time.Duration
+	MaxSize       int64 `mapstructure:"max_size"`
+	MaxConcurrent int   `mapstructure:"max_concurrent"`
+	AllowPrivate  bool  `mapstructure:"allow_private"`
+	// Allow and Deny are host names, when Allow is set only those hosts
+	// get fetched, subdomains match as well
+	Allow []string
+	Deny  []string
+}
+
+type Limits struct {
+	// MaxServers and MaxChannels are per user, 0 means unlimited
+	MaxServers  int `mapstructure:"max_servers"`
+	MaxChannels int `mapstructure:"max_channels"`
+	// MaxConnections caps how many servers a user can be connected to at
+	// the same time, 0 means unlimited
+	MaxConnections int `mapstructure:"max_connections"`
+	// ConnectRate is how many new connections a user can open within
+	// ConnectWindow, 0 means unlimited
+	ConnectRate   int           `mapstructure:"connect_rate"`
+	ConnectWindow time.Duration `mapstructure:"connect_window"`
+	// MaxSessions caps how many WebSocket sessions a user can have open
+	// at the same time, 0 means unlimited
+	MaxSessions int `mapstructure:"max_sessions"`
+	// SessionPolicy is what happens to a new session over MaxSessions,
+	// "evict" closes the oldest one and "reject" closes the new one
+	SessionPolicy string `mapstructure:"session_policy"`
+	// Users overrides the limits for specific users
+	Users map[string]Limits
+}
+
+// ForUser returns the limits that apply to username,
+// admins are unlimited unless they have an override
+func (l Limits) ForUser(username string, admin bool) Limits {
+	if override, ok := l.Users[username]; ok {
+		return override
+	}
+	if admin {
+		return Limits{}
+	}
+	return l
 }
 
 type Autoget struct {
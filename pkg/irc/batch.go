@@ -0,0 +1,105 @@
+package irc
+
+import (
+	"time"
+)
+
+// Batch is a group of messages sent by the server between a BATCH +ref
+// and BATCH -ref pair
+type Batch struct {
+	Tags     map[string]string
+	Ref      string
+	Type     string
+	Params   []string
+	Messages []*Message
+}
+
+// GetBatch returns the finished batch when passed a BATCH -ref message
+func GetBatch(msg *Message) *Batch {
+	if batch, ok := msg.meta.(*Batch); ok {
+		return batch
+	}
+	return nil
+}
+
+func isChatHistoryBatch(batchType string) bool {
+	return batchType == "chathistory" || batchType == "draft/chathistory"
+}
+
+func (c *Client) handleBatch(msg *Message) {
+	if len(msg.Params) == 0 || len(msg.Params[0]) < 2 {
+		return
+	}
+
+	ref := msg.Params[0][1:]
+
+	switch msg.Params[0][0] {
+	case '+':
+		if len(msg.Params) > 1 {
+			c.state.batches[ref] = &Batch{
+				Tags:   msg.Tags,
+				Ref:    ref,
+				Type:   msg.Params[1],
+				Params: msg.Params[2:],
+			}
+		}
+
+	case '-':
+		if batch, ok := c.state.batches[ref]; ok {
+			msg.meta = batch
+			delete(c.state.batches, ref)
+		}
+	}
+}
+
+// addToBatch collects the messages of chathistory and multiline batches,
+// they get delivered as a whole with the closing BATCH message instead of
+// one by one so they are not mistaken for live messages, nested batches
+// are opened and closed as usual
+func (c *Client) addToBatch(msg *Message) bool {
+	if ref, ok := msg.Tags["batch"]; ok && msg.Command != BATCH {
+		if batch, ok := c.state.batches[ref]; ok &&
+			(isChatHistoryBatch(batch.Type) || isMultilineBatch(batch.Type)) {
+			batch.Messages = append(batch.Messages, msg)
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsChatHistory returns true if the server lets the client fetch
+// history with the CHATHISTORY command
+func (c *Client) SupportsChatHistory() bool {
+	return c.HasCapability("chathistory") || c.HasCapability("draft/chathistory")
+}
+
+// ChatHistoryLatest requests the latest messages sent to target after t,
+// or the latest messages if t is zero
+func (c *Client) ChatHistoryLatest(target string, t time.Time, limit int) {
+	ref := "*"
+	if !t.IsZero() {
+		ref = chatHistoryTimestamp(t)
+	}
+	c.chatHistory("LATEST", target, ref, limit)
+}
+
+// ChatHistoryBefore requests the messages sent to target before t
+func (c *Client) ChatHistoryBefore(target string, t time.Time, limit int) {
+	c.chatHistory("BEFORE", target, chatHistoryTimestamp(t), limit)
+}
+
+// ChatHistoryAfter requests the messages sent to target after t
+func (c *Client) ChatHistoryAfter(target string, t time.Time, limit int) {
+	c.chatHistory("AFTER", target, chatHistoryTimestamp(t), limit)
+}
+
+func (c *Client) chatHistory(subcommand, target, ref string, limit int) {
+	if max := c.Features.Int("CHATHISTORY"); max > 0 && limit > max {
+		limit = max
+	}
+	c.Writef("CHATHISTORY %s %s %s %d", subcommand, target, ref, limit)
+}
+
+func chatHistoryTimestamp(t time.Time) string {
+	return "timestamp=" + t.UTC().Format(serverTimeFormat)
+}
@@ -0,0 +1,53 @@
+package irc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChatHistoryBatch(t *testing.T) {
+	c, _ := testClientSend()
+
+	start := ParseMessage("BATCH +ref chathistory #chan")
+	c.handleMessage(start)
+	assert.Nil(t, GetBatch(start))
+
+	msg := ParseMessage("@batch=ref;msgid=abc;time=2020-01-02T03:04:05.678Z :nick!user@host PRIVMSG #chan :hello")
+	assert.True(t, c.addToBatch(msg))
+	assert.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 678000000, time.UTC), msg.Time())
+
+	live := ParseMessage(":nick!user@host PRIVMSG #chan :live")
+	assert.False(t, c.addToBatch(live))
+
+	end := ParseMessage("BATCH -ref")
+	c.handleMessage(end)
+	batch := GetBatch(end)
+	assert.NotNil(t, batch)
+	assert.Equal(t, "chathistory", batch.Type)
+	assert.Equal(t, []string{"#chan"}, batch.Params)
+	assert.Equal(t, []*Message{msg}, batch.Messages)
+
+	c.handleMessage(ParseMessage("BATCH +split netsplit irc.a irc.b"))
+	assert.False(t, c.addToBatch(ParseMessage("@batch=split :nick!user@host QUIT :irc.a irc.b")))
+}
+
+func TestChatHistory(t *testing.T) {
+	c, out := testClientSend()
+	c.Features.Parse([]string{"nick", "CHATHISTORY=50", "are supported"})
+
+	c.ChatHistoryLatest("#chan", time.Time{}, 100)
+	assert.Equal(t, "CHATHISTORY LATEST #chan * 50\r\n", <-out)
+
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	c.ChatHistoryLatest("#chan", ts, 20)
+	assert.Equal(t, "CHATHISTORY LATEST #chan timestamp=2020-01-02T03:04:05.000Z 20\r\n", <-out)
+
+	c.ChatHistoryBefore("#chan", ts, 20)
+	assert.Equal(t, "CHATHISTORY BEFORE #chan timestamp=2020-01-02T03:04:05.000Z 20\r\n", <-out)
+
+	assert.False(t, c.SupportsChatHistory())
+	c.enabledCapabilities["draft/chathistory"] = nil
+	assert.True(t, c.SupportsChatHistory())
+}
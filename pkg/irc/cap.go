@@ -4,7 +4,22 @@ import (
 	"strings"
 )
 
-var clientWantedCaps = []string{"cap-notify"}
+var clientWantedCaps = []string{
+	"cap-notify",
+	"account-tag",
+	"away-notify",
+	"batch",
+	"message-tags",
+	"server-time",
+	"chathistory",
+	"draft/chathistory",
+	"draft/multiline",
+	"draft/channel-rename",
+	"draft/no-implicit-names",
+	"draft/metadata-2",
+	"draft/metadata",
+	accountRegistrationCap,
+}
 
 func (c *Client) GetCapability(name string) ([]string, bool) {
 	c.lock.Lock()
@@ -0,0 +1,50 @@
+package irc
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"time"
+)
+
+// Certificate describes the certificate a server presented during the
+// TLS handshake
+type Certificate struct {
+	Subject   string
+	Issuer    string
+	NotBefore time.Time
+	NotAfter  time.Time
+	// Fingerprint is the hex encoded SHA-256 hash of the certificate
+	Fingerprint string
+}
+
+func NewCertificate(cert *x509.Certificate) *Certificate {
+	return &Certificate{
+		Subject:     cert.Subject.String(),
+		Issuer:      cert.Issuer.String(),
+		NotBefore:   cert.NotBefore,
+		NotAfter:    cert.NotAfter,
+		Fingerprint: CertificateFingerprint(cert),
+	}
+}
+
+// CertificateFingerprint returns the hex encoded SHA-256 hash of cert,
+// this is the format used for certificate pinning
+func CertificateFingerprint(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(hash[:])
+}
+
+// ExpiresWithin returns true if the certificate expires within d
+func (c *Certificate) ExpiresWithin(d time.Duration) bool {
+	return time.Until(c.NotAfter) < d
+}
+
+// PeerCertificate returns the certificate of the server from the last
+// TLS handshake, it returns nil if no handshake has happened
+func (c *Client) PeerCertificate() *Certificate {
+	c.lock.Lock()
+	cert := c.peerCert
+	c.lock.Unlock()
+	return cert
+}
@@ -3,6 +3,8 @@ package irc
 import (
 	"bufio"
 	"crypto/tls"
+	"errors"
+	"io"
 	"net"
 	"strings"
 	"sync"
@@ -11,6 +13,20 @@ import (
 	"github.com/jpillora/backoff"
 )
 
+const (
+	DefaultPingInterval = 2 * time.Minute
+	DefaultPingTimeout  = 30 * time.Second
+
+	DefaultDNSTimeout          = 10 * time.Second
+	DefaultConnectTimeout      = 10 * time.Second
+	DefaultTLSTimeout          = 10 * time.Second
+	DefaultRegistrationTimeout = 30 * time.Second
+
+	DefaultFallbackDelay = 250 * time.Millisecond
+
+	DefaultKillCooldown = 5 * time.Minute
+)
+
 type Config struct {
 	Host           string
 	Port           string
@@ -21,18 +37,61 @@ type Config struct {
 	Username       string
 	Realname       string
 
+	// BindAddress is the local IP to connect from, IPv4 or IPv6
+	BindAddress string
+	// FallbackDelay is how long a connection attempt gets before one is
+	// started to the next resolved address, alternating between IPv6 and IPv4
+	FallbackDelay time.Duration
+	// PreferIPv4 makes IPv4 addresses get attempted first
+	PreferIPv4 bool
+
+	// SASLMechanisms is the order SASL mechanisms are attempted in,
+	// Account and Password are used for all but EXTERNAL
 	SASLMechanisms []string
 	Account        string
 	Password       string
 
+	// ClientTags are client-only tags added to outgoing messages when
+	// the server supports message tags
+	ClientTags map[string]string
+
+	// WebIRC passes on the real IP and hostname of the user to the server
+	// when its Password is set
+	WebIRC WebIRC
+
 	// Version is the reply to VERSION and FINGER CTCP messages
 	Version string
 	// Source is the reply to SOURCE CTCP messages
 	Source string
 
+	// PingInterval is how long the connection can be idle before a PING is sent
+	PingInterval time.Duration
+	// PingTimeout is how long to wait for any data after sending a PING
+	// before the connection is considered dead
+	PingTimeout time.Duration
+	// DNSTimeout, ConnectTimeout and TLSTimeout are how long resolving the
+	// host, opening the connection and the TLS handshake can take
+	DNSTimeout     time.Duration
+	ConnectTimeout time.Duration
+	TLSTimeout     time.Duration
+	// RegistrationTimeout is how long the server gets to complete registration,
+	// including CAP negotiation and SASL, before the connection is dropped
+	RegistrationTimeout time.Duration
+	// KillCooldown is how long to wait before reconnecting after getting
+	// killed by an operator, 0 uses DefaultKillCooldown and a negative
+	// value reconnects right away
+	KillCooldown time.Duration
+
 	HandleNickInUse func(string) string
 }
 
+type WebIRC struct {
+	Password string
+	Gateway  string
+	Hostname string
+	IP       string
+}
+
 type Client struct {
 	Config *Config
 
@@ -43,6 +102,7 @@ type Client struct {
 	state    *state
 	nick     string
 	channels []string
+	batchRef uint64
 
 	wantedCapabilities    []string
 	requestedCapabilities map[string][]string
@@ -50,20 +110,30 @@ type Client struct {
 	negotiating           bool
 	saslMechanisms        []SASL
 	currentSASL           SASL
+	saslErr               error
 
 	conn       net.Conn
 	connected  bool
 	registered bool
+	killed     time.Time
+	peerCert   *Certificate
 	dialer     *net.Dialer
+	resolver   resolver
 	recvBuf    []byte
 	scan       *bufio.Scanner
 	backoff    *backoff.Backoff
 	out        chan string
+	rawLog     io.Writer
+	rawLogLock sync.Mutex
 
 	quit      chan struct{}
+	quitOnce  sync.Once
+	done      chan struct{}
 	reconnect chan struct{}
 	sendRecv  sync.WaitGroup
 	lock      sync.Mutex
+	// configLock guards the parts of Config that can change while connected
+	configLock sync.Mutex
 }
 
 func NewClient(config *Config) *Client {
@@ -87,6 +157,38 @@ func NewClient(config *Config) *Client {
 		config.SASLMechanisms = DefaultSASLMechanisms
 	}
 
+	if config.PingInterval == 0 {
+		config.PingInterval = DefaultPingInterval
+	}
+
+	if config.PingTimeout == 0 {
+		config.PingTimeout = DefaultPingTimeout
+	}
+
+	if config.DNSTimeout == 0 {
+		config.DNSTimeout = DefaultDNSTimeout
+	}
+
+	if config.ConnectTimeout == 0 {
+		config.ConnectTimeout = DefaultConnectTimeout
+	}
+
+	if config.TLSTimeout == 0 {
+		config.TLSTimeout = DefaultTLSTimeout
+	}
+
+	if config.RegistrationTimeout == 0 {
+		config.RegistrationTimeout = DefaultRegistrationTimeout
+	}
+
+	if config.KillCooldown == 0 {
+		config.KillCooldown = DefaultKillCooldown
+	}
+
+	if config.FallbackDelay == 0 {
+		config.FallbackDelay = DefaultFallbackDelay
+	}
+
 	client := &Client{
 		Config:                config,
 		Messages:              make(chan *Message, 32),
@@ -95,7 +197,8 @@ func NewClient(config *Config) *Client {
 		nick:                  config.Nick,
 		requestedCapabilities: map[string][]string{},
 		enabledCapabilities:   map[string][]string{},
-		dialer:                &net.Dialer{Timeout: 10 * time.Second},
+		dialer:                &net.Dialer{Timeout: config.ConnectTimeout},
+		resolver:              net.DefaultResolver,
 		recvBuf:               make([]byte, 0, 4096),
 		backoff: &backoff.Backoff{
 			Min:    500 * time.Millisecond,
@@ -104,6 +207,7 @@ func NewClient(config *Config) *Client {
 		},
 		out:       make(chan string, 32),
 		quit:      make(chan struct{}),
+		done:      make(chan struct{}),
 		reconnect: make(chan struct{}),
 	}
 	client.state = newState(client)
@@ -139,6 +243,7 @@ func (c *Client) initSASL() {
 	c.wantedCapabilities = append([]string{}, clientWantedCaps...)
 	c.negotiating = false
 	c.currentSASL = nil
+	c.setSASLError(nil)
 
 	if len(saslMechanisms) > 0 {
 		c.wantedCapabilities = append(c.wantedCapabilities, "sasl")
@@ -183,6 +288,54 @@ func (c *Client) setRegistered(reg bool) {
 	c.lock.Unlock()
 }
 
+// Killed returns true if the client got killed by an operator less than
+// KillCooldown ago, it does not reconnect on its own until then
+func (c *Client) Killed() bool {
+	return c.killCooldown() > 0
+}
+
+func (c *Client) setKilled() {
+	c.lock.Lock()
+	c.killed = time.Now()
+	c.lock.Unlock()
+}
+
+// killCooldown returns how much is left of the cooldown after getting killed
+func (c *Client) killCooldown() time.Duration {
+	c.lock.Lock()
+	killed := c.killed
+	c.lock.Unlock()
+
+	if killed.IsZero() {
+		return 0
+	}
+	return time.Until(killed.Add(c.Config.KillCooldown))
+}
+
+// SetClientTags replaces the client-only tags added to outgoing messages
+func (c *Client) SetClientTags(tags map[string]string) {
+	c.configLock.Lock()
+	c.Config.ClientTags = tags
+	c.configLock.Unlock()
+}
+
+// TLSConfig returns the TLS config connections are made with, it must not
+// be modified, pass a modified clone to SetTLSConfig instead
+func (c *Client) TLSConfig() *tls.Config {
+	c.configLock.Lock()
+	config := c.Config.TLSConfig
+	c.configLock.Unlock()
+	return config
+}
+
+// SetTLSConfig replaces the TLS config, it gets used from the next time
+// the client connects
+func (c *Client) SetTLSConfig(config *tls.Config) {
+	c.configLock.Lock()
+	c.Config.TLSConfig = config
+	c.configLock.Unlock()
+}
+
 func (c *Client) Host() string {
 	return c.Config.Host
 }
@@ -191,14 +344,59 @@ func (c *Client) MOTD() []string {
 	return c.state.getMOTD()
 }
 
+// UserModes returns the modes set on the client's own nick
+func (c *Client) UserModes() string {
+	return c.state.getUserModes()
+}
+
 func (c *Client) ChannelUsers(channel string) []string {
 	return c.state.getUsers(channel)
 }
 
+// LocalIP returns the local address of the connection to the server
+func (c *Client) LocalIP() net.IP {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	if addr, ok := c.conn.LocalAddr().(*net.TCPAddr); ok {
+		return addr.IP
+	}
+	return nil
+}
+
+// IsAway reports whether nick is known to be away
+func (c *Client) IsAway(nick string) bool {
+	return c.state.isAway(nick)
+}
+
+// AwayUsers returns the nicks in channel that are known to be away
+func (c *Client) AwayUsers(channel string) []string {
+	return c.state.getAwayUsers(channel)
+}
+
 func (c *Client) ChannelTopic(channel string) string {
 	return c.state.getTopic(channel)
 }
 
+// SplitStatusMsg splits the STATUSMSG prefixes, like the @ in @#channel,
+// from a message target
+func (c *Client) SplitStatusMsg(target string) (string, string) {
+	prefixes := c.Features.String("STATUSMSG")
+
+	i := 0
+	for i < len(target) && strings.IndexByte(prefixes, target[i]) >= 0 {
+		i++
+	}
+
+	if i > 0 && isChannel(target[i:]) {
+		return target[:i], target[i:]
+	}
+	return "", target
+}
+
 func (c *Client) Nick(nick string) {
 	c.Write("NICK " + nick)
 }
@@ -207,28 +405,126 @@ func (c *Client) Oper(name, password string) {
 	c.Write("OPER " + name + " " + password)
 }
 
+const accountRegistrationCap = "draft/account-registration"
+
+// SupportsAccountRegistration returns true if accounts can be registered
+// with REGISTER
+func (c *Client) SupportsAccountRegistration() bool {
+	return c.HasCapability(accountRegistrationCap)
+}
+
+// AccountRegistrationRequiresEmail returns true if the server only
+// registers accounts that have an email address
+func (c *Client) AccountRegistrationRequiresEmail() bool {
+	values, _ := c.GetCapability(accountRegistrationCap)
+	for _, v := range values {
+		if v == "email-required" {
+			return true
+		}
+	}
+	return false
+}
+
+// Register registers an account, an empty account uses the current nick
+// and an empty email registers it without one
+func (c *Client) Register(account, email, password string) {
+	if account == "" {
+		account = "*"
+	}
+	if email == "" {
+		email = "*"
+	}
+	c.Write("REGISTER " + account + " " + email + " " + password)
+}
+
+// Verify completes the registration of an account with the code the
+// server sent to its email address
+func (c *Client) Verify(account, code string) {
+	c.Write("VERIFY " + account + " " + code)
+}
+
 func (c *Client) Mode(target, modes, params string) {
 	c.Write(strings.TrimRight("MODE "+target+" "+modes+" "+params, " "))
 }
 
-func (c *Client) Quit() {
+// Done returns a channel that gets closed when the client has
+// disconnected after Quit
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Quit disconnects from the server, the first message is sent as the
+// quit message when given
+func (c *Client) Quit(message ...string) {
 	go func() {
 		if c.Connected() {
-			c.write("QUIT")
+			if len(message) > 0 && message[0] != "" {
+				c.write("QUIT :" + message[0])
+			} else {
+				c.write("QUIT")
+			}
 		}
-		close(c.quit)
+		c.closeQuit()
 	}()
 }
 
+// closeQuit stops the client, both Quit and an ERROR from the server do
+// this, so the server answering a QUIT with ERROR can not close it twice
+func (c *Client) closeQuit() {
+	c.quitOnce.Do(func() {
+		close(c.quit)
+	})
+}
+
 func (c *Client) Join(channels ...string) {
 	c.Write("JOIN " + strings.Join(channels, ","))
 }
 
 func (c *Client) Part(channels ...string) {
-	c.Write("PART " + strings.Join(channels, ","))
+	c.PartWithMessage("", channels...)
+}
+
+// PartWithMessage leaves channels, message is sent as the part message
+// when it is not empty
+func (c *Client) PartWithMessage(message string, channels ...string) {
+	msg := "PART " + strings.Join(channels, ",")
+	if message != "" {
+		msg += " :" + message
+	}
+	c.Write(msg)
 	c.removeChannels(channels...)
 }
 
+// Rename asks the server to rename a channel, it needs the
+// draft/channel-rename capability
+func (c *Client) Rename(channel, name, reason string) {
+	msg := "RENAME " + channel + " " + name
+	if reason != "" {
+		msg += " :" + reason
+	}
+	c.Write(msg)
+}
+
+// ErrTopicTooLong is returned by SetTopic for topics longer than the
+// TOPICLEN the server advertises
+var ErrTopicTooLong = errors.New("The topic is too long")
+
+// TopicLength returns the max number of bytes in a topic, 0 if the server
+// did not advertise it
+func (c *Client) TopicLength() int {
+	return c.Features.Int("TOPICLEN")
+}
+
+// SetTopic sets the topic of channel, it is not sent if it is longer than
+// the server allows since it would get truncated
+func (c *Client) SetTopic(channel, topic string) error {
+	if max := c.TopicLength(); max > 0 && len(topic) > max {
+		return ErrTopicTooLong
+	}
+	c.Topic(channel, topic)
+	return nil
+}
+
 func (c *Client) Topic(channel string, topic ...string) {
 	msg := "TOPIC " + channel
 	if len(topic) > 0 {
@@ -237,6 +533,12 @@ func (c *Client) Topic(channel string, topic ...string) {
 	c.Write(msg)
 }
 
+// Names requests the userlist of channels, servers send it on join
+// unless the no-implicit-names capability is enabled
+func (c *Client) Names(channels ...string) {
+	c.Write("NAMES " + strings.Join(channels, ","))
+}
+
 func (c *Client) Invite(nick, channel string) {
 	c.Write("INVITE " + nick + " " + channel)
 }
@@ -249,10 +551,65 @@ func (c *Client) Privmsg(target, msg string) {
 	c.Writef("PRIVMSG %s :%s", target, msg)
 }
 
+// ReplyTag is the client tag that marks a message as a reply
+const ReplyTag = "+draft/reply"
+
+// PrivmsgReply sends msg as a reply to the message with the msgid replyTo,
+// the tags are dropped when the server doesn't support message tags
+func (c *Client) PrivmsgReply(target, msg, replyTo string) {
+	if tags := c.messageTags(replyTo); tags != "" {
+		c.Writef("@%s PRIVMSG %s :%s", tags, target, msg)
+		return
+	}
+	c.Privmsg(target, msg)
+}
+
+// messageTags returns the encoded tags for an outgoing message, the reply
+// tag and the configured client tags, or nothing if the server doesn't
+// support message tags
+func (c *Client) messageTags(replyTo string) string {
+	if !c.HasCapability("message-tags") {
+		return ""
+	}
+
+	tags := map[string]string{}
+	c.configLock.Lock()
+	for key, val := range c.Config.ClientTags {
+		tags[key] = val
+	}
+	c.configLock.Unlock()
+	if replyTo != "" {
+		tags[ReplyTag] = replyTo
+	}
+	return encodeTags(tags)
+}
+
+// ReadTag is the client tag of a read receipt, its value is the msgid
+// of the last message that was read
+const ReadTag = "+draft/read"
+
+// ReadReceipt tells target that the messages up to the one with the msgid
+// have been read, it returns false without sending anything when the
+// server doesn't support message tags
+func (c *Client) ReadReceipt(target, msgid string) bool {
+	if !c.HasCapability("message-tags") {
+		return false
+	}
+	c.Writef("@%s TAGMSG %s", encodeTags(map[string]string{ReadTag: msgid}), target)
+	return true
+}
+
 func (c *Client) Notice(target, msg string) {
 	c.Writef("NOTICE %s :%s", target, msg)
 }
 
+func (c *Client) SendCTCP(target, command, params string) {
+	c.Privmsg(target, EncodeCTCP(&CTCP{
+		Command: command,
+		Params:  params,
+	}))
+}
+
 func (c *Client) ReplyCTCP(target, command, params string) {
 	c.Notice(target, EncodeCTCP(&CTCP{
 		Command: command,
@@ -272,10 +629,41 @@ func (c *Client) List() {
 	c.Write("LIST")
 }
 
+// RequestMOTD asks the server to send its MOTD again
+func (c *Client) RequestMOTD() {
+	c.Write(MOTD)
+}
+
+// Help asks the server for help on a command or topic, or for its general
+// help when topic is empty
+func (c *Client) Help(topic string) {
+	if topic == "" {
+		c.Write(HELP)
+	} else {
+		c.Write(HELP + " " + topic)
+	}
+}
+
 func (c *Client) writePass(password string) {
 	c.write("PASS " + password)
 }
 
+func (c *Client) writeWebIRC(w WebIRC) {
+	hostname := w.Hostname
+	if hostname == "" {
+		hostname = w.IP
+	}
+	c.writef("WEBIRC %s %s %s %s", w.Password, w.Gateway, webIRCParam(hostname), webIRCParam(w.IP))
+}
+
+// webIRCParam keeps IPv6 addresses like ::1 from being parsed as a trailing parameter
+func webIRCParam(s string) string {
+	if strings.HasPrefix(s, ":") {
+		return "0" + s
+	}
+	return s
+}
+
 func (c *Client) writeNick(nick string) {
 	c.write("NICK " + nick)
 }
@@ -289,6 +677,9 @@ func (c *Client) authenticate(response string) {
 }
 
 func (c *Client) register() {
+	if c.Config.WebIRC.Password != "" {
+		c.writeWebIRC(c.Config.WebIRC)
+	}
 	c.beginCAP()
 	if c.Config.ServerPassword != "" {
 		c.writePass(c.Config.ServerPassword)
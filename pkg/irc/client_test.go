@@ -2,6 +2,7 @@ package irc
 
 import (
 	"net"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -57,6 +58,23 @@ func TestOper(t *testing.T) {
 	assert.Equal(t, "OPER name pass\r\n", <-out)
 }
 
+func TestRegisterAccount(t *testing.T) {
+	c, out := testClientSend()
+	c.Register("", "", "pass")
+	assert.Equal(t, "REGISTER * * pass\r\n", <-out)
+	c.Register("acc", "acc@example.com", "pass")
+	assert.Equal(t, "REGISTER acc acc@example.com pass\r\n", <-out)
+	c.Verify("acc", "1234")
+	assert.Equal(t, "VERIFY acc 1234\r\n", <-out)
+
+	assert.False(t, c.SupportsAccountRegistration())
+	c.enabledCapabilities[accountRegistrationCap] = nil
+	assert.True(t, c.SupportsAccountRegistration())
+	assert.False(t, c.AccountRegistrationRequiresEmail())
+	c.enabledCapabilities[accountRegistrationCap] = []string{"before-connect", "email-required"}
+	assert.True(t, c.AccountRegistrationRequiresEmail())
+}
+
 func TestMode(t *testing.T) {
 	c, out := testClientSend()
 	c.Mode("#chan", "+o", "user")
@@ -70,6 +88,13 @@ func TestQuit(t *testing.T) {
 	assert.Equal(t, "QUIT\r\n", <-out)
 	_, ok := <-c.quit
 	assert.Equal(t, false, ok)
+	// The ERROR the server answers with closes it again
+	assert.NotPanics(t, c.closeQuit)
+
+	c, out = testClientSend()
+	c.connected = true
+	c.Quit("Dispatch dev")
+	assert.Equal(t, "QUIT :Dispatch dev\r\n", <-out)
 }
 
 func TestJoin(t *testing.T) {
@@ -86,6 +111,28 @@ func TestPart(t *testing.T) {
 	assert.Equal(t, "PART #a\r\n", <-out)
 	c.Part("#b", "#c")
 	assert.Equal(t, "PART #b,#c\r\n", <-out)
+	c.PartWithMessage("bye", "#d")
+	assert.Equal(t, "PART #d :bye\r\n", <-out)
+	c.PartWithMessage("", "#e")
+	assert.Equal(t, "PART #e\r\n", <-out)
+}
+
+func TestWho(t *testing.T) {
+	c, out := testClientSend()
+	c.Who("#chan")
+	assert.Equal(t, "WHO #chan\r\n", <-out)
+
+	c.Features.Parse([]string{"nick", "WHOX", "are supported by this server"})
+	c.Who("#chan")
+	assert.Equal(t, "WHO #chan %tcuhnfa,1\r\n", <-out)
+}
+
+func TestRename(t *testing.T) {
+	c, out := testClientSend()
+	c.Rename("#old", "#new", "")
+	assert.Equal(t, "RENAME #old #new\r\n", <-out)
+	c.Rename("#old", "#new", "Moving")
+	assert.Equal(t, "RENAME #old #new :Moving\r\n", <-out)
 }
 
 func TestTopic(t *testing.T) {
@@ -98,6 +145,30 @@ func TestTopic(t *testing.T) {
 	assert.Equal(t, "TOPIC #chan :\r\n", <-out)
 }
 
+func TestSetTopic(t *testing.T) {
+	c, out := testClientSend()
+	assert.Nil(t, c.SetTopic("#chan", strings.Repeat("a", 400)))
+	assert.Equal(t, "TOPIC #chan :"+strings.Repeat("a", 400)+"\r\n", <-out)
+
+	c.Features.Parse([]string{"nick", "TOPICLEN=10", "are supported by this server"})
+	assert.Equal(t, 10, c.TopicLength())
+	assert.Nil(t, c.SetTopic("#chan", "0123456789"))
+	assert.Equal(t, "TOPIC #chan :0123456789\r\n", <-out)
+	assert.Equal(t, ErrTopicTooLong, c.SetTopic("#chan", "0123456789a"))
+	// The length is in bytes
+	assert.Equal(t, ErrTopicTooLong, c.SetTopic("#chan", "ææææææ"))
+	assert.Nil(t, c.SetTopic("#chan", ""))
+	assert.Equal(t, "TOPIC #chan :\r\n", <-out)
+}
+
+func TestNames(t *testing.T) {
+	c, out := testClientSend()
+	c.Names("#chan")
+	assert.Equal(t, "NAMES #chan\r\n", <-out)
+	c.Names("#a", "#b")
+	assert.Equal(t, "NAMES #a,#b\r\n", <-out)
+}
+
 func TestInvite(t *testing.T) {
 	c, out := testClientSend()
 	c.Invite("user", "#chan")
@@ -118,12 +189,69 @@ func TestPrivmsg(t *testing.T) {
 	assert.Equal(t, "PRIVMSG user :the message\r\n", <-out)
 }
 
+func TestPrivmsgReply(t *testing.T) {
+	c, out := testClientSend()
+	c.PrivmsgReply("#chan", "the reply", "abc;1")
+	assert.Equal(t, "PRIVMSG #chan :the reply\r\n", <-out)
+
+	c.enabledCapabilities["message-tags"] = nil
+	c.PrivmsgReply("#chan", "the reply", "abc;1")
+	assert.Equal(t, "@+draft/reply=abc\\:1 PRIVMSG #chan :the reply\r\n", <-out)
+
+	c.PrivmsgReply("#chan", "not a reply", "")
+	assert.Equal(t, "PRIVMSG #chan :not a reply\r\n", <-out)
+}
+
+func TestPrivmsgClientTags(t *testing.T) {
+	c, out := testClientSend()
+	c.Config.ClientTags = map[string]string{
+		"+vendor/bot":     "",
+		"+example.com/id": "a b",
+	}
+	c.PrivmsgReply("#chan", "msg", "")
+	assert.Equal(t, "PRIVMSG #chan :msg\r\n", <-out)
+
+	c.enabledCapabilities["message-tags"] = nil
+	c.PrivmsgReply("#chan", "msg", "")
+	assert.Equal(t, "@+example.com/id=a\\sb;+vendor/bot PRIVMSG #chan :msg\r\n", <-out)
+
+	c.PrivmsgReply("#chan", "msg", "abc")
+	assert.Equal(t, "@+draft/reply=abc;+example.com/id=a\\sb;+vendor/bot PRIVMSG #chan :msg\r\n", <-out)
+
+	// The tags can be replaced while messages are being sent
+	done := make(chan struct{})
+	go func() {
+		c.SetClientTags(map[string]string{"+vendor/bot": ""})
+		close(done)
+	}()
+	c.PrivmsgReply("#chan", "msg", "")
+	<-out
+	<-done
+	c.PrivmsgReply("#chan", "msg", "")
+	assert.Equal(t, "@+vendor/bot PRIVMSG #chan :msg\r\n", <-out)
+}
+
+func TestReadReceipt(t *testing.T) {
+	c, out := testClientSend()
+	assert.False(t, c.ReadReceipt("nick", "abc"))
+
+	c.enabledCapabilities["message-tags"] = nil
+	assert.True(t, c.ReadReceipt("nick", "abc;1"))
+	assert.Equal(t, "@+draft/read=abc\\:1 TAGMSG nick\r\n", <-out)
+}
+
 func TestNotice(t *testing.T) {
 	c, out := testClientSend()
 	c.Notice("user", "the message")
 	assert.Equal(t, "NOTICE user :the message\r\n", <-out)
 }
 
+func TestSendCTCP(t *testing.T) {
+	c, out := testClientSend()
+	c.SendCTCP("user", "VERSION", "")
+	assert.Equal(t, "PRIVMSG user :\x01VERSION\x01\r\n", <-out)
+}
+
 func TestReplyCTCP(t *testing.T) {
 	c, out := testClientSend()
 	c.ReplyCTCP("user", "PING", "PONG")
@@ -142,6 +270,14 @@ func TestAway(t *testing.T) {
 	assert.Equal(t, "AWAY :not here\r\n", <-out)
 }
 
+func TestHelp(t *testing.T) {
+	c, out := testClientSend()
+	c.Help("")
+	assert.Equal(t, "HELP\r\n", <-out)
+	c.Help("privmsg")
+	assert.Equal(t, "HELP privmsg\r\n", <-out)
+}
+
 func TestRegister(t *testing.T) {
 	c, out := testClientSend()
 	c.Config.Nick = "nick"
@@ -158,6 +294,23 @@ func TestRegister(t *testing.T) {
 	assert.Equal(t, "PASS pass\r\n", <-out)
 	assert.Equal(t, "NICK nick\r\n", <-out)
 	assert.Equal(t, "USER user 0 * :rn\r\n", <-out)
+
+	c.Config.WebIRC = WebIRC{
+		Password: "secret",
+		Gateway:  "dispatch",
+		IP:       "192.0.2.1",
+	}
+	c.register()
+	assert.Equal(t, "WEBIRC secret dispatch 192.0.2.1 192.0.2.1\r\n", <-out)
+	assert.Equal(t, "CAP LS 302\r\n", <-out)
+	assert.Equal(t, "PASS pass\r\n", <-out)
+	assert.Equal(t, "NICK nick\r\n", <-out)
+	assert.Equal(t, "USER user 0 * :rn\r\n", <-out)
+
+	c.Config.WebIRC.Hostname = "user.example.com"
+	c.Config.WebIRC.IP = "::1"
+	c.register()
+	assert.Equal(t, "WEBIRC secret dispatch user.example.com 0::1\r\n", <-out)
 }
 
 func TestFlushChannels(t *testing.T) {
@@ -172,3 +325,33 @@ func TestFlushChannels(t *testing.T) {
 	c.flushChannels()
 	assert.Equal(t, <-out, "JOIN #chan2,#chan3\r\n")
 }
+
+func TestSplitStatusMsg(t *testing.T) {
+	c := NewClient(&Config{})
+
+	prefix, target := c.SplitStatusMsg("@#chan")
+	assert.Equal(t, "", prefix)
+	assert.Equal(t, "@#chan", target)
+
+	c.Features.Parse([]string{"nick", "STATUSMSG=@+", "are supported"})
+
+	prefix, target = c.SplitStatusMsg("@#chan")
+	assert.Equal(t, "@", prefix)
+	assert.Equal(t, "#chan", target)
+
+	prefix, target = c.SplitStatusMsg("@+#chan")
+	assert.Equal(t, "@+", prefix)
+	assert.Equal(t, "#chan", target)
+
+	prefix, target = c.SplitStatusMsg("#chan")
+	assert.Equal(t, "", prefix)
+	assert.Equal(t, "#chan", target)
+
+	prefix, target = c.SplitStatusMsg("+chan")
+	assert.Equal(t, "", prefix)
+	assert.Equal(t, "+chan", target)
+
+	prefix, target = c.SplitStatusMsg("nick")
+	assert.Equal(t, "", prefix)
+	assert.Equal(t, "nick", target)
+}
@@ -8,11 +8,19 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"os"
+	"strings"
 	"time"
 )
 
 var (
 	ErrBadProtocol = errors.New("This server does not speak IRC")
+	ErrPingTimeout = errors.New("Ping timeout")
+
+	ErrDNSTimeout          = &TimeoutError{Phase: PhaseDNS}
+	ErrConnectTimeout      = &TimeoutError{Phase: PhaseConnect}
+	ErrTLSTimeout          = &TimeoutError{Phase: PhaseTLS}
+	ErrRegistrationTimeout = &TimeoutError{Phase: PhaseRegistration}
 )
 
 func (c *Client) Connect() {
@@ -32,12 +40,14 @@ func (c *Client) Writef(format string, a ...interface{}) {
 	c.out <- fmt.Sprintf(format+"\r\n", a...)
 }
 
-func (c *Client) write(data string) {
-	c.conn.Write([]byte(data + "\r\n"))
+func (c *Client) write(data string) error {
+	c.logRaw(true, data)
+	_, err := c.conn.Write([]byte(data + "\r\n"))
+	return err
 }
 
 func (c *Client) writef(format string, a ...interface{}) {
-	fmt.Fprintf(c.conn, format+"\r\n", a...)
+	c.write(fmt.Sprintf(format, a...))
 }
 
 func (c *Client) run() {
@@ -53,6 +63,7 @@ func (c *Client) run() {
 
 			c.sendRecv.Wait()
 			close(c.Messages)
+			close(c.done)
 			return
 
 		case <-c.reconnect:
@@ -66,7 +77,14 @@ func (c *Client) run() {
 			c.state.reset()
 			c.initSASL()
 
-			time.Sleep(c.backoff.Duration())
+			delay := c.backoff.Duration()
+			if cooldown := c.killCooldown(); cooldown > delay {
+				delay = cooldown
+			}
+			select {
+			case <-time.After(delay):
+			case <-c.quit:
+			}
 			c.tryConnect()
 		}
 	}
@@ -107,6 +125,9 @@ func (c *Client) tryConnect() {
 			if _, ok := err.(x509.UnknownAuthorityError); ok {
 				return
 			}
+			if _, ok := err.(*ServerNameError); ok {
+				return
+			}
 		} else {
 			return
 		}
@@ -119,26 +140,30 @@ func (c *Client) connect() error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	addr := net.JoinHostPort(c.Config.Host, c.Config.Port)
+	if err := c.bind(); err != nil {
+		return err
+	}
+
+	conn, err := c.dial(c.Config.Host, c.Config.Port)
+	if err != nil {
+		return c.bindError(err)
+	}
+
 	if c.Config.TLS {
-		conn, err := tls.DialWithDialer(c.dialer, "tcp", addr, c.Config.TLSConfig)
+		tlsConn, err := c.handshake(conn)
 		if err != nil {
+			conn.Close()
 			return err
 		}
 
-		c.conn = conn
+		c.conn = tlsConn
 	} else {
-		conn, err := c.dialer.Dial("tcp", addr)
-		if err != nil {
-			return err
-		}
-
 		c.conn = conn
 	}
 
 	c.connected = true
 	c.connChange(true, nil)
-	c.scan = bufio.NewScanner(c.conn)
+	c.scan = bufio.NewScanner(c.newKeepAliveReader())
 	c.scan.Buffer(c.recvBuf, cap(c.recvBuf))
 
 	c.register()
@@ -149,6 +174,55 @@ func (c *Client) connect() error {
 	return nil
 }
 
+func (c *Client) handshake(conn net.Conn) (*tls.Conn, error) {
+	config := c.TLSConfig()
+	if config == nil {
+		config = &tls.Config{}
+	}
+	if config.ServerName == "" {
+		config = config.Clone()
+		config.ServerName = c.Config.Host
+	}
+
+	tlsConn := tls.Client(conn, config)
+	conn.SetDeadline(time.Now().Add(c.Config.TLSTimeout))
+	if err := tlsConn.Handshake(); err != nil {
+		if isTimeout(err) {
+			return nil, ErrTLSTimeout
+		}
+		return nil, serverNameError(config.ServerName, err)
+	}
+	conn.SetDeadline(time.Time{})
+
+	if certs := tlsConn.ConnectionState().PeerCertificates; len(certs) > 0 {
+		c.peerCert = NewCertificate(certs[0])
+	}
+
+	return tlsConn, nil
+}
+
+// bind makes the dialer connect from BindAddress, if it is set
+func (c *Client) bind() error {
+	if c.Config.BindAddress != "" {
+		ip := net.ParseIP(c.Config.BindAddress)
+		if ip == nil {
+			return fmt.Errorf("Invalid bind address %s", c.Config.BindAddress)
+		}
+		c.dialer.LocalAddr = &net.TCPAddr{IP: ip}
+	}
+	return nil
+}
+
+// bindError makes failing to bind to the configured local address
+// distinguishable from the server being unreachable
+func (c *Client) bindError(err error) error {
+	var syscallErr *os.SyscallError
+	if c.Config.BindAddress != "" && errors.As(err, &syscallErr) && syscallErr.Syscall == "bind" {
+		return fmt.Errorf("Could not bind to %s: %v", c.Config.BindAddress, syscallErr.Err)
+	}
+	return err
+}
+
 func (c *Client) send() {
 	defer c.sendRecv.Done()
 
@@ -161,6 +235,7 @@ func (c *Client) send() {
 			return
 
 		case msg := <-c.out:
+			c.logRaw(true, strings.TrimSuffix(msg, "\r\n"))
 			_, err := c.conn.Write([]byte(msg))
 			if err != nil {
 				return
@@ -179,7 +254,11 @@ func (c *Client) recv() {
 				return
 
 			default:
-				c.connChange(false, nil)
+				err := c.scan.Err()
+				if err != ErrPingTimeout && err != ErrRegistrationTimeout {
+					err = nil
+				}
+				c.connChange(false, err)
 				close(c.reconnect)
 				return
 			}
@@ -189,16 +268,96 @@ func (c *Client) recv() {
 		if len(b) == 0 {
 			continue
 		}
+		c.logRaw(false, string(b))
 
 		msg := ParseMessage(string(b))
 		if msg == nil {
-			close(c.quit)
+			c.closeQuit()
 			c.connChange(false, ErrBadProtocol)
 			return
 		}
 
+		if c.addToBatch(msg) {
+			continue
+		}
+
 		c.handleMessage(msg)
 
+		if batch := GetBatch(msg); batch != nil && isMultilineBatch(batch.Type) {
+			msg = batch.multilineMessage()
+			if msg == nil || c.addToBatch(msg) {
+				continue
+			}
+		}
+
 		c.Messages <- msg
 	}
 }
+
+// keepAliveReader sends a PING when the connection has been idle for
+// interval and fails with ErrPingTimeout if nothing arrives within timeout,
+// it also fails with ErrRegistrationTimeout if registration has not completed
+// by registerDeadline
+type keepAliveReader struct {
+	conn     net.Conn
+	ping     func() error
+	interval time.Duration
+	timeout  time.Duration
+
+	registered       func() bool
+	registerDeadline time.Time
+}
+
+func (c *Client) newKeepAliveReader() *keepAliveReader {
+	return &keepAliveReader{
+		conn:             c.conn,
+		ping:             func() error { return c.write("PING :" + c.Config.Host) },
+		interval:         c.Config.PingInterval,
+		timeout:          c.Config.PingTimeout,
+		registered:       c.Registered,
+		registerDeadline: time.Now().Add(c.Config.RegistrationTimeout),
+	}
+}
+
+func (r *keepAliveReader) Read(p []byte) (int, error) {
+	deadline := time.Now().Add(r.interval)
+
+	if !r.registerDeadline.IsZero() {
+		if r.registered() {
+			r.registerDeadline = time.Time{}
+		} else if !time.Now().Before(r.registerDeadline) {
+			return 0, ErrRegistrationTimeout
+		} else if r.registerDeadline.Before(deadline) {
+			deadline = r.registerDeadline
+		}
+	}
+
+	r.conn.SetReadDeadline(deadline)
+	n, err := r.conn.Read(p)
+	if !isTimeout(err) {
+		return n, err
+	}
+
+	if !r.registerDeadline.IsZero() && !r.registered() {
+		return 0, ErrRegistrationTimeout
+	}
+
+	err = r.ping()
+	if err != nil {
+		return 0, err
+	}
+
+	r.conn.SetReadDeadline(time.Now().Add(r.timeout))
+	n, err = r.conn.Read(p)
+	if isTimeout(err) {
+		return n, ErrPingTimeout
+	}
+	return n, err
+}
+
+func isTimeout(err error) bool {
+	if err, ok := err.(net.Error); ok {
+		return err.Timeout()
+	}
+	return false
+}
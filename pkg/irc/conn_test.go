@@ -3,7 +3,12 @@ package irc
 import (
 	"bufio"
 	"bytes"
+	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
 	"log"
 	"net"
 	"testing"
@@ -99,6 +104,155 @@ func TestConnectTLS(t *testing.T) {
 	waitConnAndClose(t, c)
 }
 
+func TestConnectTLSTimeout(t *testing.T) {
+	// Accepts connections but never answers the handshake
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	c := NewClient(&Config{
+		Host:       "127.0.0.1",
+		Port:       port,
+		TLS:        true,
+		TLSTimeout: 20 * time.Millisecond,
+	})
+	assert.Equal(t, ErrTLSTimeout, c.connect())
+}
+
+func TestConnectServerName(t *testing.T) {
+	cert, err := tls.X509KeyPair(testCert, testKey)
+	assert.Nil(t, err)
+
+	hellos := make(chan *tls.ClientHelloInfo, 2)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			hellos <- hello
+			return nil, nil
+		},
+	})
+	assert.Nil(t, err)
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go ioutil.ReadAll(conn)
+		}
+	}()
+
+	block, _ := pem.Decode(testCert)
+	ca, err := x509.ParseCertificate(block.Bytes)
+	assert.Nil(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	// The certificate is only valid for example.com
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	c := NewClient(&Config{
+		Host:      "irc.example.com",
+		Port:      port,
+		TLS:       true,
+		TLSConfig: &tls.Config{RootCAs: roots},
+	})
+	c.resolver = stubResolver{addrs: ipAddrs("127.0.0.1")}
+
+	err = c.connect()
+	assert.Equal(t, "irc.example.com", (<-hellos).ServerName)
+	if assert.IsType(t, &ServerNameError{}, err) {
+		assert.Equal(t, "irc.example.com", err.(*ServerNameError).ServerName)
+	}
+
+	c.Config.TLSConfig.ServerName = "example.com"
+	c.Config.TLSConfig.NextProtos = []string{"irc"}
+	assert.Nil(t, c.connect())
+	defer c.conn.Close()
+
+	hello := <-hellos
+	assert.Equal(t, "example.com", hello.ServerName)
+	assert.Equal(t, []string{"irc"}, hello.SupportedProtos)
+}
+
+func TestPeerCertificate(t *testing.T) {
+	c := NewClient(&Config{
+		Host: "127.0.0.1",
+		Port: "45679",
+		TLS:  true,
+		TLSConfig: &tls.Config{
+			InsecureSkipVerify: true,
+		},
+	})
+	assert.Nil(t, c.PeerCertificate())
+	c.Connect()
+	waitConnAndClose(t, c)
+
+	block, _ := pem.Decode(testCert)
+	expected, err := x509.ParseCertificate(block.Bytes)
+	assert.Nil(t, err)
+	hash := sha256.Sum256(block.Bytes)
+
+	cert := c.PeerCertificate()
+	assert.NotNil(t, cert)
+	assert.Equal(t, expected.Subject.String(), cert.Subject)
+	assert.Equal(t, expected.Issuer.String(), cert.Issuer)
+	assert.True(t, expected.NotAfter.Equal(cert.NotAfter))
+	assert.Equal(t, hex.EncodeToString(hash[:]), cert.Fingerprint)
+	assert.Equal(t, cert.ExpiresWithin(0), time.Now().After(expected.NotAfter))
+}
+
+func TestConnectBindAddress(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	c := NewClient(&Config{
+		Host:        "127.0.0.1",
+		Port:        port,
+		BindAddress: "127.0.0.2",
+	})
+	assert.Nil(t, c.connect())
+
+	conn := <-accepted
+	assert.Equal(t, "127.0.0.2", conn.RemoteAddr().(*net.TCPAddr).IP.String())
+	conn.Close()
+	c.conn.Close()
+
+	c = NewClient(&Config{
+		Host:        "127.0.0.1",
+		Port:        port,
+		BindAddress: "192.0.2.1",
+	})
+	err = c.connect()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Could not bind to 192.0.2.1")
+
+	c = NewClient(&Config{
+		Host:        "127.0.0.1",
+		Port:        port,
+		BindAddress: "not an ip",
+	})
+	assert.EqualError(t, c.connect(), "Invalid bind address not an ip")
+}
+
 func TestConnectDefaultPorts(t *testing.T) {
 	c := NewClient(&Config{
 		Host: "127.0.0.1",
@@ -167,6 +321,104 @@ func TestRecvTriggersReconnect(t *testing.T) {
 	}
 }
 
+func TestRecvPingTimeout(t *testing.T) {
+	c := NewClient(&Config{
+		Host:         "127.0.0.1",
+		PingInterval: 10 * time.Millisecond,
+		PingTimeout:  10 * time.Millisecond,
+	})
+	conn, server := net.Pipe()
+	defer server.Close()
+	c.conn = conn
+	c.registered = true
+	c.scan = bufio.NewScanner(c.newKeepAliveReader())
+
+	c.sendRecv.Add(1)
+	go c.recv()
+
+	buf := make([]byte, 1024)
+	n, err := server.Read(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "PING :127.0.0.1\r\n", string(buf[:n]))
+
+	select {
+	case state := <-c.ConnectionChanged:
+		assert.False(t, state.Connected)
+		assert.Equal(t, ErrPingTimeout, state.Error)
+
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("Ping timeout not detected")
+	}
+
+	_, ok := <-c.reconnect
+	assert.False(t, ok)
+}
+
+func TestRecvPingKeepsAlive(t *testing.T) {
+	c := NewClient(&Config{
+		Host:         "test",
+		PingInterval: 10 * time.Millisecond,
+		PingTimeout:  50 * time.Millisecond,
+	})
+	conn, server := net.Pipe()
+	defer server.Close()
+	c.conn = conn
+	c.registered = true
+	c.scan = bufio.NewScanner(c.newKeepAliveReader())
+
+	c.sendRecv.Add(1)
+	go c.recv()
+
+	buf := make([]byte, 1024)
+	n, err := server.Read(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "PING :test\r\n", string(buf[:n]))
+
+	server.Write([]byte("PONG :test\r\n"))
+	assert.Equal(t, &Message{Command: PONG, Params: []string{"test"}}, <-c.Messages)
+
+	n, err = server.Read(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "PING :test\r\n", string(buf[:n]))
+}
+
+func TestRecvRegistrationTimeout(t *testing.T) {
+	c := NewClient(&Config{
+		RegistrationTimeout: 20 * time.Millisecond,
+	})
+	conn, server := net.Pipe()
+	defer server.Close()
+	c.conn = conn
+	c.scan = bufio.NewScanner(c.newKeepAliveReader())
+
+	c.sendRecv.Add(1)
+	go c.recv()
+
+	go func() {
+		for {
+			_, err := server.Write([]byte("NOTICE * :Looking up your hostname\r\n"))
+			if err != nil {
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+
+	for {
+		select {
+		case <-c.Messages:
+
+		case state := <-c.ConnectionChanged:
+			assert.False(t, state.Connected)
+			assert.Equal(t, ErrRegistrationTimeout, state.Error)
+			return
+
+		case <-time.After(500 * time.Millisecond):
+			t.Fatal("Registration timeout not detected")
+		}
+	}
+}
+
 func TestClose(t *testing.T) {
 	c := NewClient(&Config{})
 	close(c.quit)
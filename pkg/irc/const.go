@@ -4,16 +4,19 @@ const (
 	CAP          = "CAP"
 	AUTHENTICATE = "AUTHENTICATE"
 	PASS         = "PASS"
+	WEBIRC       = "WEBIRC"
 	NICK         = "NICK"
 	USER         = "USER"
 	OPER         = "OPER"
 	QUIT         = "QUIT"
 	JOIN         = "JOIN"
 	PART         = "PART"
+	KICK         = "KICK"
 	TOPIC        = "TOPIC"
 	NAMES        = "NAMES"
 	LIST         = "LIST"
 	MOTD         = "MOTD"
+	HELP         = "HELP"
 	VERSION      = "VERSION"
 	ADMIN        = "ADMIN"
 	CONNECT      = "CONNECT"
@@ -23,11 +26,22 @@ const (
 	MODE         = "MODE"
 	PRIVMSG      = "PRIVMSG"
 	NOTICE       = "NOTICE"
+	TAGMSG       = "TAGMSG"
 	USERHOST     = "USERHOST"
 	KILL         = "KILL"
 	ERROR        = "ERROR"
+	AWAY         = "AWAY"
 	PING         = "PING"
 	PONG         = "PONG"
+	BATCH        = "BATCH"
+	CHATHISTORY  = "CHATHISTORY"
+	FAIL         = "FAIL"
+	WARN         = "WARN"
+	NOTE         = "NOTE"
+	REGISTER     = "REGISTER"
+	VERIFY       = "VERIFY"
+	RENAME       = "RENAME"
+	METADATA     = "METADATA"
 
 	RPL_WELCOME           = "001"
 	RPL_YOURHOST          = "002"
@@ -59,9 +73,11 @@ const (
 	RPL_WHOISSERVER       = "312"
 	RPL_WHOISOPERATOR     = "313"
 	RPL_WHOWASUSER        = "314"
+	RPL_ENDOFWHO          = "315"
 	RPL_WHOISIDLE         = "317"
 	RPL_ENDOFWHOIS        = "318"
 	RPL_WHOISCHANNELS     = "319"
+	RPL_WHOISSPECIAL      = "320"
 	RPL_LISTSTART         = "321"
 	RPL_LIST              = "322"
 	RPL_LISTEND           = "323"
@@ -70,13 +86,16 @@ const (
 	RPL_NOTOPIC           = "331"
 	RPL_TOPIC             = "332"
 	RPL_TOPICWHOTIME      = "333"
+	RPL_WHOISBOT          = "335"
 	RPL_INVITING          = "341"
 	RPL_INVITELIST        = "346"
 	RPL_ENDOFINVITELIST   = "347"
 	RPL_EXCEPTLIST        = "348"
 	RPL_ENDOFEXCEPTLIST   = "349"
 	RPL_VERSION           = "351"
+	RPL_WHOREPLY          = "352"
 	RPL_NAMREPLY          = "353"
+	RPL_WHOSPCRPL         = "354"
 	RPL_ENDOFNAMES        = "366"
 	RPL_BANLIST           = "367"
 	RPL_ENDOFBANLIST      = "368"
@@ -84,6 +103,7 @@ const (
 	RPL_MOTDSTART         = "375"
 	RPL_MOTD              = "372"
 	RPL_ENDOFMOTD         = "376"
+	RPL_WHOISHOST         = "378"
 	RPL_YOUREOPER         = "381"
 	RPL_REHASHING         = "382"
 	ERR_UNKNOWNERROR      = "400"
@@ -118,9 +138,15 @@ const (
 	ERR_NOOPERHOST        = "491"
 	ERR_UMODEUNKNOWNFLAG  = "501"
 	ERR_USERSDONTMATCH    = "502"
+	ERR_HELPNOTFOUND      = "524"
 	RPL_STARTTLS          = "670"
 	ERR_STARTTLS          = "691"
+	RPL_HELPSTART         = "704"
+	RPL_HELPTXT           = "705"
+	RPL_ENDOFHELP         = "706"
 	ERR_NOPRIVS           = "723"
+	RPL_KEYVALUE          = "761"
+	RPL_KEYNOTSET         = "766"
 	RPL_LOGGEDIN          = "900"
 	RPL_LOGGEDOUT         = "901"
 	ERR_NICKLOCKED        = "902"
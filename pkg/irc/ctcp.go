@@ -39,6 +39,9 @@ func EncodeCTCP(ctcp *CTCP) string {
 	if ctcp == nil || ctcp.Command == "" {
 		return ""
 	}
+	if ctcp.Params == "" {
+		return fmt.Sprintf("\x01%s\x01", ctcp.Command)
+	}
 	return fmt.Sprintf("\x01%s %s\x01", ctcp.Command, ctcp.Params)
 }
 
@@ -1,6 +1,7 @@
 package irc
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -9,23 +10,40 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
+// dccAcceptTimeout is how long to wait for the sender of a passive
+// DCC SEND to connect
+var dccAcceptTimeout = time.Minute
+
 type DCCSend struct {
 	File   string `json:"file"`
 	IP     string `json:"ip"`
 	Port   string `json:"port"`
 	Length uint64 `json:"length"`
+	// Token identifies a passive offer, where the receiver listens
+	// and the sender connects
+	Token string `json:"token"`
+}
+
+// Passive reports whether the sender wants the receiver to listen
+func (p *DCCSend) Passive() bool {
+	return p.Port == "0" && p.Token != ""
 }
 
 func ParseDCCSend(ctcp *CTCP) *DCCSend {
 	params := strings.Split(ctcp.Params, " ")
 
 	if len(params) > 4 {
-		ip, err := strconv.Atoi(params[2])
-		if err != nil {
-			return nil
+		ip := params[2]
+		if net.ParseIP(ip) == nil {
+			n, err := strconv.Atoi(ip)
+			if err != nil {
+				return nil
+			}
+			ip = intToIP(n)
 		}
 
 		length, err := strconv.ParseUint(params[4], 10, 64)
@@ -38,29 +56,173 @@ func ParseDCCSend(ctcp *CTCP) *DCCSend {
 			filename = ""
 		}
 
-		return &DCCSend{
+		pack := &DCCSend{
 			File:   filename,
-			IP:     intToIP(ip),
+			IP:     ip,
 			Port:   params[3],
 			Length: length,
 		}
+		if len(params) > 5 {
+			pack.Token = params[5]
+		}
+
+		return pack
 	}
 
 	return nil
 }
 
-func DownloadDCC(w io.Writer, pack *DCCSend, progress chan DownloadProgress) error {
+// EncodeDCCSend makes a DCC SEND offer, or the answer to a passive offer
+// when Token is set
+func EncodeDCCSend(pack *DCCSend) *CTCP {
+	ip := pack.IP
+	if ip4 := net.ParseIP(ip).To4(); ip4 != nil {
+		ip = strconv.FormatUint(uint64(binary.BigEndian.Uint32(ip4)), 10)
+	}
+
+	params := fmt.Sprintf("SEND %s %s %s %d", pack.File, ip, pack.Port, pack.Length)
+	if pack.Token != "" {
+		params += " " + pack.Token
+	}
+
+	return &CTCP{
+		Command: "DCC",
+		Params:  params,
+	}
+}
+
+// RateLimiter limits throughput to a number of bytes per second, a single
+// limiter can be shared between downloads to cap their combined speed
+type RateLimiter struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+	lock   sync.Mutex
+}
+
+// NewRateLimiter returns a limiter allowing bytesPerSecond, 0 means unlimited
+func NewRateLimiter(bytesPerSecond int) *RateLimiter {
+	l := &RateLimiter{}
+	l.SetRate(bytesPerSecond)
+	return l
+}
+
+func (l *RateLimiter) SetRate(bytesPerSecond int) {
+	l.lock.Lock()
+	if float64(bytesPerSecond) != l.rate {
+		l.rate = float64(bytesPerSecond)
+		l.tokens = 0
+		l.last = time.Now()
+	}
+	l.lock.Unlock()
+}
+
+// Wait blocks until n more bytes can be transferred
+func (l *RateLimiter) Wait(n int) {
+	if l == nil {
+		return
+	}
+
+	l.lock.Lock()
+	if l.rate <= 0 {
+		l.lock.Unlock()
+		return
+	}
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.rate {
+		l.tokens = l.rate
+	}
+	l.last = now
+	l.tokens -= float64(n)
+
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.lock.Unlock()
+
+	time.Sleep(wait)
+}
+
+// DownloadDCC connects to the sender of pack and receives the file, the
+// transfer is slowed down to stay within all of limiters
+func DownloadDCC(w io.Writer, pack *DCCSend, progress chan DownloadProgress, limiters ...*RateLimiter) error {
+	return DownloadDCCContext(context.Background(), w, pack, progress, limiters...)
+}
+
+// DownloadDCCContext is DownloadDCC that stops and returns ctx.Err() when
+// ctx is done
+func DownloadDCCContext(ctx context.Context, w io.Writer, pack *DCCSend, progress chan DownloadProgress, limiters ...*RateLimiter) error {
 	if progress != nil {
 		progress <- DownloadProgress{
 			File: pack.File,
 		}
 	}
 
-	conn, err := net.DialTimeout("tcp", net.JoinHostPort(pack.IP, pack.Port), 10*time.Second)
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(pack.IP, pack.Port))
 	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		return err
 	}
+
+	return receiveDCC(ctx, conn, w, pack, progress, limiters)
+}
+
+// AcceptDCC receives a passive DCC SEND, the sender connects to ln
+// after getting the answer made by EncodeDCCSend
+func AcceptDCC(w io.Writer, ln net.Listener, pack *DCCSend, progress chan DownloadProgress, limiters ...*RateLimiter) error {
+	return AcceptDCCContext(context.Background(), w, ln, pack, progress, limiters...)
+}
+
+// AcceptDCCContext is AcceptDCC that stops and returns ctx.Err() when
+// ctx is done, ln gets closed then
+func AcceptDCCContext(ctx context.Context, w io.Writer, ln net.Listener, pack *DCCSend, progress chan DownloadProgress, limiters ...*RateLimiter) error {
+	if progress != nil {
+		progress <- DownloadProgress{
+			File: pack.File,
+		}
+	}
+
+	if tcp, ok := ln.(*net.TCPListener); ok {
+		tcp.SetDeadline(time.Now().Add(dccAcceptTimeout))
+	}
+
+	stop := closeOnDone(ctx, ln)
+	conn, err := ln.Accept()
+	stop()
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	return receiveDCC(ctx, conn, w, pack, progress, limiters)
+}
+
+// closeOnDone closes c when ctx is done before stop gets called
+func closeOnDone(ctx context.Context, c io.Closer) (stop func()) {
+	stopped := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.Close()
+		case <-stopped:
+		}
+	}()
+	return func() {
+		close(stopped)
+	}
+}
+
+func receiveDCC(ctx context.Context, conn net.Conn, w io.Writer, pack *DCCSend, progress chan DownloadProgress, limiters []*RateLimiter) error {
 	defer conn.Close()
+	defer closeOnDone(ctx, conn)()
 
 	totalBytes := uint64(0)
 	accBytes := uint64(0)
@@ -73,6 +235,9 @@ func DownloadDCC(w io.Writer, pack *DCCSend, progress chan DownloadProgress) err
 		conn.SetReadDeadline(time.Now().Add(10 * time.Second))
 		n, err := conn.Read(buf)
 		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			if err != io.EOF {
 				return err
 			}
@@ -85,11 +250,20 @@ func DownloadDCC(w io.Writer, pack *DCCSend, progress chan DownloadProgress) err
 			return err
 		}
 
+		// Not reading from the connection while waiting makes the
+		// sender slow down as well
+		for _, l := range limiters {
+			l.Wait(n)
+		}
+
 		accBytes += uint64(n)
 		totalBytes += uint64(n)
 
 		_, err = conn.Write(uint64Bytes(totalBytes))
 		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			return err
 		}
 
@@ -0,0 +1,210 @@
+package irc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDCCSend(t *testing.T) {
+	pack := ParseDCCSend(&CTCP{Command: "DCC", Params: "SEND file.txt 2130706433 5000 1024"})
+	assert.Equal(t, &DCCSend{
+		File:   "file.txt",
+		IP:     "127.0.0.1",
+		Port:   "5000",
+		Length: 1024,
+	}, pack)
+	assert.False(t, pack.Passive())
+
+	pack = ParseDCCSend(&CTCP{Command: "DCC", Params: "SEND file.txt 2130706433 0 1024 42"})
+	assert.Equal(t, &DCCSend{
+		File:   "file.txt",
+		IP:     "127.0.0.1",
+		Port:   "0",
+		Length: 1024,
+		Token:  "42",
+	}, pack)
+	assert.True(t, pack.Passive())
+
+	pack = ParseDCCSend(&CTCP{Command: "DCC", Params: "SEND file.txt ::1 0 1024 42"})
+	assert.Equal(t, "::1", pack.IP)
+	assert.True(t, pack.Passive())
+
+	assert.Nil(t, ParseDCCSend(&CTCP{Command: "DCC", Params: "SEND file.txt nope 0 1024"}))
+	assert.Nil(t, ParseDCCSend(&CTCP{Command: "DCC", Params: "SEND file.txt 2130706433 0"}))
+}
+
+func TestEncodeDCCSend(t *testing.T) {
+	ctcp := EncodeDCCSend(&DCCSend{
+		File:   "file.txt",
+		IP:     "127.0.0.1",
+		Port:   "6000",
+		Length: 1024,
+		Token:  "42",
+	})
+	assert.Equal(t, "DCC", ctcp.Command)
+	assert.Equal(t, "SEND file.txt 2130706433 6000 1024 42", ctcp.Params)
+
+	ctcp = EncodeDCCSend(&DCCSend{
+		File:   "file.txt",
+		IP:     "::1",
+		Port:   "6000",
+		Length: 1024,
+	})
+	assert.Equal(t, "SEND file.txt ::1 6000 1024", ctcp.Params)
+
+	pack := &DCCSend{File: "file.txt", IP: "10.0.0.1", Port: "0", Length: 5, Token: "7"}
+	assert.Equal(t, pack, ParseDCCSend(EncodeDCCSend(pack)))
+}
+
+func TestAcceptDCC(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	data := []byte("hello")
+	go func() {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.Write(data)
+		ack := make([]byte, 4)
+		for {
+			if _, err := conn.Read(ack); err != nil ||
+				binary.BigEndian.Uint32(ack) >= uint32(len(data)) {
+				return
+			}
+		}
+	}()
+
+	var buf bytes.Buffer
+	pack := &DCCSend{File: "file.txt", Port: "0", Length: uint64(len(data)), Token: "1"}
+	assert.Nil(t, AcceptDCC(&buf, ln, pack, nil))
+	assert.Equal(t, data, buf.Bytes())
+}
+
+func TestAcceptDCCRateLimit(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	data := bytes.Repeat([]byte("x"), 64*1024)
+	go func() {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.Write(data)
+		ack := make([]byte, 8)
+		for {
+			if _, err := io.ReadFull(conn, ack); err != nil ||
+				binary.BigEndian.Uint64(ack) >= uint64(len(data)) {
+				return
+			}
+		}
+	}()
+
+	var buf bytes.Buffer
+	pack := &DCCSend{File: "file.txt", Port: "0", Length: uint64(len(data)), Token: "1"}
+	progress := make(chan DownloadProgress, 16)
+
+	start := time.Now()
+	assert.Nil(t, AcceptDCC(&buf, ln, pack, progress, NewRateLimiter(128*1024), NewRateLimiter(0)))
+	elapsed := time.Since(start)
+
+	assert.Equal(t, data, buf.Bytes())
+	// 64 KiB at 128 KiB/s
+	assert.True(t, elapsed > 400*time.Millisecond, elapsed)
+	assert.True(t, elapsed < 1500*time.Millisecond, elapsed)
+}
+
+func TestRateLimiterShared(t *testing.T) {
+	l := NewRateLimiter(64 * 1024)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 4; j++ {
+				l.Wait(4 * 1024)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 32 KiB in total at 64 KiB/s
+	elapsed := time.Since(start)
+	assert.True(t, elapsed > 400*time.Millisecond, elapsed)
+	assert.True(t, elapsed < 1500*time.Millisecond, elapsed)
+
+	start = time.Now()
+	NewRateLimiter(0).Wait(1 << 30)
+	var unset *RateLimiter
+	unset.Wait(1 << 30)
+	assert.True(t, time.Since(start) < 100*time.Millisecond)
+}
+
+func TestDownloadDCCCancel(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.Write([]byte("partial"))
+		<-done
+	}()
+
+	var buf bytes.Buffer
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	pack := &DCCSend{File: "file.txt", IP: "127.0.0.1", Port: port, Length: 1024}
+	progress := make(chan DownloadProgress, 16)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-progress
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	assert.Equal(t, context.Canceled, DownloadDCCContext(ctx, &buf, pack, progress))
+	assert.True(t, time.Since(start) < time.Second)
+	assert.Equal(t, "partial", buf.String())
+}
+
+func TestAcceptDCCCancel(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	pack := &DCCSend{File: "file.txt", Port: "0", Length: 5, Token: "1"}
+	assert.Equal(t, context.Canceled, AcceptDCCContext(ctx, ioutil.Discard, ln, pack, nil))
+	assert.True(t, time.Since(start) < time.Second)
+}
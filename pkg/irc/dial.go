@@ -0,0 +1,182 @@
+package irc
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+)
+
+type resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+type dialResult struct {
+	conn net.Conn
+	err  error
+}
+
+// dial connects to host using happy eyeballs (RFC 8305), the resolved
+// addresses of both families are raced with FallbackDelay between each attempt
+// and the first connection to succeed gets used. Resolving gets DNSTimeout
+// and connecting gets ConnectTimeout
+func (c *Client) dial(host, port string) (net.Conn, error) {
+	if net.ParseIP(host) != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), c.Config.ConnectTimeout)
+		defer cancel()
+
+		conn, err := c.dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+		return conn, connectError(ctx, err)
+	}
+
+	ips, err := c.lookup(host)
+	if err != nil {
+		return nil, err
+	}
+	ips = interleaveAddrs(ips, c.Config.PreferIPv4)
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Config.ConnectTimeout)
+	defer cancel()
+
+	results := make(chan dialResult, len(ips))
+	next := 0
+	pending := 0
+	start := func() {
+		addr := net.JoinHostPort(ips[next].String(), port)
+		next++
+		pending++
+
+		go func() {
+			conn, err := c.dialer.DialContext(ctx, "tcp", addr)
+			results <- dialResult{conn, err}
+		}()
+	}
+
+	start()
+
+	var firstErr error
+	for pending > 0 {
+		var timer *time.Timer
+		var fallback <-chan time.Time
+		if next < len(ips) {
+			timer = time.NewTimer(c.Config.FallbackDelay)
+			fallback = timer.C
+		}
+
+		select {
+		case res := <-results:
+			pending--
+
+			if res.err == nil {
+				if timer != nil {
+					timer.Stop()
+				}
+				go closeLosers(results, pending)
+				return res.conn, nil
+			}
+
+			if firstErr == nil {
+				firstErr = res.err
+			}
+			if next < len(ips) {
+				start()
+			}
+
+		case <-fallback:
+			start()
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+
+	return nil, connectError(ctx, firstErr)
+}
+
+// ProbeTLS reports whether the server completes a TLS handshake on the
+// configured port, it gets dialed with the same timeouts and address order
+// as Connect uses. The certificate is not verified
+func (c *Client) ProbeTLS() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.bind(); err != nil {
+		return false
+	}
+
+	conn, err := c.dial(c.Config.Host, c.Config.Port)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName:         c.Config.Host,
+		InsecureSkipVerify: true,
+	})
+	conn.SetDeadline(time.Now().Add(c.Config.TLSTimeout))
+	return tlsConn.Handshake() == nil
+}
+
+func (c *Client) lookup(host string) ([]net.IPAddr, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Config.DNSTimeout)
+	defer cancel()
+
+	ips, err := c.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, ErrDNSTimeout
+		}
+		return nil, err
+	}
+	if len(ips) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return ips, nil
+}
+
+// connectError returns ErrConnectTimeout when err is caused by ctx or
+// a single attempt running out of time
+func connectError(ctx context.Context, err error) error {
+	if err != nil && (ctx.Err() == context.DeadlineExceeded || isTimeout(err)) {
+		return ErrConnectTimeout
+	}
+	return err
+}
+
+// closeLosers closes any connections that were still being attempted
+// when another one won the race
+func closeLosers(results chan dialResult, pending int) {
+	for i := 0; i < pending; i++ {
+		if res := <-results; res.conn != nil {
+			res.conn.Close()
+		}
+	}
+}
+
+// interleaveAddrs orders the addresses so that the families alternate,
+// starting with IPv6 unless preferIPv4 is set
+func interleaveAddrs(ips []net.IPAddr, preferIPv4 bool) []net.IPAddr {
+	var preferred, other []net.IPAddr
+	for _, ip := range ips {
+		if (ip.IP.To4() != nil) == preferIPv4 {
+			preferred = append(preferred, ip)
+		} else {
+			other = append(other, ip)
+		}
+	}
+
+	result := make([]net.IPAddr, 0, len(ips))
+	for len(preferred) > 0 || len(other) > 0 {
+		if len(preferred) > 0 {
+			result = append(result, preferred[0])
+			preferred = preferred[1:]
+		}
+		if len(other) > 0 {
+			result = append(result, other[0])
+			other = other[1:]
+		}
+	}
+	return result
+}
@@ -0,0 +1,143 @@
+package irc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubResolver struct {
+	addrs []net.IPAddr
+	err   error
+	stall bool
+}
+
+func (r stubResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	if r.stall {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return r.addrs, r.err
+}
+
+func ipAddrs(ips ...string) []net.IPAddr {
+	addrs := make([]net.IPAddr, len(ips))
+	for i, ip := range ips {
+		addrs[i] = net.IPAddr{IP: net.ParseIP(ip)}
+	}
+	return addrs
+}
+
+func TestInterleaveAddrs(t *testing.T) {
+	addrs := ipAddrs("10.0.0.1", "10.0.0.2", "10.0.0.3", "2001:db8::1", "2001:db8::2")
+
+	assert.Equal(t, ipAddrs(
+		"2001:db8::1", "10.0.0.1", "2001:db8::2", "10.0.0.2", "10.0.0.3",
+	), interleaveAddrs(addrs, false))
+
+	assert.Equal(t, ipAddrs(
+		"10.0.0.1", "2001:db8::1", "10.0.0.2", "2001:db8::2", "10.0.0.3",
+	), interleaveAddrs(addrs, true))
+}
+
+func TestDialFallsBack(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+
+	for _, preferIPv4 := range []bool{false, true} {
+		c := NewClient(&Config{PreferIPv4: preferIPv4})
+		// The IPv6 address is in the discard prefix, so it either fails
+		// or hangs depending on the network, IPv4 has to win either way
+		c.resolver = stubResolver{addrs: ipAddrs("100::1", "127.0.0.1")}
+
+		conn, err := c.dial("irc.example.com", port)
+		assert.Nil(t, err)
+		if assert.NotNil(t, conn) {
+			assert.Equal(t, "127.0.0.1", conn.RemoteAddr().(*net.TCPAddr).IP.String())
+			conn.Close()
+		}
+	}
+}
+
+func TestDialErrors(t *testing.T) {
+	c := NewClient(&Config{})
+	c.resolver = stubResolver{err: errors.New("lookup failed")}
+	_, err := c.dial("irc.example.com", "6667")
+	assert.EqualError(t, err, "lookup failed")
+
+	c.resolver = stubResolver{}
+	_, err = c.dial("irc.example.com", "6667")
+	assert.NotNil(t, err)
+}
+
+func TestDialTimeouts(t *testing.T) {
+	c := NewClient(&Config{DNSTimeout: 20 * time.Millisecond})
+	c.resolver = stubResolver{stall: true}
+	_, err := c.dial("irc.example.com", "6667")
+	assert.Equal(t, ErrDNSTimeout, err)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+
+	// Stall every attempt past the deadline before it gets to connect
+	stall := func(network, address string, conn syscall.RawConn) error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}
+
+	c = NewClient(&Config{ConnectTimeout: 20 * time.Millisecond})
+	c.dialer.Control = stall
+	_, err = c.dial("127.0.0.1", port)
+	assert.Equal(t, ErrConnectTimeout, err)
+
+	c.resolver = stubResolver{addrs: ipAddrs("127.0.0.1")}
+	_, err = c.dial("irc.example.com", port)
+	assert.Equal(t, ErrConnectTimeout, err)
+}
+
+func TestProbeTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+
+	c := NewClient(&Config{Host: host, Port: port})
+	assert.True(t, c.ProbeTLS())
+
+	// The listener never answers the handshake
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+	_, port, _ = net.SplitHostPort(ln.Addr().String())
+
+	c = NewClient(&Config{Host: "127.0.0.1", Port: port, TLSTimeout: 50 * time.Millisecond})
+	assert.False(t, c.ProbeTLS())
+
+	c = NewClient(&Config{Host: "irc.example.com", Port: port, DNSTimeout: 20 * time.Millisecond})
+	c.resolver = stubResolver{stall: true}
+	assert.False(t, c.ProbeTLS())
+
+	c = NewClient(&Config{Host: host, Port: port, BindAddress: "invalid"})
+	assert.False(t, c.ProbeTLS())
+}
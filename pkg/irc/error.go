@@ -0,0 +1,156 @@
+package irc
+
+import (
+	"crypto/x509"
+	"errors"
+	"strings"
+)
+
+const closingLinkPrefix = "closing link"
+
+// LinkClosedError is the connection error when the server closed the
+// connection with an ERROR message
+type LinkClosedError struct {
+	Reason string
+}
+
+func (e *LinkClosedError) Error() string {
+	if e.Reason == "" {
+		return "Closing link"
+	}
+	return "Closing link: " + e.Reason
+}
+
+const (
+	PhaseDNS          = "dns"
+	PhaseConnect      = "connect"
+	PhaseTLS          = "tls"
+	PhaseRegistration = "registration"
+)
+
+var timeoutMessages = map[string]string{
+	PhaseDNS:          "Timed out resolving the host",
+	PhaseConnect:      "Timed out connecting to the server",
+	PhaseTLS:          "Timed out during the TLS handshake",
+	PhaseRegistration: "Timed out waiting for the server to complete registration",
+}
+
+// TimeoutError is the connection error when one of the phases of
+// connecting took longer than its timeout
+type TimeoutError struct {
+	Phase string
+}
+
+func (e *TimeoutError) Error() string {
+	if msg, ok := timeoutMessages[e.Phase]; ok {
+		return msg
+	}
+	return "Timed out"
+}
+
+func (e *TimeoutError) Timeout() bool {
+	return true
+}
+
+// ServerNameError is the connection error when the TLS handshake failed
+// because of the server name sent with SNI, either the server did not
+// recognize it or its certificate is not valid for it
+type ServerNameError struct {
+	ServerName string
+	Err        error
+}
+
+func (e *ServerNameError) Error() string {
+	return "TLS handshake failed for server name " + e.ServerName + ": " + e.Err.Error()
+}
+
+func (e *ServerNameError) Unwrap() error {
+	return e.Err
+}
+
+// SASLMechanismsError is set when the server answered with RPL_SASLMECHS
+// and none of the mechanisms it supports can be used
+type SASLMechanismsError struct {
+	Available []string
+}
+
+func (e *SASLMechanismsError) Error() string {
+	if len(e.Available) == 0 {
+		return "SASL authentication failed, the server did not list any mechanisms"
+	}
+	return "SASL authentication failed, the server only supports " + strings.Join(e.Available, ", ")
+}
+
+// serverNameError wraps err in a ServerNameError when it was caused by
+// the server name
+func serverNameError(serverName string, err error) error {
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &hostnameErr) || strings.Contains(err.Error(), "unrecognized name") {
+		return &ServerNameError{ServerName: serverName, Err: err}
+	}
+	return err
+}
+
+// ParseClosingLink returns the reason from an ERROR message sent by a
+// server before it closes the connection, like:
+//
+//	Closing Link: nick[host] (K-Lined)
+//	Closing link: (user@host) [Killed (oper (reason))]
+//
+// ok is false when the message is some other error
+func ParseClosingLink(msg *Message) (reason string, ok bool) {
+	if msg.Command != ERROR {
+		return "", false
+	}
+
+	text := strings.TrimSpace(msg.LastParam())
+	if len(text) < len(closingLinkPrefix) ||
+		!strings.EqualFold(text[:len(closingLinkPrefix)], closingLinkPrefix) {
+		return "", false
+	}
+	text = strings.TrimSpace(strings.TrimPrefix(text[len(closingLinkPrefix):], ":"))
+
+	if group, ok := lastGroup(text); ok {
+		return group, true
+	}
+
+	// No reason in brackets, skip the address of the client
+	if i := strings.IndexByte(text, ' '); i > 0 {
+		return strings.TrimSpace(text[i+1:]), true
+	}
+	return "", true
+}
+
+// lastGroup returns the content of the last top level group of
+// parentheses or square brackets in s
+func lastGroup(s string) (string, bool) {
+	depth := 0
+	start := -1
+	group := ""
+	found := false
+
+	for i, c := range s {
+		switch c {
+		case '(', '[':
+			if depth == 0 {
+				start = i + 1
+			}
+			depth++
+
+		case ')', ']':
+			if depth > 0 {
+				depth--
+				if depth == 0 {
+					group = s[start:i]
+					found = true
+				}
+			}
+		}
+	}
+
+	// Unbalanced, the reason got cut off
+	if depth > 0 {
+		return strings.TrimSpace(s[start:]), true
+	}
+	return strings.TrimSpace(group), found
+}
@@ -0,0 +1,45 @@
+package irc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseClosingLink(t *testing.T) {
+	cases := []struct {
+		line   string
+		reason string
+	}{
+		{"ERROR :Closing Link: nick[1.2.3.4] (K-Lined)", "K-Lined"},
+		{"ERROR :Closing Link: 1.2.3.4 (Banned)", "Banned"},
+		{"ERROR :Closing link: (user@host.com) [Killed (oper (go away))]", "Killed (oper (go away))"},
+		{"ERROR :Closing Link: nick by irc.example.com (Ping timeout)", "Ping timeout"},
+		{"ERROR :Closing Link: 1.2.3.4 (Throttled: Reconnecting too fast) -Email staff@example.com for more information.", "Throttled: Reconnecting too fast"},
+		{"ERROR :Closing Link: nick[host] (Quit: bye (see you))", "Quit: bye (see you)"},
+		{"ERROR :closing link: host (Excess Flood", "Excess Flood"},
+		{"ERROR :Closing Link: host Connection reset", "Connection reset"},
+		{"ERROR :Closing Link: host", ""},
+		{"ERROR :Closing Link:", ""},
+	}
+
+	for _, tc := range cases {
+		reason, ok := ParseClosingLink(ParseMessage(tc.line))
+		assert.True(t, ok, tc.line)
+		assert.Equal(t, tc.reason, reason, tc.line)
+	}
+
+	for _, line := range []string{
+		"ERROR :Your host is trying to (re)connect too fast -- throttled",
+		"ERROR :Closing",
+		"NOTICE * :Closing Link: host (K-Lined)",
+	} {
+		_, ok := ParseClosingLink(ParseMessage(line))
+		assert.False(t, ok, line)
+	}
+}
+
+func TestLinkClosedError(t *testing.T) {
+	assert.Equal(t, "Closing link: K-Lined", (&LinkClosedError{Reason: "K-Lined"}).Error())
+	assert.Equal(t, "Closing link", (&LinkClosedError{}).Error())
+}
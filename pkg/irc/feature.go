@@ -123,6 +123,7 @@ var featureTransforms = map[string]featureTransform{
 	"CHANLIMIT":   parseChanlimit,
 	"CHANNELLEN":  toInt,
 	"CHANTYPES":   toCharList,
+	"CHATHISTORY": toInt,
 	"HOSTLEN":     toInt,
 	"KICKLEN":     toInt,
 	"MAXCHANNELS": toInt,
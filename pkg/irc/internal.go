@@ -13,6 +13,9 @@ func (c *Client) handleMessage(msg *Message) {
 	case PING:
 		go c.write("PONG :" + msg.LastParam())
 
+	case BATCH:
+		c.handleBatch(msg)
+
 	case JOIN:
 		if len(msg.Params) > 0 {
 			channel := msg.Params[0]
@@ -38,6 +41,15 @@ func (c *Client) handleMessage(msg *Message) {
 	case QUIT:
 		msg.meta = c.state.removeUserAll(msg.Sender)
 
+	case KILL:
+		if len(msg.Params) > 0 {
+			if c.Is(msg.Params[0]) {
+				c.setKilled()
+			} else {
+				msg.meta = c.state.removeUserAll(msg.Params[0])
+			}
+		}
+
 	case NICK:
 		if c.Is(msg.Sender) {
 			c.setNick(msg.LastParam())
@@ -45,6 +57,19 @@ func (c *Client) handleMessage(msg *Message) {
 
 		msg.meta = c.state.renameUser(msg.Sender, msg.LastParam())
 
+	case RENAME:
+		if len(msg.Params) > 1 {
+			c.state.renameChannel(msg.Params[0], msg.Params[1])
+		}
+
+	case AWAY:
+		c.state.setAway(msg.Sender, len(msg.Params) > 0 && msg.LastParam() != "")
+
+	case RPL_AWAY:
+		if len(msg.Params) > 1 {
+			c.state.setAway(msg.Params[1], true)
+		}
+
 	case PRIVMSG:
 		if ctcp := msg.ToCTCP(); ctcp != nil {
 			c.handleCTCP(ctcp, msg)
@@ -62,9 +87,21 @@ func (c *Client) handleMessage(msg *Message) {
 				c.state.setMode(target, msg.Params[2], mode.Add, mode.Remove)
 
 				msg.meta = mode
+			} else if !isChannel(target) && c.Is(target) {
+				mode := ParseMode(msg.Params[1])
+				c.state.setUserModes(mode.Add, mode.Remove)
 			}
 		}
 
+	case RPL_UMODEIS:
+		if len(msg.Params) > 1 {
+			c.state.resetUserModes()
+			c.state.setUserModes(ParseMode(msg.Params[1]).Add, "")
+		}
+
+	case RPL_YOUREOPER:
+		c.state.setUserModes("o", "")
+
 	case TOPIC, RPL_TOPIC:
 		chIndex := 0
 		if msg.Command == RPL_TOPIC {
@@ -93,9 +130,16 @@ func (c *Client) handleMessage(msg *Message) {
 		c.sendRecv.Add(1)
 		go c.send()
 
+		c.subscribeMetadata()
+
 	case RPL_ISUPPORT:
 		c.Features.Parse(msg.Params)
 
+	case METADATA, RPL_KEYVALUE, RPL_KEYNOTSET:
+		if m := c.parseMetadata(msg); m != nil {
+			msg.meta = m
+		}
+
 	case ERR_NICKNAMEINUSE, ERR_NICKCOLLISION, ERR_UNAVAILRESOURCE:
 		if c.Config.HandleNickInUse != nil && len(msg.Params) > 1 {
 			go c.writeNick(c.Config.HandleNickInUse(msg.Params[1]))
@@ -105,22 +149,57 @@ func (c *Client) handleMessage(msg *Message) {
 		channel := msg.Params[2]
 		users := strings.Split(strings.TrimSuffix(msg.LastParam(), " "), " ")
 
-		userBuffer := c.state.userBuffers[channel]
-		c.state.userBuffers[channel] = append(userBuffer, users...)
+		// NAMES replies for several channels can be interleaved
+		key := c.Casefold(channel)
+		c.state.userBuffers[key] = append(c.state.userBuffers[key], users...)
 
 	case RPL_ENDOFNAMES:
 		channel := msg.Params[1]
-		users := c.state.userBuffers[channel]
+		key := c.Casefold(channel)
+		users := c.state.userBuffers[key]
 
 		c.state.setUsers(users, channel)
-		delete(c.state.userBuffers, channel)
+		delete(c.state.userBuffers, key)
 		msg.meta = users
 
+	case RPL_WHOREPLY, RPL_WHOSPCRPL:
+		if target, reply := parseWhoReply(msg); reply != nil {
+			// WHO replies for several targets can be interleaved
+			key := c.Casefold(target)
+			c.state.whoBuffers[key] = append(c.state.whoBuffers[key], reply)
+			c.state.setAway(reply.Nick, reply.Away)
+		}
+
+	case RPL_ENDOFWHO:
+		if len(msg.Params) > 1 {
+			key := c.Casefold(msg.Params[1])
+			msg.meta = c.state.whoBuffers[key]
+			delete(c.state.whoBuffers, key)
+		}
+
 	case ERROR:
+		var err error
+		killed := c.Killed()
+		if reason, ok := ParseClosingLink(msg); ok {
+			err = &LinkClosedError{Reason: reason}
+			if strings.HasPrefix(reason, "Killed") {
+				c.setKilled()
+				killed = true
+			}
+		}
+
+		if killed {
+			// The server closes the connection, which makes recv
+			// reconnect once the cooldown is over, recv also passes
+			// the ERROR on so servers that send no KILL get reported
+			c.connChange(false, err)
+			return
+		}
+
 		c.Messages <- msg
-		c.connChange(false, nil)
+		c.connChange(false, err)
 		time.Sleep(5 * time.Second)
-		close(c.quit)
+		c.closeQuit()
 		return
 	}
 
@@ -2,6 +2,7 @@ package irc
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -35,3 +36,206 @@ func TestHandleNamreply(t *testing.T) {
 
 	assert.Equal(t, []string{"a", "b", "c", "d"}, endMsg.meta)
 }
+
+func TestHandleNamreplyInterleaved(t *testing.T) {
+	c, _ := testClientSend()
+
+	c.handleMessage(&Message{
+		Command: RPL_NAMREPLY,
+		Params:  []string{"", "", "#chan", "a b"},
+	})
+	c.handleMessage(&Message{
+		Command: RPL_NAMREPLY,
+		Params:  []string{"", "", "#other", "x"},
+	})
+	c.handleMessage(&Message{
+		Command: RPL_NAMREPLY,
+		Params:  []string{"", "", "#CHAN", "c"},
+	})
+
+	otherEnd := &Message{
+		Command: RPL_ENDOFNAMES,
+		Params:  []string{"", "#other"},
+	}
+	c.handleMessage(otherEnd)
+	c.handleMessage(&Message{
+		Command: RPL_NAMREPLY,
+		Params:  []string{"", "", "#other", "y"},
+	})
+
+	chanEnd := &Message{
+		Command: RPL_ENDOFNAMES,
+		Params:  []string{"", "#chan"},
+	}
+	c.handleMessage(chanEnd)
+
+	assert.Equal(t, []string{"x"}, otherEnd.meta)
+	assert.Equal(t, []string{"a", "b", "c"}, chanEnd.meta)
+}
+
+func TestHandleWhoInterleaved(t *testing.T) {
+	c, _ := testClientSend()
+
+	c.handleMessage(&Message{
+		Command: RPL_WHOREPLY,
+		Params:  []string{"me", "#chan", "~a", "a.host", "srv", "a", "H", "0 A"},
+	})
+	c.handleMessage(&Message{
+		Command: RPL_WHOSPCRPL,
+		Params:  []string{"me", whoxToken, "#other", "~x", "x.host", "x", "G", "xacc"},
+	})
+	c.handleMessage(&Message{
+		Command: RPL_WHOREPLY,
+		Params:  []string{"me", "#CHAN", "~b", "b.host", "srv", "b", "G@", "0 B"},
+	})
+	// WHOX replies to queries with other fields are ignored
+	c.handleMessage(&Message{
+		Command: RPL_WHOSPCRPL,
+		Params:  []string{"me", "999", "#chan", "nope"},
+	})
+
+	chanEnd := &Message{
+		Command: RPL_ENDOFWHO,
+		Params:  []string{"me", "#chan", "End of WHO list"},
+	}
+	c.handleMessage(chanEnd)
+	c.handleMessage(&Message{
+		Command: RPL_WHOSPCRPL,
+		Params:  []string{"me", whoxToken, "#other", "~y", "y.host", "y", "H", "0"},
+	})
+	otherEnd := &Message{
+		Command: RPL_ENDOFWHO,
+		Params:  []string{"me", "#other", "End of WHO list"},
+	}
+	c.handleMessage(otherEnd)
+
+	assert.Equal(t, []*WhoReply{
+		{Nick: "a", Username: "~a", Host: "a.host"},
+		{Nick: "b", Username: "~b", Host: "b.host", Away: true},
+	}, GetWhoReplies(chanEnd))
+	assert.Equal(t, []*WhoReply{
+		{Nick: "x", Username: "~x", Host: "x.host", Account: "xacc", Away: true},
+		{Nick: "y", Username: "~y", Host: "y.host"},
+	}, GetWhoReplies(otherEnd))
+	assert.True(t, c.IsAway("b"))
+	assert.False(t, c.IsAway("a"))
+}
+
+func TestHandleRename(t *testing.T) {
+	c, _ := testClientSend()
+	c.state.setUsers([]string{"a", "@b"}, "#old")
+	c.state.setTopic("topic", "#old")
+
+	c.handleMessage(&Message{
+		Command: RENAME,
+		Sender:  "b",
+		Params:  []string{"#old", "#new", "Moving"},
+	})
+
+	assert.Empty(t, c.ChannelUsers("#old"))
+	assert.Equal(t, []string{"a", "@b"}, c.ChannelUsers("#new"))
+	assert.Equal(t, "topic", c.ChannelTopic("#new"))
+}
+
+func TestHandleKill(t *testing.T) {
+	c, _ := testClientSend()
+	c.setNick("nick")
+	c.state.setUsers([]string{"nick", "other"}, "#chan")
+
+	msg := &Message{
+		Command: KILL,
+		Sender:  "oper",
+		Params:  []string{"other", "spam"},
+	}
+	c.handleMessage(msg)
+	assert.Equal(t, []string{"#chan"}, GetQuitChannels(msg))
+	assert.Equal(t, []string{"nick"}, c.ChannelUsers("#chan"))
+	assert.False(t, c.Killed())
+
+	c.handleMessage(&Message{
+		Command: KILL,
+		Sender:  "oper",
+		Params:  []string{"nick", "go away"},
+	})
+	assert.True(t, c.Killed())
+	assert.InDelta(t, float64(DefaultKillCooldown), float64(c.killCooldown()), float64(time.Second))
+
+	// Reconnecting waits for the cooldown instead of giving up
+	c.handleMessage(ParseMessage("ERROR :Closing link: (user@host.com) [Killed (oper (go away))]"))
+	state := <-c.ConnectionChanged
+	assert.Equal(t, &LinkClosedError{Reason: "Killed (oper (go away))"}, state.Error)
+	select {
+	case <-c.quit:
+		t.Fatal("Client quit after getting killed")
+	default:
+	}
+
+	c.Config.KillCooldown = time.Millisecond
+	time.Sleep(2 * time.Millisecond)
+	assert.False(t, c.Killed())
+}
+
+func TestHandleKillError(t *testing.T) {
+	c, _ := testClientSend()
+	c.Config.KillCooldown = time.Minute
+
+	// Some servers only send the ERROR
+	c.handleMessage(ParseMessage("ERROR :Closing link: (user@host.com) [Killed (oper (go away))]"))
+	<-c.ConnectionChanged
+	assert.True(t, c.Killed())
+	assert.Len(t, c.Messages, 0)
+
+	// Without a cooldown it reconnects right away instead of quitting
+	c, _ = testClientSend()
+	c.Config.KillCooldown = -1
+	c.handleMessage(ParseMessage("ERROR :Closing link: (user@host.com) [Killed (oper (go away))]"))
+	<-c.ConnectionChanged
+	assert.False(t, c.Killed())
+	select {
+	case <-c.quit:
+		t.Fatal("Client quit after getting killed")
+	default:
+	}
+}
+
+func TestHandleUserModes(t *testing.T) {
+	c, _ := testClientSend()
+	c.setNick("nick")
+
+	c.handleMessage(&Message{
+		Command: MODE,
+		Params:  []string{"nick", "+iw"},
+	})
+	assert.Equal(t, "iw", c.UserModes())
+
+	c.handleMessage(&Message{
+		Command: MODE,
+		Params:  []string{"nick", "-w+x"},
+	})
+	assert.Equal(t, "ix", c.UserModes())
+
+	c.handleMessage(&Message{
+		Command: MODE,
+		Params:  []string{"other", "+o"},
+	})
+	assert.Equal(t, "ix", c.UserModes())
+
+	c.handleMessage(&Message{
+		Command: RPL_UMODEIS,
+		Params:  []string{"nick", "+Zi"},
+	})
+	assert.Equal(t, "Zi", c.UserModes())
+
+	c.handleMessage(&Message{
+		Command: RPL_YOUREOPER,
+		Params:  []string{"nick", "You are now an IRC operator"},
+	})
+	assert.Equal(t, "Zio", c.UserModes())
+
+	// The MODE that usually follows does not add it twice
+	c.handleMessage(&Message{
+		Command: MODE,
+		Params:  []string{"nick", "+o"},
+	})
+	assert.Equal(t, "Zio", c.UserModes())
+}
@@ -1,9 +1,13 @@
 package irc
 
 import (
+	"sort"
 	"strings"
+	"time"
 )
 
+const serverTimeFormat = "2006-01-02T15:04:05.000Z"
+
 type Message struct {
 	Tags    map[string]string
 	Sender  string
@@ -22,6 +26,17 @@ func (m *Message) LastParam() string {
 	return ""
 }
 
+// Time returns the time from the server-time tag, or the current time
+// if the tag is missing
+func (m *Message) Time() time.Time {
+	if v, ok := m.Tags["time"]; ok {
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t
+		}
+	}
+	return time.Now()
+}
+
 func (m *Message) IsFromServer() bool {
 	return m.Sender == "" || strings.Contains(m.Sender, ".")
 }
@@ -132,3 +147,79 @@ var unescapeTagReplacer = strings.NewReplacer(
 func unescapeTag(s string) string {
 	return unescapeTagReplacer.Replace(s)
 }
+
+var escapeTagReplacer = strings.NewReplacer(
+	"\\", "\\\\",
+	";", "\\:",
+	" ", "\\s",
+	"\r", "\\r",
+	"\n", "\\n",
+)
+
+func escapeTag(s string) string {
+	return escapeTagReplacer.Replace(s)
+}
+
+// ReplyTo returns the msgid of the message m is a reply to
+func (m *Message) ReplyTo() string {
+	if id, ok := m.Tags[ReplyTag]; ok {
+		return id
+	}
+	return m.Tags["+reply"]
+}
+
+// ReadReceipt returns the msgid of the last message that was read when
+// m is a read receipt
+func (m *Message) ReadReceipt() (string, bool) {
+	if m.Command != TAGMSG {
+		return "", false
+	}
+	id := m.Tags[ReadTag]
+	return id, id != ""
+}
+
+// ClientTags returns the client-only tags of m matching one of names,
+// a name ending in * matches every tag starting with the rest of it
+func (m *Message) ClientTags(names []string) map[string]string {
+	var tags map[string]string
+
+	for key, val := range m.Tags {
+		if !strings.HasPrefix(key, "+") || !matchTag(key, names) {
+			continue
+		}
+		if tags == nil {
+			tags = map[string]string{}
+		}
+		tags[key] = val
+	}
+	return tags
+}
+
+func matchTag(key string, names []string) bool {
+	for _, name := range names {
+		if strings.HasSuffix(name, "*") {
+			if strings.HasPrefix(key, name[:len(name)-1]) {
+				return true
+			}
+		} else if key == name {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeTags returns tags in the form sent to the server, sorted by key
+func encodeTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for i, key := range keys {
+		if val := tags[key]; val != "" {
+			keys[i] = key + "=" + escapeTag(val)
+		}
+	}
+	return strings.Join(keys, ";")
+}
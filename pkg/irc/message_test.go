@@ -182,6 +182,31 @@ func TestLastParam(t *testing.T) {
 	assert.Equal(t, "", ParseMessage("NO_PARAMS").LastParam())
 }
 
+func TestClientTags(t *testing.T) {
+	msg := ParseMessage("@+vendor/a=1;+vendor/b;+other=x;msgid=abc :nick PRIVMSG #chan :hi")
+
+	assert.Equal(t, map[string]string{
+		"+vendor/a": "1",
+		"+vendor/b": "",
+	}, msg.ClientTags([]string{"+vendor/*"}))
+	assert.Equal(t, map[string]string{
+		"+other": "x",
+	}, msg.ClientTags([]string{"+other", "msgid"}))
+	assert.Nil(t, msg.ClientTags(nil))
+	assert.Nil(t, ParseMessage(":nick PRIVMSG #chan :hi").ClientTags([]string{"*"}))
+}
+
+func TestParseReadReceipt(t *testing.T) {
+	id, ok := ParseMessage("@+draft/read=abc\\:1 :nick!user@host TAGMSG me").ReadReceipt()
+	assert.True(t, ok)
+	assert.Equal(t, "abc;1", id)
+
+	_, ok = ParseMessage("@+draft/typing=active :nick!user@host TAGMSG me").ReadReceipt()
+	assert.False(t, ok)
+	_, ok = ParseMessage("@+draft/read=abc :nick!user@host PRIVMSG me :hi").ReadReceipt()
+	assert.False(t, ok)
+}
+
 func TestBadMessage(t *testing.T) {
 	assert.Nil(t, ParseMessage("@"))
 	assert.Nil(t, ParseMessage("@ :"))
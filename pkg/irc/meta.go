@@ -7,7 +7,7 @@ func GetNickChannels(msg *Message) []string {
 }
 
 // GetQuitChannels returns the channels the client has in common with
-// the user that quit
+// the user that quit or got killed
 func GetQuitChannels(msg *Message) []string {
 	return stringListMeta(msg)
 }
@@ -25,6 +25,24 @@ func GetNamreplyUsers(msg *Message) []string {
 	return stringListMeta(msg)
 }
 
+// GetWhoReplies returns all WHO replies for the target
+// when passed a RPL_ENDOFWHO message
+func GetWhoReplies(msg *Message) []*WhoReply {
+	if replies, ok := msg.meta.([]*WhoReply); ok {
+		return replies
+	}
+	return nil
+}
+
+// GetMetadata returns the key that a METADATA notification,
+// RPL_KEYVALUE or RPL_KEYNOTSET is about
+func GetMetadata(msg *Message) *Metadata {
+	if m, ok := msg.meta.(*Metadata); ok {
+		return m
+	}
+	return nil
+}
+
 func stringListMeta(msg *Message) []string {
 	if list, ok := msg.meta.([]string); ok {
 		return list
@@ -0,0 +1,90 @@
+package irc
+
+import "strings"
+
+// metadataKeys are subscribed to on servers that support draft/metadata-2,
+// they make up a user profile
+var metadataKeys = []string{"avatar", "display-name", "homepage", "status"}
+
+// Metadata is a key set on a user or channel, Value is empty when the key
+// is not set or got cleared
+type Metadata struct {
+	Target     string
+	Key        string
+	Visibility string
+	Value      string
+}
+
+// SupportsMetadata returns true if the server lets users publish and read
+// key/value metadata with the METADATA command
+func (c *Client) SupportsMetadata() bool {
+	return c.HasCapability("draft/metadata-2") || c.HasCapability("draft/metadata")
+}
+
+// MetadataGet requests the values of keys on target
+func (c *Client) MetadataGet(target string, keys ...string) {
+	c.Write("METADATA " + target + " GET " + strings.Join(keys, " "))
+}
+
+// MetadataList requests every key set on target
+func (c *Client) MetadataList(target string) {
+	c.Write("METADATA " + target + " LIST")
+}
+
+// MetadataSet sets key on the user to value, an empty value clears it
+func (c *Client) MetadataSet(key, value string) {
+	if value == "" {
+		c.Write("METADATA * SET " + key)
+	} else {
+		c.Write("METADATA * SET " + key + " :" + value)
+	}
+}
+
+// subscribeMetadata asks for notifications when the profile keys of other
+// users change, only draft/metadata-2 has subscriptions
+func (c *Client) subscribeMetadata() {
+	if c.HasCapability("draft/metadata-2") {
+		c.Write("METADATA * SUB " + strings.Join(metadataKeys, " "))
+	}
+}
+
+// parseMetadata returns the key that a METADATA notification, a
+// RPL_KEYVALUE or a RPL_KEYNOTSET is about. A target of * is the user
+func (c *Client) parseMetadata(msg *Message) *Metadata {
+	p := msg.Params
+	var m *Metadata
+
+	switch msg.Command {
+	case METADATA:
+		// METADATA <target> <key> <visibility> [:<value>]
+		if len(p) < 3 {
+			return nil
+		}
+		m = &Metadata{Target: p[0], Key: p[1], Visibility: p[2]}
+		if len(p) > 3 {
+			m.Value = p[3]
+		}
+
+	case RPL_KEYVALUE:
+		// <client> <target> <key> <visibility> :<value>
+		if len(p) < 5 {
+			return nil
+		}
+		m = &Metadata{Target: p[1], Key: p[2], Visibility: p[3], Value: p[4]}
+
+	case RPL_KEYNOTSET:
+		// <client> <target> <key> :key not set
+		if len(p) < 3 {
+			return nil
+		}
+		m = &Metadata{Target: p[1], Key: p[2]}
+
+	default:
+		return nil
+	}
+
+	if m.Target == "*" {
+		m.Target = c.GetNick()
+	}
+	return m
+}
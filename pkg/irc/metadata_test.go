@@ -0,0 +1,79 @@
+package irc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataCommands(t *testing.T) {
+	c, out := testClientSend()
+	assert.False(t, c.SupportsMetadata())
+	c.enabledCapabilities["draft/metadata"] = nil
+	assert.True(t, c.SupportsMetadata())
+
+	c.MetadataGet("nick", "avatar", "status")
+	assert.Equal(t, "METADATA nick GET avatar status\r\n", <-out)
+	c.MetadataList("#chan")
+	assert.Equal(t, "METADATA #chan LIST\r\n", <-out)
+	c.MetadataSet("display-name", "Mr. Nick")
+	assert.Equal(t, "METADATA * SET display-name :Mr. Nick\r\n", <-out)
+	c.MetadataSet("status", "")
+	assert.Equal(t, "METADATA * SET status\r\n", <-out)
+}
+
+func TestHandleMetadata(t *testing.T) {
+	c, out := testClientSend()
+	c.setNick("nick")
+
+	msg := &Message{
+		Command: METADATA,
+		Params:  []string{"other", "avatar", "*", "https://example.com/a.png"},
+	}
+	c.handleMessage(msg)
+	assert.Equal(t, &Metadata{
+		Target:     "other",
+		Key:        "avatar",
+		Visibility: "*",
+		Value:      "https://example.com/a.png",
+	}, GetMetadata(msg))
+
+	// Cleared keys have no value
+	msg = &Message{
+		Command: METADATA,
+		Params:  []string{"other", "avatar", "*"},
+	}
+	c.handleMessage(msg)
+	assert.Equal(t, &Metadata{Target: "other", Key: "avatar", Visibility: "*"}, GetMetadata(msg))
+
+	msg = &Message{
+		Command: RPL_KEYVALUE,
+		Params:  []string{"nick", "*", "status", "*", "Working"},
+	}
+	c.handleMessage(msg)
+	assert.Equal(t, &Metadata{
+		Target:     "nick",
+		Key:        "status",
+		Visibility: "*",
+		Value:      "Working",
+	}, GetMetadata(msg))
+
+	msg = &Message{
+		Command: RPL_KEYNOTSET,
+		Params:  []string{"nick", "other", "homepage", "key not set"},
+	}
+	c.handleMessage(msg)
+	assert.Equal(t, &Metadata{Target: "other", Key: "homepage"}, GetMetadata(msg))
+
+	msg = &Message{
+		Command: METADATA,
+		Params:  []string{"other"},
+	}
+	c.handleMessage(msg)
+	assert.Nil(t, GetMetadata(msg))
+
+	// Profile keys get subscribed to on connect with draft/metadata-2
+	c.enabledCapabilities["draft/metadata-2"] = nil
+	c.handleMessage(&Message{Command: RPL_WELCOME, Params: []string{"nick"}})
+	assert.Equal(t, "METADATA * SUB avatar display-name homepage status\r\n", <-out)
+}
@@ -0,0 +1,161 @@
+package irc
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	multilineCap       = "draft/multiline"
+	multilineConcatTag = "draft/multiline-concat"
+)
+
+func isMultilineBatch(batchType string) bool {
+	return batchType == multilineCap
+}
+
+// SupportsMultiline returns true if messages spanning several lines can be
+// sent as a single message
+func (c *Client) SupportsMultiline() bool {
+	return c.HasCapability("batch") && c.HasCapability(multilineCap)
+}
+
+// multilineMessage joins the lines of a finished multiline batch into a
+// single message, lines tagged with multiline-concat continue the line
+// before them instead of starting a new one
+func (b *Batch) multilineMessage() *Message {
+	if len(b.Messages) == 0 || len(b.Params) == 0 {
+		return nil
+	}
+
+	first := b.Messages[0]
+	tags := map[string]string{}
+	for k, v := range first.Tags {
+		tags[k] = v
+	}
+	delete(tags, "batch")
+	delete(tags, multilineConcatTag)
+	for k, v := range b.Tags {
+		tags[k] = v
+	}
+
+	var content strings.Builder
+	for i, msg := range b.Messages {
+		if _, concat := msg.Tags[multilineConcatTag]; i > 0 && !concat {
+			content.WriteByte('\n')
+		}
+		content.WriteString(msg.LastParam())
+	}
+
+	return &Message{
+		Tags:    tags,
+		Sender:  first.Sender,
+		Ident:   first.Ident,
+		Host:    first.Host,
+		Command: first.Command,
+		Params:  []string{b.Params[0], content.String()},
+	}
+}
+
+// PrivmsgMultiline sends msg as a reply to the message with the msgid
+// replyTo, or as a regular message if replyTo is empty. Messages spanning
+// several lines get sent in multiline batches when the server supports them
+// and as one PRIVMSG per line otherwise. It returns the content of each
+// message the server receives.
+func (c *Client) PrivmsgMultiline(target, msg, replyTo string) []string {
+	lines := strings.Split(strings.Replace(msg, "\r\n", "\n", -1), "\n")
+	if len(lines) == 1 {
+		c.PrivmsgReply(target, msg, replyTo)
+		return lines
+	}
+
+	var sent []string
+
+	if !c.SupportsMultiline() {
+		for _, line := range lines {
+			if line == "" {
+				continue
+			}
+			c.PrivmsgReply(target, line, replyTo)
+			sent = append(sent, line)
+			replyTo = ""
+		}
+		return sent
+	}
+
+	maxBytes, maxLines := c.multilineLimits()
+	for _, group := range groupMultiline(lines, maxBytes, maxLines) {
+		content := strings.Join(group, "\n")
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		c.writeMultiline(target, group, replyTo)
+		sent = append(sent, content)
+		replyTo = ""
+	}
+	return sent
+}
+
+func (c *Client) writeMultiline(target string, lines []string, replyTo string) {
+	c.lock.Lock()
+	c.batchRef++
+	ref := "ml" + strconv.FormatUint(c.batchRef, 36)
+	c.lock.Unlock()
+
+	if tags := c.messageTags(replyTo); tags != "" {
+		c.Writef("@%s BATCH +%s %s %s", tags, ref, multilineCap, target)
+	} else {
+		c.Writef("BATCH +%s %s %s", ref, multilineCap, target)
+	}
+	for _, line := range lines {
+		c.Writef("@batch=%s PRIVMSG %s :%s", ref, target, line)
+	}
+	c.Writef("BATCH -%s", ref)
+}
+
+// multilineLimits returns the max-bytes and max-lines values of the
+// multiline capability, zero means no limit
+func (c *Client) multilineLimits() (maxBytes int, maxLines int) {
+	values, _ := c.GetCapability(multilineCap)
+	for _, v := range values {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		n, _ := strconv.Atoi(kv[1])
+		switch kv[0] {
+		case "max-bytes":
+			maxBytes = n
+		case "max-lines":
+			maxLines = n
+		}
+	}
+	return
+}
+
+// groupMultiline splits lines into groups that fit within the limits, the
+// byte count includes the newlines between lines
+func groupMultiline(lines []string, maxBytes, maxLines int) [][]string {
+	var groups [][]string
+	var group []string
+	size := 0
+
+	for _, line := range lines {
+		if len(group) > 0 &&
+			((maxLines > 0 && len(group) >= maxLines) ||
+				(maxBytes > 0 && size+1+len(line) > maxBytes)) {
+			groups = append(groups, group)
+			group = nil
+			size = 0
+		}
+
+		if len(group) > 0 {
+			size++
+		}
+		size += len(line)
+		group = append(group, line)
+	}
+
+	return append(groups, group)
+}
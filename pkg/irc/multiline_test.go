@@ -0,0 +1,130 @@
+package irc
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func recvLines(lines ...string) *Client {
+	c := NewClient(&Config{})
+	c.conn = &mockConn{hook: make(chan string, 16)}
+
+	buf := &bytes.Buffer{}
+	for _, line := range lines {
+		buf.WriteString(line + "\r\n")
+	}
+	c.scan = bufio.NewScanner(buf)
+
+	c.sendRecv.Add(1)
+	go c.recv()
+	return c
+}
+
+func TestRecvMultiline(t *testing.T) {
+	c := recvLines(
+		"@msgid=abc;time=2020-01-02T03:04:05.000Z :nick!user@host BATCH +ml draft/multiline #chan",
+		"@batch=ml :nick!user@host PRIVMSG #chan :hello",
+		"@batch=ml :nick!user@host PRIVMSG #chan :",
+		"@batch=ml :nick!user@host PRIVMSG #chan :wor",
+		"@batch=ml;draft/multiline-concat :nick!user@host PRIVMSG #chan :ld",
+		"BATCH -ml",
+		":nick!user@host PRIVMSG #chan :after",
+	)
+
+	assert.Equal(t, BATCH, (<-c.Messages).Command)
+
+	msg := <-c.Messages
+	assert.Equal(t, PRIVMSG, msg.Command)
+	assert.Equal(t, "nick", msg.Sender)
+	assert.Equal(t, "user", msg.Ident)
+	assert.Equal(t, "host", msg.Host)
+	assert.Equal(t, []string{"#chan", "hello\n\nworld"}, msg.Params)
+	assert.Equal(t, map[string]string{
+		"msgid": "abc",
+		"time":  "2020-01-02T03:04:05.000Z",
+	}, msg.Tags)
+
+	assert.Equal(t, "after", (<-c.Messages).LastParam())
+}
+
+func TestRecvMultilineInChatHistory(t *testing.T) {
+	c := recvLines(
+		"BATCH +hist chathistory #chan",
+		"@batch=hist :nick!user@host PRIVMSG #chan :first",
+		"@batch=hist;msgid=abc :nick!user@host BATCH +ml draft/multiline #chan",
+		"@batch=ml :nick!user@host PRIVMSG #chan :a",
+		"@batch=ml :nick!user@host PRIVMSG #chan :b",
+		"BATCH -ml",
+		"BATCH -hist",
+	)
+
+	assert.Equal(t, []string{"+hist", "chathistory", "#chan"}, (<-c.Messages).Params)
+	assert.Equal(t, []string{"+ml", "draft/multiline", "#chan"}, (<-c.Messages).Params)
+
+	batch := GetBatch(<-c.Messages)
+	assert.NotNil(t, batch)
+	assert.Equal(t, "hist", batch.Ref)
+	assert.Len(t, batch.Messages, 2)
+	assert.Equal(t, "first", batch.Messages[0].LastParam())
+	assert.Equal(t, "a\nb", batch.Messages[1].LastParam())
+	assert.Equal(t, "abc", batch.Messages[1].Tags["msgid"])
+}
+
+func TestMultilineMessageEmpty(t *testing.T) {
+	assert.Nil(t, (&Batch{Type: multilineCap, Params: []string{"#chan"}}).multilineMessage())
+}
+
+func TestPrivmsgMultiline(t *testing.T) {
+	c, out := testClientSend()
+	c.enabledCapabilities["batch"] = nil
+	c.enabledCapabilities["message-tags"] = nil
+	c.enabledCapabilities[multilineCap] = []string{"max-bytes=4096"}
+
+	assert.True(t, c.SupportsMultiline())
+	assert.Equal(t, []string{"a\n\nb"}, c.PrivmsgMultiline("#chan", "a\r\n\nb", "xyz"))
+	assert.Equal(t, "@+draft/reply=xyz BATCH +ml1 draft/multiline #chan\r\n", <-out)
+	assert.Equal(t, "@batch=ml1 PRIVMSG #chan :a\r\n", <-out)
+	assert.Equal(t, "@batch=ml1 PRIVMSG #chan :\r\n", <-out)
+	assert.Equal(t, "@batch=ml1 PRIVMSG #chan :b\r\n", <-out)
+	assert.Equal(t, "BATCH -ml1\r\n", <-out)
+
+	assert.Equal(t, []string{"single"}, c.PrivmsgMultiline("#chan", "single", ""))
+	assert.Equal(t, "PRIVMSG #chan :single\r\n", <-out)
+}
+
+func TestPrivmsgMultilineLimits(t *testing.T) {
+	c, out := testClientSend()
+	c.enabledCapabilities["batch"] = nil
+	c.enabledCapabilities[multilineCap] = []string{"max-bytes=4096", "max-lines=2"}
+
+	assert.Equal(t, []string{"a\nb", "c"}, c.PrivmsgMultiline("#chan", "a\nb\nc", ""))
+	assert.Equal(t, "BATCH +ml1 draft/multiline #chan\r\n", <-out)
+	assert.Equal(t, "@batch=ml1 PRIVMSG #chan :a\r\n", <-out)
+	assert.Equal(t, "@batch=ml1 PRIVMSG #chan :b\r\n", <-out)
+	assert.Equal(t, "BATCH -ml1\r\n", <-out)
+	assert.Equal(t, "BATCH +ml2 draft/multiline #chan\r\n", <-out)
+	assert.Equal(t, "@batch=ml2 PRIVMSG #chan :c\r\n", <-out)
+	assert.Equal(t, "BATCH -ml2\r\n", <-out)
+}
+
+func TestPrivmsgMultilineFallback(t *testing.T) {
+	c, out := testClientSend()
+	c.enabledCapabilities["message-tags"] = nil
+
+	assert.False(t, c.SupportsMultiline())
+	assert.Equal(t, []string{"a", "b"}, c.PrivmsgMultiline("#chan", "a\n\nb\n", "xyz"))
+	assert.Equal(t, "@+draft/reply=xyz PRIVMSG #chan :a\r\n", <-out)
+	assert.Equal(t, "PRIVMSG #chan :b\r\n", <-out)
+}
+
+func TestGroupMultiline(t *testing.T) {
+	lines := []string{"aaa", "bb", "c", "dddd"}
+
+	assert.Equal(t, [][]string{lines}, groupMultiline(lines, 0, 0))
+	assert.Equal(t, [][]string{{"aaa", "bb"}, {"c", "dddd"}}, groupMultiline(lines, 0, 2))
+	assert.Equal(t, [][]string{{"aaa", "bb", "c"}, {"dddd"}}, groupMultiline(lines, 8, 0))
+	assert.Equal(t, [][]string{{"aaa"}, {"bb"}, {"c"}, {"dddd"}}, groupMultiline(lines, 2, 0))
+}
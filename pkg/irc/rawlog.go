@@ -0,0 +1,91 @@
+package irc
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// SetRawLog makes the client write every line sent and received to w with
+// credentials redacted, a nil w turns it off
+func (c *Client) SetRawLog(w io.Writer) {
+	c.rawLogLock.Lock()
+	c.rawLog = w
+	c.rawLogLock.Unlock()
+}
+
+func (c *Client) logRaw(outgoing bool, line string) {
+	c.rawLogLock.Lock()
+	defer c.rawLogLock.Unlock()
+
+	if c.rawLog == nil {
+		return
+	}
+
+	dir := "<<"
+	if outgoing {
+		dir = ">>"
+	}
+	fmt.Fprintf(c.rawLog, "%s %s %s\n", time.Now().Format(time.RFC3339), dir, redact(line))
+}
+
+const redacted = "[redacted]"
+
+var redactedServices = []string{"nickserv", "chanserv", "q", "x", "authserv"}
+
+// redact hides passwords and authentication payloads in a raw IRC line
+func redact(line string) string {
+	msg := ParseMessage(line)
+	if msg == nil {
+		return line
+	}
+
+	switch msg.Command {
+	case PASS, AUTHENTICATE:
+		return msg.Command + " " + redacted
+
+	case WEBIRC:
+		if len(msg.Params) > 1 {
+			return msg.Command + " " + redacted + " " + strings.Join(msg.Params[1:], " ")
+		}
+
+	case OPER:
+		if len(msg.Params) > 0 {
+			return msg.Command + " " + msg.Params[0] + " " + redacted
+		}
+
+	// The replies from the server have a prefix and carry no secrets
+	case REGISTER:
+		if msg.Sender == "" && len(msg.Params) > 2 {
+			return msg.Command + " " + msg.Params[0] + " " + msg.Params[1] + " " + redacted
+		}
+
+	case VERIFY:
+		if msg.Sender == "" && len(msg.Params) > 1 {
+			return msg.Command + " " + msg.Params[0] + " " + redacted
+		}
+
+	case PRIVMSG:
+		if len(msg.Params) == 2 && isService(msg.Params[0]) {
+			fields := strings.Fields(msg.Params[1])
+			if len(fields) > 1 {
+				return msg.Command + " " + msg.Params[0] + " :" + fields[0] + " " + redacted
+			}
+		}
+	}
+
+	return line
+}
+
+func isService(target string) bool {
+	if i := strings.IndexByte(target, '@'); i > 0 {
+		target = target[:i]
+	}
+	for _, service := range redactedServices {
+		if strings.EqualFold(target, service) {
+			return true
+		}
+	}
+	return false
+}
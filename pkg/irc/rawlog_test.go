@@ -0,0 +1,58 @@
+package irc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{"PASS secret", "PASS [redacted]"},
+		{"AUTHENTICATE Zm9vAGZvbwBiYXI=", "AUTHENTICATE [redacted]"},
+		{"OPER admin secret", "OPER admin [redacted]"},
+		{"WEBIRC secret dispatch host 192.0.2.1", "WEBIRC [redacted] dispatch host 192.0.2.1"},
+		{"REGISTER account user@example.com secret", "REGISTER account user@example.com [redacted]"},
+		{"REGISTER * * secret", "REGISTER * * [redacted]"},
+		{"VERIFY account 123456", "VERIFY account [redacted]"},
+		{":server REGISTER SUCCESS account :Account created", ":server REGISTER SUCCESS account :Account created"},
+		{":server VERIFY SUCCESS account :Account verified", ":server VERIFY SUCCESS account :Account verified"},
+		{"PRIVMSG NickServ :IDENTIFY nick secret", "PRIVMSG NickServ :IDENTIFY [redacted]"},
+		{"PRIVMSG nickserv@services.net :IDENTIFY secret", "PRIVMSG nickserv@services.net :IDENTIFY [redacted]"},
+		{"PRIVMSG #chan :IDENTIFY secret", "PRIVMSG #chan :IDENTIFY secret"},
+		{"NICK nick", "NICK nick"},
+		{":server 001 nick :Welcome", ":server 001 nick :Welcome"},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, redact(tc.input))
+	}
+}
+
+func TestRawLog(t *testing.T) {
+	c, out := testClientSend()
+	buf := &bytes.Buffer{}
+	c.SetRawLog(buf)
+
+	c.writePass("secret")
+	<-out
+	c.Nick("nick")
+	<-out
+	c.logRaw(false, "PING :server")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasSuffix(lines[0], " >> PASS [redacted]"))
+	assert.True(t, strings.HasSuffix(lines[1], " >> NICK nick"))
+	assert.True(t, strings.HasSuffix(lines[2], " << PING :server"))
+
+	c.SetRawLog(nil)
+	c.Nick("nick2")
+	<-out
+	assert.Len(t, strings.Split(strings.TrimSpace(buf.String()), "\n"), 3)
+}
@@ -13,11 +13,13 @@ import (
 	"github.com/xdg-go/scram"
 )
 
+// DefaultSASLMechanisms is the order mechanisms are attempted in when
+// Config.SASLMechanisms is not set, only the ones the server advertises
+// get used
 var DefaultSASLMechanisms = []string{
 	"EXTERNAL",
-	//"SCRAM-SHA-512",
+	"SCRAM-SHA-512",
 	"SCRAM-SHA-256",
-	//"SCRAM-SHA-1",
 	"PLAIN",
 }
 
@@ -66,11 +68,14 @@ var (
 	ErrUnsupportedHash = errors.New("unsupported hash algorithm")
 )
 
+// SASLScram implements SCRAM-SHA-1, SCRAM-SHA-256 and SCRAM-SHA-512,
+// the server signature is verified before the exchange is considered done
 type SASLScram struct {
 	Username string
 	Password string
 	Hash     string
 	conv     *scram.ClientConversation
+	nonce    scram.NonceGeneratorFcn
 }
 
 func (s *SASLScram) Name() string {
@@ -84,6 +89,9 @@ func (s *SASLScram) Step(response string) (string, error) {
 			if err != nil {
 				return "", err
 			}
+			if s.nonce != nil {
+				client = client.WithNonceGenerator(s.nonce)
+			}
 			s.conv = client.NewConversation()
 		} else {
 			return "", ErrUnsupportedHash
@@ -101,6 +109,7 @@ func (s *SASLScram) Step(response string) (string, error) {
 
 	res, err := s.conv.Step(challenge)
 	if err != nil {
+		s.conv = nil
 		return "", err
 	}
 
@@ -136,6 +145,34 @@ func (c *Client) filterSASLMechanisms(supportedMechs []string) {
 	c.saslMechanisms = saslMechanisms
 }
 
+// usingSASL returns whether the mechanism currently being attempted is
+// one of mechs
+func (c *Client) usingSASL(mechs []string) bool {
+	if c.currentSASL != nil {
+		for _, mech := range mechs {
+			if c.currentSASL.Name() == mech {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SASLError returns why SASL authentication could not be attempted with
+// any of the configured mechanisms, or nil
+func (c *Client) SASLError() error {
+	c.lock.Lock()
+	err := c.saslErr
+	c.lock.Unlock()
+	return err
+}
+
+func (c *Client) setSASLError(err error) {
+	c.lock.Lock()
+	c.saslErr = err
+	c.lock.Unlock()
+}
+
 func (c *Client) handleSASL(msg *Message) {
 	switch msg.Command {
 	case AUTHENTICATE:
@@ -146,7 +183,9 @@ func (c *Client) handleSASL(msg *Message) {
 		// TODO: handle 400 chunking on incoming messages
 		auth, err := c.currentSASL.Step(msg.LastParam())
 		if err != nil {
-			c.tryNextSASL()
+			// The server answers the abort with ERR_SASLABORTED,
+			// which moves on to the next mechanism
+			c.authenticate("*")
 			return
 		}
 
@@ -164,12 +203,19 @@ func (c *Client) handleSASL(msg *Message) {
 		c.tryNextSASL()
 
 	case RPL_SASLMECHS:
+		// The server follows up with ERR_SASLFAIL when the current
+		// mechanism is not supported, which moves on to the next
+		// supported one
+		var supportedMechs []string
 		if len(msg.Params) > 1 {
-			supportedMechs := strings.Split(msg.Params[1], ",")
+			supportedMechs = strings.Split(msg.Params[1], ",")
 			c.filterSASLMechanisms(supportedMechs)
 		}
 
 		if len(c.saslMechanisms) == 0 {
+			if !c.usingSASL(supportedMechs) {
+				c.setSASLError(&SASLMechanismsError{Available: supportedMechs})
+			}
 			c.finishCAP()
 		}
 
@@ -0,0 +1,150 @@
+package irc
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func b64(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func TestSASLScram(t *testing.T) {
+	// Test vectors from RFC 5802 and RFC 7677
+	cases := []struct {
+		hash        string
+		nonce       string
+		clientFirst string
+		serverFirst string
+		clientFinal string
+		serverFinal string
+	}{
+		{
+			"SHA-1",
+			"fyko+d2lbbFgONRv9qkxdawL",
+			"n,,n=user,r=fyko+d2lbbFgONRv9qkxdawL",
+			"r=fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j,s=QSXCR+Q6sek8bf92,i=4096",
+			"c=biws,r=fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j,p=v0X8v3Bz2T0CJGbJQyF0X+HI4Ts=",
+			"v=rmF9pqV8S7suAoZWja4dJRkFsKQ=",
+		}, {
+			"SHA-256",
+			"rOprNGfwEbeRWgbNEkqO",
+			"n,,n=user,r=rOprNGfwEbeRWgbNEkqO",
+			"r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096",
+			"c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=",
+			"v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4=",
+		},
+	}
+
+	for _, tc := range cases {
+		nonce := tc.nonce
+		s := &SASLScram{
+			Username: "user",
+			Password: "pencil",
+			Hash:     tc.hash,
+			nonce:    func() string { return nonce },
+		}
+		assert.Equal(t, "SCRAM-"+tc.hash, s.Name())
+
+		res, err := s.Step("+")
+		assert.Nil(t, err)
+		assert.Equal(t, b64(tc.clientFirst), res)
+
+		res, err = s.Step(b64(tc.serverFirst))
+		assert.Nil(t, err)
+		assert.Equal(t, b64(tc.clientFinal), res)
+
+		res, err = s.Step(b64(tc.serverFinal))
+		assert.Nil(t, err)
+		assert.Equal(t, "+", res)
+	}
+}
+
+func TestSASLScramBadServerSignature(t *testing.T) {
+	s := &SASLScram{
+		Username: "user",
+		Password: "pencil",
+		Hash:     "SHA-256",
+		nonce:    func() string { return "rOprNGfwEbeRWgbNEkqO" },
+	}
+
+	s.Step("+")
+	s.Step(b64("r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096"))
+	_, err := s.Step(b64("v=rmF9pqV8S7suAoZWja4dJRkFsKQ="))
+	assert.NotNil(t, err)
+
+	s = &SASLScram{Hash: "MD5"}
+	_, err = s.Step("+")
+	assert.Equal(t, ErrUnsupportedHash, err)
+}
+
+func TestSASLMechanismOrder(t *testing.T) {
+	c, out := testClientSend()
+	c.Config.Account = "user"
+	c.Config.Password = "pencil"
+	c.Config.SASLMechanisms = []string{"SCRAM-SHA-256", "PLAIN", "SCRAM-SHA-512"}
+	c.initSASL()
+	c.negotiating = true
+	c.enabledCapabilities["sasl"] = []string{"PLAIN", "SCRAM-SHA-512"}
+
+	assert.True(t, c.beginSASL())
+	assert.Equal(t, "AUTHENTICATE PLAIN\r\n", <-out)
+
+	c.handleMessage(&Message{Command: ERR_SASLFAIL})
+	assert.Equal(t, "AUTHENTICATE SCRAM-SHA-512\r\n", <-out)
+
+	c.handleMessage(&Message{Command: AUTHENTICATE, Params: []string{"+"}})
+	assert.Contains(t, <-out, "AUTHENTICATE ")
+
+	c.handleMessage(&Message{Command: AUTHENTICATE, Params: []string{"not base64"}})
+	assert.Equal(t, "AUTHENTICATE *\r\n", <-out)
+
+	c.handleMessage(&Message{Command: ERR_SASLABORTED})
+	assert.Equal(t, "CAP END\r\n", <-out)
+}
+
+func TestSASLMechs(t *testing.T) {
+	c, out := testClientSend()
+	c.Config.Account = "user"
+	c.Config.Password = "pencil"
+	c.Config.SASLMechanisms = []string{"SCRAM-SHA-512", "SCRAM-SHA-256", "PLAIN"}
+	c.initSASL()
+	c.negotiating = true
+	c.enabledCapabilities["sasl"] = nil
+
+	assert.True(t, c.beginSASL())
+	assert.Equal(t, "AUTHENTICATE SCRAM-SHA-512\r\n", <-out)
+
+	// Retries with the preferred one of the available mechanisms
+	c.handleMessage(&Message{Command: RPL_SASLMECHS, Params: []string{"nick", "EXTERNAL,PLAIN", "are available SASL mechanisms"}})
+	c.handleMessage(&Message{Command: ERR_SASLFAIL})
+	assert.Equal(t, "AUTHENTICATE PLAIN\r\n", <-out)
+	assert.Nil(t, c.SASLError())
+
+	c.handleMessage(&Message{Command: ERR_SASLFAIL})
+	assert.Equal(t, "CAP END\r\n", <-out)
+	assert.Nil(t, c.SASLError())
+
+	c.initSASL()
+	c.negotiating = true
+	assert.True(t, c.beginSASL())
+	assert.Equal(t, "AUTHENTICATE SCRAM-SHA-512\r\n", <-out)
+
+	// None of them are usable
+	c.handleMessage(&Message{Command: RPL_SASLMECHS, Params: []string{"nick", "EXTERNAL,ECDSA-NIST256P-CHALLENGE", "are available SASL mechanisms"}})
+	assert.Equal(t, "CAP END\r\n", <-out)
+	assert.Equal(t, &SASLMechanismsError{Available: []string{"EXTERNAL", "ECDSA-NIST256P-CHALLENGE"}}, c.SASLError())
+	assert.EqualError(t, c.SASLError(), "SASL authentication failed, the server only supports EXTERNAL, ECDSA-NIST256P-CHALLENGE")
+
+	c.handleMessage(&Message{Command: ERR_SASLFAIL})
+	select {
+	case line := <-out:
+		t.Fatal("unexpected", line)
+	default:
+	}
+
+	c.initSASL()
+	assert.Nil(t, c.SASLError())
+}
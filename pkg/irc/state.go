@@ -10,10 +10,16 @@ type state struct {
 
 	users map[string][]*User
 	topic map[string]string
+	// away is the nicks known to be away, it is kept up to date by
+	// away-notify and RPL_AWAY
+	away map[string]bool
 
 	userBuffers map[string][]string
+	whoBuffers  map[string][]*WhoReply
+	batches     map[string]*Batch
 
-	motd []string
+	motd      []string
+	userModes string
 
 	lock sync.Mutex
 }
@@ -76,7 +82,10 @@ func newState(client *Client) *state {
 		client:      client,
 		users:       make(map[string][]*User),
 		topic:       make(map[string]string),
+		away:        make(map[string]bool),
 		userBuffers: make(map[string][]string),
+		whoBuffers:  make(map[string][]*WhoReply),
+		batches:     make(map[string]*Batch),
 	}
 }
 
@@ -84,8 +93,12 @@ func (s *state) reset() {
 	s.lock.Lock()
 	s.users = make(map[string][]*User)
 	s.topic = make(map[string]string)
+	s.away = make(map[string]bool)
 	s.userBuffers = make(map[string][]string)
+	s.whoBuffers = make(map[string][]*WhoReply)
+	s.batches = make(map[string]*Batch)
 	s.motd = []string{}
+	s.userModes = ""
 	s.lock.Unlock()
 }
 
@@ -93,6 +106,22 @@ func (s *state) removeChannel(channel string) {
 	s.lock.Lock()
 	delete(s.users, channel)
 	delete(s.topic, channel)
+	for nick := range s.away {
+		s.pruneAway(nick)
+	}
+	s.lock.Unlock()
+}
+
+func (s *state) renameChannel(from, to string) {
+	s.lock.Lock()
+	if users, ok := s.users[from]; ok {
+		delete(s.users, from)
+		s.users[to] = users
+	}
+	if topic, ok := s.topic[from]; ok {
+		delete(s.topic, from)
+		s.topic[to] = topic
+	}
 	s.lock.Unlock()
 }
 
@@ -142,6 +171,7 @@ func (s *state) addUser(user, channel string) {
 func (s *state) removeUser(user, channel string) {
 	s.lock.Lock()
 	s.internalRemoveUser(user, channel)
+	s.pruneAway(user)
 	s.lock.Unlock()
 }
 
@@ -154,6 +184,7 @@ func (s *state) removeUserAll(user string) []string {
 			channels = append(channels, channel)
 		}
 	}
+	delete(s.away, user)
 
 	s.lock.Unlock()
 	return channels
@@ -182,6 +213,51 @@ func (s *state) setMode(channel, user, add, remove string) {
 	s.lock.Unlock()
 }
 
+func (s *state) setAway(nick string, away bool) {
+	s.lock.Lock()
+	if away {
+		s.away[nick] = true
+	} else {
+		delete(s.away, nick)
+	}
+	s.lock.Unlock()
+}
+
+func (s *state) isAway(nick string) bool {
+	s.lock.Lock()
+	away := s.away[nick]
+	s.lock.Unlock()
+	return away
+}
+
+func (s *state) getAwayUsers(channel string) []string {
+	s.lock.Lock()
+	var away []string
+	for _, user := range s.users[channel] {
+		if s.away[user.nick] {
+			away = append(away, user.nick)
+		}
+	}
+	s.lock.Unlock()
+	return away
+}
+
+// pruneAway forgets the away state of nicks that are not in any channel
+func (s *state) pruneAway(nick string) {
+	if !s.away[nick] {
+		return
+	}
+
+	for _, users := range s.users {
+		for _, user := range users {
+			if user.nick == nick {
+				return
+			}
+		}
+	}
+	delete(s.away, nick)
+}
+
 func (s *state) getTopic(channel string) string {
 	s.lock.Lock()
 	topic := s.topic[channel]
@@ -202,6 +278,32 @@ func (s *state) getMOTD() []string {
 	return motd
 }
 
+func (s *state) getUserModes() string {
+	s.lock.Lock()
+	modes := s.userModes
+	s.lock.Unlock()
+	return modes
+}
+
+func (s *state) setUserModes(add, remove string) {
+	s.lock.Lock()
+	for _, mode := range add {
+		if !strings.ContainsRune(s.userModes, mode) {
+			s.userModes += string(mode)
+		}
+	}
+	for _, mode := range remove {
+		s.userModes = strings.Replace(s.userModes, string(mode), "", 1)
+	}
+	s.lock.Unlock()
+}
+
+func (s *state) resetUserModes() {
+	s.lock.Lock()
+	s.userModes = ""
+	s.lock.Unlock()
+}
+
 func (s *state) rename(channel, oldNick, newNick string) bool {
 	for _, user := range s.users[channel] {
 		if user.nick == oldNick {
@@ -215,6 +317,11 @@ func (s *state) rename(channel, oldNick, newNick string) bool {
 func (s *state) renameAll(oldNick, newNick string) []string {
 	channels := []string{}
 
+	if s.away[oldNick] {
+		delete(s.away, oldNick)
+		s.away[newNick] = true
+	}
+
 	for channel := range s.users {
 		if s.rename(channel, oldNick, newNick) {
 			channels = append(channels, channel)
@@ -87,3 +87,35 @@ func TestStateChannelUserMode(t *testing.T) {
 	user.RemoveModes("v")
 	assert.Equal(t, "test", user.String())
 }
+
+func TestStateAway(t *testing.T) {
+	c := NewClient(&Config{Nick: "nick"})
+	c.handleMessage(&Message{Command: JOIN, Sender: "user", Params: []string{"#chan1"}})
+	c.handleMessage(&Message{Command: JOIN, Sender: "user", Params: []string{"#chan2"}})
+	c.handleMessage(&Message{Command: JOIN, Sender: "other", Params: []string{"#chan1"}})
+
+	c.handleMessage(&Message{Command: AWAY, Sender: "user", Params: []string{"lunch"}})
+	assert.True(t, c.IsAway("user"))
+	assert.Equal(t, []string{"user"}, c.AwayUsers("#chan1"))
+	assert.Equal(t, []string{"user"}, c.AwayUsers("#chan2"))
+
+	c.handleMessage(&Message{Command: AWAY, Sender: "user"})
+	assert.False(t, c.IsAway("user"))
+	assert.Empty(t, c.AwayUsers("#chan1"))
+
+	c.handleMessage(&Message{Command: RPL_AWAY, Params: []string{"nick", "other", "gone"}})
+	assert.Equal(t, []string{"other"}, c.AwayUsers("#chan1"))
+
+	c.handleMessage(&Message{Command: NICK, Sender: "other", Params: []string{"renamed"}})
+	assert.False(t, c.IsAway("other"))
+	assert.True(t, c.IsAway("renamed"))
+
+	c.handleMessage(&Message{Command: PART, Sender: "renamed", Params: []string{"#chan1"}})
+	assert.False(t, c.IsAway("renamed"))
+
+	c.handleMessage(&Message{Command: AWAY, Sender: "user", Params: []string{"lunch"}})
+	c.handleMessage(&Message{Command: PART, Sender: "user", Params: []string{"#chan1"}})
+	assert.True(t, c.IsAway("user"))
+	c.handleMessage(&Message{Command: QUIT, Sender: "user", Params: []string{"bye"}})
+	assert.False(t, c.IsAway("user"))
+}
@@ -0,0 +1,65 @@
+package irc
+
+import "strings"
+
+// whoxToken marks the WHOX replies to queries sent by Who, replies to
+// other WHOX queries can have different fields
+const whoxToken = "1"
+
+// WhoReply is what a WHO reply tells about a user
+type WhoReply struct {
+	Nick     string
+	Username string
+	Host     string
+	// Account is only known when the server supports WHOX
+	Account string
+	Away    bool
+}
+
+// Who asks for the users matching target, a channel or a mask, their
+// accounts are included when the server supports WHOX
+func (c *Client) Who(target string) {
+	if c.Features.Has("WHOX") {
+		c.Write("WHO " + target + " %tcuhnfa," + whoxToken)
+	} else {
+		c.Write("WHO " + target)
+	}
+}
+
+// parseWhoReply returns the target of a RPL_WHOREPLY, or a RPL_WHOSPCRPL
+// sent in response to Who, along with the user it describes
+func parseWhoReply(msg *Message) (string, *WhoReply) {
+	p := msg.Params
+
+	switch msg.Command {
+	case RPL_WHOREPLY:
+		// <client> <channel> <username> <host> <server> <nick> <flags> :<hopcount> <realname>
+		if len(p) < 7 {
+			return "", nil
+		}
+		return p[1], &WhoReply{
+			Nick:     p[5],
+			Username: p[2],
+			Host:     p[3],
+			Away:     strings.HasPrefix(p[6], "G"),
+		}
+
+	case RPL_WHOSPCRPL:
+		// <client> <token> <channel> <username> <host> <nick> <flags> <account>
+		if len(p) < 8 || p[1] != whoxToken {
+			return "", nil
+		}
+		reply := &WhoReply{
+			Nick:     p[5],
+			Username: p[3],
+			Host:     p[4],
+			Away:     strings.HasPrefix(p[6], "G"),
+		}
+		if p[7] != "0" {
+			reply.Account = p[7]
+		}
+		return p[2], reply
+	}
+
+	return "", nil
+}
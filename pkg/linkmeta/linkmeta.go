@@ -16,6 +16,9 @@ var (
 		Timeout: 15 * time.Second,
 	}
 
+	// MaxSize is how much of a page Fetch reads
+	MaxSize int64 = 1024 * 1024
+
 	ErrContentType = errors.New("Unsupported Content-Type")
 )
 
@@ -30,18 +33,27 @@ type Meta struct {
 }
 
 func Fetch(url string) (*Meta, error) {
-	resp, err := http.Get(url)
+	return FetchWith(Client, url, MaxSize)
+}
+
+// FetchWith fetches url using client, reading at most maxSize bytes
+func FetchWith(client *http.Client, url string, maxSize int64) (*Meta, error) {
+	resp, err := client.Get(url)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(resp.Status)
+	}
+
 	// TODO: Image links
 	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
 		return nil, ErrContentType
 	}
 
-	return ExtractMeta(resp.Body, url)
+	return ExtractMeta(io.LimitReader(resp.Body, maxSize), url)
 }
 
 func ExtractMeta(body io.Reader, url string) (*Meta, error) {
@@ -0,0 +1,240 @@
+// Package logging writes log entries with fields, either in the format of
+// the standard library logger or as one JSON object per line
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	// LevelTrace is for per-message diagnostics that are too noisy to
+	// leave on outside of debugging
+	LevelTrace Level = iota
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = []string{"trace", "debug", "info", "warn", "error"}
+
+func (l Level) String() string {
+	if l >= 0 && int(l) < len(levelNames) {
+		return levelNames[l]
+	}
+	return fmt.Sprintf("level(%d)", l)
+}
+
+// ParseLevel parses the name of a level, an empty name means info
+func ParseLevel(name string) (Level, error) {
+	if name == "" {
+		return LevelInfo, nil
+	}
+	for i, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return Level(i), nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("Invalid log level %s", name)
+}
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Field is a named value attached to an entry, in text mode the values
+// are written in order between the event and the message
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+type Logger struct {
+	out   io.Writer
+	json  bool
+	level Level
+	now   func() time.Time
+	lock  sync.Mutex
+}
+
+func New(out io.Writer) *Logger {
+	return &Logger{
+		out:   out,
+		level: LevelInfo,
+		now:   time.Now,
+	}
+}
+
+var std = New(os.Stderr)
+
+// Default returns the logger used by dispatch
+func Default() *Logger {
+	return std
+}
+
+func (l *Logger) SetOutput(out io.Writer) {
+	l.lock.Lock()
+	l.out = out
+	l.lock.Unlock()
+}
+
+// SetFormat switches between FormatText and FormatJSON,
+// an empty format means text
+func (l *Logger) SetFormat(format string) error {
+	var json bool
+	switch strings.ToLower(format) {
+	case "", FormatText:
+	case FormatJSON:
+		json = true
+	default:
+		return fmt.Errorf("Invalid log format %s", format)
+	}
+
+	l.lock.Lock()
+	l.json = json
+	l.lock.Unlock()
+	return nil
+}
+
+func (l *Logger) SetLevel(level Level) {
+	l.lock.Lock()
+	l.level = level
+	l.lock.Unlock()
+}
+
+func (l *Logger) Level() Level {
+	l.lock.Lock()
+	level := l.level
+	l.lock.Unlock()
+	return level
+}
+
+// Enabled returns true if entries at level get written
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.Level()
+}
+
+// Log writes an entry for event, like IRC or Auth, unless level is
+// below the level of the logger
+func (l *Logger) Log(level Level, event, msg string, fields ...Field) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if level < l.level {
+		return
+	}
+
+	now := l.now()
+	var line []byte
+	if l.json {
+		line = formatJSON(now, level, event, msg, fields)
+	} else {
+		line = formatText(now, event, msg, fields)
+	}
+	l.out.Write(line)
+}
+
+func (l *Logger) Trace(event, msg string, fields ...Field) {
+	l.Log(LevelTrace, event, msg, fields...)
+}
+
+func (l *Logger) Debug(event, msg string, fields ...Field) {
+	l.Log(LevelDebug, event, msg, fields...)
+}
+
+func (l *Logger) Info(event, msg string, fields ...Field) {
+	l.Log(LevelInfo, event, msg, fields...)
+}
+
+func (l *Logger) Warn(event, msg string, fields ...Field) {
+	l.Log(LevelWarn, event, msg, fields...)
+}
+
+func (l *Logger) Error(event, msg string, fields ...Field) {
+	l.Log(LevelError, event, msg, fields...)
+}
+
+// Writer returns an io.Writer that logs each write as an entry at level,
+// it lets the standard library logger go through l. A leading [Event]
+// in a line is used as the event
+func (l *Logger) Writer(level Level) io.Writer {
+	return &writer{logger: l, level: level}
+}
+
+type writer struct {
+	logger *Logger
+	level  Level
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	msg := strings.TrimSuffix(string(p), "\n")
+
+	var event string
+	if strings.HasPrefix(msg, "[") {
+		if end := strings.Index(msg, "] "); end > 0 {
+			event = msg[1:end]
+			msg = msg[end+2:]
+		}
+	}
+
+	w.logger.Log(w.level, event, msg)
+	return len(p), nil
+}
+
+func formatText(now time.Time, event, msg string, fields []Field) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString(now.Format("2006/01/02 15:04:05 "))
+	if event != "" {
+		buf.WriteString("[" + event + "] ")
+	}
+	for _, field := range fields {
+		fmt.Fprint(buf, field.Value)
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(msg)
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+func formatJSON(now time.Time, level Level, event, msg string, fields []Field) []byte {
+	entry := make(map[string]interface{}, len(fields)+4)
+	for _, field := range fields {
+		if err, ok := field.Value.(error); ok {
+			entry[field.Key] = err.Error()
+		} else {
+			entry[field.Key] = field.Value
+		}
+	}
+	entry["time"] = now.UTC().Format(time.RFC3339Nano)
+	entry["level"] = level.String()
+	entry["msg"] = msg
+	if event != "" {
+		entry["event"] = event
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		line, _ = json.Marshal(map[string]interface{}{
+			"time":  entry["time"],
+			"level": entry["level"],
+			"event": event,
+			"msg":   msg,
+			"error": err.Error(),
+		})
+	}
+	return append(line, '\n')
+}
@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLogger() (*Logger, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	l := New(buf)
+	l.now = func() time.Time {
+		return time.Date(2020, 6, 5, 12, 30, 0, 0, time.UTC)
+	}
+	return l, buf
+}
+
+func TestText(t *testing.T) {
+	l, buf := newTestLogger()
+	l.Info("IRC", "Connected", F("user", 12), F("server", "irc.example.com"))
+	assert.Equal(t, "2020/06/05 12:30:00 [IRC] 12 irc.example.com Connected\n", buf.String())
+}
+
+func TestJSON(t *testing.T) {
+	l, buf := newTestLogger()
+	assert.Nil(t, l.SetFormat("json"))
+	l.Warn("IRC", "Connection error", F("user", 12), F("server", "irc.example.com"),
+		F("error", errors.New("timeout")))
+
+	var entry map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, map[string]interface{}{
+		"time":   "2020-06-05T12:30:00Z",
+		"level":  "warn",
+		"event":  "IRC",
+		"msg":    "Connection error",
+		"user":   float64(12),
+		"server": "irc.example.com",
+		"error":  "timeout",
+	}, entry)
+
+	assert.NotNil(t, l.SetFormat("xml"))
+}
+
+func TestWriter(t *testing.T) {
+	l, buf := newTestLogger()
+	assert.Nil(t, l.SetFormat("json"))
+	w := l.Writer(LevelInfo)
+	w.Write([]byte("[Init] 3 users\n"))
+
+	var entry map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "Init", entry["event"])
+	assert.Equal(t, "3 users", entry["msg"])
+}
+
+func TestLevel(t *testing.T) {
+	l, buf := newTestLogger()
+	l.SetLevel(LevelWarn)
+	l.Trace("", "trace")
+	l.Debug("", "debug")
+	l.Info("", "info")
+	assert.Empty(t, buf.String())
+	assert.False(t, l.Enabled(LevelInfo))
+
+	l.Error("", "error")
+	assert.Equal(t, "2020/06/05 12:30:00 error\n", buf.String())
+
+	buf.Reset()
+	l.SetLevel(LevelDebug)
+	l.Trace("", "trace")
+	assert.Empty(t, buf.String())
+	l.SetLevel(LevelTrace)
+	l.Trace("", "trace")
+	assert.Equal(t, "2020/06/05 12:30:00 trace\n", buf.String())
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("")
+	assert.Nil(t, err)
+	assert.Equal(t, LevelInfo, level)
+
+	level, err = ParseLevel("DEBUG")
+	assert.Nil(t, err)
+	assert.Equal(t, LevelDebug, level)
+
+	level, err = ParseLevel("trace")
+	assert.Nil(t, err)
+	assert.Equal(t, LevelTrace, level)
+
+	_, err = ParseLevel("loud")
+	assert.NotNil(t, err)
+}
@@ -43,7 +43,27 @@ func (s *Session) Key() string {
 	return key
 }
 
-func (s *Session) SetCookie(w http.ResponseWriter, r *http.Request) {
+// CookieOptions holds the configurable attributes of the cookies set
+// by dispatch
+type CookieOptions struct {
+	Domain   string
+	SameSite http.SameSite
+}
+
+// Apply sets the attributes on cookie, browsers reject SameSite=None
+// cookies that are not Secure
+func (o CookieOptions) Apply(cookie *http.Cookie) {
+	cookie.Domain = o.Domain
+	cookie.SameSite = o.SameSite
+	if cookie.SameSite == 0 {
+		cookie.SameSite = http.SameSiteLaxMode
+	}
+	if cookie.SameSite == http.SameSiteNoneMode {
+		cookie.Secure = true
+	}
+}
+
+func (s *Session) SetCookie(w http.ResponseWriter, r *http.Request, opts CookieOptions) {
 	s.lock.Lock()
 	created := time.Unix(s.createdAt, 0)
 	s.lock.Unlock()
@@ -56,10 +76,9 @@ func (s *Session) SetCookie(w http.ResponseWriter, r *http.Request) {
 		HttpOnly: true,
 		Secure:   r.TLS != nil,
 	}
+	opts.Apply(cookie)
 
-	if v := cookie.String(); v != "" {
-		w.Header().Add("Set-Cookie", v+"; SameSite=Lax")
-	}
+	http.SetCookie(w, cookie)
 }
 
 func (s *Session) Expired() bool {
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"log"
+	"sort"
+
+	"github.com/khlieng/dispatch/pkg/logging"
+)
+
+// isAdmin guards the admin API, denied requests get logged
+func (h *wsHandler) isAdmin(action string) bool {
+	if h.state.srv != nil && h.state.user.IsAdmin() {
+		return true
+	}
+
+	log.Println(h.addr, "[Admin]", h.state.user.Username, "denied", action)
+	h.state.sendJSON("error", Error{
+		Message: "Admin access required",
+	})
+	return false
+}
+
+func (h *wsHandler) adminUsers(b []byte) {
+	if !h.isAdmin("list users") {
+		return
+	}
+
+	log.Println(h.addr, "[Admin]", h.state.user.Username, "listed users")
+
+	states := h.state.srv.states.list()
+	users := make([]AdminUser, len(states))
+	for idx, state := range states {
+		users[idx] = AdminUser{
+			ID:       state.user.ID,
+			Username: state.user.Username,
+			Admin:    state.user.IsAdmin(),
+			Sessions: state.getSessions(),
+		}
+
+		connectionStates := state.getConnectionStates()
+		for host, i := range state.getIRCs() {
+			users[idx].Servers = append(users[idx].Servers, AdminServer{
+				Host:      host,
+				Nick:      i.GetNick(),
+				Connected: connectionStates[host].Connected,
+			})
+		}
+		sort.Slice(users[idx].Servers, func(i, j int) bool {
+			return users[idx].Servers[i].Host < users[idx].Servers[j].Host
+		})
+	}
+
+	h.state.sendJSON("admin_users", AdminUsers{
+		Users: users,
+	})
+}
+
+// adminDisconnect closes a WebSocket session of a user when Session is set,
+// or its connection to an IRC server when Server is set
+func (h *wsHandler) adminDisconnect(b []byte) {
+	var data AdminDisconnect
+	data.UnmarshalJSON(b)
+
+	if !h.isAdmin("disconnect") {
+		return
+	}
+
+	target := h.state.srv.states.get(data.User)
+	if target == nil {
+		h.state.sendJSON("error", Error{
+			Message: "No such user",
+		})
+		return
+	}
+
+	if data.Session != "" && target.closeWS(data.Session) {
+		log.Println(h.addr, "[Admin]", h.state.user.Username, "closed session",
+			data.Session, "of", target.user.Username)
+	}
+
+	if data.Server != "" {
+		if i, ok := target.getIRC(data.Server); ok {
+			log.Println(h.addr, "[Admin]", h.state.user.Username, "closed connection to",
+				data.Server, "of", target.user.Username)
+
+			target.deleteIRC(data.Server)
+			i.Quit()
+
+			target.sendJSON("error", Error{
+				Server:  data.Server,
+				Message: "Disconnected by an administrator",
+			})
+		}
+	}
+
+	h.adminUsers(nil)
+}
+
+// adminDeleteUser deletes a user, its sessions and everything stored for
+// it, deleting a user that is already gone just refreshes the user list
+func (h *wsHandler) adminDeleteUser(b []byte) {
+	var data AdminDeleteUser
+	data.UnmarshalJSON(b)
+
+	if !h.isAdmin("delete user") {
+		return
+	}
+
+	if target := h.state.srv.states.get(data.User); target != nil {
+		err := h.state.srv.deleteUser(target)
+		if err != nil {
+			log.Println(h.addr, "[Admin]", h.state.user.Username, "failed to delete user",
+				target.user.Username, "|", err)
+			h.state.sendJSON("error", Error{
+				Message: err.Error(),
+			})
+			return
+		}
+
+		log.Println(h.addr, "[Admin]", h.state.user.Username, "deleted user",
+			target.user.Username, "| User ID:", target.user.ID)
+	}
+
+	h.adminUsers(nil)
+}
+
+// adminLogLevel changes the log level until the config gets reloaded,
+// it lets admins turn on trace logging while debugging
+func (h *wsHandler) adminLogLevel(b []byte) {
+	var data AdminLogLevel
+	data.UnmarshalJSON(b)
+
+	if !h.isAdmin("log level") {
+		return
+	}
+
+	logger := logging.Default()
+	if data.Level != "" {
+		level, err := logging.ParseLevel(data.Level)
+		if err != nil {
+			h.state.sendJSON("error", Error{
+				Message: err.Error(),
+			})
+			return
+		}
+
+		logger.SetLevel(level)
+		log.Println(h.addr, "[Admin]", h.state.user.Username, "set log level to", level)
+	}
+
+	h.state.sendJSON("admin_log_level", AdminLogLevel{
+		Level: logger.Level().String(),
+	})
+}
+
+// adminCompact compacts every store, the results get sent once it is done
+func (h *wsHandler) adminCompact(b []byte) {
+	if !h.isAdmin("compact") {
+		return
+	}
+
+	log.Println(h.addr, "[Admin]", h.state.user.Username, "started compaction")
+
+	go func() {
+		h.state.sendJSON("admin_compact", AdminCompact{
+			Results: h.state.srv.compactStores(),
+		})
+	}()
+}
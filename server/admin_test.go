@@ -0,0 +1,238 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/khlieng/dispatch/pkg/logging"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func adminTestStates(t *testing.T) (*wsHandler, *State, *State) {
+	admin, err := storage.NewUser(store)
+	assert.Nil(t, err)
+	assert.Nil(t, admin.SetAdmin(true))
+
+	target, err := storage.NewUser(store)
+	assert.Nil(t, err)
+
+	srv := &Dispatch{
+		cfg:    &config.Config{},
+		states: &stateStore{states: map[uint64]*State{}},
+	}
+
+	adminState := NewState(admin, srv)
+	targetState := NewState(target, srv)
+	srv.states.set(adminState)
+	srv.states.set(targetState)
+
+	h := &wsHandler{state: adminState}
+	h.initHandlers()
+
+	return h, adminState, targetState
+}
+
+func TestAdminDisconnectSession(t *testing.T) {
+	h, adminState, target := adminTestStates(t)
+	go target.run()
+
+	upgrader := websocket.Upgrader{}
+	connected := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		target.setWS("session", newWSConn(conn))
+		close(connected)
+	}))
+	defer ts.Close()
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http"), nil)
+	assert.Nil(t, err)
+	defer client.Close()
+	<-connected
+
+	assert.Equal(t, []string{"session"}, target.getSessions())
+
+	h.dispatchRequest(WSRequest{
+		Type: "admin_disconnect",
+		Data: []byte(`{"user":` + strconv.FormatUint(target.user.ID, 10) + `,"session":"session"}`),
+	})
+
+	assert.Empty(t, target.getSessions())
+
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err = client.ReadMessage()
+	assert.NotNil(t, err)
+
+	res := <-adminState.broadcast
+	assert.Equal(t, "admin_users", res.Type)
+}
+
+func TestAdminDisconnectServer(t *testing.T) {
+	h, adminState, target := adminTestStates(t)
+	go target.run()
+
+	target.setIRC("host.com", irc.NewClient(&irc.Config{
+		Nick: "nick",
+		Host: "host.com",
+	}))
+
+	h.dispatchRequest(WSRequest{Type: "admin_users"})
+	res := <-adminState.broadcast
+	assert.Equal(t, "admin_users", res.Type)
+
+	var found bool
+	for _, u := range res.Data.(AdminUsers).Users {
+		if u.ID == target.user.ID {
+			found = true
+			assert.Equal(t, []AdminServer{{Host: "host.com", Nick: "nick"}}, u.Servers)
+		}
+	}
+	assert.True(t, found)
+
+	h.dispatchRequest(WSRequest{
+		Type: "admin_disconnect",
+		Data: []byte(`{"user":` + strconv.FormatUint(target.user.ID, 10) + `,"server":"host.com"}`),
+	})
+
+	_, ok := target.getIRC("host.com")
+	assert.False(t, ok)
+}
+
+func TestAdminDenied(t *testing.T) {
+	h, adminState, target := adminTestStates(t)
+	assert.Nil(t, adminState.user.SetAdmin(false))
+
+	target.setIRC("host.com", irc.NewClient(&irc.Config{Host: "host.com"}))
+
+	h.dispatchRequest(WSRequest{
+		Type: "admin_disconnect",
+		Data: []byte(`{"user":` + strconv.FormatUint(target.user.ID, 10) + `,"server":"host.com"}`),
+	})
+
+	checkResponse(t, "error", Error{Message: "Admin access required"}, <-adminState.broadcast)
+
+	_, ok := target.getIRC("host.com")
+	assert.True(t, ok)
+}
+
+func TestAdminLogLevel(t *testing.T) {
+	h, adminState, _ := adminTestStates(t)
+	logger := logging.Default()
+	defer logger.SetLevel(logger.Level())
+
+	buf := &bytes.Buffer{}
+	logger.SetOutput(buf)
+	defer logger.SetOutput(os.Stderr)
+
+	c := irc.NewClient(&irc.Config{Nick: "nick", Host: "host.com"})
+	i := newIRCHandler(c, NewState(user, nil))
+	i.dispatchMessage(&irc.Message{Command: irc.PING, Params: []string{"1"}})
+	assert.NotContains(t, buf.String(), "PING")
+
+	h.dispatchRequest(WSRequest{Type: "admin_log_level", Data: []byte(`{"level":"trace"}`)})
+	checkResponse(t, "admin_log_level", AdminLogLevel{Level: "trace"}, <-adminState.broadcast)
+
+	i.dispatchMessage(&irc.Message{Command: irc.PING, Params: []string{"1"}})
+	assert.Contains(t, buf.String(), "PING")
+
+	h.dispatchRequest(WSRequest{Type: "admin_log_level", Data: []byte(`{"level":"loud"}`)})
+	checkResponse(t, "error", Error{Message: "Invalid log level loud"}, <-adminState.broadcast)
+	assert.Equal(t, logging.LevelTrace, logger.Level())
+
+	h.dispatchRequest(WSRequest{Type: "admin_log_level"})
+	checkResponse(t, "admin_log_level", AdminLogLevel{Level: "trace"}, <-adminState.broadcast)
+}
+
+func TestAdminCompact(t *testing.T) {
+	h, adminState, target := adminTestStates(t)
+	adminState.srv.Store = store
+
+	h.dispatchRequest(WSRequest{Type: "admin_compact"})
+
+	var res WSResponse
+	select {
+	case res = <-adminState.broadcast:
+	case <-time.After(5 * time.Second):
+		t.Fatal("compaction did not finish")
+	}
+	assert.Equal(t, "admin_compact", res.Type)
+
+	results := res.Data.(AdminCompact).Results
+	assert.Len(t, results, 3)
+	assert.Equal(t, "database", results[0].Store)
+	for _, result := range results {
+		assert.Empty(t, result.Error, result.Store)
+		assert.Equal(t, result.SizeBefore-result.SizeAfter, result.Reclaimed)
+	}
+
+	// The store keeps working
+	_, err := storage.NewUser(store)
+	assert.Nil(t, err)
+
+	h = &wsHandler{state: target}
+	h.initHandlers()
+	h.dispatchRequest(WSRequest{Type: "admin_compact"})
+	checkResponse(t, "error", Error{Message: "Admin access required"}, <-target.broadcast)
+}
+
+func TestPromoteAdmins(t *testing.T) {
+	u, err := storage.NewUser(store)
+	assert.Nil(t, err)
+
+	other, err := storage.NewUser(store)
+	assert.Nil(t, err)
+
+	d := New(&config.Config{Admins: []string{u.Username}})
+	d.promoteAdmins([]*storage.User{u, other})
+
+	assert.True(t, u.IsAdmin())
+	assert.False(t, other.IsAdmin())
+
+	d.SetConfig(&config.Config{})
+	d.promoteAdmins([]*storage.User{u, other})
+	assert.True(t, u.IsAdmin())
+}
+
+func TestAdminDeleteUser(t *testing.T) {
+	defer useUserMessageLogs()()
+
+	h, adminState, target := adminTestStates(t)
+	go target.run()
+
+	req := WSRequest{
+		Type: "admin_delete_user",
+		Data: []byte(`{"user":` + strconv.FormatUint(target.user.ID, 10) + `}`),
+	}
+	h.dispatchRequest(req)
+
+	res := <-adminState.broadcast
+	assert.Equal(t, "admin_users", res.Type)
+	assert.Nil(t, h.state.srv.states.get(target.user.ID))
+	_, err := os.Stat(storage.Path.User(target.user.Username))
+	assert.True(t, os.IsNotExist(err))
+
+	h.dispatchRequest(req)
+	res = <-adminState.broadcast
+	assert.Equal(t, "admin_users", res.Type)
+
+	h = &wsHandler{state: target}
+	h.initHandlers()
+	h.dispatchRequest(WSRequest{
+		Type: "admin_delete_user",
+		Data: []byte(`{"user":` + strconv.FormatUint(adminState.user.ID, 10) + `}`),
+	})
+	assert.NotNil(t, h.state.srv.states.get(adminState.user.ID))
+}
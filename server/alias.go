@@ -0,0 +1,110 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// splitCommand splits a command line like "/join #go" into its
+// lowercased name and the rest of the line
+func splitCommand(line string) (string, string) {
+	line = strings.TrimPrefix(line, "/")
+	name := line
+	args := ""
+
+	if i := strings.IndexByte(line, ' '); i > 0 {
+		name = line[:i]
+		args = strings.TrimLeft(line[i+1:], " ")
+	}
+
+	return strings.ToLower(name), args
+}
+
+// splitArgs splits s on spaces, double quoted arguments are kept together
+// and \" can be used to include a quote
+func splitArgs(s string) []string {
+	args := []string{}
+	arg := strings.Builder{}
+	inArg := false
+	quoted := false
+
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+
+		switch {
+		case ch == '\\' && i+1 < len(s) && s[i+1] == '"':
+			arg.WriteByte('"')
+			inArg = true
+			i++
+
+		case ch == '"':
+			quoted = !quoted
+			inArg = true
+
+		case ch == ' ' && !quoted:
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+
+		default:
+			arg.WriteByte(ch)
+			inArg = true
+		}
+	}
+
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args
+}
+
+// expandAlias substitutes $1, $2, ... in expansion with the positional
+// arguments in args, $* with all of them as written and $$ with a $
+func expandAlias(expansion, args string) (string, error) {
+	params := splitArgs(args)
+	result := strings.Builder{}
+
+	for i := 0; i < len(expansion); i++ {
+		ch := expansion[i]
+		if ch != '$' || i+1 == len(expansion) {
+			result.WriteByte(ch)
+			continue
+		}
+
+		next := expansion[i+1]
+		switch {
+		case next == '$':
+			result.WriteByte('$')
+			i++
+
+		case next == '*':
+			result.WriteString(args)
+			i++
+
+		case next >= '1' && next <= '9':
+			end := i + 1
+			for end < len(expansion) && expansion[end] >= '0' && expansion[end] <= '9' {
+				end++
+			}
+
+			n, _ := strconv.Atoi(expansion[i+1 : end])
+			if n > len(params) {
+				return "", fmt.Errorf("Missing parameter $%d", n)
+			}
+			result.WriteString(params[n-1])
+			i = end - 1
+
+		default:
+			result.WriteByte(ch)
+		}
+	}
+
+	return result.String(), nil
+}
+
+func isValidAliasName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, " /")
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitCommand(t *testing.T) {
+	name, args := splitCommand("/J  #go-nuts key")
+	assert.Equal(t, "j", name)
+	assert.Equal(t, "#go-nuts key", args)
+
+	name, args = splitCommand("/away")
+	assert.Equal(t, "away", name)
+	assert.Equal(t, "", args)
+}
+
+func TestSplitArgs(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected []string
+	}{
+		{"", []string{}},
+		{"a b  c", []string{"a", "b", "c"}},
+		{`"a b" c`, []string{"a b", "c"}},
+		{`a "" c`, []string{"a", "", "c"}},
+		{`say \"hi\"`, []string{"say", `"hi"`}},
+		{`"unterminated quote`, []string{"unterminated quote"}},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, splitArgs(tc.input))
+	}
+}
+
+func TestExpandAlias(t *testing.T) {
+	cases := []struct {
+		expansion string
+		args      string
+		expected  string
+	}{
+		{"/join", "", "/join"},
+		{"/join $1", "#go-nuts", "/join #go-nuts"},
+		{"/msg NickServ $*", `identify "my pass"`, `/msg NickServ identify "my pass"`},
+		{"/kick $2 $1 bye", `nick #chan`, "/kick #chan nick bye"},
+		{"/msg $1 $2", `bob "hello there"`, "/msg bob hello there"},
+		{"/msg bob costs $$5", "", "/msg bob costs $5"},
+		{"/msg bob $x $", "", "/msg bob $x $"},
+		{"/mode $1 $10", "1 2 3 4 5 6 7 8 9 10", "/mode 1 10"},
+	}
+
+	for _, tc := range cases {
+		result, err := expandAlias(tc.expansion, tc.args)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.expected, result)
+	}
+
+	_, err := expandAlias("/kick $1 $2", "nick")
+	assert.EqualError(t, err, "Missing parameter $2")
+
+	_, err = expandAlias("/join $1", "")
+	assert.EqualError(t, err, "Missing parameter $1")
+}
+
+func TestIsValidAliasName(t *testing.T) {
+	assert.True(t, isValidAliasName("j"))
+	assert.False(t, isValidAliasName(""))
+	assert.False(t, isValidAliasName("a b"))
+	assert.False(t, isValidAliasName("a/b"))
+}
@@ -36,7 +36,7 @@ func (d *Dispatch) handleAuth(w http.ResponseWriter, r *http.Request, createUser
 					if newKey != "" {
 						d.states.setSession(session)
 						d.states.deleteSession(key)
-						session.SetCookie(w, r)
+						session.SetCookie(w, r, d.cookieOptions())
 					}
 				}
 			} else {
@@ -45,7 +45,7 @@ func (d *Dispatch) handleAuth(w http.ResponseWriter, r *http.Request, createUser
 		}
 
 		if state != nil {
-			log.Println(r.RemoteAddr, "[Auth] GET", r.URL.Path, "| Valid token | User ID:", state.user.ID)
+			log.Println(realIP(r, d.Config().Proxy), "[Auth] GET", r.URL.Path, "| Valid token | User ID:", state.user.ID)
 		} else if createUser {
 			state, err = d.newUser(w, r)
 			if err != nil {
@@ -63,7 +63,7 @@ func (d *Dispatch) newUser(w http.ResponseWriter, r *http.Request) (*State, erro
 		return nil, err
 	}
 
-	log.Println(r.RemoteAddr, "[Auth] New anonymous user | ID:", user.ID)
+	log.Println(realIP(r, d.Config().Proxy), "[Auth] New anonymous user | ID:", user.ID)
 
 	session, err := session.New(user.ID)
 	if err != nil {
@@ -75,7 +75,30 @@ func (d *Dispatch) newUser(w http.ResponseWriter, r *http.Request) (*State, erro
 	d.states.set(state)
 	go state.run()
 
-	session.SetCookie(w, r)
+	session.SetCookie(w, r, d.cookieOptions())
 
 	return state, nil
 }
+
+// deleteUser closes the IRC and WebSocket connections of a user before
+// removing its sessions and everything stored for it, deleting a user
+// that is already deleted is not an error
+func (d *Dispatch) deleteUser(state *State) error {
+	state.kill()
+
+	err := d.states.delete(state.user.ID)
+	if rmErr := state.user.Remove(); err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+func (d *Dispatch) cookieOptions() session.CookieOptions {
+	cfg := d.Config().Cookies
+	sameSite, _ := cfg.SameSiteMode()
+
+	return session.CookieOptions{
+		Domain:   cfg.Domain,
+		SameSite: sameSite,
+	}
+}
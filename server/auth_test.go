@@ -0,0 +1,189 @@
+package server
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/khlieng/dispatch/pkg/session"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/boltdb"
+	"github.com/kjk/betterguid"
+	"github.com/stretchr/testify/assert"
+)
+
+func responseCookies(w *httptest.ResponseRecorder) map[string]*http.Cookie {
+	cookies := map[string]*http.Cookie{}
+	for _, cookie := range w.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	return cookies
+}
+
+func TestCookieAttributes(t *testing.T) {
+	sess, err := session.New(1)
+	assert.Nil(t, err)
+
+	d := New(&config.Config{})
+	r := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	sess.SetCookie(w, r, d.cookieOptions())
+	setPushCookie(w, r, d.cookieOptions())
+
+	for _, cookie := range responseCookies(w) {
+		assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+		assert.Empty(t, cookie.Domain)
+		assert.False(t, cookie.Secure)
+		assert.True(t, cookie.HttpOnly)
+	}
+
+	d = New(&config.Config{
+		Cookies: config.Cookies{Domain: "example.com", SameSite: "Strict"},
+	})
+	r.TLS = &tls.ConnectionState{}
+	w = httptest.NewRecorder()
+	sess.SetCookie(w, r, d.cookieOptions())
+	setPushCookie(w, r, d.cookieOptions())
+
+	cookies := responseCookies(w)
+	assert.Len(t, cookies, 2)
+	for _, cookie := range cookies {
+		assert.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+		assert.Equal(t, "example.com", cookie.Domain)
+		assert.True(t, cookie.Secure)
+	}
+	assert.Equal(t, sess.Key(), cookies[session.CookieName].Value)
+}
+
+func TestCookieSameSiteNone(t *testing.T) {
+	sess, err := session.New(1)
+	assert.Nil(t, err)
+
+	d := New(&config.Config{
+		Cookies: config.Cookies{SameSite: "none"},
+	})
+	r := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	sess.SetCookie(w, r, d.cookieOptions())
+	setPushCookie(w, r, d.cookieOptions())
+
+	cookies := responseCookies(w)
+	assert.Len(t, cookies, 2)
+	for _, cookie := range cookies {
+		assert.Equal(t, http.SameSiteNoneMode, cookie.SameSite)
+		assert.True(t, cookie.Secure)
+	}
+}
+
+func TestCookieSameSiteInvalid(t *testing.T) {
+	_, err := config.Cookies{SameSite: "sometimes"}.SameSiteMode()
+	assert.NotNil(t, err)
+
+	d := New(&config.Config{
+		Cookies: config.Cookies{SameSite: "sometimes"},
+	})
+	assert.Equal(t, http.SameSiteLaxMode, d.cookieOptions().SameSite)
+}
+
+// useUserMessageLogs gives new users their own message log like outside
+// of tests, removing them would otherwise close the shared store
+func useUserMessageLogs() func() {
+	getMessageStore := storage.GetMessageStore
+	storage.GetMessageStore = func(user *storage.User) (storage.MessageStore, error) {
+		return boltdb.New(storage.Path.Log(user.Username))
+	}
+	return func() {
+		storage.GetMessageStore = getMessageStore
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	defer useUserMessageLogs()()
+
+	u, err := storage.NewUser(store)
+	assert.Nil(t, err)
+	assert.Nil(t, u.AddServer(&storage.Server{Host: "127.0.0.1", Nick: "nick"}))
+	assert.Nil(t, u.AddChannel(&storage.Channel{Server: "127.0.0.1", Name: "#chan"}))
+	assert.Nil(t, u.AddOpenDM("127.0.0.1", "friend"))
+	assert.Nil(t, u.LogMessage(&storage.Message{
+		ID:      betterguid.New(),
+		Server:  "127.0.0.1",
+		From:    "friend",
+		To:      "#chan",
+		Content: "hello",
+	}))
+	assert.Nil(t, ioutil.WriteFile(storage.Path.DownloadedFile(u.Username, "file.txt"), []byte("data"), 0600))
+
+	sessionStore := store.(storage.SessionStore)
+	sess, err := session.New(u.ID)
+	assert.Nil(t, err)
+	assert.Nil(t, sessionStore.SaveSession(sess))
+
+	d := &Dispatch{
+		Store: store,
+		states: &stateStore{
+			states:       map[uint64]*State{},
+			sessions:     map[string]*session.Session{sess.Key(): sess},
+			sessionStore: sessionStore,
+		},
+	}
+	s := NewState(u, d)
+	d.states.set(s)
+	go s.run()
+
+	port, lines := stubIRCServer(t, ":srv 001 nick :Welcome\r\n")
+	i := irc.NewClient(&irc.Config{Host: "127.0.0.1", Port: port, Nick: "nick"})
+	s.setIRC("127.0.0.1", i)
+	i.Connect()
+	<-i.Messages
+
+	assert.Nil(t, d.deleteUser(s))
+
+	closed := false
+	timeout := time.After(time.Second)
+	for !closed {
+		select {
+		case line := <-lines:
+			closed = strings.HasPrefix(line, "QUIT")
+		case <-timeout:
+			t.Fatal("IRC connection was not closed")
+		}
+	}
+
+	_, err = os.Stat(storage.Path.User(u.Username))
+	assert.True(t, os.IsNotExist(err))
+	assert.Nil(t, d.states.get(u.ID))
+	assert.Nil(t, d.states.getSession(sess.Key()))
+
+	sessions, err := sessionStore.GetSessions()
+	assert.Nil(t, err)
+	for _, stored := range sessions {
+		assert.NotEqual(t, sess.Key(), stored.Key())
+	}
+
+	users, err := store.GetUsers()
+	assert.Nil(t, err)
+	for _, loaded := range users {
+		assert.NotEqual(t, u.ID, loaded.ID)
+	}
+
+	servers, err := store.GetServers(u)
+	assert.Nil(t, err)
+	assert.Empty(t, servers)
+	channels, err := store.GetChannels(u)
+	assert.Nil(t, err)
+	assert.Empty(t, channels)
+	openDMs, err := store.GetOpenDMs(u)
+	assert.Nil(t, err)
+	assert.Empty(t, openDMs)
+
+	// Deleting it again does nothing
+	assert.Nil(t, d.deleteUser(s))
+}
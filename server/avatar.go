@@ -0,0 +1,199 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/khlieng/dispatch/config"
+)
+
+// avatarUser is what is known about a user when resolving their avatar
+type avatarUser struct {
+	Nick     string
+	Account  string
+	Metadata map[string]string
+}
+
+// avatarResolver turns a user into the URL of their avatar
+type avatarResolver interface {
+	// resolve returns the avatar URL of the user, empty if there is none
+	resolve(user avatarUser) string
+	// origins are what img-src has to allow for the avatars to load
+	origins() []string
+}
+
+// avatarProviders are the values of avatars.provider, adding one here is
+// all it takes for it to be configurable
+var avatarProviders = map[string]func(cfg config.Avatars) avatarResolver{
+	"gravatar": func(cfg config.Avatars) avatarResolver { return gravatarResolver{cfg.URL} },
+	"metadata": func(cfg config.Avatars) avatarResolver { return metadataAvatarResolver{cfg.Hosts} },
+	"static":   func(cfg config.Avatars) avatarResolver { return staticAvatarResolver{cfg.Static} },
+}
+
+// newAvatarResolver returns the configured avatar provider, nil when
+// avatars are turned off or the provider is unknown
+func newAvatarResolver(cfg config.Avatars) avatarResolver {
+	if newResolver, ok := avatarProviders[cfg.Provider]; ok {
+		return newResolver(cfg)
+	}
+	return nil
+}
+
+// gravatarResolver fills in a hash of the account name, Gravatar itself is
+// keyed by email hashes so it only ever serves the generated default image,
+// which makes this an identicon provider
+type gravatarResolver struct {
+	url string
+}
+
+func (r gravatarResolver) resolve(user avatarUser) string {
+	if user.Account == "" {
+		return ""
+	}
+
+	hash := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(user.Account))))
+	return strings.Replace(r.url, "{hash}", hex.EncodeToString(hash[:]), 1)
+}
+
+func (r gravatarResolver) origins() []string {
+	return avatarOrigins([]string{r.url})
+}
+
+// metadataAvatarResolver uses the avatar users set with the metadata
+// extension, as long as it is on one of the allowed hosts
+type metadataAvatarResolver struct {
+	hosts []string
+}
+
+func (r metadataAvatarResolver) resolve(user avatarUser) string {
+	avatar := user.Metadata["avatar"]
+	u, err := url.Parse(avatar)
+	if err != nil || u.Scheme != "https" || u.User != nil {
+		return ""
+	}
+
+	for _, host := range r.hosts {
+		if strings.EqualFold(u.Host, host) {
+			return avatar
+		}
+	}
+	return ""
+}
+
+func (r metadataAvatarResolver) origins() []string {
+	origins := make([]string, len(r.hosts))
+	for i, host := range r.hosts {
+		origins[i] = "https://" + host
+	}
+	return origins
+}
+
+type staticAvatarResolver struct {
+	avatars map[string]string
+}
+
+func (r staticAvatarResolver) resolve(user avatarUser) string {
+	if user.Account == "" {
+		return ""
+	}
+	return r.avatars[strings.ToLower(user.Account)]
+}
+
+func (r staticAvatarResolver) origins() []string {
+	urls := make([]string, 0, len(r.avatars))
+	for _, avatar := range r.avatars {
+		urls = append(urls, avatar)
+	}
+	return avatarOrigins(urls)
+}
+
+// avatarOrigins returns the sorted unique origins of urls
+func avatarOrigins(urls []string) []string {
+	seen := map[string]bool{}
+	var origins []string
+
+	for _, avatar := range urls {
+		u, err := url.Parse(avatar)
+		if err != nil || u.Host == "" {
+			continue
+		}
+
+		origin := u.Scheme + "://" + u.Host
+		if !seen[origin] {
+			seen[origin] = true
+			origins = append(origins, origin)
+		}
+	}
+
+	sort.Strings(origins)
+	return origins
+}
+
+// avatarResolver returns the configured avatar provider, nil if there is
+// none
+func (s *State) avatarResolver() avatarResolver {
+	if s.srv == nil {
+		return nil
+	}
+	return newAvatarResolver(s.srv.Config().Avatars)
+}
+
+// avatar returns the avatar URL of a user, empty if there is none
+func (s *State) avatar(server, nick, account string) string {
+	r := s.avatarResolver()
+	if r == nil {
+		return ""
+	}
+
+	user := avatarUser{Nick: nick, Account: account}
+	if m := s.userMetadata(server, []string{nick}); m != nil {
+		user.Metadata = m[nick]
+	}
+	return r.resolve(user)
+}
+
+// userlistAvatars returns the avatar URLs of the users in a userlist,
+// keyed by nick, nil if none of them have one
+func (s *State) userlistAvatars(users []string, accounts map[string]string, metadata map[string]map[string]string) map[string]string {
+	r := s.avatarResolver()
+	if r == nil {
+		return nil
+	}
+
+	var avatars map[string]string
+	for _, user := range users {
+		nick := user[len(userPrefix(user)):]
+		avatar := r.resolve(avatarUser{
+			Nick:     nick,
+			Account:  accounts[nick],
+			Metadata: metadata[nick],
+		})
+		if avatar == "" {
+			continue
+		}
+
+		if avatars == nil {
+			avatars = map[string]string{}
+		}
+		avatars[nick] = avatar
+	}
+	return avatars
+}
+
+// avatarImgSrc returns the origins img-src needs for avatars to load,
+// with a leading space, empty when avatars are turned off
+func (d *Dispatch) avatarImgSrc() string {
+	r := newAvatarResolver(d.Config().Avatars)
+	if r == nil {
+		return ""
+	}
+
+	var src string
+	for _, origin := range r.origins() {
+		src += " " + origin
+	}
+	return src
+}
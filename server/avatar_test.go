@@ -0,0 +1,119 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAvatarProviders(t *testing.T) {
+	assert.Nil(t, newAvatarResolver(config.Avatars{}))
+	assert.Nil(t, newAvatarResolver(config.Avatars{Provider: "unknown"}))
+
+	gravatar := newAvatarResolver(config.Avatars{
+		Provider: "gravatar",
+		URL:      "https://www.gravatar.com/avatar/{hash}?d=identicon",
+	})
+	assert.Equal(t, "", gravatar.resolve(avatarUser{Nick: "nick"}))
+	// The hash is of the lowercased account name
+	avatar := "https://www.gravatar.com/avatar/9af211329b2fc82e5efe906062c730082819b23fe8394bc435e0b1bf0458eb54?d=identicon"
+	assert.Equal(t, avatar, gravatar.resolve(avatarUser{Nick: "nick", Account: "Account"}))
+	assert.Equal(t, avatar, gravatar.resolve(avatarUser{Account: " account "}))
+	assert.Equal(t, []string{"https://www.gravatar.com"}, gravatar.origins())
+
+	metadata := newAvatarResolver(config.Avatars{
+		Provider: "metadata",
+		Hosts:    []string{"avatars.example.com", "img.example.org"},
+	})
+	for _, tc := range []struct {
+		avatar string
+		result string
+	}{
+		{"https://avatars.example.com/nick.png", "https://avatars.example.com/nick.png"},
+		{"https://IMG.example.org/a.png?size=64", "https://IMG.example.org/a.png?size=64"},
+		{"http://avatars.example.com/nick.png", ""},
+		{"https://evil.example.com/nick.png", ""},
+		{"https://avatars.example.com.evil.com/nick.png", ""},
+		{"https://user@avatars.example.com/nick.png", ""},
+		{"javascript:alert(1)", ""},
+		{"", ""},
+	} {
+		assert.Equal(t, tc.result, metadata.resolve(avatarUser{
+			Nick:     "nick",
+			Metadata: map[string]string{"avatar": tc.avatar},
+		}), tc.avatar)
+	}
+	assert.Equal(t, "", metadata.resolve(avatarUser{Nick: "nick"}))
+	assert.Equal(t, []string{"https://avatars.example.com", "https://img.example.org"}, metadata.origins())
+
+	static := newAvatarResolver(config.Avatars{
+		Provider: "static",
+		Static: map[string]string{
+			"alice": "https://cdn.example.com/alice.png",
+			"bob":   "https://cdn.example.com/bob.png",
+			"carol": "https://other.example.com/carol.png",
+		},
+	})
+	assert.Equal(t, "https://cdn.example.com/alice.png", static.resolve(avatarUser{Nick: "x", Account: "Alice"}))
+	assert.Equal(t, "", static.resolve(avatarUser{Nick: "alice"}))
+	assert.Equal(t, "", static.resolve(avatarUser{Account: "dave"}))
+	assert.Equal(t, []string{"https://cdn.example.com", "https://other.example.com"}, static.origins())
+}
+
+func TestUserAvatars(t *testing.T) {
+	s := NewState(user, New(&config.Config{
+		Avatars: config.Avatars{
+			Provider: "static",
+			Static:   map[string]string{"acc": "https://example.com/acc.png"},
+		},
+	}))
+
+	assert.Equal(t, "https://example.com/acc.png", s.avatar("host.com", "nick", "acc"))
+	assert.Equal(t, "", s.avatar("host.com", "nick", ""))
+	assert.Equal(t, map[string]string{
+		"a": "https://example.com/acc.png",
+	}, s.userlistAvatars([]string{"@a", "b"}, map[string]string{"a": "acc", "b": "other"}, nil))
+	assert.Nil(t, s.userlistAvatars([]string{"b"}, nil, nil))
+	assert.Equal(t, " https://example.com", s.srv.avatarImgSrc())
+
+	s.srv.SetConfig(&config.Config{
+		Avatars: config.Avatars{Provider: "metadata", Hosts: []string{"example.com"}},
+	})
+	s.setMetadata("host.com", &irc.Metadata{Target: "a", Key: "avatar", Value: "https://example.com/a.png"})
+	assert.Equal(t, "https://example.com/a.png", s.avatar("host.com", "a", ""))
+	assert.Equal(t, map[string]string{
+		"a": "https://example.com/a.png",
+	}, s.userlistAvatars([]string{"+a", "b"}, nil, s.userMetadata("host.com", []string{"+a", "b"})))
+
+	s.srv.SetConfig(&config.Config{})
+	assert.Equal(t, "", s.avatar("host.com", "a", ""))
+	assert.Equal(t, "", s.srv.avatarImgSrc())
+	assert.Equal(t, "", NewState(user, nil).avatar("host.com", "a", "acc"))
+}
+
+func TestMessageAvatar(t *testing.T) {
+	c := irc.NewClient(&irc.Config{
+		Nick:     "nick",
+		Username: "user",
+		Host:     "host.com",
+	})
+	s := NewState(user, New(&config.Config{
+		Avatars: config.Avatars{
+			Provider: "static",
+			Static:   map[string]string{"acc": "https://example.com/acc.png"},
+		},
+	}))
+
+	newIRCHandler(c, s).dispatchMessage(&irc.Message{
+		Command: irc.PRIVMSG,
+		Sender:  "someone",
+		Tags:    map[string]string{"account": "acc"},
+		Params:  []string{"#chan", "hello"},
+	})
+
+	res := <-s.broadcast
+	assert.Equal(t, "message", res.Type)
+	assert.Equal(t, "https://example.com/acc.png", res.Data.(Message).Avatar)
+}
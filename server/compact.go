@@ -0,0 +1,75 @@
+package server
+
+import (
+	"log"
+	"time"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+// compactCheckInterval is how often runCompaction looks at the config
+// again while compaction is disabled
+var compactCheckInterval = time.Minute
+
+// runCompaction compacts the stores every storage.compact_interval, the
+// interval is read again after every run so config changes apply
+func (d *Dispatch) runCompaction() {
+	for {
+		interval := d.Config().Storage.CompactInterval
+		if interval <= 0 {
+			time.Sleep(compactCheckInterval)
+			continue
+		}
+
+		time.Sleep(interval)
+		if d.Config().Storage.CompactInterval > 0 {
+			d.compactStores()
+		}
+	}
+}
+
+// compactStores compacts the database and the message logs of every user,
+// stores that do not support it get skipped
+func (d *Dispatch) compactStores() []CompactResult {
+	d.compactLock.Lock()
+	defer d.compactLock.Unlock()
+
+	results := []CompactResult{}
+	if compacter, ok := d.Store.(storage.Compacter); ok {
+		results = append(results, compactResult("database", compacter.Compact))
+	}
+
+	for _, state := range d.states.list() {
+		name := "messages of user " + state.user.Username
+		result := compactResult(name, state.user.CompactMessages)
+		if result.Error != storage.ErrCompactUnsupported.Error() {
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+func compactResult(name string, compact func() (storage.CompactStats, error)) CompactResult {
+	stats, err := compact()
+	if err != nil {
+		if err != storage.ErrCompactUnsupported {
+			log.Println("[Storage] Compacting", name, "failed:", err)
+		}
+		return CompactResult{
+			Store: name,
+			Error: err.Error(),
+		}
+	}
+
+	log.Printf("[Storage] Compacted %s in %s, %d bytes reclaimed", name,
+		stats.Duration.Round(time.Millisecond), stats.Reclaimed())
+
+	return CompactResult{
+		Store:      name,
+		Duration:   stats.Duration.Milliseconds(),
+		SizeBefore: stats.SizeBefore,
+		SizeAfter:  stats.SizeAfter,
+		Reclaimed:  stats.Reclaimed(),
+	}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"strings"
+	"time"
+)
+
+// ctcpTimeout is how long to wait for the reply to a CTCP request
+var ctcpTimeout = 30 * time.Second
+
+type ctcpRequest struct {
+	sent  time.Time
+	timer *time.Timer
+}
+
+func ctcpRequestKey(server, nick, command string) string {
+	return server + " " + strings.ToLower(nick) + " " + strings.ToUpper(command)
+}
+
+// addCTCPRequest tracks a CTCP request until it gets a reply, a ctcp_reply
+// with Timeout set gets sent if it never does
+func (s *State) addCTCPRequest(server, target, command string) {
+	key := ctcpRequestKey(server, target, command)
+	req := &ctcpRequest{sent: time.Now()}
+
+	req.timer = time.AfterFunc(ctcpTimeout, func() {
+		s.ircLock.Lock()
+		pending := s.pendingCTCP[key] == req
+		if pending {
+			delete(s.pendingCTCP, key)
+		}
+		s.ircLock.Unlock()
+
+		if pending {
+			s.sendJSON("ctcp_reply", CTCPReply{
+				Server:  server,
+				From:    target,
+				Command: strings.ToUpper(command),
+				Timeout: true,
+			})
+		}
+	})
+
+	s.ircLock.Lock()
+	if prev, ok := s.pendingCTCP[key]; ok {
+		prev.timer.Stop()
+	}
+	s.pendingCTCP[key] = req
+	s.ircLock.Unlock()
+}
+
+func (s *State) takeCTCPRequest(server, nick, command string) (*ctcpRequest, bool) {
+	key := ctcpRequestKey(server, nick, command)
+
+	s.ircLock.Lock()
+	req, ok := s.pendingCTCP[key]
+	if ok {
+		req.timer.Stop()
+		delete(s.pendingCTCP, key)
+	}
+	s.ircLock.Unlock()
+
+	return req, ok
+}
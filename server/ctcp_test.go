@@ -0,0 +1,83 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/stretchr/testify/assert"
+)
+
+func ctcpTestHandler() (*ircHandler, *State) {
+	c := irc.NewClient(&irc.Config{
+		Nick:     "nick",
+		Username: "user",
+		Host:     "host.com",
+	})
+	s := NewState(user, nil)
+	return newIRCHandler(c, s), s
+}
+
+func TestCTCPPingRoundTrip(t *testing.T) {
+	i, s := ctcpTestHandler()
+
+	s.addCTCPRequest("host.com", "bob", "PING")
+	time.Sleep(20 * time.Millisecond)
+
+	i.dispatchMessage(&irc.Message{
+		Command: irc.NOTICE,
+		Sender:  "Bob",
+		Params:  []string{"nick", "\x01PING 1234\x01"},
+	})
+
+	res := <-s.broadcast
+	assert.Equal(t, "ctcp_reply", res.Type)
+
+	reply := res.Data.(CTCPReply)
+	assert.Equal(t, "host.com", reply.Server)
+	assert.Equal(t, "Bob", reply.From)
+	assert.Equal(t, "PING", reply.Command)
+	assert.Equal(t, "1234", reply.Reply)
+	assert.False(t, reply.Timeout)
+	assert.True(t, reply.Latency >= 20)
+	assert.True(t, reply.Latency < 1000)
+
+	_, pending := s.takeCTCPRequest("host.com", "bob", "PING")
+	assert.False(t, pending)
+}
+
+func TestCTCPUnsolicitedReply(t *testing.T) {
+	i, s := ctcpTestHandler()
+
+	i.dispatchMessage(&irc.Message{
+		Command: irc.NOTICE,
+		Sender:  "bob",
+		Params:  []string{"nick", "\x01VERSION something\x01"},
+	})
+
+	assert.Len(t, s.broadcast, 0)
+}
+
+func TestCTCPTimeout(t *testing.T) {
+	defer func(timeout time.Duration) {
+		ctcpTimeout = timeout
+	}(ctcpTimeout)
+	ctcpTimeout = 10 * time.Millisecond
+
+	_, s := ctcpTestHandler()
+	s.addCTCPRequest("host.com", "bob", "version")
+
+	select {
+	case res := <-s.broadcast:
+		assert.Equal(t, "ctcp_reply", res.Type)
+		assert.Equal(t, CTCPReply{
+			Server:  "host.com",
+			From:    "bob",
+			Command: "VERSION",
+			Timeout: true,
+		}, res.Data)
+
+	case <-time.After(time.Second):
+		t.Error("Timed out waiting for ctcp_reply")
+	}
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+)
+
+const defaultDownloadCacheControl = "private, no-store"
+
+// setDownloadHeaders makes browsers save downloads instead of rendering
+// them, the content comes from arbitrary IRC users and HTML or SVG files
+// would otherwise be able to run scripts in our origin
+func setDownloadHeaders(h http.Header, filename, cacheControl string) {
+	if cacheControl == "" {
+		cacheControl = defaultDownloadCacheControl
+	}
+
+	h.Set("Cache-Control", cacheControl)
+	h.Set("Content-Type", "application/octet-stream")
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": sanitizeFilename(filename),
+	}))
+}
+
+func sanitizeFilename(filename string) string {
+	filename = path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	filename = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, filename)
+
+	filename = strings.TrimLeft(filename, ".")
+	if filename == "" || filename == "/" {
+		return "download"
+	}
+	return filename
+}
+
+type pendingDCC struct {
+	pack   *irc.DCCSend
+	client *irc.Client
+	from   string
+}
+
+// dccTransfer is a running DCC download
+type dccTransfer struct {
+	server   string
+	from     string
+	pack     *irc.DCCSend
+	progress irc.DownloadProgress
+	cancel   context.CancelFunc
+	// deletePartial is set when the download got cancelled and what has
+	// been downloaded so far should be removed
+	deletePartial bool
+}
+
+func (t *dccTransfer) info() DCCTransfer {
+	return DCCTransfer{
+		Server:         t.server,
+		From:           t.from,
+		File:           t.pack.File,
+		Size:           t.pack.Length,
+		PercCompletion: t.progress.PercCompletion,
+		BytesCompleted: t.progress.BytesCompleted,
+		Speed:          t.progress.Speed,
+		SecondsToGo:    t.progress.SecondsToGo,
+	}
+}
+
+// sendDCCCancelled tells the user that the download of file got
+// cancelled, path is the partially downloaded file to remove if any
+func (s *State) sendDCCCancelled(server, file, path string) {
+	deleted := false
+	if path != "" {
+		deleted = os.Remove(path) == nil
+	}
+
+	s.sendJSON("dcc_cancelled", DCCCancelled{
+		Server:  server,
+		File:    file,
+		Deleted: deleted,
+	})
+}
+
+// downloadDCC receives pack from the sender, for passive offers this means
+// listening and telling the sender where to connect, total limits the
+// combined speed of all downloads
+func downloadDCC(ctx context.Context, w io.Writer, cfg config.DCC, client *irc.Client, from string, pack *irc.DCCSend, progress chan irc.DownloadProgress, total *irc.RateLimiter) error {
+	limiter := irc.NewRateLimiter(cfg.MaxSpeed * 1024)
+
+	if !pack.Passive() {
+		return irc.DownloadDCCContext(ctx, w, pack, progress, limiter, total)
+	}
+
+	ln, err := listenDCC(cfg, client, from, pack)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+
+	return irc.AcceptDCCContext(ctx, w, ln, pack, progress, limiter, total)
+}
+
+func listenDCC(cfg config.DCC, client *irc.Client, from string, pack *irc.DCCSend) (net.Listener, error) {
+	var bindIP string
+	advertiseIP := cfg.ExternalIP
+
+	if advertiseIP != "" {
+		if net.ParseIP(advertiseIP) == nil {
+			return nil, fmt.Errorf("Invalid DCC external IP %s", advertiseIP)
+		}
+	} else {
+		ip := client.LocalIP()
+		if ip == nil {
+			return nil, errors.New("No local address to receive DCC on")
+		}
+		bindIP = ip.String()
+		advertiseIP = bindIP
+	}
+
+	ln, err := listenDCCPort(bindIP, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := *pack
+	reply.IP = advertiseIP
+	reply.Port = strconv.Itoa(ln.Addr().(*net.TCPAddr).Port)
+	client.Privmsg(from, irc.EncodeCTCP(irc.EncodeDCCSend(&reply)))
+
+	return ln, nil
+}
+
+func listenDCCPort(ip string, cfg config.DCC) (net.Listener, error) {
+	low, high, err := cfg.Ports()
+	if err != nil {
+		return nil, err
+	}
+
+	if low == 0 {
+		return net.Listen("tcp", net.JoinHostPort(ip, "0"))
+	}
+
+	for port := low; port <= high; port++ {
+		ln, err := net.Listen("tcp", net.JoinHostPort(ip, strconv.Itoa(port)))
+		if err == nil {
+			return ln, nil
+		}
+	}
+
+	return nil, fmt.Errorf("No free DCC port in range %s", cfg.PortRange)
+}
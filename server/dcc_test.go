@@ -0,0 +1,313 @@
+package server
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListenDCCExternalIP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	lines := make(chan string, 32)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.Write([]byte(":srv 001 nick :Welcome\r\n"))
+
+		scan := bufio.NewScanner(conn)
+		for scan.Scan() {
+			lines <- scan.Text()
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	c := irc.NewClient(&irc.Config{
+		Nick: "nick",
+		Host: "127.0.0.1",
+		Port: port,
+	})
+	c.Connect()
+	defer c.Quit()
+
+	cfg := config.DCC{ExternalIP: "203.0.113.5"}
+	pack := &irc.DCCSend{File: "file.txt", IP: "10.0.0.1", Port: "0", Length: 10, Token: "7"}
+	dccLn, err := listenDCC(cfg, c, "sender", pack)
+	assert.Nil(t, err)
+	defer dccLn.Close()
+
+	dccPort := strconv.Itoa(dccLn.Addr().(*net.TCPAddr).Port)
+	expected := "PRIVMSG sender :\x01DCC SEND file.txt 3405803781 " + dccPort + " 10 7\x01"
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case line := <-lines:
+			if strings.HasPrefix(line, "PRIVMSG") {
+				assert.Equal(t, expected, line)
+				return
+			}
+		case <-timeout:
+			t.Fatal("no DCC reply sent")
+		}
+	}
+}
+
+func TestListenDCCPortRange(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer taken.Close()
+
+	port := strconv.Itoa(taken.Addr().(*net.TCPAddr).Port)
+
+	_, err = listenDCCPort("127.0.0.1", config.DCC{PortRange: port + "-" + port})
+	assert.EqualError(t, err, "No free DCC port in range "+port+"-"+port)
+
+	taken.Close()
+	ln, err := listenDCCPort("127.0.0.1", config.DCC{PortRange: port})
+	assert.Nil(t, err)
+	assert.Equal(t, port, strconv.Itoa(ln.Addr().(*net.TCPAddr).Port))
+	ln.Close()
+
+	for _, r := range []string{"abc", "10-5", "0-10", "5000-70000", "1-x"} {
+		_, err = listenDCCPort("127.0.0.1", config.DCC{PortRange: r})
+		assert.EqualError(t, err, "Invalid DCC port range "+r)
+	}
+}
+
+func TestSetDownloadHeaders(t *testing.T) {
+	h := http.Header{}
+	setDownloadHeaders(h, "file.html", "")
+	assert.Equal(t, "private, no-store", h.Get("Cache-Control"))
+	assert.Equal(t, "nosniff", h.Get("X-Content-Type-Options"))
+	assert.Equal(t, "application/octet-stream", h.Get("Content-Type"))
+	assert.Equal(t, "attachment; filename=file.html", h.Get("Content-Disposition"))
+
+	h = http.Header{}
+	setDownloadHeaders(h, "file.txt", "private, max-age=60")
+	assert.Equal(t, "private, max-age=60", h.Get("Cache-Control"))
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	cases := []struct {
+		input, expected string
+	}{
+		{"file.txt", "file.txt"},
+		{"../../etc/passwd", "passwd"},
+		{"..\\..\\boot.ini", "boot.ini"},
+		{"a\"b\r\nc.txt", "abc.txt"},
+		{".hidden", "hidden"},
+		{"..", "download"},
+		{"", "download"},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, sanitizeFilename(tc.input), tc.input)
+	}
+
+	h := http.Header{}
+	setDownloadHeaders(h, "my file \"x\".txt", "")
+	assert.Equal(t, `attachment; filename="my file x.txt"`, h.Get("Content-Disposition"))
+}
+
+func TestServeDownloadedHTML(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "evil.html")
+	content := "<html><script>alert(1)</script></html>"
+	assert.Nil(t, ioutil.WriteFile(file, []byte(content), 0600))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/downloads/1/evil.html", nil)
+	setDownloadHeaders(w.Header(), "evil.html", "")
+	http.ServeFile(w, r, file)
+
+	res := w.Result()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/octet-stream", res.Header.Get("Content-Type"))
+	assert.Equal(t, "attachment; filename=evil.html", res.Header.Get("Content-Disposition"))
+	assert.Equal(t, "nosniff", res.Header.Get("X-Content-Type-Options"))
+	assert.Equal(t, content, w.Body.String())
+}
+
+func TestDCCDownloadLimit(t *testing.T) {
+	d := New(&config.Config{
+		DCC: config.DCC{
+			Enabled:      true,
+			MaxDownloads: 2,
+			Autoget:      config.Autoget{Enabled: true},
+		},
+	})
+	s := NewState(user, d)
+
+	assert.True(t, s.acquireDCC())
+	assert.True(t, s.acquireDCC())
+	assert.False(t, s.acquireDCC())
+
+	c := irc.NewClient(&irc.Config{Nick: "nick", Host: "host.com"})
+	i := newIRCHandler(c, s)
+	i.receiveDCCSend(&irc.DCCSend{File: "file.bin", IP: "127.0.0.1", Port: "1"}, &irc.Message{Sender: "sender"})
+
+	res := <-s.broadcast
+	assert.Equal(t, "pm", res.Type)
+	assert.Equal(t, "file.bin: Rejected, already running 2 downloads", res.Data.(Message).Content)
+
+	s.releaseDCC()
+	assert.True(t, s.acquireDCC())
+	assert.False(t, s.acquireDCC())
+
+	s.releaseDCC()
+	s.releaseDCC()
+	s.releaseDCC()
+	assert.True(t, s.acquireDCC())
+	assert.True(t, s.acquireDCC())
+	assert.False(t, s.acquireDCC())
+
+	unlimited := NewState(user, New(&config.Config{}))
+	for n := 0; n < 10; n++ {
+		assert.True(t, unlimited.acquireDCC())
+	}
+}
+
+func TestDCCTransfers(t *testing.T) {
+	u, err := storage.NewUser(store)
+	assert.Nil(t, err)
+
+	s := NewState(u, New(&config.Config{}))
+	h := &wsHandler{state: s}
+	h.initHandlers()
+
+	pack := &irc.DCCSend{File: "b.bin", Length: 2048}
+	b, ctx := s.startDCCTransfer("host.com", "sender", pack)
+	a, _ := s.startDCCTransfer("host.com", "other", &irc.DCCSend{File: "a.bin", Length: 10})
+	s.setDCCProgress(irc.DownloadProgress{
+		File:           "b.bin",
+		PercCompletion: 50,
+		BytesCompleted: "1.0 KiB",
+		Speed:          "512 B/s",
+		SecondsToGo:    2,
+	})
+
+	h.dispatchRequest(WSRequest{Type: "dcc_transfers"})
+	res := <-s.broadcast
+	assert.Equal(t, "dcc_transfers", res.Type)
+	assert.Equal(t, DCCTransfers{Transfers: []DCCTransfer{
+		{Server: "host.com", From: "other", File: "a.bin", Size: 10},
+		{
+			Server:         "host.com",
+			From:           "sender",
+			File:           "b.bin",
+			Size:           2048,
+			PercCompletion: 50,
+			BytesCompleted: "1.0 KiB",
+			Speed:          "512 B/s",
+			SecondsToGo:    2,
+		},
+	}}, res.Data)
+
+	h.dispatchRequest(WSRequest{Type: "cancel_dcc", Data: []byte(`{"file":"b.bin","delete":true}`)})
+	select {
+	case <-ctx.Done():
+	default:
+		t.Fatal("transfer not cancelled")
+	}
+	assert.True(t, s.endDCCTransfer(b))
+	assert.False(t, s.endDCCTransfer(a))
+	assert.Empty(t, s.getDCCTransfers())
+
+	h.dispatchRequest(WSRequest{Type: "cancel_dcc", Data: []byte(`{"file":"b.bin"}`)})
+	res = <-s.broadcast
+	assert.Equal(t, "error", res.Type)
+	assert.Equal(t, "No DCC transfer of b.bin is running", res.Data.(Error).Message)
+}
+
+func TestCancelDCCAutoget(t *testing.T) {
+	u, err := storage.NewUser(store)
+	assert.Nil(t, err)
+
+	s := NewState(u, New(&config.Config{
+		DCC: config.DCC{
+			Enabled: true,
+			Autoget: config.Autoget{Enabled: true},
+		},
+	}))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.Write([]byte("partial"))
+		<-done
+	}()
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	c := irc.NewClient(&irc.Config{Nick: "nick", Host: "host.com"})
+	i := newIRCHandler(c, s)
+	pack := &irc.DCCSend{File: "file.bin", IP: "127.0.0.1", Port: port, Length: 1024}
+
+	finished := make(chan struct{})
+	go func() {
+		i.receiveDCCSend(pack, &irc.Message{Sender: "sender"})
+		close(finished)
+	}()
+
+	path := storage.Path.DownloadedFile(u.Username, "file.bin")
+	for n := 0; ; n++ {
+		if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+			break
+		}
+		if n == 100 {
+			t.Fatal("download not started")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.True(t, s.cancelDCCTransfer("file.bin", true))
+	select {
+	case <-finished:
+	case <-time.After(2 * time.Second):
+		t.Fatal("download not stopped")
+	}
+
+	res := <-s.broadcast
+	assert.Equal(t, "pm", res.Type)
+	assert.Equal(t, "file.bin: Download cancelled", res.Data.(Message).Content)
+
+	res = <-s.broadcast
+	assert.Equal(t, "dcc_cancelled", res.Type)
+	assert.Equal(t, DCCCancelled{Server: "host.com", File: "file.bin", Deleted: true}, res.Data)
+
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+	assert.Empty(t, s.getDCCTransfers())
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+func newDownloadKey() []byte {
+	key := make([]byte, 32)
+	rand.Read(key)
+	return key
+}
+
+// downloadToken authorizes a single file for a user on the downloads
+// origin, the session cookie is never sent there
+func (d *Dispatch) downloadToken(userID uint64, filename string) string {
+	mac := hmac.New(sha256.New, d.downloadKey)
+	mac.Write([]byte(strconv.FormatUint(userID, 10) + "/" + filename))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (d *Dispatch) validDownloadToken(userID uint64, filename, token string) bool {
+	expected := d.downloadToken(userID, filename)
+	return hmac.Equal([]byte(expected), []byte(token))
+}
+
+// downloadURL returns where the user can get filename, this is the
+// configured downloads origin if there is one so that untrusted files
+// can't reach the cookies or DOM of the app, otherwise the app itself
+func (s *State) downloadURL(filename string) string {
+	path := fmt.Sprintf("/downloads/%s/%s", s.user.Username, url.PathEscape(filename))
+
+	if s.srv != nil {
+		if origin := strings.TrimRight(s.srv.Config().DCC.DownloadsOrigin, "/"); origin != "" {
+			return origin + path + "?token=" + s.srv.downloadToken(s.user.ID, filename)
+		}
+	}
+
+	return fmt.Sprintf("%s://%s%s", s.String("scheme"), s.String("host"), path)
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadURLSameOrigin(t *testing.T) {
+	s := NewState(&storage.User{ID: 1, Username: "1"}, New(&config.Config{}))
+	s.Set("scheme", "https")
+	s.Set("host", "irc.example.com")
+
+	assert.Equal(t, "https://irc.example.com/downloads/1/file%20name.txt", s.downloadURL("file name.txt"))
+
+	s = NewState(&storage.User{ID: 1, Username: "1"}, nil)
+	s.Set("scheme", "http")
+	s.Set("host", "localhost")
+
+	assert.Equal(t, "http://localhost/downloads/1/file.txt", s.downloadURL("file.txt"))
+}
+
+func TestDownloadURLSeparateOrigin(t *testing.T) {
+	d := New(&config.Config{
+		DCC: config.DCC{DownloadsOrigin: "https://downloads.example.com/"},
+	})
+	s := NewState(&storage.User{ID: 1, Username: "1"}, d)
+	s.Set("scheme", "https")
+	s.Set("host", "irc.example.com")
+
+	token := d.downloadToken(1, "file.txt")
+	assert.Equal(t, "https://downloads.example.com/downloads/1/file.txt?token="+token, s.downloadURL("file.txt"))
+
+	assert.True(t, d.validDownloadToken(1, "file.txt", token))
+	assert.False(t, d.validDownloadToken(2, "file.txt", token))
+	assert.False(t, d.validDownloadToken(1, "other.txt", token))
+	assert.False(t, d.validDownloadToken(1, "file.txt", ""))
+
+	other := New(&config.Config{})
+	assert.False(t, other.validDownloadToken(1, "file.txt", token))
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"log"
+	"regexp"
+
+	"github.com/khlieng/dispatch/pkg/irc"
+)
+
+// MessageFilter gets to look at every incoming message before it is
+// handled, it can modify the message and returns false to drop it
+type MessageFilter func(server string, msg *irc.Message) bool
+
+// DropMatching drops PRIVMSGs and NOTICEs with text matching pattern
+func DropMatching(pattern *regexp.Regexp) MessageFilter {
+	return func(server string, msg *irc.Message) bool {
+		if msg.Command != irc.PRIVMSG && msg.Command != irc.NOTICE {
+			return true
+		}
+		return !pattern.MatchString(msg.LastParam())
+	}
+}
+
+// NewDropFilters compiles the configured drop patterns
+func NewDropFilters(patterns []string) ([]MessageFilter, error) {
+	filters := make([]MessageFilter, 0, len(patterns))
+	for _, p := range patterns {
+		pattern, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, DropMatching(pattern))
+	}
+	return filters, nil
+}
+
+// filterMessage runs msg through the filters in order, a panicking filter
+// gets logged and skipped
+func (i *ircHandler) filterMessage(msg *irc.Message) bool {
+	for _, filter := range i.filters {
+		if !runFilter(filter, i.client.Host(), msg) {
+			return false
+		}
+	}
+	return true
+}
+
+func runFilter(filter MessageFilter, server string, msg *irc.Message) (keep bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Println("[Filter]", server, "Recovered from panic:", r)
+			keep = true
+		}
+	}()
+
+	return filter(server, msg)
+}
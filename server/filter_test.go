@@ -0,0 +1,74 @@
+package server
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/stretchr/testify/assert"
+)
+
+func filterHandler(filters ...MessageFilter) (*ircHandler, *State) {
+	c := irc.NewClient(&irc.Config{
+		Nick:     "nick",
+		Username: "user",
+		Host:     "host.com",
+	})
+	s := NewState(user, nil)
+	i := newIRCHandler(c, s)
+	i.filters = filters
+	return i, s
+}
+
+func TestFilterDrop(t *testing.T) {
+	i, s := filterHandler(DropMatching(regexp.MustCompile("(?i)buy cheap")))
+
+	i.dispatchMessage(&irc.Message{
+		Command: irc.PRIVMSG,
+		Sender:  "spammer",
+		Params:  []string{"#chan", "BUY CHEAP stuff"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.PRIVMSG,
+		Sender:  "someone",
+		Params:  []string{"#chan", "hello"},
+	})
+
+	res := <-s.broadcast
+	assert.Equal(t, "message", res.Type)
+	assert.Equal(t, "hello", res.Data.(Message).Content)
+	assert.Len(t, s.broadcast, 0)
+}
+
+func TestFilterTransform(t *testing.T) {
+	mask := func(server string, msg *irc.Message) bool {
+		if msg.Command == irc.PRIVMSG {
+			msg.Params[len(msg.Params)-1] = strings.Replace(msg.LastParam(), "darn", "****", -1)
+		}
+		return true
+	}
+	panics := func(server string, msg *irc.Message) bool {
+		panic("broken filter")
+	}
+	i, s := filterHandler(panics, mask)
+
+	i.dispatchMessage(&irc.Message{
+		Command: irc.PRIVMSG,
+		Sender:  "someone",
+		Params:  []string{"#chan", "darn it"},
+	})
+
+	res := <-s.broadcast
+	assert.Equal(t, "message", res.Type)
+	assert.Equal(t, "**** it", res.Data.(Message).Content)
+}
+
+func TestNewDropFilters(t *testing.T) {
+	filters, err := NewDropFilters([]string{"a+", "b"})
+	assert.Nil(t, err)
+	assert.Len(t, filters, 2)
+
+	_, err = NewDropFilters([]string{"("})
+	assert.NotNil(t, err)
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 
 	"github.com/khlieng/dispatch/config"
@@ -30,6 +31,7 @@ type indexData struct {
 	Version  dispatchVersion
 
 	Settings *storage.ClientSettings
+	AlwaysOn bool
 
 	// Users in the selected channel
 	Users *Userlist
@@ -59,14 +61,20 @@ func (d *Dispatch) getIndexData(r *http.Request, state *State) *indexData {
 	}
 
 	data.Settings = state.user.GetClientSettings()
+	data.AlwaysOn = state.user.IsAlwaysOn()
 
 	servers, err := state.user.GetServers()
 	if err != nil {
 		return nil
 	}
+	sort.SliceStable(servers, func(i, j int) bool {
+		return servers[i].Order < servers[j].Order
+	})
+
 	connections := state.getConnectionStates()
 	for _, server := range servers {
 		server.Password = ""
+		server.OperPassword = ""
 		server.Username = ""
 		server.Realname = ""
 
@@ -91,6 +99,9 @@ func (d *Dispatch) getIndexData(r *http.Request, state *State) *indexData {
 			channels[i].Topic = client.ChannelTopic(channel.Name)
 		}
 	}
+	sort.SliceStable(channels, func(i, j int) bool {
+		return channels[i].Order < channels[j].Order
+	})
 	data.Channels = channels
 
 	openDMs, err := state.user.GetOpenDMs()
@@ -110,11 +121,9 @@ func (d *Dispatch) getIndexData(r *http.Request, state *State) *indexData {
 func (d *indexData) addUsersAndMessages(server, name string, state *State) {
 	if i, ok := state.getIRC(server); ok && isChannel(name) {
 		if users := i.ChannelUsers(name); len(users) > 0 {
-			d.Users = &Userlist{
-				Server:  server,
-				Channel: name,
-				Users:   users,
-			}
+			userlist := newUserlist(server, name, users, state.userlistLimit())
+			state.fillUserlist(i, &userlist)
+			d.Users = &userlist
 		}
 	}
 
@@ -139,6 +139,8 @@ func easyjson7e607aefDecodeGithubComKhliengDispatchServer(in *jlexer.Lexer, out
 					in.AddError((*out.Settings).UnmarshalJSON(data))
 				}
 			}
+		case "alwaysOn":
+			out.AlwaysOn = bool(in.Bool())
 		case "users":
 			if in.IsNull() {
 				in.Skip()
@@ -274,6 +276,16 @@ func easyjson7e607aefEncodeGithubComKhliengDispatchServer(out *jwriter.Writer, i
 		}
 		out.Raw((*in.Settings).MarshalJSON())
 	}
+	if in.AlwaysOn {
+		const prefix string = ",\"alwaysOn\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.AlwaysOn))
+	}
 	if in.Users != nil {
 		const prefix string = ",\"users\":"
 		if first {
@@ -400,6 +412,16 @@ func easyjson7e607aefDecodeGithubComKhliengDispatchStorage(in *jlexer.Lexer, out
 			out.Name = string(in.String())
 		case "topic":
 			out.Topic = string(in.String())
+		case "order":
+			out.Order = int32(in.Int32())
+		case "group":
+			out.Group = string(in.String())
+		case "color":
+			out.Color = string(in.String())
+		case "label":
+			out.Label = string(in.String())
+		case "lazy":
+			out.Lazy = bool(in.Bool())
 		default:
 			in.SkipRecursive()
 		}
@@ -440,6 +462,56 @@ func easyjson7e607aefEncodeGithubComKhliengDispatchStorage(out *jwriter.Writer,
 		}
 		out.String(string(in.Topic))
 	}
+	if in.Order != 0 {
+		const prefix string = ",\"order\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int32(int32(in.Order))
+	}
+	if in.Group != "" {
+		const prefix string = ",\"group\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Group))
+	}
+	if in.Color != "" {
+		const prefix string = ",\"color\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Color))
+	}
+	if in.Label != "" {
+		const prefix string = ",\"label\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Label))
+	}
+	if in.Lazy {
+		const prefix string = ",\"lazy\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Lazy))
+	}
 	out.RawByte('}')
 }
 func easyjson7e607aefDecodeGithubComKhliengDispatchServer1(in *jlexer.Lexer, out *dispatchVersion) {
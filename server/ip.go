@@ -1,6 +1,14 @@
 package server
 
-import "net"
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/khlieng/dispatch/config"
+)
+
+var defaultProxyHeaders = []string{"X-Forwarded-For", "X-Real-IP"}
 
 func addrToIPBytes(addr net.Addr) []byte {
 	ip := addr.(*net.TCPAddr).IP
@@ -11,3 +19,60 @@ func addrToIPBytes(addr net.Addr) []byte {
 
 	return ip
 }
+
+func isTrustedProxy(ip net.IP, trusted []string) bool {
+	for _, proxy := range trusted {
+		if strings.Contains(proxy, "/") {
+			if _, ipnet, err := net.ParseCIDR(proxy); err == nil && ipnet.Contains(ip) {
+				return true
+			}
+		} else if proxyIP := net.ParseIP(proxy); proxyIP != nil && proxyIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// realIP returns the IP of the client that made r, the proxy headers are
+// only used when the request comes from a trusted proxy since anyone can
+// set them
+func realIP(r *http.Request, cfg config.Proxy) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+
+	if ip == nil || !isTrustedProxy(ip, cfg.Trusted) {
+		return ip
+	}
+
+	headers := cfg.Headers
+	if len(headers) == 0 {
+		headers = defaultProxyHeaders
+	}
+
+	for _, header := range headers {
+		values := r.Header.Values(header)
+		if len(values) == 0 {
+			continue
+		}
+
+		// Each proxy appends the address it got the request from, walk
+		// back until reaching one that is not a trusted proxy
+		hops := strings.Split(strings.Join(values, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := net.ParseIP(strings.TrimSpace(hops[i]))
+			if hop == nil {
+				break
+			}
+			ip = hop
+			if !isTrustedProxy(hop, cfg.Trusted) {
+				break
+			}
+		}
+		return ip
+	}
+
+	return ip
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRealIP(t *testing.T) {
+	proxy := config.Proxy{
+		Trusted: []string{"10.0.0.1", "192.168.0.0/16"},
+	}
+
+	cases := []struct {
+		remoteAddr string
+		headers    map[string]string
+		expected   string
+	}{
+		{"203.0.113.5:1234", nil, "203.0.113.5"},
+		// Spoofed headers from clients that are not proxies are ignored
+		{"203.0.113.5:1234", map[string]string{"X-Forwarded-For": "1.2.3.4"}, "203.0.113.5"},
+		{"203.0.113.5:1234", map[string]string{"X-Real-IP": "1.2.3.4"}, "203.0.113.5"},
+		{"10.0.0.1:1234", map[string]string{"X-Forwarded-For": "198.51.100.7"}, "198.51.100.7"},
+		{"192.168.1.20:1234", map[string]string{"X-Real-IP": "198.51.100.7"}, "198.51.100.7"},
+		// A client can prepend whatever it wants, only the hops added by
+		// trusted proxies count
+		{"10.0.0.1:1234", map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.7, 192.168.1.1"}, "198.51.100.7"},
+		{"10.0.0.1:1234", map[string]string{"X-Forwarded-For": "garbage"}, "10.0.0.1"},
+		{"10.0.0.1:1234", nil, "10.0.0.1"},
+		{"[2001:db8::1]:1234", map[string]string{"X-Forwarded-For": "1.2.3.4"}, "2001:db8::1"},
+	}
+
+	for _, tc := range cases {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = tc.remoteAddr
+		for k, v := range tc.headers {
+			r.Header.Set(k, v)
+		}
+
+		assert.Equal(t, net.ParseIP(tc.expected), realIP(r, proxy), tc.remoteAddr, tc.headers)
+	}
+}
+
+func TestRealIPHeaders(t *testing.T) {
+	proxy := config.Proxy{
+		Trusted: []string{"10.0.0.1"},
+		Headers: []string{"CF-Connecting-IP"},
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("X-Forwarded-For", "1.2.3.4")
+	assert.Equal(t, net.ParseIP("10.0.0.1"), realIP(r, proxy))
+
+	r.Header.Set("CF-Connecting-IP", "198.51.100.7")
+	assert.Equal(t, net.ParseIP("198.51.100.7"), realIP(r, proxy))
+}
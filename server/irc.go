@@ -2,14 +2,25 @@ package server
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"net"
+	"strings"
 
+	"github.com/khlieng/dispatch/config"
 	"github.com/khlieng/dispatch/pkg/irc"
 	"github.com/khlieng/dispatch/storage"
 	"github.com/khlieng/dispatch/version"
 )
 
+// leaveMessage fills in a quit or part message set by the user,
+// {version} is replaced by the version of dispatch
+func leaveMessage(message string) string {
+	return strings.ReplaceAll(message, "{version}", version.Tag)
+}
+
 func createNickInUseHandler(i *irc.Client, state *State) func(string) string {
 	return func(nick string) string {
 		newNick := nick + "_"
@@ -29,36 +40,75 @@ func createNickInUseHandler(i *irc.Client, state *State) func(string) string {
 	}
 }
 
+func getWebIRC(cfg *config.Config, host string) (config.WebIRC, bool) {
+	for _, webirc := range cfg.WebIRC {
+		if webirc.Host == host && webirc.Password != "" {
+			if webirc.Gateway == "" {
+				webirc.Gateway = "dispatch"
+			}
+			return webirc, true
+		}
+	}
+	return config.WebIRC{}, false
+}
+
+func getBindAddress(cfg *config.Config, host string) string {
+	for _, bind := range cfg.Bind {
+		if bind.Host == host {
+			return bind.Address
+		}
+	}
+	return cfg.BindAddress
+}
+
 func connectIRC(server *storage.Server, state *State, srcIP []byte) *irc.Client {
 	cfg := state.srv.Config()
 
 	ircCfg := irc.Config{
-		Host:     server.Host,
-		Port:     server.Port,
-		TLS:      server.TLS,
-		Nick:     server.Nick,
-		Username: server.Username,
-		Realname: server.Realname,
-		Account:  server.Account,
-		Password: server.Password,
-		Version:  fmt.Sprintf("Dispatch %s (git: %s)", version.Tag, version.Commit),
-		Source:   "https://github.com/khlieng/dispatch",
+		Host:          server.Host,
+		Port:          server.Port,
+		TLS:           server.TLS,
+		Nick:          server.Nick,
+		Username:      server.Username,
+		Realname:      server.Realname,
+		Account:       server.Account,
+		Password:      server.Password,
+		BindAddress:   getBindAddress(cfg, server.Host),
+		FallbackDelay: cfg.FallbackDelay,
+		PreferIPv4:    cfg.PreferIPv4,
+		Version:       fmt.Sprintf("Dispatch %s (git: %s)", version.Tag, version.Commit),
+		Source:        "https://github.com/khlieng/dispatch",
+		ClientTags:    parseClientTags(server.SendTags),
+
+		DNSTimeout:          cfg.Timeouts.DNS,
+		ConnectTimeout:      cfg.Timeouts.Connect,
+		TLSTimeout:          cfg.Timeouts.TLS,
+		RegistrationTimeout: cfg.Timeouts.Registration,
+		PingInterval:        cfg.Timeouts.PingInterval,
+		PingTimeout:         cfg.Timeouts.PingTimeout,
+		KillCooldown:        cfg.KillCooldown,
 	}
 
-	if server.TLS {
-		ircCfg.TLSConfig = &tls.Config{
-			InsecureSkipVerify: !cfg.VerifyCertificates,
-		}
+	if len(cfg.SASLMechanisms) > 0 {
+		ircCfg.SASLMechanisms = cfg.SASLMechanisms
+	}
 
-		if cert := state.user.GetCertificate(); cert != nil {
-			ircCfg.TLSConfig.Certificates = []tls.Certificate{*cert}
-		}
+	if server.TLS {
+		ircCfg.TLSConfig = newTLSConfig(cfg, server, state.user.GetCertificate())
 	}
 
 	if cfg.HexIP {
 		ircCfg.Username = hex.EncodeToString(srcIP)
 	}
 
+	if webirc, ok := getWebIRC(cfg, server.Host); ok && len(srcIP) > 0 {
+		ircCfg.WebIRC = irc.WebIRC{
+			Password: webirc.Password,
+			Gateway:  webirc.Gateway,
+			IP:       net.IP(srcIP).String(),
+		}
+	}
+
 	if server.ServerPassword == "" &&
 		cfg.Defaults.ServerPassword != "" &&
 		server.Host == cfg.Defaults.Host {
@@ -71,8 +121,82 @@ func connectIRC(server *storage.Server, state *State, srcIP []byte) *irc.Client
 	i.Config.HandleNickInUse = createNickInUseHandler(i, state)
 
 	state.setIRC(server.Host, i)
+	state.setForwardTags(server.Host, server.ForwardTags)
 	i.Connect()
 	go newIRCHandler(i, state).run()
 
 	return i
 }
+
+// newTLSConfig returns the TLS config for connecting to server, the SNI
+// server name is left empty to default to the host
+func newTLSConfig(cfg *config.Config, server *storage.Server, cert *tls.Certificate) *tls.Config {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: !cfg.VerifyCertificates,
+		ServerName:         server.TLSServerName,
+		NextProtos:         server.ALPN,
+	}
+
+	if cert != nil {
+		tlsConfig.Certificates = []tls.Certificate{*cert}
+	}
+	setPinnedCerts(tlsConfig, server.PinnedCerts, !cfg.VerifyCertificates)
+	return tlsConfig
+}
+
+var errPinMismatch = errors.New("The certificate does not match any of the pinned fingerprints")
+
+// setPinnedCerts makes tlsConfig trust the certificates matching any of
+// pins instead of verifying them, without pins it goes back to skipVerify
+func setPinnedCerts(tlsConfig *tls.Config, pins []string, skipVerify bool) {
+	if len(pins) == 0 {
+		tlsConfig.InsecureSkipVerify = skipVerify
+		tlsConfig.VerifyPeerCertificate = nil
+		return
+	}
+
+	pins = append([]string(nil), pins...)
+	tlsConfig.InsecureSkipVerify = true
+	tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errPinMismatch
+		}
+		cert, err := x509.ParseCertificate(rawCerts[0])
+		if err != nil {
+			return err
+		}
+		if matchPin(pins, irc.CertificateFingerprint(cert)) < 0 {
+			return errPinMismatch
+		}
+		return nil
+	}
+}
+
+// matchPin returns the index of the pin matching fingerprint, 0 is the
+// current one and anything after it is a rotation pin, or -1 if none match
+func matchPin(pins []string, fingerprint string) int {
+	for i, pin := range pins {
+		if pin == fingerprint {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseClientTags turns client tags in the key=value form into a map
+func parseClientTags(tags []string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	m := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		kv := strings.SplitN(tag, "=", 2)
+		if len(kv) == 2 {
+			m[kv[0]] = kv[1]
+		} else {
+			m[kv[0]] = ""
+		}
+	}
+	return m
+}
@@ -1,35 +1,58 @@
 package server
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"log"
+	"net"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 	"unicode"
 
-	"github.com/kjk/betterguid"
-
 	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/khlieng/dispatch/pkg/logging"
 	"github.com/khlieng/dispatch/storage"
 )
 
+// autoRunDelay is the time between each auto-run command
+var autoRunDelay = 500 * time.Millisecond
+
+// certExpiryWarning is how close to expiring the certificate of a server
+// has to be for the user to get warned
+const certExpiryWarning = 14 * 24 * time.Hour
+
 var excludedErrors = []string{
 	irc.ERR_NICKNAMEINUSE,
 	irc.ERR_NICKCOLLISION,
 	irc.ERR_UNAVAILRESOURCE,
 	irc.ERR_FORWARD,
+	irc.ERR_NOMOTD,
 }
 
+const (
+	chatHistoryLimit = 100
+	maxPendingWhois  = 32
+)
+
 type ircHandler struct {
 	client *irc.Client
 	state  *State
 
-	whois       WhoisReply
+	whois       map[string]*WhoisReply
 	motdBuffer  MOTD
+	helpBuffer  Help
 	listBuffer  storage.ChannelListIndex
+	listCount   int
+	netsplits   *netsplitTracker
 	dccProgress chan irc.DownloadProgress
+	filters     []MessageFilter
+	// killed is set when a KILL for the user arrived before the ERROR
+	// closing the link
+	killed bool
 
 	handlers map[string]func(*irc.Message)
 }
@@ -38,7 +61,12 @@ func newIRCHandler(client *irc.Client, state *State) *ircHandler {
 	i := &ircHandler{
 		client:      client,
 		state:       state,
+		whois:       map[string]*WhoisReply{},
 		dccProgress: make(chan irc.DownloadProgress, 4),
+		netsplits:   newNetsplitTracker(client.Host(), state),
+	}
+	if state.srv != nil {
+		i.filters = state.srv.Filters
 	}
 	i.initHandlers()
 	return i
@@ -50,7 +78,10 @@ func (i *ircHandler) run() {
 		select {
 		case msg, ok := <-i.client.Messages:
 			if !ok {
-				i.state.deleteIRC(i.client.Host())
+				// A new client might have taken its place already
+				if current, ok := i.state.getIRC(i.client.Host()); !ok || current == i.client {
+					i.state.deleteIRC(i.client.Host())
+				}
 				return
 			}
 
@@ -63,17 +94,19 @@ func (i *ircHandler) run() {
 			if state.Error != nil && (lastConnErr == nil ||
 				state.Error.Error() != lastConnErr.Error()) {
 				lastConnErr = state.Error
-				i.log("Connection error:", state.Error)
+				i.log(logging.LevelWarn, "Connection error", logging.F("error", state.Error))
 			} else if state.Connected {
-				i.log("Connected")
+				i.log(logging.LevelInfo, "Connected")
+				i.sendTLSInfo()
 			}
 
 		case progress := <-i.dccProgress:
+			i.state.setDCCProgress(progress)
+
 			if progress.Error != nil {
 				i.sendDCCInfo("%s: Download failed (%s)", true, progress.File, progress.Error)
 			} else if progress.PercCompletion == 100 {
-				i.sendDCCInfo("Download finished, get it here: %s://%s/downloads/%s/%s", true,
-					i.state.String("scheme"), i.state.String("host"), i.state.user.Username, progress.File)
+				i.sendDCCInfo("Download finished, get it here: %s", true, i.state.downloadURL(progress.File))
 			} else if progress.PercCompletion == 0 {
 				i.sendDCCInfo("%s: Starting download", true, progress.File)
 			} else {
@@ -85,6 +118,14 @@ func (i *ircHandler) run() {
 }
 
 func (i *ircHandler) dispatchMessage(msg *irc.Message) {
+	if logging.Default().Enabled(logging.LevelTrace) {
+		i.printMessage(msg)
+	}
+
+	if !i.filterMessage(msg) {
+		return
+	}
+
 	if msg.Command[0] == '4' && !isExcludedError(msg.Command) {
 		err := IRCError{
 			Server:  i.client.Host(),
@@ -119,36 +160,73 @@ func (i *ircHandler) nick(msg *irc.Message) {
 		go i.state.user.SetNick(msg.LastParam(), i.client.Host())
 	}
 
+	i.state.renameMetadata(i.client.Host(), msg.Sender, msg.LastParam())
+	i.state.renameWhoUser(i.client.Host(), msg.Sender, msg.LastParam())
+
 	channels := irc.GetNickChannels(msg)
 	go i.state.user.LogEvent(i.client.Host(), "nick", []string{msg.Sender, msg.LastParam()}, channels...)
 }
 
 func (i *ircHandler) join(msg *irc.Message) {
 	host := i.client.Host()
-
-	i.state.sendJSON("join", Join{
-		Server:   host,
-		User:     msg.Sender,
-		Channels: msg.Params,
-	})
-
 	channel := msg.Params[0]
 
+	if i.client.Is(msg.Sender) || !i.netsplits.join(msg.Sender, channel) {
+		i.state.sendJSON("join", Join{
+			Server:   host,
+			User:     msg.Sender,
+			Channels: msg.Params,
+			Away:     i.client.IsAway(msg.Sender),
+		})
+	}
+
 	if i.client.Is(msg.Sender) {
-		// In case no topic is set and there's a cached one that needs to be cleared
-		i.client.Topic(channel)
+		duplicate := !i.state.setJoined(host, i.client.Casefold(channel)) &&
+			i.state.ignoreDuplicateJoins()
+
+		// Lazy channels get their topic and userlist when the client
+		// asks for them with load_channel
+		if !i.state.user.IsChannelLazy(host, channel) {
+			// In case no topic is set and there's a cached one that needs to be cleared
+			i.client.Topic(channel)
+
+			if i.client.HasCapability("draft/no-implicit-names") {
+				i.client.Names(channel)
+			}
+		}
+
+		// Bouncers can replay the JOINs for channels the user is already
+		// in, the client has the scrollback and the channel is stored.
+		// Missed messages still get requested, the chathistory batch skips
+		// the ones that are already logged
+		if duplicate {
+			i.requestChatHistory(channel)
+			return
+		}
 
 		i.state.sendLastMessages(host, channel, 50)
+		i.requestChatHistory(channel)
 
-		go i.state.user.AddChannel(&storage.Channel{
-			Server: host,
-			Name:   channel,
-		})
+		go i.addChannel(host, channel)
 	}
 
 	go i.state.user.LogEvent(host, "join", []string{msg.Sender}, channel)
 }
 
+func (i *ircHandler) addChannel(host, channel string) {
+	err := i.state.user.AddChannel(&storage.Channel{
+		Server: host,
+		Name:   channel,
+	})
+	if err == storage.ErrChannelLimit {
+		i.state.sendJSON("error", Error{
+			Server:  host,
+			Message: err.Error(),
+		})
+		i.client.Part(channel)
+	}
+}
+
 func (i *ircHandler) part(msg *irc.Message) {
 	host := i.client.Host()
 	channel := msg.Params[0]
@@ -165,12 +243,32 @@ func (i *ircHandler) part(msg *irc.Message) {
 	i.state.sendJSON("part", part)
 
 	if i.client.Is(msg.Sender) {
+		i.state.setParted(host, i.client.Casefold(channel))
+		i.state.deleteWhoChannel(host, i.client.Casefold(channel))
 		go i.state.user.RemoveChannel(host, part.Channel)
+	} else {
+		i.state.deleteWhoUser(host, i.client.Casefold(channel), msg.Sender)
 	}
 
 	go i.state.user.LogEvent(host, "part", []string{msg.Sender}, channel)
 }
 
+// kick forgets the channel when the user got kicked from it, so joining
+// it again is not taken for a replayed JOIN
+func (i *ircHandler) kick(msg *irc.Message) {
+	if len(msg.Params) < 2 {
+		return
+	}
+
+	channel := i.client.Casefold(msg.Params[0])
+	if i.client.Is(msg.Params[1]) {
+		i.state.setParted(i.client.Host(), channel)
+		i.state.deleteWhoChannel(i.client.Host(), channel)
+	} else {
+		i.state.deleteWhoUser(i.client.Host(), channel, msg.Params[1])
+	}
+}
+
 func (i *ircHandler) mode(msg *irc.Message) {
 	if mode := irc.GetMode(msg); mode != nil {
 		i.state.sendJSON("mode", Mode{
@@ -187,17 +285,35 @@ func (i *ircHandler) message(msg *irc.Message) {
 				return
 			}
 		} else if ctcp.Command != "ACTION" {
+			if msg.Command == irc.NOTICE {
+				i.ctcpReply(msg, ctcp)
+			}
 			return
 		}
 	}
 
+	sent := msg.Time()
 	message := Message{
-		ID:      betterguid.New(),
+		ID:      storage.MessageIDAt(sent),
 		Server:  i.client.Host(),
 		From:    msg.Sender,
-		Content: msg.LastParam(),
+		Account: msg.Tags["account"],
+		Content: storage.LimitMessage(msg.LastParam()),
+		Away:    i.client.IsAway(msg.Sender),
+		MsgID:   msg.Tags["msgid"],
+		ReplyTo: msg.ReplyTo(),
+		Tags:    msg.ClientTags(i.state.getForwardTags(i.client.Host())),
+	}
+	statusMsg, target := i.client.SplitStatusMsg(msg.Params[0])
+	message.StatusMsg = statusMsg
+	message.Avatar = i.state.avatar(message.Server, message.From, message.Account)
+
+	// The server sent our own message back, it has already been
+	// logged and shown when it was sent
+	if i.client.Is(msg.Sender) && msg.Command == irc.PRIVMSG &&
+		i.state.sent.take(message.Server, target, msg.LastParam()) {
+		return
 	}
-	target := msg.Params[0]
 
 	if i.client.Is(target) {
 		i.state.sendJSON("pm", message)
@@ -217,22 +333,164 @@ func (i *ircHandler) message(msg *irc.Message) {
 			ID:      message.ID,
 			Server:  message.Server,
 			From:    message.From,
+			Account: message.Account,
 			To:      target,
 			Content: message.Content,
+			Time:    sent.Unix(),
+			MsgID:   message.MsgID,
+			ReplyTo: message.ReplyTo,
+			Mention: i.isMention(msg.Sender, message.Content),
 		})
 	}
+
+	if target != "*" {
+		i.previewLinks(target, &message)
+	}
+}
+
+func (i *ircHandler) previewLinks(target string, message *Message) {
+	if i.state.srv == nil || !i.state.srv.Config().LinkPreviews.Enabled {
+		return
+	}
+
+	for _, url := range previewURLs(message.Content) {
+		go func(url string) {
+			if meta := i.state.srv.previews.fetch(url); meta != nil {
+				i.state.sendJSON("link_preview", LinkPreview{
+					Server:      message.Server,
+					Target:      target,
+					MessageID:   message.ID,
+					URL:         url,
+					SiteName:    meta.SiteName,
+					Color:       meta.Color,
+					Title:       meta.Title,
+					Description: meta.Description,
+					ImageURL:    meta.ImageURL,
+					VideoURL:    meta.VideoURL,
+				})
+			}
+		}(url)
+	}
+}
+
+func (i *ircHandler) ctcpReply(msg *irc.Message, ctcp *irc.CTCP) {
+	req, ok := i.state.takeCTCPRequest(i.client.Host(), msg.Sender, ctcp.Command)
+	if !ok {
+		return
+	}
+
+	i.state.sendJSON("ctcp_reply", CTCPReply{
+		Server:  i.client.Host(),
+		From:    msg.Sender,
+		Command: ctcp.Command,
+		Reply:   ctcp.Params,
+		Latency: time.Since(req.sent).Milliseconds(),
+	})
 }
 
 func (i *ircHandler) quit(msg *irc.Message) {
+	if !i.netsplits.quit(msg.Sender, msg.LastParam()) {
+		i.state.sendJSON("quit", Quit{
+			Server: i.client.Host(),
+			User:   msg.Sender,
+			Reason: msg.LastParam(),
+		})
+	}
+
+	i.state.deleteMetadata(i.client.Host(), msg.Sender)
+	i.state.deleteWhoUser(i.client.Host(), "", msg.Sender)
+
+	channels := irc.GetQuitChannels(msg)
+
+	go i.state.user.LogEvent(i.client.Host(), "quit", []string{msg.Sender, msg.LastParam()}, channels...)
+}
+
+// kill tells the user when an operator killed the connection, others
+// getting killed is shown like a quit
+func (i *ircHandler) kill(msg *irc.Message) {
+	if len(msg.Params) == 0 {
+		return
+	}
+	target := msg.Params[0]
+	reason := msg.LastParam()
+	if len(msg.Params) < 2 {
+		reason = ""
+	}
+
+	if i.client.Is(target) {
+		i.killed = true
+		i.sendKilled(msg.Sender, reason)
+		return
+	}
+
+	quitReason := "Killed (" + msg.Sender + " (" + reason + "))"
 	i.state.sendJSON("quit", Quit{
 		Server: i.client.Host(),
-		User:   msg.Sender,
-		Reason: msg.LastParam(),
+		User:   target,
+		Reason: quitReason,
 	})
 
 	channels := irc.GetQuitChannels(msg)
 
-	go i.state.user.LogEvent(i.client.Host(), "quit", []string{msg.Sender, msg.LastParam()}, channels...)
+	go i.state.user.LogEvent(i.client.Host(), "quit", []string{target, quitReason}, channels...)
+}
+
+func (i *ircHandler) sendKilled(by, reason string) {
+	cooldown := i.client.Config.KillCooldown
+	if cooldown < 0 {
+		cooldown = 0
+	}
+
+	i.log(logging.LevelWarn, "Killed by "+by, logging.F("reason", reason))
+	i.state.sendJSON("killed", Killed{
+		Server:   i.client.Host(),
+		By:       by,
+		Reason:   reason,
+		Cooldown: int64(cooldown / time.Second),
+	})
+}
+
+// parseKillReason splits the reason of a closing link like
+// "Killed (oper (reason))" into who did the kill and why
+func parseKillReason(reason string) (by, why string, ok bool) {
+	if !strings.HasPrefix(reason, "Killed (") || !strings.HasSuffix(reason, ")") {
+		return "", "", false
+	}
+
+	by = reason[len("Killed (") : len(reason)-1]
+	if idx := strings.Index(by, " ("); idx >= 0 {
+		why = strings.TrimSuffix(by[idx+2:], ")")
+		by = by[:idx]
+	}
+	return by, why, true
+}
+
+// runCommands sends OPER when credentials are stored for the server and
+// then its auto-run commands, a leading / is stripped since they are sent
+// as raw IRC lines
+func (i *ircHandler) runCommands() {
+	server, err := i.state.user.GetServer(i.client.Host())
+	if err != nil {
+		return
+	}
+
+	if name, password, _ := i.state.user.GetServerOper(i.client.Host()); name != "" && password != "" {
+		i.client.Oper(name, password)
+	}
+
+	sent := 0
+	for _, command := range server.Commands {
+		command = strings.TrimSpace(strings.TrimPrefix(command, "/"))
+		if command == "" {
+			continue
+		}
+
+		if sent > 0 {
+			time.Sleep(autoRunDelay)
+		}
+		i.client.Write(command)
+		sent++
+	}
 }
 
 func (i *ircHandler) info(msg *irc.Message) {
@@ -249,6 +507,10 @@ func (i *ircHandler) info(msg *irc.Message) {
 		}
 
 		go i.state.user.SetNick(msg.Params[0], i.client.Host())
+		go func() {
+			i.runCommands()
+			i.state.flushOutbox(i.client)
+		}()
 	}
 
 	i.state.sendJSON("pm", Message{
@@ -274,26 +536,98 @@ func (i *ircHandler) features(msg *irc.Message) {
 	}
 }
 
+// whoisReply returns the WHOIS being collected for the nick a reply is
+// about, WHOIS replies for different nicks can arrive interleaved
+func (i *ircHandler) whoisReply(msg *irc.Message, create bool) *WhoisReply {
+	if len(msg.Params) < 2 {
+		return nil
+	}
+
+	key := i.client.Casefold(msg.Params[1])
+	whois, ok := i.whois[key]
+	if !ok && create {
+		// Replies that never got an end are dropped eventually
+		if len(i.whois) >= maxPendingWhois {
+			i.whois = map[string]*WhoisReply{}
+		}
+
+		whois = &WhoisReply{}
+		i.whois[key] = whois
+	}
+	return whois
+}
+
 func (i *ircHandler) whoisUser(msg *irc.Message) {
-	i.whois.Nick = msg.Params[1]
-	i.whois.Username = msg.Params[2]
-	i.whois.Host = msg.Params[3]
-	i.whois.Realname = msg.Params[5]
+	if whois := i.whoisReply(msg, true); whois != nil && len(msg.Params) > 5 {
+		whois.Nick = msg.Params[1]
+		whois.Username = msg.Params[2]
+		whois.Host = msg.Params[3]
+		whois.Realname = msg.Params[5]
+	}
 }
 
 func (i *ircHandler) whoisServer(msg *irc.Message) {
-	i.whois.Server = msg.Params[2]
+	if whois := i.whoisReply(msg, true); whois != nil && len(msg.Params) > 2 {
+		whois.Server = msg.Params[2]
+	}
 }
 
 func (i *ircHandler) whoisChannels(msg *irc.Message) {
-	i.whois.Channels = append(i.whois.Channels, strings.Split(strings.TrimRight(msg.LastParam(), " "), " ")...)
+	if whois := i.whoisReply(msg, true); whois != nil {
+		whois.Channels = append(whois.Channels, strings.Split(strings.TrimRight(msg.LastParam(), " "), " ")...)
+	}
+}
+
+func (i *ircHandler) whoisOperator(msg *irc.Message) {
+	if whois := i.whoisReply(msg, true); whois != nil {
+		whois.Operator = msg.LastParam()
+	}
+}
+
+func (i *ircHandler) whoisSpecial(msg *irc.Message) {
+	if whois := i.whoisReply(msg, true); whois != nil {
+		whois.Special = append(whois.Special, msg.LastParam())
+	}
+}
+
+// whoisBot handles both 335 and 336, some servers send the bot line as 336
+// which others use for RPL_INVITELIST, so it has to match an ongoing WHOIS
+func (i *ircHandler) whoisBot(msg *irc.Message) {
+	if whois := i.whoisReply(msg, false); whois != nil && len(msg.Params) > 2 {
+		whois.Bot = true
+	}
+}
+
+// whoisHost handles RPL_WHOISHOST, "is connecting from *@host ip"
+func (i *ircHandler) whoisHost(msg *irc.Message) {
+	whois := i.whoisReply(msg, true)
+	if whois == nil {
+		return
+	}
+
+	text := msg.LastParam()
+	if idx := strings.Index(text, "from "); idx >= 0 {
+		fields := strings.Fields(text[idx+5:])
+		if len(fields) > 0 {
+			host := fields[0]
+			if at := strings.IndexByte(host, '@'); at >= 0 {
+				host = host[at+1:]
+			}
+			whois.ActualHost = host
+		}
+		if len(fields) > 1 && net.ParseIP(fields[1]) != nil {
+			whois.ActualIP = fields[1]
+		}
+	}
 }
 
 func (i *ircHandler) whoisEnd(msg *irc.Message) {
-	if i.whois.Nick != "" {
-		i.state.sendJSON("whois", i.whois)
+	if whois := i.whoisReply(msg, false); whois != nil {
+		if whois.Nick != "" {
+			i.state.sendJSON("whois", *whois)
+		}
+		delete(i.whois, i.client.Casefold(msg.Params[1]))
 	}
-	i.whois = WhoisReply{}
 }
 
 func (i *ircHandler) topic(msg *irc.Message) {
@@ -305,6 +639,7 @@ func (i *ircHandler) topic(msg *irc.Message) {
 		nick = msg.Sender
 
 		go i.state.user.LogEvent(i.client.Host(), "topic", []string{nick, msg.LastParam()}, channel)
+		go i.state.user.LogTopic(i.client.Host(), channel, nick, msg.LastParam())
 	} else {
 		channel = msg.Params[1]
 	}
@@ -324,31 +659,206 @@ func (i *ircHandler) noTopic(msg *irc.Message) {
 	})
 }
 
-func (i *ircHandler) namesEnd(msg *irc.Message) {
-	i.state.sendJSON("users", Userlist{
-		Server:  i.client.Host(),
-		Channel: msg.Params[1],
-		Users:   irc.GetNamreplyUsers(msg),
+// metadata caches the keys set on other users to fill in userlists with
+// and passes them on, notifications only come for subscribed keys
+func (i *ircHandler) metadata(msg *irc.Message) {
+	m := irc.GetMetadata(msg)
+	if m == nil {
+		return
+	}
+
+	if !isChannel(m.Target) {
+		i.state.setMetadata(i.client.Host(), m)
+	}
+
+	i.state.sendJSON("metadata", Metadata{
+		Server: i.client.Host(),
+		Target: m.Target,
+		Key:    m.Key,
+		Value:  m.Value,
 	})
 }
 
+func (i *ircHandler) namesEnd(msg *irc.Message) {
+	channel := msg.Params[1]
+	userlist := newUserlist(i.client.Host(), channel,
+		irc.GetNamreplyUsers(msg), i.state.userlistLimit())
+	i.state.fillUserlist(i.client, &userlist)
+
+	i.state.sendJSON("users", userlist)
+}
+
+// whoEnd caches the accounts and hosts from the WHO replies and sends the
+// userlist of the channel again with them, the client has collected the
+// replies per target so queries for different channels do not get mixed up
+func (i *ircHandler) whoEnd(msg *irc.Message) {
+	channel := msg.Params[1]
+	replies := irc.GetWhoReplies(msg)
+	if !isChannel(channel) || len(replies) == 0 {
+		return
+	}
+	i.state.setWhoReplies(i.client.Host(), i.client.Casefold(channel), replies)
+
+	users := i.client.ChannelUsers(channel)
+	if len(users) == 0 {
+		return
+	}
+
+	userlist := newUserlist(i.client.Host(), channel, users, i.state.userlistLimit())
+	i.state.fillUserlist(i.client, &userlist)
+
+	i.state.sendJSON("users", userlist)
+}
+
+func (i *ircHandler) away(msg *irc.Message) {
+	away := UserAway{
+		Server: i.client.Host(),
+		User:   msg.Sender,
+	}
+	if msg.Command == irc.RPL_AWAY {
+		if len(msg.Params) < 2 {
+			return
+		}
+		away.User = msg.Params[1]
+	}
+	if len(msg.Params) > 0 {
+		away.Message = msg.LastParam()
+		away.Away = away.Message != ""
+	}
+
+	i.state.sendJSON("user_away", away)
+}
+
 func (i *ircHandler) motdStart(msg *irc.Message) {
-	i.motdBuffer.Server = i.client.Host()
-	i.motdBuffer.Title = msg.LastParam()
+	// Start over in case an earlier MOTD never ended
+	i.motdBuffer = MOTD{
+		Server: i.client.Host(),
+		Title:  msg.LastParam(),
+	}
 }
 
 func (i *ircHandler) motd(msg *irc.Message) {
+	i.motdBuffer.Server = i.client.Host()
 	i.motdBuffer.Content = append(i.motdBuffer.Content, msg.LastParam())
 }
 
 func (i *ircHandler) motdEnd(msg *irc.Message) {
-	i.state.sendJSON("motd", i.motdBuffer)
+	i.sendMOTD(i.motdBuffer)
 	i.motdBuffer = MOTD{}
 }
 
+func (i *ircHandler) noMOTD(msg *irc.Message) {
+	i.sendMOTD(MOTD{
+		Server:  i.client.Host(),
+		Content: []string{msg.LastParam()},
+		Missing: true,
+	})
+	i.motdBuffer = MOTD{}
+}
+
+// sendMOTD sends the MOTD depending on the ShowMOTD setting of the user,
+// a MOTD the user asked for always gets sent
+func (i *ircHandler) sendMOTD(motd MOTD) {
+	requested := i.state.Bool("request_motd_" + i.client.Host())
+	i.state.Set("request_motd_"+i.client.Host(), false)
+
+	changed := true
+	hash := motdHash(motd)
+	if server, err := i.state.user.GetServer(i.client.Host()); err == nil {
+		changed = server.MOTDHash != hash
+		if changed {
+			if err = i.state.user.SetServerMOTDHash(i.client.Host(), hash); err != nil {
+				i.log(logging.LevelWarn, "Could not store the MOTD hash", logging.F("error", err))
+			}
+		}
+	}
+
+	switch i.state.user.GetClientSettings().ShowMOTD {
+	case storage.MOTDChanged:
+		if !changed && !requested {
+			return
+		}
+	case storage.MOTDNever:
+		if !requested {
+			return
+		}
+	}
+
+	i.state.sendJSON("motd", motd)
+}
+
+// motdHash identifies a MOTD by its content, the title is left out since
+// it names the server, which differs between the servers of a network
+func motdHash(motd MOTD) string {
+	h := sha256.New()
+	for _, line := range motd.Content {
+		h.Write([]byte(line))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// helpTopic returns the subject of a help reply, the params are the nick,
+// the subject and the text
+func helpTopic(msg *irc.Message) string {
+	if len(msg.Params) > 2 {
+		return msg.Params[1]
+	}
+	return ""
+}
+
+func (i *ircHandler) helpStart(msg *irc.Message) {
+	// Start over in case an earlier reply never ended
+	i.helpBuffer = Help{
+		Server:  i.client.Host(),
+		Topic:   helpTopic(msg),
+		Content: []string{msg.LastParam()},
+	}
+}
+
+func (i *ircHandler) help(msg *irc.Message) {
+	if i.helpBuffer.Server == "" {
+		i.helpBuffer.Server = i.client.Host()
+		i.helpBuffer.Topic = helpTopic(msg)
+	}
+	i.helpBuffer.Content = append(i.helpBuffer.Content, msg.LastParam())
+}
+
+func (i *ircHandler) helpEnd(msg *irc.Message) {
+	i.help(msg)
+	i.state.sendJSON("help", i.helpBuffer)
+	i.helpBuffer = Help{}
+}
+
+func (i *ircHandler) helpNotFound(msg *irc.Message) {
+	i.state.sendJSON("help", Help{
+		Server:  i.client.Host(),
+		Topic:   helpTopic(msg),
+		Content: []string{msg.LastParam()},
+		Missing: true,
+	})
+	i.helpBuffer = Help{}
+}
+
+// channelListProgressInterval is how many RPL_LIST entries are received
+// between each channel_list_progress event
+const channelListProgressInterval = 500
+
+// listStart starts a new channel list. Unlike WHOIS and NAMES the LIST
+// replies are not buffered by target, RPL_LIST carries none, and since a
+// server answers one LIST at a time the replies to two of them never
+// interleave, each one ends with RPL_LISTEND before the next starts
+func (i *ircHandler) listStart(msg *irc.Message) {
+	if i.listBuffer != nil || i.state.Bool("update_chanlist_"+i.client.Host()) {
+		i.listBuffer = storage.NewMapChannelListIndex()
+		i.listCount = 0
+	}
+}
+
 func (i *ircHandler) list(msg *irc.Message) {
 	if i.listBuffer == nil && i.state.Bool("update_chanlist_"+i.client.Host()) {
 		i.listBuffer = storage.NewMapChannelListIndex()
+		i.listCount = 0
 	}
 
 	if i.listBuffer != nil {
@@ -358,12 +868,25 @@ func (i *ircHandler) list(msg *irc.Message) {
 			UserCount: userCount,
 			Topic:     msg.LastParam(),
 		})
+
+		i.listCount++
+		if i.listCount%channelListProgressInterval == 0 {
+			i.state.sendJSON("channel_list_progress", ChannelListProgress{
+				Server: i.client.Host(),
+				Count:  i.listCount,
+			})
+		}
 	}
 }
 
 func (i *ircHandler) listEnd(msg *irc.Message) {
 	if i.listBuffer != nil {
 		i.state.Set("update_chanlist_"+i.client.Host(), false)
+		i.state.sendJSON("channel_list_progress", ChannelListProgress{
+			Server: i.client.Host(),
+			Count:  i.listCount,
+			Done:   true,
+		})
 
 		go func(idx storage.ChannelListIndex) {
 			idx.Finish()
@@ -380,6 +903,19 @@ func (i *ircHandler) badNick(msg *irc.Message) {
 	})
 }
 
+// saslMechs reports when the client gave up on SASL because the server
+// supports none of the configured mechanisms
+func (i *ircHandler) saslMechs(msg *irc.Message) {
+	if err := i.client.SASLError(); err != nil {
+		i.log(logging.LevelWarn, "SASL failed", logging.F("error", err))
+
+		i.state.sendJSON("error", Error{
+			Server:  i.client.Host(),
+			Message: err.Error(),
+		})
+	}
+}
+
 func (i *ircHandler) forward(msg *irc.Message) {
 	if len(msg.Params) > 2 {
 		i.state.sendJSON("channel_forward", ChannelForward{
@@ -390,34 +926,196 @@ func (i *ircHandler) forward(msg *irc.Message) {
 	}
 }
 
+// rename moves the stored channel along with its messages to the new
+// name before telling the client, so anything it fetches afterwards
+// is found under the new name
+func (i *ircHandler) rename(msg *irc.Message) {
+	if len(msg.Params) < 2 {
+		return
+	}
+
+	rename := ChannelRename{
+		Server: i.client.Host(),
+		Old:    msg.Params[0],
+		New:    msg.Params[1],
+	}
+	if len(msg.Params) > 2 {
+		rename.Reason = msg.LastParam()
+	}
+
+	i.state.setParted(rename.Server, i.client.Casefold(rename.Old))
+	i.state.setJoined(rename.Server, i.client.Casefold(rename.New))
+
+	err := i.state.user.RenameChannel(rename.Server, rename.Old, rename.New)
+	if err != nil {
+		i.log(logging.LevelWarn, "Could not rename channel "+rename.Old+" to "+rename.New,
+			logging.F("error", err))
+	}
+
+	i.state.sendJSON("channel_rename", rename)
+}
+
+// tagmsg passes on read receipts for DMs, they are only shown to users
+// that send them as well
+func (i *ircHandler) tagmsg(msg *irc.Message) {
+	msgid, ok := msg.ReadReceipt()
+	if !ok || len(msg.Params) == 0 || isChannel(msg.Params[0]) || i.client.Is(msg.Sender) {
+		return
+	}
+
+	if i.state.user.GetClientSettings().ReadReceipts {
+		i.state.sendJSON("read_receipt", ReadReceipt{
+			Server: i.client.Host(),
+			From:   msg.Sender,
+			MsgID:  msgid,
+		})
+	}
+}
+
 func (i *ircHandler) error(msg *irc.Message) {
+	if reason, ok := irc.ParseClosingLink(msg); ok {
+		// Some servers send no KILL, only the ERROR that closes the link
+		if by, why, ok := parseKillReason(reason); ok && !i.killed {
+			i.sendKilled(by, why)
+		}
+		i.killed = false
+
+		i.state.sendJSON("link_closed", LinkClosed{
+			Server: i.client.Host(),
+			Reason: reason,
+		})
+		return
+	}
+
 	i.state.sendJSON("error", IRCError{
 		Server:  i.client.Host(),
 		Message: msg.LastParam(),
 	})
 }
 
+func (i *ircHandler) youreOper(msg *irc.Message) {
+	i.state.sendJSON("oper", OperReply{
+		Server:  i.client.Host(),
+		Success: true,
+		Message: msg.LastParam(),
+		Modes:   i.client.UserModes(),
+	})
+}
+
+func (i *ircHandler) operFailed(msg *irc.Message) {
+	// A wrong server password gets the same reply during registration
+	if msg.Command == irc.ERR_PASSWDMISMATCH && !i.client.Registered() {
+		return
+	}
+
+	i.state.sendJSON("oper", OperReply{
+		Server:  i.client.Host(),
+		Message: msg.LastParam(),
+		Modes:   i.client.UserModes(),
+	})
+}
+
+func (i *ircHandler) accountRegistration(msg *irc.Message) {
+	if len(msg.Params) < 3 {
+		return
+	}
+
+	i.state.sendJSON("account_registration", AccountRegistrationReply{
+		Server:  i.client.Host(),
+		Command: msg.Command,
+		Status:  strings.ToLower(msg.Params[0]),
+		Account: msg.Params[1],
+		Message: msg.LastParam(),
+	})
+}
+
+func (i *ircHandler) standardReply(msg *irc.Message) {
+	if len(msg.Params) < 3 {
+		return
+	}
+
+	// FAIL RENAME <code> <old channel> <new channel> :<description>
+	if msg.Command == irc.FAIL && msg.Params[0] == irc.RENAME {
+		err := IRCError{
+			Server:  i.client.Host(),
+			Message: msg.LastParam(),
+		}
+		if len(msg.Params) > 3 {
+			err.Target = msg.Params[2]
+		}
+		i.state.sendJSON("error", err)
+		return
+	}
+
+	if msg.Command == irc.FAIL && (msg.Params[0] == irc.REGISTER || msg.Params[0] == irc.VERIFY) {
+		reply := AccountRegistrationReply{
+			Server:  i.client.Host(),
+			Command: msg.Params[0],
+			Status:  "failed",
+			Code:    msg.Params[1],
+			Message: msg.LastParam(),
+		}
+		if len(msg.Params) > 3 {
+			reply.Account = msg.Params[2]
+		}
+		i.state.sendJSON("account_registration", reply)
+		return
+	}
+
+	i.state.sendJSON("standard_reply", StandardReply{
+		Server:   i.client.Host(),
+		Severity: strings.ToLower(msg.Command),
+		Command:  msg.Params[0],
+		Code:     msg.Params[1],
+		Context:  msg.Params[2 : len(msg.Params)-1],
+		Message:  msg.LastParam(),
+	})
+}
+
 func (i *ircHandler) receiveDCCSend(pack *irc.DCCSend, msg *irc.Message) {
 	cfg := i.state.srv.Config()
 
 	if cfg.DCC.Enabled {
 		if cfg.DCC.Autoget.Enabled {
-			file, err := os.OpenFile(storage.Path.DownloadedFile(i.state.user.Username, pack.File), os.O_CREATE|os.O_WRONLY, 0644)
+			if !i.state.acquireDCC() {
+				i.sendDCCInfo("%s: Rejected, already running %d downloads", true, pack.File, cfg.DCC.MaxDownloads)
+				return
+			}
+			defer i.state.releaseDCC()
+
+			path := storage.Path.DownloadedFile(i.state.user.Username, pack.File)
+			file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0644)
 			if err != nil {
 				return
 			}
 			defer file.Close()
 
-			irc.DownloadDCC(file, pack, i.dccProgress)
+			transfer, ctx := i.state.startDCCTransfer(i.client.Host(), msg.Sender, pack)
+			err = downloadDCC(ctx, file, cfg.DCC, i.client, msg.Sender, pack, i.dccProgress, i.state.srv.dccLimiter)
+			deletePartial := i.state.endDCCTransfer(transfer)
+
+			if errors.Is(err, context.Canceled) {
+				file.Close()
+				if !deletePartial {
+					path = ""
+				}
+				i.sendDCCInfo("%s: Download cancelled", true, pack.File)
+				i.state.sendDCCCancelled(i.client.Host(), pack.File, path)
+			} else if err != nil {
+				i.sendDCCError(pack, err)
+			}
 		} else {
-			i.state.setPendingDCC(pack.File, pack)
+			i.state.setPendingDCC(pack.File, &pendingDCC{
+				pack:   pack,
+				client: i.client,
+				from:   msg.Sender,
+			})
 
 			i.state.sendJSON("dcc_send", DCCSend{
 				Server:   i.client.Host(),
 				From:     msg.Sender,
 				Filename: pack.File,
-				URL: fmt.Sprintf("%s://%s/downloads/%s/%s",
-					i.state.String("scheme"), i.state.String("host"), i.state.user.Username, pack.File),
+				URL:      i.state.downloadURL(pack.File),
 			})
 
 			time.Sleep(150 * time.Second)
@@ -426,17 +1124,146 @@ func (i *ircHandler) receiveDCCSend(pack *irc.DCCSend, msg *irc.Message) {
 	}
 }
 
+func (i *ircHandler) sendDCCError(pack *irc.DCCSend, err error) {
+	logging.Default().Error("DCC", err.Error(),
+		logging.F("user", i.state.user.ID),
+		logging.F("file", pack.File))
+
+	i.state.sendJSON("error", Error{
+		Server:  i.client.Host(),
+		Message: fmt.Sprintf("DCC %s failed: %s", pack.File, err),
+	})
+}
+
+// requestChatHistory asks the server for the messages sent to target since
+// the last locally logged one, on servers without chathistory support the
+// local log is all there is
+func (i *ircHandler) requestChatHistory(target string) {
+	if !i.client.SupportsChatHistory() {
+		return
+	}
+
+	var since time.Time
+	messages, _, err := i.state.user.GetLastMessages(i.client.Host(), target, 1)
+	if err == nil && len(messages) > 0 {
+		since = time.Unix(messages[0].Time, 0)
+	}
+
+	i.client.ChatHistoryLatest(target, since, chatHistoryLimit)
+}
+
+func (i *ircHandler) batch(msg *irc.Message) {
+	batch := irc.GetBatch(msg)
+	if batch == nil || len(batch.Params) == 0 ||
+		(batch.Type != "chathistory" && batch.Type != "draft/chathistory") {
+		return
+	}
+
+	host := i.client.Host()
+	target := batch.Params[0]
+
+	local, _, err := i.state.user.GetLastMessages(host, target, len(batch.Messages)+chatHistoryLimit)
+	if err != nil {
+		local = nil
+	}
+
+	seen := map[string]bool{}
+	for _, m := range local {
+		seen[chatHistoryKey(m)] = true
+	}
+
+	messages := []*storage.Message{}
+	for _, m := range batch.Messages {
+		if m.Command != irc.PRIVMSG && m.Command != irc.NOTICE {
+			continue
+		}
+		if ctcp := m.ToCTCP(); ctcp != nil && ctcp.Command != "ACTION" {
+			continue
+		}
+
+		t := m.Time()
+		message := storage.Message{
+			ID:      storage.MessageIDAt(t),
+			Server:  host,
+			From:    m.Sender,
+			Account: m.Tags["account"],
+			To:      target,
+			Content: storage.LimitMessage(m.LastParam()),
+			Time:    t.Unix(),
+			MsgID:   m.Tags["msgid"],
+		}
+		message.Mention = i.isMention(m.Sender, message.Content)
+
+		key := chatHistoryKey(message)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		messages = append(messages, &message)
+	}
+
+	if len(messages) == 0 {
+		return
+	}
+
+	err = i.state.user.LogMessages(messages)
+	if err != nil {
+		i.log(logging.LevelError, err.Error())
+		return
+	}
+
+	res := Messages{
+		Server:   host,
+		To:       target,
+		Messages: make([]storage.Message, len(messages)),
+	}
+	for idx, m := range messages {
+		res.Messages[idx] = *m
+	}
+
+	// Older than everything logged locally means this was requested
+	// when scrolling back
+	if len(local) > 0 && messages[len(messages)-1].Time < local[0].Time {
+		res.Prepend = true
+		if len(batch.Messages) >= chatHistoryLimit {
+			res.Next = messages[0].ID
+		}
+	}
+
+	i.state.sendJSON("messages", res)
+}
+
+// chatHistoryKey identifies a message by its msgid, or by its content
+// when it was logged without one
+func chatHistoryKey(m storage.Message) string {
+	if m.MsgID != "" {
+		return m.MsgID
+	}
+	return fmt.Sprint(m.Time, m.From, m.Content)
+}
+
 func (i *ircHandler) initHandlers() {
 	i.handlers = map[string]func(*irc.Message){
 		irc.NICK:                 i.nick,
 		irc.JOIN:                 i.join,
 		irc.PART:                 i.part,
+		irc.KICK:                 i.kick,
 		irc.MODE:                 i.mode,
 		irc.PRIVMSG:              i.message,
 		irc.NOTICE:               i.message,
 		irc.QUIT:                 i.quit,
+		irc.KILL:                 i.kill,
 		irc.TOPIC:                i.topic,
 		irc.ERROR:                i.error,
+		irc.REGISTER:             i.accountRegistration,
+		irc.VERIFY:               i.accountRegistration,
+		irc.FAIL:                 i.standardReply,
+		irc.WARN:                 i.standardReply,
+		irc.NOTE:                 i.standardReply,
+		irc.BATCH:                i.batch,
+		irc.AWAY:                 i.away,
+		irc.RPL_AWAY:             i.away,
 		irc.RPL_WELCOME:          i.info,
 		irc.RPL_YOURHOST:         i.info,
 		irc.RPL_CREATED:          i.info,
@@ -449,22 +1276,78 @@ func (i *ircHandler) initHandlers() {
 		irc.RPL_WHOISUSER:        i.whoisUser,
 		irc.RPL_WHOISSERVER:      i.whoisServer,
 		irc.RPL_WHOISCHANNELS:    i.whoisChannels,
+		irc.RPL_WHOISOPERATOR:    i.whoisOperator,
+		irc.RPL_WHOISSPECIAL:     i.whoisSpecial,
+		irc.RPL_WHOISBOT:         i.whoisBot,
+		"336":                    i.whoisBot,
+		irc.RPL_WHOISHOST:        i.whoisHost,
 		irc.RPL_ENDOFWHOIS:       i.whoisEnd,
 		irc.RPL_NOTOPIC:          i.noTopic,
 		irc.RPL_TOPIC:            i.topic,
 		irc.RPL_ENDOFNAMES:       i.namesEnd,
+		irc.RPL_ENDOFWHO:         i.whoEnd,
 		irc.RPL_MOTDSTART:        i.motdStart,
 		irc.RPL_MOTD:             i.motd,
 		irc.RPL_ENDOFMOTD:        i.motdEnd,
+		irc.ERR_NOMOTD:           i.noMOTD,
+		irc.RPL_YOUREOPER:        i.youreOper,
+		irc.ERR_PASSWDMISMATCH:   i.operFailed,
+		irc.ERR_NOOPERHOST:       i.operFailed,
+		irc.RPL_HELPSTART:        i.helpStart,
+		irc.RPL_HELPTXT:          i.help,
+		irc.RPL_ENDOFHELP:        i.helpEnd,
+		irc.ERR_HELPNOTFOUND:     i.helpNotFound,
+		irc.RPL_LISTSTART:        i.listStart,
 		irc.RPL_LIST:             i.list,
 		irc.RPL_LISTEND:          i.listEnd,
 		irc.ERR_ERRONEUSNICKNAME: i.badNick,
 		irc.ERR_FORWARD:          i.forward,
+		irc.RPL_SASLMECHS:        i.saslMechs,
+		irc.RENAME:               i.rename,
+		irc.METADATA:             i.metadata,
+		irc.RPL_KEYVALUE:         i.metadata,
+		irc.RPL_KEYNOTSET:        i.metadata,
+		irc.TAGMSG:               i.tagmsg,
 	}
 }
 
-func (i *ircHandler) log(v ...interface{}) {
-	log.Println("[IRC]", i.state.user.ID, i.client.Host(), fmt.Sprint(v...))
+// log writes an entry for this user and server, fields get written
+// after them
+func (i *ircHandler) log(level logging.Level, msg string, fields ...logging.Field) {
+	fields = append([]logging.Field{
+		logging.F("user", i.state.user.ID),
+		logging.F("server", i.client.Host()),
+	}, fields...)
+	logging.Default().Log(level, "IRC", msg, fields...)
+}
+
+// sendTLSInfo tells the user about the certificate of the server,
+// with a warning if it is about to expire
+func (i *ircHandler) sendTLSInfo() {
+	info := newTLSInfo(i.client.Host(), i.client.PeerCertificate())
+	if info == nil {
+		return
+	}
+
+	i.state.sendJSON("tls_info", *info)
+
+	if server, err := i.state.user.GetServer(info.Server); err == nil &&
+		matchPin(server.PinnedCerts, info.Fingerprint) > 0 {
+		i.log(logging.LevelWarn, "Connected with a rotation pin", logging.F("fingerprint", info.Fingerprint))
+		i.state.sendJSON("error", Error{
+			Server:  info.Server,
+			Message: "The certificate of this server matches a rotation pin, it should be made the current pin",
+		})
+	}
+
+	if info.ExpiresSoon {
+		expires := time.Unix(info.NotAfter, 0).UTC()
+		i.log(logging.LevelWarn, "Certificate expires soon", logging.F("expires", expires))
+		i.state.sendJSON("error", Error{
+			Server:  info.Server,
+			Message: "The certificate of this server expires " + expires.Format("2006-01-02 15:04 UTC"),
+		})
+	}
 }
 
 func (i *ircHandler) sendDCCInfo(message string, log bool, a ...interface{}) {
@@ -485,6 +1368,13 @@ func (i *ircHandler) sendDCCInfo(message string, log bool, a ...interface{}) {
 	}
 }
 
+// isMention reports whether content sent by sender mentioned the current
+// nick, it is stored with the message so changing nick later does not
+// change which messages count as mentions
+func (i *ircHandler) isMention(sender, content string) bool {
+	return !i.client.Is(sender) && storage.IsMention(content, i.client.GetNick())
+}
+
 func isChannel(s string) bool {
 	return strings.IndexAny(s, "&#+!") == 0
 }
@@ -511,6 +1401,9 @@ func formatIRCError(msg *irc.Message) string {
 	return errMsg
 }
 
-func printMessage(msg *irc.Message, i *irc.Client) {
-	log.Println(i.GetNick()+":", msg.Sender, msg.Command, msg.Params)
+func (i *ircHandler) printMessage(msg *irc.Message) {
+	i.log(logging.LevelTrace, strings.Join(msg.Params, " "),
+		logging.F("nick", i.client.GetNick()),
+		logging.F("sender", msg.Sender),
+		logging.F("command", msg.Command))
 }
@@ -1,19 +1,32 @@
 package server
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"log"
+	"net"
 	"os"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/khlieng/dispatch/config"
 	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/khlieng/dispatch/pkg/logging"
 	"github.com/khlieng/dispatch/storage"
 	"github.com/khlieng/dispatch/storage/bleve"
 	"github.com/khlieng/dispatch/storage/boltdb"
+	"github.com/kjk/betterguid"
 	"github.com/stretchr/testify/assert"
 )
 
-var user *storage.User
+var (
+	user  *storage.User
+	store storage.Store
+)
 
 func TestMain(m *testing.M) {
 	tempdir, err := ioutil.TempDir("", "test_")
@@ -28,6 +41,8 @@ func TestMain(m *testing.M) {
 		log.Fatal(err)
 	}
 
+	store = db
+
 	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
 		return db, nil
 	}
@@ -136,6 +151,7 @@ func TestHandleIRCMessage(t *testing.T) {
 	assert.Equal(t, "nick", msg.From)
 	assert.Equal(t, "#chan", msg.To)
 	assert.Equal(t, "the message", msg.Content)
+	assert.Empty(t, msg.Account)
 
 	res = dispatchMessage(&irc.Message{
 		Command: irc.PRIVMSG,
@@ -149,6 +165,105 @@ func TestHandleIRCMessage(t *testing.T) {
 	assert.Equal(t, "someone", msg.From)
 	assert.Empty(t, msg.To)
 	assert.Equal(t, "the message", msg.Content)
+
+	res = dispatchMessage(&irc.Message{
+		Tags:    map[string]string{"account": "someacc"},
+		Command: irc.PRIVMSG,
+		Sender:  "someone",
+		Params:  []string{"#chan", "the message"},
+	})
+
+	msg, ok = res.Data.(Message)
+	assert.True(t, ok)
+	assert.Equal(t, "someacc", msg.Account)
+
+	c := irc.NewClient(&irc.Config{
+		Nick: "nick",
+		Host: "host.com",
+	})
+	c.Features.Parse([]string{"nick", "STATUSMSG=@+", "are supported"})
+	s := NewState(user, nil)
+	newIRCHandler(c, s).dispatchMessage(&irc.Message{
+		Command: irc.PRIVMSG,
+		Sender:  "someone",
+		Params:  []string{"@#chan", "ops only"},
+	})
+
+	res = <-s.broadcast
+	assert.Equal(t, "message", res.Type)
+	msg, ok = res.Data.(Message)
+	assert.True(t, ok)
+	assert.Equal(t, "#chan", msg.To)
+	assert.Equal(t, "@", msg.StatusMsg)
+}
+
+func TestHandleIRCMessageMention(t *testing.T) {
+	c := irc.NewClient(&irc.Config{
+		Nick: "nick",
+		Host: "host.com",
+	})
+	i := newIRCHandler(c, NewState(user, nil))
+
+	assert.True(t, i.isMention("someone", "nick: hello"))
+	assert.False(t, i.isMention("someone", "nickname"))
+	// Our own messages never count
+	assert.False(t, i.isMention("nick", "nick: hello"))
+}
+
+func TestHandleIRCMessageReply(t *testing.T) {
+	res := dispatchMessage(&irc.Message{
+		Tags: map[string]string{
+			"msgid":        "child",
+			"+draft/reply": "unknown-parent",
+		},
+		Command: irc.PRIVMSG,
+		Sender:  "nick",
+		Params:  []string{"#chan", "the reply"},
+	})
+
+	msg, ok := res.Data.(Message)
+	assert.True(t, ok)
+	assert.Equal(t, "child", msg.MsgID)
+	assert.Equal(t, "unknown-parent", msg.ReplyTo)
+
+	res = dispatchMessage(&irc.Message{
+		Tags:    map[string]string{"+reply": "parent"},
+		Command: irc.PRIVMSG,
+		Sender:  "nick",
+		Params:  []string{"#chan", "the reply"},
+	})
+
+	msg, ok = res.Data.(Message)
+	assert.True(t, ok)
+	assert.Equal(t, "parent", msg.ReplyTo)
+}
+
+func TestHandleIRCMessageClientTags(t *testing.T) {
+	c := irc.NewClient(&irc.Config{
+		Nick: "nick",
+		Host: "host.com",
+	})
+	s := NewState(user, nil)
+	i := newIRCHandler(c, s)
+	msg := &irc.Message{
+		Tags: map[string]string{
+			"+vendor/bot": "1",
+			"+other":      "x",
+			"msgid":       "abc",
+		},
+		Command: irc.PRIVMSG,
+		Sender:  "bot",
+		Params:  []string{"#chan", "beep"},
+	}
+
+	i.dispatchMessage(msg)
+	res := <-s.broadcast
+	assert.Nil(t, res.Data.(Message).Tags)
+
+	s.setForwardTags("host.com", []string{"+vendor/*"})
+	i.dispatchMessage(msg)
+	res = <-s.broadcast
+	assert.Equal(t, map[string]string{"+vendor/bot": "1"}, res.Data.(Message).Tags)
 }
 
 func TestHandleIRCQuit(t *testing.T) {
@@ -165,6 +280,239 @@ func TestHandleIRCQuit(t *testing.T) {
 	}, res)
 }
 
+func TestHandleIRCKill(t *testing.T) {
+	res := dispatchMessage(&irc.Message{
+		Command: irc.KILL,
+		Sender:  "oper",
+		Params:  []string{"other", "spam"},
+	})
+	checkResponse(t, "quit", Quit{
+		Server: "host.com",
+		User:   "other",
+		Reason: "Killed (oper (spam))",
+	}, res)
+}
+
+func TestHandleIRCSelfKill(t *testing.T) {
+	port, _ := stubIRCServer(t, ":srv 001 nick :Welcome\r\n"+
+		":oper!o@host KILL nick :go away\r\n"+
+		"ERROR :Closing link: (nick@host) [Killed (oper (go away))]\r\n")
+
+	c := irc.NewClient(&irc.Config{
+		Host:         "127.0.0.1",
+		Port:         port,
+		Nick:         "nick",
+		KillCooldown: time.Minute,
+	})
+	s := NewState(user, nil)
+	h := newIRCHandler(c, s)
+	c.Connect()
+	defer c.Quit()
+
+	var killed *Killed
+	var linkClosed bool
+	timeout := time.After(time.Second)
+	for killed == nil || !linkClosed {
+		select {
+		case msg := <-c.Messages:
+			h.dispatchMessage(msg)
+		case res := <-s.broadcast:
+			switch res.Type {
+			case "killed":
+				k := res.Data.(Killed)
+				killed = &k
+			case "link_closed":
+				linkClosed = true
+			}
+		case <-timeout:
+			t.Fatal("Missing killed and link_closed events")
+		}
+	}
+
+	assert.Equal(t, &Killed{
+		Server:   "127.0.0.1",
+		By:       "oper",
+		Reason:   "go away",
+		Cooldown: 60,
+	}, killed)
+	assert.True(t, c.Killed())
+}
+
+func TestHandleIRCSelfKillError(t *testing.T) {
+	// Some servers send no KILL, only the ERROR closing the link
+	port, _ := stubIRCServer(t, ":srv 001 nick :Welcome\r\n"+
+		"ERROR :Closing link: (nick@host) [Killed (oper (go away))]\r\n")
+
+	c := irc.NewClient(&irc.Config{
+		Host:         "127.0.0.1",
+		Port:         port,
+		Nick:         "nick",
+		KillCooldown: time.Minute,
+	})
+	s := NewState(user, nil)
+	h := newIRCHandler(c, s)
+	c.Connect()
+	defer c.Quit()
+
+	var killed []Killed
+	var linkClosed bool
+	timeout := time.After(time.Second)
+	for len(killed) == 0 || !linkClosed {
+		select {
+		case msg := <-c.Messages:
+			h.dispatchMessage(msg)
+		case res := <-s.broadcast:
+			switch res.Type {
+			case "killed":
+				killed = append(killed, res.Data.(Killed))
+			case "link_closed":
+				linkClosed = true
+			}
+		case <-timeout:
+			t.Fatal("Missing killed and link_closed events")
+		}
+	}
+
+	assert.Equal(t, []Killed{{
+		Server:   "127.0.0.1",
+		By:       "oper",
+		Reason:   "go away",
+		Cooldown: 60,
+	}}, killed)
+	assert.True(t, c.Killed())
+}
+
+func TestParseKillReason(t *testing.T) {
+	for _, tc := range []struct {
+		reason string
+		by     string
+		why    string
+		ok     bool
+	}{
+		{"Killed (oper (go away))", "oper", "go away", true},
+		{"Killed (oper (spam (again)))", "oper", "spam (again)", true},
+		{"Killed (oper)", "oper", "", true},
+		{"K-Lined", "", "", false},
+	} {
+		by, why, ok := parseKillReason(tc.reason)
+		assert.Equal(t, tc.by, by, tc.reason)
+		assert.Equal(t, tc.why, why, tc.reason)
+		assert.Equal(t, tc.ok, ok, tc.reason)
+	}
+}
+
+func TestHandleIRCAccountRegistration(t *testing.T) {
+	res := dispatchMessage(&irc.Message{
+		Command: irc.REGISTER,
+		Sender:  "host.com",
+		Params:  []string{"SUCCESS", "acc", "Account created"},
+	})
+	checkResponse(t, "account_registration", AccountRegistrationReply{
+		Server:  "host.com",
+		Command: irc.REGISTER,
+		Status:  "success",
+		Account: "acc",
+		Message: "Account created",
+	}, res)
+
+	res = dispatchMessage(&irc.Message{
+		Command: irc.REGISTER,
+		Sender:  "host.com",
+		Params:  []string{"VERIFICATION_REQUIRED", "acc", "Check your email"},
+	})
+	checkResponse(t, "account_registration", AccountRegistrationReply{
+		Server:  "host.com",
+		Command: irc.REGISTER,
+		Status:  "verification_required",
+		Account: "acc",
+		Message: "Check your email",
+	}, res)
+
+	res = dispatchMessage(&irc.Message{
+		Command: irc.VERIFY,
+		Sender:  "host.com",
+		Params:  []string{"SUCCESS", "acc", "Account verified"},
+	})
+	checkResponse(t, "account_registration", AccountRegistrationReply{
+		Server:  "host.com",
+		Command: irc.VERIFY,
+		Status:  "success",
+		Account: "acc",
+		Message: "Account verified",
+	}, res)
+
+	res = dispatchMessage(&irc.Message{
+		Command: irc.FAIL,
+		Sender:  "host.com",
+		Params:  []string{"REGISTER", "ACCOUNT_EXISTS", "acc", "Account already exists"},
+	})
+	checkResponse(t, "account_registration", AccountRegistrationReply{
+		Server:  "host.com",
+		Command: irc.REGISTER,
+		Status:  "failed",
+		Account: "acc",
+		Code:    "ACCOUNT_EXISTS",
+		Message: "Account already exists",
+	}, res)
+
+	res = dispatchMessage(&irc.Message{
+		Command: irc.FAIL,
+		Sender:  "host.com",
+		Params:  []string{"VERIFY", "INVALID_CODE", "Invalid verification code"},
+	})
+	checkResponse(t, "account_registration", AccountRegistrationReply{
+		Server:  "host.com",
+		Command: irc.VERIFY,
+		Status:  "failed",
+		Code:    "INVALID_CODE",
+		Message: "Invalid verification code",
+	}, res)
+}
+
+func TestHandleIRCStandardReply(t *testing.T) {
+	res := dispatchMessage(&irc.Message{
+		Command: irc.FAIL,
+		Sender:  "host.com",
+		Params:  []string{"CHATHISTORY", "INVALID_TARGET", "LATEST", "#chan", "Messages could not be retrieved"},
+	})
+	checkResponse(t, "standard_reply", StandardReply{
+		Server:   "host.com",
+		Severity: "fail",
+		Command:  "CHATHISTORY",
+		Code:     "INVALID_TARGET",
+		Context:  []string{"LATEST", "#chan"},
+		Message:  "Messages could not be retrieved",
+	}, res)
+
+	res = dispatchMessage(&irc.Message{
+		Command: irc.WARN,
+		Sender:  "host.com",
+		Params:  []string{"REHASH", "CERTS_EXPIRED", "Certificate has expired"},
+	})
+	checkResponse(t, "standard_reply", StandardReply{
+		Server:   "host.com",
+		Severity: "warn",
+		Command:  "REHASH",
+		Code:     "CERTS_EXPIRED",
+		Context:  []string{},
+		Message:  "Certificate has expired",
+	}, res)
+
+	res = dispatchMessage(&irc.Message{
+		Command: irc.NOTE,
+		Sender:  "host.com",
+		Params:  []string{"*", "OPER_MESSAGE", "The message"},
+	})
+	checkResponse(t, "standard_reply", StandardReply{
+		Server:   "host.com",
+		Severity: "note",
+		Command:  "*",
+		Code:     "OPER_MESSAGE",
+		Context:  []string{},
+		Message:  "The message",
+	}, res)
+}
+
 func TestHandleIRCWelcome(t *testing.T) {
 	res := dispatchMessageMulti(&irc.Message{
 		Command: irc.RPL_WELCOME,
@@ -199,13 +547,16 @@ func TestHandleIRCWhois(t *testing.T) {
 	})
 	i.dispatchMessage(&irc.Message{
 		Command: irc.RPL_WHOISSERVER,
-		Params:  []string{"", "", "srv.com"},
+		Params:  []string{"", "nick", "srv.com"},
 	})
 	i.dispatchMessage(&irc.Message{
 		Command: irc.RPL_WHOISCHANNELS,
-		Params:  []string{"#chan #chan1"},
+		Params:  []string{"", "nick", "#chan #chan1"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_ENDOFWHOIS,
+		Params:  []string{"", "nick", "End of /WHOIS list"},
 	})
-	i.dispatchMessage(&irc.Message{Command: irc.RPL_ENDOFWHOIS})
 
 	checkResponse(t, "whois", WhoisReply{
 		Nick:     "nick",
@@ -217,6 +568,179 @@ func TestHandleIRCWhois(t *testing.T) {
 	}, <-s.broadcast)
 }
 
+func TestHandleIRCWhoisInterleaved(t *testing.T) {
+	c := irc.NewClient(&irc.Config{
+		Nick:     "nick",
+		Username: "user",
+		Host:     "host.com",
+	})
+	s := NewState(nil, nil)
+	i := newIRCHandler(c, s)
+
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_WHOISUSER,
+		Params:  []string{"nick", "alice", "a", "alice.host", "*", "Alice"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_WHOISUSER,
+		Params:  []string{"nick", "bob", "b", "bob.host", "*", "Bob"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_WHOISCHANNELS,
+		Params:  []string{"nick", "Alice", "#a"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_WHOISCHANNELS,
+		Params:  []string{"nick", "bob", "#b"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_ENDOFWHOIS,
+		Params:  []string{"nick", "bob", "End of /WHOIS list"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_WHOISSERVER,
+		Params:  []string{"nick", "alice", "srv.com", "info"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_ENDOFWHOIS,
+		Params:  []string{"nick", "alice", "End of /WHOIS list"},
+	})
+
+	checkResponse(t, "whois", WhoisReply{
+		Nick:     "bob",
+		Username: "b",
+		Host:     "bob.host",
+		Realname: "Bob",
+		Channels: []string{"#b"},
+	}, <-s.broadcast)
+	checkResponse(t, "whois", WhoisReply{
+		Nick:     "alice",
+		Username: "a",
+		Host:     "alice.host",
+		Realname: "Alice",
+		Server:   "srv.com",
+		Channels: []string{"#a"},
+	}, <-s.broadcast)
+	assert.Len(t, i.whois, 0)
+}
+
+func TestHandleIRCJoinChannelLimit(t *testing.T) {
+	user.AddChannel(&storage.Channel{Server: "host.com", Name: "#existing"})
+	channels, err := user.GetChannels()
+	assert.Nil(t, err)
+
+	defer func(getLimits func(*storage.User) storage.Limits) {
+		storage.GetLimits = getLimits
+	}(storage.GetLimits)
+	storage.GetLimits = func(_ *storage.User) storage.Limits {
+		return storage.Limits{Channels: len(channels)}
+	}
+
+	c := irc.NewClient(&irc.Config{
+		Nick:     "nick",
+		Username: "user",
+		Host:     "host.com",
+	})
+	s := NewState(user, nil)
+	newIRCHandler(c, s).dispatchMessage(&irc.Message{
+		Command: irc.JOIN,
+		Sender:  "nick",
+		Params:  []string{"#limit"},
+	})
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case res := <-s.broadcast:
+			if res.Type == "error" {
+				checkResponse(t, "error", Error{
+					Server:  "host.com",
+					Message: storage.ErrChannelLimit.Error(),
+				}, res)
+				return
+			}
+
+		case <-timeout:
+			t.Fatal("Timed out waiting for the channel limit error")
+		}
+	}
+}
+
+func TestHandleIRCWhoisExtra(t *testing.T) {
+	c := irc.NewClient(&irc.Config{
+		Nick:     "nick",
+		Username: "user",
+		Host:     "host.com",
+	})
+	s := NewState(nil, nil)
+	i := newIRCHandler(c, s)
+
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_WHOISUSER,
+		Params:  []string{"", "bot", "user", "host", "", "realname"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_WHOISOPERATOR,
+		Params:  []string{"nick", "bot", "is an IRC Operator"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_WHOISSPECIAL,
+		Params:  []string{"nick", "bot", "is a Network Service"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_WHOISSPECIAL,
+		Params:  []string{"nick", "bot", "is available for help"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_WHOISBOT,
+		Params:  []string{"nick", "Bot", "is a Bot on the network"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_WHOISHOST,
+		Params:  []string{"nick", "bot", "is connecting from *@real.host.com 192.0.2.10"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: "379",
+		Params:  []string{"nick", "bot", "is using modes +iw"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_ENDOFWHOIS,
+		Params:  []string{"nick", "bot", "End of /WHOIS list"},
+	})
+
+	checkResponse(t, "whois", WhoisReply{
+		Nick:       "bot",
+		Username:   "user",
+		Host:       "host",
+		Realname:   "realname",
+		Operator:   "is an IRC Operator",
+		Bot:        true,
+		Special:    []string{"is a Network Service", "is available for help"},
+		ActualHost: "real.host.com",
+		ActualIP:   "192.0.2.10",
+	}, <-s.broadcast)
+
+	i.dispatchMessage(&irc.Message{
+		Command: "336",
+		Params:  []string{"nick", "#chan", "End of invite list"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_WHOISUSER,
+		Params:  []string{"", "someone", "user", "host", "", "realname"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: "336",
+		Params:  []string{"nick", "someone", "is a bot"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_ENDOFWHOIS,
+		Params:  []string{"nick", "someone", "End of /WHOIS list"},
+	})
+
+	res := <-s.broadcast
+	assert.True(t, res.Data.(WhoisReply).Bot)
+}
+
 func TestHandleIRCTopic(t *testing.T) {
 	res := dispatchMessage(&irc.Message{
 		Command: irc.RPL_TOPIC,
@@ -261,7 +785,7 @@ func TestHandleIRCMotd(t *testing.T) {
 		Username: "user",
 		Host:     "host.com",
 	})
-	s := NewState(nil, nil)
+	s := NewState(user, nil)
 	i := newIRCHandler(c, s)
 
 	i.dispatchMessage(&irc.Message{
@@ -283,6 +807,312 @@ func TestHandleIRCMotd(t *testing.T) {
 		Title:   "motd title",
 		Content: []string{"line 1", "line 2"},
 	}, <-s.broadcast)
+
+	// An interrupted MOTD does not leak into the next one
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_MOTD,
+		Params:  []string{"stale"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_MOTDSTART,
+		Params:  []string{"motd title"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_MOTD,
+		Params:  []string{"line 1"},
+	})
+	i.dispatchMessage(&irc.Message{Command: irc.RPL_ENDOFMOTD})
+
+	checkResponse(t, "motd", MOTD{
+		Server:  "host.com",
+		Title:   "motd title",
+		Content: []string{"line 1"},
+	}, <-s.broadcast)
+}
+
+func TestHandleIRCNoMotd(t *testing.T) {
+	c := irc.NewClient(&irc.Config{
+		Nick:     "nick",
+		Username: "user",
+		Host:     "host.com",
+	})
+	s := NewState(user, nil)
+	i := newIRCHandler(c, s)
+
+	i.dispatchMessage(&irc.Message{
+		Command: irc.ERR_NOMOTD,
+		Params:  []string{"nick", "MOTD File is missing"},
+	})
+
+	checkResponse(t, "motd", MOTD{
+		Server:  "host.com",
+		Content: []string{"MOTD File is missing"},
+		Missing: true,
+	}, <-s.broadcast)
+	assert.Len(t, s.broadcast, 0)
+}
+
+func TestHandleIRCMotdChanged(t *testing.T) {
+	u, err := storage.NewUser(store)
+	assert.Nil(t, err)
+	assert.Nil(t, u.AddServer(&storage.Server{Host: "host.com"}))
+	settings := u.GetClientSettings()
+	settings.ShowMOTD = storage.MOTDChanged
+	assert.Nil(t, u.SetClientSettings(settings))
+
+	c := irc.NewClient(&irc.Config{
+		Nick:     "nick",
+		Username: "user",
+		Host:     "host.com",
+	})
+	s := NewState(u, nil)
+	i := newIRCHandler(c, s)
+
+	motd := func(lines ...string) {
+		i.dispatchMessage(&irc.Message{
+			Command: irc.RPL_MOTDSTART,
+			Params:  []string{"motd title"},
+		})
+		for _, line := range lines {
+			i.dispatchMessage(&irc.Message{
+				Command: irc.RPL_MOTD,
+				Params:  []string{line},
+			})
+		}
+		i.dispatchMessage(&irc.Message{Command: irc.RPL_ENDOFMOTD})
+	}
+	expected := func(lines ...string) MOTD {
+		return MOTD{
+			Server:  "host.com",
+			Title:   "motd title",
+			Content: lines,
+		}
+	}
+
+	motd("line 1")
+	checkResponse(t, "motd", expected("line 1"), <-s.broadcast)
+	server, err := u.GetServer("host.com")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, server.MOTDHash)
+
+	// Unchanged after reconnecting
+	motd("line 1")
+	assert.Len(t, s.broadcast, 0)
+
+	motd("line 1", "line 2")
+	checkResponse(t, "motd", expected("line 1", "line 2"), <-s.broadcast)
+
+	// Asked for by the user
+	s.Set("request_motd_host.com", true)
+	motd("line 1", "line 2")
+	checkResponse(t, "motd", expected("line 1", "line 2"), <-s.broadcast)
+	motd("line 1", "line 2")
+	assert.Len(t, s.broadcast, 0)
+
+	settings.ShowMOTD = storage.MOTDNever
+	assert.Nil(t, u.SetClientSettings(settings))
+	motd("line 3")
+	assert.Len(t, s.broadcast, 0)
+	s.Set("request_motd_host.com", true)
+	motd("line 3")
+	checkResponse(t, "motd", expected("line 3"), <-s.broadcast)
+
+	settings.ShowMOTD = storage.MOTDAlways
+	assert.Nil(t, u.SetClientSettings(settings))
+	motd("line 3")
+	checkResponse(t, "motd", expected("line 3"), <-s.broadcast)
+
+	settings.ShowMOTD = "sometimes"
+	assert.Equal(t, storage.ErrInvalidShowMOTD, u.SetClientSettings(settings))
+}
+
+func TestHandleIRCHelp(t *testing.T) {
+	c := irc.NewClient(&irc.Config{
+		Nick:     "nick",
+		Username: "user",
+		Host:     "host.com",
+	})
+	s := NewState(nil, nil)
+	i := newIRCHandler(c, s)
+
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_HELPSTART,
+		Params:  []string{"nick", "privmsg", "** Help for PRIVMSG **"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_HELPTXT,
+		Params:  []string{"nick", "privmsg", "PRIVMSG <target> <text>"},
+	})
+	assert.Len(t, s.broadcast, 0)
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_ENDOFHELP,
+		Params:  []string{"nick", "privmsg", "End of /HELP"},
+	})
+
+	checkResponse(t, "help", Help{
+		Server:  "host.com",
+		Topic:   "privmsg",
+		Content: []string{"** Help for PRIVMSG **", "PRIVMSG <target> <text>", "End of /HELP"},
+	}, <-s.broadcast)
+
+	// An interrupted reply does not leak into the next one
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_HELPTXT,
+		Params:  []string{"nick", "join", "stale"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_HELPSTART,
+		Params:  []string{"nick", "*", "Help topics"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_ENDOFHELP,
+		Params:  []string{"nick", "*", "End of /HELP"},
+	})
+
+	checkResponse(t, "help", Help{
+		Server:  "host.com",
+		Topic:   "*",
+		Content: []string{"Help topics", "End of /HELP"},
+	}, <-s.broadcast)
+
+	// Servers that skip the start reply still get their text through
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_HELPTXT,
+		Params:  []string{"nick", "join", "JOIN <channel>"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_ENDOFHELP,
+		Params:  []string{"nick", "join", "End of /HELP"},
+	})
+
+	checkResponse(t, "help", Help{
+		Server:  "host.com",
+		Topic:   "join",
+		Content: []string{"JOIN <channel>", "End of /HELP"},
+	}, <-s.broadcast)
+	assert.Len(t, s.broadcast, 0)
+}
+
+func TestHandleIRCHelpNotFound(t *testing.T) {
+	c := irc.NewClient(&irc.Config{
+		Nick:     "nick",
+		Username: "user",
+		Host:     "host.com",
+	})
+	s := NewState(nil, nil)
+	i := newIRCHandler(c, s)
+
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_HELPTXT,
+		Params:  []string{"nick", "foo", "stale"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.ERR_HELPNOTFOUND,
+		Params:  []string{"nick", "foo", "No help available on this topic"},
+	})
+
+	checkResponse(t, "help", Help{
+		Server:  "host.com",
+		Topic:   "foo",
+		Content: []string{"No help available on this topic"},
+		Missing: true,
+	}, <-s.broadcast)
+	assert.Len(t, s.broadcast, 0)
+	assert.Equal(t, Help{}, i.helpBuffer)
+}
+
+func TestHandleIRCError(t *testing.T) {
+	c := irc.NewClient(&irc.Config{
+		Nick:     "nick",
+		Username: "user",
+		Host:     "host.com",
+	})
+	s := NewState(nil, nil)
+	i := newIRCHandler(c, s)
+
+	i.dispatchMessage(irc.ParseMessage("ERROR :Closing Link: nick[1.2.3.4] (K-Lined)"))
+	checkResponse(t, "link_closed", LinkClosed{
+		Server: "host.com",
+		Reason: "K-Lined",
+	}, <-s.broadcast)
+
+	i.dispatchMessage(irc.ParseMessage("ERROR :Something went wrong"))
+	checkResponse(t, "error", IRCError{
+		Server:  "host.com",
+		Message: "Something went wrong",
+	}, <-s.broadcast)
+
+	assert.Equal(t, ConnectionUpdate{
+		Server:    "host.com",
+		Error:     "Closing link: K-Lined",
+		ErrorType: "link_closed",
+	}, newConnectionUpdate("host.com", irc.ConnectionState{
+		Error: &irc.LinkClosedError{Reason: "K-Lined"},
+	}))
+}
+
+func TestConnectionUpdateServerName(t *testing.T) {
+	update := newConnectionUpdate("host.com", irc.ConnectionState{
+		Error: &irc.ServerNameError{ServerName: "irc.example.com", Err: errors.New("tls: unrecognized name")},
+	})
+	assert.Equal(t, "server_name", update.ErrorType)
+	assert.Equal(t, "TLS handshake failed for server name irc.example.com: tls: unrecognized name", update.Error)
+}
+
+func TestConnectionUpdateTimeout(t *testing.T) {
+	for _, err := range []*irc.TimeoutError{
+		irc.ErrDNSTimeout, irc.ErrConnectTimeout, irc.ErrTLSTimeout, irc.ErrRegistrationTimeout,
+	} {
+		assert.Equal(t, ConnectionUpdate{
+			Server:    "host.com",
+			Error:     err.Error(),
+			ErrorType: err.Phase + "_timeout",
+		}, newConnectionUpdate("host.com", irc.ConnectionState{Error: err}))
+	}
+}
+
+func TestHandleIRCOper(t *testing.T) {
+	c := irc.NewClient(&irc.Config{
+		Nick:     "nick",
+		Username: "user",
+		Host:     "host.com",
+	})
+	s := NewState(nil, nil)
+	i := newIRCHandler(c, s)
+
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_YOUREOPER,
+		Params:  []string{"nick", "You are now an IRC operator"},
+	})
+	checkResponse(t, "oper", OperReply{
+		Server:  "host.com",
+		Success: true,
+		Message: "You are now an IRC operator",
+	}, <-s.broadcast)
+
+	i.dispatchMessage(&irc.Message{
+		Command: irc.ERR_NOOPERHOST,
+		Params:  []string{"nick", "No O-lines for your host"},
+	})
+	checkResponse(t, "error", IRCError{
+		Server:  "host.com",
+		Message: "No O-lines for your host",
+	}, <-s.broadcast)
+	checkResponse(t, "oper", OperReply{
+		Server:  "host.com",
+		Message: "No O-lines for your host",
+	}, <-s.broadcast)
+
+	// Before registration this is about the server password
+	i.dispatchMessage(&irc.Message{
+		Command: irc.ERR_PASSWDMISMATCH,
+		Params:  []string{"nick", "Password incorrect"},
+	})
+	checkResponse(t, "error", IRCError{
+		Server:  "host.com",
+		Message: "Password incorrect",
+	}, <-s.broadcast)
+	assert.Len(t, s.broadcast, 0)
 }
 
 func TestHandleIRCBadNick(t *testing.T) {
@@ -305,3 +1135,525 @@ func TestHandleIRCBadNick(t *testing.T) {
 		Server: "host.com",
 	}, <-s.broadcast)
 }
+
+func TestHandleIRCListProgress(t *testing.T) {
+	c := irc.NewClient(&irc.Config{
+		Nick: "nick",
+		Host: "host.com",
+	})
+	s := NewState(user, nil)
+	s.Set("update_chanlist_host.com", true)
+	i := newIRCHandler(c, s)
+
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_LISTSTART,
+		Params:  []string{"nick", "Channel", "Users Name"},
+	})
+
+	for n := 0; n < channelListProgressInterval; n++ {
+		i.dispatchMessage(&irc.Message{
+			Command: irc.RPL_LIST,
+			Params:  []string{"nick", "#chan" + strconv.Itoa(n), "5", "topic"},
+		})
+	}
+
+	checkResponse(t, "channel_list_progress", ChannelListProgress{
+		Server: "host.com",
+		Count:  channelListProgressInterval,
+	}, <-s.broadcast)
+
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_LIST,
+		Params:  []string{"nick", "#last", "5", "topic"},
+	})
+	i.dispatchMessage(&irc.Message{
+		Command: irc.RPL_LISTEND,
+		Params:  []string{"nick", "End of /LIST"},
+	})
+
+	checkResponse(t, "channel_list_progress", ChannelListProgress{
+		Server: "host.com",
+		Count:  channelListProgressInterval + 1,
+		Done:   true,
+	}, <-s.broadcast)
+	assert.Nil(t, i.listBuffer)
+	assert.False(t, s.Bool("update_chanlist_host.com"))
+}
+
+func TestHandleIRCWelcomeRunsCommands(t *testing.T) {
+	defer func(delay time.Duration) {
+		autoRunDelay = delay
+	}(autoRunDelay)
+	autoRunDelay = 10 * time.Millisecond
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	err = user.AddServer(&storage.Server{
+		Host: "127.0.0.1",
+		Port: port,
+		Nick: "nick",
+		Commands: []string{
+			"/MODE nick +B",
+			"",
+			"PRIVMSG NickServ :identify pass",
+			"JOIN #bots",
+		},
+	})
+	assert.Nil(t, err)
+	defer user.RemoveServer("127.0.0.1")
+
+	lines := make(chan string, 32)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.Write([]byte(":srv 001 nick :Welcome\r\n"))
+
+		scan := bufio.NewScanner(conn)
+		for scan.Scan() {
+			lines <- scan.Text()
+		}
+	}()
+
+	c := irc.NewClient(&irc.Config{
+		Nick: "nick",
+		Host: "127.0.0.1",
+		Port: port,
+	})
+	s := NewState(user, nil)
+	go s.run()
+	c.Connect()
+	go newIRCHandler(c, s).run()
+	defer c.Quit()
+
+	expected := []string{
+		"MODE nick +B",
+		"PRIVMSG NickServ :identify pass",
+		"JOIN #bots",
+	}
+	var got []string
+	timeout := time.After(2 * time.Second)
+	for len(got) < len(expected) {
+		select {
+		case line := <-lines:
+			for _, cmd := range expected {
+				if line == cmd {
+					got = append(got, line)
+				}
+			}
+
+		case <-timeout:
+			t.Fatal("Timed out waiting for the auto-run commands, got", got)
+		}
+	}
+	assert.Equal(t, expected, got)
+}
+
+func TestIRCHandlerLogJSON(t *testing.T) {
+	logger := logging.Default()
+	buf := &bytes.Buffer{}
+	logger.SetOutput(buf)
+	assert.Nil(t, logger.SetFormat(logging.FormatJSON))
+	defer func() {
+		logger.SetOutput(os.Stderr)
+		logger.SetFormat(logging.FormatText)
+	}()
+
+	c := irc.NewClient(&irc.Config{Nick: "nick", Host: "host.com"})
+	i := newIRCHandler(c, NewState(user, nil))
+	i.log(logging.LevelWarn, "Connection error", logging.F("error", errors.New("timeout")))
+
+	var entry map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "warn", entry["level"])
+	assert.Equal(t, "IRC", entry["event"])
+	assert.Equal(t, "Connection error", entry["msg"])
+	assert.Equal(t, float64(user.ID), entry["user"])
+	assert.Equal(t, "host.com", entry["server"])
+	assert.Equal(t, "timeout", entry["error"])
+	assert.Contains(t, entry, "time")
+}
+
+func TestHandleIRCWhoInterleaved(t *testing.T) {
+	port, _ := stubIRCServer(t, ":srv 001 nick :Welcome\r\n"+
+		":srv 353 nick = #a :nick a1 @a2\r\n"+
+		":srv 366 nick #a :End of NAMES list\r\n"+
+		":srv 353 nick = #b :nick b1\r\n"+
+		":srv 366 nick #b :End of NAMES list\r\n"+
+		":srv 352 nick #a ~a1 a1.host srv a1 H :0 A1\r\n"+
+		":srv 352 nick #b ~b1 b1.host srv b1 G :0 B1\r\n"+
+		":srv 352 nick #a ~a2 a2.host srv a2 G@ :0 A2\r\n"+
+		":srv 315 nick #b :End of WHO list\r\n"+
+		":srv 315 nick #a :End of WHO list\r\n"+
+		":srv 353 nick = #a :nick a1 @a2\r\n"+
+		":srv 366 nick #a :End of NAMES list\r\n")
+
+	c := irc.NewClient(&irc.Config{Host: "127.0.0.1", Port: port, Nick: "nick"})
+	s := NewState(user, nil)
+	h := newIRCHandler(c, s)
+	c.Connect()
+	defer c.Quit()
+
+	var userlists []Userlist
+	timeout := time.After(time.Second)
+	for len(userlists) < 5 {
+		select {
+		case msg := <-c.Messages:
+			h.dispatchMessage(msg)
+		case res := <-s.broadcast:
+			if res.Type == "users" {
+				userlists = append(userlists, res.Data.(Userlist))
+			}
+		case <-timeout:
+			t.Fatal("Missing userlists")
+		}
+	}
+
+	assert.Equal(t, Userlist{
+		Server:  "127.0.0.1",
+		Channel: "#b",
+		Users:   []string{"b1", "nick"},
+		Away:    []string{"b1"},
+		Hosts:   map[string]string{"b1": "~b1@b1.host"},
+	}, userlists[2])
+	assert.Equal(t, Userlist{
+		Server:  "127.0.0.1",
+		Channel: "#a",
+		Users:   []string{"@a2", "a1", "nick"},
+		Away:    []string{"a2"},
+		Hosts: map[string]string{
+			"a1": "~a1@a1.host",
+			"a2": "~a2@a2.host",
+		},
+	}, userlists[3])
+	// NAMES after the WHO keeps the cached hosts
+	assert.Equal(t, userlists[3], userlists[4])
+}
+
+func TestHandleIRCRename(t *testing.T) {
+	assert.Nil(t, user.AddChannel(&storage.Channel{Server: "host.com", Name: "#old"}))
+
+	res := dispatchMessage(&irc.Message{
+		Command: irc.RENAME,
+		Sender:  "op",
+		Params:  []string{"#old", "#new", "Moving"},
+	})
+	checkResponse(t, "channel_rename", ChannelRename{
+		Server: "host.com",
+		Old:    "#old",
+		New:    "#new",
+		Reason: "Moving",
+	}, res)
+
+	channels, err := user.GetChannels()
+	assert.Nil(t, err)
+	var names []string
+	for _, channel := range channels {
+		if channel.Server == "host.com" {
+			names = append(names, channel.Name)
+		}
+	}
+	assert.Contains(t, names, "#new")
+	assert.NotContains(t, names, "#old")
+	assert.Nil(t, user.RemoveChannel("host.com", "#new"))
+
+	res = dispatchMessage(&irc.Message{
+		Command: irc.FAIL,
+		Params:  []string{"RENAME", "CHANNEL_NAME_IN_USE", "#old", "#taken", "Channel already exists"},
+	})
+	checkResponse(t, "error", IRCError{
+		Server:  "host.com",
+		Target:  "#old",
+		Message: "Channel already exists",
+	}, res)
+}
+
+func TestHandleIRCReadReceipt(t *testing.T) {
+	receipt := &irc.Message{
+		Tags:    map[string]string{irc.ReadTag: "abc"},
+		Command: irc.TAGMSG,
+		Sender:  "other",
+		Params:  []string{"nick"},
+	}
+
+	settings := user.GetClientSettings()
+	defer user.SetClientSettings(user.GetClientSettings())
+	settings.ReadReceipts = false
+	assert.Nil(t, user.SetClientSettings(settings))
+	assert.Len(t, dispatchMessageMulti(receipt), 0)
+
+	settings.ReadReceipts = true
+	assert.Nil(t, user.SetClientSettings(settings))
+	checkResponse(t, "read_receipt", ReadReceipt{
+		Server: "host.com",
+		From:   "other",
+		MsgID:  "abc",
+	}, dispatchMessage(receipt))
+
+	assert.Len(t, dispatchMessageMulti(&irc.Message{
+		Tags:    map[string]string{irc.ReadTag: "abc"},
+		Command: irc.TAGMSG,
+		Sender:  "other",
+		Params:  []string{"#chan"},
+	}), 0)
+}
+
+func TestHandleIRCSASLMechs(t *testing.T) {
+	port, lines := stubIRCServer(t, ":srv CAP * LS :sasl\r\n"+
+		":srv CAP * ACK :sasl\r\n"+
+		":srv 908 nick EXTERNAL,ECDSA-NIST256P-CHALLENGE :are available SASL mechanisms\r\n"+
+		":srv 904 nick :SASL authentication failed\r\n"+
+		":srv 001 nick :Welcome\r\n")
+
+	c := irc.NewClient(&irc.Config{
+		Host:           "127.0.0.1",
+		Port:           port,
+		Nick:           "nick",
+		Account:        "nick",
+		Password:       "pass",
+		SASLMechanisms: []string{"PLAIN"},
+	})
+	s := NewState(user, nil)
+	h := newIRCHandler(c, s)
+	c.Connect()
+	defer c.Quit()
+
+	assert.Equal(t, "AUTHENTICATE PLAIN", nextLine(t, lines, "AUTHENTICATE"))
+	assert.Equal(t, "CAP END", nextLine(t, lines, "CAP END"))
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case msg := <-c.Messages:
+			h.dispatchMessage(msg)
+			if msg.Command == irc.RPL_SASLMECHS {
+				checkResponse(t, "error", Error{
+					Server:  "127.0.0.1",
+					Message: "SASL authentication failed, the server only supports EXTERNAL, ECDSA-NIST256P-CHALLENGE",
+				}, <-s.broadcast)
+				return
+			}
+		case <-timeout:
+			t.Fatal("no RPL_SASLMECHS")
+		}
+	}
+}
+
+func TestHandleIRCJoinLazy(t *testing.T) {
+	u, err := storage.NewUser(store)
+	assert.Nil(t, err)
+	assert.Nil(t, u.AddChannel(&storage.Channel{Server: "127.0.0.1", Name: "#lazy"}))
+
+	port, lines := stubIRCServer(t, ":srv CAP * LS :draft/no-implicit-names\r\n"+
+		":srv CAP * ACK :draft/no-implicit-names\r\n:srv 001 nick :Welcome\r\n")
+	c := irc.NewClient(&irc.Config{Host: "127.0.0.1", Port: port, Nick: "nick"})
+	s := NewState(u, nil)
+	s.setIRC("127.0.0.1", c)
+	h := newIRCHandler(c, s)
+	c.Connect()
+	defer c.Quit()
+	for msg := range c.Messages {
+		if msg.Command == irc.RPL_WELCOME {
+			break
+		}
+	}
+
+	join := func(channel string) {
+		h.dispatchMessage(&irc.Message{
+			Command: irc.JOIN,
+			Sender:  "nick",
+			Params:  []string{channel},
+		})
+	}
+
+	// Channels are eager by default, the userlist has to be asked for
+	// with no-implicit-names enabled
+	join("#lazy")
+	assert.Equal(t, "TOPIC #lazy", nextLine(t, lines, "TOPIC"))
+	assert.Equal(t, "NAMES #lazy", nextLine(t, lines, "NAMES"))
+
+	ws := &wsHandler{state: s}
+	ws.initHandlers()
+	ws.dispatchRequest(WSRequest{
+		Type: "set_channel_lazy",
+		Data: []byte(`{"server":"127.0.0.1","channel":"#lazy","lazy":true}`),
+	})
+	assert.True(t, u.IsChannelLazy("127.0.0.1", "#lazy"))
+
+	join("#lazy")
+	join("#eager")
+	assert.Equal(t, "TOPIC #eager", nextLine(t, lines, "TOPIC"))
+	assert.Equal(t, "NAMES #eager", nextLine(t, lines, "NAMES"))
+
+	ws.dispatchRequest(WSRequest{
+		Type: "load_channel",
+		Data: []byte(`{"server":"127.0.0.1","channel":"#lazy"}`),
+	})
+	assert.Equal(t, "TOPIC #lazy", nextLine(t, lines, "TOPIC"))
+	assert.Equal(t, "NAMES #lazy", nextLine(t, lines, "NAMES"))
+
+	// Rejoining keeps the channel lazy
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, u.IsChannelLazy("127.0.0.1", "#lazy"))
+	assert.False(t, u.IsChannelLazy("127.0.0.1", "#eager"))
+
+	ws.dispatchRequest(WSRequest{
+		Type: "set_channel_lazy",
+		Data: []byte(`{"server":"127.0.0.1","channel":"#missing","lazy":true}`),
+	})
+	for res := range s.broadcast {
+		if res.Type == "error" {
+			checkResponse(t, "error", Error{
+				Server:  "127.0.0.1",
+				Message: storage.ErrNotFound.Error(),
+			}, res)
+			break
+		}
+	}
+}
+
+func TestHandleIRCJoinReplayed(t *testing.T) {
+	u, err := storage.NewUser(store)
+	assert.Nil(t, err)
+	assert.Nil(t, u.LogMessage(&storage.Message{
+		ID:      betterguid.New(),
+		Server:  "host.com",
+		From:    "someone",
+		To:      "#chan",
+		Content: "hello",
+	}))
+
+	c := irc.NewClient(&irc.Config{
+		Nick:     "nick",
+		Username: "user",
+		Host:     "host.com",
+	})
+	s := NewState(u, New(&config.Config{IgnoreDuplicateJoins: true}))
+	h := newIRCHandler(c, s)
+
+	dispatch := func(command, channel string) []string {
+		h.dispatchMessage(&irc.Message{
+			Command: command,
+			Sender:  "nick",
+			Params:  []string{channel},
+		})
+
+		var types []string
+		timeout := time.After(100 * time.Millisecond)
+		for {
+			select {
+			case res := <-s.broadcast:
+				types = append(types, res.Type)
+			case <-timeout:
+				return types
+			}
+		}
+	}
+	joinEvents := func() int {
+		messages, _, err := u.GetLastMessages("host.com", "#chan", 1)
+		assert.Nil(t, err)
+		return len(messages[0].Events)
+	}
+
+	assert.Equal(t, []string{"join", "messages"}, dispatch(irc.JOIN, "#chan"))
+	assert.Equal(t, 1, joinEvents())
+
+	// The replayed JOIN updates the client without the scrollback or
+	// getting logged again
+	assert.Equal(t, []string{"join"}, dispatch(irc.JOIN, "#CHAN"))
+	assert.Equal(t, 1, joinEvents())
+
+	assert.Equal(t, []string{"part"}, dispatch(irc.PART, "#chan"))
+	assert.Equal(t, []string{"join", "messages"}, dispatch(irc.JOIN, "#chan"))
+
+	// Getting kicked means the next JOIN is a real one
+	h.dispatchMessage(&irc.Message{
+		Command: irc.KICK,
+		Sender:  "op",
+		Params:  []string{"#Chan", "NICK", "bye"},
+	})
+	assert.Equal(t, []string{"join", "messages"}, dispatch(irc.JOIN, "#chan"))
+
+	s.srv.SetConfig(&config.Config{})
+	assert.Equal(t, []string{"join", "messages"}, dispatch(irc.JOIN, "#chan"))
+}
+
+func TestHandleIRCJoinReplayedAfterReconnect(t *testing.T) {
+	u, err := storage.NewUser(store)
+	assert.Nil(t, err)
+	assert.Nil(t, u.LogMessage(&storage.Message{
+		ID:      betterguid.New(),
+		Server:  "host.com",
+		From:    "someone",
+		To:      "#chan",
+		Content: "hello",
+	}))
+
+	c := irc.NewClient(&irc.Config{
+		Nick:     "nick",
+		Username: "user",
+		Host:     "host.com",
+	})
+	s := NewState(u, New(&config.Config{IgnoreDuplicateJoins: true}))
+	go newIRCHandler(c, s).run()
+
+	events := func() []string {
+		var types []string
+		timeout := time.After(100 * time.Millisecond)
+		for {
+			select {
+			case res := <-s.broadcast:
+				if res.Type != "connection_update" {
+					types = append(types, res.Type)
+				}
+			case <-timeout:
+				return types
+			}
+		}
+	}
+	join := func() []string {
+		c.Messages <- &irc.Message{
+			Command: irc.JOIN,
+			Sender:  "nick",
+			Params:  []string{"#chan"},
+		}
+		return events()
+	}
+	reconnect := func() {
+		c.ConnectionChanged <- irc.ConnectionState{Connected: false}
+		c.ConnectionChanged <- irc.ConnectionState{Connected: true}
+		events()
+	}
+	joinEvents := func() int {
+		messages, _, err := u.GetLastMessages("host.com", "#chan", 1)
+		assert.Nil(t, err)
+		return len(messages[0].Events)
+	}
+
+	assert.Equal(t, []string{"join", "messages"}, join())
+	assert.Equal(t, 1, joinEvents())
+
+	// The bouncer replays the JOIN after dispatch reconnects to it
+	reconnect()
+	assert.Equal(t, []string{"join"}, join())
+	assert.Equal(t, 1, joinEvents())
+
+	// Failed reconnects do not make it forget the channels
+	c.ConnectionChanged <- irc.ConnectionState{Connected: false}
+	c.ConnectionChanged <- irc.ConnectionState{Connected: false}
+	c.ConnectionChanged <- irc.ConnectionState{Connected: true}
+	events()
+	assert.Equal(t, []string{"join"}, join())
+
+	// The channel is only remembered for the connection after the one it
+	// was joined on
+	reconnect()
+	reconnect()
+	assert.Equal(t, []string{"join", "messages"}, join())
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	cfg := &config.Config{VerifyCertificates: true}
+
+	// An empty server name makes the irc client use the host
+	tlsConfig := newTLSConfig(cfg, &storage.Server{Host: "irc.example.com"}, nil)
+	assert.Empty(t, tlsConfig.ServerName)
+	assert.Nil(t, tlsConfig.NextProtos)
+	assert.False(t, tlsConfig.InsecureSkipVerify)
+	assert.Nil(t, tlsConfig.Certificates)
+
+	cert := &tls.Certificate{}
+	tlsConfig = newTLSConfig(&config.Config{}, &storage.Server{
+		Host:          "10.0.0.1",
+		TLSServerName: "irc.example.com",
+		ALPN:          []string{"irc"},
+	}, cert)
+	assert.Equal(t, "irc.example.com", tlsConfig.ServerName)
+	assert.Equal(t, []string{"irc"}, tlsConfig.NextProtos)
+	assert.True(t, tlsConfig.InsecureSkipVerify)
+	assert.Len(t, tlsConfig.Certificates, 1)
+}
+
+// pinnedHandshake does a TLS handshake with a server presenting cert using
+// the TLS config of a server with pins
+func pinnedHandshake(t *testing.T, cert tls.Certificate, pins []string) error {
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	})
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.(*tls.Conn).Handshake()
+	}()
+
+	tlsConfig := newTLSConfig(&config.Config{VerifyCertificates: true}, &storage.Server{
+		Host:        "irc.example.com",
+		PinnedCerts: pins,
+	}, nil)
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", ln.Addr().String(), tlsConfig)
+	if err == nil {
+		conn.Close()
+	}
+	return err
+}
+
+func TestPinnedCerts(t *testing.T) {
+	current := newTestCertificate(t, time.Now().Add(time.Hour))
+	next := newTestCertificate(t, time.Now().Add(time.Hour))
+	other := newTestCertificate(t, time.Now().Add(time.Hour))
+
+	fingerprint := func(cert tls.Certificate) string {
+		hash := sha256.Sum256(cert.Certificate[0])
+		return hex.EncodeToString(hash[:])
+	}
+	pins := []string{fingerprint(current), fingerprint(next)}
+
+	// The self-signed certificates would fail verification without pins
+	assert.NotNil(t, pinnedHandshake(t, current, nil))
+
+	assert.Nil(t, pinnedHandshake(t, current, pins))
+	assert.Equal(t, 0, matchPin(pins, fingerprint(current)))
+
+	assert.Nil(t, pinnedHandshake(t, next, pins))
+	assert.Equal(t, 1, matchPin(pins, fingerprint(next)))
+
+	err := pinnedHandshake(t, other, pins)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), errPinMismatch.Error())
+	assert.Equal(t, -1, matchPin(pins, fingerprint(other)))
+
+	tlsConfig := newTLSConfig(&config.Config{}, &storage.Server{PinnedCerts: pins}, nil)
+	setPinnedCerts(tlsConfig, nil, false)
+	assert.False(t, tlsConfig.InsecureSkipVerify)
+	assert.Nil(t, tlsConfig.VerifyPeerCertificate)
+}
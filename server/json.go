@@ -17,6 +17,23 @@ type WSRequest struct {
 type WSResponse struct {
 	Type string
 	Data interface{}
+	// Seq numbers the events sent to all sessions of the user, a session
+	// can reconnect with the last one it got to have the missed ones
+	// replayed
+	Seq uint64
+}
+
+// Resume is sent to a reconnecting session when the events it missed
+// could be replayed, they follow it
+type Resume struct {
+	Replayed int
+}
+
+// RequestError is sent when a request from the client could not be handled,
+// Type is the type of the request when it could be read
+type RequestError struct {
+	Type    string
+	Message string
 }
 
 type Server struct {
@@ -25,6 +42,38 @@ type Server struct {
 	Features map[string]interface{}
 }
 
+type ServerCommands struct {
+	Server   string
+	Commands []string
+}
+
+// ClientTags are the client-only tags forwarded from incoming messages
+// and the ones in the key=value form added to outgoing messages
+type ClientTags struct {
+	Server  string
+	Forward []string
+	Send    []string
+}
+
+// ServerTLS is the server name sent with SNI and the ALPN protocols
+// offered when connecting to a server with TLS
+type ServerTLS struct {
+	Server     string
+	ServerName string
+	ALPN       []string
+}
+
+// ServerPins are the fingerprints of the certificates a server is pinned
+// to, the first is the current one and the rest are rotation pins
+type ServerPins struct {
+	Server string
+	Pins   []string
+}
+
+type AlwaysOn struct {
+	Enabled bool
+}
+
 type Features struct {
 	Server   string
 	Features map[string]interface{}
@@ -54,8 +103,16 @@ func newConnectionUpdate(server string, state irc.ConnectionState) ConnectionUpd
 	}
 	if state.Error != nil {
 		status.Error = state.Error.Error()
-		if _, ok := state.Error.(x509.UnknownAuthorityError); ok {
+		switch err := state.Error.(type) {
+		case x509.UnknownAuthorityError:
 			status.ErrorType = "verify"
+		case *irc.LinkClosedError:
+			status.ErrorType = "link_closed"
+		case *irc.TimeoutError:
+			// dns_timeout, connect_timeout, tls_timeout or registration_timeout
+			status.ErrorType = err.Phase + "_timeout"
+		case *irc.ServerNameError:
+			status.ErrorType = "server_name"
 		}
 	}
 	return status
@@ -75,6 +132,7 @@ type Join struct {
 	Server   string
 	User     string
 	Channels []string
+	Away     bool
 }
 
 type Part struct {
@@ -95,13 +153,62 @@ type Quit struct {
 	Reason string
 }
 
+// Killed is sent when an operator killed the connection to a server,
+// Cooldown is how many seconds it takes before reconnecting
+type Killed struct {
+	Server   string
+	By       string
+	Reason   string
+	Cooldown int64
+}
+
+type Netsplit struct {
+	Server  string
+	Servers []string
+	Users   []string
+}
+
+// Netjoin maps the channels joined to the users that rejoined them
+type Netjoin struct {
+	Server   string
+	Servers  []string
+	Channels map[string][]string
+}
+
 type Message struct {
 	ID      string
 	Server  string
 	From    string
 	To      string
+	Account string
 	Content string
 	Type    string
+	// StatusMsg holds the prefixes of messages sent only to users with
+	// those channel modes, like @ for ops
+	StatusMsg string
+	// Away is set when the sender is known to be away
+	Away bool
+	// MsgID is the msgid tag set by the IRC server
+	MsgID string
+	// ReplyTo is the msgid of the message this is a reply to
+	ReplyTo string
+	// Tags are the client-only tags forwarded from the IRC message
+	Tags map[string]string
+	// Avatar is the avatar URL of the sender
+	Avatar string
+}
+
+// QueuedMessage is a message sent while disconnected from the server,
+// Sent is set once it has been sent after reconnecting
+type QueuedMessage struct {
+	ID        string
+	Server    string
+	To        string
+	Content   string
+	StatusMsg string
+	ReplyTo   string
+	Time      int64
+	Sent      bool
 }
 
 type Messages struct {
@@ -123,12 +230,122 @@ type Userlist struct {
 	Server  string
 	Channel string
 	Users   []string
+	// Total and Prefixes are only set when Users is the first page of a
+	// userlist larger than the configured limit
+	Total    int
+	Prefixes map[string]int
+	// Away is the nicks in Users that are away
+	Away []string
+	// Accounts and Hosts are filled in from WHO replies, keyed by nick,
+	// Hosts holds user@host
+	Accounts map[string]string
+	Hosts    map[string]string
+	// Metadata is the known metadata of the users, keyed by nick
+	Metadata map[string]map[string]string
+	// Avatars are the avatar URLs of the users, keyed by nick
+	Avatars map[string]string
+}
+
+// Who asks the server who is in a channel, the userlist gets sent again
+// with their accounts and hosts once the server is done replying
+type Who struct {
+	Server  string
+	Channel string
+}
+
+type FetchUsers struct {
+	Server  string
+	Channel string
+	Offset  int
+}
+
+type UserlistPage struct {
+	Server   string
+	Channel  string
+	Users    []string
+	Offset   int
+	Total    int
+	Away     []string
+	Metadata map[string]map[string]string
+	Avatars  map[string]string
+	// Accounts and Hosts are filled in from cached WHO replies, keyed by nick
+	Accounts map[string]string
+	Hosts    map[string]string
+}
+
+type UserAway struct {
+	Server  string
+	User    string
+	Away    bool
+	Message string
 }
 
 type MOTD struct {
 	Server  string
 	Title   string
 	Content []string
+	// Missing is set when the server has no MOTD, Content then holds
+	// the reason
+	Missing bool
+}
+
+// OperRequest holds the credentials to send with OPER, the ones stored
+// for the server are used when Name is empty. With set_oper they get
+// stored instead, an empty Name removes them.
+type OperRequest struct {
+	Server   string
+	Name     string
+	Password string
+}
+
+// OperReply is the result of an OPER command, Modes are the user modes
+// of the client after it
+type OperReply struct {
+	Server  string
+	Success bool
+	Message string
+	Modes   string
+}
+
+// AccountRegistration registers an account with REGISTER, an empty Account
+// uses the current nick. With verify_account Code gets sent with VERIFY
+// to complete a registration waiting for email verification.
+type AccountRegistration struct {
+	Server   string
+	Account  string
+	Email    string
+	Password string
+	Code     string
+}
+
+// AccountRegistrationReply is the result of REGISTER or VERIFY, Status is
+// "success", "verification_required" or "failed", Code holds the FAIL code
+type AccountRegistrationReply struct {
+	Server  string
+	Command string
+	Status  string
+	Account string
+	Code    string
+	Message string
+}
+
+type MOTDRequest struct {
+	Server string
+}
+
+// Help is the reply to a HELP command
+type Help struct {
+	Server  string
+	Topic   string
+	Content []string
+	// Missing is set when the server has no help on Topic, Content then
+	// holds the reason
+	Missing bool
+}
+
+type HelpRequest struct {
+	Server string
+	Topic  string
 }
 
 type Invite struct {
@@ -148,6 +365,37 @@ type Whois struct {
 	User   string
 }
 
+type CTCP struct {
+	Server  string
+	Target  string
+	Command string
+	Params  string
+}
+
+type CTCPReply struct {
+	Server  string
+	From    string
+	Command string
+	Reply   string
+	// Latency is the round-trip time in milliseconds
+	Latency int64
+	Timeout bool
+}
+
+type LinkPreview struct {
+	Server string
+	// Target is the channel or the user the message is in a DM with
+	Target      string
+	MessageID   string
+	URL         string
+	SiteName    string
+	Color       string
+	Title       string
+	Description string
+	ImageURL    string
+	VideoURL    string
+}
+
 type WhoisReply struct {
 	Nick     string
 	Username string
@@ -155,6 +403,96 @@ type WhoisReply struct {
 	Realname string
 	Server   string
 	Channels []string
+	Operator string
+	Bot      bool
+	// Special is any extra lines the server has about the user
+	Special    []string
+	ActualHost string
+	ActualIP   string
+}
+
+type AdminUsers struct {
+	Users []AdminUser
+}
+
+type AdminUser struct {
+	ID       uint64
+	Username string
+	Admin    bool
+	// Sessions is the addresses of the connected WebSocket sessions
+	Sessions []string
+	Servers  []AdminServer
+}
+
+type AdminServer struct {
+	Host      string
+	Nick      string
+	Connected bool
+}
+
+type AdminDisconnect struct {
+	User    uint64
+	Session string
+	Server  string
+}
+
+// AdminLogLevel changes the log level when Level is set,
+// the reply holds the current one
+type AdminLogLevel struct {
+	Level string
+}
+
+// CompactResult is how the compaction of a store went, Duration is in
+// milliseconds and the sizes are in bytes
+type CompactResult struct {
+	Store      string
+	Duration   int64
+	SizeBefore int64
+	SizeAfter  int64
+	Reclaimed  int64
+	Error      string
+}
+
+// AdminCompact holds the results of compacting every store
+type AdminCompact struct {
+	Results []CompactResult
+}
+
+// AdminDeleteUser deletes a user and everything stored for it
+type AdminDeleteUser struct {
+	User uint64
+}
+
+// ServerCheck asks for a test connection to a server that does not get
+// saved, Nick is used to register and defaults to dispatch
+type ServerCheck struct {
+	Host string
+	Port string
+	TLS  bool
+	Nick string
+}
+
+// ServerCheckResult tells if the server could be registered with,
+// Error is set when it could not
+type ServerCheckResult struct {
+	Host    string
+	Port    string
+	TLS     bool
+	OK      bool
+	Network string
+	Error   string
+}
+
+// IdleDisconnect is sent when the connections to Servers got closed
+// because the user had no sessions open for too long
+type IdleDisconnect struct {
+	Servers []string
+}
+
+// SearchReindexed is sent when the search index has been rebuilt,
+// Messages is how many got indexed
+type SearchReindexed struct {
+	Messages int
 }
 
 type Away struct {
@@ -167,16 +505,134 @@ type Raw struct {
 	Message string
 }
 
+// Command is a line of user input, aliases get expanded before it is sent
+type Command struct {
+	Server  string
+	Channel string
+	Command string
+}
+
+// CommandHistory holds the last lines the user sent to a channel, oldest first
+type CommandHistory struct {
+	Server   string
+	Channel  string
+	Commands []string
+}
+
+// Order holds the order and grouping of the users servers and channels
+type Order struct {
+	Placements []storage.Placement
+}
+
+// Appearance is the color and label of a server, or a channel
+// when Channel is set
+type Appearance struct {
+	Server  string
+	Channel string
+	Color   string
+	Label   string
+}
+
+// ChannelLazy sets whether the topic and userlist of a channel are only
+// fetched when the client asks for them with LoadChannel
+type ChannelLazy struct {
+	Server  string
+	Channel string
+	Lazy    bool
+}
+
+// LoadChannel requests the topic and userlist of a channel, they are sent
+// as they usually would be
+type LoadChannel struct {
+	Server  string
+	Channel string
+}
+
+// Metadata is a key set on a user or channel, Value is empty when the key
+// is not set or got cleared
+type Metadata struct {
+	Server string
+	Target string
+	Key    string
+	Value  string
+}
+
+// SetMetadata sets a key on the users own metadata, an empty Value
+// clears it
+type SetMetadata struct {
+	Server string
+	Key    string
+	Value  string
+}
+
+// FetchMetadata requests the keys set on a user or channel, or all of
+// them when Keys is empty, they are sent as metadata events
+type FetchMetadata struct {
+	Server string
+	Target string
+	Keys   []string
+}
+
+type Alias struct {
+	Name      string
+	Expansion string
+}
+
+type Aliases struct {
+	Aliases map[string]string
+}
+
 type SearchRequest struct {
 	Server  string
 	Channel string
 	Phrase  string
+	// Snippets makes the results come back as Matches
+	Snippets bool
+	Offset   int
+	Limit    int
 }
 
 type SearchResult struct {
 	Server  string
 	Channel string
 	Results []storage.Message
+	Matches []storage.SearchMatch
+	// Offset is where this page starts, Total is the number of matches
+	// across all pages
+	Offset int
+	Total  uint64
+}
+
+type FetchMentions struct {
+	Limit int
+}
+
+// Mentions are the newest messages across all channels that mentioned
+// the user
+type Mentions struct {
+	Mentions []storage.Mention
+}
+
+// Pasted tells the user that a message got sent as a link to a paste
+type Pasted struct {
+	Server string
+	To     string
+	URL    string
+}
+
+type CreatePaste struct {
+	Name    string
+	Content string
+}
+
+// PasteCreated is a paste the user created, Expires is a unix timestamp
+// and 0 means never
+type PasteCreated struct {
+	ID      string
+	Name    string
+	URL     string
+	Size    int
+	Expires int64
 }
 
 type ClientCert struct {
@@ -190,6 +646,96 @@ type FetchMessages struct {
 	Next    string
 }
 
+type FetchMessageContext struct {
+	Server  string
+	Channel string
+	ID      string
+	Count   int
+}
+
+// FetchMessagesAt jumps to the first message sent at or after Time,
+// which is a unix timestamp
+type FetchMessagesAt struct {
+	Server  string
+	Channel string
+	Time    int64
+	Count   int
+}
+
+type MessageContext struct {
+	Server   string
+	To       string
+	ID       string
+	Messages []storage.Message
+}
+
+// FetchTopic asks the server for the current topic of a channel, it gets
+// sent as a topic event
+type FetchTopic struct {
+	Server  string
+	Channel string
+}
+
+type FetchTopics struct {
+	Server  string
+	Channel string
+	Next    string
+}
+
+type TopicHistory struct {
+	Server  string
+	Channel string
+	Topics  []storage.Topic
+	Next    string
+}
+
+type ServerInfoRequest struct {
+	Server string
+}
+
+type ServerInfo struct {
+	Server   string
+	Network  string
+	Nick     string
+	Modes    string
+	Status   ConnectionUpdate
+	Features map[string]interface{}
+	TLS      *TLSInfo
+}
+
+// TLSInfo describes the certificate of a server, NotBefore and NotAfter
+// are unix timestamps and ExpiresSoon is set when it expires within
+// certExpiryWarning
+type TLSInfo struct {
+	Server      string
+	Subject     string
+	Issuer      string
+	NotBefore   int64
+	NotAfter    int64
+	Fingerprint string
+	ExpiresSoon bool
+}
+
+func newTLSInfo(server string, cert *irc.Certificate) *TLSInfo {
+	if cert == nil {
+		return nil
+	}
+	return &TLSInfo{
+		Server:      server,
+		Subject:     cert.Subject,
+		Issuer:      cert.Issuer,
+		NotBefore:   cert.NotBefore.Unix(),
+		NotAfter:    cert.NotAfter.Unix(),
+		Fingerprint: cert.Fingerprint,
+		ExpiresSoon: cert.ExpiresWithin(certExpiryWarning),
+	}
+}
+
+type RawLog struct {
+	Server  string
+	Enabled bool
+}
+
 type Error struct {
 	Server  string
 	Message string
@@ -201,10 +747,27 @@ type IRCError struct {
 	Message string
 }
 
+// LinkClosed is sent when the server closes the connection with an ERROR
+type LinkClosed struct {
+	Server string
+	Reason string
+}
+
+// StandardReply is an IRCv3 FAIL, WARN or NOTE message
+type StandardReply struct {
+	Server   string
+	Severity string
+	Command  string
+	Code     string
+	Context  []string
+	Message  string
+}
+
 type ChannelSearch struct {
 	Server string
 	Q      string
 	Start  int
+	Fuzzy  bool
 }
 
 type ChannelSearchResult struct {
@@ -212,12 +775,43 @@ type ChannelSearchResult struct {
 	Results []*storage.ChannelListItem
 }
 
+type ChannelListProgress struct {
+	Server string
+	Count  int
+	Done   bool
+}
+
 type ChannelForward struct {
 	Server string
 	Old    string
 	New    string
 }
 
+// ChannelRename is sent when a channel got renamed, it is also the
+// request to rename one
+type ChannelRename struct {
+	Server string
+	Old    string
+	New    string
+	Reason string
+}
+
+// MarkRead is sent when the user has read a DM up to the message with
+// the msgid MsgID
+type MarkRead struct {
+	Server string
+	Target string
+	MsgID  string
+}
+
+// ReadReceipt is sent when the other side of a DM has read it up to the
+// message with the msgid MsgID
+type ReadReceipt struct {
+	Server string
+	From   string
+	MsgID  string
+}
+
 type DCCSend struct {
 	Server   string
 	From     string
@@ -225,6 +819,33 @@ type DCCSend struct {
 	URL      string
 }
 
+type DCCTransfer struct {
+	Server         string
+	From           string
+	File           string
+	Size           uint64
+	PercCompletion float64
+	BytesCompleted string
+	Speed          string
+	SecondsToGo    float64
+}
+
+type DCCTransfers struct {
+	Transfers []DCCTransfer
+}
+
+type CancelDCC struct {
+	File string
+	// Delete removes what has been downloaded so far
+	Delete bool
+}
+
+type DCCCancelled struct {
+	Server  string
+	File    string
+	Deleted bool
+}
+
 type Tab struct {
 	storage.Tab
 }
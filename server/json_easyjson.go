@@ -1,4 +1,4 @@
-//out.Data: false//v7: false//v30: false// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+//out.Data: false//v24: false//v29: false//v75: false// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
 
 package server
 
@@ -71,6 +71,37 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer(in *jlexer.Lexer, out
 				}
 				in.Delim(']')
 			}
+		case "operator":
+			out.Operator = string(in.String())
+		case "bot":
+			out.Bot = bool(in.Bool())
+		case "special":
+			if in.IsNull() {
+				in.Skip()
+				out.Special = nil
+			} else {
+				in.Delim('[')
+				if out.Special == nil {
+					if !in.IsDelim(']') {
+						out.Special = make([]string, 0, 4)
+					} else {
+						out.Special = []string{}
+					}
+				} else {
+					out.Special = (out.Special)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v2 string
+					v2 = string(in.String())
+					out.Special = append(out.Special, v2)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "actualHost":
+			out.ActualHost = string(in.String())
+		case "actualIP":
+			out.ActualIP = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -141,15 +172,74 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer(out *jwriter.Writer, i
 		}
 		{
 			out.RawByte('[')
-			for v2, v3 := range in.Channels {
-				if v2 > 0 {
+			for v3, v4 := range in.Channels {
+				if v3 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v4))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Operator != "" {
+		const prefix string = ",\"operator\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Operator))
+	}
+	if in.Bot {
+		const prefix string = ",\"bot\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Bot))
+	}
+	if len(in.Special) != 0 {
+		const prefix string = ",\"special\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v5, v6 := range in.Special {
+				if v5 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v3))
+				out.String(string(v6))
 			}
 			out.RawByte(']')
 		}
 	}
+	if in.ActualHost != "" {
+		const prefix string = ",\"actualHost\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ActualHost))
+	}
+	if in.ActualIP != "" {
+		const prefix string = ",\"actualIP\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ActualIP))
+	}
 	out.RawByte('}')
 }
 
@@ -284,6 +374,8 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer2(in *jlexer.Lexer, out
 			} else {
 				out.Data = in.Interface()
 			}
+		case "seq":
+			out.Seq = uint64(in.Uint64())
 		default:
 			in.SkipRecursive()
 		}
@@ -320,6 +412,16 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer2(out *jwriter.Writer,
 			out.Raw(json.Marshal(in.Data))
 		}
 	}
+	if in.Seq != 0 {
+		const prefix string = ",\"seq\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint64(uint64(in.Seq))
+	}
 	out.RawByte('}')
 }
 
@@ -427,7 +529,7 @@ func (v *WSRequest) UnmarshalJSON(data []byte) error {
 func (v *WSRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer3(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer4(in *jlexer.Lexer, out *Userlist) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer4(in *jlexer.Lexer, out *UserlistPage) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -466,13 +568,138 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer4(in *jlexer.Lexer, out
 					out.Users = (out.Users)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v4 string
-					v4 = string(in.String())
-					out.Users = append(out.Users, v4)
+					var v7 string
+					v7 = string(in.String())
+					out.Users = append(out.Users, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "offset":
+			out.Offset = int(in.Int())
+		case "total":
+			out.Total = int(in.Int())
+		case "away":
+			if in.IsNull() {
+				in.Skip()
+				out.Away = nil
+			} else {
+				in.Delim('[')
+				if out.Away == nil {
+					if !in.IsDelim(']') {
+						out.Away = make([]string, 0, 4)
+					} else {
+						out.Away = []string{}
+					}
+				} else {
+					out.Away = (out.Away)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v8 string
+					v8 = string(in.String())
+					out.Away = append(out.Away, v8)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
+		case "metadata":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Metadata = make(map[string]map[string]string)
+				} else {
+					out.Metadata = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v149 map[string]string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						in.Delim('{')
+						if !in.IsDelim('}') {
+							v149 = make(map[string]string)
+						} else {
+							v149 = nil
+						}
+						for !in.IsDelim('}') {
+							key := string(in.String())
+							in.WantColon()
+							var v150 string
+							v150 = string(in.String())
+							(v149)[key] = v150
+							in.WantComma()
+						}
+						in.Delim('}')
+					}
+					(out.Metadata)[key] = v149
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "avatars":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Avatars = make(map[string]string)
+				} else {
+					out.Avatars = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v155 string
+					v155 = string(in.String())
+					(out.Avatars)[key] = v155
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "accounts":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Accounts = make(map[string]string)
+				} else {
+					out.Accounts = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v157 string
+					v157 = string(in.String())
+					(out.Accounts)[key] = v157
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "hosts":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Hosts = make(map[string]string)
+				} else {
+					out.Hosts = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v158 string
+					v158 = string(in.String())
+					(out.Hosts)[key] = v158
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -483,7 +710,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer4(in *jlexer.Lexer, out
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer4(out *jwriter.Writer, in Userlist) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer4(out *jwriter.Writer, in UserlistPage) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -513,42 +740,193 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer4(out *jwriter.Writer,
 		}
 		{
 			out.RawByte('[')
-			for v5, v6 := range in.Users {
-				if v5 > 0 {
+			for v9, v10 := range in.Users {
+				if v9 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v6))
+				out.String(string(v10))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Offset != 0 {
+		const prefix string = ",\"offset\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Offset))
+	}
+	if in.Total != 0 {
+		const prefix string = ",\"total\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Total))
+	}
+	if len(in.Away) != 0 {
+		const prefix string = ",\"away\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v11, v12 := range in.Away {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v12))
 			}
 			out.RawByte(']')
 		}
 	}
+	if len(in.Metadata) != 0 {
+		const prefix string = ",\"metadata\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v151First := true
+			for v151Name, v151Value := range in.Metadata {
+				if v151First {
+					v151First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v151Name))
+				out.RawByte(':')
+				if v151Value == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+					out.RawString(`null`)
+				} else {
+					out.RawByte('{')
+					v152First := true
+					for v152Name, v152Value := range v151Value {
+						if v152First {
+							v152First = false
+						} else {
+							out.RawByte(',')
+						}
+						out.String(string(v152Name))
+						out.RawByte(':')
+						out.String(string(v152Value))
+					}
+					out.RawByte('}')
+				}
+			}
+			out.RawByte('}')
+		}
+	}
+	if len(in.Avatars) != 0 {
+		const prefix string = ",\"avatars\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v156First := true
+			for v156Name, v156Value := range in.Avatars {
+				if v156First {
+					v156First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v156Name))
+				out.RawByte(':')
+				out.String(string(v156Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	if len(in.Accounts) != 0 {
+		const prefix string = ",\"accounts\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v159First := true
+			for v159Name, v159Value := range in.Accounts {
+				if v159First {
+					v159First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v159Name))
+				out.RawByte(':')
+				out.String(string(v159Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	if len(in.Hosts) != 0 {
+		const prefix string = ",\"hosts\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v160First := true
+			for v160Name, v160Value := range in.Hosts {
+				if v160First {
+					v160First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v160Name))
+				out.RawByte(':')
+				out.String(string(v160Value))
+			}
+			out.RawByte('}')
+		}
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Userlist) MarshalJSON() ([]byte, error) {
+func (v UserlistPage) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Userlist) MarshalEasyJSON(w *jwriter.Writer) {
+func (v UserlistPage) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Userlist) UnmarshalJSON(data []byte) error {
+func (v *UserlistPage) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Userlist) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *UserlistPage) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer4(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer5(in *jlexer.Lexer, out *Topic) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer5(in *jlexer.Lexer, out *Userlist) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -571,21 +949,9086 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer5(in *jlexer.Lexer, out
 			out.Server = string(in.String())
 		case "channel":
 			out.Channel = string(in.String())
-		case "topic":
-			out.Topic = string(in.String())
-		case "nick":
-			out.Nick = string(in.String())
-		default:
-			in.SkipRecursive()
-		}
-		in.WantComma()
-	}
-	in.Delim('}')
-	if isTopLevel {
-		in.Consumed()
-	}
+		case "users":
+			if in.IsNull() {
+				in.Skip()
+				out.Users = nil
+			} else {
+				in.Delim('[')
+				if out.Users == nil {
+					if !in.IsDelim(']') {
+						out.Users = make([]string, 0, 4)
+					} else {
+						out.Users = []string{}
+					}
+				} else {
+					out.Users = (out.Users)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v13 string
+					v13 = string(in.String())
+					out.Users = append(out.Users, v13)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "total":
+			out.Total = int(in.Int())
+		case "prefixes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Prefixes = make(map[string]int)
+				} else {
+					out.Prefixes = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v14 int
+					v14 = int(in.Int())
+					(out.Prefixes)[key] = v14
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "away":
+			if in.IsNull() {
+				in.Skip()
+				out.Away = nil
+			} else {
+				in.Delim('[')
+				if out.Away == nil {
+					if !in.IsDelim(']') {
+						out.Away = make([]string, 0, 4)
+					} else {
+						out.Away = []string{}
+					}
+				} else {
+					out.Away = (out.Away)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v15 string
+					v15 = string(in.String())
+					out.Away = append(out.Away, v15)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "accounts":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Accounts = make(map[string]string)
+				} else {
+					out.Accounts = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v117 string
+					v117 = string(in.String())
+					(out.Accounts)[key] = v117
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "hosts":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Hosts = make(map[string]string)
+				} else {
+					out.Hosts = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v118 string
+					v118 = string(in.String())
+					(out.Hosts)[key] = v118
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "metadata":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Metadata = make(map[string]map[string]string)
+				} else {
+					out.Metadata = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v145 map[string]string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						in.Delim('{')
+						if !in.IsDelim('}') {
+							v145 = make(map[string]string)
+						} else {
+							v145 = nil
+						}
+						for !in.IsDelim('}') {
+							key := string(in.String())
+							in.WantColon()
+							var v146 string
+							v146 = string(in.String())
+							(v145)[key] = v146
+							in.WantComma()
+						}
+						in.Delim('}')
+					}
+					(out.Metadata)[key] = v145
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "avatars":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Avatars = make(map[string]string)
+				} else {
+					out.Avatars = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v153 string
+					v153 = string(in.String())
+					(out.Avatars)[key] = v153
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer5(out *jwriter.Writer, in Userlist) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if len(in.Users) != 0 {
+		const prefix string = ",\"users\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v16, v17 := range in.Users {
+				if v16 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v17))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Total != 0 {
+		const prefix string = ",\"total\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Total))
+	}
+	if len(in.Prefixes) != 0 {
+		const prefix string = ",\"prefixes\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v18First := true
+			for v18Name, v18Value := range in.Prefixes {
+				if v18First {
+					v18First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v18Name))
+				out.RawByte(':')
+				out.Int(int(v18Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	if len(in.Away) != 0 {
+		const prefix string = ",\"away\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v19, v20 := range in.Away {
+				if v19 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v20))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Accounts) != 0 {
+		const prefix string = ",\"accounts\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v119First := true
+			for v119Name, v119Value := range in.Accounts {
+				if v119First {
+					v119First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v119Name))
+				out.RawByte(':')
+				out.String(string(v119Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	if len(in.Hosts) != 0 {
+		const prefix string = ",\"hosts\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v120First := true
+			for v120Name, v120Value := range in.Hosts {
+				if v120First {
+					v120First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v120Name))
+				out.RawByte(':')
+				out.String(string(v120Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	if len(in.Metadata) != 0 {
+		const prefix string = ",\"metadata\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v147First := true
+			for v147Name, v147Value := range in.Metadata {
+				if v147First {
+					v147First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v147Name))
+				out.RawByte(':')
+				if v147Value == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+					out.RawString(`null`)
+				} else {
+					out.RawByte('{')
+					v148First := true
+					for v148Name, v148Value := range v147Value {
+						if v148First {
+							v148First = false
+						} else {
+							out.RawByte(',')
+						}
+						out.String(string(v148Name))
+						out.RawByte(':')
+						out.String(string(v148Value))
+					}
+					out.RawByte('}')
+				}
+			}
+			out.RawByte('}')
+		}
+	}
+	if len(in.Avatars) != 0 {
+		const prefix string = ",\"avatars\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v154First := true
+			for v154Name, v154Value := range in.Avatars {
+				if v154First {
+					v154First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v154Name))
+				out.RawByte(':')
+				out.String(string(v154Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Userlist) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer5(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Userlist) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer5(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Userlist) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer5(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Userlist) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer5(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer6(in *jlexer.Lexer, out *UserAway) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		case "away":
+			out.Away = bool(in.Bool())
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer6(out *jwriter.Writer, in UserAway) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.User != "" {
+		const prefix string = ",\"user\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.User))
+	}
+	if in.Away {
+		const prefix string = ",\"away\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Away))
+	}
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UserAway) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer6(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserAway) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer6(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UserAway) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer6(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserAway) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer6(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer7(in *jlexer.Lexer, out *TopicHistory) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "topics":
+			if in.IsNull() {
+				in.Skip()
+				out.Topics = nil
+			} else {
+				in.Delim('[')
+				if out.Topics == nil {
+					if !in.IsDelim(']') {
+						out.Topics = make([]storage.Topic, 0, 0)
+					} else {
+						out.Topics = []storage.Topic{}
+					}
+				} else {
+					out.Topics = (out.Topics)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v21 storage.Topic
+					easyjson42239ddeDecodeGithubComKhliengDispatchStorage(in, &v21)
+					out.Topics = append(out.Topics, v21)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "next":
+			out.Next = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer7(out *jwriter.Writer, in TopicHistory) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if len(in.Topics) != 0 {
+		const prefix string = ",\"topics\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v22, v23 := range in.Topics {
+				if v22 > 0 {
+					out.RawByte(',')
+				}
+				easyjson42239ddeEncodeGithubComKhliengDispatchStorage(out, v23)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Next != "" {
+		const prefix string = ",\"next\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Next))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v TopicHistory) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer7(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TopicHistory) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer7(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TopicHistory) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer7(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TopicHistory) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer7(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchStorage(in *jlexer.Lexer, out *storage.Topic) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "topic":
+			out.Topic = string(in.String())
+		case "nick":
+			out.Nick = string(in.String())
+		case "time":
+			out.Time = int64(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchStorage(out *jwriter.Writer, in storage.Topic) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Topic != "" {
+		const prefix string = ",\"topic\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Topic))
+	}
+	if in.Nick != "" {
+		const prefix string = ",\"nick\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Nick))
+	}
+	if in.Time != 0 {
+		const prefix string = ",\"time\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Time))
+	}
+	out.RawByte('}')
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer8(in *jlexer.Lexer, out *Topic) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "topic":
+			out.Topic = string(in.String())
+		case "nick":
+			out.Nick = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer8(out *jwriter.Writer, in Topic) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.Topic != "" {
+		const prefix string = ",\"topic\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Topic))
+	}
+	if in.Nick != "" {
+		const prefix string = ",\"nick\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Nick))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Topic) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer8(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Topic) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer8(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Topic) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer8(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Topic) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer8(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer9(in *jlexer.Lexer, out *Tab) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "name":
+			out.Name = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer9(out *jwriter.Writer, in Tab) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Name))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Tab) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer9(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Tab) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer9(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Tab) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer9(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Tab) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer9(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer10(in *jlexer.Lexer, out *ServerName) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "name":
+			out.Name = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer10(out *jwriter.Writer, in ServerName) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Name))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ServerName) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer10(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ServerName) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer10(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ServerName) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer10(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ServerName) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer10(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer11(in *jlexer.Lexer, out *ServerInfoRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer11(out *jwriter.Writer, in ServerInfoRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ServerInfoRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer11(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ServerInfoRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer11(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ServerInfoRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer11(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ServerInfoRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer11(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer12(in *jlexer.Lexer, out *ServerInfo) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "network":
+			out.Network = string(in.String())
+		case "nick":
+			out.Nick = string(in.String())
+		case "modes":
+			out.Modes = string(in.String())
+		case "status":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Status).UnmarshalJSON(data))
+			}
+		case "features":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Features = make(map[string]interface{})
+				} else {
+					out.Features = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v24 interface{}
+					if m, ok := v24.(easyjson.Unmarshaler); ok {
+						m.UnmarshalEasyJSON(in)
+					} else if m, ok := v24.(json.Unmarshaler); ok {
+						_ = m.UnmarshalJSON(in.Raw())
+					} else {
+						v24 = in.Interface()
+					}
+					(out.Features)[key] = v24
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "tls":
+			if in.IsNull() {
+				in.Skip()
+				out.TLS = nil
+			} else {
+				if out.TLS == nil {
+					out.TLS = new(TLSInfo)
+				}
+				(*out.TLS).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer12(out *jwriter.Writer, in ServerInfo) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Network))
+	}
+	if in.Nick != "" {
+		const prefix string = ",\"nick\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Nick))
+	}
+	if in.Modes != "" {
+		const prefix string = ",\"modes\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Modes))
+	}
+	if true {
+		const prefix string = ",\"status\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((in.Status).MarshalJSON())
+	}
+	if len(in.Features) != 0 {
+		const prefix string = ",\"features\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v25First := true
+			for v25Name, v25Value := range in.Features {
+				if v25First {
+					v25First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v25Name))
+				out.RawByte(':')
+				if m, ok := v25Value.(easyjson.Marshaler); ok {
+					m.MarshalEasyJSON(out)
+				} else if m, ok := v25Value.(json.Marshaler); ok {
+					out.Raw(m.MarshalJSON())
+				} else {
+					out.Raw(json.Marshal(v25Value))
+				}
+			}
+			out.RawByte('}')
+		}
+	}
+	if in.TLS != nil {
+		const prefix string = ",\"tls\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.TLS).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ServerInfo) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer12(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ServerInfo) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer12(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ServerInfo) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer12(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ServerInfo) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer12(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer13(in *jlexer.Lexer, out *ServerCommands) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "commands":
+			if in.IsNull() {
+				in.Skip()
+				out.Commands = nil
+			} else {
+				in.Delim('[')
+				if out.Commands == nil {
+					if !in.IsDelim(']') {
+						out.Commands = make([]string, 0, 4)
+					} else {
+						out.Commands = []string{}
+					}
+				} else {
+					out.Commands = (out.Commands)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v26 string
+					v26 = string(in.String())
+					out.Commands = append(out.Commands, v26)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer13(out *jwriter.Writer, in ServerCommands) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if len(in.Commands) != 0 {
+		const prefix string = ",\"commands\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v27, v28 := range in.Commands {
+				if v27 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v28))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ServerCommands) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer13(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ServerCommands) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer13(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ServerCommands) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer13(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ServerCommands) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer13(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer14(in *jlexer.Lexer, out *Server) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	out.Server = new(storage.Server)
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "status":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Status).UnmarshalJSON(data))
+			}
+		case "features":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Features = make(map[string]interface{})
+				} else {
+					out.Features = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v29 interface{}
+					if m, ok := v29.(easyjson.Unmarshaler); ok {
+						m.UnmarshalEasyJSON(in)
+					} else if m, ok := v29.(json.Unmarshaler); ok {
+						_ = m.UnmarshalJSON(in.Raw())
+					} else {
+						v29 = in.Interface()
+					}
+					(out.Features)[key] = v29
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "name":
+			out.Name = string(in.String())
+		case "host":
+			out.Host = string(in.String())
+		case "port":
+			out.Port = string(in.String())
+		case "tls":
+			out.TLS = bool(in.Bool())
+		case "serverPassword":
+			out.ServerPassword = string(in.String())
+		case "nick":
+			out.Nick = string(in.String())
+		case "username":
+			out.Username = string(in.String())
+		case "realname":
+			out.Realname = string(in.String())
+		case "account":
+			out.Account = string(in.String())
+		case "password":
+			out.Password = string(in.String())
+		case "order":
+			out.Order = int32(in.Int32())
+		case "group":
+			out.Group = string(in.String())
+		case "color":
+			out.Color = string(in.String())
+		case "label":
+			out.Label = string(in.String())
+		case "operName":
+			out.OperName = string(in.String())
+		case "operPassword":
+			out.OperPassword = string(in.String())
+		case "commands":
+			if in.IsNull() {
+				in.Skip()
+				out.Commands = nil
+			} else {
+				in.Delim('[')
+				if out.Commands == nil {
+					if !in.IsDelim(']') {
+						out.Commands = make([]string, 0, 4)
+					} else {
+						out.Commands = []string{}
+					}
+				} else {
+					out.Commands = (out.Commands)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v30 string
+					v30 = string(in.String())
+					out.Commands = append(out.Commands, v30)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "forwardTags":
+			if in.IsNull() {
+				in.Skip()
+				out.ForwardTags = nil
+			} else {
+				in.Delim('[')
+				if out.ForwardTags == nil {
+					if !in.IsDelim(']') {
+						out.ForwardTags = make([]string, 0, 4)
+					} else {
+						out.ForwardTags = []string{}
+					}
+				} else {
+					out.ForwardTags = (out.ForwardTags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v102 string
+					v102 = string(in.String())
+					out.ForwardTags = append(out.ForwardTags, v102)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "sendTags":
+			if in.IsNull() {
+				in.Skip()
+				out.SendTags = nil
+			} else {
+				in.Delim('[')
+				if out.SendTags == nil {
+					if !in.IsDelim(']') {
+						out.SendTags = make([]string, 0, 4)
+					} else {
+						out.SendTags = []string{}
+					}
+				} else {
+					out.SendTags = (out.SendTags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v103 string
+					v103 = string(in.String())
+					out.SendTags = append(out.SendTags, v103)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "tlsServerName":
+			out.TLSServerName = string(in.String())
+		case "alpn":
+			if in.IsNull() {
+				in.Skip()
+				out.ALPN = nil
+			} else {
+				in.Delim('[')
+				if out.ALPN == nil {
+					if !in.IsDelim(']') {
+						out.ALPN = make([]string, 0, 4)
+					} else {
+						out.ALPN = []string{}
+					}
+				} else {
+					out.ALPN = (out.ALPN)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v121 string
+					v121 = string(in.String())
+					out.ALPN = append(out.ALPN, v121)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "pinnedCerts":
+			if in.IsNull() {
+				in.Skip()
+				out.PinnedCerts = nil
+			} else {
+				in.Delim('[')
+				if out.PinnedCerts == nil {
+					if !in.IsDelim(']') {
+						out.PinnedCerts = make([]string, 0, 4)
+					} else {
+						out.PinnedCerts = []string{}
+					}
+				} else {
+					out.PinnedCerts = (out.PinnedCerts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v136 string
+					v136 = string(in.String())
+					out.PinnedCerts = append(out.PinnedCerts, v136)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer14(out *jwriter.Writer, in Server) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if true {
+		const prefix string = ",\"status\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Raw((in.Status).MarshalJSON())
+	}
+	if len(in.Features) != 0 {
+		const prefix string = ",\"features\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v31First := true
+			for v31Name, v31Value := range in.Features {
+				if v31First {
+					v31First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v31Name))
+				out.RawByte(':')
+				if m, ok := v31Value.(easyjson.Marshaler); ok {
+					m.MarshalEasyJSON(out)
+				} else if m, ok := v31Value.(json.Marshaler); ok {
+					out.Raw(m.MarshalJSON())
+				} else {
+					out.Raw(json.Marshal(v31Value))
+				}
+			}
+			out.RawByte('}')
+		}
+	}
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Name))
+	}
+	if in.Host != "" {
+		const prefix string = ",\"host\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Host))
+	}
+	if in.Port != "" {
+		const prefix string = ",\"port\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Port))
+	}
+	if in.TLS {
+		const prefix string = ",\"tls\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.TLS))
+	}
+	if in.ServerPassword != "" {
+		const prefix string = ",\"serverPassword\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ServerPassword))
+	}
+	if in.Nick != "" {
+		const prefix string = ",\"nick\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Nick))
+	}
+	if in.Username != "" {
+		const prefix string = ",\"username\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Username))
+	}
+	if in.Realname != "" {
+		const prefix string = ",\"realname\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Realname))
+	}
+	if in.Account != "" {
+		const prefix string = ",\"account\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Account))
+	}
+	if in.Password != "" {
+		const prefix string = ",\"password\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Password))
+	}
+	if in.Order != 0 {
+		const prefix string = ",\"order\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int32(int32(in.Order))
+	}
+	if in.Group != "" {
+		const prefix string = ",\"group\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Group))
+	}
+	if len(in.Commands) != 0 {
+		const prefix string = ",\"commands\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v32, v33 := range in.Commands {
+				if v32 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v33))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Color != "" {
+		const prefix string = ",\"color\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Color))
+	}
+	if in.Label != "" {
+		const prefix string = ",\"label\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Label))
+	}
+	if in.OperName != "" {
+		const prefix string = ",\"operName\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.OperName))
+	}
+	if in.OperPassword != "" {
+		const prefix string = ",\"operPassword\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.OperPassword))
+	}
+	if len(in.ForwardTags) != 0 {
+		const prefix string = ",\"forwardTags\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v104, v105 := range in.ForwardTags {
+				if v104 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v105))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.SendTags) != 0 {
+		const prefix string = ",\"sendTags\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v106, v107 := range in.SendTags {
+				if v106 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v107))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.TLSServerName != "" {
+		const prefix string = ",\"tlsServerName\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.TLSServerName))
+	}
+	if len(in.ALPN) != 0 {
+		const prefix string = ",\"alpn\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v122, v123 := range in.ALPN {
+				if v122 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v123))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.PinnedCerts) != 0 {
+		const prefix string = ",\"pinnedCerts\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v137, v138 := range in.PinnedCerts {
+				if v137 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v138))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Server) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer14(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Server) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer14(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Server) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer14(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Server) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer14(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer15(in *jlexer.Lexer, out *SearchResult) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "results":
+			if in.IsNull() {
+				in.Skip()
+				out.Results = nil
+			} else {
+				in.Delim('[')
+				if out.Results == nil {
+					if !in.IsDelim(']') {
+						out.Results = make([]storage.Message, 0, 0)
+					} else {
+						out.Results = []storage.Message{}
+					}
+				} else {
+					out.Results = (out.Results)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v34 storage.Message
+					easyjson42239ddeDecodeGithubComKhliengDispatchStorage1(in, &v34)
+					out.Results = append(out.Results, v34)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "matches":
+			if in.IsNull() {
+				in.Skip()
+				out.Matches = nil
+			} else {
+				in.Delim('[')
+				if out.Matches == nil {
+					if !in.IsDelim(']') {
+						out.Matches = make([]storage.SearchMatch, 0, 0)
+					} else {
+						out.Matches = []storage.SearchMatch{}
+					}
+				} else {
+					out.Matches = (out.Matches)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v37 storage.SearchMatch
+					easyjson42239ddeDecodeGithubComKhliengDispatchStorage5(in, &v37)
+					out.Matches = append(out.Matches, v37)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "offset":
+			out.Offset = int(in.Int())
+		case "total":
+			out.Total = uint64(in.Uint64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer15(out *jwriter.Writer, in SearchResult) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if len(in.Results) != 0 {
+		const prefix string = ",\"results\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v35, v36 := range in.Results {
+				if v35 > 0 {
+					out.RawByte(',')
+				}
+				easyjson42239ddeEncodeGithubComKhliengDispatchStorage1(out, v36)
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Matches) != 0 {
+		const prefix string = ",\"matches\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v38, v39 := range in.Matches {
+				if v38 > 0 {
+					out.RawByte(',')
+				}
+				easyjson42239ddeEncodeGithubComKhliengDispatchStorage5(out, v39)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Offset != 0 {
+		const prefix string = ",\"offset\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Offset))
+	}
+	if in.Total != 0 {
+		const prefix string = ",\"total\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint64(uint64(in.Total))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SearchResult) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer15(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SearchResult) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer15(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SearchResult) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer15(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SearchResult) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer15(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchStorage1(in *jlexer.Lexer, out *storage.Message) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "from":
+			out.From = string(in.String())
+		case "account":
+			out.Account = string(in.String())
+		case "content":
+			out.Content = string(in.String())
+		case "time":
+			out.Time = int64(in.Int64())
+		case "events":
+			if in.IsNull() {
+				in.Skip()
+				out.Events = nil
+			} else {
+				in.Delim('[')
+				if out.Events == nil {
+					if !in.IsDelim(']') {
+						out.Events = make([]storage.Event, 0, 1)
+					} else {
+						out.Events = []storage.Event{}
+					}
+				} else {
+					out.Events = (out.Events)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v37 storage.Event
+					easyjson42239ddeDecodeGithubComKhliengDispatchStorage2(in, &v37)
+					out.Events = append(out.Events, v37)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "msgID":
+			out.MsgID = string(in.String())
+		case "replyTo":
+			out.ReplyTo = string(in.String())
+		case "mention":
+			out.Mention = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchStorage1(out *jwriter.Writer, in storage.Message) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	if in.From != "" {
+		const prefix string = ",\"from\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.From))
+	}
+	if in.Account != "" {
+		const prefix string = ",\"account\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Account))
+	}
+	if in.Content != "" {
+		const prefix string = ",\"content\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Content))
+	}
+	if in.Time != 0 {
+		const prefix string = ",\"time\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Time))
+	}
+	if len(in.Events) != 0 {
+		const prefix string = ",\"events\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v38, v39 := range in.Events {
+				if v38 > 0 {
+					out.RawByte(',')
+				}
+				easyjson42239ddeEncodeGithubComKhliengDispatchStorage2(out, v39)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.MsgID != "" {
+		const prefix string = ",\"msgID\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.MsgID))
+	}
+	if in.ReplyTo != "" {
+		const prefix string = ",\"replyTo\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ReplyTo))
+	}
+	if in.Mention {
+		const prefix string = ",\"mention\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Mention))
+	}
+	out.RawByte('}')
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchStorage5(in *jlexer.Lexer, out *storage.SearchMatch) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "message":
+			easyjson42239ddeDecodeGithubComKhliengDispatchStorage1(in, &out.Message)
+		case "snippet":
+			easyjson42239ddeDecodeGithubComKhliengDispatchStorage6(in, &out.Snippet)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchStorage5(out *jwriter.Writer, in storage.SearchMatch) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix[1:])
+		easyjson42239ddeEncodeGithubComKhliengDispatchStorage1(out, in.Message)
+	}
+	{
+		const prefix string = ",\"snippet\":"
+		out.RawString(prefix)
+		easyjson42239ddeEncodeGithubComKhliengDispatchStorage6(out, in.Snippet)
+	}
+	out.RawByte('}')
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchStorage6(in *jlexer.Lexer, out *storage.Snippet) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "text":
+			out.Text = string(in.String())
+		case "start":
+			out.Start = int(in.Int())
+		case "highlights":
+			if in.IsNull() {
+				in.Skip()
+				out.Highlights = nil
+			} else {
+				in.Delim('[')
+				if out.Highlights == nil {
+					if !in.IsDelim(']') {
+						out.Highlights = make([]storage.Highlight, 0, 4)
+					} else {
+						out.Highlights = []storage.Highlight{}
+					}
+				} else {
+					out.Highlights = (out.Highlights)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v40 storage.Highlight
+					easyjson42239ddeDecodeGithubComKhliengDispatchStorage7(in, &v40)
+					out.Highlights = append(out.Highlights, v40)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchStorage6(out *jwriter.Writer, in storage.Snippet) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Text != "" {
+		const prefix string = ",\"text\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Text))
+	}
+	if in.Start != 0 {
+		const prefix string = ",\"start\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Start))
+	}
+	if len(in.Highlights) != 0 {
+		const prefix string = ",\"highlights\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v41, v42 := range in.Highlights {
+				if v41 > 0 {
+					out.RawByte(',')
+				}
+				easyjson42239ddeEncodeGithubComKhliengDispatchStorage7(out, v42)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchStorage7(in *jlexer.Lexer, out *storage.Highlight) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "start":
+			out.Start = int(in.Int())
+		case "end":
+			out.End = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchStorage7(out *jwriter.Writer, in storage.Highlight) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"start\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Start))
+	}
+	{
+		const prefix string = ",\"end\":"
+		out.RawString(prefix)
+		out.Int(int(in.End))
+	}
+	out.RawByte('}')
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchStorage2(in *jlexer.Lexer, out *storage.Event) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "type":
+			out.Type = string(in.String())
+		case "params":
+			if in.IsNull() {
+				in.Skip()
+				out.Params = nil
+			} else {
+				in.Delim('[')
+				if out.Params == nil {
+					if !in.IsDelim(']') {
+						out.Params = make([]string, 0, 4)
+					} else {
+						out.Params = []string{}
+					}
+				} else {
+					out.Params = (out.Params)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v40 string
+					v40 = string(in.String())
+					out.Params = append(out.Params, v40)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "time":
+			out.Time = int64(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchStorage2(out *jwriter.Writer, in storage.Event) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Type != "" {
+		const prefix string = ",\"type\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Type))
+	}
+	if len(in.Params) != 0 {
+		const prefix string = ",\"params\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v41, v42 := range in.Params {
+				if v41 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v42))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Time != 0 {
+		const prefix string = ",\"time\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Time))
+	}
+	out.RawByte('}')
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer16(in *jlexer.Lexer, out *SearchRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "phrase":
+			out.Phrase = string(in.String())
+		case "snippets":
+			out.Snippets = bool(in.Bool())
+		case "offset":
+			out.Offset = int(in.Int())
+		case "limit":
+			out.Limit = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer16(out *jwriter.Writer, in SearchRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.Phrase != "" {
+		const prefix string = ",\"phrase\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Phrase))
+	}
+	if in.Snippets {
+		const prefix string = ",\"snippets\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Snippets))
+	}
+	if in.Offset != 0 {
+		const prefix string = ",\"offset\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Offset))
+	}
+	if in.Limit != 0 {
+		const prefix string = ",\"limit\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Limit))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SearchRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer16(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SearchRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer16(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SearchRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer16(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SearchRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer16(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer17(in *jlexer.Lexer, out *ReconnectSettings) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "skipVerify":
+			out.SkipVerify = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer17(out *jwriter.Writer, in ReconnectSettings) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.SkipVerify {
+		const prefix string = ",\"skipVerify\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.SkipVerify))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ReconnectSettings) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer17(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ReconnectSettings) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer17(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ReconnectSettings) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer17(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ReconnectSettings) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer17(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer18(in *jlexer.Lexer, out *RawLog) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "enabled":
+			out.Enabled = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer18(out *jwriter.Writer, in RawLog) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Enabled {
+		const prefix string = ",\"enabled\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Enabled))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RawLog) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer18(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RawLog) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer18(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RawLog) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer18(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RawLog) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer18(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer19(in *jlexer.Lexer, out *Raw) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer19(out *jwriter.Writer, in Raw) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Raw) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer19(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Raw) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer19(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Raw) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer19(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Raw) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer19(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer20(in *jlexer.Lexer, out *Quit) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		case "reason":
+			out.Reason = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer20(out *jwriter.Writer, in Quit) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.User != "" {
+		const prefix string = ",\"user\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.User))
+	}
+	if in.Reason != "" {
+		const prefix string = ",\"reason\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Reason))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Quit) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer20(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Quit) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer20(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Quit) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer20(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Quit) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer20(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer21(in *jlexer.Lexer, out *Part) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "channels":
+			if in.IsNull() {
+				in.Skip()
+				out.Channels = nil
+			} else {
+				in.Delim('[')
+				if out.Channels == nil {
+					if !in.IsDelim(']') {
+						out.Channels = make([]string, 0, 4)
+					} else {
+						out.Channels = []string{}
+					}
+				} else {
+					out.Channels = (out.Channels)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v43 string
+					v43 = string(in.String())
+					out.Channels = append(out.Channels, v43)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "reason":
+			out.Reason = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer21(out *jwriter.Writer, in Part) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.User != "" {
+		const prefix string = ",\"user\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.User))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if len(in.Channels) != 0 {
+		const prefix string = ",\"channels\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v44, v45 := range in.Channels {
+				if v44 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v45))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Reason != "" {
+		const prefix string = ",\"reason\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Reason))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Part) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer21(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Part) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer21(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Part) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer21(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Part) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer21(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer22(in *jlexer.Lexer, out *Order) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "placements":
+			if in.IsNull() {
+				in.Skip()
+				out.Placements = nil
+			} else {
+				in.Delim('[')
+				if out.Placements == nil {
+					if !in.IsDelim(']') {
+						out.Placements = make([]storage.Placement, 0, 1)
+					} else {
+						out.Placements = []storage.Placement{}
+					}
+				} else {
+					out.Placements = (out.Placements)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v46 storage.Placement
+					easyjson42239ddeDecodeGithubComKhliengDispatchStorage3(in, &v46)
+					out.Placements = append(out.Placements, v46)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer22(out *jwriter.Writer, in Order) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Placements) != 0 {
+		const prefix string = ",\"placements\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('[')
+			for v47, v48 := range in.Placements {
+				if v47 > 0 {
+					out.RawByte(',')
+				}
+				easyjson42239ddeEncodeGithubComKhliengDispatchStorage3(out, v48)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Order) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer22(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Order) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer22(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Order) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer22(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Order) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer22(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchStorage3(in *jlexer.Lexer, out *storage.Placement) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "order":
+			out.Order = int32(in.Int32())
+		case "group":
+			out.Group = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchStorage3(out *jwriter.Writer, in storage.Placement) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.Order != 0 {
+		const prefix string = ",\"order\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int32(int32(in.Order))
+	}
+	if in.Group != "" {
+		const prefix string = ",\"group\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Group))
+	}
+	out.RawByte('}')
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer23(in *jlexer.Lexer, out *NickFail) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer23(out *jwriter.Writer, in NickFail) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v NickFail) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer23(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v NickFail) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer23(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *NickFail) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer23(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *NickFail) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer23(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer24(in *jlexer.Lexer, out *Nick) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "oldNick":
+			out.Old = string(in.String())
+		case "newNick":
+			out.New = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer24(out *jwriter.Writer, in Nick) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Old != "" {
+		const prefix string = ",\"oldNick\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Old))
+	}
+	if in.New != "" {
+		const prefix string = ",\"newNick\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.New))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Nick) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer24(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Nick) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer24(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Nick) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer24(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Nick) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer24(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer25(in *jlexer.Lexer, out *Netsplit) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "servers":
+			if in.IsNull() {
+				in.Skip()
+				out.Servers = nil
+			} else {
+				in.Delim('[')
+				if out.Servers == nil {
+					if !in.IsDelim(']') {
+						out.Servers = make([]string, 0, 4)
+					} else {
+						out.Servers = []string{}
+					}
+				} else {
+					out.Servers = (out.Servers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v49 string
+					v49 = string(in.String())
+					out.Servers = append(out.Servers, v49)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "users":
+			if in.IsNull() {
+				in.Skip()
+				out.Users = nil
+			} else {
+				in.Delim('[')
+				if out.Users == nil {
+					if !in.IsDelim(']') {
+						out.Users = make([]string, 0, 4)
+					} else {
+						out.Users = []string{}
+					}
+				} else {
+					out.Users = (out.Users)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v50 string
+					v50 = string(in.String())
+					out.Users = append(out.Users, v50)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer25(out *jwriter.Writer, in Netsplit) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if len(in.Servers) != 0 {
+		const prefix string = ",\"servers\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v51, v52 := range in.Servers {
+				if v51 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v52))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Users) != 0 {
+		const prefix string = ",\"users\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v53, v54 := range in.Users {
+				if v53 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v54))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Netsplit) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer25(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Netsplit) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer25(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Netsplit) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer25(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Netsplit) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer25(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer26(in *jlexer.Lexer, out *Netjoin) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "servers":
+			if in.IsNull() {
+				in.Skip()
+				out.Servers = nil
+			} else {
+				in.Delim('[')
+				if out.Servers == nil {
+					if !in.IsDelim(']') {
+						out.Servers = make([]string, 0, 4)
+					} else {
+						out.Servers = []string{}
+					}
+				} else {
+					out.Servers = (out.Servers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v55 string
+					v55 = string(in.String())
+					out.Servers = append(out.Servers, v55)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "channels":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Channels = make(map[string][]string)
+				} else {
+					out.Channels = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v56 []string
+					if in.IsNull() {
+						in.Skip()
+						v56 = nil
+					} else {
+						in.Delim('[')
+						if v56 == nil {
+							if !in.IsDelim(']') {
+								v56 = make([]string, 0, 4)
+							} else {
+								v56 = []string{}
+							}
+						} else {
+							v56 = (v56)[:0]
+						}
+						for !in.IsDelim(']') {
+							var v57 string
+							v57 = string(in.String())
+							v56 = append(v56, v57)
+							in.WantComma()
+						}
+						in.Delim(']')
+					}
+					(out.Channels)[key] = v56
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer26(out *jwriter.Writer, in Netjoin) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if len(in.Servers) != 0 {
+		const prefix string = ",\"servers\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v58, v59 := range in.Servers {
+				if v58 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v59))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Channels) != 0 {
+		const prefix string = ",\"channels\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v60First := true
+			for v60Name, v60Value := range in.Channels {
+				if v60First {
+					v60First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v60Name))
+				out.RawByte(':')
+				if v60Value == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+					out.RawString("null")
+				} else {
+					out.RawByte('[')
+					for v61, v62 := range v60Value {
+						if v61 > 0 {
+							out.RawByte(',')
+						}
+						out.String(string(v62))
+					}
+					out.RawByte(']')
+				}
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Netjoin) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer26(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Netjoin) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer26(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Netjoin) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer26(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Netjoin) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer26(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer27(in *jlexer.Lexer, out *Mode) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	out.Mode = new(irc.Mode)
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		case "add":
+			out.Add = string(in.String())
+		case "remove":
+			out.Remove = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer27(out *jwriter.Writer, in Mode) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.User != "" {
+		const prefix string = ",\"user\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.User))
+	}
+	if in.Add != "" {
+		const prefix string = ",\"add\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Add))
+	}
+	if in.Remove != "" {
+		const prefix string = ",\"remove\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Remove))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Mode) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer27(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Mode) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer27(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Mode) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer27(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Mode) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer27(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer28(in *jlexer.Lexer, out *Messages) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "to":
+			out.To = string(in.String())
+		case "messages":
+			if in.IsNull() {
+				in.Skip()
+				out.Messages = nil
+			} else {
+				in.Delim('[')
+				if out.Messages == nil {
+					if !in.IsDelim(']') {
+						out.Messages = make([]storage.Message, 0, 0)
+					} else {
+						out.Messages = []storage.Message{}
+					}
+				} else {
+					out.Messages = (out.Messages)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v63 storage.Message
+					easyjson42239ddeDecodeGithubComKhliengDispatchStorage1(in, &v63)
+					out.Messages = append(out.Messages, v63)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "prepend":
+			out.Prepend = bool(in.Bool())
+		case "next":
+			out.Next = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer28(out *jwriter.Writer, in Messages) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.To != "" {
+		const prefix string = ",\"to\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.To))
+	}
+	if len(in.Messages) != 0 {
+		const prefix string = ",\"messages\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v64, v65 := range in.Messages {
+				if v64 > 0 {
+					out.RawByte(',')
+				}
+				easyjson42239ddeEncodeGithubComKhliengDispatchStorage1(out, v65)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Prepend {
+		const prefix string = ",\"prepend\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Prepend))
+	}
+	if in.Next != "" {
+		const prefix string = ",\"next\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Next))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Messages) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer28(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Messages) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer28(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Messages) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer28(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Messages) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer28(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer29(in *jlexer.Lexer, out *MessageContext) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "to":
+			out.To = string(in.String())
+		case "id":
+			out.ID = string(in.String())
+		case "messages":
+			if in.IsNull() {
+				in.Skip()
+				out.Messages = nil
+			} else {
+				in.Delim('[')
+				if out.Messages == nil {
+					if !in.IsDelim(']') {
+						out.Messages = make([]storage.Message, 0, 0)
+					} else {
+						out.Messages = []storage.Message{}
+					}
+				} else {
+					out.Messages = (out.Messages)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v66 storage.Message
+					easyjson42239ddeDecodeGithubComKhliengDispatchStorage1(in, &v66)
+					out.Messages = append(out.Messages, v66)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer29(out *jwriter.Writer, in MessageContext) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.To != "" {
+		const prefix string = ",\"to\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.To))
+	}
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ID))
+	}
+	if len(in.Messages) != 0 {
+		const prefix string = ",\"messages\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v67, v68 := range in.Messages {
+				if v67 > 0 {
+					out.RawByte(',')
+				}
+				easyjson42239ddeEncodeGithubComKhliengDispatchStorage1(out, v68)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MessageContext) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer29(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MessageContext) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer29(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MessageContext) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer29(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MessageContext) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer29(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer30(in *jlexer.Lexer, out *Message) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "server":
+			out.Server = string(in.String())
+		case "from":
+			out.From = string(in.String())
+		case "to":
+			out.To = string(in.String())
+		case "account":
+			out.Account = string(in.String())
+		case "content":
+			out.Content = string(in.String())
+		case "type":
+			out.Type = string(in.String())
+		case "statusMsg":
+			out.StatusMsg = string(in.String())
+		case "away":
+			out.Away = bool(in.Bool())
+		case "msgID":
+			out.MsgID = string(in.String())
+		case "replyTo":
+			out.ReplyTo = string(in.String())
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Tags = make(map[string]string)
+				} else {
+					out.Tags = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v100 string
+					v100 = string(in.String())
+					(out.Tags)[key] = v100
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "avatar":
+			out.Avatar = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer30(out *jwriter.Writer, in Message) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Server))
+	}
+	if in.From != "" {
+		const prefix string = ",\"from\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.From))
+	}
+	if in.To != "" {
+		const prefix string = ",\"to\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.To))
+	}
+	if in.Account != "" {
+		const prefix string = ",\"account\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Account))
+	}
+	if in.Content != "" {
+		const prefix string = ",\"content\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Content))
+	}
+	if in.Type != "" {
+		const prefix string = ",\"type\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Type))
+	}
+	if in.StatusMsg != "" {
+		const prefix string = ",\"statusMsg\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.StatusMsg))
+	}
+	if in.Away {
+		const prefix string = ",\"away\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Away))
+	}
+	if in.MsgID != "" {
+		const prefix string = ",\"msgID\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.MsgID))
+	}
+	if in.ReplyTo != "" {
+		const prefix string = ",\"replyTo\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ReplyTo))
+	}
+	if len(in.Tags) != 0 {
+		const prefix string = ",\"tags\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v101First := true
+			for v101Name, v101Value := range in.Tags {
+				if v101First {
+					v101First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v101Name))
+				out.RawByte(':')
+				out.String(string(v101Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	if in.Avatar != "" {
+		const prefix string = ",\"avatar\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Avatar))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Message) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer30(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Message) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer30(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Message) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer30(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Message) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer30(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer31(in *jlexer.Lexer, out *MOTDRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer31(out *jwriter.Writer, in MOTDRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MOTDRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer31(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MOTDRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer31(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MOTDRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer31(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MOTDRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer31(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer32(in *jlexer.Lexer, out *MOTD) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "title":
+			out.Title = string(in.String())
+		case "content":
+			if in.IsNull() {
+				in.Skip()
+				out.Content = nil
+			} else {
+				in.Delim('[')
+				if out.Content == nil {
+					if !in.IsDelim(']') {
+						out.Content = make([]string, 0, 4)
+					} else {
+						out.Content = []string{}
+					}
+				} else {
+					out.Content = (out.Content)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v69 string
+					v69 = string(in.String())
+					out.Content = append(out.Content, v69)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "missing":
+			out.Missing = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer32(out *jwriter.Writer, in MOTD) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Title != "" {
+		const prefix string = ",\"title\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Title))
+	}
+	if len(in.Content) != 0 {
+		const prefix string = ",\"content\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v70, v71 := range in.Content {
+				if v70 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v71))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Missing {
+		const prefix string = ",\"missing\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Missing))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MOTD) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer32(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MOTD) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer32(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MOTD) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer32(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MOTD) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer32(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer33(in *jlexer.Lexer, out *LinkPreview) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "target":
+			out.Target = string(in.String())
+		case "messageID":
+			out.MessageID = string(in.String())
+		case "url":
+			out.URL = string(in.String())
+		case "siteName":
+			out.SiteName = string(in.String())
+		case "color":
+			out.Color = string(in.String())
+		case "title":
+			out.Title = string(in.String())
+		case "description":
+			out.Description = string(in.String())
+		case "imageURL":
+			out.ImageURL = string(in.String())
+		case "videoURL":
+			out.VideoURL = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer33(out *jwriter.Writer, in LinkPreview) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Target != "" {
+		const prefix string = ",\"target\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Target))
+	}
+	if in.MessageID != "" {
+		const prefix string = ",\"messageID\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.MessageID))
+	}
+	if in.URL != "" {
+		const prefix string = ",\"url\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.URL))
+	}
+	if in.SiteName != "" {
+		const prefix string = ",\"siteName\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.SiteName))
+	}
+	if in.Color != "" {
+		const prefix string = ",\"color\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Color))
+	}
+	if in.Title != "" {
+		const prefix string = ",\"title\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Title))
+	}
+	if in.Description != "" {
+		const prefix string = ",\"description\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Description))
+	}
+	if in.ImageURL != "" {
+		const prefix string = ",\"imageURL\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ImageURL))
+	}
+	if in.VideoURL != "" {
+		const prefix string = ",\"videoURL\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.VideoURL))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v LinkPreview) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer33(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v LinkPreview) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer33(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *LinkPreview) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer33(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *LinkPreview) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer33(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer34(in *jlexer.Lexer, out *Kick) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer34(out *jwriter.Writer, in Kick) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.User != "" {
+		const prefix string = ",\"user\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.User))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Kick) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer34(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Kick) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer34(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Kick) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer34(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Kick) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer34(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer35(in *jlexer.Lexer, out *Join) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		case "channels":
+			if in.IsNull() {
+				in.Skip()
+				out.Channels = nil
+			} else {
+				in.Delim('[')
+				if out.Channels == nil {
+					if !in.IsDelim(']') {
+						out.Channels = make([]string, 0, 4)
+					} else {
+						out.Channels = []string{}
+					}
+				} else {
+					out.Channels = (out.Channels)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v72 string
+					v72 = string(in.String())
+					out.Channels = append(out.Channels, v72)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "away":
+			out.Away = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer35(out *jwriter.Writer, in Join) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.User != "" {
+		const prefix string = ",\"user\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.User))
+	}
+	if len(in.Channels) != 0 {
+		const prefix string = ",\"channels\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v73, v74 := range in.Channels {
+				if v73 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v74))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Away {
+		const prefix string = ",\"away\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Away))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Join) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer35(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Join) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer35(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Join) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer35(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Join) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer35(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer36(in *jlexer.Lexer, out *Invite) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer36(out *jwriter.Writer, in Invite) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.User != "" {
+		const prefix string = ",\"user\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.User))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Invite) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer36(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Invite) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer36(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Invite) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer36(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Invite) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer36(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer37(in *jlexer.Lexer, out *IRCError) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "target":
+			out.Target = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer37(out *jwriter.Writer, in IRCError) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Target != "" {
+		const prefix string = ",\"target\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Target))
+	}
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v IRCError) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer37(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v IRCError) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer37(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *IRCError) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer37(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *IRCError) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer37(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer38(in *jlexer.Lexer, out *FetchUsers) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "offset":
+			out.Offset = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer38(out *jwriter.Writer, in FetchUsers) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.Offset != 0 {
+		const prefix string = ",\"offset\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Offset))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FetchUsers) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer38(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FetchUsers) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer38(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FetchUsers) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer38(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FetchUsers) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer38(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer39(in *jlexer.Lexer, out *FetchTopics) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "next":
+			out.Next = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer39(out *jwriter.Writer, in FetchTopics) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.Next != "" {
+		const prefix string = ",\"next\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Next))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FetchTopics) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer39(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FetchTopics) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer39(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FetchTopics) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer39(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FetchTopics) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer39(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer40(in *jlexer.Lexer, out *FetchMessages) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "next":
+			out.Next = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer40(out *jwriter.Writer, in FetchMessages) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.Next != "" {
+		const prefix string = ",\"next\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Next))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FetchMessages) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer40(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FetchMessages) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer40(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FetchMessages) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer40(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FetchMessages) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer40(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer41(in *jlexer.Lexer, out *FetchMessageContext) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "id":
+			out.ID = string(in.String())
+		case "count":
+			out.Count = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer41(out *jwriter.Writer, in FetchMessageContext) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ID))
+	}
+	if in.Count != 0 {
+		const prefix string = ",\"count\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Count))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FetchMessageContext) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer41(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FetchMessageContext) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer41(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FetchMessageContext) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer41(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FetchMessageContext) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer41(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer42(in *jlexer.Lexer, out *Features) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "features":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Features = make(map[string]interface{})
+				} else {
+					out.Features = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v75 interface{}
+					if m, ok := v75.(easyjson.Unmarshaler); ok {
+						m.UnmarshalEasyJSON(in)
+					} else if m, ok := v75.(json.Unmarshaler); ok {
+						_ = m.UnmarshalJSON(in.Raw())
+					} else {
+						v75 = in.Interface()
+					}
+					(out.Features)[key] = v75
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer42(out *jwriter.Writer, in Features) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if len(in.Features) != 0 {
+		const prefix string = ",\"features\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v76First := true
+			for v76Name, v76Value := range in.Features {
+				if v76First {
+					v76First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v76Name))
+				out.RawByte(':')
+				if m, ok := v76Value.(easyjson.Marshaler); ok {
+					m.MarshalEasyJSON(out)
+				} else if m, ok := v76Value.(json.Marshaler); ok {
+					out.Raw(m.MarshalJSON())
+				} else {
+					out.Raw(json.Marshal(v76Value))
+				}
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Features) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer42(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Features) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer42(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Features) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer42(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Features) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer42(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer43(in *jlexer.Lexer, out *Error) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer43(out *jwriter.Writer, in Error) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Error) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer43(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Error) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer43(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Error) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer43(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Error) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer43(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer44(in *jlexer.Lexer, out *DCCSend) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "from":
+			out.From = string(in.String())
+		case "filename":
+			out.Filename = string(in.String())
+		case "url":
+			out.URL = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer44(out *jwriter.Writer, in DCCSend) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.From != "" {
+		const prefix string = ",\"from\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.From))
+	}
+	if in.Filename != "" {
+		const prefix string = ",\"filename\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Filename))
+	}
+	if in.URL != "" {
+		const prefix string = ",\"url\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.URL))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v DCCSend) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer44(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v DCCSend) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer44(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *DCCSend) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer44(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *DCCSend) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer44(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer45(in *jlexer.Lexer, out *ConnectionUpdate) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "connected":
+			out.Connected = bool(in.Bool())
+		case "error":
+			out.Error = string(in.String())
+		case "errorType":
+			out.ErrorType = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer45(out *jwriter.Writer, in ConnectionUpdate) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Connected {
+		const prefix string = ",\"connected\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Connected))
+	}
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Error))
+	}
+	if in.ErrorType != "" {
+		const prefix string = ",\"errorType\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ErrorType))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ConnectionUpdate) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer45(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ConnectionUpdate) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer45(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ConnectionUpdate) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer45(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ConnectionUpdate) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer45(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer46(in *jlexer.Lexer, out *Command) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "command":
+			out.Command = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer46(out *jwriter.Writer, in Command) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.Command != "" {
+		const prefix string = ",\"command\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Command))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Command) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer46(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Command) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer46(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Command) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer46(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Command) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer46(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer47(in *jlexer.Lexer, out *ClientCert) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "cert":
+			out.Cert = string(in.String())
+		case "key":
+			out.Key = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer47(out *jwriter.Writer, in ClientCert) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Cert != "" {
+		const prefix string = ",\"cert\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Cert))
+	}
+	if in.Key != "" {
+		const prefix string = ",\"key\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Key))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ClientCert) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer47(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ClientCert) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer47(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ClientCert) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer47(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ClientCert) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer47(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer48(in *jlexer.Lexer, out *ChannelSearchResult) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "results":
+			if in.IsNull() {
+				in.Skip()
+				out.Results = nil
+			} else {
+				in.Delim('[')
+				if out.Results == nil {
+					if !in.IsDelim(']') {
+						out.Results = make([]*storage.ChannelListItem, 0, 8)
+					} else {
+						out.Results = []*storage.ChannelListItem{}
+					}
+				} else {
+					out.Results = (out.Results)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v77 *storage.ChannelListItem
+					if in.IsNull() {
+						in.Skip()
+						v77 = nil
+					} else {
+						if v77 == nil {
+							v77 = new(storage.ChannelListItem)
+						}
+						easyjson42239ddeDecodeGithubComKhliengDispatchStorage4(in, v77)
+					}
+					out.Results = append(out.Results, v77)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "server":
+			out.Server = string(in.String())
+		case "q":
+			out.Q = string(in.String())
+		case "start":
+			out.Start = int(in.Int())
+		case "fuzzy":
+			out.Fuzzy = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer48(out *jwriter.Writer, in ChannelSearchResult) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Results) != 0 {
+		const prefix string = ",\"results\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('[')
+			for v78, v79 := range in.Results {
+				if v78 > 0 {
+					out.RawByte(',')
+				}
+				if v79 == nil {
+					out.RawString("null")
+				} else {
+					easyjson42239ddeEncodeGithubComKhliengDispatchStorage4(out, *v79)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Server))
+	}
+	if in.Q != "" {
+		const prefix string = ",\"q\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Q))
+	}
+	if in.Start != 0 {
+		const prefix string = ",\"start\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Start))
+	}
+	if in.Fuzzy {
+		const prefix string = ",\"fuzzy\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Fuzzy))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ChannelSearchResult) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer48(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ChannelSearchResult) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer48(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ChannelSearchResult) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer48(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ChannelSearchResult) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer48(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchStorage4(in *jlexer.Lexer, out *storage.ChannelListItem) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "userCount":
+			out.UserCount = int(in.Int())
+		case "topic":
+			out.Topic = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchStorage4(out *jwriter.Writer, in storage.ChannelListItem) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	if in.UserCount != 0 {
+		const prefix string = ",\"userCount\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.UserCount))
+	}
+	if in.Topic != "" {
+		const prefix string = ",\"topic\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Topic))
+	}
+	out.RawByte('}')
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer49(in *jlexer.Lexer, out *ChannelSearch) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "q":
+			out.Q = string(in.String())
+		case "start":
+			out.Start = int(in.Int())
+		case "fuzzy":
+			out.Fuzzy = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer49(out *jwriter.Writer, in ChannelSearch) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Q != "" {
+		const prefix string = ",\"q\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Q))
+	}
+	if in.Start != 0 {
+		const prefix string = ",\"start\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Start))
+	}
+	if in.Fuzzy {
+		const prefix string = ",\"fuzzy\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Fuzzy))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ChannelSearch) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer49(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ChannelSearch) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer49(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ChannelSearch) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer49(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ChannelSearch) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer49(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer50(in *jlexer.Lexer, out *ChannelListProgress) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "count":
+			out.Count = int(in.Int())
+		case "done":
+			out.Done = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer50(out *jwriter.Writer, in ChannelListProgress) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Count != 0 {
+		const prefix string = ",\"count\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Count))
+	}
+	if in.Done {
+		const prefix string = ",\"done\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Done))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ChannelListProgress) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer50(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ChannelListProgress) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer50(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ChannelListProgress) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer50(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ChannelListProgress) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer50(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer51(in *jlexer.Lexer, out *ChannelForward) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "old":
+			out.Old = string(in.String())
+		case "new":
+			out.New = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer51(out *jwriter.Writer, in ChannelForward) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Old != "" {
+		const prefix string = ",\"old\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Old))
+	}
+	if in.New != "" {
+		const prefix string = ",\"new\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.New))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ChannelForward) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer51(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ChannelForward) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer51(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ChannelForward) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer51(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ChannelForward) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer51(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer52(in *jlexer.Lexer, out *CTCPReply) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "from":
+			out.From = string(in.String())
+		case "command":
+			out.Command = string(in.String())
+		case "reply":
+			out.Reply = string(in.String())
+		case "latency":
+			out.Latency = int64(in.Int64())
+		case "timeout":
+			out.Timeout = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer52(out *jwriter.Writer, in CTCPReply) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.From != "" {
+		const prefix string = ",\"from\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.From))
+	}
+	if in.Command != "" {
+		const prefix string = ",\"command\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Command))
+	}
+	if in.Reply != "" {
+		const prefix string = ",\"reply\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Reply))
+	}
+	if in.Latency != 0 {
+		const prefix string = ",\"latency\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Latency))
+	}
+	if in.Timeout {
+		const prefix string = ",\"timeout\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Timeout))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v CTCPReply) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer52(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v CTCPReply) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer52(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *CTCPReply) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer52(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *CTCPReply) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer52(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer53(in *jlexer.Lexer, out *CTCP) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "target":
+			out.Target = string(in.String())
+		case "command":
+			out.Command = string(in.String())
+		case "params":
+			out.Params = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer53(out *jwriter.Writer, in CTCP) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Target != "" {
+		const prefix string = ",\"target\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Target))
+	}
+	if in.Command != "" {
+		const prefix string = ",\"command\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Command))
+	}
+	if in.Params != "" {
+		const prefix string = ",\"params\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Params))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v CTCP) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer53(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v CTCP) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer53(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *CTCP) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer53(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *CTCP) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer53(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer54(in *jlexer.Lexer, out *Away) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer54(out *jwriter.Writer, in Away) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Away) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer54(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Away) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer54(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Away) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer54(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Away) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer54(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer55(in *jlexer.Lexer, out *AlwaysOn) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "enabled":
+			out.Enabled = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer55(out *jwriter.Writer, in AlwaysOn) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Enabled {
+		const prefix string = ",\"enabled\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.Enabled))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v AlwaysOn) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer55(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v AlwaysOn) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer55(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *AlwaysOn) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer55(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *AlwaysOn) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer55(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer56(in *jlexer.Lexer, out *Aliases) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "aliases":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Aliases = make(map[string]string)
+				} else {
+					out.Aliases = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v80 string
+					v80 = string(in.String())
+					(out.Aliases)[key] = v80
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer56(out *jwriter.Writer, in Aliases) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Aliases) != 0 {
+		const prefix string = ",\"aliases\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('{')
+			v81First := true
+			for v81Name, v81Value := range in.Aliases {
+				if v81First {
+					v81First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v81Name))
+				out.RawByte(':')
+				out.String(string(v81Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Aliases) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer56(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Aliases) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer56(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Aliases) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer56(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Aliases) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer56(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer57(in *jlexer.Lexer, out *Alias) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "expansion":
+			out.Expansion = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer57(out *jwriter.Writer, in Alias) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	if in.Expansion != "" {
+		const prefix string = ",\"expansion\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Expansion))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Alias) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer57(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Alias) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer57(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Alias) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer57(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Alias) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer57(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer58(in *jlexer.Lexer, out *AdminUsers) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "users":
+			if in.IsNull() {
+				in.Skip()
+				out.Users = nil
+			} else {
+				in.Delim('[')
+				if out.Users == nil {
+					if !in.IsDelim(']') {
+						out.Users = make([]AdminUser, 0, 0)
+					} else {
+						out.Users = []AdminUser{}
+					}
+				} else {
+					out.Users = (out.Users)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v82 AdminUser
+					(v82).UnmarshalEasyJSON(in)
+					out.Users = append(out.Users, v82)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer58(out *jwriter.Writer, in AdminUsers) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Users) != 0 {
+		const prefix string = ",\"users\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('[')
+			for v83, v84 := range in.Users {
+				if v83 > 0 {
+					out.RawByte(',')
+				}
+				(v84).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v AdminUsers) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer58(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v AdminUsers) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer58(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *AdminUsers) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer58(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *AdminUsers) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer58(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer59(in *jlexer.Lexer, out *AdminUser) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = uint64(in.Uint64())
+		case "username":
+			out.Username = string(in.String())
+		case "admin":
+			out.Admin = bool(in.Bool())
+		case "sessions":
+			if in.IsNull() {
+				in.Skip()
+				out.Sessions = nil
+			} else {
+				in.Delim('[')
+				if out.Sessions == nil {
+					if !in.IsDelim(']') {
+						out.Sessions = make([]string, 0, 4)
+					} else {
+						out.Sessions = []string{}
+					}
+				} else {
+					out.Sessions = (out.Sessions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v85 string
+					v85 = string(in.String())
+					out.Sessions = append(out.Sessions, v85)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "servers":
+			if in.IsNull() {
+				in.Skip()
+				out.Servers = nil
+			} else {
+				in.Delim('[')
+				if out.Servers == nil {
+					if !in.IsDelim(']') {
+						out.Servers = make([]AdminServer, 0, 1)
+					} else {
+						out.Servers = []AdminServer{}
+					}
+				} else {
+					out.Servers = (out.Servers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v86 AdminServer
+					(v86).UnmarshalEasyJSON(in)
+					out.Servers = append(out.Servers, v86)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer59(out *jwriter.Writer, in AdminUser) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ID != 0 {
+		const prefix string = ",\"id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Uint64(uint64(in.ID))
+	}
+	if in.Username != "" {
+		const prefix string = ",\"username\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Username))
+	}
+	if in.Admin {
+		const prefix string = ",\"admin\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Admin))
+	}
+	if len(in.Sessions) != 0 {
+		const prefix string = ",\"sessions\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v87, v88 := range in.Sessions {
+				if v87 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v88))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Servers) != 0 {
+		const prefix string = ",\"servers\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v89, v90 := range in.Servers {
+				if v89 > 0 {
+					out.RawByte(',')
+				}
+				(v90).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v AdminUser) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer59(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v AdminUser) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer59(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *AdminUser) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer59(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *AdminUser) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer59(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer60(in *jlexer.Lexer, out *AdminServer) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "host":
+			out.Host = string(in.String())
+		case "nick":
+			out.Nick = string(in.String())
+		case "connected":
+			out.Connected = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer60(out *jwriter.Writer, in AdminServer) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Host != "" {
+		const prefix string = ",\"host\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Host))
+	}
+	if in.Nick != "" {
+		const prefix string = ",\"nick\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Nick))
+	}
+	if in.Connected {
+		const prefix string = ",\"connected\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Connected))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v AdminServer) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer60(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v AdminServer) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer60(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *AdminServer) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer60(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *AdminServer) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer60(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer61(in *jlexer.Lexer, out *AdminDisconnect) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "user":
+			out.User = uint64(in.Uint64())
+		case "session":
+			out.Session = string(in.String())
+		case "server":
+			out.Server = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer61(out *jwriter.Writer, in AdminDisconnect) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.User != 0 {
+		const prefix string = ",\"user\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Uint64(uint64(in.User))
+	}
+	if in.Session != "" {
+		const prefix string = ",\"session\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Session))
+	}
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Server))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v AdminDisconnect) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer61(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v AdminDisconnect) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer61(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *AdminDisconnect) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer61(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *AdminDisconnect) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer61(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer62(in *jlexer.Lexer, out *Appearance) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "color":
+			out.Color = string(in.String())
+		case "label":
+			out.Label = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer62(out *jwriter.Writer, in Appearance) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.Color != "" {
+		const prefix string = ",\"color\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Color))
+	}
+	if in.Label != "" {
+		const prefix string = ",\"label\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Label))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Appearance) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer62(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Appearance) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer62(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Appearance) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer62(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Appearance) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer62(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer63(in *jlexer.Lexer, out *FetchMessagesAt) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "time":
+			out.Time = int64(in.Int64())
+		case "count":
+			out.Count = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer63(out *jwriter.Writer, in FetchMessagesAt) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.Time != 0 {
+		const prefix string = ",\"time\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Time))
+	}
+	if in.Count != 0 {
+		const prefix string = ",\"count\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Count))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FetchMessagesAt) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer63(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FetchMessagesAt) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer63(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FetchMessagesAt) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer63(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FetchMessagesAt) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer63(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer64(in *jlexer.Lexer, out *StandardReply) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "severity":
+			out.Severity = string(in.String())
+		case "command":
+			out.Command = string(in.String())
+		case "code":
+			out.Code = string(in.String())
+		case "context":
+			if in.IsNull() {
+				in.Skip()
+				out.Context = nil
+			} else {
+				in.Delim('[')
+				if out.Context == nil {
+					if !in.IsDelim(']') {
+						out.Context = make([]string, 0, 4)
+					} else {
+						out.Context = []string{}
+					}
+				} else {
+					out.Context = (out.Context)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v91 string
+					v91 = string(in.String())
+					out.Context = append(out.Context, v91)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer64(out *jwriter.Writer, in StandardReply) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Severity != "" {
+		const prefix string = ",\"severity\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Severity))
+	}
+	if in.Command != "" {
+		const prefix string = ",\"command\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Command))
+	}
+	if in.Code != "" {
+		const prefix string = ",\"code\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Code))
+	}
+	if len(in.Context) != 0 {
+		const prefix string = ",\"context\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v92, v93 := range in.Context {
+				if v92 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v93))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StandardReply) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer64(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StandardReply) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer64(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StandardReply) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer64(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StandardReply) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer64(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer65(in *jlexer.Lexer, out *CommandHistory) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "commands":
+			if in.IsNull() {
+				in.Skip()
+				out.Commands = nil
+			} else {
+				in.Delim('[')
+				if out.Commands == nil {
+					if !in.IsDelim(']') {
+						out.Commands = make([]string, 0, 4)
+					} else {
+						out.Commands = []string{}
+					}
+				} else {
+					out.Commands = (out.Commands)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v94 string
+					v94 = string(in.String())
+					out.Commands = append(out.Commands, v94)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer65(out *jwriter.Writer, in CommandHistory) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if len(in.Commands) != 0 {
+		const prefix string = ",\"commands\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v95, v96 := range in.Commands {
+				if v95 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v96))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v CommandHistory) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer65(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v CommandHistory) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer65(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *CommandHistory) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer65(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *CommandHistory) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer65(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer66(in *jlexer.Lexer, out *Help) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "topic":
+			out.Topic = string(in.String())
+		case "content":
+			if in.IsNull() {
+				in.Skip()
+				out.Content = nil
+			} else {
+				in.Delim('[')
+				if out.Content == nil {
+					if !in.IsDelim(']') {
+						out.Content = make([]string, 0, 4)
+					} else {
+						out.Content = []string{}
+					}
+				} else {
+					out.Content = (out.Content)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v97 string
+					v97 = string(in.String())
+					out.Content = append(out.Content, v97)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "missing":
+			out.Missing = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer66(out *jwriter.Writer, in Help) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Topic != "" {
+		const prefix string = ",\"topic\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Topic))
+	}
+	if len(in.Content) != 0 {
+		const prefix string = ",\"content\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v98, v99 := range in.Content {
+				if v98 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v99))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Missing {
+		const prefix string = ",\"missing\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Missing))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Help) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer66(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Help) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer66(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Help) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer66(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Help) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer66(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer67(in *jlexer.Lexer, out *HelpRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "topic":
+			out.Topic = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer67(out *jwriter.Writer, in HelpRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Topic != "" {
+		const prefix string = ",\"topic\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Topic))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v HelpRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer67(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v HelpRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer67(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *HelpRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer67(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *HelpRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer67(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer68(in *jlexer.Lexer, out *LinkClosed) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "reason":
+			out.Reason = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer68(out *jwriter.Writer, in LinkClosed) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Reason != "" {
+		const prefix string = ",\"reason\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Reason))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v LinkClosed) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer68(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v LinkClosed) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer68(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *LinkClosed) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer68(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *LinkClosed) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer68(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer69(in *jlexer.Lexer, out *OperRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "name":
+			out.Name = string(in.String())
+		case "password":
+			out.Password = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer69(out *jwriter.Writer, in OperRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Name))
+	}
+	if in.Password != "" {
+		const prefix string = ",\"password\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Password))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OperRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer69(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OperRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer69(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OperRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer69(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OperRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer69(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer70(in *jlexer.Lexer, out *OperReply) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "success":
+			out.Success = bool(in.Bool())
+		case "message":
+			out.Message = string(in.String())
+		case "modes":
+			out.Modes = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer70(out *jwriter.Writer, in OperReply) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Success {
+		const prefix string = ",\"success\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Success))
+	}
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Message))
+	}
+	if in.Modes != "" {
+		const prefix string = ",\"modes\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Modes))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OperReply) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer70(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OperReply) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer70(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OperReply) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer70(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OperReply) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer70(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer71(in *jlexer.Lexer, out *RequestError) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "type":
+			out.Type = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer71(out *jwriter.Writer, in RequestError) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Type != "" {
+		const prefix string = ",\"type\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Type))
+	}
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RequestError) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer71(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RequestError) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer71(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RequestError) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer71(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RequestError) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer71(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer72(in *jlexer.Lexer, out *ClientTags) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "forward":
+			if in.IsNull() {
+				in.Skip()
+				out.Forward = nil
+			} else {
+				in.Delim('[')
+				if out.Forward == nil {
+					if !in.IsDelim(']') {
+						out.Forward = make([]string, 0, 4)
+					} else {
+						out.Forward = []string{}
+					}
+				} else {
+					out.Forward = (out.Forward)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v108 string
+					v108 = string(in.String())
+					out.Forward = append(out.Forward, v108)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "send":
+			if in.IsNull() {
+				in.Skip()
+				out.Send = nil
+			} else {
+				in.Delim('[')
+				if out.Send == nil {
+					if !in.IsDelim(']') {
+						out.Send = make([]string, 0, 4)
+					} else {
+						out.Send = []string{}
+					}
+				} else {
+					out.Send = (out.Send)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v109 string
+					v109 = string(in.String())
+					out.Send = append(out.Send, v109)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer72(out *jwriter.Writer, in ClientTags) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if len(in.Forward) != 0 {
+		const prefix string = ",\"forward\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v110, v111 := range in.Forward {
+				if v110 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v111))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Send) != 0 {
+		const prefix string = ",\"send\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v112, v113 := range in.Send {
+				if v112 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v113))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ClientTags) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer72(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ClientTags) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer72(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ClientTags) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer72(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ClientTags) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer72(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer73(in *jlexer.Lexer, out *QueuedMessage) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "server":
+			out.Server = string(in.String())
+		case "to":
+			out.To = string(in.String())
+		case "content":
+			out.Content = string(in.String())
+		case "statusMsg":
+			out.StatusMsg = string(in.String())
+		case "replyTo":
+			out.ReplyTo = string(in.String())
+		case "time":
+			out.Time = int64(in.Int64())
+		case "sent":
+			out.Sent = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer73(out *jwriter.Writer, in QueuedMessage) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Server))
+	}
+	if in.To != "" {
+		const prefix string = ",\"to\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.To))
+	}
+	if in.Content != "" {
+		const prefix string = ",\"content\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Content))
+	}
+	if in.StatusMsg != "" {
+		const prefix string = ",\"statusMsg\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.StatusMsg))
+	}
+	if in.ReplyTo != "" {
+		const prefix string = ",\"replyTo\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ReplyTo))
+	}
+	if in.Time != 0 {
+		const prefix string = ",\"time\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Time))
+	}
+	if in.Sent {
+		const prefix string = ",\"sent\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Sent))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v QueuedMessage) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer73(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v QueuedMessage) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer73(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *QueuedMessage) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer73(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *QueuedMessage) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer73(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer74(in *jlexer.Lexer, out *AccountRegistration) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "account":
+			out.Account = string(in.String())
+		case "email":
+			out.Email = string(in.String())
+		case "password":
+			out.Password = string(in.String())
+		case "code":
+			out.Code = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer5(out *jwriter.Writer, in Topic) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer74(out *jwriter.Writer, in AccountRegistration) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -595,63 +10038,73 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer5(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Server))
 	}
-	if in.Channel != "" {
-		const prefix string = ",\"channel\":"
+	if in.Account != "" {
+		const prefix string = ",\"account\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Channel))
+		out.String(string(in.Account))
 	}
-	if in.Topic != "" {
-		const prefix string = ",\"topic\":"
+	if in.Email != "" {
+		const prefix string = ",\"email\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Topic))
+		out.String(string(in.Email))
 	}
-	if in.Nick != "" {
-		const prefix string = ",\"nick\":"
+	if in.Password != "" {
+		const prefix string = ",\"password\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Nick))
+		out.String(string(in.Password))
+	}
+	if in.Code != "" {
+		const prefix string = ",\"code\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Code))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Topic) MarshalJSON() ([]byte, error) {
+func (v AccountRegistration) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer5(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer74(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Topic) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer5(w, v)
+func (v AccountRegistration) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer74(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Topic) UnmarshalJSON(data []byte) error {
+func (v *AccountRegistration) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer5(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer74(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Topic) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer5(l, v)
+func (v *AccountRegistration) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer74(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer6(in *jlexer.Lexer, out *Tab) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer75(in *jlexer.Lexer, out *AccountRegistrationReply) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -672,8 +10125,16 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer6(in *jlexer.Lexer, out
 		switch key {
 		case "server":
 			out.Server = string(in.String())
-		case "name":
-			out.Name = string(in.String())
+		case "command":
+			out.Command = string(in.String())
+		case "status":
+			out.Status = string(in.String())
+		case "account":
+			out.Account = string(in.String())
+		case "code":
+			out.Code = string(in.String())
+		case "message":
+			out.Message = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -684,7 +10145,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer6(in *jlexer.Lexer, out
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer6(out *jwriter.Writer, in Tab) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer75(out *jwriter.Writer, in AccountRegistrationReply) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -694,43 +10155,83 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer6(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Server))
 	}
-	if in.Name != "" {
-		const prefix string = ",\"name\":"
+	if in.Command != "" {
+		const prefix string = ",\"command\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Name))
+		out.String(string(in.Command))
+	}
+	if in.Status != "" {
+		const prefix string = ",\"status\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Status))
+	}
+	if in.Account != "" {
+		const prefix string = ",\"account\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Account))
+	}
+	if in.Code != "" {
+		const prefix string = ",\"code\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Code))
+	}
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Message))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Tab) MarshalJSON() ([]byte, error) {
+func (v AccountRegistrationReply) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer6(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer75(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Tab) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer6(w, v)
+func (v AccountRegistrationReply) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer75(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Tab) UnmarshalJSON(data []byte) error {
+func (v *AccountRegistrationReply) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer6(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer75(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Tab) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer6(l, v)
+func (v *AccountRegistrationReply) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer75(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer7(in *jlexer.Lexer, out *ServerName) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer76(in *jlexer.Lexer, out *Resume) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -749,10 +10250,8 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer7(in *jlexer.Lexer, out
 			continue
 		}
 		switch key {
-		case "server":
-			out.Server = string(in.String())
-		case "name":
-			out.Name = string(in.String())
+		case "replayed":
+			out.Replayed = int(in.Int())
 		default:
 			in.SkipRecursive()
 		}
@@ -763,53 +10262,43 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer7(in *jlexer.Lexer, out
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer7(out *jwriter.Writer, in ServerName) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer76(out *jwriter.Writer, in Resume) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Server != "" {
-		const prefix string = ",\"server\":"
+	if in.Replayed != 0 {
+		const prefix string = ",\"replayed\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Server))
-	}
-	if in.Name != "" {
-		const prefix string = ",\"name\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Name))
+		out.Int(int(in.Replayed))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ServerName) MarshalJSON() ([]byte, error) {
+func (v Resume) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer7(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer76(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ServerName) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer7(w, v)
+func (v Resume) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer76(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ServerName) UnmarshalJSON(data []byte) error {
+func (v *Resume) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer7(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer76(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ServerName) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer7(l, v)
+func (v *Resume) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer76(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer8(in *jlexer.Lexer, out *Server) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer77(in *jlexer.Lexer, out *AdminLogLevel) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -818,7 +10307,6 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer8(in *jlexer.Lexer, out
 		in.Skip()
 		return
 	}
-	out.Server = new(storage.Server)
 	in.Delim('{')
 	for !in.IsDelim('}') {
 		key := in.UnsafeFieldName(false)
@@ -829,56 +10317,8 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer8(in *jlexer.Lexer, out
 			continue
 		}
 		switch key {
-		case "status":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.Status).UnmarshalJSON(data))
-			}
-		case "features":
-			if in.IsNull() {
-				in.Skip()
-			} else {
-				in.Delim('{')
-				if !in.IsDelim('}') {
-					out.Features = make(map[string]interface{})
-				} else {
-					out.Features = nil
-				}
-				for !in.IsDelim('}') {
-					key := string(in.String())
-					in.WantColon()
-					var v7 interface{}
-					if m, ok := v7.(easyjson.Unmarshaler); ok {
-						m.UnmarshalEasyJSON(in)
-					} else if m, ok := v7.(json.Unmarshaler); ok {
-						_ = m.UnmarshalJSON(in.Raw())
-					} else {
-						v7 = in.Interface()
-					}
-					(out.Features)[key] = v7
-					in.WantComma()
-				}
-				in.Delim('}')
-			}
-		case "name":
-			out.Name = string(in.String())
-		case "host":
-			out.Host = string(in.String())
-		case "port":
-			out.Port = string(in.String())
-		case "tls":
-			out.TLS = bool(in.Bool())
-		case "serverPassword":
-			out.ServerPassword = string(in.String())
-		case "nick":
-			out.Nick = string(in.String())
-		case "username":
-			out.Username = string(in.String())
-		case "realname":
-			out.Realname = string(in.String())
-		case "account":
-			out.Account = string(in.String())
-		case "password":
-			out.Password = string(in.String())
+		case "level":
+			out.Level = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -889,64 +10329,87 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer8(in *jlexer.Lexer, out
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer8(out *jwriter.Writer, in Server) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer77(out *jwriter.Writer, in AdminLogLevel) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if true {
-		const prefix string = ",\"status\":"
+	if in.Level != "" {
+		const prefix string = ",\"level\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.Raw((in.Status).MarshalJSON())
+		out.String(string(in.Level))
 	}
-	if len(in.Features) != 0 {
-		const prefix string = ",\"features\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v AdminLogLevel) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer77(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v AdminLogLevel) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer77(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *AdminLogLevel) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer77(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *AdminLogLevel) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer77(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer78(in *jlexer.Lexer, out *ServerCheck) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
 		}
-		{
-			out.RawByte('{')
-			v8First := true
-			for v8Name, v8Value := range in.Features {
-				if v8First {
-					v8First = false
-				} else {
-					out.RawByte(',')
-				}
-				out.String(string(v8Name))
-				out.RawByte(':')
-				if m, ok := v8Value.(easyjson.Marshaler); ok {
-					m.MarshalEasyJSON(out)
-				} else if m, ok := v8Value.(json.Marshaler); ok {
-					out.Raw(m.MarshalJSON())
-				} else {
-					out.Raw(json.Marshal(v8Value))
-				}
-			}
-			out.RawByte('}')
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
 		}
-	}
-	if in.Name != "" {
-		const prefix string = ",\"name\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+		switch key {
+		case "host":
+			out.Host = string(in.String())
+		case "port":
+			out.Port = string(in.String())
+		case "tls":
+			out.TLS = bool(in.Bool())
+		case "nick":
+			out.Nick = string(in.String())
+		default:
+			in.SkipRecursive()
 		}
-		out.String(string(in.Name))
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
 	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer78(out *jwriter.Writer, in ServerCheck) {
+	out.RawByte('{')
+	first := true
+	_ = first
 	if in.Host != "" {
 		const prefix string = ",\"host\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
+		first = false
+		out.RawString(prefix[1:])
 		out.String(string(in.Host))
 	}
 	if in.Port != "" {
@@ -969,16 +10432,6 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer8(out *jwriter.Writer,
 		}
 		out.Bool(bool(in.TLS))
 	}
-	if in.ServerPassword != "" {
-		const prefix string = ",\"serverPassword\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.ServerPassword))
-	}
 	if in.Nick != "" {
 		const prefix string = ",\"nick\":"
 		if first {
@@ -989,73 +10442,33 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer8(out *jwriter.Writer,
 		}
 		out.String(string(in.Nick))
 	}
-	if in.Username != "" {
-		const prefix string = ",\"username\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Username))
-	}
-	if in.Realname != "" {
-		const prefix string = ",\"realname\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Realname))
-	}
-	if in.Account != "" {
-		const prefix string = ",\"account\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Account))
-	}
-	if in.Password != "" {
-		const prefix string = ",\"password\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Password))
-	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Server) MarshalJSON() ([]byte, error) {
+func (v ServerCheck) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer8(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer78(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Server) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer8(w, v)
+func (v ServerCheck) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer78(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Server) UnmarshalJSON(data []byte) error {
+func (v *ServerCheck) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer8(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer78(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Server) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer8(l, v)
+func (v *ServerCheck) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer78(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer9(in *jlexer.Lexer, out *SearchResult) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer79(in *jlexer.Lexer, out *ServerCheckResult) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1074,33 +10487,18 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer9(in *jlexer.Lexer, out
 			continue
 		}
 		switch key {
-		case "server":
-			out.Server = string(in.String())
-		case "channel":
-			out.Channel = string(in.String())
-		case "results":
-			if in.IsNull() {
-				in.Skip()
-				out.Results = nil
-			} else {
-				in.Delim('[')
-				if out.Results == nil {
-					if !in.IsDelim(']') {
-						out.Results = make([]storage.Message, 0, 0)
-					} else {
-						out.Results = []storage.Message{}
-					}
-				} else {
-					out.Results = (out.Results)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v9 storage.Message
-					easyjson42239ddeDecodeGithubComKhliengDispatchStorage(in, &v9)
-					out.Results = append(out.Results, v9)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
+		case "host":
+			out.Host = string(in.String())
+		case "port":
+			out.Port = string(in.String())
+		case "tls":
+			out.TLS = bool(in.Bool())
+		case "ok":
+			out.OK = bool(in.Bool())
+		case "network":
+			out.Network = string(in.String())
+		case "error":
+			out.Error = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -1111,72 +10509,93 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer9(in *jlexer.Lexer, out
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer9(out *jwriter.Writer, in SearchResult) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer79(out *jwriter.Writer, in ServerCheckResult) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Server != "" {
-		const prefix string = ",\"server\":"
+	if in.Host != "" {
+		const prefix string = ",\"host\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Server))
+		out.String(string(in.Host))
 	}
-	if in.Channel != "" {
-		const prefix string = ",\"channel\":"
+	if in.Port != "" {
+		const prefix string = ",\"port\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Channel))
+		out.String(string(in.Port))
 	}
-	if len(in.Results) != 0 {
-		const prefix string = ",\"results\":"
+	if in.TLS {
+		const prefix string = ",\"tls\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v10, v11 := range in.Results {
-				if v10 > 0 {
-					out.RawByte(',')
-				}
-				easyjson42239ddeEncodeGithubComKhliengDispatchStorage(out, v11)
-			}
-			out.RawByte(']')
+		out.Bool(bool(in.TLS))
+	}
+	if in.OK {
+		const prefix string = ",\"ok\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.OK))
+	}
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Network))
+	}
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
 		}
+		out.String(string(in.Error))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v SearchResult) MarshalJSON() ([]byte, error) {
+func (v ServerCheckResult) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer9(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer79(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v SearchResult) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer9(w, v)
+func (v ServerCheckResult) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer79(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *SearchResult) UnmarshalJSON(data []byte) error {
+func (v *ServerCheckResult) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer9(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer79(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *SearchResult) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer9(l, v)
+func (v *ServerCheckResult) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer79(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchStorage(in *jlexer.Lexer, out *storage.Message) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer80(in *jlexer.Lexer, out *TLSInfo) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1195,35 +10614,20 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchStorage(in *jlexer.Lexer, out
 			continue
 		}
 		switch key {
-		case "from":
-			out.From = string(in.String())
-		case "content":
-			out.Content = string(in.String())
-		case "time":
-			out.Time = int64(in.Int64())
-		case "events":
-			if in.IsNull() {
-				in.Skip()
-				out.Events = nil
-			} else {
-				in.Delim('[')
-				if out.Events == nil {
-					if !in.IsDelim(']') {
-						out.Events = make([]storage.Event, 0, 1)
-					} else {
-						out.Events = []storage.Event{}
-					}
-				} else {
-					out.Events = (out.Events)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v12 storage.Event
-					easyjson42239ddeDecodeGithubComKhliengDispatchStorage1(in, &v12)
-					out.Events = append(out.Events, v12)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
+		case "server":
+			out.Server = string(in.String())
+		case "subject":
+			out.Subject = string(in.String())
+		case "issuer":
+			out.Issuer = string(in.String())
+		case "notBefore":
+			out.NotBefore = int64(in.Int64())
+		case "notAfter":
+			out.NotAfter = int64(in.Int64())
+		case "fingerprint":
+			out.Fingerprint = string(in.String())
+		case "expiresSoon":
+			out.ExpiresSoon = bool(in.Bool())
 		default:
 			in.SkipRecursive()
 		}
@@ -1234,62 +10638,103 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchStorage(in *jlexer.Lexer, out
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchStorage(out *jwriter.Writer, in storage.Message) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer80(out *jwriter.Writer, in TLSInfo) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.From != "" {
-		const prefix string = ",\"from\":"
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.Subject != "" {
+		const prefix string = ",\"subject\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.From))
+		out.String(string(in.Subject))
 	}
-	if in.Content != "" {
-		const prefix string = ",\"content\":"
+	if in.Issuer != "" {
+		const prefix string = ",\"issuer\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Content))
+		out.String(string(in.Issuer))
 	}
-	if in.Time != 0 {
-		const prefix string = ",\"time\":"
+	if in.NotBefore != 0 {
+		const prefix string = ",\"notBefore\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int64(int64(in.Time))
+		out.Int64(int64(in.NotBefore))
 	}
-	if len(in.Events) != 0 {
-		const prefix string = ",\"events\":"
+	if in.NotAfter != 0 {
+		const prefix string = ",\"notAfter\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v13, v14 := range in.Events {
-				if v13 > 0 {
-					out.RawByte(',')
-				}
-				easyjson42239ddeEncodeGithubComKhliengDispatchStorage1(out, v14)
-			}
-			out.RawByte(']')
+		out.Int64(int64(in.NotAfter))
+	}
+	if in.Fingerprint != "" {
+		const prefix string = ",\"fingerprint\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Fingerprint))
+	}
+	if in.ExpiresSoon {
+		const prefix string = ",\"expiresSoon\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
 		}
+		out.Bool(bool(in.ExpiresSoon))
 	}
 	out.RawByte('}')
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchStorage1(in *jlexer.Lexer, out *storage.Event) {
+
+// MarshalJSON supports json.Marshaler interface
+func (v TLSInfo) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer80(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TLSInfo) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer80(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TLSInfo) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer80(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TLSInfo) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer80(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer81(in *jlexer.Lexer, out *IdleDisconnect) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1308,33 +10753,29 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchStorage1(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "type":
-			out.Type = string(in.String())
-		case "params":
+		case "servers":
 			if in.IsNull() {
 				in.Skip()
-				out.Params = nil
+				out.Servers = nil
 			} else {
 				in.Delim('[')
-				if out.Params == nil {
+				if out.Servers == nil {
 					if !in.IsDelim(']') {
-						out.Params = make([]string, 0, 4)
+						out.Servers = make([]string, 0, 4)
 					} else {
-						out.Params = []string{}
+						out.Servers = []string{}
 					}
 				} else {
-					out.Params = (out.Params)[:0]
+					out.Servers = (out.Servers)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v15 string
-					v15 = string(in.String())
-					out.Params = append(out.Params, v15)
+					var v114 string
+					v114 = string(in.String())
+					out.Servers = append(out.Servers, v114)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "time":
-			out.Time = int64(in.Int64())
 		default:
 			in.SkipRecursive()
 		}
@@ -1345,48 +10786,52 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchStorage1(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchStorage1(out *jwriter.Writer, in storage.Event) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer81(out *jwriter.Writer, in IdleDisconnect) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Type != "" {
-		const prefix string = ",\"type\":"
+	if len(in.Servers) != 0 {
+		const prefix string = ",\"servers\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Type))
-	}
-	if len(in.Params) != 0 {
-		const prefix string = ",\"params\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
 		{
 			out.RawByte('[')
-			for v16, v17 := range in.Params {
-				if v16 > 0 {
+			for v115, v116 := range in.Servers {
+				if v115 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v17))
+				out.String(string(v116))
 			}
 			out.RawByte(']')
 		}
 	}
-	if in.Time != 0 {
-		const prefix string = ",\"time\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Int64(int64(in.Time))
-	}
 	out.RawByte('}')
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer10(in *jlexer.Lexer, out *SearchRequest) {
+
+// MarshalJSON supports json.Marshaler interface
+func (v IdleDisconnect) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer81(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v IdleDisconnect) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer81(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *IdleDisconnect) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer81(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *IdleDisconnect) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer81(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer82(in *jlexer.Lexer, out *SearchReindexed) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1405,12 +10850,8 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer10(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "server":
-			out.Server = string(in.String())
-		case "channel":
-			out.Channel = string(in.String())
-		case "phrase":
-			out.Phrase = string(in.String())
+		case "messages":
+			out.Messages = int(in.Int())
 		default:
 			in.SkipRecursive()
 		}
@@ -1421,63 +10862,43 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer10(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer10(out *jwriter.Writer, in SearchRequest) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer82(out *jwriter.Writer, in SearchReindexed) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Server != "" {
-		const prefix string = ",\"server\":"
+	if in.Messages != 0 {
+		const prefix string = ",\"messages\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Server))
-	}
-	if in.Channel != "" {
-		const prefix string = ",\"channel\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Channel))
-	}
-	if in.Phrase != "" {
-		const prefix string = ",\"phrase\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Phrase))
+		out.Int(int(in.Messages))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v SearchRequest) MarshalJSON() ([]byte, error) {
+func (v SearchReindexed) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer10(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer82(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v SearchRequest) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer10(w, v)
+func (v SearchReindexed) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer82(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *SearchRequest) UnmarshalJSON(data []byte) error {
+func (v *SearchReindexed) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer10(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer82(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *SearchRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer10(l, v)
+func (v *SearchReindexed) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer82(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer11(in *jlexer.Lexer, out *ReconnectSettings) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer83(in *jlexer.Lexer, out *AdminDeleteUser) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1496,10 +10917,8 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer11(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "server":
-			out.Server = string(in.String())
-		case "skipVerify":
-			out.SkipVerify = bool(in.Bool())
+		case "user":
+			out.User = uint64(in.Uint64())
 		default:
 			in.SkipRecursive()
 		}
@@ -1510,53 +10929,43 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer11(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer11(out *jwriter.Writer, in ReconnectSettings) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer83(out *jwriter.Writer, in AdminDeleteUser) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Server != "" {
-		const prefix string = ",\"server\":"
+	if in.User != 0 {
+		const prefix string = ",\"user\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Server))
-	}
-	if in.SkipVerify {
-		const prefix string = ",\"skipVerify\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Bool(bool(in.SkipVerify))
+		out.Uint64(uint64(in.User))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ReconnectSettings) MarshalJSON() ([]byte, error) {
+func (v AdminDeleteUser) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer11(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer83(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ReconnectSettings) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer11(w, v)
+func (v AdminDeleteUser) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer83(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ReconnectSettings) UnmarshalJSON(data []byte) error {
+func (v *AdminDeleteUser) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer11(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer83(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ReconnectSettings) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer11(l, v)
+func (v *AdminDeleteUser) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer83(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer12(in *jlexer.Lexer, out *Raw) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer84(in *jlexer.Lexer, out *Who) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1577,8 +10986,8 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer12(in *jlexer.Lexer, ou
 		switch key {
 		case "server":
 			out.Server = string(in.String())
-		case "message":
-			out.Message = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -1589,7 +10998,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer12(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer12(out *jwriter.Writer, in Raw) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer84(out *jwriter.Writer, in Who) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1599,43 +11008,43 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer12(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Server))
 	}
-	if in.Message != "" {
-		const prefix string = ",\"message\":"
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Message))
+		out.String(string(in.Channel))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Raw) MarshalJSON() ([]byte, error) {
+func (v Who) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer12(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer84(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Raw) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer12(w, v)
+func (v Who) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer84(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Raw) UnmarshalJSON(data []byte) error {
+func (v *Who) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer12(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer84(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Raw) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer12(l, v)
+func (v *Who) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer84(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer13(in *jlexer.Lexer, out *Quit) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer85(in *jlexer.Lexer, out *ChannelRename) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1656,8 +11065,10 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer13(in *jlexer.Lexer, ou
 		switch key {
 		case "server":
 			out.Server = string(in.String())
-		case "user":
-			out.User = string(in.String())
+		case "old":
+			out.Old = string(in.String())
+		case "new":
+			out.New = string(in.String())
 		case "reason":
 			out.Reason = string(in.String())
 		default:
@@ -1670,7 +11081,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer13(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer13(out *jwriter.Writer, in Quit) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer85(out *jwriter.Writer, in ChannelRename) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1680,15 +11091,25 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer13(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Server))
 	}
-	if in.User != "" {
-		const prefix string = ",\"user\":"
+	if in.Old != "" {
+		const prefix string = ",\"old\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.User))
+		out.String(string(in.Old))
+	}
+	if in.New != "" {
+		const prefix string = ",\"new\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.New))
 	}
 	if in.Reason != "" {
 		const prefix string = ",\"reason\":"
@@ -1704,29 +11125,29 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer13(out *jwriter.Writer,
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Quit) MarshalJSON() ([]byte, error) {
+func (v ChannelRename) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer13(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer85(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Quit) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer13(w, v)
+func (v ChannelRename) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer85(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Quit) UnmarshalJSON(data []byte) error {
+func (v *ChannelRename) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer13(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer85(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Quit) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer13(l, v)
+func (v *ChannelRename) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer85(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer14(in *jlexer.Lexer, out *Part) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer86(in *jlexer.Lexer, out *MarkRead) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1747,35 +11168,10 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer14(in *jlexer.Lexer, ou
 		switch key {
 		case "server":
 			out.Server = string(in.String())
-		case "user":
-			out.User = string(in.String())
-		case "channel":
-			out.Channel = string(in.String())
-		case "channels":
-			if in.IsNull() {
-				in.Skip()
-				out.Channels = nil
-			} else {
-				in.Delim('[')
-				if out.Channels == nil {
-					if !in.IsDelim(']') {
-						out.Channels = make([]string, 0, 4)
-					} else {
-						out.Channels = []string{}
-					}
-				} else {
-					out.Channels = (out.Channels)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v18 string
-					v18 = string(in.String())
-					out.Channels = append(out.Channels, v18)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "reason":
-			out.Reason = string(in.String())
+		case "target":
+			out.Target = string(in.String())
+		case "msgID":
+			out.MsgID = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -1786,7 +11182,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer14(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer14(out *jwriter.Writer, in Part) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer86(out *jwriter.Writer, in MarkRead) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1796,82 +11192,144 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer14(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Server))
 	}
-	if in.User != "" {
-		const prefix string = ",\"user\":"
+	if in.Target != "" {
+		const prefix string = ",\"target\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.User))
+		out.String(string(in.Target))
 	}
-	if in.Channel != "" {
-		const prefix string = ",\"channel\":"
+	if in.MsgID != "" {
+		const prefix string = ",\"msgID\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Channel))
+		out.String(string(in.MsgID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MarkRead) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer86(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MarkRead) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer86(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MarkRead) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer86(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MarkRead) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer86(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer87(in *jlexer.Lexer, out *ReadReceipt) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "server":
+			out.Server = string(in.String())
+		case "from":
+			out.From = string(in.String())
+		case "msgID":
+			out.MsgID = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
 	}
-	if len(in.Channels) != 0 {
-		const prefix string = ",\"channels\":"
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer87(out *jwriter.Writer, in ReadReceipt) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Server))
+	}
+	if in.From != "" {
+		const prefix string = ",\"from\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v19, v20 := range in.Channels {
-				if v19 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v20))
-			}
-			out.RawByte(']')
-		}
+		out.String(string(in.From))
 	}
-	if in.Reason != "" {
-		const prefix string = ",\"reason\":"
+	if in.MsgID != "" {
+		const prefix string = ",\"msgID\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Reason))
+		out.String(string(in.MsgID))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Part) MarshalJSON() ([]byte, error) {
+func (v ReadReceipt) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer14(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer87(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Part) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer14(w, v)
+func (v ReadReceipt) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer87(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Part) UnmarshalJSON(data []byte) error {
+func (v *ReadReceipt) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer14(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer87(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Part) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer14(l, v)
+func (v *ReadReceipt) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer87(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer15(in *jlexer.Lexer, out *NickFail) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer88(in *jlexer.Lexer, out *ServerTLS) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1892,6 +11350,31 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer15(in *jlexer.Lexer, ou
 		switch key {
 		case "server":
 			out.Server = string(in.String())
+		case "serverName":
+			out.ServerName = string(in.String())
+		case "alpn":
+			if in.IsNull() {
+				in.Skip()
+				out.ALPN = nil
+			} else {
+				in.Delim('[')
+				if out.ALPN == nil {
+					if !in.IsDelim(']') {
+						out.ALPN = make([]string, 0, 4)
+					} else {
+						out.ALPN = []string{}
+					}
+				} else {
+					out.ALPN = (out.ALPN)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v124 string
+					v124 = string(in.String())
+					out.ALPN = append(out.ALPN, v124)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -1902,7 +11385,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer15(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer15(out *jwriter.Writer, in NickFail) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer88(out *jwriter.Writer, in ServerTLS) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1912,33 +11395,62 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer15(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Server))
 	}
+	if in.ServerName != "" {
+		const prefix string = ",\"serverName\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ServerName))
+	}
+	if len(in.ALPN) != 0 {
+		const prefix string = ",\"alpn\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v125, v126 := range in.ALPN {
+				if v125 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v126))
+			}
+			out.RawByte(']')
+		}
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v NickFail) MarshalJSON() ([]byte, error) {
+func (v ServerTLS) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer15(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer88(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v NickFail) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer15(w, v)
+func (v ServerTLS) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer88(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *NickFail) UnmarshalJSON(data []byte) error {
+func (v *ServerTLS) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer15(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer88(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *NickFail) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer15(l, v)
+func (v *ServerTLS) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer88(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer16(in *jlexer.Lexer, out *Nick) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer89(in *jlexer.Lexer, out *Killed) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1959,10 +11471,12 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer16(in *jlexer.Lexer, ou
 		switch key {
 		case "server":
 			out.Server = string(in.String())
-		case "oldNick":
-			out.Old = string(in.String())
-		case "newNick":
-			out.New = string(in.String())
+		case "by":
+			out.By = string(in.String())
+		case "reason":
+			out.Reason = string(in.String())
+		case "cooldown":
+			out.Cooldown = int64(in.Int64())
 		default:
 			in.SkipRecursive()
 		}
@@ -1973,7 +11487,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer16(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer16(out *jwriter.Writer, in Nick) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer89(out *jwriter.Writer, in Killed) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1983,53 +11497,63 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer16(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Server))
 	}
-	if in.Old != "" {
-		const prefix string = ",\"oldNick\":"
+	if in.By != "" {
+		const prefix string = ",\"by\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Old))
+		out.String(string(in.By))
 	}
-	if in.New != "" {
-		const prefix string = ",\"newNick\":"
+	if in.Reason != "" {
+		const prefix string = ",\"reason\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.New))
+		out.String(string(in.Reason))
+	}
+	if in.Cooldown != 0 {
+		const prefix string = ",\"cooldown\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Cooldown))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Nick) MarshalJSON() ([]byte, error) {
+func (v Killed) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer16(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer89(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Nick) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer16(w, v)
+func (v Killed) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer89(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Nick) UnmarshalJSON(data []byte) error {
+func (v *Killed) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer16(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer89(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Nick) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer16(l, v)
+func (v *Killed) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer89(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer17(in *jlexer.Lexer, out *Mode) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer90(in *jlexer.Lexer, out *DCCTransfer) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2038,7 +11562,6 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer17(in *jlexer.Lexer, ou
 		in.Skip()
 		return
 	}
-	out.Mode = new(irc.Mode)
 	in.Delim('{')
 	for !in.IsDelim('}') {
 		key := in.UnsafeFieldName(false)
@@ -2051,14 +11574,20 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer17(in *jlexer.Lexer, ou
 		switch key {
 		case "server":
 			out.Server = string(in.String())
-		case "channel":
-			out.Channel = string(in.String())
-		case "user":
-			out.User = string(in.String())
-		case "add":
-			out.Add = string(in.String())
-		case "remove":
-			out.Remove = string(in.String())
+		case "from":
+			out.From = string(in.String())
+		case "file":
+			out.File = string(in.String())
+		case "size":
+			out.Size = uint64(in.Uint64())
+		case "percCompletion":
+			out.PercCompletion = float64(in.Float64())
+		case "bytesCompleted":
+			out.BytesCompleted = string(in.String())
+		case "speed":
+			out.Speed = string(in.String())
+		case "secondsToGo":
+			out.SecondsToGo = float64(in.Float64())
 		default:
 			in.SkipRecursive()
 		}
@@ -2069,7 +11598,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer17(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer17(out *jwriter.Writer, in Mode) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer90(out *jwriter.Writer, in DCCTransfer) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -2079,73 +11608,103 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer17(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Server))
 	}
-	if in.Channel != "" {
-		const prefix string = ",\"channel\":"
+	if in.From != "" {
+		const prefix string = ",\"from\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Channel))
+		out.String(string(in.From))
 	}
-	if in.User != "" {
-		const prefix string = ",\"user\":"
+	if in.File != "" {
+		const prefix string = ",\"file\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.User))
+		out.String(string(in.File))
 	}
-	if in.Add != "" {
-		const prefix string = ",\"add\":"
+	if in.Size != 0 {
+		const prefix string = ",\"size\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Add))
+		out.Uint64(uint64(in.Size))
 	}
-	if in.Remove != "" {
-		const prefix string = ",\"remove\":"
+	if in.PercCompletion != 0 {
+		const prefix string = ",\"percCompletion\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Remove))
+		out.Float64(float64(in.PercCompletion))
+	}
+	if in.BytesCompleted != "" {
+		const prefix string = ",\"bytesCompleted\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.BytesCompleted))
+	}
+	if in.Speed != "" {
+		const prefix string = ",\"speed\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Speed))
+	}
+	if in.SecondsToGo != 0 {
+		const prefix string = ",\"secondsToGo\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Float64(float64(in.SecondsToGo))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Mode) MarshalJSON() ([]byte, error) {
+func (v DCCTransfer) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer17(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer90(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Mode) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer17(w, v)
+func (v DCCTransfer) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer90(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Mode) UnmarshalJSON(data []byte) error {
+func (v *DCCTransfer) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer17(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer90(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Mode) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer17(l, v)
+func (v *DCCTransfer) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer90(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer18(in *jlexer.Lexer, out *Messages) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer91(in *jlexer.Lexer, out *DCCTransfers) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2164,37 +11723,29 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer18(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "server":
-			out.Server = string(in.String())
-		case "to":
-			out.To = string(in.String())
-		case "messages":
+		case "transfers":
 			if in.IsNull() {
 				in.Skip()
-				out.Messages = nil
+				out.Transfers = nil
 			} else {
 				in.Delim('[')
-				if out.Messages == nil {
+				if out.Transfers == nil {
 					if !in.IsDelim(']') {
-						out.Messages = make([]storage.Message, 0, 0)
+						out.Transfers = make([]DCCTransfer, 0, 2)
 					} else {
-						out.Messages = []storage.Message{}
+						out.Transfers = []DCCTransfer{}
 					}
 				} else {
-					out.Messages = (out.Messages)[:0]
+					out.Transfers = (out.Transfers)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v21 storage.Message
-					easyjson42239ddeDecodeGithubComKhliengDispatchStorage(in, &v21)
-					out.Messages = append(out.Messages, v21)
+					var v127 DCCTransfer
+					(v127).UnmarshalEasyJSON(in)
+					out.Transfers = append(out.Transfers, v127)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "prepend":
-			out.Prepend = bool(in.Bool())
-		case "next":
-			out.Next = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -2205,92 +11756,52 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer18(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer18(out *jwriter.Writer, in Messages) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer91(out *jwriter.Writer, in DCCTransfers) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Server != "" {
-		const prefix string = ",\"server\":"
+	if len(in.Transfers) != 0 {
+		const prefix string = ",\"transfers\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Server))
-	}
-	if in.To != "" {
-		const prefix string = ",\"to\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.To))
-	}
-	if len(in.Messages) != 0 {
-		const prefix string = ",\"messages\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
 		{
 			out.RawByte('[')
-			for v22, v23 := range in.Messages {
-				if v22 > 0 {
+			for v128, v129 := range in.Transfers {
+				if v128 > 0 {
 					out.RawByte(',')
 				}
-				easyjson42239ddeEncodeGithubComKhliengDispatchStorage(out, v23)
+				(v129).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
 	}
-	if in.Prepend {
-		const prefix string = ",\"prepend\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Bool(bool(in.Prepend))
-	}
-	if in.Next != "" {
-		const prefix string = ",\"next\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Next))
-	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Messages) MarshalJSON() ([]byte, error) {
+func (v DCCTransfers) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer18(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer91(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Messages) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer18(w, v)
+func (v DCCTransfers) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer91(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Messages) UnmarshalJSON(data []byte) error {
+func (v *DCCTransfers) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer18(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer91(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Messages) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer18(l, v)
+func (v *DCCTransfers) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer91(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer19(in *jlexer.Lexer, out *Message) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer92(in *jlexer.Lexer, out *CancelDCC) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2301,26 +11812,18 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer19(in *jlexer.Lexer, ou
 	}
 	in.Delim('{')
 	for !in.IsDelim('}') {
-		key := in.UnsafeFieldName(false)
-		in.WantColon()
-		if in.IsNull() {
-			in.Skip()
-			in.WantComma()
-			continue
-		}
-		switch key {
-		case "id":
-			out.ID = string(in.String())
-		case "server":
-			out.Server = string(in.String())
-		case "from":
-			out.From = string(in.String())
-		case "to":
-			out.To = string(in.String())
-		case "content":
-			out.Content = string(in.String())
-		case "type":
-			out.Type = string(in.String())
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "file":
+			out.File = string(in.String())
+		case "delete":
+			out.Delete = bool(in.Bool())
 		default:
 			in.SkipRecursive()
 		}
@@ -2331,93 +11834,53 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer19(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer19(out *jwriter.Writer, in Message) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer92(out *jwriter.Writer, in CancelDCC) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.ID != "" {
-		const prefix string = ",\"id\":"
+	if in.File != "" {
+		const prefix string = ",\"file\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.ID))
-	}
-	if in.Server != "" {
-		const prefix string = ",\"server\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Server))
-	}
-	if in.From != "" {
-		const prefix string = ",\"from\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.From))
-	}
-	if in.To != "" {
-		const prefix string = ",\"to\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.To))
-	}
-	if in.Content != "" {
-		const prefix string = ",\"content\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Content))
+		out.String(string(in.File))
 	}
-	if in.Type != "" {
-		const prefix string = ",\"type\":"
+	if in.Delete {
+		const prefix string = ",\"delete\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Type))
+		out.Bool(bool(in.Delete))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Message) MarshalJSON() ([]byte, error) {
+func (v CancelDCC) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer19(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer92(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Message) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer19(w, v)
+func (v CancelDCC) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer92(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Message) UnmarshalJSON(data []byte) error {
+func (v *CancelDCC) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer19(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer92(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Message) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer19(l, v)
+func (v *CancelDCC) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer92(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer20(in *jlexer.Lexer, out *MOTD) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer93(in *jlexer.Lexer, out *DCCCancelled) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2438,31 +11901,10 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer20(in *jlexer.Lexer, ou
 		switch key {
 		case "server":
 			out.Server = string(in.String())
-		case "title":
-			out.Title = string(in.String())
-		case "content":
-			if in.IsNull() {
-				in.Skip()
-				out.Content = nil
-			} else {
-				in.Delim('[')
-				if out.Content == nil {
-					if !in.IsDelim(']') {
-						out.Content = make([]string, 0, 4)
-					} else {
-						out.Content = []string{}
-					}
-				} else {
-					out.Content = (out.Content)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v24 string
-					v24 = string(in.String())
-					out.Content = append(out.Content, v24)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
+		case "file":
+			out.File = string(in.String())
+		case "deleted":
+			out.Deleted = bool(in.Bool())
 		default:
 			in.SkipRecursive()
 		}
@@ -2473,7 +11915,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer20(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer20(out *jwriter.Writer, in MOTD) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer93(out *jwriter.Writer, in DCCCancelled) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -2483,62 +11925,53 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer20(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Server))
 	}
-	if in.Title != "" {
-		const prefix string = ",\"title\":"
+	if in.File != "" {
+		const prefix string = ",\"file\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Title))
+		out.String(string(in.File))
 	}
-	if len(in.Content) != 0 {
-		const prefix string = ",\"content\":"
+	if in.Deleted {
+		const prefix string = ",\"deleted\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v25, v26 := range in.Content {
-				if v25 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v26))
-			}
-			out.RawByte(']')
-		}
+		out.Bool(bool(in.Deleted))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v MOTD) MarshalJSON() ([]byte, error) {
+func (v DCCCancelled) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer20(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer93(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v MOTD) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer20(w, v)
+func (v DCCCancelled) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer93(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *MOTD) UnmarshalJSON(data []byte) error {
+func (v *DCCCancelled) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer20(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer93(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *MOTD) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer20(l, v)
+func (v *DCCCancelled) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer93(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer21(in *jlexer.Lexer, out *Kick) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer94(in *jlexer.Lexer, out *CompactResult) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2557,12 +11990,18 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer21(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "server":
-			out.Server = string(in.String())
-		case "channel":
-			out.Channel = string(in.String())
-		case "user":
-			out.User = string(in.String())
+		case "store":
+			out.Store = string(in.String())
+		case "duration":
+			out.Duration = int64(in.Int64())
+		case "sizeBefore":
+			out.SizeBefore = int64(in.Int64())
+		case "sizeAfter":
+			out.SizeAfter = int64(in.Int64())
+		case "reclaimed":
+			out.Reclaimed = int64(in.Int64())
+		case "error":
+			out.Error = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -2573,63 +12012,93 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer21(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer21(out *jwriter.Writer, in Kick) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer94(out *jwriter.Writer, in CompactResult) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Server != "" {
-		const prefix string = ",\"server\":"
+	if in.Store != "" {
+		const prefix string = ",\"store\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Server))
+		out.String(string(in.Store))
 	}
-	if in.Channel != "" {
-		const prefix string = ",\"channel\":"
+	if in.Duration != 0 {
+		const prefix string = ",\"duration\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Channel))
+		out.Int64(int64(in.Duration))
 	}
-	if in.User != "" {
-		const prefix string = ",\"user\":"
+	if in.SizeBefore != 0 {
+		const prefix string = ",\"sizeBefore\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.User))
+		out.Int64(int64(in.SizeBefore))
+	}
+	if in.SizeAfter != 0 {
+		const prefix string = ",\"sizeAfter\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.SizeAfter))
+	}
+	if in.Reclaimed != 0 {
+		const prefix string = ",\"reclaimed\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Reclaimed))
+	}
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Error))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Kick) MarshalJSON() ([]byte, error) {
+func (v CompactResult) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer21(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer94(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Kick) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer21(w, v)
+func (v CompactResult) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer94(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Kick) UnmarshalJSON(data []byte) error {
+func (v *CompactResult) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer21(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer94(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Kick) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer21(l, v)
+func (v *CompactResult) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer94(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer22(in *jlexer.Lexer, out *Join) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer95(in *jlexer.Lexer, out *AdminCompact) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2648,29 +12117,25 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer22(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "server":
-			out.Server = string(in.String())
-		case "user":
-			out.User = string(in.String())
-		case "channels":
+		case "results":
 			if in.IsNull() {
 				in.Skip()
-				out.Channels = nil
+				out.Results = nil
 			} else {
 				in.Delim('[')
-				if out.Channels == nil {
+				if out.Results == nil {
 					if !in.IsDelim(']') {
-						out.Channels = make([]string, 0, 4)
+						out.Results = make([]CompactResult, 0, 2)
 					} else {
-						out.Channels = []string{}
+						out.Results = []CompactResult{}
 					}
 				} else {
-					out.Channels = (out.Channels)[:0]
+					out.Results = (out.Results)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v27 string
-					v27 = string(in.String())
-					out.Channels = append(out.Channels, v27)
+					var v130 CompactResult
+					(v130).UnmarshalEasyJSON(in)
+					out.Results = append(out.Results, v130)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -2685,41 +12150,21 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer22(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer22(out *jwriter.Writer, in Join) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer95(out *jwriter.Writer, in AdminCompact) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Server != "" {
-		const prefix string = ",\"server\":"
+	if len(in.Results) != 0 {
+		const prefix string = ",\"results\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Server))
-	}
-	if in.User != "" {
-		const prefix string = ",\"user\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.User))
-	}
-	if len(in.Channels) != 0 {
-		const prefix string = ",\"channels\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
 		{
 			out.RawByte('[')
-			for v28, v29 := range in.Channels {
-				if v28 > 0 {
+			for v131, v132 := range in.Results {
+				if v131 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v29))
+				(v132).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -2728,29 +12173,29 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer22(out *jwriter.Writer,
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Join) MarshalJSON() ([]byte, error) {
+func (v AdminCompact) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer22(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer95(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Join) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer22(w, v)
+func (v AdminCompact) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer95(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Join) UnmarshalJSON(data []byte) error {
+func (v *AdminCompact) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer22(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer95(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Join) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer22(l, v)
+func (v *AdminCompact) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer95(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer23(in *jlexer.Lexer, out *Invite) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer96(in *jlexer.Lexer, out *FetchMentions) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2769,12 +12214,8 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer23(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "server":
-			out.Server = string(in.String())
-		case "channel":
-			out.Channel = string(in.String())
-		case "user":
-			out.User = string(in.String())
+		case "limit":
+			out.Limit = int(in.Int())
 		default:
 			in.SkipRecursive()
 		}
@@ -2785,63 +12226,43 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer23(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer23(out *jwriter.Writer, in Invite) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer96(out *jwriter.Writer, in FetchMentions) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Server != "" {
-		const prefix string = ",\"server\":"
+	if in.Limit != 0 {
+		const prefix string = ",\"limit\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Server))
-	}
-	if in.Channel != "" {
-		const prefix string = ",\"channel\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Channel))
-	}
-	if in.User != "" {
-		const prefix string = ",\"user\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.User))
+		out.Int(int(in.Limit))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Invite) MarshalJSON() ([]byte, error) {
+func (v FetchMentions) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer23(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer96(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Invite) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer23(w, v)
+func (v FetchMentions) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer96(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Invite) UnmarshalJSON(data []byte) error {
+func (v *FetchMentions) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer23(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer96(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Invite) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer23(l, v)
+func (v *FetchMentions) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer96(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer24(in *jlexer.Lexer, out *IRCError) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer97(in *jlexer.Lexer, out *Mentions) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2860,12 +12281,29 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer24(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "server":
-			out.Server = string(in.String())
-		case "target":
-			out.Target = string(in.String())
-		case "message":
-			out.Message = string(in.String())
+		case "mentions":
+			if in.IsNull() {
+				in.Skip()
+				out.Mentions = nil
+			} else {
+				in.Delim('[')
+				if out.Mentions == nil {
+					if !in.IsDelim(']') {
+						out.Mentions = make([]storage.Mention, 0, 0)
+					} else {
+						out.Mentions = []storage.Mention{}
+					}
+				} else {
+					out.Mentions = (out.Mentions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v133 storage.Mention
+					easyjson42239ddeDecodeGithubComKhliengDispatchStorage8(in, &v133)
+					out.Mentions = append(out.Mentions, v133)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -2876,63 +12314,29 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer24(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer24(out *jwriter.Writer, in IRCError) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer97(out *jwriter.Writer, in Mentions) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Server != "" {
-		const prefix string = ",\"server\":"
+	if len(in.Mentions) != 0 {
+		const prefix string = ",\"mentions\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Server))
-	}
-	if in.Target != "" {
-		const prefix string = ",\"target\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Target))
-	}
-	if in.Message != "" {
-		const prefix string = ",\"message\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v134, v135 := range in.Mentions {
+				if v134 > 0 {
+					out.RawByte(',')
+				}
+				easyjson42239ddeEncodeGithubComKhliengDispatchStorage8(out, v135)
+			}
+			out.RawByte(']')
 		}
-		out.String(string(in.Message))
 	}
 	out.RawByte('}')
 }
 
-// MarshalJSON supports json.Marshaler interface
-func (v IRCError) MarshalJSON() ([]byte, error) {
-	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer24(&w, v)
-	return w.Buffer.BuildBytes(), w.Error
-}
-
-// MarshalEasyJSON supports easyjson.Marshaler interface
-func (v IRCError) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer24(w, v)
-}
-
-// UnmarshalJSON supports json.Unmarshaler interface
-func (v *IRCError) UnmarshalJSON(data []byte) error {
-	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer24(&r, v)
-	return r.Error()
-}
-
-// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *IRCError) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer24(l, v)
-}
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer25(in *jlexer.Lexer, out *FetchMessages) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchStorage8(in *jlexer.Lexer, out *storage.Mention) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2955,8 +12359,8 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer25(in *jlexer.Lexer, ou
 			out.Server = string(in.String())
 		case "channel":
 			out.Channel = string(in.String())
-		case "next":
-			out.Next = string(in.String())
+		case "message":
+			easyjson42239ddeDecodeGithubComKhliengDispatchStorage1(in, &out.Message)
 		default:
 			in.SkipRecursive()
 		}
@@ -2967,7 +12371,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer25(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer25(out *jwriter.Writer, in FetchMessages) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchStorage8(out *jwriter.Writer, in storage.Mention) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -2987,43 +12391,43 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer25(out *jwriter.Writer,
 		}
 		out.String(string(in.Channel))
 	}
-	if in.Next != "" {
-		const prefix string = ",\"next\":"
+	{
+		const prefix string = ",\"message\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Next))
+		easyjson42239ddeEncodeGithubComKhliengDispatchStorage1(out, in.Message)
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v FetchMessages) MarshalJSON() ([]byte, error) {
+func (v Mentions) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer25(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer97(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v FetchMessages) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer25(w, v)
+func (v Mentions) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer97(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *FetchMessages) UnmarshalJSON(data []byte) error {
+func (v *Mentions) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer25(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer97(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *FetchMessages) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer25(l, v)
+func (v *Mentions) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer97(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer26(in *jlexer.Lexer, out *Features) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer98(in *jlexer.Lexer, out *ServerPins) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3044,31 +12448,28 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer26(in *jlexer.Lexer, ou
 		switch key {
 		case "server":
 			out.Server = string(in.String())
-		case "features":
+		case "pins":
 			if in.IsNull() {
 				in.Skip()
+				out.Pins = nil
 			} else {
-				in.Delim('{')
-				if !in.IsDelim('}') {
-					out.Features = make(map[string]interface{})
-				} else {
-					out.Features = nil
-				}
-				for !in.IsDelim('}') {
-					key := string(in.String())
-					in.WantColon()
-					var v30 interface{}
-					if m, ok := v30.(easyjson.Unmarshaler); ok {
-						m.UnmarshalEasyJSON(in)
-					} else if m, ok := v30.(json.Unmarshaler); ok {
-						_ = m.UnmarshalJSON(in.Raw())
+				in.Delim('[')
+				if out.Pins == nil {
+					if !in.IsDelim(']') {
+						out.Pins = make([]string, 0, 4)
 					} else {
-						v30 = in.Interface()
+						out.Pins = []string{}
 					}
-					(out.Features)[key] = v30
+				} else {
+					out.Pins = (out.Pins)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v139 string
+					v139 = string(in.String())
+					out.Pins = append(out.Pins, v139)
 					in.WantComma()
 				}
-				in.Delim('}')
+				in.Delim(']')
 			}
 		default:
 			in.SkipRecursive()
@@ -3080,7 +12481,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer26(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer26(out *jwriter.Writer, in Features) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer98(out *jwriter.Writer, in ServerPins) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -3090,8 +12491,8 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer26(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Server))
 	}
-	if len(in.Features) != 0 {
-		const prefix string = ",\"features\":"
+	if len(in.Pins) != 0 {
+		const prefix string = ",\"pins\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
@@ -3099,54 +12500,43 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer26(out *jwriter.Writer,
 			out.RawString(prefix)
 		}
 		{
-			out.RawByte('{')
-			v31First := true
-			for v31Name, v31Value := range in.Features {
-				if v31First {
-					v31First = false
-				} else {
+			out.RawByte('[')
+			for v140, v141 := range in.Pins {
+				if v140 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v31Name))
-				out.RawByte(':')
-				if m, ok := v31Value.(easyjson.Marshaler); ok {
-					m.MarshalEasyJSON(out)
-				} else if m, ok := v31Value.(json.Marshaler); ok {
-					out.Raw(m.MarshalJSON())
-				} else {
-					out.Raw(json.Marshal(v31Value))
-				}
+				out.String(string(v141))
 			}
-			out.RawByte('}')
+			out.RawByte(']')
 		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Features) MarshalJSON() ([]byte, error) {
+func (v ServerPins) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer26(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer98(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Features) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer26(w, v)
+func (v ServerPins) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer98(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Features) UnmarshalJSON(data []byte) error {
+func (v *ServerPins) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer26(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer98(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Features) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer26(l, v)
+func (v *ServerPins) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer98(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer27(in *jlexer.Lexer, out *Error) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer99(in *jlexer.Lexer, out *Pasted) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3167,8 +12557,10 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer27(in *jlexer.Lexer, ou
 		switch key {
 		case "server":
 			out.Server = string(in.String())
-		case "message":
-			out.Message = string(in.String())
+		case "to":
+			out.To = string(in.String())
+		case "url":
+			out.URL = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -3179,7 +12571,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer27(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer27(out *jwriter.Writer, in Error) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer99(out *jwriter.Writer, in Pasted) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -3189,43 +12581,53 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer27(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Server))
 	}
-	if in.Message != "" {
-		const prefix string = ",\"message\":"
+	if in.To != "" {
+		const prefix string = ",\"to\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Message))
+		out.String(string(in.To))
+	}
+	if in.URL != "" {
+		const prefix string = ",\"url\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.URL))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Error) MarshalJSON() ([]byte, error) {
+func (v Pasted) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer27(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer99(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Error) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer27(w, v)
+func (v Pasted) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer99(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Error) UnmarshalJSON(data []byte) error {
+func (v *Pasted) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer27(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer99(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Error) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer27(l, v)
+func (v *Pasted) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer99(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer28(in *jlexer.Lexer, out *DCCSend) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer100(in *jlexer.Lexer, out *CreatePaste) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3244,14 +12646,10 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer28(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "server":
-			out.Server = string(in.String())
-		case "from":
-			out.From = string(in.String())
-		case "filename":
-			out.Filename = string(in.String())
-		case "url":
-			out.URL = string(in.String())
+		case "name":
+			out.Name = string(in.String())
+		case "content":
+			out.Content = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -3262,73 +12660,53 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer28(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer28(out *jwriter.Writer, in DCCSend) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer100(out *jwriter.Writer, in CreatePaste) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Server != "" {
-		const prefix string = ",\"server\":"
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Server))
-	}
-	if in.From != "" {
-		const prefix string = ",\"from\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.From))
-	}
-	if in.Filename != "" {
-		const prefix string = ",\"filename\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Filename))
+		out.String(string(in.Name))
 	}
-	if in.URL != "" {
-		const prefix string = ",\"url\":"
+	if in.Content != "" {
+		const prefix string = ",\"content\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.URL))
+		out.String(string(in.Content))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v DCCSend) MarshalJSON() ([]byte, error) {
+func (v CreatePaste) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer28(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer100(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v DCCSend) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer28(w, v)
+func (v CreatePaste) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer100(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *DCCSend) UnmarshalJSON(data []byte) error {
+func (v *CreatePaste) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer28(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer100(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *DCCSend) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer28(l, v)
+func (v *CreatePaste) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer100(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer29(in *jlexer.Lexer, out *ConnectionUpdate) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer101(in *jlexer.Lexer, out *PasteCreated) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3347,14 +12725,16 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer29(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "server":
-			out.Server = string(in.String())
-		case "connected":
-			out.Connected = bool(in.Bool())
-		case "error":
-			out.Error = string(in.String())
-		case "errorType":
-			out.ErrorType = string(in.String())
+		case "id":
+			out.ID = string(in.String())
+		case "name":
+			out.Name = string(in.String())
+		case "url":
+			out.URL = string(in.String())
+		case "size":
+			out.Size = int(in.Int())
+		case "expires":
+			out.Expires = int64(in.Int64())
 		default:
 			in.SkipRecursive()
 		}
@@ -3365,73 +12745,83 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer29(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer29(out *jwriter.Writer, in ConnectionUpdate) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer101(out *jwriter.Writer, in PasteCreated) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Server != "" {
-		const prefix string = ",\"server\":"
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Server))
+		out.String(string(in.ID))
 	}
-	if in.Connected {
-		const prefix string = ",\"connected\":"
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Bool(bool(in.Connected))
+		out.String(string(in.Name))
 	}
-	if in.Error != "" {
-		const prefix string = ",\"error\":"
+	if in.URL != "" {
+		const prefix string = ",\"url\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Error))
+		out.String(string(in.URL))
 	}
-	if in.ErrorType != "" {
-		const prefix string = ",\"errorType\":"
+	if in.Size != 0 {
+		const prefix string = ",\"size\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.ErrorType))
+		out.Int(int(in.Size))
+	}
+	if in.Expires != 0 {
+		const prefix string = ",\"expires\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Expires))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ConnectionUpdate) MarshalJSON() ([]byte, error) {
+func (v PasteCreated) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer29(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer101(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ConnectionUpdate) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer29(w, v)
+func (v PasteCreated) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer101(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ConnectionUpdate) UnmarshalJSON(data []byte) error {
+func (v *PasteCreated) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer29(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer101(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ConnectionUpdate) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer29(l, v)
+func (v *PasteCreated) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer101(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer30(in *jlexer.Lexer, out *ClientCert) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer102(in *jlexer.Lexer, out *ChannelLazy) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3450,10 +12840,12 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer30(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "cert":
-			out.Cert = string(in.String())
-		case "key":
-			out.Key = string(in.String())
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "lazy":
+			out.Lazy = bool(in.Bool())
 		default:
 			in.SkipRecursive()
 		}
@@ -3464,53 +12856,63 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer30(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer30(out *jwriter.Writer, in ClientCert) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer102(out *jwriter.Writer, in ChannelLazy) {
 	out.RawByte('{')
 	first := true
-	_ = first
-	if in.Cert != "" {
-		const prefix string = ",\"cert\":"
+	_ = first
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Cert))
+		out.String(string(in.Server))
 	}
-	if in.Key != "" {
-		const prefix string = ",\"key\":"
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Key))
+		out.String(string(in.Channel))
+	}
+	if in.Lazy {
+		const prefix string = ",\"lazy\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Lazy))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ClientCert) MarshalJSON() ([]byte, error) {
+func (v ChannelLazy) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer30(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer102(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ClientCert) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer30(w, v)
+func (v ChannelLazy) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer102(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ClientCert) UnmarshalJSON(data []byte) error {
+func (v *ChannelLazy) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer30(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer102(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ClientCert) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer30(l, v)
+func (v *ChannelLazy) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer102(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer31(in *jlexer.Lexer, out *ChannelSearchResult) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer103(in *jlexer.Lexer, out *LoadChannel) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3529,43 +12931,10 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer31(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "results":
-			if in.IsNull() {
-				in.Skip()
-				out.Results = nil
-			} else {
-				in.Delim('[')
-				if out.Results == nil {
-					if !in.IsDelim(']') {
-						out.Results = make([]*storage.ChannelListItem, 0, 8)
-					} else {
-						out.Results = []*storage.ChannelListItem{}
-					}
-				} else {
-					out.Results = (out.Results)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v32 *storage.ChannelListItem
-					if in.IsNull() {
-						in.Skip()
-						v32 = nil
-					} else {
-						if v32 == nil {
-							v32 = new(storage.ChannelListItem)
-						}
-						easyjson42239ddeDecodeGithubComKhliengDispatchStorage2(in, v32)
-					}
-					out.Results = append(out.Results, v32)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
 		case "server":
 			out.Server = string(in.String())
-		case "q":
-			out.Q = string(in.String())
-		case "start":
-			out.Start = int(in.Int())
+		case "channel":
+			out.Channel = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -3576,86 +12945,53 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer31(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer31(out *jwriter.Writer, in ChannelSearchResult) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer103(out *jwriter.Writer, in LoadChannel) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if len(in.Results) != 0 {
-		const prefix string = ",\"results\":"
-		first = false
-		out.RawString(prefix[1:])
-		{
-			out.RawByte('[')
-			for v33, v34 := range in.Results {
-				if v33 > 0 {
-					out.RawByte(',')
-				}
-				if v34 == nil {
-					out.RawString("null")
-				} else {
-					easyjson42239ddeEncodeGithubComKhliengDispatchStorage2(out, *v34)
-				}
-			}
-			out.RawByte(']')
-		}
-	}
 	if in.Server != "" {
 		const prefix string = ",\"server\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
+		first = false
+		out.RawString(prefix[1:])
 		out.String(string(in.Server))
 	}
-	if in.Q != "" {
-		const prefix string = ",\"q\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Q))
-	}
-	if in.Start != 0 {
-		const prefix string = ",\"start\":"
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int(int(in.Start))
+		out.String(string(in.Channel))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ChannelSearchResult) MarshalJSON() ([]byte, error) {
+func (v LoadChannel) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer31(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer103(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ChannelSearchResult) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer31(w, v)
+func (v LoadChannel) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer103(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ChannelSearchResult) UnmarshalJSON(data []byte) error {
+func (v *LoadChannel) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer31(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer103(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ChannelSearchResult) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer31(l, v)
+func (v *LoadChannel) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer103(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchStorage2(in *jlexer.Lexer, out *storage.ChannelListItem) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer104(in *jlexer.Lexer, out *FetchTopic) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3674,12 +13010,10 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchStorage2(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "name":
-			out.Name = string(in.String())
-		case "userCount":
-			out.UserCount = int(in.Int())
-		case "topic":
-			out.Topic = string(in.String())
+		case "server":
+			out.Server = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -3690,39 +13024,53 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchStorage2(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchStorage2(out *jwriter.Writer, in storage.ChannelListItem) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer104(out *jwriter.Writer, in FetchTopic) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Name != "" {
-		const prefix string = ",\"name\":"
+	if in.Server != "" {
+		const prefix string = ",\"server\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Name))
-	}
-	if in.UserCount != 0 {
-		const prefix string = ",\"userCount\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Int(int(in.UserCount))
+		out.String(string(in.Server))
 	}
-	if in.Topic != "" {
-		const prefix string = ",\"topic\":"
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Topic))
+		out.String(string(in.Channel))
 	}
 	out.RawByte('}')
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer32(in *jlexer.Lexer, out *ChannelSearch) {
+
+// MarshalJSON supports json.Marshaler interface
+func (v FetchTopic) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer104(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FetchTopic) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer104(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FetchTopic) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer104(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FetchTopic) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer104(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer105(in *jlexer.Lexer, out *Metadata) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3743,10 +13091,12 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer32(in *jlexer.Lexer, ou
 		switch key {
 		case "server":
 			out.Server = string(in.String())
-		case "q":
-			out.Q = string(in.String())
-		case "start":
-			out.Start = int(in.Int())
+		case "target":
+			out.Target = string(in.String())
+		case "key":
+			out.Key = string(in.String())
+		case "value":
+			out.Value = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -3757,7 +13107,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer32(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer32(out *jwriter.Writer, in ChannelSearch) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer105(out *jwriter.Writer, in Metadata) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -3767,53 +13117,63 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer32(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Server))
 	}
-	if in.Q != "" {
-		const prefix string = ",\"q\":"
+	if in.Target != "" {
+		const prefix string = ",\"target\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Q))
+		out.String(string(in.Target))
 	}
-	if in.Start != 0 {
-		const prefix string = ",\"start\":"
+	if in.Key != "" {
+		const prefix string = ",\"key\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int(int(in.Start))
+		out.String(string(in.Key))
+	}
+	if in.Value != "" {
+		const prefix string = ",\"value\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Value))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ChannelSearch) MarshalJSON() ([]byte, error) {
+func (v Metadata) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer32(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer105(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ChannelSearch) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer32(w, v)
+func (v Metadata) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer105(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ChannelSearch) UnmarshalJSON(data []byte) error {
+func (v *Metadata) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer32(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer105(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ChannelSearch) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer32(l, v)
+func (v *Metadata) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer105(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer33(in *jlexer.Lexer, out *ChannelForward) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer106(in *jlexer.Lexer, out *SetMetadata) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3834,10 +13194,10 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer33(in *jlexer.Lexer, ou
 		switch key {
 		case "server":
 			out.Server = string(in.String())
-		case "old":
-			out.Old = string(in.String())
-		case "new":
-			out.New = string(in.String())
+		case "key":
+			out.Key = string(in.String())
+		case "value":
+			out.Value = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -3848,7 +13208,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer33(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer33(out *jwriter.Writer, in ChannelForward) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer106(out *jwriter.Writer, in SetMetadata) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -3858,53 +13218,53 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer33(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Server))
 	}
-	if in.Old != "" {
-		const prefix string = ",\"old\":"
+	if in.Key != "" {
+		const prefix string = ",\"key\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Old))
+		out.String(string(in.Key))
 	}
-	if in.New != "" {
-		const prefix string = ",\"new\":"
+	if in.Value != "" {
+		const prefix string = ",\"value\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.New))
+		out.String(string(in.Value))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ChannelForward) MarshalJSON() ([]byte, error) {
+func (v SetMetadata) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer33(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer106(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ChannelForward) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer33(w, v)
+func (v SetMetadata) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer106(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ChannelForward) UnmarshalJSON(data []byte) error {
+func (v *SetMetadata) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer33(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer106(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ChannelForward) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer33(l, v)
+func (v *SetMetadata) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer106(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer34(in *jlexer.Lexer, out *Away) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer107(in *jlexer.Lexer, out *FetchMetadata) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3925,8 +13285,31 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer34(in *jlexer.Lexer, ou
 		switch key {
 		case "server":
 			out.Server = string(in.String())
-		case "message":
-			out.Message = string(in.String())
+		case "target":
+			out.Target = string(in.String())
+		case "keys":
+			if in.IsNull() {
+				in.Skip()
+				out.Keys = nil
+			} else {
+				in.Delim('[')
+				if out.Keys == nil {
+					if !in.IsDelim(']') {
+						out.Keys = make([]string, 0, 4)
+					} else {
+						out.Keys = []string{}
+					}
+				} else {
+					out.Keys = (out.Keys)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v142 string
+					v142 = string(in.String())
+					out.Keys = append(out.Keys, v142)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -3937,7 +13320,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer34(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer34(out *jwriter.Writer, in Away) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer107(out *jwriter.Writer, in FetchMetadata) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -3947,39 +13330,58 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer34(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Server))
 	}
-	if in.Message != "" {
-		const prefix string = ",\"message\":"
+	if in.Target != "" {
+		const prefix string = ",\"target\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Message))
+		out.String(string(in.Target))
+	}
+	if len(in.Keys) != 0 {
+		const prefix string = ",\"keys\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v143, v144 := range in.Keys {
+				if v143 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v144))
+			}
+			out.RawByte(']')
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Away) MarshalJSON() ([]byte, error) {
+func (v FetchMetadata) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer34(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer107(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Away) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer34(w, v)
+func (v FetchMetadata) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer107(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Away) UnmarshalJSON(data []byte) error {
+func (v *FetchMetadata) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer34(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer107(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Away) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer34(l, v)
+func (v *FetchMetadata) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer107(l, v)
 }
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const landingCSP = "default-src 'none'; style-src 'unsafe-inline'"
+
+// serveLanding keeps the client from visitors without a session when
+// auth.landing is "redirect" or "page", it returns false when the client
+// should be served. The login route at auth.landing_url is always served.
+func (d *Dispatch) serveLanding(w http.ResponseWriter, r *http.Request) bool {
+	auth := d.Config().Auth
+	if auth.Landing != "redirect" && auth.Landing != "page" {
+		return false
+	}
+
+	target := auth.LandingURL
+	if target == "" {
+		target = "/login"
+	}
+	if isLandingRoute(r, target) {
+		return false
+	}
+
+	if d.handleAuth(w, r, false, false) != nil {
+		return false
+	}
+
+	if auth.Landing == "redirect" {
+		w.Header().Set("Cache-Control", disabledCacheControl)
+		http.Redirect(w, r, target, http.StatusFound)
+		return true
+	}
+
+	page := landingPage(target)
+
+	d.setPageHeaders(w)
+	if cspEnabled {
+		w.Header().Set("Content-Security-Policy", landingCSP)
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(page)))
+	w.Write(page)
+	return true
+}
+
+// isLandingRoute returns true if target is a path on this server and
+// r is for it
+func isLandingRoute(r *http.Request, target string) bool {
+	u, err := url.Parse(target)
+	if err != nil || (u.Host != "" && u.Host != r.Host) {
+		return false
+	}
+	return u.Path == r.URL.Path
+}
+
+func landingPage(target string) []byte {
+	return []byte(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Dispatch</title>
+<style>
+body { font-family: sans-serif; text-align: center; margin-top: 20vh; }
+</style>
+</head>
+<body>
+<h1>Dispatch</h1>
+<p><a href="` + html.EscapeString(target) + `">Log in</a></p>
+</body>
+</html>
+`)
+}
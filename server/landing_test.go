@@ -0,0 +1,106 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/session"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func landingTestDispatch(t *testing.T, auth config.Auth) (*Dispatch, *session.Session) {
+	sess, err := session.New(user.ID)
+	assert.Nil(t, err)
+
+	d := New(&config.Config{Auth: auth})
+	d.states = &stateStore{
+		states:       map[uint64]*State{},
+		sessions:     map[string]*session.Session{sess.Key(): sess},
+		sessionStore: store.(storage.SessionStore),
+	}
+	d.states.set(NewState(user, d))
+	return d, sess
+}
+
+func getIndex(d *Dispatch, path string, sess *session.Session) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("GET", path, nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	if sess != nil {
+		r.AddCookie(&http.Cookie{Name: session.CookieName, Value: sess.Key()})
+	}
+	w := httptest.NewRecorder()
+	d.serveFiles(w, r)
+	return w
+}
+
+func TestLandingApp(t *testing.T) {
+	d, _ := landingTestDispatch(t, config.Auth{})
+
+	w := getIndex(d, "/", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html", w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Header().Get("Location"))
+}
+
+func TestLandingRedirect(t *testing.T) {
+	d, sess := landingTestDispatch(t, config.Auth{
+		Landing:    "redirect",
+		LandingURL: "/login",
+	})
+
+	for _, path := range []string{"/", "/irc.libera.chat/%23dispatch"} {
+		w := getIndex(d, path, nil)
+		assert.Equal(t, http.StatusFound, w.Code, path)
+		assert.Equal(t, "/login", w.Header().Get("Location"), path)
+	}
+
+	// The login route serves the client
+	w := getIndex(d, "/login", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Location"))
+
+	w = getIndex(d, "/", sess)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Location"))
+
+	// Static files are not affected
+	w = getIndex(d, "/robots.txt", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	d, _ = landingTestDispatch(t, config.Auth{
+		Landing:    "redirect",
+		LandingURL: "https://sso.example.com/login",
+	})
+	w = getIndex(d, "/login", nil)
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://sso.example.com/login", w.Header().Get("Location"))
+}
+
+func TestLandingPage(t *testing.T) {
+	d, sess := landingTestDispatch(t, config.Auth{
+		Landing:    "page",
+		LandingURL: "/login?next=/",
+	})
+
+	cspEnabled = true
+	defer func() { cspEnabled = false }()
+
+	w := getIndex(d, "/", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, landingCSP, w.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, "deny", w.Header().Get("X-Frame-Options"))
+	assert.True(t, strings.Contains(w.Body.String(), `href="/login?next=/"`))
+	assert.Empty(t, w.Result().Cookies())
+
+	w = getIndex(d, "/login", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, landingCSP, w.Header().Get("Content-Security-Policy"))
+
+	w = getIndex(d, "/", sess)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, landingCSP, w.Header().Get("Content-Security-Policy"))
+}
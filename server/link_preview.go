@@ -0,0 +1,238 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/linkmeta"
+)
+
+const (
+	maxPreviewsPerMessage = 3
+	maxPreviewCacheSize   = 1024
+)
+
+var (
+	previewCacheTTL = time.Hour
+	// previewFailureTTL is how long failed fetches are cached, short enough
+	// for a page that was down or slow to get a preview soon after
+	previewFailureTTL = time.Minute
+
+	urlRegexp = regexp.MustCompile(`https?://[^\s<>"'\x00-\x1f]+`)
+
+	errPreviewHost = errors.New("Host not allowed")
+)
+
+type previewCacheEntry struct {
+	meta    *linkmeta.Meta
+	expires time.Time
+}
+
+// linkPreviewer fetches metadata for links on behalf of users, with a
+// bounded number of concurrent fetches and a cache shared by everyone
+type linkPreviewer struct {
+	config    func() config.LinkPreviews
+	transport *http.Transport
+
+	lock   sync.Mutex
+	active int
+	cache  map[string]previewCacheEntry
+}
+
+func newLinkPreviewer(cfg func() config.LinkPreviews) *linkPreviewer {
+	p := &linkPreviewer{
+		config: cfg,
+		cache:  map[string]previewCacheEntry{},
+	}
+
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: p.checkAddress,
+	}
+	// No proxy is used, checkAddress would only see the address of the
+	// proxy and not the one it connects to
+	p.transport = &http.Transport{
+		Proxy:               nil,
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+		MaxIdleConns:        16,
+		IdleConnTimeout:     time.Minute,
+	}
+
+	return p
+}
+
+// previewURLs returns the http(s) links in text
+func previewURLs(text string) []string {
+	var urls []string
+	seen := map[string]bool{}
+
+	for _, match := range urlRegexp.FindAllString(text, -1) {
+		match = strings.TrimRight(match, ".,:;!?)]}")
+		if !seen[match] {
+			seen[match] = true
+			urls = append(urls, match)
+		}
+		if len(urls) == maxPreviewsPerMessage {
+			break
+		}
+	}
+
+	return urls
+}
+
+// fetch returns the metadata for rawurl, nil means there is no preview,
+// either because fetching failed or because too many fetches are running
+func (p *linkPreviewer) fetch(rawurl string) *linkmeta.Meta {
+	cfg := p.config()
+
+	u, err := url.Parse(rawurl)
+	if err != nil || !hostAllowed(cfg, u.Hostname()) {
+		return nil
+	}
+
+	p.lock.Lock()
+	if entry, ok := p.cache[rawurl]; ok && time.Now().Before(entry.expires) {
+		p.lock.Unlock()
+		return entry.meta
+	}
+	if cfg.MaxConcurrent > 0 && p.active >= cfg.MaxConcurrent {
+		p.lock.Unlock()
+		return nil
+	}
+	p.active++
+	p.lock.Unlock()
+
+	client := &http.Client{
+		Transport: p.transport,
+		Timeout:   cfg.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return http.ErrUseLastResponse
+			}
+			if !hostAllowed(cfg, req.URL.Hostname()) {
+				return errPreviewHost
+			}
+			return nil
+		},
+	}
+
+	meta, err := linkmeta.FetchWith(client, rawurl, cfg.MaxSize)
+	if err != nil {
+		meta = nil
+	}
+
+	p.lock.Lock()
+	p.active--
+	if len(p.cache) >= maxPreviewCacheSize {
+		p.pruneCache()
+	}
+	ttl := previewCacheTTL
+	if meta == nil {
+		ttl = previewFailureTTL
+	}
+	p.cache[rawurl] = previewCacheEntry{
+		meta:    meta,
+		expires: time.Now().Add(ttl),
+	}
+	p.lock.Unlock()
+
+	return meta
+}
+
+func (p *linkPreviewer) pruneCache() {
+	now := time.Now()
+	for url, entry := range p.cache {
+		if now.After(entry.expires) {
+			delete(p.cache, url)
+		}
+	}
+
+	for url := range p.cache {
+		if len(p.cache) < maxPreviewCacheSize {
+			break
+		}
+		delete(p.cache, url)
+	}
+}
+
+func (p *linkPreviewer) checkAddress(network, address string, c syscall.RawConn) error {
+	if p.config().AllowPrivate {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || isPrivateIP(ip) || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+		return errPreviewHost
+	}
+
+	return nil
+}
+
+var privateNets = []*net.IPNet{
+	mustParseCIDR("10.0.0.0/8"),
+	mustParseCIDR("172.16.0.0/12"),
+	mustParseCIDR("192.168.0.0/16"),
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("fc00::/7"),
+}
+
+func isPrivateIP(ip net.IP) bool {
+	for _, n := range privateNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func hostAllowed(cfg config.LinkPreviews, host string) bool {
+	host = strings.ToLower(host)
+	if host == "" {
+		return false
+	}
+
+	for _, deny := range cfg.Deny {
+		if matchHost(host, deny) {
+			return false
+		}
+	}
+
+	if len(cfg.Allow) == 0 {
+		return true
+	}
+	for _, allow := range cfg.Allow {
+		if matchHost(host, allow) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchHost(host, pattern string) bool {
+	pattern = strings.ToLower(pattern)
+	return host == pattern || strings.HasSuffix(host, "."+pattern)
+}
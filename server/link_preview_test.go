@@ -0,0 +1,156 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/stretchr/testify/assert"
+)
+
+func previewServer(hits *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+
+		switch r.URL.Path {
+		case "/page":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<html><head><title>The title</title>
+<meta property="og:description" content="The description">
+<meta property="og:image" content="https://img.example/a.png">
+</head></html>`)
+
+		case "/big":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, "<html><head>"+strings.Repeat(" ", 4096)+"<title>Too far</title></head></html>")
+
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+		}
+	}))
+}
+
+func testPreviewConfig() config.LinkPreviews {
+	return config.LinkPreviews{
+		Enabled:       true,
+		Timeout:       2 * time.Second,
+		MaxSize:       1024,
+		MaxConcurrent: 2,
+		AllowPrivate:  true,
+	}
+}
+
+func TestPreviewURLs(t *testing.T) {
+	assert.Equal(t, []string{"https://a.com/x", "http://b.com"},
+		previewURLs("see https://a.com/x, and (http://b.com) or https://a.com/x."))
+	assert.Len(t, previewURLs("http://1.com http://2.com http://3.com http://4.com"), maxPreviewsPerMessage)
+	assert.Nil(t, previewURLs("no links ftp://here"))
+}
+
+func TestLinkPreviewFetch(t *testing.T) {
+	var hits int32
+	srv := previewServer(&hits)
+	defer srv.Close()
+
+	cfg := testPreviewConfig()
+	p := newLinkPreviewer(func() config.LinkPreviews { return cfg })
+
+	meta := p.fetch(srv.URL + "/page")
+	assert.NotNil(t, meta)
+	assert.Equal(t, "The title", meta.Title)
+	assert.Equal(t, "The description", meta.Description)
+	assert.Equal(t, "https://img.example/a.png", meta.ImageURL)
+
+	// Cached
+	assert.Equal(t, meta, p.fetch(srv.URL+"/page"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+
+	assert.Nil(t, p.fetch(srv.URL+"/image"))
+	assert.WithinDuration(t, time.Now().Add(previewFailureTTL), p.cache[srv.URL+"/image"].expires, time.Second)
+	assert.WithinDuration(t, time.Now().Add(previewCacheTTL), p.cache[srv.URL+"/page"].expires, time.Second)
+
+	// Proxies from the environment are not used, they would get around
+	// the checks on the addresses that get connected to
+	assert.Nil(t, p.transport.Proxy)
+
+	meta = p.fetch(srv.URL + "/big")
+	assert.NotNil(t, meta)
+	assert.Empty(t, meta.Title)
+
+	p.active = cfg.MaxConcurrent
+	assert.Nil(t, p.fetch(srv.URL+"/page?busy"))
+	p.active = 0
+
+	cfg.Deny = []string{"127.0.0.1"}
+	assert.Nil(t, p.fetch(srv.URL+"/page?deny"))
+
+	cfg.Deny = nil
+	cfg.Allow = []string{"example.com"}
+	assert.Nil(t, p.fetch(srv.URL+"/page?allow"))
+
+	cfg.Allow = nil
+	cfg.AllowPrivate = false
+	p = newLinkPreviewer(func() config.LinkPreviews { return cfg })
+	assert.Nil(t, p.fetch(srv.URL+"/page?private"))
+}
+
+func TestHostAllowed(t *testing.T) {
+	cfg := config.LinkPreviews{
+		Deny: []string{"bad.com"},
+	}
+	assert.True(t, hostAllowed(cfg, "good.com"))
+	assert.False(t, hostAllowed(cfg, "bad.com"))
+	assert.False(t, hostAllowed(cfg, "sub.BAD.com"))
+	assert.True(t, hostAllowed(cfg, "notbad.com"))
+
+	cfg.Allow = []string{"good.com"}
+	assert.True(t, hostAllowed(cfg, "www.good.com"))
+	assert.False(t, hostAllowed(cfg, "other.com"))
+	assert.False(t, hostAllowed(cfg, ""))
+}
+
+func TestHandleIRCMessageLinkPreview(t *testing.T) {
+	var hits int32
+	srv := previewServer(&hits)
+	defer srv.Close()
+
+	d := New(&config.Config{LinkPreviews: testPreviewConfig()})
+	c := irc.NewClient(&irc.Config{
+		Nick:     "nick",
+		Username: "user",
+		Host:     "host.com",
+	})
+	s := NewState(user, d)
+	newIRCHandler(c, s).dispatchMessage(&irc.Message{
+		Command: irc.PRIVMSG,
+		Sender:  "someone",
+		Params:  []string{"#chan", "look " + srv.URL + "/page"},
+	})
+
+	res := <-s.broadcast
+	assert.Equal(t, "message", res.Type)
+	msg := res.Data.(Message)
+
+	select {
+	case res = <-s.broadcast:
+		assert.Equal(t, "link_preview", res.Type)
+		assert.Equal(t, LinkPreview{
+			Server:      "host.com",
+			Target:      "#chan",
+			MessageID:   msg.ID,
+			URL:         srv.URL + "/page",
+			Title:       "The title",
+			Description: "The description",
+			ImageURL:    "https://img.example/a.png",
+		}, res.Data)
+
+	case <-time.After(2 * time.Second):
+		t.Fatal("no link_preview sent")
+	}
+}
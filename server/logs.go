@@ -0,0 +1,137 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+// logPageSize is how many messages are written between each flush of
+// the response
+const logPageSize = 500
+
+const logDateLayout = "2006-01-02"
+
+// serveLogs writes the history of a channel as plain text, oldest first,
+// the from and to query params limit it to a range of dates
+func (d *Dispatch) serveLogs(w http.ResponseWriter, r *http.Request, state *State, server, channel string) {
+	settings := state.user.GetClientSettings()
+
+	from, to, err := parseLogRange(r.URL.Query(), settings.Location())
+	if err != nil {
+		fail(w, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", disabledCacheControl)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": logFilename(server, channel, from, to),
+	}))
+
+	out := bufio.NewWriter(w)
+	defer out.Flush()
+
+	flush := func() {
+		out.Flush()
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+
+	written := 0
+	err = state.user.StreamMessages(server, channel, from, func(msg *storage.Message) bool {
+		if !to.IsZero() && !time.Unix(msg.Time, 0).Before(to) {
+			return false
+		}
+		writeLogMessage(out, settings, *msg)
+
+		written++
+		if written%logPageSize == 0 {
+			flush()
+		}
+		return true
+	})
+	if err != nil {
+		log.Println("[Logs]", state.user.ID, server, channel+":", err)
+	}
+}
+
+func writeLogMessage(w *bufio.Writer, settings *storage.ClientSettings, msg storage.Message) {
+	if msg.Content != "" || len(msg.Events) == 0 {
+		fmt.Fprintf(w, "[%s] <%s> %s\n", settings.FormatTime(time.Unix(msg.Time, 0)), msg.From, msg.Content)
+	}
+
+	for _, event := range msg.Events {
+		fmt.Fprintf(w, "[%s] * %s\n", settings.FormatTime(time.Unix(event.Time, 0)), formatLogEvent(event))
+	}
+}
+
+func formatLogEvent(event storage.Event) string {
+	param := func(i int) string {
+		if i < len(event.Params) {
+			return event.Params[i]
+		}
+		return ""
+	}
+
+	switch event.Type {
+	case "join":
+		return param(0) + " joined"
+	case "part":
+		return param(0) + " left"
+	case "quit":
+		if reason := param(1); reason != "" {
+			return param(0) + " quit (" + reason + ")"
+		}
+		return param(0) + " quit"
+	case "nick":
+		return param(0) + " is now known as " + param(1)
+	case "topic":
+		return param(0) + " changed the topic to: " + param(1)
+	}
+	return strings.TrimSpace(event.Type + " " + strings.Join(event.Params, " "))
+}
+
+// parseLogRange parses the from and to dates in loc, to is inclusive so
+// the returned time is the start of the day after it
+func parseLogRange(query url.Values, loc *time.Location) (time.Time, time.Time, error) {
+	var from, to time.Time
+
+	if v := query.Get("from"); v != "" {
+		t, err := time.ParseInLocation(logDateLayout, v, loc)
+		if err != nil {
+			return from, to, err
+		}
+		from = t
+	}
+
+	if v := query.Get("to"); v != "" {
+		t, err := time.ParseInLocation(logDateLayout, v, loc)
+		if err != nil {
+			return from, to, err
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+
+	return from, to, nil
+}
+
+func logFilename(server, channel string, from, to time.Time) string {
+	name := server + "-" + strings.TrimLeft(channel, "#&")
+	if !from.IsZero() {
+		name += "-" + from.Format(logDateLayout)
+	}
+	if !to.IsZero() {
+		name += "-to-" + to.AddDate(0, 0, -1).Format(logDateLayout)
+	}
+	return sanitizeFilename(name + ".txt")
+}
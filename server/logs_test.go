@@ -0,0 +1,165 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatLogEvent(t *testing.T) {
+	cases := []struct {
+		event    storage.Event
+		expected string
+	}{
+		{storage.Event{Type: "join", Params: []string{"nick"}}, "nick joined"},
+		{storage.Event{Type: "part", Params: []string{"nick"}}, "nick left"},
+		{storage.Event{Type: "quit", Params: []string{"nick"}}, "nick quit"},
+		{storage.Event{Type: "quit", Params: []string{"nick", "bye"}}, "nick quit (bye)"},
+		{storage.Event{Type: "nick", Params: []string{"old", "new"}}, "old is now known as new"},
+		{storage.Event{Type: "topic", Params: []string{"nick", "the topic"}}, "nick changed the topic to: the topic"},
+		{storage.Event{Type: "kick", Params: []string{"op", "nick"}}, "kick op nick"},
+		{storage.Event{Type: "mode"}, "mode"},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, formatLogEvent(tc.event))
+	}
+}
+
+func TestWriteLogMessage(t *testing.T) {
+	settings := &storage.ClientSettings{Timezone: "UTC", TimeFormat: "15:04"}
+	ts := time.Date(2020, 1, 1, 12, 30, 0, 0, time.UTC).Unix()
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	writeLogMessage(w, settings, storage.Message{From: "nick", Content: "hello", Time: ts})
+	writeLogMessage(w, settings, storage.Message{Events: []storage.Event{
+		{Type: "join", Params: []string{"a"}, Time: ts},
+		{Type: "part", Params: []string{"b"}, Time: ts + 60},
+	}})
+	w.Flush()
+
+	assert.Equal(t, "[12:30] <nick> hello\n[12:30] * a joined\n[12:31] * b left\n", buf.String())
+}
+
+func TestParseLogRange(t *testing.T) {
+	loc := time.FixedZone("X", 3600)
+
+	from, to, err := parseLogRange(url.Values{}, loc)
+	assert.Nil(t, err)
+	assert.True(t, from.IsZero())
+	assert.True(t, to.IsZero())
+
+	from, to, err = parseLogRange(url.Values{"from": {"2020-01-02"}, "to": {"2020-01-03"}}, loc)
+	assert.Nil(t, err)
+	assert.True(t, from.Equal(time.Date(2020, 1, 2, 0, 0, 0, 0, loc)))
+	assert.True(t, to.Equal(time.Date(2020, 1, 4, 0, 0, 0, 0, loc)))
+
+	_, _, err = parseLogRange(url.Values{"from": {"yesterday"}}, loc)
+	assert.NotNil(t, err)
+	_, _, err = parseLogRange(url.Values{"to": {"2020-13-01"}}, loc)
+	assert.NotNil(t, err)
+}
+
+func TestLogFilename(t *testing.T) {
+	from := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "irc.example.com-go-nuts.txt", logFilename("irc.example.com", "#go-nuts", time.Time{}, time.Time{}))
+	assert.Equal(t, "irc.example.com-go-nuts-2020-01-02-to-2020-01-03.txt", logFilename("irc.example.com", "#go-nuts", from, to))
+}
+
+func serveTestLogs(s *State, server, channel, query string) (*httptest.ResponseRecorder, []string) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/logs/"+server+"/"+url.PathEscape(channel)+"?"+query, nil)
+	s.srv.serveLogs(w, r, s, server, channel)
+
+	body := strings.TrimSuffix(w.Body.String(), "\n")
+	if body == "" {
+		return w, nil
+	}
+	return w, strings.Split(body, "\n")
+}
+
+func TestServeLogsRange(t *testing.T) {
+	s := NewState(user, &Dispatch{})
+	server := "logs.range.test"
+
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	for day := 0; day < 4; day++ {
+		ts := start.AddDate(0, 0, day)
+		assert.Nil(t, user.LogMessage(&storage.Message{
+			ID:      storage.MessageIDAt(ts),
+			Server:  server,
+			From:    "nick",
+			To:      "#chan",
+			Content: "day" + strconv.Itoa(day),
+			Time:    ts.Unix(),
+		}))
+	}
+
+	w, lines := serveTestLogs(s, server, "#chan", "")
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=logs.range.test-chan.txt`, w.Header().Get("Content-Disposition"))
+	assert.Len(t, lines, 4)
+	assert.Equal(t, "["+user.FormatTime(start)+"] <nick> day0", lines[0])
+	assert.True(t, strings.HasSuffix(lines[3], "day3"))
+
+	_, lines = serveTestLogs(s, server, "#chan", "from=2020-01-02&to=2020-01-03")
+	assert.Len(t, lines, 2)
+	assert.True(t, strings.HasSuffix(lines[0], "day1"))
+	assert.True(t, strings.HasSuffix(lines[1], "day2"))
+
+	_, lines = serveTestLogs(s, server, "#chan", "from=2020-01-04")
+	assert.Len(t, lines, 1)
+	assert.True(t, strings.HasSuffix(lines[0], "day3"))
+
+	_, lines = serveTestLogs(s, server, "#chan", "to=2019-12-31")
+	assert.Empty(t, lines)
+
+	_, lines = serveTestLogs(s, server, "#chan", "from=2021-01-01")
+	assert.Empty(t, lines)
+
+	_, lines = serveTestLogs(s, server, "#nope", "")
+	assert.Empty(t, lines)
+
+	w, _ = serveTestLogs(s, server, "#chan", "from=nope")
+	assert.Equal(t, 400, w.Code)
+}
+
+func TestServeLogsPaging(t *testing.T) {
+	s := NewState(user, &Dispatch{})
+	server := "logs.paging.test"
+	count := logPageSize*2 + 10
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	messages := make([]*storage.Message, count)
+	for i := range messages {
+		messages[i] = &storage.Message{
+			Server:  server,
+			From:    "nick",
+			To:      "#chan",
+			Content: strconv.Itoa(i),
+			Time:    start.Add(time.Duration(i) * time.Second).Unix(),
+		}
+	}
+	assert.Nil(t, user.LogMessages(messages))
+
+	_, lines := serveTestLogs(s, server, "#chan", "")
+	assert.Len(t, lines, count)
+	for i, line := range lines {
+		if !strings.HasSuffix(line, "> "+strconv.Itoa(i)) {
+			t.Fatalf("line %d out of order: %s", i, line)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const loopbackSize = 32
+
+// loopbackWindow is how long after sending a message the server echoing
+// it back is treated as a duplicate
+var loopbackWindow = 10 * time.Second
+
+type sentMessage struct {
+	key  string
+	time time.Time
+}
+
+// sentMessages remembers the last messages sent by the user so copies
+// echoed back by servers without echo-message can be suppressed
+type sentMessages struct {
+	ring [loopbackSize]sentMessage
+	next int
+	lock sync.Mutex
+}
+
+func sentMessageKey(server, target, content string) string {
+	return server + "\x00" + strings.ToLower(target) + "\x00" + content
+}
+
+func (s *sentMessages) add(server, target, content string) {
+	s.lock.Lock()
+	s.ring[s.next] = sentMessage{
+		key:  sentMessageKey(server, target, content),
+		time: time.Now(),
+	}
+	s.next = (s.next + 1) % loopbackSize
+	s.lock.Unlock()
+}
+
+// take reports whether the message was sent recently, each sent message
+// only matches once
+func (s *sentMessages) take(server, target, content string) bool {
+	key := sentMessageKey(server, target, content)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for i := range s.ring {
+		sent := &s.ring[i]
+		if sent.key == key && time.Since(sent.time) <= loopbackWindow {
+			*sent = sentMessage{}
+			return true
+		}
+	}
+	return false
+}
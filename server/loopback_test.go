@@ -0,0 +1,59 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSentMessages(t *testing.T) {
+	defer func(window time.Duration) {
+		loopbackWindow = window
+	}(loopbackWindow)
+	loopbackWindow = 50 * time.Millisecond
+
+	var sent sentMessages
+	sent.add("srv", "#Chan", "hello")
+	assert.False(t, sent.take("srv", "#chan", "hello there"))
+	assert.False(t, sent.take("other", "#chan", "hello"))
+	assert.True(t, sent.take("srv", "#chan", "hello"))
+	assert.False(t, sent.take("srv", "#chan", "hello"))
+
+	sent.add("srv", "#chan", "hello")
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, sent.take("srv", "#chan", "hello"))
+
+	for i := 0; i < loopbackSize+1; i++ {
+		sent.add("srv", "#chan", string(rune('a'+i)))
+	}
+	assert.False(t, sent.take("srv", "#chan", "a"))
+	assert.True(t, sent.take("srv", "#chan", "b"))
+}
+
+func TestHandleIRCMessageLoopback(t *testing.T) {
+	c := irc.NewClient(&irc.Config{
+		Nick:     "nick",
+		Username: "user",
+		Host:     "host.com",
+	})
+	s := NewState(user, nil)
+	i := newIRCHandler(c, s)
+
+	s.sent.add("host.com", "#chan", "my message")
+
+	echo := &irc.Message{
+		Command: irc.PRIVMSG,
+		Sender:  "nick",
+		Params:  []string{"#chan", "my message"},
+	}
+	i.dispatchMessage(echo)
+	assert.Len(t, s.broadcast, 0)
+
+	// Sending the same thing again later is not a duplicate
+	i.dispatchMessage(echo)
+	res := <-s.broadcast
+	assert.Equal(t, "message", res.Type)
+	assert.Equal(t, "my message", res.Data.(Message).Content)
+}
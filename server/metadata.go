@@ -0,0 +1,85 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/khlieng/dispatch/pkg/irc"
+)
+
+var errMetadataUnsupported = errors.New("The server does not support metadata")
+
+// setMetadata caches a metadata key of a user, an empty value removes it
+func (s *State) setMetadata(server string, m *irc.Metadata) {
+	s.ircLock.Lock()
+	defer s.ircLock.Unlock()
+
+	users, ok := s.metadata[server]
+	if !ok {
+		if m.Value == "" {
+			return
+		}
+		users = map[string]map[string]string{}
+		s.metadata[server] = users
+	}
+
+	if m.Value == "" {
+		delete(users[m.Target], m.Key)
+		if len(users[m.Target]) == 0 {
+			delete(users, m.Target)
+		}
+		return
+	}
+
+	if users[m.Target] == nil {
+		users[m.Target] = map[string]string{}
+	}
+	users[m.Target][m.Key] = m.Value
+}
+
+// renameMetadata moves the cached metadata of a user that changed nick
+func (s *State) renameMetadata(server, old, new string) {
+	s.ircLock.Lock()
+	if users, ok := s.metadata[server]; ok {
+		if m, ok := users[old]; ok {
+			delete(users, old)
+			users[new] = m
+		}
+	}
+	s.ircLock.Unlock()
+}
+
+func (s *State) deleteMetadata(server, nick string) {
+	s.ircLock.Lock()
+	delete(s.metadata[server], nick)
+	s.ircLock.Unlock()
+}
+
+// userMetadata returns the cached metadata of the users in a userlist,
+// keyed by nick, nil if none of them have any
+func (s *State) userMetadata(server string, users []string) map[string]map[string]string {
+	s.ircLock.Lock()
+	defer s.ircLock.Unlock()
+
+	cached := s.metadata[server]
+	if len(cached) == 0 {
+		return nil
+	}
+
+	var result map[string]map[string]string
+	for _, user := range users {
+		nick := user[len(userPrefix(user)):]
+		m, ok := cached[nick]
+		if !ok {
+			continue
+		}
+
+		if result == nil {
+			result = map[string]map[string]string{}
+		}
+		result[nick] = make(map[string]string, len(m))
+		for k, v := range m {
+			result[nick][k] = v
+		}
+	}
+	return result
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataCache(t *testing.T) {
+	s := NewState(user, nil)
+	assert.Nil(t, s.userMetadata("host.com", []string{"a"}))
+
+	s.setMetadata("host.com", &irc.Metadata{Target: "a", Key: "status", Value: "Working"})
+	s.setMetadata("host.com", &irc.Metadata{Target: "a", Key: "avatar", Value: "https://a.png"})
+	s.setMetadata("host.com", &irc.Metadata{Target: "b", Key: "status", Value: "Away"})
+	s.setMetadata("other.com", &irc.Metadata{Target: "c", Key: "status", Value: "Here"})
+
+	assert.Equal(t, map[string]map[string]string{
+		"a": {"status": "Working", "avatar": "https://a.png"},
+		"b": {"status": "Away"},
+	}, s.userMetadata("host.com", []string{"@a", "+b", "c"}))
+
+	s.setMetadata("host.com", &irc.Metadata{Target: "a", Key: "avatar"})
+	s.renameMetadata("host.com", "a", "aa")
+	s.deleteMetadata("host.com", "b")
+	assert.Equal(t, map[string]map[string]string{
+		"aa": {"status": "Working"},
+	}, s.userMetadata("host.com", []string{"a", "aa", "b"}))
+
+	s.setMetadata("host.com", &irc.Metadata{Target: "aa", Key: "status"})
+	assert.Nil(t, s.userMetadata("host.com", []string{"aa"}))
+
+	s.deleteIRC("other.com")
+	assert.Nil(t, s.userMetadata("other.com", []string{"c"}))
+}
+
+func TestMetadata(t *testing.T) {
+	port, lines := stubIRCServer(t, ":srv CAP * LS :draft/metadata-2\r\n"+
+		":srv CAP * ACK :draft/metadata-2\r\n"+
+		":srv 001 nick :Welcome\r\n"+
+		":srv METADATA other display-name * :Other Person\r\n"+
+		":srv 761 nick * status * :Working\r\n"+
+		":srv 766 nick other status :key not set\r\n"+
+		":srv 353 nick = #chan :nick @other\r\n"+
+		":srv 366 nick #chan :End of /NAMES list\r\n")
+
+	c := irc.NewClient(&irc.Config{Host: "127.0.0.1", Port: port, Nick: "nick"})
+	s := NewState(user, nil)
+	s.setIRC("127.0.0.1", c)
+	ih := newIRCHandler(c, s)
+	c.Connect()
+	defer c.Quit()
+
+	assert.Equal(t, "METADATA * SUB avatar display-name homepage status", nextLine(t, lines, "METADATA"))
+
+	var metadata []Metadata
+	var userlist Userlist
+	timeout := time.After(time.Second)
+	for userlist.Channel == "" {
+		select {
+		case msg := <-c.Messages:
+			ih.dispatchMessage(msg)
+		case res := <-s.broadcast:
+			switch res.Type {
+			case "metadata":
+				metadata = append(metadata, res.Data.(Metadata))
+			case "users":
+				userlist = res.Data.(Userlist)
+			}
+		case <-timeout:
+			t.Fatal("No userlist received")
+		}
+	}
+
+	assert.Equal(t, []Metadata{
+		{Server: "127.0.0.1", Target: "other", Key: "display-name", Value: "Other Person"},
+		{Server: "127.0.0.1", Target: "nick", Key: "status", Value: "Working"},
+		{Server: "127.0.0.1", Target: "other", Key: "status"},
+	}, metadata)
+	assert.Equal(t, map[string]map[string]string{
+		"nick":  {"status": "Working"},
+		"other": {"display-name": "Other Person"},
+	}, userlist.Metadata)
+
+	h := &wsHandler{state: s}
+	h.initHandlers()
+
+	h.dispatchRequest(WSRequest{
+		Type: "set_metadata",
+		Data: []byte(`{"server":"127.0.0.1","key":"status","value":"Away"}`),
+	})
+	assert.Equal(t, "METADATA * SET status :Away", nextLine(t, lines, "METADATA"))
+
+	h.dispatchRequest(WSRequest{
+		Type: "fetch_metadata",
+		Data: []byte(`{"server":"127.0.0.1","target":"other","keys":["avatar","status"]}`),
+	})
+	assert.Equal(t, "METADATA other GET avatar status", nextLine(t, lines, "METADATA"))
+
+	h.dispatchRequest(WSRequest{
+		Type: "fetch_metadata",
+		Data: []byte(`{"server":"127.0.0.1","target":"#chan"}`),
+	})
+	assert.Equal(t, "METADATA #chan LIST", nextLine(t, lines, "METADATA"))
+}
+
+func TestMetadataUnsupported(t *testing.T) {
+	s := NewState(user, nil)
+	s.setIRC("host.com", irc.NewClient(&irc.Config{Host: "host.com", Nick: "nick"}))
+	h := &wsHandler{state: s}
+	h.initHandlers()
+
+	h.dispatchRequest(WSRequest{
+		Type: "set_metadata",
+		Data: []byte(`{"server":"host.com","key":"status","value":"Away"}`),
+	})
+	checkResponse(t, "error", Error{
+		Server:  "host.com",
+		Message: errMetadataUnsupported.Error(),
+	}, <-s.broadcast)
+
+	h.dispatchRequest(WSRequest{
+		Type: "fetch_metadata",
+		Data: []byte(`{"server":"host.com","target":"other"}`),
+	})
+	checkResponse(t, "error", Error{
+		Server:  "host.com",
+		Message: errMetadataUnsupported.Error(),
+	}, <-s.broadcast)
+}
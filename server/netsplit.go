@@ -0,0 +1,168 @@
+package server
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+)
+
+// netsplitWindow is the coalescing window used when no config is loaded
+var netsplitWindow = 2 * time.Second
+
+// netjoinTimeout is how long users lost in a netsplit are remembered
+// so that their JOINs can be recognized as a netjoin
+const netjoinTimeout = time.Hour
+
+// isNetsplitReason returns true if reason looks like the "server1 server2"
+// quit reason IRC servers use when a link between them breaks
+func isNetsplitReason(reason string) bool {
+	servers := strings.Split(reason, " ")
+	if len(servers) != 2 || servers[0] == servers[1] {
+		return false
+	}
+
+	for _, server := range servers {
+		if !isServerName(server) {
+			return false
+		}
+	}
+	return true
+}
+
+func isServerName(s string) bool {
+	if !strings.Contains(s, ".") || s[0] == '.' || s[len(s)-1] == '.' {
+		return false
+	}
+
+	for _, ch := range s {
+		if !(ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' ||
+			ch >= '0' && ch <= '9' || ch == '.' || ch == '-' || ch == '*' || ch == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+type splitUser struct {
+	servers string
+	time    time.Time
+}
+
+// netsplitTracker coalesces the QUITs and JOINs caused by a netsplit
+// and the following netjoin into single events
+type netsplitTracker struct {
+	server string
+	state  *State
+
+	splits     map[string][]string
+	joins      map[string]map[string][]string
+	splitUsers map[string]splitUser
+	lock       sync.Mutex
+}
+
+func newNetsplitTracker(server string, state *State) *netsplitTracker {
+	return &netsplitTracker{
+		server:     server,
+		state:      state,
+		splits:     map[string][]string{},
+		joins:      map[string]map[string][]string{},
+		splitUsers: map[string]splitUser{},
+	}
+}
+
+// quit returns true if the quit was part of a netsplit and got coalesced
+func (t *netsplitTracker) quit(nick, reason string) bool {
+	cfg := t.state.netsplitConfig()
+	if cfg.IndividualEvents || !isNetsplitReason(reason) {
+		return false
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := time.Now()
+	for nick, user := range t.splitUsers {
+		if now.Sub(user.time) > netjoinTimeout {
+			delete(t.splitUsers, nick)
+		}
+	}
+	t.splitUsers[nick] = splitUser{servers: reason, time: now}
+
+	users, pending := t.splits[reason]
+	t.splits[reason] = append(users, nick)
+	if !pending {
+		time.AfterFunc(cfg.Window, func() {
+			t.flushSplit(reason)
+		})
+	}
+	return true
+}
+
+// join returns true if the join was part of a netjoin and got coalesced
+func (t *netsplitTracker) join(nick, channel string) bool {
+	cfg := t.state.netsplitConfig()
+	if cfg.IndividualEvents {
+		return false
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	user, ok := t.splitUsers[nick]
+	if !ok || time.Since(user.time) > netjoinTimeout {
+		return false
+	}
+
+	channels, pending := t.joins[user.servers]
+	if !pending {
+		channels = map[string][]string{}
+		t.joins[user.servers] = channels
+
+		time.AfterFunc(cfg.Window, func() {
+			t.flushJoin(user.servers)
+		})
+	}
+	channels[channel] = append(channels[channel], nick)
+	return true
+}
+
+func (t *netsplitTracker) flushSplit(servers string) {
+	t.lock.Lock()
+	users := t.splits[servers]
+	delete(t.splits, servers)
+	t.lock.Unlock()
+
+	t.state.sendJSON("netsplit", Netsplit{
+		Server:  t.server,
+		Servers: strings.Split(servers, " "),
+		Users:   users,
+	})
+}
+
+func (t *netsplitTracker) flushJoin(servers string) {
+	t.lock.Lock()
+	channels := t.joins[servers]
+	delete(t.joins, servers)
+
+	for _, users := range channels {
+		for _, nick := range users {
+			delete(t.splitUsers, nick)
+		}
+	}
+	t.lock.Unlock()
+
+	t.state.sendJSON("netjoin", Netjoin{
+		Server:   t.server,
+		Servers:  strings.Split(servers, " "),
+		Channels: channels,
+	})
+}
+
+func (s *State) netsplitConfig() config.Netsplit {
+	if s.srv == nil {
+		return config.Netsplit{Window: netsplitWindow}
+	}
+	return s.srv.Config().Netsplit
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsNetsplitReason(t *testing.T) {
+	assert.True(t, isNetsplitReason("irc.server1.net irc.server2.net"))
+	assert.True(t, isNetsplitReason("*.net *.split"))
+	assert.False(t, isNetsplitReason("irc.server1.net irc.server1.net"))
+	assert.False(t, isNetsplitReason("irc.server1.net"))
+	assert.False(t, isNetsplitReason("going home"))
+	assert.False(t, isNetsplitReason("see you.later alligator."))
+	assert.False(t, isNetsplitReason("Quit: bye.now"))
+	assert.False(t, isNetsplitReason(""))
+}
+
+func TestNetsplitTracker(t *testing.T) {
+	defer func(window time.Duration) {
+		netsplitWindow = window
+	}(netsplitWindow)
+	netsplitWindow = 10 * time.Millisecond
+
+	s := NewState(user, nil)
+	tracker := newNetsplitTracker("host.com", s)
+
+	assert.False(t, tracker.quit("bob", "Quit: bye"))
+	assert.True(t, tracker.quit("alice", "irc.a.net irc.b.net"))
+	assert.True(t, tracker.quit("carol", "irc.a.net irc.b.net"))
+
+	res := <-s.broadcast
+	checkResponse(t, "netsplit", Netsplit{
+		Server:  "host.com",
+		Servers: []string{"irc.a.net", "irc.b.net"},
+		Users:   []string{"alice", "carol"},
+	}, res)
+
+	assert.False(t, tracker.join("bob", "#chan"))
+	assert.True(t, tracker.join("alice", "#chan"))
+	assert.True(t, tracker.join("alice", "#other"))
+	assert.True(t, tracker.join("carol", "#chan"))
+
+	res = <-s.broadcast
+	checkResponse(t, "netjoin", Netjoin{
+		Server:  "host.com",
+		Servers: []string{"irc.a.net", "irc.b.net"},
+		Channels: map[string][]string{
+			"#chan":  {"alice", "carol"},
+			"#other": {"alice"},
+		},
+	}, res)
+
+	// Users are only part of one netjoin
+	assert.False(t, tracker.join("alice", "#third"))
+}
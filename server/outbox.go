@@ -0,0 +1,149 @@
+package server
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/khlieng/dispatch/storage"
+)
+
+var errOutboxFull = errors.New("Too many messages are waiting for the server to reconnect")
+
+// outbox holds the messages sent while disconnected from a server until
+// the connection is registered again
+type outbox struct {
+	queues map[string][]QueuedMessage
+	lock   sync.Mutex
+}
+
+// add queues msg, it fails when limit messages are already waiting
+func (o *outbox) add(msg QueuedMessage, limit int) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.queues == nil {
+		o.queues = map[string][]QueuedMessage{}
+	}
+	if limit > 0 && len(o.queues[msg.Server]) >= limit {
+		return errOutboxFull
+	}
+	o.queues[msg.Server] = append(o.queues[msg.Server], msg)
+	return nil
+}
+
+// take removes and returns the messages waiting for server
+func (o *outbox) take(server string) []QueuedMessage {
+	o.lock.Lock()
+	msgs := o.queues[server]
+	delete(o.queues, server)
+	o.lock.Unlock()
+	return msgs
+}
+
+func (o *outbox) clear(server string) {
+	o.take(server)
+}
+
+// queueMessage puts msg in the outbox if queueing is enabled and the
+// connection to the server is not ready to send it, it returns false
+// when msg should be sent right away
+func (s *State) queueMessage(i *irc.Client, msg Message) bool {
+	if s.srv == nil || i.Registered() {
+		return false
+	}
+	cfg := s.srv.Config().Messages
+	if !cfg.QueueOffline {
+		return false
+	}
+
+	now := time.Now()
+	queued := QueuedMessage{
+		ID:        storage.MessageIDAt(now),
+		Server:    msg.Server,
+		To:        msg.To,
+		Content:   msg.Content,
+		StatusMsg: msg.StatusMsg,
+		ReplyTo:   msg.ReplyTo,
+		Time:      now.Unix(),
+	}
+
+	if err := s.outbox.add(queued, cfg.QueueSize); err != nil {
+		s.sendJSON("error", Error{
+			Server:  msg.Server,
+			Message: err.Error(),
+		})
+		return true
+	}
+
+	s.sendJSON("queued_message", queued)
+	return true
+}
+
+// flushOutbox sends the messages that were queued while disconnected
+func (s *State) flushOutbox(i *irc.Client) {
+	prefix := false
+	if s.srv != nil {
+		prefix = s.srv.Config().Messages.QueuePrefix
+	}
+
+	for _, queued := range s.outbox.take(i.Host()) {
+		msg := Message{
+			Server:    queued.Server,
+			To:        queued.To,
+			Content:   queued.Content,
+			StatusMsg: queued.StatusMsg,
+			ReplyTo:   queued.ReplyTo,
+		}
+		if prefix {
+			msg.Content = "[" + time.Unix(queued.Time, 0).UTC().Format("15:04 UTC") + "] " + msg.Content
+		}
+		if !s.sendMessage(i, msg) {
+			continue
+		}
+
+		queued.Sent = true
+		s.sendJSON("queued_message", queued)
+	}
+}
+
+// deliverMessage pastes msg when it is too long, queues it while the
+// connection is not ready or sends it right away
+func (s *State) deliverMessage(i *irc.Client, msg Message) {
+	if s.srv != nil && shouldPaste(s.srv.Config().Messages.Paste, msg.Content) {
+		go s.pasteMessage(i, msg)
+	} else if !s.queueMessage(i, msg) {
+		s.sendMessage(i, msg)
+	}
+}
+
+// sendMessage sends msg to its target and logs it, it returns false when
+// msg is only for users with a status the server does not support sending
+// to, instead of sending it to the whole channel
+func (s *State) sendMessage(i *irc.Client, msg Message) bool {
+	target := msg.To
+	if msg.StatusMsg != "" {
+		if prefix, _ := i.SplitStatusMsg(msg.StatusMsg + msg.To); prefix != msg.StatusMsg {
+			s.sendJSON("error", Error{
+				Server:  msg.Server,
+				Message: "The server does not support sending messages only to " + msg.StatusMsg + " users",
+			})
+			return false
+		}
+		target = msg.StatusMsg + msg.To
+	}
+	for _, content := range i.PrivmsgMultiline(target, msg.Content, msg.ReplyTo) {
+		s.sent.add(msg.Server, msg.To, content)
+	}
+
+	go s.user.LogMessage(&storage.Message{
+		ID:      msg.ID,
+		Server:  msg.Server,
+		From:    i.GetNick(),
+		To:      msg.To,
+		Content: msg.Content,
+		ReplyTo: msg.ReplyTo,
+	})
+	return true
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutbox(t *testing.T) {
+	var o outbox
+	assert.Nil(t, o.add(QueuedMessage{Server: "srv", Content: "a"}, 2))
+	assert.Nil(t, o.add(QueuedMessage{Server: "srv", Content: "b"}, 2))
+	assert.Equal(t, errOutboxFull, o.add(QueuedMessage{Server: "srv", Content: "c"}, 2))
+	assert.Nil(t, o.add(QueuedMessage{Server: "other", Content: "c"}, 2))
+
+	msgs := o.take("srv")
+	assert.Len(t, msgs, 2)
+	assert.Equal(t, "a", msgs[0].Content)
+	assert.Equal(t, "b", msgs[1].Content)
+	assert.Empty(t, o.take("srv"))
+
+	o.clear("other")
+	assert.Empty(t, o.take("other"))
+}
+
+func TestQueueMessage(t *testing.T) {
+	s := NewState(user, New(&config.Config{}))
+	c := irc.NewClient(&irc.Config{Nick: "nick", Host: "outbox.example.com"})
+	assert.False(t, s.queueMessage(c, Message{Server: "outbox.example.com", To: "#chan", Content: "hi"}))
+
+	s.srv.SetConfig(&config.Config{Messages: config.Messages{QueueOffline: true, QueueSize: 1}})
+	assert.True(t, s.queueMessage(c, Message{Server: "outbox.example.com", To: "#chan", Content: "hi"}))
+	res := <-s.broadcast
+	assert.Equal(t, "queued_message", res.Type)
+	queued := res.Data.(QueuedMessage)
+	assert.Equal(t, "#chan", queued.To)
+	assert.Equal(t, "hi", queued.Content)
+	assert.False(t, queued.Sent)
+
+	assert.True(t, s.queueMessage(c, Message{Server: "outbox.example.com", To: "#chan", Content: "again"}))
+	checkResponse(t, "error", Error{
+		Server:  "outbox.example.com",
+		Message: errOutboxFull.Error(),
+	}, <-s.broadcast)
+}
+
+func TestOutboxFlushOnReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	lines := make(chan string, 32)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.Write([]byte(":srv 001 nick :Welcome\r\n"))
+
+		scan := bufio.NewScanner(conn)
+		for scan.Scan() {
+			lines <- scan.Text()
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	c := irc.NewClient(&irc.Config{
+		Nick: "nick",
+		Host: "127.0.0.1",
+		Port: port,
+	})
+	s := NewState(user, New(&config.Config{
+		Messages: config.Messages{
+			QueueOffline: true,
+			QueuePrefix:  true,
+		},
+	}))
+	s.setIRC("127.0.0.1", c)
+
+	h := &wsHandler{state: s}
+	h.initHandlers()
+	h.dispatchRequest(WSRequest{
+		Type: "message",
+		Data: []byte(`{"server":"127.0.0.1","to":"#chan","content":"while down"}`),
+	})
+	res := <-s.broadcast
+	assert.Equal(t, "queued_message", res.Type)
+	assert.False(t, res.Data.(QueuedMessage).Sent)
+
+	c.Connect()
+	go newIRCHandler(c, s).run()
+	defer c.Quit()
+
+	timeout := time.After(2 * time.Second)
+	var line string
+	for !strings.HasPrefix(line, "PRIVMSG") {
+		select {
+		case line = <-lines:
+		case <-timeout:
+			t.Fatal("Queued message was not sent")
+		}
+	}
+	assert.Regexp(t, `^PRIVMSG #chan :\[\d\d:\d\d UTC\] while down$`, line)
+
+	for res.Type != "queued_message" || !res.Data.(QueuedMessage).Sent {
+		select {
+		case res = <-s.broadcast:
+		case <-timeout:
+			t.Fatal("No event for the sent message")
+		}
+	}
+	assert.Equal(t, "while down", res.Data.(QueuedMessage).Content)
+}
+
+func TestSendMessageStatusMsg(t *testing.T) {
+	s := NewState(user, nil)
+	c := irc.NewClient(&irc.Config{Nick: "nick", Host: "host.com"})
+	c.Features.Parse([]string{"nick", "STATUSMSG=@", "are supported"})
+
+	assert.True(t, s.sendMessage(c, Message{Server: "host.com", To: "#chan", Content: "ops", StatusMsg: "@"}))
+	assert.True(t, s.sent.take("host.com", "#chan", "ops"))
+
+	// Sending it to the whole channel would reach more users than intended
+	assert.False(t, s.sendMessage(c, Message{Server: "host.com", To: "#chan", Content: "voiced", StatusMsg: "+"}))
+	assert.False(t, s.sent.take("host.com", "#chan", "voiced"))
+	checkResponse(t, "error", Error{
+		Server:  "host.com",
+		Message: "The server does not support sending messages only to + users",
+	}, <-s.broadcast)
+}
@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/khlieng/dispatch/storage"
+)
+
+// maxPasteLinkLength is the most that is read from the response of an
+// external paste service
+const maxPasteLinkLength = 2048
+
+// pasteCleanupInterval is how often expired pastes are deleted
+var pasteCleanupInterval = time.Hour
+
+var (
+	errBadPasteLink  = errors.New("The paste service did not respond with a link")
+	errPasteTooLarge = errors.New("The paste is too large")
+)
+
+// shouldPaste returns true if content is too long to be sent as is
+func shouldPaste(cfg config.Paste, content string) bool {
+	if !cfg.Enabled {
+		return false
+	}
+	if cfg.MaxLines > 0 && strings.Count(content, "\n")+1 > cfg.MaxLines {
+		return true
+	}
+	return cfg.MaxLength > 0 && len(content) > cfg.MaxLength
+}
+
+// pasteMessage sends msg as a link to a paste of its content
+func (s *State) pasteMessage(i *irc.Client, msg Message) {
+	link, err := s.paste(s.srv.Config().Messages.Paste, msg.Content)
+	if err != nil {
+		log.Println("[Paste]", s.user.ID, msg.Server, msg.To+":", err)
+		s.sendJSON("error", Error{
+			Server:  msg.Server,
+			Message: "Could not paste the message: " + err.Error(),
+		})
+		return
+	}
+
+	s.sendJSON("pasted", Pasted{
+		Server: msg.Server,
+		To:     msg.To,
+		URL:    link,
+	})
+
+	msg.Content = link
+	if !s.queueMessage(i, msg) {
+		s.sendMessage(i, msg)
+	}
+}
+
+// paste uploads content to the paste service, or stores it when there is
+// none, and returns the link to it
+func (s *State) paste(cfg config.Paste, content string) (string, error) {
+	if cfg.Service != "" {
+		return uploadPaste(cfg, content)
+	}
+
+	paste, err := s.createPaste("", []byte(content))
+	if err != nil {
+		return "", err
+	}
+	return s.pasteURL(paste.ID), nil
+}
+
+// createPaste stores a paste for the user within the configured limits
+func (s *State) createPaste(name string, content []byte) (*storage.Paste, error) {
+	var cfg config.Pastes
+	if s.srv != nil {
+		cfg = s.srv.Config().Pastes
+	}
+
+	if cfg.MaxSize > 0 && len(content) > cfg.MaxSize {
+		return nil, errPasteTooLarge
+	}
+	return s.user.CreatePaste(name, content, cfg.Expire)
+}
+
+func (s *State) pasteURL(id string) string {
+	return fmt.Sprintf("%s://%s/paste/%s", s.String("scheme"), s.String("host"), id)
+}
+
+func uploadPaste(cfg config.Paste, content string) (string, error) {
+	ctx := context.Background()
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", cfg.Service, strings.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return "", fmt.Errorf("The paste service responded with %s", res.Status)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, maxPasteLinkLength))
+	if err != nil {
+		return "", err
+	}
+
+	link := strings.TrimSpace(string(body))
+	if !strings.HasPrefix(link, "http://") && !strings.HasPrefix(link, "https://") ||
+		strings.ContainsAny(link, " \r\n") {
+		return "", errBadPasteLink
+	}
+	return link, nil
+}
+
+// servePaste serves a paste stored by dispatch, they are public to
+// anyone with the link. Pastes are always served as plain text, anything
+// else would let a paste run as a script or stylesheet on this origin
+func (d *Dispatch) servePaste(w http.ResponseWriter, r *http.Request, id string) {
+	var state *State
+	if userID, ok := storage.PasteOwner(id); ok {
+		state = d.states.get(userID)
+	}
+	if state == nil {
+		fail(w, http.StatusNotFound)
+		return
+	}
+
+	_, content, err := state.user.GetPaste(id)
+	if err != nil {
+		if err != storage.ErrNotFound {
+			log.Println("[Paste]", id+":", err)
+		}
+		fail(w, http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.Header().Set("Content-Security-Policy", "default-src 'none'")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", disabledCacheControl)
+	w.Write(content)
+}
+
+// runPasteCleanup deletes expired pastes every pasteCleanupInterval, they
+// are never served once expired but stay on disk until then
+func (d *Dispatch) runPasteCleanup() {
+	for {
+		time.Sleep(pasteCleanupInterval)
+		d.deleteExpiredPastes(time.Now())
+	}
+}
+
+func (d *Dispatch) deleteExpiredPastes(now time.Time) int {
+	total := 0
+	for _, state := range d.states.list() {
+		n, err := state.user.DeleteExpiredPastes(now)
+		if err != nil {
+			log.Println("[Paste]", state.user.ID, err)
+		}
+		total += n
+	}
+	return total
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/khlieng/dispatch/pkg/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldPaste(t *testing.T) {
+	cfg := config.Paste{MaxLines: 2, MaxLength: 10}
+	assert.False(t, shouldPaste(cfg, "a\nb\nc"))
+
+	cfg.Enabled = true
+	assert.False(t, shouldPaste(cfg, "short"))
+	assert.False(t, shouldPaste(cfg, "a\nb"))
+	assert.True(t, shouldPaste(cfg, "a\nb\nc"))
+	assert.True(t, shouldPaste(cfg, "a long message"))
+
+	cfg.MaxLength = 0
+	assert.False(t, shouldPaste(cfg, strings.Repeat("a", 1000)))
+}
+
+func pasteTestDispatch(cfg *config.Config) (*Dispatch, *State) {
+	d := New(cfg)
+	d.states = &stateStore{
+		states:   map[uint64]*State{},
+		sessions: map[string]*session.Session{},
+	}
+
+	s := NewState(user, d)
+	s.Set("scheme", "https")
+	s.Set("host", "dispatch.example.com")
+	d.states.set(s)
+	return d, s
+}
+
+func getPaste(d *Dispatch, link string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("GET", strings.TrimPrefix(link, "https://dispatch.example.com"), nil)
+	w := httptest.NewRecorder()
+	d.ServeHTTP(w, r)
+	return w
+}
+
+func TestInternalPaste(t *testing.T) {
+	d, s := pasteTestDispatch(&config.Config{})
+
+	content := "line 1\nline 2\n<script>alert(1)</script>"
+	link, err := s.paste(config.Paste{}, content)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(link, "https://dispatch.example.com/paste/"+strconv.FormatUint(user.ID, 10)+"-"))
+
+	w := getPaste(d, link)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, content, w.Body.String())
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+
+	for _, path := range []string{
+		"/paste/" + strconv.FormatUint(user.ID, 10) + "-AAAAAAAAAAA",
+		"/paste/999999-AAAAAAAAAAA",
+		"/paste/..%2Fdispatch.db",
+		"/paste/",
+	} {
+		w = getPaste(d, path)
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+}
+
+func TestCreatePaste(t *testing.T) {
+	d, s := pasteTestDispatch(&config.Config{
+		Pastes: config.Pastes{MaxSize: 32, Expire: time.Hour},
+	})
+
+	h := &wsHandler{state: s}
+	h.initHandlers()
+	h.dispatchRequest(WSRequest{
+		Type: "create_paste",
+		Data: []byte(`{"name":"../main.go","content":"package main\n"}`),
+	})
+
+	res := <-s.broadcast
+	assert.Equal(t, "paste", res.Type)
+	paste := res.Data.(PasteCreated)
+	assert.Equal(t, "main.go", paste.Name)
+	assert.Equal(t, 13, paste.Size)
+	assert.Equal(t, "https://dispatch.example.com/paste/"+paste.ID, paste.URL)
+	assert.InDelta(t, time.Now().Add(time.Hour).Unix(), paste.Expires, 5)
+
+	w := getPaste(d, paste.URL)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "package main\n", w.Body.String())
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+
+	h.dispatchRequest(WSRequest{
+		Type: "create_paste",
+		Data: []byte(`{"name":"page.html","content":"<h1>hi</h1>"}`),
+	})
+	res = <-s.broadcast
+	w = getPaste(d, res.Data.(PasteCreated).URL)
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+
+	h.dispatchRequest(WSRequest{
+		Type: "create_paste",
+		Data: []byte(`{"name":"script.js","content":"alert(1)"}`),
+	})
+	res = <-s.broadcast
+	w = getPaste(d, res.Data.(PasteCreated).URL)
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+
+	h.dispatchRequest(WSRequest{
+		Type: "create_paste",
+		Data: []byte(`{"content":"` + strings.Repeat("a", 33) + `"}`),
+	})
+	checkResponse(t, "error", Error{
+		Message: errPasteTooLarge.Error(),
+	}, <-s.broadcast)
+
+	// Expired pastes are not served and get cleaned up
+	assert.Equal(t, 0, d.deleteExpiredPastes(time.Now()))
+	assert.Equal(t, 3, d.deleteExpiredPastes(time.Now().Add(2*time.Hour)))
+	w = getPaste(d, paste.URL)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestExternalPaste(t *testing.T) {
+	var posted string
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		posted = string(body)
+
+		switch r.URL.Path {
+		case "/ok":
+			w.Write([]byte("https://paste.example.com/abc\n"))
+		case "/html":
+			w.Write([]byte("<html><body>https://paste.example.com/abc</body></html>"))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer service.Close()
+
+	s := NewState(user, nil)
+
+	link, err := s.paste(config.Paste{Service: service.URL + "/ok"}, "a\nb")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://paste.example.com/abc", link)
+	assert.Equal(t, "a\nb", posted)
+
+	_, err = s.paste(config.Paste{Service: service.URL + "/html"}, "a\nb")
+	assert.Equal(t, errBadPasteLink, err)
+
+	_, err = s.paste(config.Paste{Service: service.URL + "/fail"}, "a\nb")
+	assert.NotNil(t, err)
+}
+
+func TestMessagePasted(t *testing.T) {
+	port, lines := stubIRCServer(t, ":srv 001 nick :Welcome\r\n")
+
+	c := irc.NewClient(&irc.Config{
+		Nick: "nick",
+		Host: "127.0.0.1",
+		Port: port,
+	})
+	s := NewState(user, New(&config.Config{
+		Messages: config.Messages{
+			Paste: config.Paste{Enabled: true, MaxLines: 2},
+		},
+	}))
+	s.Set("scheme", "http")
+	s.Set("host", "localhost")
+	s.setIRC("127.0.0.1", c)
+	c.Connect()
+	go newIRCHandler(c, s).run()
+	defer c.Quit()
+
+	h := &wsHandler{state: s}
+	h.initHandlers()
+	h.dispatchRequest(WSRequest{
+		Type: "message",
+		Data: []byte(`{"server":"127.0.0.1","to":"#chan","content":"one\ntwo\nthree"}`),
+	})
+
+	var res WSResponse
+	timeout := time.After(2 * time.Second)
+	for res.Type != "pasted" {
+		select {
+		case res = <-s.broadcast:
+		case <-timeout:
+			t.Fatal("No pasted event")
+		}
+	}
+	pasted := res.Data.(Pasted)
+	assert.Equal(t, "#chan", pasted.To)
+	assert.True(t, strings.HasPrefix(pasted.URL, "http://localhost/paste/"))
+
+	assert.Equal(t, "PRIVMSG #chan :"+pasted.URL, nextLine(t, lines, "PRIVMSG"))
+}
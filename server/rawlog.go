@@ -0,0 +1,77 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// rotatingFile is an append-only file that gets moved to path.1 once it
+// grows past maxSize, replacing any previous rotation
+type rotatingFile struct {
+	path    string
+	maxSize int64
+	file    *os.File
+	size    int64
+	lock    sync.Mutex
+}
+
+func openRotatingFile(path string, maxSize int64) (*rotatingFile, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &rotatingFile{
+		path:    path,
+		maxSize: maxSize,
+	}
+	return f, f.open()
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		f.file.Close()
+
+		err := os.Rename(f.path, f.path+".1")
+		if err != nil {
+			return 0, err
+		}
+
+		err = f.open()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.file.Close()
+}
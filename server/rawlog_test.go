@@ -0,0 +1,42 @@
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "raw", "srv.log")
+	f, err := openRotatingFile(path, 10)
+	assert.Nil(t, err)
+
+	f.Write([]byte("12345"))
+	f.Write([]byte("67890"))
+	f.Write([]byte("abc"))
+	f.Close()
+
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", string(data))
+
+	data, err = ioutil.ReadFile(path + ".1")
+	assert.Nil(t, err)
+	assert.Equal(t, "1234567890", string(data))
+
+	f, err = openRotatingFile(path, 10)
+	assert.Nil(t, err)
+	f.Write([]byte("defg"))
+	f.Close()
+
+	data, err = ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "abcdefg", string(data))
+}
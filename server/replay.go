@@ -0,0 +1,64 @@
+package server
+
+import (
+	"time"
+)
+
+// replayBufferSize is how many of the last events sent to all sessions of
+// a user are kept around for sessions that reconnect
+const replayBufferSize = 256
+
+// replayBuffer numbers the events sent to all sessions of a user and keeps
+// the last ones, a session that briefly lost its connection gets the ones
+// it missed instead of loading everything again
+type replayBuffer struct {
+	events []WSResponse
+	next   int
+	seq    uint64
+}
+
+func newReplayBuffer(size int) *replayBuffer {
+	return &replayBuffer{
+		events: make([]WSResponse, 0, size),
+		// Starting at the current time in microseconds keeps the numbers
+		// from repeating when the state of the user gets recreated
+		seq: uint64(time.Now().UnixNano() / int64(time.Microsecond)),
+	}
+}
+
+// add assigns the next sequence number to res and stores it
+func (b *replayBuffer) add(res WSResponse) WSResponse {
+	b.seq++
+	res.Seq = b.seq
+
+	if len(b.events) < cap(b.events) {
+		b.events = append(b.events, res)
+	} else {
+		b.events[b.next] = res
+		b.next = (b.next + 1) % len(b.events)
+	}
+	return res
+}
+
+// since returns the events after seq in order, it returns false when
+// some of them are no longer buffered or seq is unknown
+func (b *replayBuffer) since(seq uint64) ([]WSResponse, bool) {
+	if seq > b.seq {
+		return nil, false
+	}
+	if seq == b.seq {
+		return nil, true
+	}
+
+	missed := int(b.seq - seq)
+	if missed > len(b.events) {
+		return nil, false
+	}
+
+	events := make([]WSResponse, 0, missed)
+	start := b.next + len(b.events) - missed
+	for i := 0; i < missed; i++ {
+		events = append(events, b.events[(start+i)%len(b.events)])
+	}
+	return events, true
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayBuffer(t *testing.T) {
+	b := newReplayBuffer(3)
+	start := b.seq
+
+	events, ok := b.since(start)
+	assert.True(t, ok)
+	assert.Empty(t, events)
+
+	for _, typ := range []string{"a", "b"} {
+		b.add(WSResponse{Type: typ})
+	}
+
+	events, ok = b.since(start)
+	assert.True(t, ok)
+	assert.Equal(t, []WSResponse{
+		{Type: "a", Seq: start + 1},
+		{Type: "b", Seq: start + 2},
+	}, events)
+
+	for _, typ := range []string{"c", "d", "e"} {
+		b.add(WSResponse{Type: typ})
+	}
+
+	events, ok = b.since(start + 3)
+	assert.True(t, ok)
+	assert.Equal(t, []WSResponse{
+		{Type: "d", Seq: start + 4},
+		{Type: "e", Seq: start + 5},
+	}, events)
+
+	events, ok = b.since(start + 2)
+	assert.True(t, ok)
+	assert.Len(t, events, 3)
+	assert.Equal(t, "c", events[0].Type)
+
+	_, ok = b.since(start + 1)
+	assert.False(t, ok)
+	_, ok = b.since(start + 6)
+	assert.False(t, ok)
+}
+
+func TestStateResumeWS(t *testing.T) {
+	s := NewState(user, nil)
+	go s.run()
+
+	phone := newWSConn(nil)
+	s.setWS("10.0.0.1:1234", phone)
+	s.sendJSON("away", "first")
+
+	var seq uint64
+	select {
+	case <-phone.queue.ready:
+		events := phone.queue.drain()
+		assert.Len(t, events, 1)
+		seq = events[0].Seq
+	case <-time.After(time.Second):
+		t.Fatal("Session did not receive event")
+	}
+
+	s.deleteWS("10.0.0.1:1234")
+	s.sendJSON("away", "second")
+	s.sendJSON("away", "third")
+
+	// Wait for the events to go through the broadcast
+	time.Sleep(20 * time.Millisecond)
+
+	resumed := newWSConn(nil)
+	assert.True(t, s.resumeWS("10.0.0.1:5678", resumed, seq))
+	events := resumed.queue.drain()
+	assert.Len(t, events, 3)
+	checkResponse(t, "resume", Resume{Replayed: 2}, events[0])
+	assert.Equal(t, "second", events[1].Data)
+	assert.Equal(t, seq+1, events[1].Seq)
+	assert.Equal(t, "third", events[2].Data)
+	assert.Equal(t, seq+2, events[2].Seq)
+	assert.Equal(t, 1, s.numWS())
+
+	assert.False(t, s.resumeWS("10.0.0.1:9999", newWSConn(nil), seq-replayBufferSize))
+	assert.Equal(t, 1, s.numWS())
+}
@@ -17,6 +17,8 @@ import (
 
 	"github.com/dsnet/compress/brotli"
 	"github.com/khlieng/dispatch/assets"
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/session"
 	"github.com/tdewolff/minify/v2"
 	"github.com/tdewolff/minify/v2/html"
 )
@@ -65,6 +67,7 @@ var (
 		".woff":  "application/font-woff",
 		".ttf":   "application/x-font-ttf",
 		".png":   "image/png",
+		".svg":   "image/svg+xml",
 		".ico":   "image/x-icon",
 		".json":  "application/json",
 	}
@@ -81,7 +84,7 @@ func (d *Dispatch) initFileServer() {
 	if cfg.Dev {
 		renderIndexPage(indexTemplateData{
 			Scripts: []string{"/boot.js", "/main.js"},
-		})
+		}, cfg.Compression.IndexLevel())
 	} else {
 		bootloader := decompressedAsset(findAssetName("boot*.js"))
 		runtime := decompressedAsset(findAssetName("runtime*.js"))
@@ -127,30 +130,17 @@ func (d *Dispatch) initFileServer() {
 				}
 			}
 
-			file := &File{
-				ContentType:  contentTypes[filepath.Ext(assetName)],
-				CacheControl: longCacheControl,
-				Compressed:   strings.HasSuffix(asset, ".br"),
-			}
-
 			data, err := assets.Asset(asset)
 			fatalErr(err)
-			file.Data = data
-			file.Length = strconv.Itoa(len(data))
 
-			if file.Compressed {
-				file.GzipData = gzipAsset(data)
-				file.GzipLength = strconv.Itoa(len(file.GzipData))
-			}
-
-			files["/"+assetName] = file
+			files["/"+assetName] = newAssetFile(asset, data, cfg.Compression)
 		}
 
 		renderIndexPage(indexTemplateData{
 			Stylesheet:   indexStylesheet,
 			InlineScript: inlineScript,
 			Scripts:      indexScripts,
-		})
+		}, cfg.Compression.IndexLevel())
 
 		serviceWorker = decompressedAsset("sw.js")
 		hash.Reset()
@@ -174,7 +164,35 @@ func (d *Dispatch) initFileServer() {
 	}
 }
 
-func renderIndexPage(data indexTemplateData) {
+// newAssetFile prepares an asset for serving, brotli compressed assets
+// also get a gzip version and so do other compressible ones when it
+// makes them smaller
+func newAssetFile(name string, data []byte, cfg config.Compression) *File {
+	assetName := strings.TrimSuffix(name, ".br")
+
+	file := &File{
+		Data:         data,
+		Length:       strconv.Itoa(len(data)),
+		ContentType:  contentTypes[filepath.Ext(assetName)],
+		CacheControl: longCacheControl,
+		Compressed:   strings.HasSuffix(name, ".br"),
+	}
+
+	if file.Compressed {
+		file.GzipData = gzipAsset(data, cfg.AssetsLevel())
+	} else if cfg.Compressible(assetName) {
+		if gz := gzipData(data, cfg.AssetsLevel()); len(gz) < len(data) {
+			file.GzipData = gz
+		}
+	}
+	if file.GzipData != nil {
+		file.GzipLength = strconv.Itoa(len(file.GzipData))
+	}
+
+	return file
+}
+
+func renderIndexPage(data indexTemplateData, level int) {
 	tmpl, err := template.New("").Parse(indexTemplate)
 	fatalErr(err)
 
@@ -182,7 +200,7 @@ func renderIndexPage(data indexTemplateData) {
 	m.AddFunc("text/html", html.Minify)
 
 	buf := &bytes.Buffer{}
-	gzw, err := gzip.NewWriterLevel(buf, gzip.BestCompression)
+	gzw, err := gzip.NewWriterLevel(buf, level)
 	fatalErr(err)
 	mw := m.Writer("text/html", gzw)
 
@@ -221,15 +239,23 @@ func decompressedAsset(name string) []byte {
 	return decompressAsset(asset)
 }
 
-func gzipAsset(data []byte) []byte {
+func gzipAsset(data []byte, level int) []byte {
 	br, err := brotli.NewReader(bytes.NewReader(data), nil)
 	fatalErr(err)
 
+	return gzipReader(br, level)
+}
+
+func gzipData(data []byte, level int) []byte {
+	return gzipReader(bytes.NewReader(data), level)
+}
+
+func gzipReader(r io.Reader, level int) []byte {
 	buf := &bytes.Buffer{}
-	gzw, err := gzip.NewWriterLevel(buf, gzip.BestCompression)
+	gzw, err := gzip.NewWriterLevel(buf, level)
 	fatalErr(err)
 
-	io.Copy(gzw, br)
+	io.Copy(gzw, r)
 	gzw.Close()
 	return buf.Bytes()
 }
@@ -254,6 +280,10 @@ func (d *Dispatch) serveFiles(w http.ResponseWriter, r *http.Request) {
 }
 
 func (d *Dispatch) serveIndex(w http.ResponseWriter, r *http.Request) {
+	if d.serveLanding(w, r) {
+		return
+	}
+
 	if pusher, ok := w.(http.Pusher); ok {
 		options := &http.PushOptions{
 			Header: http.Header{
@@ -267,7 +297,7 @@ func (d *Dispatch) serveIndex(w http.ResponseWriter, r *http.Request) {
 				pusher.Push(asset.path, options)
 			}
 
-			setPushCookie(w, r)
+			setPushCookie(w, r, d.cookieOptions())
 		} else {
 			pushed := false
 
@@ -282,7 +312,7 @@ func (d *Dispatch) serveIndex(w http.ResponseWriter, r *http.Request) {
 			}
 
 			if pushed {
-				setPushCookie(w, r)
+				setPushCookie(w, r, d.cookieOptions())
 			}
 		}
 	}
@@ -300,7 +330,7 @@ func (d *Dispatch) serveIndex(w http.ResponseWriter, r *http.Request) {
 			"script-src 'self' 'sha256-" + inlineScriptSha256 + "'",
 			"style-src 'self' 'unsafe-inline'",
 			"font-src 'self'",
-			"img-src 'self'",
+			"img-src 'self'" + d.avatarImgSrc(),
 			"manifest-src 'self'",
 			"connect-src 'self' " + wsSrc,
 			"worker-src 'self'",
@@ -309,6 +339,19 @@ func (d *Dispatch) serveIndex(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Security-Policy", strings.Join(csp, "; "))
 	}
 
+	d.setPageHeaders(w)
+
+	if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Length", indexPageLen)
+		w.Write(indexPage)
+	} else {
+		serveDecompressed(w, indexPage)
+	}
+}
+
+// setPageHeaders sets the headers shared by the HTML pages
+func (d *Dispatch) setPageHeaders(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "text/html")
 	w.Header().Set("Cache-Control", disabledCacheControl)
 	w.Header().Set("X-Content-Type-Options", "nosniff")
@@ -323,25 +366,20 @@ func (d *Dispatch) serveIndex(w http.ResponseWriter, r *http.Request) {
 	for k, v := range d.Config().Headers {
 		w.Header().Set(k, v)
 	}
-
-	if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
-		w.Header().Set("Content-Encoding", "gzip")
-		w.Header().Set("Content-Length", indexPageLen)
-		w.Write(indexPage)
-	} else {
-		serveDecompressed(w, indexPage)
-	}
 }
 
-func setPushCookie(w http.ResponseWriter, r *http.Request) {
-	http.SetCookie(w, &http.Cookie{
+func setPushCookie(w http.ResponseWriter, r *http.Request, opts session.CookieOptions) {
+	cookie := &http.Cookie{
 		Name:     "push",
 		Value:    h2PushCookieValue,
 		Path:     "/",
 		Expires:  time.Now().AddDate(1, 0, 0),
 		HttpOnly: true,
 		Secure:   r.TLS != nil,
-	})
+	}
+	opts.Apply(cookie)
+
+	http.SetCookie(w, cookie)
 }
 
 func (d *Dispatch) serveFile(w http.ResponseWriter, r *http.Request, file *File) {
@@ -352,7 +390,7 @@ func (d *Dispatch) serveFile(w http.ResponseWriter, r *http.Request, file *File)
 		w.Header().Set("Content-Encoding", "br")
 		w.Header().Set("Content-Length", file.Length)
 		w.Write(file.Data)
-	} else if file.Compressed && strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+	} else if file.GzipData != nil && strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
 		w.Header().Set("Content-Encoding", "gzip")
 		w.Header().Set("Content-Length", file.GzipLength)
 		w.Write(file.GzipData)
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/khlieng/dispatch/assets"
+	"github.com/khlieng/dispatch/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAssetFile(t *testing.T) {
+	cfg := config.Compression{
+		Assets:     9,
+		Extensions: []string{".svg", ".txt"},
+	}
+
+	png, err := assets.Asset("icon_192.png")
+	assert.Nil(t, err)
+	file := newAssetFile("icon_192.png", png, cfg)
+	assert.False(t, file.Compressed)
+	assert.Nil(t, file.GzipData)
+	assert.Equal(t, "image/png", file.ContentType)
+
+	svg := []byte(strings.Repeat(`<svg><path d="M0 0h24v24H0z"/></svg>`, 20))
+	file = newAssetFile("icon.svg", svg, cfg)
+	assert.False(t, file.Compressed)
+	assert.NotNil(t, file.GzipData)
+	assert.Equal(t, strconv.Itoa(len(file.GzipData)), file.GzipLength)
+	gzr, err := gzip.NewReader(bytes.NewReader(file.GzipData))
+	assert.Nil(t, err)
+	data, err := ioutil.ReadAll(gzr)
+	assert.Nil(t, err)
+	assert.Equal(t, svg, data)
+
+	// Gzipping tiny files makes them bigger
+	file = newAssetFile("tiny.txt", []byte("a"), cfg)
+	assert.Nil(t, file.GzipData)
+
+	name := findAssetName("main*.css")
+	css, err := assets.Asset(name + ".br")
+	assert.Nil(t, err)
+	file = newAssetFile(name+".br", css, cfg)
+	assert.True(t, file.Compressed)
+	assert.NotNil(t, file.GzipData)
+}
+
+func TestServeIncompressibleFile(t *testing.T) {
+	png, err := assets.Asset("icon_192.png")
+	assert.Nil(t, err)
+	file := newAssetFile("icon_192.png", png, config.Compression{
+		Extensions: []string{".png"},
+	})
+
+	d := New(&config.Config{})
+	r := httptest.NewRequest("GET", "/icon_192.png", nil)
+	r.Header.Set("Accept-Encoding", "gzip, br")
+	w := httptest.NewRecorder()
+	d.serveFile(w, r, file)
+
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, strconv.Itoa(len(png)), w.Header().Get("Content-Length"))
+	assert.Equal(t, png, w.Body.Bytes())
+}
+
+func BenchmarkGzipAsset(b *testing.B) {
+	data, err := assets.Asset(findAssetName("main*.js") + ".br")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for level := gzip.BestSpeed; level <= gzip.BestCompression; level++ {
+		b.Run("level"+strconv.Itoa(level), func(b *testing.B) {
+			var size int
+			for i := 0; i < b.N; i++ {
+				size = len(gzipAsset(data, level))
+			}
+			b.ReportMetric(float64(size), "bytes")
+		})
+	}
+}
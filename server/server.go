@@ -1,37 +1,61 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/khlieng/dispatch/config"
 	"github.com/khlieng/dispatch/pkg/https"
 	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/khlieng/dispatch/pkg/logging"
 	"github.com/khlieng/dispatch/pkg/session"
 	"github.com/khlieng/dispatch/storage"
 )
 
 var channelIndexes = storage.NewChannelIndexManager()
 
+// shutdownTimeout is how long Shutdown waits for the IRC connections
+// to close
+var shutdownTimeout = 5 * time.Second
+
 type Dispatch struct {
 	Store        storage.Store
 	SessionStore storage.SessionStore
+	// Filters run on every incoming IRC message, in order
+	Filters []MessageFilter
 
 	cfg      *config.Config
+	previews *linkPreviewer
 	upgrader websocket.Upgrader
 	states   *stateStore
-	lock     sync.Mutex
+	// downloadKey signs download URLs on the downloads origin, it changes
+	// on every restart
+	downloadKey []byte
+	// dccLimiter caps the combined speed of all DCC downloads
+	dccLimiter *irc.RateLimiter
+	// compactLock makes compactions of all stores run one at a time
+	compactLock sync.Mutex
+	lock        sync.Mutex
 }
 
 func New(cfg *config.Config) *Dispatch {
-	return &Dispatch{
-		cfg: cfg,
+	d := &Dispatch{
+		cfg:         cfg,
+		downloadKey: newDownloadKey(),
+		dccLimiter:  irc.NewRateLimiter(cfg.DCC.MaxTotalSpeed * 1024),
 	}
+	d.previews = newLinkPreviewer(func() config.LinkPreviews {
+		return d.Config().LinkPreviews
+	})
+	return d
 }
 
 func (d *Dispatch) Config() *config.Config {
@@ -45,6 +69,46 @@ func (d *Dispatch) SetConfig(cfg *config.Config) {
 	d.lock.Lock()
 	d.cfg = cfg
 	d.lock.Unlock()
+
+	if err := cfg.Log.Configure(logging.Default()); err != nil {
+		log.Println(err)
+	}
+
+	if d.dccLimiter != nil {
+		d.dccLimiter.SetRate(cfg.DCC.MaxTotalSpeed * 1024)
+	}
+
+	if d.states != nil {
+		var users []*storage.User
+		for _, state := range d.states.list() {
+			users = append(users, state.user)
+		}
+		d.promoteAdmins(users)
+	}
+}
+
+// promoteAdmins makes the users listed as admins in the config admins,
+// users that are removed from the list keep their role
+func (d *Dispatch) promoteAdmins(users []*storage.User) {
+	admins := d.Config().Admins
+
+	for _, user := range users {
+		if user.IsAdmin() {
+			continue
+		}
+
+		for _, admin := range admins {
+			if user.Username == admin {
+				err := user.SetAdmin(true)
+				if err != nil {
+					log.Println(err)
+				} else {
+					log.Println("[Admin]", user.Username, "promoted to admin by config")
+				}
+				break
+			}
+		}
+	}
 }
 
 func (d *Dispatch) Run() {
@@ -65,10 +129,39 @@ func (d *Dispatch) Run() {
 	go d.states.run()
 
 	d.loadUsers()
+	go d.runCompaction()
+	go d.runPasteCleanup()
 	d.initFileServer()
 	d.startHTTP()
 }
 
+// Shutdown closes the connections of all users, the IRC servers get the
+// quit message of the user
+func (d *Dispatch) Shutdown() {
+	if d.states == nil {
+		return
+	}
+
+	var clients []*irc.Client
+	for _, state := range d.states.list() {
+		for _, i := range state.getIRCs() {
+			clients = append(clients, i)
+		}
+		state.kill()
+	}
+
+	log.Println("[Shutdown] Closing", len(clients), "IRC connections")
+
+	timeout := time.After(shutdownTimeout)
+	for _, i := range clients {
+		select {
+		case <-i.Done():
+		case <-timeout:
+			return
+		}
+	}
+}
+
 func (d *Dispatch) loadUsers() {
 	users, err := storage.LoadUsers(d.Store)
 	if err != nil {
@@ -77,6 +170,8 @@ func (d *Dispatch) loadUsers() {
 
 	log.Printf("[Init] %d users", len(users))
 
+	d.promoteAdmins(users)
+
 	for _, user := range users {
 		go d.loadUser(user)
 	}
@@ -87,27 +182,8 @@ func (d *Dispatch) loadUser(user *storage.User) {
 	d.states.set(state)
 	go state.run()
 
-	channels, err := user.GetChannels()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	servers, err := user.GetServers()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	for _, server := range servers {
-		i := connectIRC(server, state, user.GetLastIP())
-
-		var joining []string
-		for _, channel := range channels {
-			if channel.Server == server.Host {
-				joining = append(joining, channel.Name)
-			}
-		}
-		i.Join(joining...)
-	}
+	state.connectServers()
+	state.startIdleTimer()
 }
 
 func (d *Dispatch) startHTTP() {
@@ -167,34 +243,81 @@ func (d *Dispatch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 
 		d.upgradeWS(w, r, state)
-	} else if strings.HasPrefix(r.URL.Path, "/downloads") {
+	} else if strings.HasPrefix(r.URL.Path, "/paste/") {
+		d.servePaste(w, r, strings.TrimPrefix(r.URL.Path, "/paste/"))
+	} else if strings.HasPrefix(r.URL.Path, "/logs/") {
+		params := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/logs/"), "/", 2)
+		if len(params) != 2 || params[0] == "" || params[1] == "" {
+			fail(w, http.StatusNotFound)
+			return
+		}
+
 		state := d.handleAuth(w, r, false, false)
 		if state == nil {
-			log.Println("[Auth] No state")
-			fail(w, http.StatusInternalServerError)
+			fail(w, http.StatusUnauthorized)
 			return
 		}
 
+		d.serveLogs(w, r, state, params[0], params[1])
+	} else if strings.HasPrefix(r.URL.Path, "/downloads") {
 		params := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
 
 		if len(params) == 3 {
 			userID, err := strconv.ParseUint(params[1], 10, 64)
 			if err != nil {
 				fail(w, http.StatusBadRequest)
+				return
+			}
+
+			var state *State
+			if token := r.URL.Query().Get("token"); token != "" {
+				if d.validDownloadToken(userID, params[2], token) {
+					state = d.states.get(userID)
+				}
+			} else {
+				state = d.handleAuth(w, r, false, false)
+			}
+
+			if state == nil {
+				log.Println("[Auth] No state")
+				fail(w, http.StatusUnauthorized)
+				return
 			}
 
 			if userID != state.user.ID {
 				fail(w, http.StatusUnauthorized)
+				return
 			}
 
 			filename := params[2]
-			w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+			setDownloadHeaders(w.Header(), filename, d.Config().DCC.CacheControl)
 
-			if pack, ok := state.getPendingDCC(filename); ok {
+			if pending, ok := state.getPendingDCC(filename); ok {
+				if !state.acquireDCC() {
+					fail(w, http.StatusTooManyRequests)
+					return
+				}
+				defer state.releaseDCC()
 				state.deletePendingDCC(filename)
 
-				w.Header().Set("Content-Length", strconv.FormatUint(pack.Length, 10))
-				irc.DownloadDCC(w, pack, nil)
+				progress := make(chan irc.DownloadProgress, 4)
+				go func() {
+					for p := range progress {
+						state.setDCCProgress(p)
+					}
+				}()
+
+				transfer, ctx := state.startDCCTransfer(pending.client.Host(), pending.from, pending.pack)
+				w.Header().Set("Content-Length", strconv.FormatUint(pending.pack.Length, 10))
+				err := downloadDCC(ctx, w, d.Config().DCC, pending.client, pending.from, pending.pack, progress, d.dccLimiter)
+				state.endDCCTransfer(transfer)
+				close(progress)
+
+				if errors.Is(err, context.Canceled) {
+					state.sendDCCCancelled(pending.client.Host(), filename, "")
+				} else if err != nil {
+					log.Println("[DCC]", state.user.ID, filename+":", err)
+				}
 			} else {
 				file := storage.Path.DownloadedFile(state.user.Username, filename)
 				http.ServeFile(w, r, file)
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+)
+
+// serverCheckTimeout is how long checkServer waits for the server to
+// complete registration
+var serverCheckTimeout = 10 * time.Second
+
+var (
+	errServerCheckTimeout = errors.New("Timed out waiting for the server")
+	errServerCheckClosed  = errors.New("The server closed the connection")
+)
+
+// checkServer connects to a server without adding it to see if it can be
+// reached and registered with, the connection is closed as soon as the
+// server has told its network name or finished sending the MOTD
+func checkServer(cfg *config.Config, data ServerCheck) ServerCheckResult {
+	res := ServerCheckResult{
+		Host: strings.ToLower(data.Host),
+		Port: data.Port,
+		TLS:  data.TLS,
+	}
+
+	nick := data.Nick
+	if nick == "" {
+		nick = "dispatch"
+	}
+
+	ircCfg := irc.Config{
+		Host:                res.Host,
+		Port:                data.Port,
+		TLS:                 data.TLS,
+		Nick:                nick,
+		BindAddress:         getBindAddress(cfg, res.Host),
+		FallbackDelay:       cfg.FallbackDelay,
+		PreferIPv4:          cfg.PreferIPv4,
+		DNSTimeout:          cfg.Timeouts.DNS,
+		ConnectTimeout:      cfg.Timeouts.Connect,
+		TLSTimeout:          cfg.Timeouts.TLS,
+		RegistrationTimeout: serverCheckTimeout,
+	}
+	if data.TLS {
+		ircCfg.TLSConfig = &tls.Config{
+			InsecureSkipVerify: !cfg.VerifyCertificates,
+		}
+	}
+
+	i := irc.NewClient(&ircCfg)
+	res.Port = ircCfg.Port
+	i.Connect()
+	defer closeServerCheck(i)
+
+	connected := false
+	timeout := time.After(serverCheckTimeout)
+	for {
+		select {
+		case state := <-i.ConnectionChanged:
+			if state.Error != nil {
+				res.Error = state.Error.Error()
+				return res
+			}
+			if state.Connected {
+				connected = true
+			} else if connected {
+				res.Error = errServerCheckClosed.Error()
+				return res
+			}
+
+		case msg, ok := <-i.Messages:
+			if !ok {
+				res.Error = errServerCheckClosed.Error()
+				return res
+			}
+
+			switch msg.Command {
+			case irc.RPL_WELCOME:
+				res.OK = true
+
+			case irc.RPL_ISUPPORT:
+				if network := i.Features.String("NETWORK"); network != "" {
+					res.Network = network
+					return res
+				}
+
+			case irc.RPL_ENDOFMOTD, irc.ERR_NOMOTD:
+				return res
+
+			case irc.ERROR:
+				res.Error = msg.LastParam()
+				return res
+			}
+
+		case <-timeout:
+			if !res.OK {
+				res.Error = errServerCheckTimeout.Error()
+			}
+			return res
+		}
+	}
+}
+
+// closeServerCheck quits and keeps reading from the client until it is
+// done so it does not get stuck sending to a full channel
+func closeServerCheck(i *irc.Client) {
+	i.Quit()
+
+	go func() {
+		for {
+			select {
+			case <-i.ConnectionChanged:
+			case _, ok := <-i.Messages:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+}
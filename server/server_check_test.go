@@ -0,0 +1,136 @@
+package server
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/stretchr/testify/assert"
+)
+
+func stubIRCServer(t *testing.T, reply string) (string, chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	lines := make(chan string, 32)
+	go func() {
+		defer ln.Close()
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		scan := bufio.NewScanner(conn)
+		for scan.Scan() {
+			lines <- scan.Text()
+			if strings.HasPrefix(scan.Text(), "USER") {
+				conn.Write([]byte(reply))
+			}
+		}
+		close(lines)
+	}()
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	return port, lines
+}
+
+func TestCheckServer(t *testing.T) {
+	port, lines := stubIRCServer(t, ":srv 001 check :Welcome\r\n"+
+		":srv 005 check CHANTYPES=# NETWORK=StubNet :are supported by this server\r\n")
+
+	res := checkServer(&config.Config{}, ServerCheck{
+		Host: "127.0.0.1",
+		Port: port,
+		Nick: "check",
+	})
+	assert.Equal(t, ServerCheckResult{
+		Host:    "127.0.0.1",
+		Port:    port,
+		OK:      true,
+		Network: "StubNet",
+	}, res)
+
+	var received []string
+	timeout := time.After(time.Second)
+	for len(received) == 0 || received[len(received)-1] != "QUIT" {
+		select {
+		case line := <-lines:
+			received = append(received, line)
+		case <-timeout:
+			t.Fatal("Connection was not closed")
+		}
+	}
+	assert.Contains(t, received, "NICK check")
+}
+
+func TestCheckServerError(t *testing.T) {
+	port, _ := stubIRCServer(t, "ERROR :Closing link: (banned)\r\n")
+
+	res := checkServer(&config.Config{}, ServerCheck{
+		Host: "127.0.0.1",
+		Port: port,
+	})
+	assert.False(t, res.OK)
+	assert.Equal(t, "Closing link: (banned)", res.Error)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	_, port, _ = net.SplitHostPort(ln.Addr().String())
+	ln.Close()
+
+	res = checkServer(&config.Config{}, ServerCheck{
+		Host: "127.0.0.1",
+		Port: port,
+	})
+	assert.False(t, res.OK)
+	assert.NotEmpty(t, res.Error)
+}
+
+func TestCheckServerRequest(t *testing.T) {
+	port, _ := stubIRCServer(t, ":srv 001 dispatch :Welcome\r\n:srv 422 dispatch :No MOTD\r\n")
+
+	s := NewState(user, New(&config.Config{}))
+	h := &wsHandler{state: s}
+	h.initHandlers()
+	h.dispatchRequest(WSRequest{
+		Type: "check_server",
+		Data: []byte(`{"host":"127.0.0.1","port":"` + port + `"}`),
+	})
+
+	select {
+	case res := <-s.broadcast:
+		checkResponse(t, "server_check", ServerCheckResult{
+			Host: "127.0.0.1",
+			Port: port,
+			OK:   true,
+		}, res)
+	case <-time.After(2 * time.Second):
+		t.Fatal("No server_check event")
+	}
+
+	servers, err := s.user.GetServers()
+	assert.Nil(t, err)
+	for _, server := range servers {
+		assert.NotEqual(t, "127.0.0.1", server.Host)
+	}
+}
+
+func TestCheckServerTLSTimeout(t *testing.T) {
+	// The stub never answers the handshake
+	port, _ := stubIRCServer(t, "")
+
+	res := checkServer(&config.Config{
+		Timeouts: config.Timeouts{TLS: 50 * time.Millisecond},
+	}, ServerCheck{
+		Host: "127.0.0.1",
+		Port: port,
+		TLS:  true,
+	})
+	assert.False(t, res.OK)
+	assert.Equal(t, irc.ErrTLSTimeout.Error(), res.Error)
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"net"
+	"strings"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/khlieng/dispatch/storage"
+)
+
+// splitServerHost moves a port passed as part of the host to Port,
+// a port starting with + enables TLS
+func splitServerHost(server *storage.Server) {
+	if server.Port == "" {
+		if host, port, err := net.SplitHostPort(server.Host); err == nil && host != "" {
+			server.Host = host
+			server.Port = port
+		}
+	}
+
+	if strings.HasPrefix(server.Port, "+") {
+		server.Port = server.Port[1:]
+		server.TLS = true
+	}
+}
+
+// inferServerPort fills in the port of a server that is being added,
+// servers without a port get the configured TLS or plain port. When
+// DetectTLS is enabled the TLS port gets probed first
+func inferServerPort(cfg *config.Config, server *storage.Server) {
+	splitServerHost(server)
+	if server.Port != "" {
+		return
+	}
+
+	ports := cfg.ServerPorts
+	if !server.TLS && ports.DetectTLS {
+		server.TLS = probeTLS(cfg, server.Host, ports.TLSPort())
+	}
+
+	if server.TLS {
+		server.Port = ports.TLSPort()
+	} else {
+		server.Port = ports.PlainPort()
+	}
+}
+
+// probeTLS returns true if host completes a TLS handshake on port, it is
+// dialed with the same settings and timeouts as connectIRC uses. The
+// certificate is not verified here since the connection itself does that
+func probeTLS(cfg *config.Config, host, port string) bool {
+	i := irc.NewClient(&irc.Config{
+		Host:           host,
+		Port:           port,
+		BindAddress:    getBindAddress(cfg, host),
+		FallbackDelay:  cfg.FallbackDelay,
+		PreferIPv4:     cfg.PreferIPv4,
+		DNSTimeout:     cfg.Timeouts.DNS,
+		ConnectTimeout: cfg.Timeouts.Connect,
+		TLSTimeout:     cfg.Timeouts.TLS,
+	})
+	return i.ProbeTLS()
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferServerPort(t *testing.T) {
+	cfg := &config.Config{}
+	custom := &config.Config{
+		ServerPorts: config.ServerPorts{Plain: "7000", TLS: "7070"},
+	}
+
+	cases := []struct {
+		cfg      *config.Config
+		input    storage.Server
+		expected storage.Server
+	}{
+		{cfg, storage.Server{Host: "irc.example.com"}, storage.Server{Host: "irc.example.com", Port: "6667"}},
+		{cfg, storage.Server{Host: "irc.example.com", TLS: true}, storage.Server{Host: "irc.example.com", Port: "6697", TLS: true}},
+		{cfg, storage.Server{Host: "irc.example.com", Port: "6697"}, storage.Server{Host: "irc.example.com", Port: "6697"}},
+		{cfg, storage.Server{Host: "irc.example.com", Port: "+6697"}, storage.Server{Host: "irc.example.com", Port: "6697", TLS: true}},
+		{cfg, storage.Server{Host: "irc.example.com:6668"}, storage.Server{Host: "irc.example.com", Port: "6668"}},
+		{cfg, storage.Server{Host: "irc.example.com:+7000"}, storage.Server{Host: "irc.example.com", Port: "7000", TLS: true}},
+		{cfg, storage.Server{Host: "[::1]:6667"}, storage.Server{Host: "::1", Port: "6667"}},
+		{cfg, storage.Server{Host: "::1"}, storage.Server{Host: "::1", Port: "6667"}},
+		{cfg, storage.Server{Host: "irc.example.com:6668", Port: "6669"}, storage.Server{Host: "irc.example.com:6668", Port: "6669"}},
+		{custom, storage.Server{Host: "irc.example.com"}, storage.Server{Host: "irc.example.com", Port: "7000"}},
+		{custom, storage.Server{Host: "irc.example.com", TLS: true}, storage.Server{Host: "irc.example.com", Port: "7070", TLS: true}},
+	}
+
+	for _, tc := range cases {
+		server := tc.input
+		inferServerPort(tc.cfg, &server)
+		assert.Equal(t, tc.expected, server, tc.input.Host+" "+tc.input.Port)
+	}
+}
+
+func TestInferServerPortDetectTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	_, tlsPort, _ := net.SplitHostPort(srv.Listener.Addr().String())
+
+	cfg := &config.Config{
+		ServerPorts: config.ServerPorts{Plain: "6667", TLS: tlsPort, DetectTLS: true},
+	}
+	server := storage.Server{Host: "127.0.0.1"}
+	inferServerPort(cfg, &server)
+	assert.True(t, server.TLS)
+	assert.Equal(t, tlsPort, server.Port)
+
+	// The stub never answers the handshake
+	cfg.Timeouts.TLS = 100 * time.Millisecond
+
+	plainPort, _ := stubIRCServer(t, "")
+	cfg.ServerPorts.TLS = plainPort
+	server = storage.Server{Host: "127.0.0.1"}
+	inferServerPort(cfg, &server)
+	assert.False(t, server.TLS)
+	assert.Equal(t, "6667", server.Port)
+}
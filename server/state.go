@@ -1,7 +1,12 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -14,6 +19,10 @@ const (
 	// AnonymousUserExpiration is the time to wait before removing an anonymous
 	// user that has no irc or websocket connections
 	AnonymousUserExpiration = 1 * time.Minute
+
+	// replayLimit caps how many messages per channel that were missed while
+	// no sessions were connected get sent to a new session
+	replayLimit = 500
 )
 
 // State is the live state of a single user
@@ -22,10 +31,43 @@ type State struct {
 
 	irc             map[string]*irc.Client
 	connectionState map[string]irc.ConnectionState
-	pendingDCCSends map[string]*irc.DCCSend
-	ircLock         sync.Mutex
+	pendingDCCSends map[string]*pendingDCC
+	activeDCC       int
+	dccTransfers    map[string]*dccTransfer
+	pendingCTCP     map[string]*ctcpRequest
+	rawLogs         map[string]*rotatingFile
+	// forwardTags are the client-only tags passed on from incoming
+	// messages, per server
+	forwardTags map[string][]string
+	// joined holds the casefolded channels the user is in, per server,
+	// for recognizing JOINs that bouncers replay
+	joined map[string]map[string]bool
+	// lastJoined holds the channels of the last connection to each
+	// server, bouncers replay the JOINs for them after reconnecting
+	lastJoined map[string]map[string]bool
+	// who holds the accounts and hosts from WHO replies, per server,
+	// casefolded channel and nick
+	who map[string]map[string]map[string]whoUser
+	// metadata holds the keys other users have set, per server and nick
+	metadata map[string]map[string]map[string]string
+	// connects holds when the user opened new connections, for
+	// limiting the rate of them
+	connects []time.Time
+	// suspended is set when the IRC connections got closed because the
+	// user was idle
+	suspended bool
+	ircLock   sync.Mutex
+	// connectLock serializes adding servers, which can take a while
+	// when the TLS port gets probed
+	connectLock sync.Mutex
+
+	sent   sentMessages
+	outbox outbox
 
 	ws        map[string]*wsConn
+	replay    *replayBuffer
+	lastSeen  time.Time
+	idle      *time.Timer
 	wsLock    sync.Mutex
 	broadcast chan WSResponse
 
@@ -40,8 +82,17 @@ func NewState(user *storage.User, srv *Dispatch) *State {
 		stateData:       stateData{m: map[string]interface{}{}},
 		irc:             make(map[string]*irc.Client),
 		connectionState: make(map[string]irc.ConnectionState),
-		pendingDCCSends: make(map[string]*irc.DCCSend),
+		pendingDCCSends: make(map[string]*pendingDCC),
+		dccTransfers:    make(map[string]*dccTransfer),
+		pendingCTCP:     make(map[string]*ctcpRequest),
+		rawLogs:         make(map[string]*rotatingFile),
+		forwardTags:     make(map[string][]string),
+		joined:          make(map[string]map[string]bool),
+		lastJoined:      make(map[string]map[string]bool),
+		who:             make(map[string]map[string]map[string]whoUser),
+		metadata:        make(map[string]map[string]map[string]string),
 		ws:              make(map[string]*wsConn),
+		replay:          newReplayBuffer(replayBufferSize),
 		broadcast:       make(chan WSResponse, 32),
 		srv:             srv,
 		user:            user,
@@ -58,6 +109,17 @@ func (s *State) getIRC(server string) (*irc.Client, bool) {
 	return i, ok
 }
 
+func (s *State) getIRCs() map[string]*irc.Client {
+	s.ircLock.Lock()
+	clients := make(map[string]*irc.Client, len(s.irc))
+	for server, i := range s.irc {
+		clients[server] = i
+	}
+	s.ircLock.Unlock()
+
+	return clients
+}
+
 func (s *State) setIRC(server string, i *irc.Client) {
 	s.ircLock.Lock()
 	s.irc[server] = i
@@ -73,11 +135,33 @@ func (s *State) deleteIRC(server string) {
 	s.ircLock.Lock()
 	delete(s.irc, server)
 	delete(s.connectionState, server)
+	if f, ok := s.rawLogs[server]; ok {
+		f.Close()
+		delete(s.rawLogs, server)
+	}
+	delete(s.forwardTags, server)
+	delete(s.joined, server)
+	delete(s.lastJoined, server)
+	delete(s.who, server)
+	delete(s.metadata, server)
 	s.ircLock.Unlock()
 
+	s.outbox.clear(server)
+
 	s.resetExpirationIfEmpty()
 }
 
+// quitMessage is sent when disconnecting from a server without a
+// message given
+func (s *State) quitMessage() string {
+	return leaveMessage(s.user.GetClientSettings().QuitMessage)
+}
+
+// partMessage is sent when leaving a channel without a message given
+func (s *State) partMessage() string {
+	return leaveMessage(s.user.GetClientSettings().PartMessage)
+}
+
 func (s *State) numIRC() int {
 	s.ircLock.Lock()
 	n := len(s.irc)
@@ -100,20 +184,105 @@ func (s *State) getConnectionStates() map[string]irc.ConnectionState {
 
 func (s *State) setConnectionState(server string, state irc.ConnectionState) {
 	s.ircLock.Lock()
+	// The channels have to be joined again when the connection drops,
+	// failed reconnects also report being disconnected so only the first
+	// one moves them to lastJoined
+	if s.connectionState[server].Connected && !state.Connected {
+		s.lastJoined[server] = s.joined[server]
+		delete(s.joined, server)
+	}
 	s.connectionState[server] = state
 	s.ircLock.Unlock()
 }
 
-func (s *State) getPendingDCC(filename string) (*irc.DCCSend, bool) {
+func (s *State) getForwardTags(server string) []string {
+	s.ircLock.Lock()
+	tags := s.forwardTags[server]
+	s.ircLock.Unlock()
+	return tags
+}
+
+func (s *State) setForwardTags(server string, tags []string) {
+	s.ircLock.Lock()
+	s.forwardTags[server] = tags
+	s.ircLock.Unlock()
+}
+
+// setJoined records that the user is in channel on server, it returns
+// false if they already were, on this or the last connection
+func (s *State) setJoined(server, channel string) bool {
+	s.ircLock.Lock()
+	defer s.ircLock.Unlock()
+
+	channels, ok := s.joined[server]
+	if !ok {
+		channels = map[string]bool{}
+		s.joined[server] = channels
+	}
+	if channels[channel] {
+		return false
+	}
+	channels[channel] = true
+
+	if s.lastJoined[server][channel] {
+		delete(s.lastJoined[server], channel)
+		return false
+	}
+	return true
+}
+
+func (s *State) setParted(server, channel string) {
+	s.ircLock.Lock()
+	delete(s.joined[server], channel)
+	delete(s.lastJoined[server], channel)
+	s.ircLock.Unlock()
+}
+
+// ignoreDuplicateJoins returns true if JOINs for channels the user is
+// already in should not get the scrollback sent again
+func (s *State) ignoreDuplicateJoins() bool {
+	return s.srv != nil && s.srv.Config().IgnoreDuplicateJoins
+}
+
+// setRawLog turns logging of the raw IRC traffic for a server on or off
+func (s *State) setRawLog(server string, enabled bool, maxSize int64) error {
+	i, ok := s.getIRC(server)
+	if !ok {
+		return nil
+	}
+
+	s.ircLock.Lock()
+	defer s.ircLock.Unlock()
+
+	if f, ok := s.rawLogs[server]; ok {
+		i.SetRawLog(nil)
+		f.Close()
+		delete(s.rawLogs, server)
+	}
+
+	if enabled {
+		f, err := openRotatingFile(storage.Path.RawLog(s.user.Username, server), maxSize)
+		if err != nil {
+			return err
+		}
+
+		i.SetRawLog(f)
+		s.rawLogs[server] = f
+	}
+
+	return nil
+}
+
+func (s *State) getPendingDCC(filename string) (*pendingDCC, bool) {
 	s.ircLock.Lock()
 	pack, ok := s.pendingDCCSends[filename]
 	s.ircLock.Unlock()
 	return pack, ok
 }
 
-func (s *State) setPendingDCC(filename string, pack *irc.DCCSend) {
+func (s *State) setPendingDCC(filename string, pending *pendingDCC) {
 	s.ircLock.Lock()
-	s.pendingDCCSends[filename] = pack
+	s.pendingDCCSends[filename] = pending
 	s.ircLock.Unlock()
 }
 
@@ -123,32 +292,415 @@ func (s *State) deletePendingDCC(filename string) {
 	s.ircLock.Unlock()
 }
 
-func (s *State) setWS(addr string, w *wsConn) {
+// acquireDCC reserves a slot for a DCC download, it returns false when the
+// user already has the configured maximum number of downloads running
+func (s *State) acquireDCC() bool {
+	limit := 0
+	if s.srv != nil {
+		limit = s.srv.Config().DCC.MaxDownloads
+	}
+
+	s.ircLock.Lock()
+	defer s.ircLock.Unlock()
+
+	if limit > 0 && s.activeDCC >= limit {
+		return false
+	}
+	s.activeDCC++
+	return true
+}
+
+func (s *State) releaseDCC() {
+	s.ircLock.Lock()
+	if s.activeDCC > 0 {
+		s.activeDCC--
+	}
+	s.ircLock.Unlock()
+}
+
+// startDCCTransfer tracks a running download of pack so that it can be
+// listed and cancelled, the returned context is done when it gets
+// cancelled and endDCCTransfer has to be called when the download ends
+func (s *State) startDCCTransfer(server, from string, pack *irc.DCCSend) (*dccTransfer, context.Context) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &dccTransfer{
+		server:   server,
+		from:     from,
+		pack:     pack,
+		progress: irc.DownloadProgress{File: pack.File},
+		cancel:   cancel,
+	}
+
+	s.ircLock.Lock()
+	s.dccTransfers[pack.File] = t
+	s.ircLock.Unlock()
+
+	return t, ctx
+}
+
+// endDCCTransfer stops tracking t, it returns whether the partially
+// downloaded file should be deleted
+func (s *State) endDCCTransfer(t *dccTransfer) bool {
+	t.cancel()
+
+	s.ircLock.Lock()
+	defer s.ircLock.Unlock()
+
+	if s.dccTransfers[t.pack.File] == t {
+		delete(s.dccTransfers, t.pack.File)
+	}
+	return t.deletePartial
+}
+
+func (s *State) setDCCProgress(progress irc.DownloadProgress) {
+	s.ircLock.Lock()
+	if t, ok := s.dccTransfers[progress.File]; ok {
+		t.progress = progress
+	}
+	s.ircLock.Unlock()
+}
+
+func (s *State) getDCCTransfers() []DCCTransfer {
+	s.ircLock.Lock()
+	transfers := make([]DCCTransfer, 0, len(s.dccTransfers))
+	for _, t := range s.dccTransfers {
+		transfers = append(transfers, t.info())
+	}
+	s.ircLock.Unlock()
+
+	sort.Slice(transfers, func(i, j int) bool {
+		return transfers[i].File < transfers[j].File
+	})
+	return transfers
+}
+
+// cancelDCCTransfer stops the download of file, deletePartial removes
+// what has been downloaded so far once it has stopped
+func (s *State) cancelDCCTransfer(file string, deletePartial bool) bool {
+	s.ircLock.Lock()
+	t, ok := s.dccTransfers[file]
+	if ok {
+		t.deletePartial = deletePartial
+	}
+	s.ircLock.Unlock()
+
+	if ok {
+		t.cancel()
+	}
+	return ok
+}
+
+var errConnectionLimit = errors.New("You have reached the maximum number of connections, disconnect from a server first")
+
+// acquireConnection checks the connection limits of the user before a
+// new server gets connected to and counts it towards the rate limit
+func (s *State) acquireConnection() error {
+	if s.srv == nil {
+		return nil
+	}
+	limits := s.srv.Config().Limits.ForUser(s.user.Username, s.user.IsAdmin())
+
+	s.ircLock.Lock()
+	defer s.ircLock.Unlock()
+
+	if limits.MaxConnections > 0 && len(s.irc) >= limits.MaxConnections {
+		return errConnectionLimit
+	}
+
+	if limits.ConnectRate > 0 && limits.ConnectWindow > 0 {
+		now := time.Now()
+		recent := s.connects[:0]
+		for _, t := range s.connects {
+			if now.Sub(t) < limits.ConnectWindow {
+				recent = append(recent, t)
+			}
+		}
+		s.connects = recent
+
+		if len(s.connects) >= limits.ConnectRate {
+			wait := limits.ConnectWindow - now.Sub(s.connects[0])
+			return fmt.Errorf("You are connecting to servers too quickly, try again in %s",
+				time.Duration(math.Ceil(wait.Seconds()))*time.Second)
+		}
+		s.connects = append(s.connects, now)
+	}
+
+	return nil
+}
+
+// setWS adds a session, it returns false when the user is at the session
+// limit and the policy is to reject new sessions
+func (s *State) setWS(addr string, w *wsConn) bool {
+	limit, policy := s.sessionLimit()
+
 	s.wsLock.Lock()
-	s.ws[addr] = w
+	ok := s.admitWS(addr, limit, policy)
+	if ok {
+		s.addWS(addr, w)
+	}
 	s.wsLock.Unlock()
 
-	s.reset <- 0
+	if ok {
+		s.reset <- 0
+	}
+	return ok
+}
+
+// resumeWS adds a session that already got the events up to seq, the ones
+// it missed get replayed to it first. Nothing happens and false is returned
+// if they are no longer buffered.
+func (s *State) resumeWS(addr string, w *wsConn, seq uint64) bool {
+	limit, policy := s.sessionLimit()
+
+	s.wsLock.Lock()
+	events, ok := s.replay.since(seq)
+	ok = ok && s.admitWS(addr, limit, policy)
+	if ok {
+		w.push(WSResponse{
+			Type: "resume",
+			Data: Resume{Replayed: len(events)},
+		})
+		for _, res := range events {
+			w.push(res)
+		}
+		s.addWS(addr, w)
+	}
+	s.wsLock.Unlock()
+
+	if ok {
+		s.reset <- 0
+	}
+	return ok
+}
+
+// sessionLimit returns the max number of sessions of the user and
+// what to do about new ones over it, admins are not limited
+func (s *State) sessionLimit() (int, string) {
+	if s.srv == nil {
+		return 0, ""
+	}
+
+	limits := s.srv.Config().Limits.ForUser(s.user.Username, s.user.IsAdmin())
+	return limits.MaxSessions, limits.SessionPolicy
+}
+
+// admitWS makes room for a new session at addr when the user is at limit by
+// closing the oldest session, or returns false if policy is "reject". It has
+// to be called with wsLock held.
+func (s *State) admitWS(addr string, limit int, policy string) bool {
+	if limit <= 0 {
+		return true
+	}
+
+	n := len(s.ws)
+	if _, ok := s.ws[addr]; ok {
+		n--
+	}
+	if n < limit {
+		return true
+	}
+	if policy == "reject" {
+		return false
+	}
+
+	for ; n >= limit; n-- {
+		oldestAddr := ""
+		var oldest *wsConn
+		for a, ws := range s.ws {
+			if a != addr && (oldest == nil || ws.connected.Before(oldest.connected)) {
+				oldestAddr, oldest = a, ws
+			}
+		}
+
+		delete(s.ws, oldestAddr)
+		go oldest.closeWithReason(wsCloseSessionLimit, "Closed by a newer session")
+		log.Println(oldestAddr, "[State] User ID:", s.user.ID, "| Closed the oldest session, limit is", limit)
+	}
+	return true
+}
+
+// addWS has to be called with wsLock held
+func (s *State) addWS(addr string, w *wsConn) {
+	s.ws[addr] = w
+	s.lastSeen = time.Time{}
+	if s.idle != nil {
+		s.idle.Stop()
+		s.idle = nil
+	}
 }
 
 func (s *State) deleteWS(addr string) {
 	s.wsLock.Lock()
 	delete(s.ws, addr)
+	if len(s.ws) == 0 {
+		s.lastSeen = time.Now()
+	}
 	s.wsLock.Unlock()
 
+	s.startIdleTimer()
 	s.resetExpirationIfEmpty()
 }
 
-func (s *State) numWS() int {
+// startIdleTimer suspends the user when no session connects within
+// the idle timeout
+func (s *State) startIdleTimer() {
+	timeout := s.idleTimeout()
+
+	s.wsLock.Lock()
+	if timeout > 0 && len(s.ws) == 0 && s.idle == nil {
+		s.idle = time.AfterFunc(timeout, s.suspend)
+	}
+	s.wsLock.Unlock()
+}
+
+// getLastSeen returns when the last session disconnected, it is zero
+// while sessions are connected
+func (s *State) getLastSeen() time.Time {
+	s.wsLock.Lock()
+	lastSeen := s.lastSeen
+	s.wsLock.Unlock()
+	return lastSeen
+}
+
+func (s *State) idleTimeout() time.Duration {
+	if s.srv == nil || s.user.IsAlwaysOn() {
+		return 0
+	}
+	return s.srv.Config().IdleDisconnectFor(s.user.Username)
+}
+
+// suspend closes the IRC connections of an idle user, the servers are kept
+// and get reconnected by resume
+func (s *State) suspend() {
+	if s.numWS() > 0 {
+		return
+	}
+
 	s.ircLock.Lock()
-	n := len(s.ws)
+	clients := make([]*irc.Client, 0, len(s.irc))
+	servers := make([]string, 0, len(s.irc))
+	for server, i := range s.irc {
+		clients = append(clients, i)
+		servers = append(servers, server)
+	}
+	s.suspended = len(clients) > 0
+	for key := range s.pendingDCCSends {
+		delete(s.pendingDCCSends, key)
+	}
+	for key := range s.pendingCTCP {
+		delete(s.pendingCTCP, key)
+	}
+	s.ircLock.Unlock()
+
+	if len(clients) > 0 {
+		log.Println("[State] User ID:", s.user.ID, "| Idle, disconnecting from IRC")
+
+		// Sessions that resume get this from the replay buffer
+		sort.Strings(servers)
+		s.sendJSON("idle_disconnect", IdleDisconnect{
+			Servers: servers,
+		})
+	}
+	quitMessage := s.quitMessage()
+	for _, i := range clients {
+		i.Quit(quitMessage)
+	}
+}
+
+// resume reconnects the servers of a suspended user
+func (s *State) resume() {
+	s.ircLock.Lock()
+	suspended := s.suspended
+	s.suspended = false
 	s.ircLock.Unlock()
 
+	if !suspended {
+		return
+	}
+
+	log.Println("[State] User ID:", s.user.ID, "| Back, reconnecting to IRC")
+	s.connectServers()
+}
+
+func (s *State) isSuspended() bool {
+	s.ircLock.Lock()
+	suspended := s.suspended
+	s.ircLock.Unlock()
+	return suspended
+}
+
+// connectServers connects to all of the users servers and joins
+// their channels
+func (s *State) connectServers() {
+	channels, err := s.user.GetChannels()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	servers, err := s.user.GetServers()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	for _, server := range servers {
+		i := connectIRC(server, s, s.user.GetLastIP())
+
+		var joining []string
+		for _, channel := range channels {
+			if channel.Server == server.Host {
+				joining = append(joining, channel.Name)
+			}
+		}
+		i.Join(joining...)
+	}
+}
+
+func (s *State) getSessions() []string {
+	s.wsLock.Lock()
+	sessions := make([]string, 0, len(s.ws))
+	for addr := range s.ws {
+		sessions = append(sessions, addr)
+	}
+	s.wsLock.Unlock()
+
+	sort.Strings(sessions)
+	return sessions
+}
+
+// closeWS forcibly disconnects a WebSocket session
+func (s *State) closeWS(addr string) bool {
+	s.wsLock.Lock()
+	ws, ok := s.ws[addr]
+	if ok {
+		delete(s.ws, addr)
+		ws.conn.Close()
+	}
+	s.wsLock.Unlock()
+
+	return ok
+}
+
+func (s *State) numWS() int {
+	s.wsLock.Lock()
+	n := len(s.ws)
+	s.wsLock.Unlock()
+
 	return n
 }
 
+// sendJSON sends an event to every WebSocket session of the user, changes
+// made in one session get sent this way to keep the others in sync
 func (s *State) sendJSON(t string, v interface{}) {
-	s.broadcast <- WSResponse{t, v}
+	s.broadcast <- WSResponse{Type: t, Data: v}
+}
+
+func (s *State) userlistLimit() int {
+	if s.srv == nil {
+		return 0
+	}
+	return s.srv.Config().UserlistLimit
 }
 
 func (s *State) sendLastMessages(server, channel string, count int) {
@@ -168,6 +720,40 @@ func (s *State) sendLastMessages(server, channel string, count int) {
 	}
 }
 
+// sendMissedMessages sends the last messages of a channel, and if more
+// than that have been logged since the user was last seen, those too
+func (s *State) sendMissedMessages(server, channel string, count int, since time.Time) {
+	if since.IsZero() {
+		s.sendLastMessages(server, channel, count)
+		return
+	}
+
+	messages, hasMore, err := s.user.GetLastMessages(server, channel, replayLimit)
+	if err != nil || len(messages) == 0 {
+		return
+	}
+
+	start := len(messages) - count
+	if start < 0 {
+		start = 0
+	}
+	for start > 0 && messages[start-1].Time >= since.Unix() {
+		start--
+	}
+
+	res := Messages{
+		Server:   server,
+		To:       channel,
+		Messages: messages[start:],
+	}
+
+	if start > 0 || hasMore {
+		res.Next = messages[start].ID
+	}
+
+	s.sendJSON("messages", res)
+}
+
 func (s *State) sendMessages(server, channel string, count int, fromID string) {
 	messages, hasMore, err := s.user.GetMessages(server, channel, count, fromID)
 	if err == nil && len(messages) > 0 {
@@ -184,23 +770,58 @@ func (s *State) sendMessages(server, channel string, count int, fromID string) {
 
 		s.sendJSON("messages", res)
 	}
+
+	if err == nil && !hasMore {
+		s.requestOlderChatHistory(server, channel, messages, fromID)
+	}
+}
+
+// requestOlderChatHistory continues scrollback from the IRC server when
+// the local log runs out, the result arrives as a chathistory batch
+func (s *State) requestOlderChatHistory(server, channel string, messages []storage.Message, fromID string) {
+	i, ok := s.getIRC(server)
+	if !ok || !i.SupportsChatHistory() {
+		return
+	}
+
+	if len(messages) == 0 && fromID != "" {
+		messages, _ = s.user.GetMessageContext(server, channel, fromID, 0)
+	}
+
+	before := time.Now()
+	if len(messages) > 0 {
+		before = time.Unix(messages[0].Time, 0)
+	}
+
+	i.ChatHistoryBefore(channel, before, chatHistoryLimit)
 }
 
 func (s *State) resetExpirationIfEmpty() {
-	if s.numIRC() == 0 && s.numWS() == 0 {
+	if s.numIRC() == 0 && s.numWS() == 0 && !s.isSuspended() {
 		s.reset <- AnonymousUserExpiration
 	}
 }
 
+// kill closes all WebSocket and IRC connections, calling it again
+// does nothing
 func (s *State) kill() {
+	quitMessage := s.quitMessage()
+
 	s.wsLock.Lock()
 	for _, ws := range s.ws {
 		ws.conn.Close()
 	}
 	s.wsLock.Unlock()
 	s.ircLock.Lock()
-	for _, i := range s.irc {
-		i.Quit()
+	for server, i := range s.irc {
+		i.Quit(quitMessage)
+		delete(s.irc, server)
+
+		if f, ok := s.rawLogs[server]; ok {
+			i.SetRawLog(nil)
+			f.Close()
+			delete(s.rawLogs, server)
+		}
 	}
 	s.ircLock.Unlock()
 }
@@ -210,8 +831,13 @@ func (s *State) run() {
 		select {
 		case res := <-s.broadcast:
 			s.wsLock.Lock()
-			for _, ws := range s.ws {
-				ws.out <- res
+			res = s.replay.add(res)
+			for addr, ws := range s.ws {
+				if !ws.push(res) {
+					log.Println(addr, "[WebSocket] Session is not keeping up, disconnecting")
+					delete(s.ws, addr)
+					ws.conn.Close()
+				}
 			}
 			s.wsLock.Unlock()
 
@@ -324,22 +950,47 @@ func (s *stateStore) get(id uint64) *State {
 	return state
 }
 
+func (s *stateStore) list() []*State {
+	s.lock.Lock()
+	states := make([]*State, 0, len(s.states))
+	for _, state := range s.states {
+		states = append(states, state)
+	}
+	s.lock.Unlock()
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].user.ID < states[j].user.ID
+	})
+	return states
+}
+
 func (s *stateStore) set(state *State) {
 	s.lock.Lock()
 	s.states[state.user.ID] = state
 	s.lock.Unlock()
 }
 
-func (s *stateStore) delete(id uint64) {
+// delete removes the state of a user and all of its sessions, the
+// sessions are gone from the session store when it returns
+func (s *stateStore) delete(id uint64) error {
+	var keys []string
+
 	s.lock.Lock()
 	delete(s.states, id)
 	for key, session := range s.sessions {
 		if session.UserID == id {
 			delete(s.sessions, key)
-			go s.sessionStore.DeleteSession(key)
+			keys = append(keys, key)
 		}
 	}
 	s.lock.Unlock()
+
+	for _, key := range keys {
+		if err := s.sessionStore.DeleteSession(key); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *stateStore) getSession(key string) *session.Session {
@@ -0,0 +1,292 @@
+package server
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateBroadcastsToAllSessions(t *testing.T) {
+	s := NewState(user, nil)
+	go s.run()
+
+	phone := newWSConn(nil)
+	desktop := newWSConn(nil)
+	s.setWS("10.0.0.1:1234", phone)
+	s.setWS("10.0.0.2:1234", desktop)
+
+	h := &wsHandler{state: s}
+	h.initHandlers()
+	h.dispatchRequest(WSRequest{
+		Type: "open_dm",
+		Data: []byte(`{"server":"srv","name":"bob"}`),
+	})
+
+	for _, ws := range []*wsConn{phone, desktop} {
+		select {
+		case <-ws.queue.ready:
+			events := ws.queue.drain()
+			assert.Len(t, events, 1)
+			checkResponse(t, "open_dm", Tab{storage.Tab{Server: "srv", Name: "bob"}}, events[0])
+
+		case <-time.After(time.Second):
+			t.Fatal("Session did not receive event")
+		}
+	}
+
+	openDMs, err := user.GetOpenDMs()
+	assert.Nil(t, err)
+	assert.Contains(t, openDMs, storage.Tab{Server: "srv", Name: "bob"})
+}
+
+func TestStateIdleDisconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	conns := make(chan net.Conn, 4)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte(":srv 001 nick :Welcome\r\n"))
+			conns <- conn
+		}
+	}()
+
+	u, err := storage.NewUser(store)
+	assert.Nil(t, err)
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	assert.Nil(t, u.AddServer(&storage.Server{Host: "127.0.0.1", Port: port, Nick: "nick"}))
+
+	s := NewState(u, New(&config.Config{IdleDisconnect: 50 * time.Millisecond}))
+	go s.run()
+
+	s.connectServers()
+	conn := acceptIRC(t, conns)
+
+	s.setWS("10.0.0.1:1234", newWSConn(nil))
+	s.deleteWS("10.0.0.1:1234")
+	assert.False(t, s.getLastSeen().IsZero())
+
+	// The server sees QUIT once the user has been idle long enough
+	scan := bufio.NewScanner(conn)
+	for scan.Scan() && !strings.HasPrefix(scan.Text(), "QUIT") {
+	}
+	conn.Close()
+
+	assert.True(t, s.isSuspended())
+	waitFor(t, func() bool { return s.numIRC() == 0 })
+
+	s.setWS("10.0.0.1:1234", newWSConn(nil))
+	assert.True(t, s.getLastSeen().IsZero())
+	s.resume()
+	assert.False(t, s.isSuspended())
+	acceptIRC(t, conns).Close()
+	_, ok := s.getIRC("127.0.0.1")
+	assert.True(t, ok)
+
+	// Always-on users stay connected
+	assert.Nil(t, u.SetAlwaysOn(true))
+	s.deleteWS("10.0.0.1:1234")
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, s.isSuspended())
+
+	s.kill()
+}
+
+func TestStateIdleTimerReset(t *testing.T) {
+	u, err := storage.NewUser(store)
+	assert.Nil(t, err)
+
+	s := NewState(u, New(&config.Config{
+		IdleDisconnect: time.Hour,
+		IdleDisconnectUsers: map[string]time.Duration{
+			u.Username: 50 * time.Millisecond,
+		},
+	}))
+	go s.run()
+	s.setIRC("host.com", irc.NewClient(&irc.Config{Nick: "nick", Host: "host.com"}))
+
+	s.wsLock.Lock()
+	seq := s.replay.seq
+	s.wsLock.Unlock()
+
+	// Coming back before the timeout keeps the connection
+	s.setWS("10.0.0.1:1234", newWSConn(nil))
+	s.deleteWS("10.0.0.1:1234")
+	time.Sleep(20 * time.Millisecond)
+	s.setWS("10.0.0.1:1234", newWSConn(nil))
+	time.Sleep(60 * time.Millisecond)
+	assert.False(t, s.isSuspended())
+
+	s.deleteWS("10.0.0.1:1234")
+	waitFor(t, s.isSuspended)
+
+	var events []WSResponse
+	waitFor(t, func() bool {
+		s.wsLock.Lock()
+		events, _ = s.replay.since(seq)
+		s.wsLock.Unlock()
+		return len(events) > 0
+	})
+	checkResponse(t, "idle_disconnect", IdleDisconnect{
+		Servers: []string{"host.com"},
+	}, events[len(events)-1])
+	s.deleteIRC("host.com")
+
+	// 0 keeps the user connected
+	s.srv.SetConfig(&config.Config{
+		IdleDisconnect: 50 * time.Millisecond,
+		IdleDisconnectUsers: map[string]time.Duration{
+			u.Username: 0,
+		},
+	})
+	assert.Equal(t, time.Duration(0), s.idleTimeout())
+
+	s.kill()
+}
+
+func TestStateSendMissedMessages(t *testing.T) {
+	u, err := storage.NewUser(store)
+	assert.Nil(t, err)
+
+	channel := "#missed" + u.Username
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		u.LogMessage(&storage.Message{
+			ID:      storage.MessageIDAt(now.Add(time.Duration(i-10) * time.Hour)),
+			Server:  "srv",
+			From:    "nick",
+			To:      channel,
+			Content: strconv.Itoa(i),
+			Time:    now.Add(time.Duration(i-10) * time.Hour).Unix(),
+		})
+	}
+
+	s := NewState(u, nil)
+
+	s.sendMissedMessages("srv", channel, 3, time.Time{})
+	res := <-s.broadcast
+	assert.Len(t, res.Data.(Messages).Messages, 3)
+
+	// Missed more than count
+	s.sendMissedMessages("srv", channel, 3, now.Add(-5*time.Hour-time.Minute))
+	res = <-s.broadcast
+	messages := res.Data.(Messages)
+	assert.Len(t, messages.Messages, 5)
+	assert.Equal(t, "5", messages.Messages[0].Content)
+	assert.Equal(t, messages.Messages[0].ID, messages.Next)
+
+	// Missed less than count
+	s.sendMissedMessages("srv", channel, 3, now.Add(-time.Hour-time.Minute))
+	res = <-s.broadcast
+	assert.Len(t, res.Data.(Messages).Messages, 3)
+}
+
+func acceptIRC(t *testing.T, conns chan net.Conn) net.Conn {
+	select {
+	case conn := <-conns:
+		return conn
+	case <-time.After(2 * time.Second):
+		t.Fatal("IRC connection not made")
+	}
+	return nil
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	timeout := time.After(2 * time.Second)
+	for !cond() {
+		select {
+		case <-timeout:
+			t.Fatal("Timed out")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestStateAcquireConnection(t *testing.T) {
+	d := New(&config.Config{
+		Limits: config.Limits{MaxConnections: 2},
+	})
+	s := NewState(user, d)
+	go s.run()
+
+	assert.Nil(t, s.acquireConnection())
+	s.setIRC("a.example.com", irc.NewClient(&irc.Config{}))
+	assert.Nil(t, s.acquireConnection())
+	s.setIRC("b.example.com", irc.NewClient(&irc.Config{}))
+	assert.Equal(t, errConnectionLimit, s.acquireConnection())
+
+	s.deleteIRC("a.example.com")
+	assert.Nil(t, s.acquireConnection())
+
+	d.SetConfig(&config.Config{
+		Limits: config.Limits{
+			MaxConnections: 2,
+			Users: map[string]config.Limits{
+				user.Username: {},
+			},
+		},
+	})
+	assert.Nil(t, s.acquireConnection())
+}
+
+func TestStateConnectRate(t *testing.T) {
+	s := NewState(user, New(&config.Config{
+		Limits: config.Limits{
+			ConnectRate:   2,
+			ConnectWindow: 50 * time.Millisecond,
+		},
+	}))
+
+	assert.Nil(t, s.acquireConnection())
+	assert.Nil(t, s.acquireConnection())
+	err := s.acquireConnection()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "too quickly")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	assert.Nil(t, s.acquireConnection())
+}
+
+func TestShutdown(t *testing.T) {
+	u, err := storage.NewUser(store)
+	assert.Nil(t, err)
+	settings := u.GetClientSettings()
+	settings.QuitMessage = "Restarting"
+	assert.Nil(t, u.SetClientSettings(settings))
+
+	d := New(&config.Config{})
+	d.states = &stateStore{states: map[uint64]*State{}}
+	s := NewState(u, d)
+	d.states.set(s)
+	go s.run()
+
+	port, lines := stubIRCServer(t, ":srv 001 nick :Welcome\r\n")
+	i := irc.NewClient(&irc.Config{Host: "127.0.0.1", Port: port, Nick: "nick"})
+	s.setIRC("127.0.0.1", i)
+	i.Connect()
+	<-i.Messages
+
+	d.Shutdown()
+	assert.Equal(t, "QUIT :Restarting", nextLine(t, lines, "QUIT"))
+
+	select {
+	case <-i.Done():
+	default:
+		t.Fatal("Shutdown returned before the connection closed")
+	}
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/khlieng/dispatch/pkg/irc"
+)
+
+const userModePrefixes = "~&@%+"
+
+func userPrefix(user string) string {
+	if len(user) > 0 && strings.IndexByte(userModePrefixes, user[0]) >= 0 {
+		return user[:1]
+	}
+	return ""
+}
+
+func userRank(user string) int {
+	if prefix := userPrefix(user); prefix != "" {
+		return strings.Index(userModePrefixes, prefix)
+	}
+	return len(userModePrefixes)
+}
+
+func sortUsers(users []string) {
+	sort.SliceStable(users, func(i, j int) bool {
+		ri, rj := userRank(users[i]), userRank(users[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return strings.ToLower(users[i][len(userPrefix(users[i])):]) <
+			strings.ToLower(users[j][len(userPrefix(users[j])):])
+	})
+}
+
+// newUserlist sorts the users, if there are more than limit users only the
+// first limit of them are included along with the number of users per prefix,
+// the rest can be fetched using pageUsers
+func newUserlist(server, channel string, users []string, limit int) Userlist {
+	sortUsers(users)
+
+	list := Userlist{
+		Server:  server,
+		Channel: channel,
+		Users:   users,
+	}
+
+	if limit > 0 && len(users) > limit {
+		list.Users = users[:limit]
+		list.Total = len(users)
+		list.Prefixes = map[string]int{}
+
+		for _, user := range users {
+			list.Prefixes[userPrefix(user)]++
+		}
+	}
+
+	return list
+}
+
+func pageUsers(users []string, offset, limit int) []string {
+	sortUsers(users)
+
+	if offset < 0 || offset >= len(users) {
+		return []string{}
+	}
+
+	users = users[offset:]
+	if limit > 0 && len(users) > limit {
+		users = users[:limit]
+	}
+	return users
+}
+
+// awayUsers returns the nicks in users that are away in channel
+func awayUsers(i *irc.Client, channel string, users []string) []string {
+	away := i.AwayUsers(channel)
+	if len(away) == 0 {
+		return nil
+	}
+
+	isAway := make(map[string]bool, len(away))
+	for _, nick := range away {
+		isAway[nick] = true
+	}
+
+	var result []string
+	for _, user := range users {
+		if nick := user[len(userPrefix(user)):]; isAway[nick] {
+			result = append(result, nick)
+		}
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUserlist(t *testing.T) {
+	users := []string{"bob", "@Carl", "+dave", "alice", "@ann", "~root"}
+
+	list := newUserlist("srv", "#chan", users, 0)
+	assert.Equal(t, []string{"~root", "@ann", "@Carl", "+dave", "alice", "bob"}, list.Users)
+	assert.Zero(t, list.Total)
+	assert.Nil(t, list.Prefixes)
+
+	list = newUserlist("srv", "#chan", users, 6)
+	assert.Len(t, list.Users, 6)
+	assert.Zero(t, list.Total)
+
+	list = newUserlist("srv", "#chan", users, 3)
+	assert.Equal(t, Userlist{
+		Server:  "srv",
+		Channel: "#chan",
+		Users:   []string{"~root", "@ann", "@Carl"},
+		Total:   6,
+		Prefixes: map[string]int{
+			"~": 1,
+			"@": 2,
+			"+": 1,
+			"":  2,
+		},
+	}, list)
+}
+
+func TestPageUsers(t *testing.T) {
+	users := []string{"bob", "@Carl", "+dave", "alice", "@ann", "~root"}
+
+	assert.Equal(t, []string{"~root", "@ann"}, pageUsers(users, 0, 2))
+	assert.Equal(t, []string{"@Carl", "+dave"}, pageUsers(users, 2, 2))
+	assert.Equal(t, []string{"bob"}, pageUsers(users, 5, 2))
+	assert.Equal(t, []string{}, pageUsers(users, 6, 2))
+	assert.Equal(t, []string{}, pageUsers(users, -1, 2))
+	assert.Len(t, pageUsers(users, 1, 0), 5)
+}
+
+func TestUserlistAway(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	ircd := make(chan net.Conn, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			ircd <- conn
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	c := irc.NewClient(&irc.Config{
+		Nick: "nick",
+		Host: "127.0.0.1",
+		Port: port,
+	})
+	s := NewState(user, nil)
+	c.Connect()
+	go newIRCHandler(c, s).run()
+	defer c.Quit()
+
+	conn := <-ircd
+	defer conn.Close()
+
+	next := func(eventType string) WSResponse {
+		timeout := time.After(time.Second)
+		for {
+			select {
+			case res := <-s.broadcast:
+				if res.Type == eventType {
+					return res
+				}
+			case <-timeout:
+				t.Fatal("Timed out waiting for", eventType)
+			}
+		}
+	}
+
+	names := ":srv 353 nick = #chan :@op bob alice\r\n:srv 366 nick #chan :End of /NAMES list.\r\n"
+
+	conn.Write([]byte(":srv 001 nick :Welcome\r\n" + names))
+	assert.Empty(t, next("users").Data.(Userlist).Away)
+
+	conn.Write([]byte(":bob!b@host AWAY :brb\r\n"))
+	assert.Equal(t, UserAway{
+		Server:  "127.0.0.1",
+		User:    "bob",
+		Away:    true,
+		Message: "brb",
+	}, next("user_away").Data)
+
+	conn.Write([]byte(names))
+	assert.Equal(t, []string{"bob"}, next("users").Data.(Userlist).Away)
+
+	conn.Write([]byte(":bob!b@host PRIVMSG #chan :hi\r\n"))
+	assert.True(t, next("message").Data.(Message).Away)
+
+	conn.Write([]byte(":bob!b@host AWAY\r\n"))
+	assert.False(t, next("user_away").Data.(UserAway).Away)
+
+	conn.Write([]byte(names))
+	assert.Empty(t, next("users").Data.(Userlist).Away)
+}
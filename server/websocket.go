@@ -1,41 +1,104 @@
 package server
 
 import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/mailru/easyjson"
 )
 
+const (
+	// wsQueueSize is the max number of events queued for a session
+	wsQueueSize = 1024
+	// wsHighWater is the number of queued events after which a session
+	// gets disconnected if it does not catch up within wsHighWaterTimeout
+	wsHighWater = 256
+
+	defaultWSPingInterval = 20 * time.Second
+	defaultWSPingTimeout  = 10 * time.Second
+
+	// defaultWSMaxMessageSize is the max size in bytes of messages from
+	// the client, sessions sending anything bigger get closed
+	defaultWSMaxMessageSize = 64 * 1024
+
+	// wsCloseSessionLimit is the close code of sessions closed because
+	// the user has reached the max number of sessions
+	wsCloseSessionLimit = 4001
+)
+
+var errWSMessageTooBig = errors.New("message too big")
+
+var wsHighWaterTimeout = 30 * time.Second
+
+// droppableEvents can be dropped, oldest first, when a session falls behind,
+// all other events are either delivered or the session gets disconnected.
+// A userlist is only dropped when a newer one for the same channel is queued
+var droppableEvents = map[string]bool{
+	"channel_list_progress": true,
+	"channel_search":        true,
+}
+
 type wsConn struct {
-	conn *websocket.Conn
-	in   chan WSRequest
-	out  chan WSResponse
+	conn  *websocket.Conn
+	in    chan WSRequest
+	queue *sendQueue
+	// pingInterval is how often ping frames get sent, the session is
+	// closed when nothing has been received for pingInterval+pingTimeout
+	pingInterval time.Duration
+	pingTimeout  time.Duration
+	// maxMessageSize is the max size in bytes of messages from the client
+	maxMessageSize int64
+	// connected is when the session was opened
+	connected time.Time
 }
 
 func newWSConn(conn *websocket.Conn) *wsConn {
 	return &wsConn{
-		conn: conn,
-		in:   make(chan WSRequest, 32),
-		out:  make(chan WSResponse, 32),
+		conn:           conn,
+		in:             make(chan WSRequest, 32),
+		queue:          newSendQueue(),
+		pingInterval:   defaultWSPingInterval,
+		pingTimeout:    defaultWSPingTimeout,
+		maxMessageSize: defaultWSMaxMessageSize,
+		connected:      time.Now(),
 	}
 }
 
+// push queues an event for the session without blocking, it returns false
+// if the session is too far behind and should be disconnected
+func (c *wsConn) push(res WSResponse) bool {
+	return c.queue.push(res)
+}
+
 func (c *wsConn) send() {
 	var err error
-	ping := time.Tick(20 * time.Second)
+	ping := time.NewTicker(c.pingInterval)
+	defer ping.Stop()
 
 	for {
 		select {
-		case res, ok := <-c.out:
+		case _, ok := <-c.queue.ready:
 			if !ok {
 				return
 			}
 
-			err = c.writeJSON(res)
+			for _, res := range c.queue.drain() {
+				err = c.writeJSON(res)
+				if err != nil {
+					break
+				}
+			}
 
-		case <-ping:
-			err = c.writeJSON(WSResponse{Type: "ping"})
+		case <-ping.C:
+			err = c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.pingTimeout))
+			if err == nil {
+				err = c.writeJSON(WSResponse{Type: "ping"})
+			}
 		}
 
 		if err != nil {
@@ -45,31 +108,94 @@ func (c *wsConn) send() {
 }
 
 func (c *wsConn) recv() {
-	var req WSRequest
+	c.extendDeadline()
+	c.conn.SetPongHandler(func(string) error {
+		return c.extendDeadline()
+	})
 
 	for {
-		err := c.readJSON(&req)
+		b, err := c.readMessage()
 		if err != nil {
+			if err == errWSMessageTooBig {
+				c.conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.ClosePolicyViolation, err.Error()),
+					time.Now().Add(c.pingTimeout))
+			}
 			close(c.in)
 			return
 		}
+		c.extendDeadline()
+
+		req, err := parseWSRequest(b)
+		if err != nil {
+			c.push(WSResponse{
+				Type: "request_error",
+				Data: RequestError{
+					Type:    req.Type,
+					Message: err.Error(),
+				},
+			})
+			continue
+		}
 
 		c.in <- req
 	}
 }
 
+// extendDeadline gives the session until the next ping has had time to
+// be answered, reads fail once it passes which ends the session
+func (c *wsConn) extendDeadline() error {
+	return c.conn.SetReadDeadline(time.Now().Add(c.pingInterval + c.pingTimeout))
+}
+
 func (c *wsConn) close() {
-	close(c.out)
+	c.queue.close()
 	c.conn.Close()
 }
 
-func (c *wsConn) readJSON(v easyjson.Unmarshaler) error {
+// closeWithReason tells the client why the session gets closed before
+// closing it
+func (c *wsConn) closeWithReason(code int, reason string) {
+	c.conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(c.pingTimeout))
+	c.conn.Close()
+}
+
+// readMessage returns the next message from the client, it fails with
+// errWSMessageTooBig when the message is bigger than maxMessageSize
+func (c *wsConn) readMessage() ([]byte, error) {
 	_, r, err := c.conn.NextReader()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return easyjson.UnmarshalFromReader(r, v)
+	b, err := ioutil.ReadAll(io.LimitReader(r, c.maxMessageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > c.maxMessageSize {
+		return nil, errWSMessageTooBig
+	}
+	return b, nil
+}
+
+// parseWSRequest decodes a request, its data has to be an object when set
+func parseWSRequest(b []byte) (WSRequest, error) {
+	var req WSRequest
+
+	if err := req.UnmarshalJSON(b); err != nil {
+		return req, errors.New("Malformed request")
+	}
+	if req.Type == "" {
+		return req, errors.New("Missing request type")
+	}
+
+	data := bytes.TrimSpace(req.Data)
+	if len(data) > 0 && data[0] != '{' && !bytes.Equal(data, []byte("null")) {
+		return req, errors.New("Request data has to be an object")
+	}
+	return req, nil
 }
 
 func (c *wsConn) writeJSON(v easyjson.Marshaler) error {
@@ -84,3 +210,123 @@ func (c *wsConn) writeJSON(v easyjson.Marshaler) error {
 	}
 	return err2
 }
+
+type sendQueue struct {
+	events    []WSResponse
+	ready     chan struct{}
+	closed    bool
+	overSince time.Time
+	lock      sync.Mutex
+}
+
+func newSendQueue() *sendQueue {
+	return &sendQueue{
+		ready: make(chan struct{}, 1),
+	}
+}
+
+func (q *sendQueue) push(res WSResponse) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.closed {
+		return true
+	}
+
+	if len(q.events) >= wsQueueSize {
+		if !q.dropOldest(res) {
+			if droppableEvents[res.Type] {
+				return true
+			}
+			return false
+		}
+	}
+
+	q.events = append(q.events, res)
+
+	if len(q.events) > wsHighWater {
+		if q.overSince.IsZero() {
+			q.overSince = time.Now()
+		} else if time.Since(q.overSince) > wsHighWaterTimeout {
+			return false
+		}
+	} else {
+		q.overSince = time.Time{}
+	}
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// dropOldest drops the oldest event that can be dropped, next is the event
+// that is about to be queued and can make a queued userlist outdated
+func (q *sendQueue) dropOldest(next WSResponse) bool {
+	drop := -1
+	newer := map[userlistKey]bool{}
+	if key, ok := queuedUserlist(next); ok {
+		newer[key] = true
+	}
+
+	for i := len(q.events) - 1; i >= 0; i-- {
+		res := q.events[i]
+		if key, ok := queuedUserlist(res); ok {
+			if newer[key] {
+				drop = i
+			}
+			newer[key] = true
+		} else if droppableEvents[res.Type] {
+			drop = i
+		}
+	}
+
+	if drop < 0 {
+		return false
+	}
+	q.events = append(q.events[:drop], q.events[drop+1:]...)
+	return true
+}
+
+type userlistKey struct {
+	server  string
+	channel string
+}
+
+func queuedUserlist(res WSResponse) (userlistKey, bool) {
+	if res.Type != "users" {
+		return userlistKey{}, false
+	}
+	if userlist, ok := res.Data.(Userlist); ok {
+		return userlistKey{userlist.Server, userlist.Channel}, true
+	}
+	return userlistKey{}, false
+}
+
+func (q *sendQueue) drain() []WSResponse {
+	q.lock.Lock()
+	events := q.events
+	q.events = nil
+	q.overSince = time.Time{}
+	q.lock.Unlock()
+
+	return events
+}
+
+func (q *sendQueue) len() int {
+	q.lock.Lock()
+	n := len(q.events)
+	q.lock.Unlock()
+
+	return n
+}
+
+func (q *sendQueue) close() {
+	q.lock.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.ready)
+	}
+	q.lock.Unlock()
+}
@@ -4,9 +4,14 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/kjk/betterguid"
+
+	"github.com/khlieng/dispatch/pkg/irc"
 	"github.com/khlieng/dispatch/storage"
 )
 
@@ -24,10 +29,21 @@ func newWSHandler(conn *websocket.Conn, state *State, r *http.Request) *wsHandle
 		addr:  conn.RemoteAddr(),
 	}
 
-	if r.Header.Get("X-Forwarded-For") != "" {
-		ip := net.ParseIP(r.Header.Get("X-Forwarded-For"))
-		if ip != nil {
-			h.addr.(*net.TCPAddr).IP = ip
+	cfg := state.srv.Config()
+	if cfg.WebSocket.PingInterval > 0 {
+		h.ws.pingInterval = cfg.WebSocket.PingInterval
+	}
+	if cfg.WebSocket.PingTimeout > 0 {
+		h.ws.pingTimeout = cfg.WebSocket.PingTimeout
+	}
+	if cfg.WebSocket.MaxMessageSize > 0 {
+		h.ws.maxMessageSize = int64(cfg.WebSocket.MaxMessageSize) * 1024
+	}
+
+	if ip := realIP(r, cfg.Proxy); ip != nil {
+		h.addr = &net.TCPAddr{
+			IP:   ip,
+			Port: h.addr.(*net.TCPAddr).Port,
 		}
 	}
 
@@ -57,11 +73,32 @@ func (h *wsHandler) run() {
 func (h *wsHandler) dispatchRequest(req WSRequest) {
 	if handler, ok := h.handlers[req.Type]; ok {
 		handler(req.Data)
+	} else {
+		h.ws.push(WSResponse{
+			Type: "request_error",
+			Data: RequestError{
+				Type:    req.Type,
+				Message: "Unknown request type",
+			},
+		})
 	}
 }
 
 func (h *wsHandler) init(r *http.Request) {
-	h.state.setWS(h.addr.String(), h.ws)
+	lastSeen := h.state.getLastSeen()
+
+	// A session that lost its connection briefly only needs the events
+	// it missed, if they are still around
+	resumed := false
+	if seq, err := strconv.ParseUint(r.URL.Query().Get("seq"), 10, 64); err == nil {
+		resumed = h.state.resumeWS(h.addr.String(), h.ws, seq)
+	}
+	if !resumed && !h.state.setWS(h.addr.String(), h.ws) {
+		log.Println(h.addr, "[State] User ID:", h.state.user.ID, "| Rejected session, the session limit is reached")
+		h.ws.closeWithReason(wsCloseSessionLimit, "Too many sessions")
+		return
+	}
+	h.state.resume()
 	h.state.user.SetLastIP(addrToIPBytes(h.addr))
 	if r.TLS != nil {
 		h.state.Set("scheme", "https")
@@ -74,6 +111,10 @@ func (h *wsHandler) init(r *http.Request) {
 		h.state.numIRC(), "IRC connections |",
 		h.state.numWS(), "WebSocket connections")
 
+	if resumed {
+		return
+	}
+
 	tab, err := tabFromRequest(r)
 
 	channels, err := h.state.user.GetChannels()
@@ -88,14 +129,14 @@ func (h *wsHandler) init(r *http.Request) {
 		}
 
 		if i, ok := h.state.getIRC(channel.Server); ok {
-			h.state.sendJSON("users", Userlist{
-				Server:  channel.Server,
-				Channel: channel.Name,
-				Users:   i.ChannelUsers(channel.Name),
-			})
+			userlist := newUserlist(channel.Server, channel.Name,
+				i.ChannelUsers(channel.Name), h.state.userlistLimit())
+			h.state.fillUserlist(i, &userlist)
+
+			h.state.sendJSON("users", userlist)
 		}
 
-		h.state.sendLastMessages(channel.Server, channel.Name, 50)
+		h.state.sendMissedMessages(channel.Server, channel.Name, 50, lastSeen)
 	}
 
 	openDMs, err := h.state.user.GetOpenDMs()
@@ -108,7 +149,7 @@ func (h *wsHandler) init(r *http.Request) {
 			continue
 		}
 
-		h.state.sendLastMessages(openDM.Server, openDM.Name, 50)
+		h.state.sendMissedMessages(openDM.Server, openDM.Name, 50, lastSeen)
 	}
 }
 
@@ -117,16 +158,205 @@ func (h *wsHandler) connect(b []byte) {
 	data.UnmarshalJSON(b)
 
 	data.Host = strings.ToLower(data.Host)
+	splitServerHost(data.Server)
+
+	// Probing for TLS can take as long as the connect timeouts, so it
+	// happens outside the request loop. Connects of the same user are
+	// serialized to keep the limit and duplicate checks accurate
+	go func() {
+		h.state.connectLock.Lock()
+		defer h.state.connectLock.Unlock()
+
+		if _, ok := h.state.getIRC(data.Host); ok {
+			log.Println(h.addr, "[IRC]", data.Host, "already added")
+			return
+		}
+
+		if err := h.state.acquireConnection(); err != nil {
+			h.state.sendJSON("error", Error{
+				Server:  data.Host,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		inferServerPort(h.state.srv.Config(), data.Server)
+
+		if err := h.state.user.AddServer(data.Server); err != nil {
+			h.state.sendJSON("error", Error{
+				Server:  data.Host,
+				Message: err.Error(),
+			})
+			return
+		}
 
-	if _, ok := h.state.getIRC(data.Host); !ok {
 		log.Println(h.addr, "[IRC] Add server", data.Host)
 
 		connectIRC(data.Server, h.state, addrToIPBytes(h.addr))
+	}()
+}
 
-		go h.state.user.AddServer(data.Server)
-	} else {
-		log.Println(h.addr, "[IRC]", data.Host, "already added")
+// checkServer tests a connection to a server before it gets added,
+// it counts towards the connection rate limit
+func (h *wsHandler) checkServer(b []byte) {
+	var data ServerCheck
+	data.UnmarshalJSON(b)
+
+	if err := h.state.acquireConnection(); err != nil {
+		h.state.sendJSON("server_check", ServerCheckResult{
+			Host:  data.Host,
+			Port:  data.Port,
+			TLS:   data.TLS,
+			Error: err.Error(),
+		})
+		return
 	}
+
+	go func() {
+		h.state.sendJSON("server_check", checkServer(h.state.srv.Config(), data))
+	}()
+}
+
+func (h *wsHandler) setCommands(b []byte) {
+	var data ServerCommands
+	data.UnmarshalJSON(b)
+
+	err := h.state.user.SetServerCommands(data.Server, data.Commands)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	h.state.sendJSON("server_commands", data)
+}
+
+func (h *wsHandler) setClientTags(b []byte) {
+	var data ClientTags
+	data.UnmarshalJSON(b)
+
+	err := h.state.user.SetServerClientTags(data.Server, data.Forward, data.Send)
+	if err != nil {
+		h.state.sendJSON("error", Error{
+			Server:  data.Server,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if i, ok := h.state.getIRC(data.Server); ok {
+		i.SetClientTags(parseClientTags(data.Send))
+		h.state.setForwardTags(data.Server, data.Forward)
+	}
+
+	h.state.sendJSON("client_tags", data)
+}
+
+// setServerTLS stores the SNI and ALPN settings of a server, they are
+// used from the next time it reconnects
+func (h *wsHandler) setServerTLS(b []byte) {
+	var data ServerTLS
+	data.UnmarshalJSON(b)
+
+	err := h.state.user.SetServerTLS(data.Server, data.ServerName, data.ALPN)
+	if err != nil {
+		h.state.sendJSON("error", Error{
+			Server:  data.Server,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if i, ok := h.state.getIRC(data.Server); ok && i.TLSConfig() != nil {
+		tlsConfig := i.TLSConfig().Clone()
+		tlsConfig.ServerName = data.ServerName
+		tlsConfig.NextProtos = data.ALPN
+		i.SetTLSConfig(tlsConfig)
+	}
+
+	h.state.sendJSON("server_tls", data)
+}
+
+// setServerPins stores the certificate pins of a server, they are used
+// from the next time it reconnects
+func (h *wsHandler) setServerPins(b []byte) {
+	var data ServerPins
+	data.UnmarshalJSON(b)
+
+	err := h.state.user.SetServerPins(data.Server, data.Pins)
+	if err != nil {
+		h.state.sendJSON("error", Error{
+			Server:  data.Server,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	server, err := h.state.user.GetServer(data.Server)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	data.Pins = server.PinnedCerts
+
+	if i, ok := h.state.getIRC(data.Server); ok && i.TLSConfig() != nil {
+		tlsConfig := i.TLSConfig().Clone()
+		setPinnedCerts(tlsConfig, data.Pins, !h.state.srv.Config().VerifyCertificates)
+		i.SetTLSConfig(tlsConfig)
+	}
+
+	h.state.sendJSON("server_pins", data)
+}
+
+// createPaste stores a paste and sends back the link to it
+func (h *wsHandler) createPaste(b []byte) {
+	var data CreatePaste
+	data.UnmarshalJSON(b)
+
+	paste, err := h.state.createPaste(data.Name, []byte(data.Content))
+	if err != nil {
+		h.state.sendJSON("error", Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	h.state.sendJSON("paste", PasteCreated{
+		ID:      paste.ID,
+		Name:    paste.Name,
+		URL:     h.state.pasteURL(paste.ID),
+		Size:    paste.Size,
+		Expires: paste.Expires,
+	})
+}
+
+func (h *wsHandler) dccTransfers(b []byte) {
+	h.state.sendJSON("dcc_transfers", DCCTransfers{
+		Transfers: h.state.getDCCTransfers(),
+	})
+}
+
+func (h *wsHandler) cancelDCC(b []byte) {
+	var data CancelDCC
+	data.UnmarshalJSON(b)
+
+	if !h.state.cancelDCCTransfer(data.File, data.Delete) {
+		h.state.sendJSON("error", Error{
+			Message: "No DCC transfer of " + data.File + " is running",
+		})
+	}
+}
+
+func (h *wsHandler) setAlwaysOn(b []byte) {
+	var data AlwaysOn
+	data.UnmarshalJSON(b)
+
+	err := h.state.user.SetAlwaysOn(data.Enabled)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	h.state.sendJSON("always_on", data)
 }
 
 func (h *wsHandler) reconnect(b []byte) {
@@ -134,8 +364,11 @@ func (h *wsHandler) reconnect(b []byte) {
 	data.UnmarshalJSON(b)
 
 	if i, ok := h.state.getIRC(data.Server); ok && !i.Connected() {
-		if i.Config.TLS {
-			i.Config.TLSConfig.InsecureSkipVerify = data.SkipVerify
+		// Pinned servers are never verified, the pins are checked instead
+		if tlsConfig := i.TLSConfig(); i.Config.TLS && tlsConfig.VerifyPeerCertificate == nil {
+			tlsConfig = tlsConfig.Clone()
+			tlsConfig.InsecureSkipVerify = data.SkipVerify
+			i.SetTLSConfig(tlsConfig)
 		}
 		i.Reconnect()
 	}
@@ -155,7 +388,43 @@ func (h *wsHandler) part(b []byte) {
 	data.UnmarshalJSON(b)
 
 	if i, ok := h.state.getIRC(data.Server); ok {
-		i.Part(data.Channels...)
+		message := data.Reason
+		if message == "" {
+			message = h.state.partMessage()
+		}
+		i.PartWithMessage(message, data.Channels...)
+	}
+}
+
+func (h *wsHandler) renameChannel(b []byte) {
+	var data ChannelRename
+	data.UnmarshalJSON(b)
+
+	if i, ok := h.state.getIRC(data.Server); ok {
+		if !i.HasCapability("draft/channel-rename") {
+			h.state.sendJSON("error", IRCError{
+				Server:  data.Server,
+				Target:  data.Old,
+				Message: "The server does not support renaming channels",
+			})
+			return
+		}
+
+		i.Rename(data.Old, data.New, data.Reason)
+	}
+}
+
+// markRead sends a read receipt for a DM when the user has them enabled
+func (h *wsHandler) markRead(b []byte) {
+	var data MarkRead
+	data.UnmarshalJSON(b)
+
+	if data.MsgID == "" || isChannel(data.Target) || !h.state.user.GetClientSettings().ReadReceipts {
+		return
+	}
+
+	if i, ok := h.state.getIRC(data.Server); ok {
+		i.ReadReceipt(data.Target, data.MsgID)
 	}
 }
 
@@ -166,7 +435,12 @@ func (h *wsHandler) quit(b []byte) {
 	log.Println(h.addr, "[IRC] Remove server", data.Server)
 	if i, ok := h.state.getIRC(data.Server); ok {
 		h.state.deleteIRC(data.Server)
-		i.Quit()
+
+		message := data.Reason
+		if message == "" {
+			message = h.state.quitMessage()
+		}
+		i.Quit(message)
 	}
 
 	go h.state.user.RemoveServer(data.Server)
@@ -176,16 +450,14 @@ func (h *wsHandler) message(b []byte) {
 	var data Message
 	data.UnmarshalJSON(b)
 
-	if i, ok := h.state.getIRC(data.Server); ok {
-		i.Privmsg(data.To, data.Content)
-
-		go h.state.user.LogMessage(&storage.Message{
-			Server:  data.Server,
-			From:    i.GetNick(),
-			To:      data.To,
-			Content: data.Content,
-		})
+	i, ok := h.state.getIRC(data.Server)
+	if !ok {
+		return
 	}
+
+	// IDs are assigned by the server when the message gets logged
+	data.ID = ""
+	h.state.deliverMessage(i, data)
 }
 
 func (h *wsHandler) nick(b []byte) {
@@ -202,7 +474,22 @@ func (h *wsHandler) topic(b []byte) {
 	data.UnmarshalJSON(b)
 
 	if i, ok := h.state.getIRC(data.Server); ok {
-		i.Topic(data.Channel, data.Topic)
+		err := i.SetTopic(data.Channel, data.Topic)
+		if err == irc.ErrTopicTooLong {
+			h.state.sendJSON("error", Error{
+				Server:  data.Server,
+				Message: "Topics can be at most " + strconv.Itoa(i.TopicLength()) + " bytes long on this server",
+			})
+		}
+	}
+}
+
+func (h *wsHandler) fetchTopic(b []byte) {
+	var data FetchTopic
+	data.UnmarshalJSON(b)
+
+	if i, ok := h.state.getIRC(data.Server); ok {
+		i.Topic(data.Channel)
 	}
 }
 
@@ -233,6 +520,112 @@ func (h *wsHandler) whois(b []byte) {
 	}
 }
 
+func (h *wsHandler) motd(b []byte) {
+	var data MOTDRequest
+	data.UnmarshalJSON(b)
+
+	if i, ok := h.state.getIRC(data.Server); ok {
+		h.state.Set("request_motd_"+data.Server, true)
+		i.RequestMOTD()
+	}
+}
+
+func (h *wsHandler) oper(b []byte) {
+	var data OperRequest
+	data.UnmarshalJSON(b)
+
+	i, ok := h.state.getIRC(data.Server)
+	if !ok {
+		return
+	}
+
+	if data.Name == "" {
+		name, password, err := h.state.user.GetServerOper(data.Server)
+		if err != nil || name == "" {
+			return
+		}
+		data.Name = name
+		data.Password = password
+	}
+
+	i.Oper(data.Name, data.Password)
+}
+
+func (h *wsHandler) registerAccount(b []byte) {
+	var data AccountRegistration
+	data.UnmarshalJSON(b)
+
+	i, ok := h.state.getIRC(data.Server)
+	if !ok {
+		return
+	}
+
+	if !i.SupportsAccountRegistration() {
+		h.state.sendJSON("error", Error{
+			Server:  data.Server,
+			Message: "This server does not support account registration",
+		})
+		return
+	}
+	if data.Email == "" && i.AccountRegistrationRequiresEmail() {
+		h.state.sendJSON("error", Error{
+			Server:  data.Server,
+			Message: "This server requires an email address to register",
+		})
+		return
+	}
+
+	i.Register(data.Account, data.Email, data.Password)
+}
+
+func (h *wsHandler) verifyAccount(b []byte) {
+	var data AccountRegistration
+	data.UnmarshalJSON(b)
+
+	if i, ok := h.state.getIRC(data.Server); ok {
+		i.Verify(data.Account, data.Code)
+	}
+}
+
+func (h *wsHandler) setOper(b []byte) {
+	var data OperRequest
+	data.UnmarshalJSON(b)
+
+	err := h.state.user.SetServerOper(data.Server, data.Name, data.Password)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+func (h *wsHandler) help(b []byte) {
+	var data HelpRequest
+	data.UnmarshalJSON(b)
+
+	if i, ok := h.state.getIRC(data.Server); ok {
+		i.Help(data.Topic)
+	}
+}
+
+func (h *wsHandler) ctcp(b []byte) {
+	var data CTCP
+	data.UnmarshalJSON(b)
+
+	command := strings.ToUpper(data.Command)
+	if command == "" || command == "ACTION" || command == "DCC" {
+		return
+	}
+
+	if i, ok := h.state.getIRC(data.Server); ok {
+		params := data.Params
+		if command == "PING" && params == "" {
+			params = strconv.FormatInt(time.Now().UnixNano(), 10)
+		}
+
+		h.state.addCTCPRequest(data.Server, data.Target, command)
+		i.SendCTCP(data.Target, command, params)
+	}
+}
+
 func (h *wsHandler) away(b []byte) {
 	var data Away
 	data.UnmarshalJSON(b)
@@ -251,25 +644,264 @@ func (h *wsHandler) raw(b []byte) {
 	}
 }
 
+func (h *wsHandler) command(b []byte) {
+	var data Command
+	data.UnmarshalJSON(b)
+
+	i, ok := h.state.getIRC(data.Server)
+	if !ok {
+		return
+	}
+
+	line := data.Command
+	if strings.HasPrefix(line, "/") {
+		name, args := splitCommand(line)
+
+		aliases, err := h.state.user.GetAliases()
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		if expansion, ok := aliases[name]; ok {
+			line, err = expandAlias(expansion, args)
+			if err != nil {
+				h.state.sendJSON("error", Error{
+					Server:  data.Server,
+					Message: "/" + name + ": " + err.Error(),
+				})
+				return
+			}
+		}
+	}
+
+	if !strings.HasPrefix(line, "/") {
+		h.sendMessage(i, data.Server, data.Channel, line)
+		return
+	}
+
+	name, args := splitCommand(line)
+	switch name {
+	case "say":
+		h.sendMessage(i, data.Server, data.Channel, args)
+
+	case "me":
+		h.sendMessage(i, data.Server, data.Channel, "\x01ACTION "+args+"\x01")
+
+	case "msg":
+		if parts := strings.SplitN(args, " ", 2); len(parts) == 2 {
+			h.sendMessage(i, data.Server, parts[0], parts[1])
+		}
+
+	case "notice":
+		if parts := strings.SplitN(args, " ", 2); len(parts) == 2 {
+			i.Notice(parts[0], parts[1])
+		}
+
+	case "raw", "quote":
+		i.Write(args)
+
+	default:
+		i.Write(strings.ToUpper(name) + " " + args)
+	}
+}
+
+// sendMessage sends a message the client did not display itself,
+// so it gets sent to all sessions
+func (h *wsHandler) sendMessage(i *irc.Client, server, target, content string) {
+	if target == "" || content == "" {
+		return
+	}
+
+	statusMsg, to := i.SplitStatusMsg(target)
+	message := Message{
+		ID:        betterguid.New(),
+		Server:    server,
+		From:      i.GetNick(),
+		To:        to,
+		Content:   content,
+		StatusMsg: statusMsg,
+	}
+	h.state.sendJSON("message", message)
+	h.state.deliverMessage(i, message)
+}
+
+func (h *wsHandler) fetchAliases(b []byte) {
+	aliases, err := h.state.user.GetAliases()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	h.state.sendJSON("aliases", Aliases{Aliases: aliases})
+}
+
+func (h *wsHandler) setAlias(b []byte) {
+	var data Alias
+	data.UnmarshalJSON(b)
+
+	name := strings.ToLower(strings.TrimPrefix(data.Name, "/"))
+	if !isValidAliasName(name) || strings.TrimSpace(data.Expansion) == "" {
+		return
+	}
+
+	err := h.state.user.SetAlias(name, data.Expansion)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	h.fetchAliases(nil)
+}
+
+func (h *wsHandler) removeAlias(b []byte) {
+	var data Alias
+	data.UnmarshalJSON(b)
+
+	err := h.state.user.RemoveAlias(strings.ToLower(strings.TrimPrefix(data.Name, "/")))
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	h.fetchAliases(nil)
+}
+
+func (h *wsHandler) fetchCommandHistory(b []byte) {
+	var data CommandHistory
+	data.UnmarshalJSON(b)
+
+	commands, err := h.state.user.GetCommandHistory(data.Server, data.Channel)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	h.state.sendJSON("command_history", CommandHistory{
+		Server:   data.Server,
+		Channel:  data.Channel,
+		Commands: commands,
+	})
+}
+
+func (h *wsHandler) addCommandHistory(b []byte) {
+	var data Command
+	data.UnmarshalJSON(b)
+
+	err := h.state.user.AddCommandHistory(data.Server, data.Channel, data.Command)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+const (
+	searchLimit    = 50
+	maxSearchLimit = 200
+)
+
 func (h *wsHandler) search(b []byte) {
 	go func() {
 		var data SearchRequest
 		data.UnmarshalJSON(b)
 
-		results, err := h.state.user.SearchMessages(data.Server, data.Channel, data.Phrase)
+		if data.Offset < 0 {
+			data.Offset = 0
+		}
+		if data.Limit <= 0 {
+			data.Limit = searchLimit
+		} else if data.Limit > maxSearchLimit {
+			data.Limit = maxSearchLimit
+		}
+
+		result := SearchResult{
+			Server:  data.Server,
+			Channel: data.Channel,
+			Offset:  data.Offset,
+		}
+
+		var err error
+		if data.Snippets {
+			result.Matches, result.Total, err = h.state.user.SearchMessageSnippets(
+				data.Server, data.Channel, data.Phrase, data.Offset, data.Limit)
+		} else {
+			result.Results, result.Total, err = h.state.user.SearchMessages(
+				data.Server, data.Channel, data.Phrase, data.Offset, data.Limit)
+		}
 		if err != nil {
 			log.Println(err)
 			return
 		}
 
-		h.state.sendJSON("search", SearchResult{
-			Server:  data.Server,
-			Channel: data.Channel,
-			Results: results,
+		h.state.sendJSON("search", result)
+	}()
+}
+
+const (
+	mentionsLimit    = 50
+	maxMentionsLimit = 200
+)
+
+// fetchMentions sends the newest messages across all channels that
+// mentioned the user
+func (h *wsHandler) fetchMentions(b []byte) {
+	go func() {
+		var data FetchMentions
+		data.UnmarshalJSON(b)
+
+		if data.Limit <= 0 {
+			data.Limit = mentionsLimit
+		} else if data.Limit > maxMentionsLimit {
+			data.Limit = maxMentionsLimit
+		}
+
+		mentions, err := h.state.user.GetMentions(data.Limit)
+		if err != nil {
+			h.state.sendJSON("error", Error{
+				Message: err.Error(),
+			})
+			return
+		}
+
+		h.state.sendJSON("mentions", Mentions{
+			Mentions: mentions,
+		})
+	}()
+}
+
+// reindexSearch rebuilds the search index of the user from the message log
+func (h *wsHandler) reindexSearch(b []byte) {
+	go func() {
+		count, err := h.state.user.Reindex()
+		if err != nil {
+			log.Println(h.addr, "[Search] Reindex failed for user ID:", h.state.user.ID, "|", err)
+			h.state.sendJSON("error", Error{
+				Message: err.Error(),
+			})
+			return
+		}
+
+		log.Println(h.addr, "[Search] Reindexed", count, "messages for user ID:", h.state.user.ID)
+		h.state.sendJSON("search_reindexed", SearchReindexed{
+			Messages: count,
 		})
 	}()
 }
 
+// deleteAccount deletes the user of the session along with everything
+// stored for it, all of its sessions get closed
+func (h *wsHandler) deleteAccount(b []byte) {
+	err := h.state.srv.deleteUser(h.state)
+	if err != nil {
+		log.Println(h.addr, "[Auth] Failed to delete user ID:", h.state.user.ID, "|", err)
+		h.state.sendJSON("error", Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	log.Println(h.addr, "[Auth] Deleted account | User ID:", h.state.user.ID)
+}
+
 func (h *wsHandler) cert(b []byte) {
 	var data ClientCert
 	data.UnmarshalJSON(b)
@@ -290,20 +922,267 @@ func (h *wsHandler) fetchMessages(b []byte) {
 	h.state.sendMessages(data.Server, data.Channel, 200, data.Next)
 }
 
+func (h *wsHandler) fetchMessageContext(b []byte) {
+	var data FetchMessageContext
+	data.UnmarshalJSON(b)
+
+	if data.Count <= 0 || data.Count > 100 {
+		data.Count = 25
+	}
+
+	messages, err := h.state.user.GetMessageContext(data.Server, data.Channel, data.ID, data.Count)
+	if err != nil && err != storage.ErrNotFound {
+		log.Println(err)
+		return
+	}
+
+	// Messages is left empty when the message no longer exists
+	h.state.sendJSON("message_context", MessageContext{
+		Server:   data.Server,
+		To:       data.Channel,
+		ID:       data.ID,
+		Messages: messages,
+	})
+}
+
+func (h *wsHandler) fetchMessagesAt(b []byte) {
+	var data FetchMessagesAt
+	data.UnmarshalJSON(b)
+
+	if data.Count <= 0 || data.Count > 100 {
+		data.Count = 25
+	}
+
+	messages, id, err := h.state.user.GetMessagesAt(data.Server, data.Channel, time.Unix(data.Time, 0), data.Count)
+	if err != nil && err != storage.ErrNotFound {
+		log.Println(err)
+		return
+	}
+
+	// ID and Messages are left empty when nothing was sent after Time
+	h.state.sendJSON("message_context", MessageContext{
+		Server:   data.Server,
+		To:       data.Channel,
+		ID:       id,
+		Messages: messages,
+	})
+}
+
+func (h *wsHandler) fetchTopics(b []byte) {
+	var data FetchTopics
+	data.UnmarshalJSON(b)
+
+	topics, hasMore, err := h.state.user.GetTopics(data.Server, data.Channel, 50, data.Next)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	res := TopicHistory{
+		Server:  data.Server,
+		Channel: data.Channel,
+		Topics:  topics,
+	}
+
+	if hasMore {
+		res.Next = topics[0].ID
+	}
+
+	h.state.sendJSON("topics", res)
+}
+
+func (h *wsHandler) fetchUsers(b []byte) {
+	var data FetchUsers
+	data.UnmarshalJSON(b)
+
+	if i, ok := h.state.getIRC(data.Server); ok {
+		users := i.ChannelUsers(data.Channel)
+		page := pageUsers(users, data.Offset, h.state.userlistLimit())
+		accounts, hosts := h.state.whoInfo(data.Server, i.Casefold(data.Channel), page)
+		metadata := h.state.userMetadata(data.Server, page)
+
+		h.state.sendJSON("users_page", UserlistPage{
+			Server:   data.Server,
+			Channel:  data.Channel,
+			Users:    page,
+			Offset:   data.Offset,
+			Total:    len(users),
+			Away:     awayUsers(i, data.Channel, page),
+			Metadata: metadata,
+			Avatars:  h.state.userlistAvatars(page, accounts, metadata),
+			Accounts: accounts,
+			Hosts:    hosts,
+		})
+	}
+}
+
+func (h *wsHandler) loadChannel(b []byte) {
+	var data LoadChannel
+	data.UnmarshalJSON(b)
+
+	if i, ok := h.state.getIRC(data.Server); ok {
+		i.Topic(data.Channel)
+		i.Names(data.Channel)
+	}
+}
+
+func (h *wsHandler) setChannelLazy(b []byte) {
+	var data ChannelLazy
+	data.UnmarshalJSON(b)
+
+	err := h.state.user.SetChannelLazy(data.Server, data.Channel, data.Lazy)
+	if err != nil {
+		h.state.sendJSON("error", Error{
+			Server:  data.Server,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	h.state.sendJSON("channel_lazy", data)
+}
+
+func (h *wsHandler) setMetadata(b []byte) {
+	var data SetMetadata
+	data.UnmarshalJSON(b)
+
+	if i, ok := h.state.getIRC(data.Server); ok {
+		if !i.SupportsMetadata() {
+			h.state.sendJSON("error", Error{
+				Server:  data.Server,
+				Message: errMetadataUnsupported.Error(),
+			})
+			return
+		}
+
+		i.MetadataSet(data.Key, data.Value)
+	}
+}
+
+func (h *wsHandler) fetchMetadata(b []byte) {
+	var data FetchMetadata
+	data.UnmarshalJSON(b)
+
+	if i, ok := h.state.getIRC(data.Server); ok {
+		if !i.SupportsMetadata() {
+			h.state.sendJSON("error", Error{
+				Server:  data.Server,
+				Message: errMetadataUnsupported.Error(),
+			})
+			return
+		}
+
+		if len(data.Keys) > 0 {
+			i.MetadataGet(data.Target, data.Keys...)
+		} else {
+			i.MetadataList(data.Target)
+		}
+	}
+}
+
+func (h *wsHandler) who(b []byte) {
+	var data Who
+	data.UnmarshalJSON(b)
+
+	if i, ok := h.state.getIRC(data.Server); ok {
+		i.Who(data.Channel)
+	}
+}
+
+// serverInfo sends what is known about the connection to a server, or to
+// every server if none is specified
+func (h *wsHandler) serverInfo(b []byte) {
+	var data ServerInfoRequest
+	data.UnmarshalJSON(b)
+
+	connectionStates := h.state.getConnectionStates()
+
+	for server, i := range h.state.getIRCs() {
+		if data.Server != "" && data.Server != server {
+			continue
+		}
+
+		h.state.sendJSON("server_info", ServerInfo{
+			Server:   server,
+			Network:  i.Features.String("NETWORK"),
+			Nick:     i.GetNick(),
+			Modes:    i.UserModes(),
+			Status:   newConnectionUpdate(server, connectionStates[server]),
+			Features: i.Features.Map(),
+			TLS:      newTLSInfo(server, i.PeerCertificate()),
+		})
+	}
+}
+
+func (h *wsHandler) rawLog(b []byte) {
+	var data RawLog
+	data.UnmarshalJSON(b)
+
+	cfg := h.state.srv.Config()
+	if !cfg.RawLog.Enabled {
+		return
+	}
+
+	err := h.state.setRawLog(data.Server, data.Enabled, int64(cfg.RawLog.MaxSize)*1024*1024)
+	if err != nil {
+		log.Println(err)
+		h.state.sendJSON("error", Error{
+			Server:  data.Server,
+			Message: "Could not open raw log: " + err.Error(),
+		})
+		return
+	}
+
+	h.state.sendJSON("raw_log", data)
+}
+
 func (h *wsHandler) setServerName(b []byte) {
 	var data ServerName
 	data.UnmarshalJSON(b)
 
 	if isValidServerName(data.Name) {
 		h.state.user.SetServerName(data.Name, data.Server)
+		h.state.sendJSON("server_name", data)
 	}
 }
 
+func (h *wsHandler) setOrder(b []byte) {
+	var data Order
+	data.UnmarshalJSON(b)
+
+	err := h.state.user.SetOrder(data.Placements)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	h.state.sendJSON("order", data)
+}
+
+func (h *wsHandler) setAppearance(b []byte) {
+	var data Appearance
+	data.UnmarshalJSON(b)
+
+	err := h.state.user.SetAppearance(storage.Appearance(data))
+	if err != nil {
+		h.state.sendJSON("error", Error{
+			Server:  data.Server,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	h.state.sendJSON("appearance", data)
+}
+
 func (h *wsHandler) setSettings(b []byte) {
 	err := h.state.user.UnmarshalClientSettingsJSON(b)
 	if err != nil {
 		log.Println(err)
+		return
 	}
+
+	h.state.sendJSON("settings", h.state.user.GetClientSettings())
 }
 
 func (h *wsHandler) channelSearch(b []byte) {
@@ -317,9 +1196,16 @@ func (h *wsHandler) channelSearch(b []byte) {
 			n = 50
 		}
 
+		var results []*storage.ChannelListItem
+		if data.Fuzzy {
+			results = index.SearchFuzzy(data.Q, data.Start, n)
+		} else {
+			results = index.SearchN(data.Q, data.Start, n)
+		}
+
 		h.state.sendJSON("channel_search", ChannelSearchResult{
 			ChannelSearch: data,
-			Results:       index.SearchN(data.Q, data.Start, n),
+			Results:       results,
 		})
 	}
 
@@ -335,6 +1221,7 @@ func (h *wsHandler) openDM(b []byte) {
 
 	h.state.sendLastMessages(data.Server, data.Name, 50)
 	h.state.user.AddOpenDM(data.Server, data.Name)
+	h.state.sendJSON("open_dm", data)
 }
 
 func (h *wsHandler) closeDM(b []byte) {
@@ -342,31 +1229,78 @@ func (h *wsHandler) closeDM(b []byte) {
 	data.UnmarshalJSON(b)
 
 	h.state.user.RemoveOpenDM(data.Server, data.Name)
+	h.state.sendJSON("close_dm", data)
 }
 
 func (h *wsHandler) initHandlers() {
 	h.handlers = map[string]func([]byte){
-		"connect":         h.connect,
-		"reconnect":       h.reconnect,
-		"join":            h.join,
-		"part":            h.part,
-		"quit":            h.quit,
-		"message":         h.message,
-		"nick":            h.nick,
-		"topic":           h.topic,
-		"invite":          h.invite,
-		"kick":            h.kick,
-		"whois":           h.whois,
-		"away":            h.away,
-		"raw":             h.raw,
-		"search":          h.search,
-		"cert":            h.cert,
-		"fetch_messages":  h.fetchMessages,
-		"set_server_name": h.setServerName,
-		"settings_set":    h.setSettings,
-		"channel_search":  h.channelSearch,
-		"open_dm":         h.openDM,
-		"close_dm":        h.closeDM,
+		"connect":               h.connect,
+		"reconnect":             h.reconnect,
+		"join":                  h.join,
+		"part":                  h.part,
+		"quit":                  h.quit,
+		"message":               h.message,
+		"nick":                  h.nick,
+		"topic":                 h.topic,
+		"invite":                h.invite,
+		"kick":                  h.kick,
+		"whois":                 h.whois,
+		"ctcp":                  h.ctcp,
+		"set_commands":          h.setCommands,
+		"set_client_tags":       h.setClientTags,
+		"set_server_tls":        h.setServerTLS,
+		"set_server_pins":       h.setServerPins,
+		"set_always_on":         h.setAlwaysOn,
+		"motd":                  h.motd,
+		"help":                  h.help,
+		"oper":                  h.oper,
+		"set_oper":              h.setOper,
+		"register_account":      h.registerAccount,
+		"verify_account":        h.verifyAccount,
+		"admin_users":           h.adminUsers,
+		"admin_disconnect":      h.adminDisconnect,
+		"admin_log_level":       h.adminLogLevel,
+		"admin_delete_user":     h.adminDeleteUser,
+		"admin_compact":         h.adminCompact,
+		"check_server":          h.checkServer,
+		"reindex_search":        h.reindexSearch,
+		"delete_account":        h.deleteAccount,
+		"away":                  h.away,
+		"raw":                   h.raw,
+		"command":               h.command,
+		"fetch_aliases":         h.fetchAliases,
+		"set_alias":             h.setAlias,
+		"remove_alias":          h.removeAlias,
+		"fetch_command_history": h.fetchCommandHistory,
+		"add_command_history":   h.addCommandHistory,
+		"search":                h.search,
+		"fetch_mentions":        h.fetchMentions,
+		"fetch_metadata":        h.fetchMetadata,
+		"set_metadata":          h.setMetadata,
+		"cert":                  h.cert,
+		"fetch_messages":        h.fetchMessages,
+		"fetch_message_context": h.fetchMessageContext,
+		"fetch_messages_at":     h.fetchMessagesAt,
+		"fetch_topic":           h.fetchTopic,
+		"fetch_topics":          h.fetchTopics,
+		"fetch_users":           h.fetchUsers,
+		"load_channel":          h.loadChannel,
+		"set_channel_lazy":      h.setChannelLazy,
+		"who":                   h.who,
+		"rename_channel":        h.renameChannel,
+		"mark_read":             h.markRead,
+		"raw_log":               h.rawLog,
+		"server_info":           h.serverInfo,
+		"set_server_name":       h.setServerName,
+		"set_order":             h.setOrder,
+		"set_appearance":        h.setAppearance,
+		"settings_set":          h.setSettings,
+		"channel_search":        h.channelSearch,
+		"open_dm":               h.openDM,
+		"close_dm":              h.closeDM,
+		"dcc_transfers":         h.dccTransfers,
+		"cancel_dcc":            h.cancelDCC,
+		"create_paste":          h.createPaste,
 	}
 }
 
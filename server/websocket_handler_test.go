@@ -0,0 +1,690 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerInfo(t *testing.T) {
+	s := NewState(user, nil)
+
+	i := irc.NewClient(&irc.Config{
+		Nick: "nick",
+		Host: "host.com",
+	})
+	i.Features.Parse([]string{"nick", "NETWORK=Net", "CHANTYPES=#", "are supported"})
+	s.setIRC("host.com", i)
+	s.setConnectionState("host.com", irc.ConnectionState{Connected: true})
+
+	h := &wsHandler{state: s}
+	h.initHandlers()
+	h.dispatchRequest(WSRequest{
+		Type: "server_info",
+		Data: []byte(`{}`),
+	})
+
+	res := <-s.broadcast
+	assert.Equal(t, "server_info", res.Type)
+	info, ok := res.Data.(ServerInfo)
+	assert.True(t, ok)
+	assert.Equal(t, "host.com", info.Server)
+	assert.Equal(t, "Net", info.Network)
+	assert.Equal(t, "nick", info.Nick)
+	assert.True(t, info.Status.Connected)
+	assert.Equal(t, []string{"#"}, info.Features["CHANTYPES"])
+
+	h.dispatchRequest(WSRequest{
+		Type: "server_info",
+		Data: []byte(`{"server":"other.com"}`),
+	})
+	assert.Len(t, s.broadcast, 0)
+}
+
+func TestConnectServerLimit(t *testing.T) {
+	user.AddServer(&storage.Server{Host: "existing.example.com"})
+	servers, err := user.GetServers()
+	assert.Nil(t, err)
+
+	defer func(getLimits func(*storage.User) storage.Limits) {
+		storage.GetLimits = getLimits
+	}(storage.GetLimits)
+	storage.GetLimits = func(_ *storage.User) storage.Limits {
+		return storage.Limits{Servers: len(servers)}
+	}
+
+	s := NewState(user, New(&config.Config{}))
+	h := &wsHandler{state: s}
+	h.initHandlers()
+	h.dispatchRequest(WSRequest{
+		Type: "connect",
+		Data: []byte(`{"host":"limit.example.com","nick":"nick"}`),
+	})
+
+	checkResponse(t, "error", Error{
+		Server:  "limit.example.com",
+		Message: storage.ErrServerLimit.Error(),
+	}, <-s.broadcast)
+
+	_, ok := s.getIRC("limit.example.com")
+	assert.False(t, ok)
+}
+
+func TestConnectProbeAsync(t *testing.T) {
+	servers, err := user.GetServers()
+	assert.Nil(t, err)
+
+	defer func(getLimits func(*storage.User) storage.Limits) {
+		storage.GetLimits = getLimits
+	}(storage.GetLimits)
+	storage.GetLimits = func(_ *storage.User) storage.Limits {
+		return storage.Limits{Servers: len(servers)}
+	}
+
+	// The stub never answers the handshake, so the probe runs until the
+	// TLS timeout without holding up the request loop
+	port, _ := stubIRCServer(t, "")
+	cfg := &config.Config{
+		ServerPorts: config.ServerPorts{TLS: port, DetectTLS: true},
+		Timeouts:    config.Timeouts{TLS: 200 * time.Millisecond},
+	}
+	s := NewState(user, New(cfg))
+	h := &wsHandler{state: s}
+	h.initHandlers()
+
+	start := time.Now()
+	h.dispatchRequest(WSRequest{
+		Type: "connect",
+		Data: []byte(`{"host":"127.0.0.1","nick":"nick"}`),
+	})
+	assert.True(t, time.Since(start) < 100*time.Millisecond)
+
+	checkResponse(t, "error", Error{
+		Server:  "127.0.0.1",
+		Message: storage.ErrServerLimit.Error(),
+	}, <-s.broadcast)
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+}
+
+func TestConnectConnectionLimit(t *testing.T) {
+	s := NewState(user, New(&config.Config{
+		Limits: config.Limits{MaxConnections: 1},
+	}))
+	s.setIRC("connected.example.com", irc.NewClient(&irc.Config{}))
+
+	h := &wsHandler{state: s}
+	h.initHandlers()
+	h.dispatchRequest(WSRequest{
+		Type: "connect",
+		Data: []byte(`{"host":"another.example.com","nick":"nick"}`),
+	})
+
+	checkResponse(t, "error", Error{
+		Server:  "another.example.com",
+		Message: errConnectionLimit.Error(),
+	}, <-s.broadcast)
+
+	_, ok := s.getIRC("another.example.com")
+	assert.False(t, ok)
+}
+
+func TestSetServerTLS(t *testing.T) {
+	user.AddServer(&storage.Server{Host: "sni.example.com", TLS: true})
+
+	s := NewState(user, nil)
+	i := irc.NewClient(&irc.Config{
+		Host:      "sni.example.com",
+		TLS:       true,
+		TLSConfig: &tls.Config{},
+	})
+	s.setIRC("sni.example.com", i)
+
+	h := &wsHandler{state: s}
+	h.initHandlers()
+	h.dispatchRequest(WSRequest{
+		Type: "set_server_tls",
+		Data: []byte(`{"server":"sni.example.com","serverName":"irc.example.com","alpn":["irc"]}`),
+	})
+
+	checkResponse(t, "server_tls", ServerTLS{
+		Server:     "sni.example.com",
+		ServerName: "irc.example.com",
+		ALPN:       []string{"irc"},
+	}, <-s.broadcast)
+	assert.Equal(t, "irc.example.com", i.Config.TLSConfig.ServerName)
+	assert.Equal(t, []string{"irc"}, i.Config.TLSConfig.NextProtos)
+
+	server, err := user.GetServer("sni.example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, "irc.example.com", server.TLSServerName)
+	assert.Equal(t, []string{"irc"}, server.ALPN)
+}
+
+func TestSetClientTags(t *testing.T) {
+	user.AddServer(&storage.Server{Host: "tags.example.com"})
+
+	s := NewState(user, nil)
+	i := irc.NewClient(&irc.Config{Host: "tags.example.com"})
+	s.setIRC("tags.example.com", i)
+
+	h := &wsHandler{state: s}
+	h.initHandlers()
+	h.dispatchRequest(WSRequest{
+		Type: "set_client_tags",
+		Data: []byte(`{"server":"tags.example.com","forward":["+vendor/*"],"send":["+vendor/bot=1"]}`),
+	})
+
+	checkResponse(t, "client_tags", ClientTags{
+		Server:  "tags.example.com",
+		Forward: []string{"+vendor/*"},
+		Send:    []string{"+vendor/bot=1"},
+	}, <-s.broadcast)
+	assert.Equal(t, map[string]string{"+vendor/bot": "1"}, i.Config.ClientTags)
+	assert.Equal(t, []string{"+vendor/*"}, s.getForwardTags("tags.example.com"))
+
+	server, err := user.GetServer("tags.example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"+vendor/*"}, server.ForwardTags)
+	assert.Equal(t, []string{"+vendor/bot=1"}, server.SendTags)
+
+	h.dispatchRequest(WSRequest{
+		Type: "set_client_tags",
+		Data: []byte(`{"server":"tags.example.com","send":["msgid=1"]}`),
+	})
+
+	checkResponse(t, "error", Error{
+		Server:  "tags.example.com",
+		Message: storage.ErrInvalidClientTag.Error(),
+	}, <-s.broadcast)
+}
+
+func TestSetAppearance(t *testing.T) {
+	user.AddServer(&storage.Server{Host: "appearance.example.com"})
+
+	s := NewState(user, nil)
+	go s.run()
+
+	phone := newWSConn(nil)
+	desktop := newWSConn(nil)
+	s.setWS("10.0.0.1:1234", phone)
+	s.setWS("10.0.0.2:1234", desktop)
+
+	h := &wsHandler{state: s}
+	h.initHandlers()
+	h.dispatchRequest(WSRequest{
+		Type: "set_appearance",
+		Data: []byte(`{"server":"appearance.example.com","color":"#abc","label":"work"}`),
+	})
+
+	expected := Appearance{
+		Server: "appearance.example.com",
+		Color:  "#abc",
+		Label:  "work",
+	}
+	for _, ws := range []*wsConn{phone, desktop} {
+		select {
+		case <-ws.queue.ready:
+			events := ws.queue.drain()
+			assert.Len(t, events, 1)
+			checkResponse(t, "appearance", expected, events[0])
+
+		case <-time.After(time.Second):
+			t.Fatal("Session did not receive event")
+		}
+	}
+
+	server, err := user.GetServer("appearance.example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, "#abc", server.Color)
+	assert.Equal(t, "work", server.Label)
+
+	h.dispatchRequest(WSRequest{
+		Type: "set_appearance",
+		Data: []byte(`{"server":"appearance.example.com","color":"blue"}`),
+	})
+
+	select {
+	case <-phone.queue.ready:
+		events := phone.queue.drain()
+		assert.Len(t, events, 1)
+		checkResponse(t, "error", Error{
+			Server:  "appearance.example.com",
+			Message: storage.ErrInvalidColor.Error(),
+		}, events[0])
+
+	case <-time.After(time.Second):
+		t.Fatal("Session did not receive error")
+	}
+}
+
+func newTestCertificate(t *testing.T, notAfter time.Time) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "irc.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		DNSNames:     []string{"irc.example.com"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}
+}
+
+func TestTLSInfo(t *testing.T) {
+	notAfter := time.Now().Add(7 * 24 * time.Hour).Truncate(time.Second)
+	cert := newTestCertificate(t, notAfter)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	})
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		ioutil.ReadAll(conn)
+	}()
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	c := irc.NewClient(&irc.Config{
+		Nick: "nick",
+		Host: "127.0.0.1",
+		Port: port,
+		TLS:  true,
+		TLSConfig: &tls.Config{
+			InsecureSkipVerify: true,
+		},
+	})
+	s := NewState(user, nil)
+	s.setIRC("127.0.0.1", c)
+	c.Connect()
+	go newIRCHandler(c, s).run()
+	defer c.Quit()
+
+	hash := sha256.Sum256(cert.Certificate[0])
+	expected := TLSInfo{
+		Server:      "127.0.0.1",
+		Subject:     "CN=irc.example.com",
+		Issuer:      "CN=irc.example.com",
+		NotAfter:    notAfter.Unix(),
+		Fingerprint: hex.EncodeToString(hash[:]),
+		ExpiresSoon: true,
+	}
+
+	var res WSResponse
+	timeout := time.After(2 * time.Second)
+	for res.Type != "tls_info" {
+		select {
+		case res = <-s.broadcast:
+		case <-timeout:
+			t.Fatal("No tls_info event")
+		}
+	}
+	info := res.Data.(TLSInfo)
+	expected.NotBefore = info.NotBefore
+	assert.Equal(t, expected, info)
+
+	res = <-s.broadcast
+	assert.Equal(t, "error", res.Type)
+	assert.Contains(t, res.Data.(Error).Message, "expires "+time.Unix(notAfter.Unix(), 0).UTC().Format("2006-01-02"))
+
+	h := &wsHandler{state: s}
+	h.initHandlers()
+	h.dispatchRequest(WSRequest{Type: "server_info", Data: []byte(`{"server":"127.0.0.1"}`)})
+	res = <-s.broadcast
+	assert.Equal(t, &expected, res.Data.(ServerInfo).TLS)
+}
+
+func TestSetServerPins(t *testing.T) {
+	user.AddServer(&storage.Server{Host: "pins.example.com", TLS: true})
+
+	s := NewState(user, &Dispatch{cfg: &config.Config{VerifyCertificates: true}})
+	i := irc.NewClient(&irc.Config{Host: "pins.example.com", TLS: true, TLSConfig: &tls.Config{}})
+	s.setIRC("pins.example.com", i)
+
+	h := &wsHandler{state: s}
+	h.initHandlers()
+
+	pin := strings.Repeat("ab", 32)
+	h.dispatchRequest(WSRequest{
+		Type: "set_server_pins",
+		Data: []byte(`{"server":"pins.example.com","pins":["` + strings.Repeat("AB:", 31) + `AB",""]}`),
+	})
+	checkResponse(t, "server_pins", ServerPins{
+		Server: "pins.example.com",
+		Pins:   []string{pin},
+	}, <-s.broadcast)
+	assert.True(t, i.Config.TLSConfig.InsecureSkipVerify)
+	assert.NotNil(t, i.Config.TLSConfig.VerifyPeerCertificate)
+
+	server, err := user.GetServer("pins.example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{pin}, server.PinnedCerts)
+
+	h.dispatchRequest(WSRequest{
+		Type: "set_server_pins",
+		Data: []byte(`{"server":"pins.example.com","pins":["abc"]}`),
+	})
+	checkResponse(t, "error", Error{
+		Server:  "pins.example.com",
+		Message: storage.ErrInvalidPin.Error(),
+	}, <-s.broadcast)
+
+	h.dispatchRequest(WSRequest{
+		Type: "set_server_pins",
+		Data: []byte(`{"server":"pins.example.com","pins":[]}`),
+	})
+	checkResponse(t, "server_pins", ServerPins{
+		Server: "pins.example.com",
+	}, <-s.broadcast)
+	assert.False(t, i.Config.TLSConfig.InsecureSkipVerify)
+	assert.Nil(t, i.Config.TLSConfig.VerifyPeerCertificate)
+}
+
+func TestTLSInfoRotationPin(t *testing.T) {
+	current := newTestCertificate(t, time.Now().Add(time.Hour))
+	next := newTestCertificate(t, time.Now().Add(365*24*time.Hour))
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{next},
+	})
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		ioutil.ReadAll(conn)
+	}()
+
+	currentHash := sha256.Sum256(current.Certificate[0])
+	nextHash := sha256.Sum256(next.Certificate[0])
+	server := &storage.Server{
+		Host:        "127.0.0.1",
+		TLS:         true,
+		PinnedCerts: []string{hex.EncodeToString(currentHash[:]), hex.EncodeToString(nextHash[:])},
+	}
+	u, err := storage.NewUser(store)
+	assert.Nil(t, err)
+	assert.Nil(t, u.AddServer(server))
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	c := irc.NewClient(&irc.Config{
+		Nick:      "nick",
+		Host:      "127.0.0.1",
+		Port:      port,
+		TLS:       true,
+		TLSConfig: newTLSConfig(&config.Config{VerifyCertificates: true}, server, nil),
+	})
+	s := NewState(u, nil)
+	s.setIRC("127.0.0.1", c)
+	c.Connect()
+	go newIRCHandler(c, s).run()
+	defer c.Quit()
+
+	var res WSResponse
+	timeout := time.After(2 * time.Second)
+	for res.Type != "tls_info" {
+		select {
+		case res = <-s.broadcast:
+		case <-timeout:
+			t.Fatal("No tls_info event")
+		}
+	}
+
+	res = <-s.broadcast
+	assert.Equal(t, "error", res.Type)
+	assert.Contains(t, res.Data.(Error).Message, "rotation pin")
+}
+
+func nextLine(t *testing.T, lines chan string, prefix string) string {
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case line := <-lines:
+			if strings.HasPrefix(line, prefix) {
+				return line
+			}
+		case <-timeout:
+			t.Fatal("No", prefix, "received")
+			return ""
+		}
+	}
+}
+
+func TestMarkRead(t *testing.T) {
+	u, err := storage.NewUser(store)
+	assert.Nil(t, err)
+
+	s := NewState(u, New(&config.Config{}))
+	h := &wsHandler{state: s}
+	h.initHandlers()
+
+	port, lines := stubIRCServer(t, ":srv CAP * LS :message-tags\r\n"+
+		":srv CAP * ACK :message-tags\r\n:srv 001 nick :Welcome\r\n")
+	i := irc.NewClient(&irc.Config{Host: "127.0.0.1", Port: port, Nick: "nick"})
+	s.setIRC("127.0.0.1", i)
+	i.Connect()
+	defer i.Quit()
+	for msg := range i.Messages {
+		if msg.Command == irc.RPL_WELCOME {
+			break
+		}
+	}
+
+	markRead := func(target, msgid string) {
+		h.dispatchRequest(WSRequest{
+			Type: "mark_read",
+			Data: []byte(`{"server":"127.0.0.1","target":"` + target + `","msgID":"` + msgid + `"}`),
+		})
+	}
+
+	// Read receipts are opt-in
+	markRead("other", "abc")
+
+	settings := u.GetClientSettings()
+	settings.ReadReceipts = true
+	assert.Nil(t, u.SetClientSettings(settings))
+
+	markRead("#chan", "def")
+	markRead("other", "")
+	markRead("other", "ghi")
+	assert.Equal(t, "@+draft/read=ghi TAGMSG other", nextLine(t, lines, "@"))
+}
+
+func TestLeaveMessages(t *testing.T) {
+	u, err := storage.NewUser(store)
+	assert.Nil(t, err)
+	settings := u.GetClientSettings()
+	settings.QuitMessage = "Dispatch {version}"
+	settings.PartMessage = "bye"
+	assert.Nil(t, u.SetClientSettings(settings))
+
+	s := NewState(u, New(&config.Config{}))
+	go s.run()
+	h := &wsHandler{state: s}
+	h.initHandlers()
+
+	connect := func() chan string {
+		port, lines := stubIRCServer(t, ":srv 001 nick :Welcome\r\n")
+		i := irc.NewClient(&irc.Config{Host: "127.0.0.1", Port: port, Nick: "nick"})
+		s.setIRC("127.0.0.1", i)
+		i.Connect()
+		<-i.Messages
+		return lines
+	}
+
+	lines := connect()
+	h.dispatchRequest(WSRequest{
+		Type: "part",
+		Data: []byte(`{"server":"127.0.0.1","channels":["#a"]}`),
+	})
+	assert.Equal(t, "PART #a :bye", nextLine(t, lines, "PART"))
+
+	h.dispatchRequest(WSRequest{
+		Type: "part",
+		Data: []byte(`{"server":"127.0.0.1","channels":["#b"],"reason":"later"}`),
+	})
+	assert.Equal(t, "PART #b :later", nextLine(t, lines, "PART"))
+
+	h.dispatchRequest(WSRequest{
+		Type: "quit",
+		Data: []byte(`{"server":"127.0.0.1"}`),
+	})
+	assert.Equal(t, "QUIT :Dispatch "+version.Tag, nextLine(t, lines, "QUIT"))
+
+	lines = connect()
+	h.dispatchRequest(WSRequest{
+		Type: "quit",
+		Data: []byte(`{"server":"127.0.0.1","reason":"brb"}`),
+	})
+	assert.Equal(t, "QUIT :brb", nextLine(t, lines, "QUIT"))
+}
+
+func TestSetTopic(t *testing.T) {
+	s := NewState(user, nil)
+	h := &wsHandler{state: s}
+	h.initHandlers()
+
+	port, lines := stubIRCServer(t, ":srv 001 nick :Welcome\r\n"+
+		":srv 005 nick TOPICLEN=10 :are supported by this server\r\n")
+	i := irc.NewClient(&irc.Config{Host: "127.0.0.1", Port: port, Nick: "nick"})
+	s.setIRC("127.0.0.1", i)
+	i.Connect()
+	defer i.Quit()
+	for msg := range i.Messages {
+		if msg.Command == irc.RPL_ISUPPORT {
+			break
+		}
+	}
+
+	h.dispatchRequest(WSRequest{
+		Type: "topic",
+		Data: []byte(`{"server":"127.0.0.1","channel":"#chan","topic":"way too long"}`),
+	})
+	checkResponse(t, "error", Error{
+		Server:  "127.0.0.1",
+		Message: "Topics can be at most 10 bytes long on this server",
+	}, <-s.broadcast)
+
+	h.dispatchRequest(WSRequest{
+		Type: "topic",
+		Data: []byte(`{"server":"127.0.0.1","channel":"#chan","topic":"just right"}`),
+	})
+	assert.Equal(t, "TOPIC #chan :just right", nextLine(t, lines, "TOPIC"))
+
+	h.dispatchRequest(WSRequest{
+		Type: "fetch_topic",
+		Data: []byte(`{"server":"127.0.0.1","channel":"#chan"}`),
+	})
+	assert.Equal(t, "TOPIC #chan", nextLine(t, lines, "TOPIC"))
+}
+
+func TestFetchUsersWho(t *testing.T) {
+	port, _ := stubIRCServer(t, ":srv 001 nick :Welcome\r\n"+
+		":srv 353 nick = #chan :nick @op user\r\n"+
+		":srv 366 nick #chan :End of NAMES list\r\n")
+
+	c := irc.NewClient(&irc.Config{Host: "127.0.0.1", Port: port, Nick: "nick"})
+	s := NewState(user, nil)
+	s.setIRC("127.0.0.1", c)
+	c.Connect()
+	defer c.Quit()
+
+	timeout := time.After(time.Second)
+	for len(c.ChannelUsers("#chan")) == 0 {
+		select {
+		case <-c.Messages:
+		case <-timeout:
+			t.Fatal("Missing NAMES")
+		}
+	}
+
+	s.setWhoReplies("127.0.0.1", "#chan", []*irc.WhoReply{
+		{Nick: "op", Username: "~op", Host: "op.host", Account: "opacc"},
+		{Nick: "user", Username: "user", Host: "user.host"},
+	})
+
+	h := &wsHandler{state: s}
+	h.initHandlers()
+	h.dispatchRequest(WSRequest{
+		Type: "fetch_users",
+		Data: []byte(`{"server":"127.0.0.1","channel":"#chan"}`),
+	})
+
+	res := <-s.broadcast
+	assert.Equal(t, "users_page", res.Type)
+	page := res.Data.(UserlistPage)
+	assert.Equal(t, map[string]string{"op": "opacc"}, page.Accounts)
+	assert.Equal(t, map[string]string{
+		"op":   "~op@op.host",
+		"user": "user@user.host",
+	}, page.Hosts)
+}
+
+func TestCommandMessage(t *testing.T) {
+	s := NewState(user, nil)
+	i := irc.NewClient(&irc.Config{Nick: "nick", Host: "command.example.com"})
+	i.Features.Parse([]string{"nick", "STATUSMSG=@", "are supported"})
+	s.setIRC("command.example.com", i)
+
+	h := &wsHandler{state: s}
+	h.initHandlers()
+
+	for _, tc := range []struct {
+		command   string
+		to        string
+		content   string
+		statusMsg string
+	}{
+		{"hello", "#chan", "hello", ""},
+		{"/me waves", "#chan", "\x01ACTION waves\x01", ""},
+		{"/msg @#ops only ops", "#ops", "only ops", "@"},
+	} {
+		h.dispatchRequest(WSRequest{
+			Type: "command",
+			Data: []byte(`{"server":"command.example.com","channel":"#chan","command":"` + tc.command + `"}`),
+		})
+
+		res := <-s.broadcast
+		assert.Equal(t, "message", res.Type, tc.command)
+		msg := res.Data.(Message)
+		assert.NotEmpty(t, msg.ID)
+		assert.Equal(t, tc.to, msg.To)
+		assert.Equal(t, tc.content, msg.Content)
+		assert.Equal(t, tc.statusMsg, msg.StatusMsg)
+
+		// It goes through the same path as the messages the client sends,
+		// so the echo of it does not show up twice
+		assert.True(t, s.sent.take("command.example.com", tc.to, tc.content), tc.command)
+	}
+}
@@ -0,0 +1,301 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendQueueStalledReader(t *testing.T) {
+	ws := newWSConn(nil)
+
+	for i := 0; i < 10; i++ {
+		assert.True(t, ws.push(WSResponse{Type: "message", Data: i}))
+	}
+	for i := 0; i < 10*wsQueueSize; i++ {
+		assert.True(t, ws.push(WSResponse{Type: "users", Data: Userlist{Channel: "#chan", Total: i}}))
+	}
+	assert.Equal(t, wsQueueSize, ws.queue.len())
+
+	events := ws.queue.drain()
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, WSResponse{Type: "message", Data: i}, events[i])
+	}
+	assert.Equal(t, 10*wsQueueSize-1, events[len(events)-1].Data.(Userlist).Total)
+
+	for i := 0; i < wsQueueSize-1; i++ {
+		assert.True(t, ws.push(WSResponse{Type: "message", Data: i}))
+	}
+	assert.True(t, ws.push(WSResponse{Type: "users", Data: Userlist{Channel: "#chan"}}))
+	// The only userlist of a channel is never dropped
+	assert.False(t, ws.push(WSResponse{Type: "users", Data: Userlist{Channel: "#other"}}))
+	assert.True(t, ws.push(WSResponse{Type: "users", Data: Userlist{Channel: "#chan", Total: 1}}))
+	assert.False(t, ws.push(WSResponse{Type: "message"}))
+	assert.Equal(t, wsQueueSize, ws.queue.len())
+
+	events = ws.queue.drain()
+	assert.Equal(t, Userlist{Channel: "#chan", Total: 1}, events[len(events)-1].Data)
+	assert.Equal(t, "message", events[len(events)-2].Type)
+}
+
+func TestSendQueueHighWater(t *testing.T) {
+	defer func(timeout time.Duration) {
+		wsHighWaterTimeout = timeout
+	}(wsHighWaterTimeout)
+	wsHighWaterTimeout = 10 * time.Millisecond
+
+	ws := newWSConn(nil)
+	for i := 0; i <= wsHighWater; i++ {
+		assert.True(t, ws.push(WSResponse{Type: "message"}))
+	}
+	time.Sleep(20 * time.Millisecond)
+	assert.False(t, ws.push(WSResponse{Type: "message"}))
+
+	ws.queue.drain()
+	assert.True(t, ws.push(WSResponse{Type: "message"}))
+}
+
+func TestWSPingTimeout(t *testing.T) {
+	s := NewState(user, nil)
+	conns := make(chan *wsConn, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		h := &wsHandler{
+			ws:    newWSConn(conn),
+			state: s,
+			addr:  conn.RemoteAddr(),
+		}
+		h.ws.pingInterval = 20 * time.Millisecond
+		h.ws.pingTimeout = 20 * time.Millisecond
+		s.setWS(h.addr.String(), h.ws)
+		conns <- h.ws
+		h.run()
+	}))
+	defer srv.Close()
+
+	// The stub session keeps its connection open but stops answering pings
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	assert.Nil(t, err)
+	defer client.Close()
+
+	var ponging int32 = 1
+	client.SetPingHandler(func(data string) error {
+		if atomic.LoadInt32(&ponging) == 1 {
+			return client.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+		}
+		return nil
+	})
+	go func() {
+		for {
+			if _, _, err := client.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ws := <-conns
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(t, s.getSessions(), 1)
+
+	atomic.StoreInt32(&ponging, 0)
+
+	select {
+	case _, ok := <-ws.in:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("Session was not closed")
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Len(t, s.getSessions(), 0)
+}
+
+// dialTestWS connects a client to a session that runs until the test server
+// is closed, requests the session receives are read from the returned wsConn
+func dialTestWS(t *testing.T, maxMessageSize int64) (*websocket.Conn, *wsConn, func()) {
+	conns := make(chan *wsConn, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		ws := newWSConn(conn)
+		ws.maxMessageSize = maxMessageSize
+		conns <- ws
+		go ws.send()
+		ws.recv()
+	}))
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	assert.Nil(t, err)
+
+	return client, <-conns, func() {
+		client.Close()
+		srv.Close()
+	}
+}
+
+func TestWSMessageTooBig(t *testing.T) {
+	client, ws, done := dialTestWS(t, 32)
+	defer done()
+
+	assert.Nil(t, client.WriteMessage(websocket.TextMessage, []byte(`{"type":"away","data":{}}`)))
+	req := <-ws.in
+	assert.Equal(t, "away", req.Type)
+
+	assert.Nil(t, client.WriteMessage(websocket.TextMessage,
+		[]byte(`{"type":"message","data":{"content":"`+strings.Repeat("a", 64)+`"}}`)))
+
+	_, ok := <-ws.in
+	assert.False(t, ok)
+
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err := client.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation), err)
+}
+
+func TestWSMalformedRequest(t *testing.T) {
+	client, ws, done := dialTestWS(t, defaultWSMaxMessageSize)
+	defer done()
+
+	assert.Nil(t, client.WriteMessage(websocket.TextMessage, []byte(`{"type":"message","data":`)))
+
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	_, b, err := client.ReadMessage()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"type":"request_error","data":{"type":"message","message":"Malformed request"}}`, string(b))
+
+	// The session stays open
+	assert.Nil(t, client.WriteMessage(websocket.TextMessage, []byte(`{"type":"away","data":{"server":"srv"}}`)))
+	req := <-ws.in
+	assert.Equal(t, "away", req.Type)
+	assert.JSONEq(t, `{"server":"srv"}`, string(req.Data))
+}
+
+func TestParseWSRequest(t *testing.T) {
+	cases := []struct {
+		input string
+		err   string
+	}{
+		{`{"type":"away","data":{"server":"srv"}}`, ""},
+		{`{"type":"fetch_aliases"}`, ""},
+		{`{"type":"fetch_aliases","data":null}`, ""},
+		{`{"type":"away","data":{"server":`, "Malformed request"},
+		{`nope`, "Malformed request"},
+		{`{"type":5}`, "Malformed request"},
+		{`{"data":{}}`, "Missing request type"},
+		{`{"type":"away","data":"srv"}`, "Request data has to be an object"},
+		{`{"type":"away","data":[1]}`, "Request data has to be an object"},
+	}
+
+	for _, tc := range cases {
+		_, err := parseWSRequest([]byte(tc.input))
+		if tc.err == "" {
+			assert.Nil(t, err, tc.input)
+		} else if assert.NotNil(t, err, tc.input) {
+			assert.Equal(t, tc.err, err.Error(), tc.input)
+		}
+	}
+}
+
+func sessionLimitTest(t *testing.T, limits config.Limits, admin bool) (*State, func() *websocket.Conn) {
+	u, err := storage.NewUser(store)
+	assert.Nil(t, err)
+	assert.Nil(t, u.SetAdmin(admin))
+
+	s := NewState(u, New(&config.Config{Limits: limits}))
+	go s.run()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		newWSHandler(conn, s, r).run()
+	}))
+	t.Cleanup(srv.Close)
+
+	dial := func() *websocket.Conn {
+		n := len(s.getSessions())
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+		assert.Nil(t, err)
+		t.Cleanup(func() { conn.Close() })
+
+		// Wait for the session to be added or rejected, this keeps
+		// the order they were opened in
+		time.Sleep(50 * time.Millisecond)
+		if len(s.getSessions()) == n {
+			time.Sleep(50 * time.Millisecond)
+		}
+		return conn
+	}
+	return s, dial
+}
+
+func readCloseError(t *testing.T, conn *websocket.Conn) *websocket.CloseError {
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	for {
+		_, _, err := conn.ReadMessage()
+		if err != nil {
+			closeErr, _ := err.(*websocket.CloseError)
+			return closeErr
+		}
+	}
+}
+
+func TestSessionLimitEvict(t *testing.T) {
+	s, dial := sessionLimitTest(t, config.Limits{MaxSessions: 2, SessionPolicy: "evict"}, false)
+
+	oldest := dial()
+	dial()
+	assert.Len(t, s.getSessions(), 2)
+
+	newest := dial()
+	assert.Len(t, s.getSessions(), 2)
+	assert.Equal(t, &websocket.CloseError{
+		Code: wsCloseSessionLimit,
+		Text: "Closed by a newer session",
+	}, readCloseError(t, oldest))
+
+	newest.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	_, _, err := newest.ReadMessage()
+	assert.True(t, websocket.IsUnexpectedCloseError(err) || strings.Contains(err.Error(), "timeout"), err)
+	assert.Len(t, s.getSessions(), 2)
+}
+
+func TestSessionLimitReject(t *testing.T) {
+	s, dial := sessionLimitTest(t, config.Limits{MaxSessions: 2, SessionPolicy: "reject"}, false)
+
+	dial()
+	dial()
+	rejected := dial()
+	assert.Equal(t, &websocket.CloseError{
+		Code: wsCloseSessionLimit,
+		Text: "Too many sessions",
+	}, readCloseError(t, rejected))
+	assert.Len(t, s.getSessions(), 2)
+}
+
+func TestSessionLimitAdmin(t *testing.T) {
+	s, dial := sessionLimitTest(t, config.Limits{MaxSessions: 1, SessionPolicy: "reject"}, true)
+
+	dial()
+	dial()
+	dial()
+	assert.Len(t, s.getSessions(), 3)
+}
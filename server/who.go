@@ -0,0 +1,108 @@
+package server
+
+import (
+	"github.com/khlieng/dispatch/pkg/irc"
+)
+
+// whoUser is what the WHO replies for a channel said about one of its users
+type whoUser struct {
+	account string
+	// host is user@host
+	host string
+}
+
+// setWhoReplies caches the accounts and hosts from the WHO replies for a
+// channel, replacing what was known about it, channel is casefolded
+func (s *State) setWhoReplies(server, channel string, replies []*irc.WhoReply) {
+	users := make(map[string]whoUser, len(replies))
+	for _, reply := range replies {
+		users[reply.Nick] = whoUser{
+			account: reply.Account,
+			host:    reply.Username + "@" + reply.Host,
+		}
+	}
+
+	s.ircLock.Lock()
+	channels, ok := s.who[server]
+	if !ok {
+		channels = map[string]map[string]whoUser{}
+		s.who[server] = channels
+	}
+	channels[channel] = users
+	s.ircLock.Unlock()
+}
+
+// whoInfo returns the cached accounts and hosts of the users in a userlist,
+// keyed by nick, they are nil if none of the users have one
+func (s *State) whoInfo(server, channel string, users []string) (accounts, hosts map[string]string) {
+	s.ircLock.Lock()
+	defer s.ircLock.Unlock()
+
+	cached := s.who[server][channel]
+	if len(cached) == 0 {
+		return nil, nil
+	}
+
+	for _, user := range users {
+		nick := user[len(userPrefix(user)):]
+		info, ok := cached[nick]
+		if !ok {
+			continue
+		}
+
+		if hosts == nil {
+			hosts = map[string]string{}
+		}
+		hosts[nick] = info.host
+
+		if info.account != "" {
+			if accounts == nil {
+				accounts = map[string]string{}
+			}
+			accounts[nick] = info.account
+		}
+	}
+	return accounts, hosts
+}
+
+// renameWhoUser moves the cached WHO data of a user that changed nick
+func (s *State) renameWhoUser(server, old, new string) {
+	s.ircLock.Lock()
+	for _, users := range s.who[server] {
+		if info, ok := users[old]; ok {
+			delete(users, old)
+			users[new] = info
+		}
+	}
+	s.ircLock.Unlock()
+}
+
+// deleteWhoUser forgets the cached WHO data of a user that left channel,
+// an empty channel means every channel, like after a QUIT
+func (s *State) deleteWhoUser(server, channel, nick string) {
+	s.ircLock.Lock()
+	if channel != "" {
+		delete(s.who[server][channel], nick)
+	} else {
+		for _, users := range s.who[server] {
+			delete(users, nick)
+		}
+	}
+	s.ircLock.Unlock()
+}
+
+// deleteWhoChannel forgets the cached WHO data of a channel the user left
+func (s *State) deleteWhoChannel(server, channel string) {
+	s.ircLock.Lock()
+	delete(s.who[server], channel)
+	s.ircLock.Unlock()
+}
+
+// fillUserlist adds what is known about the users in list besides their
+// nicks, the away status, WHO data, metadata and avatars
+func (s *State) fillUserlist(i *irc.Client, list *Userlist) {
+	list.Away = awayUsers(i, list.Channel, list.Users)
+	list.Accounts, list.Hosts = s.whoInfo(list.Server, i.Casefold(list.Channel), list.Users)
+	list.Metadata = s.userMetadata(list.Server, list.Users)
+	list.Avatars = s.userlistAvatars(list.Users, list.Accounts, list.Metadata)
+}
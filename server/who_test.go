@@ -0,0 +1,49 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhoInfo(t *testing.T) {
+	s := NewState(user, nil)
+	s.setWhoReplies("srv", "#chan", []*irc.WhoReply{
+		{Nick: "op", Username: "~op", Host: "op.host", Account: "opacc"},
+		{Nick: "user", Username: "user", Host: "user.host"},
+		{Nick: "gone", Username: "gone", Host: "gone.host", Account: "goneacc"},
+	})
+	s.setWhoReplies("srv", "#other", []*irc.WhoReply{
+		{Nick: "user", Username: "user", Host: "user.host"},
+	})
+
+	accounts, hosts := s.whoInfo("srv", "#chan", []string{"@op", "user", "quiet"})
+	assert.Equal(t, map[string]string{"op": "opacc"}, accounts)
+	assert.Equal(t, map[string]string{
+		"op":   "~op@op.host",
+		"user": "user@user.host",
+	}, hosts)
+
+	accounts, hosts = s.whoInfo("srv", "#none", []string{"op"})
+	assert.Nil(t, accounts)
+	assert.Nil(t, hosts)
+
+	s.renameWhoUser("srv", "op", "newop")
+	accounts, _ = s.whoInfo("srv", "#chan", []string{"op", "newop"})
+	assert.Equal(t, map[string]string{"newop": "opacc"}, accounts)
+
+	s.deleteWhoUser("srv", "#chan", "newop")
+	accounts, _ = s.whoInfo("srv", "#chan", []string{"newop"})
+	assert.Nil(t, accounts)
+
+	s.deleteWhoUser("srv", "", "user")
+	_, hosts = s.whoInfo("srv", "#chan", []string{"user"})
+	assert.Nil(t, hosts)
+	_, hosts = s.whoInfo("srv", "#other", []string{"user"})
+	assert.Nil(t, hosts)
+
+	s.deleteWhoChannel("srv", "#chan")
+	_, hosts = s.whoInfo("srv", "#chan", []string{"gone"})
+	assert.Nil(t, hosts)
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	DefaultMessageStore          = "bolt"
+	DefaultMessageSearchProvider = "bleve"
+)
+
+var (
+	messageStores          = map[string]MessageStoreCreator{}
+	messageSearchProviders = map[string]MessageSearchProviderCreator{
+		"none": func(*User) (MessageSearchProvider, error) {
+			return noSearch{}, nil
+		},
+	}
+	backendLock sync.Mutex
+)
+
+// RegisterMessageStore makes a message store backend selectable by name
+func RegisterMessageStore(name string, create MessageStoreCreator) {
+	backendLock.Lock()
+	messageStores[name] = create
+	backendLock.Unlock()
+}
+
+// RegisterMessageSearchProvider makes a search backend selectable by name
+func RegisterMessageSearchProvider(name string, create MessageSearchProviderCreator) {
+	backendLock.Lock()
+	messageSearchProviders[name] = create
+	backendLock.Unlock()
+}
+
+// UseMessageStore sets GetMessageStore to the backend registered as name,
+// an empty name selects DefaultMessageStore
+func UseMessageStore(name string) error {
+	if name == "" {
+		name = DefaultMessageStore
+	}
+
+	backendLock.Lock()
+	defer backendLock.Unlock()
+
+	create, ok := messageStores[name]
+	if !ok {
+		var names []string
+		for name := range messageStores {
+			names = append(names, name)
+		}
+		return unknownBackend("message store", name, names)
+	}
+	GetMessageStore = create
+	return nil
+}
+
+// UseMessageSearchProvider sets GetMessageSearchProvider to the backend
+// registered as name, an empty name selects DefaultMessageSearchProvider
+func UseMessageSearchProvider(name string) error {
+	if name == "" {
+		name = DefaultMessageSearchProvider
+	}
+
+	backendLock.Lock()
+	defer backendLock.Unlock()
+
+	create, ok := messageSearchProviders[name]
+	if !ok {
+		var names []string
+		for name := range messageSearchProviders {
+			names = append(names, name)
+		}
+		return unknownBackend("search provider", name, names)
+	}
+	GetMessageSearchProvider = create
+	return nil
+}
+
+func unknownBackend(kind, name string, available []string) error {
+	sort.Strings(available)
+	return fmt.Errorf("Unknown %s %q, available: %s", kind, name, strings.Join(available, ", "))
+}
+
+// noSearch is the "none" search provider, nothing gets indexed
+type noSearch struct{}
+
+func (noSearch) SearchMessages(server, channel, q string, offset, limit int) ([]string, uint64, error) {
+	return nil, 0, nil
+}
+
+func (noSearch) Index(id string, message *Message) error {
+	return nil
+}
+
+func (noSearch) Close() {}
@@ -0,0 +1,65 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/khlieng/dispatch/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSearch struct {
+	indexed []string
+}
+
+func (f *fakeSearch) SearchMessages(server, channel, q string, offset, limit int) ([]string, uint64, error) {
+	return f.indexed, uint64(len(f.indexed)), nil
+}
+
+func (f *fakeSearch) Index(id string, message *storage.Message) error {
+	f.indexed = append(f.indexed, id)
+	return nil
+}
+
+func (f *fakeSearch) Close() {}
+
+func TestSelectBackend(t *testing.T) {
+	defer func(store storage.MessageStoreCreator, search storage.MessageSearchProviderCreator) {
+		storage.GetMessageStore = store
+		storage.GetMessageSearchProvider = search
+	}(storage.GetMessageStore, storage.GetMessageSearchProvider)
+
+	fake := &fakeSearch{}
+	storage.RegisterMessageSearchProvider("fake", func(*storage.User) (storage.MessageSearchProvider, error) {
+		return fake, nil
+	})
+
+	assert.Nil(t, storage.UseMessageSearchProvider("fake"))
+	search, err := storage.GetMessageSearchProvider(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, fake, search)
+
+	assert.Nil(t, storage.UseMessageSearchProvider("none"))
+	search, err = storage.GetMessageSearchProvider(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, search.Index("1", &storage.Message{Content: "hi"}))
+	ids, total, err := search.SearchMessages("srv", "#chan", "hi", 0, 10)
+	assert.Nil(t, err)
+	assert.Empty(t, ids)
+	assert.Zero(t, total)
+
+	err = storage.UseMessageSearchProvider("sqlite")
+	assert.EqualError(t, err, `Unknown search provider "sqlite", available: fake, none`)
+
+	storage.RegisterMessageStore("fake", func(*storage.User) (storage.MessageStore, error) {
+		return nil, storage.ErrNotFound
+	})
+	assert.Nil(t, storage.UseMessageStore("fake"))
+	_, err = storage.GetMessageStore(nil)
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	err = storage.UseMessageStore("postgres")
+	assert.EqualError(t, err, `Unknown message store "postgres", available: fake`)
+
+	err = storage.UseMessageStore("")
+	assert.EqualError(t, err, `Unknown message store "bolt", available: fake`)
+}
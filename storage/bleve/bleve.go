@@ -1,12 +1,18 @@
 package bleve
 
 import (
+	"strings"
+
 	"github.com/blevesearch/bleve"
 	"github.com/blevesearch/bleve/analysis/analyzer/keyword"
 
 	"github.com/khlieng/dispatch/storage"
 )
 
+// searchOrder sorts search results newest first, message IDs are ordered
+// by time, which keeps pages stable as long as no messages are added
+var searchOrder = []string{"-_id"}
+
 // Bleve implements storage.MessageSearchProvider
 type Bleve struct {
 	index bleve.Index
@@ -27,11 +33,17 @@ func New(path string) (*Bleve, error) {
 		contentMapping.IncludeTermVectors = false
 		contentMapping.IncludeInAll = false
 
+		mentionMapping := bleve.NewBooleanFieldMapping()
+		mentionMapping.Store = false
+		mentionMapping.IncludeInAll = false
+
 		messageMapping := bleve.NewDocumentMapping()
 		messageMapping.StructTagKey = "bleve"
 		messageMapping.AddFieldMappingsAt("server", keywordMapping)
 		messageMapping.AddFieldMappingsAt("to", keywordMapping)
+		messageMapping.AddFieldMappingsAt("account", keywordMapping)
 		messageMapping.AddFieldMappingsAt("content", contentMapping)
+		messageMapping.AddFieldMappingsAt("mention", mentionMapping)
 
 		mapping := bleve.NewIndexMapping()
 		mapping.AddDocumentMapping("message", messageMapping)
@@ -48,22 +60,40 @@ func (b *Bleve) Index(id string, message *storage.Message) error {
 	return b.index.Index(id, message)
 }
 
-func (b *Bleve) SearchMessages(server, channel, q string) ([]string, error) {
+// SearchMessages searches the content of messages, an account:name term in
+// q restricts the results to messages sent from that account
+func (b *Bleve) SearchMessages(server, channel, q string, offset, limit int) ([]string, uint64, error) {
 	serverQuery := bleve.NewMatchQuery(server)
 	serverQuery.SetField("server")
 	channelQuery := bleve.NewMatchQuery(channel)
 	channelQuery.SetField("to")
-	contentQuery := bleve.NewMatchQuery(q)
-	contentQuery.SetField("content")
-	contentQuery.SetFuzziness(2)
 
 	query := bleve.NewBooleanQuery()
-	query.AddMust(serverQuery, channelQuery, contentQuery)
+	query.AddMust(serverQuery, channelQuery)
+
+	terms := []string{}
+	for _, term := range strings.Fields(q) {
+		if strings.HasPrefix(term, "account:") && len(term) > 8 {
+			accountQuery := bleve.NewTermQuery(term[8:])
+			accountQuery.SetField("account")
+			query.AddMust(accountQuery)
+		} else {
+			terms = append(terms, term)
+		}
+	}
+
+	if len(terms) > 0 {
+		contentQuery := bleve.NewMatchQuery(strings.Join(terms, " "))
+		contentQuery.SetField("content")
+		contentQuery.SetFuzziness(2)
+		query.AddMust(contentQuery)
+	}
 
-	search := bleve.NewSearchRequest(query)
+	search := bleve.NewSearchRequestOptions(query, limit, offset, false)
+	search.SortBy(searchOrder)
 	searchResults, err := b.index.Search(search)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	ids := make([]string, len(searchResults.Hits))
@@ -71,9 +101,44 @@ func (b *Bleve) SearchMessages(server, channel, q string) ([]string, error) {
 		ids[i] = hit.ID
 	}
 
-	return ids, nil
+	return ids, searchResults.Total, nil
+}
+
+// SearchMentions returns the newest messages in channel that mentioned
+// the user
+func (b *Bleve) SearchMentions(server, channel string, limit int) ([]string, error) {
+	return searchMentions(b.index, server, channel, limit)
+}
+
+// Highlight finds the words in content that match q the same way
+// SearchMessages does, including fuzzy matches
+func (b *Bleve) Highlight(content, q string) storage.Snippet {
+	return snippet(b.index.Mapping().AnalyzerNamed("en"), content, q, 2)
 }
 
 func (b *Bleve) Close() {
 	b.index.Close()
 }
+
+func searchMentions(index bleve.Index, server, channel string, limit int) ([]string, error) {
+	serverQuery := bleve.NewTermQuery(server)
+	serverQuery.SetField("server")
+	channelQuery := bleve.NewTermQuery(channel)
+	channelQuery.SetField("to")
+	mentionQuery := bleve.NewBoolFieldQuery(true)
+	mentionQuery.SetField("mention")
+
+	query := bleve.NewConjunctionQuery(serverQuery, channelQuery, mentionQuery)
+	search := bleve.NewSearchRequestOptions(query, limit, 0, false)
+	search.SortBy(searchOrder)
+	searchResults, err := index.Search(search)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(searchResults.Hits))
+	for i, hit := range searchResults.Hits {
+		ids[i] = hit.ID
+	}
+	return ids, nil
+}
@@ -0,0 +1,159 @@
+package bleve
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/blevesearch/bleve"
+	"github.com/blevesearch/bleve/analysis"
+	"github.com/blevesearch/bleve/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/search/query"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+// Hashed implements storage.MessageSearchProvider for encrypted message
+// stores, content terms are replaced by a keyed hash before being indexed
+// so the index does not leak the plaintext. Fuzzy matching is not supported.
+type Hashed struct {
+	index    bleve.Index
+	analyzer *analysis.Analyzer
+	key      []byte
+}
+
+type hashedMessage struct {
+	Server  string   `bleve:"server"`
+	To      string   `bleve:"to"`
+	Account string   `bleve:"account"`
+	Content []string `bleve:"content"`
+	Mention bool     `bleve:"mention"`
+}
+
+func (hashedMessage) Type() string {
+	return "message"
+}
+
+func NewHashed(path string, key []byte) (*Hashed, error) {
+	index, err := bleve.Open(path)
+	if err == bleve.ErrorIndexPathDoesNotExist {
+		keywordMapping := bleve.NewTextFieldMapping()
+		keywordMapping.Analyzer = keyword.Name
+		keywordMapping.Store = false
+		keywordMapping.IncludeTermVectors = false
+		keywordMapping.IncludeInAll = false
+
+		messageMapping := bleve.NewDocumentMapping()
+		messageMapping.StructTagKey = "bleve"
+		messageMapping.AddFieldMappingsAt("server", keywordMapping)
+		messageMapping.AddFieldMappingsAt("to", keywordMapping)
+		messageMapping.AddFieldMappingsAt("account", keywordMapping)
+		messageMapping.AddFieldMappingsAt("content", keywordMapping)
+		messageMapping.AddFieldMappingsAt("mention", bleve.NewBooleanFieldMapping())
+
+		mapping := bleve.NewIndexMapping()
+		mapping.AddDocumentMapping("message", messageMapping)
+
+		index, err = bleve.New(path, mapping)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	analyzer := index.Mapping().AnalyzerNamed("en")
+	return &Hashed{
+		index:    index,
+		analyzer: analyzer,
+		key:      key,
+	}, nil
+}
+
+func (h *Hashed) hash(term string) string {
+	mac := hmac.New(sha256.New, h.key)
+	mac.Write([]byte(term))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (h *Hashed) terms(text string) []string {
+	tokens := h.analyzer.Analyze([]byte(text))
+	terms := make([]string, 0, len(tokens))
+	seen := map[string]bool{}
+
+	for _, token := range tokens {
+		term := h.hash(string(token.Term))
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+func (h *Hashed) Index(id string, message *storage.Message) error {
+	return h.index.Index(id, hashedMessage{
+		Server:  message.Server,
+		To:      message.To,
+		Account: h.hash(message.Account),
+		Content: h.terms(message.Content),
+		Mention: message.Mention,
+	})
+}
+
+// SearchMessages returns messages containing all the terms in q, an
+// account:name term restricts the results to messages sent from that account
+func (h *Hashed) SearchMessages(server, channel, q string, offset, limit int) ([]string, uint64, error) {
+	serverQuery := bleve.NewTermQuery(server)
+	serverQuery.SetField("server")
+	channelQuery := bleve.NewTermQuery(channel)
+	channelQuery.SetField("to")
+
+	must := []query.Query{serverQuery, channelQuery}
+	text := []string{}
+
+	for _, term := range strings.Fields(q) {
+		if strings.HasPrefix(term, "account:") && len(term) > 8 {
+			accountQuery := bleve.NewTermQuery(h.hash(term[8:]))
+			accountQuery.SetField("account")
+			must = append(must, accountQuery)
+		} else {
+			text = append(text, term)
+		}
+	}
+
+	for _, term := range h.terms(strings.Join(text, " ")) {
+		contentQuery := bleve.NewTermQuery(term)
+		contentQuery.SetField("content")
+		must = append(must, contentQuery)
+	}
+
+	search := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(must...), limit, offset, false)
+	search.SortBy(searchOrder)
+	searchResults, err := h.index.Search(search)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]string, len(searchResults.Hits))
+	for i, hit := range searchResults.Hits {
+		ids[i] = hit.ID
+	}
+
+	return ids, searchResults.Total, nil
+}
+
+// SearchMentions returns the newest messages in channel that mentioned
+// the user
+func (h *Hashed) SearchMentions(server, channel string, limit int) ([]string, error) {
+	return searchMentions(h.index, server, channel, limit)
+}
+
+// Highlight finds the words in content that match q, it is only called
+// with decrypted content so the terms can be compared directly
+func (h *Hashed) Highlight(content, q string) storage.Snippet {
+	return snippet(h.analyzer, content, q, 0)
+}
+
+func (h *Hashed) Close() {
+	h.index.Close()
+}
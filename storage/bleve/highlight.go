@@ -0,0 +1,87 @@
+package bleve
+
+import (
+	"strings"
+
+	"github.com/blevesearch/bleve/analysis"
+	"github.com/blevesearch/bleve/search"
+	"github.com/blevesearch/bleve/search/highlight"
+	"github.com/blevesearch/bleve/search/highlight/fragmenter/simple"
+	highlighter "github.com/blevesearch/bleve/search/highlight/highlighter/simple"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+// snippetSize is the max number of characters in a snippet
+const snippetSize = 200
+
+// contentTerms returns the terms of q that are matched against the
+// content of messages
+func contentTerms(q string) []string {
+	terms := []string{}
+	for _, term := range strings.Fields(q) {
+		if !strings.HasPrefix(term, "account:") || len(term) <= 8 {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// snippet finds the tokens in content that match the analyzed terms of q,
+// terms that are at most fuzziness edits away from each other match. The
+// snippet is the fragment bleve scores highest, with the highlights that
+// fall inside it
+func snippet(analyzer *analysis.Analyzer, content, q string, fuzziness int) storage.Snippet {
+	terms := analyzer.Analyze([]byte(strings.Join(contentTerms(q), " ")))
+	locations := highlight.TermLocations{}
+	termLocations := search.TermLocationMap{}
+
+	for _, token := range analyzer.Analyze([]byte(content)) {
+		for _, term := range terms {
+			distance, exceeded := search.LevenshteinDistanceMax(string(token.Term), string(term.Term), fuzziness)
+			if !exceeded && distance <= fuzziness {
+				locations = append(locations, &highlight.TermLocation{
+					Term:  string(term.Term),
+					Pos:   token.Position,
+					Start: token.Start,
+					End:   token.End,
+				})
+				termLocations.AddLocation(string(term.Term), &search.Location{
+					Pos:   uint64(token.Position),
+					Start: uint64(token.Start),
+					End:   uint64(token.End),
+				})
+				break
+			}
+		}
+	}
+
+	fragments := simple.NewFragmenter(snippetSize).Fragment([]byte(content), locations)
+	if len(fragments) == 0 {
+		return storage.Snippet{}
+	}
+
+	scorer := highlighter.NewFragmentScorer(termLocations)
+	best := fragments[0]
+	for _, fragment := range fragments {
+		scorer.Score(fragment)
+		if fragment.Score > best.Score {
+			best = fragment
+		}
+	}
+
+	result := storage.Snippet{
+		Text:  content[best.Start:best.End],
+		Start: best.Start,
+	}
+	for _, location := range locations {
+		if location.Start >= best.Start && location.End <= best.End {
+			result.Highlights = append(result.Highlights, storage.Highlight{
+				Start: location.Start - best.Start,
+				End:   location.End - best.Start,
+			})
+		}
+	}
+
+	return result
+}
@@ -3,7 +3,12 @@ package boltdb
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
+	"log"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	bolt "go.etcd.io/bbolt"
 
@@ -16,18 +21,33 @@ var (
 	bucketServers  = []byte("Servers")
 	bucketChannels = []byte("Channels")
 	bucketOpenDMs  = []byte("OpenDMs")
+	bucketAliases  = []byte("Aliases")
 	bucketMessages = []byte("Messages")
 	bucketSessions = []byte("Sessions")
+	bucketTopics   = []byte("Topics")
+	bucketHistory  = []byte("CommandHistory")
 )
 
+// ErrLocked is returned by New when another process has the database open
+var ErrLocked = errors.New("The database is in use by another process, is dispatch running?")
+
+// openTimeout is how long New waits for other processes to close the database
+const openTimeout = time.Second
+
 // BoltStore implements storage.Store, storage.MessageStore and storage.SessionStore
 type BoltStore struct {
 	db *bolt.DB
+	// lock is held for writing while Compact swaps out db
+	lock sync.RWMutex
+	// compactLock makes compactions run one at a time
+	compactLock sync.Mutex
 }
 
 func New(path string) (*BoltStore, error) {
-	db, err := bolt.Open(path, 0600, nil)
-	if err != nil {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: openTimeout})
+	if err == bolt.ErrTimeout {
+		return nil, ErrLocked
+	} else if err != nil {
 		return nil, err
 	}
 
@@ -36,31 +56,54 @@ func New(path string) (*BoltStore, error) {
 		tx.CreateBucketIfNotExists(bucketServers)
 		tx.CreateBucketIfNotExists(bucketChannels)
 		tx.CreateBucketIfNotExists(bucketOpenDMs)
+		tx.CreateBucketIfNotExists(bucketAliases)
 		tx.CreateBucketIfNotExists(bucketMessages)
 		tx.CreateBucketIfNotExists(bucketSessions)
+		tx.CreateBucketIfNotExists(bucketTopics)
+		tx.CreateBucketIfNotExists(bucketHistory)
 		return nil
 	})
 
 	return &BoltStore{
-		db,
+		db: db,
 	}, nil
 }
 
 func (s *BoltStore) Close() {
+	s.lock.Lock()
 	s.db.Close()
+	s.lock.Unlock()
+}
+
+func (s *BoltStore) view(fn func(*bolt.Tx) error) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.db.View(fn)
+}
+
+func (s *BoltStore) update(fn func(*bolt.Tx) error) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.db.Update(fn)
+}
+
+func (s *BoltStore) batch(fn func(*bolt.Tx) error) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.db.Batch(fn)
 }
 
 func (s *BoltStore) GetUsers() ([]*storage.User, error) {
 	var users []*storage.User
 
-	s.db.View(func(tx *bolt.Tx) error {
+	s.view(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketUsers)
 
 		return b.ForEach(func(k, v []byte) error {
 			user := storage.User{
 				IDBytes: make([]byte, 8),
 			}
-			user.Unmarshal(v)
+			unmarshal(&user, v)
 			copy(user.IDBytes, k)
 
 			users = append(users, &user)
@@ -73,14 +116,18 @@ func (s *BoltStore) GetUsers() ([]*storage.User, error) {
 }
 
 func (s *BoltStore) SaveUser(user *storage.User) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketUsers)
 
+		// The username never changes once set, it is read without holding
+		// any lock while the user is saved
 		if user.ID == 0 {
 			user.ID, _ = b.NextSequence()
 			user.IDBytes = idToBytes(user.ID)
 		}
-		user.Username = strconv.FormatUint(user.ID, 10)
+		if user.Username == "" {
+			user.Username = strconv.FormatUint(user.ID, 10)
+		}
 
 		data, err := user.Marshal(nil)
 		if err != nil {
@@ -92,7 +139,7 @@ func (s *BoltStore) SaveUser(user *storage.User) error {
 }
 
 func (s *BoltStore) DeleteUser(user *storage.User) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		err := tx.Bucket(bucketUsers).Delete(user.IDBytes)
 		if err != nil {
 			return err
@@ -102,6 +149,8 @@ func (s *BoltStore) DeleteUser(user *storage.User) error {
 			tx.Bucket(bucketServers),
 			tx.Bucket(bucketChannels),
 			tx.Bucket(bucketOpenDMs),
+			tx.Bucket(bucketAliases),
+			tx.Bucket(bucketHistory),
 		)
 	})
 }
@@ -109,7 +158,7 @@ func (s *BoltStore) DeleteUser(user *storage.User) error {
 func (s *BoltStore) GetServer(user *storage.User, address string) (*storage.Server, error) {
 	var server *storage.Server
 
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.view(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketServers)
 		id := serverID(user, address)
 
@@ -118,7 +167,7 @@ func (s *BoltStore) GetServer(user *storage.User, address string) (*storage.Serv
 			return storage.ErrNotFound
 		} else {
 			server = &storage.Server{}
-			server.Unmarshal(v)
+			unmarshal(server, v)
 			return nil
 		}
 	})
@@ -129,12 +178,12 @@ func (s *BoltStore) GetServer(user *storage.User, address string) (*storage.Serv
 func (s *BoltStore) GetServers(user *storage.User) ([]*storage.Server, error) {
 	var servers []*storage.Server
 
-	s.db.View(func(tx *bolt.Tx) error {
+	s.view(func(tx *bolt.Tx) error {
 		c := tx.Bucket(bucketServers).Cursor()
 
 		for k, v := c.Seek(user.IDBytes); bytes.HasPrefix(k, user.IDBytes); k, v = c.Next() {
 			server := storage.Server{}
-			server.Unmarshal(v)
+			unmarshal(&server, v)
 			servers = append(servers, &server)
 		}
 
@@ -145,7 +194,7 @@ func (s *BoltStore) GetServers(user *storage.User) ([]*storage.Server, error) {
 }
 
 func (s *BoltStore) SaveServer(user *storage.User, server *storage.Server) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketServers)
 		data, _ := server.Marshal(nil)
 
@@ -154,7 +203,7 @@ func (s *BoltStore) SaveServer(user *storage.User, server *storage.Server) error
 }
 
 func (s *BoltStore) RemoveServer(user *storage.User, address string) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		serverID := serverID(user, address)
 		err := tx.Bucket(bucketServers).Delete(serverID)
 		if err != nil {
@@ -164,19 +213,20 @@ func (s *BoltStore) RemoveServer(user *storage.User, address string) error {
 		return deletePrefix(serverID,
 			tx.Bucket(bucketChannels),
 			tx.Bucket(bucketOpenDMs),
+			tx.Bucket(bucketHistory),
 		)
 	})
 }
 
 func (s *BoltStore) SetNick(user *storage.User, nick, address string) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketServers)
 		id := serverID(user, address)
 
 		server := storage.Server{}
 		v := b.Get(id)
 		if v != nil {
-			server.Unmarshal(v)
+			unmarshal(&server, v)
 			server.Nick = nick
 
 			data, _ := server.Marshal(nil)
@@ -188,14 +238,14 @@ func (s *BoltStore) SetNick(user *storage.User, nick, address string) error {
 }
 
 func (s *BoltStore) SetServerName(user *storage.User, name, address string) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketServers)
 		id := serverID(user, address)
 
 		server := storage.Server{}
 		v := b.Get(id)
 		if v != nil {
-			server.Unmarshal(v)
+			unmarshal(&server, v)
 			server.Name = name
 
 			data, _ := server.Marshal(nil)
@@ -209,12 +259,12 @@ func (s *BoltStore) SetServerName(user *storage.User, name, address string) erro
 func (s *BoltStore) GetChannels(user *storage.User) ([]*storage.Channel, error) {
 	var channels []*storage.Channel
 
-	s.db.View(func(tx *bolt.Tx) error {
+	s.view(func(tx *bolt.Tx) error {
 		c := tx.Bucket(bucketChannels).Cursor()
 
 		for k, v := c.Seek(user.IDBytes); bytes.HasPrefix(k, user.IDBytes); k, v = c.Next() {
 			channel := storage.Channel{}
-			channel.Unmarshal(v)
+			unmarshal(&channel, v)
 			channels = append(channels, &channel)
 		}
 
@@ -225,16 +275,134 @@ func (s *BoltStore) GetChannels(user *storage.User) ([]*storage.Channel, error)
 }
 
 func (s *BoltStore) AddChannel(user *storage.User, channel *storage.Channel) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketChannels)
+		id := channelID(user, channel.Server, channel.Name)
+
+		// Rejoining a channel keeps its place in the sidebar
+		ch := *channel
+		if v := b.Get(id); v != nil {
+			existing := storage.Channel{}
+			unmarshal(&existing, v)
+			ch.Order = existing.Order
+			ch.Group = existing.Group
+			ch.Color = existing.Color
+			ch.Label = existing.Label
+			ch.Lazy = existing.Lazy
+		}
+
+		data, _ := ch.Marshal(nil)
+		return b.Put(id, data)
+	})
+}
+
+func (s *BoltStore) SetOrder(user *storage.User, placements []storage.Placement) error {
+	return s.batch(func(tx *bolt.Tx) error {
+		for _, p := range placements {
+			if p.Channel == "" {
+				b := tx.Bucket(bucketServers)
+				id := serverID(user, p.Server)
+
+				v := b.Get(id)
+				if v == nil {
+					continue
+				}
+
+				server := storage.Server{}
+				unmarshal(&server, v)
+				server.Order = p.Order
+				server.Group = p.Group
+
+				data, _ := server.Marshal(nil)
+				err := b.Put(id, data)
+				if err != nil {
+					return err
+				}
+			} else {
+				b := tx.Bucket(bucketChannels)
+				id := channelID(user, p.Server, p.Channel)
+
+				v := b.Get(id)
+				if v == nil {
+					continue
+				}
+
+				channel := storage.Channel{}
+				unmarshal(&channel, v)
+				channel.Order = p.Order
+				channel.Group = p.Group
+
+				data, _ := channel.Marshal(nil)
+				err := b.Put(id, data)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *BoltStore) SetAppearance(user *storage.User, appearance storage.Appearance) error {
+	return s.batch(func(tx *bolt.Tx) error {
+		if appearance.Channel == "" {
+			b := tx.Bucket(bucketServers)
+			id := serverID(user, appearance.Server)
+
+			v := b.Get(id)
+			if v == nil {
+				return storage.ErrNotFound
+			}
+
+			server := storage.Server{}
+			unmarshal(&server, v)
+			server.Color = appearance.Color
+			server.Label = appearance.Label
+
+			data, _ := server.Marshal(nil)
+			return b.Put(id, data)
+		}
+
+		b := tx.Bucket(bucketChannels)
+		id := channelID(user, appearance.Server, appearance.Channel)
+
+		v := b.Get(id)
+		if v == nil {
+			return storage.ErrNotFound
+		}
+
+		channel := storage.Channel{}
+		unmarshal(&channel, v)
+		channel.Color = appearance.Color
+		channel.Label = appearance.Label
+
 		data, _ := channel.Marshal(nil)
+		return b.Put(id, data)
+	})
+}
+
+func (s *BoltStore) SetChannelLazy(user *storage.User, server, channel string, lazy bool) error {
+	return s.batch(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketChannels)
+		id := channelID(user, server, channel)
 
-		return b.Put(channelID(user, channel.Server, channel.Name), data)
+		v := b.Get(id)
+		if v == nil {
+			return storage.ErrNotFound
+		}
+
+		ch := storage.Channel{}
+		unmarshal(&ch, v)
+		ch.Lazy = lazy
+
+		data, _ := ch.Marshal(nil)
+		return b.Put(id, data)
 	})
 }
 
 func (s *BoltStore) RemoveChannel(user *storage.User, server, channel string) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketChannels)
 		id := channelID(user, server, channel)
 
@@ -245,7 +413,7 @@ func (s *BoltStore) RemoveChannel(user *storage.User, server, channel string) er
 func (s *BoltStore) GetOpenDMs(user *storage.User) ([]storage.Tab, error) {
 	var openDMs []storage.Tab
 
-	s.db.View(func(tx *bolt.Tx) error {
+	s.view(func(tx *bolt.Tx) error {
 		c := tx.Bucket(bucketOpenDMs).Cursor()
 
 		for k, _ := c.Seek(user.IDBytes); bytes.HasPrefix(k, user.IDBytes); k, _ = c.Next() {
@@ -263,7 +431,7 @@ func (s *BoltStore) GetOpenDMs(user *storage.User) ([]storage.Tab, error) {
 }
 
 func (s *BoltStore) AddOpenDM(user *storage.User, server, nick string) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketOpenDMs)
 
 		return b.Put(channelID(user, server, nick), nil)
@@ -271,13 +439,75 @@ func (s *BoltStore) AddOpenDM(user *storage.User, server, nick string) error {
 }
 
 func (s *BoltStore) RemoveOpenDM(user *storage.User, server, nick string) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketOpenDMs)
 
 		return b.Delete(channelID(user, server, nick))
 	})
 }
 
+func (s *BoltStore) GetAliases(user *storage.User) (map[string]string, error) {
+	aliases := map[string]string{}
+
+	s.view(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketAliases).Cursor()
+
+		for k, v := c.Seek(user.IDBytes); bytes.HasPrefix(k, user.IDBytes); k, v = c.Next() {
+			aliases[string(k[8:])] = string(v)
+		}
+
+		return nil
+	})
+
+	return aliases, nil
+}
+
+func (s *BoltStore) SetAlias(user *storage.User, name, expansion string) error {
+	return s.batch(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketAliases)
+
+		return b.Put(aliasID(user, name), []byte(expansion))
+	})
+}
+
+func (s *BoltStore) RemoveAlias(user *storage.User, name string) error {
+	return s.batch(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketAliases)
+
+		return b.Delete(aliasID(user, name))
+	})
+}
+
+// historySeparator separates the lines of a command history, it can't
+// be part of an IRC message
+const historySeparator = "\x00"
+
+func (s *BoltStore) GetCommandHistory(user *storage.User, server, channel string) ([]string, error) {
+	var history []string
+
+	err := s.view(func(tx *bolt.Tx) error {
+		v := tx.Bucket(bucketHistory).Get(channelID(user, server, channel))
+		if len(v) > 0 {
+			history = strings.Split(string(v), historySeparator)
+		}
+		return nil
+	})
+
+	return history, err
+}
+
+func (s *BoltStore) SetCommandHistory(user *storage.User, server, channel string, history []string) error {
+	return s.batch(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketHistory)
+		id := channelID(user, server, channel)
+
+		if len(history) == 0 {
+			return b.Delete(id)
+		}
+		return b.Put(id, []byte(strings.Join(history, historySeparator)))
+	})
+}
+
 func (s *BoltStore) logMessage(tx *bolt.Tx, message *storage.Message) error {
 	b, err := tx.Bucket(bucketMessages).CreateBucketIfNotExists([]byte(message.Server + ":" + message.To))
 	if err != nil {
@@ -293,13 +523,13 @@ func (s *BoltStore) logMessage(tx *bolt.Tx, message *storage.Message) error {
 }
 
 func (s *BoltStore) LogMessage(message *storage.Message) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		return s.logMessage(tx, message)
 	})
 }
 
 func (s *BoltStore) LogMessages(messages []*storage.Message) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		for _, message := range messages {
 			err := s.logMessage(tx, message)
 			if err != nil {
@@ -315,7 +545,7 @@ func (s *BoltStore) GetMessages(server, channel string, count int, fromID string
 	messages := make([]storage.Message, count)
 	hasMore := false
 
-	s.db.View(func(tx *bolt.Tx) error {
+	s.view(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketMessages).Bucket([]byte(server + ":" + channel))
 		if b == nil {
 			return nil
@@ -328,12 +558,12 @@ func (s *BoltStore) GetMessages(server, channel string, count int, fromID string
 
 			for k, v := c.Prev(); count > 0 && k != nil; k, v = c.Prev() {
 				count--
-				messages[count].Unmarshal(v)
+				unmarshal(&messages[count], v)
 			}
 		} else {
 			for k, v := c.Last(); count > 0 && k != nil; k, v = c.Prev() {
 				count--
-				messages[count].Unmarshal(v)
+				unmarshal(&messages[count], v)
 			}
 		}
 
@@ -356,21 +586,272 @@ func (s *BoltStore) GetMessages(server, channel string, count int, fromID string
 func (s *BoltStore) GetMessagesByID(server, channel string, ids []string) ([]storage.Message, error) {
 	messages := make([]storage.Message, len(ids))
 
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.view(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketMessages).Bucket([]byte(server + ":" + channel))
 
 		for i, id := range ids {
-			messages[i].Unmarshal(b.Get([]byte(id)))
+			unmarshal(&messages[i], b.Get([]byte(id)))
 		}
 		return nil
 	})
 	return messages, err
 }
 
+// streamChunkSize is how many messages StreamMessages reads in each
+// transaction, fn is called between them so slow readers like log
+// downloads do not keep a transaction open
+const streamChunkSize = 500
+
+// StreamMessages calls fn with the messages of channel sent at or after
+// from, oldest first, until it runs out of messages or fn returns false
+func (s *BoltStore) StreamMessages(server, channel string, from time.Time, fn func(*storage.Message) bool) error {
+	seek := []byte(storage.MessageIDPrefix(from))
+	after := false
+
+	for {
+		chunk := make([]storage.Message, 0, streamChunkSize)
+
+		err := s.view(func(tx *bolt.Tx) error {
+			b := tx.Bucket(bucketMessages).Bucket([]byte(server + ":" + channel))
+			if b == nil {
+				return nil
+			}
+
+			c := b.Cursor()
+			k, v := c.Seek(seek)
+			if after && bytes.Equal(k, seek) {
+				k, v = c.Next()
+			}
+
+			for ; k != nil && len(chunk) < streamChunkSize; k, v = c.Next() {
+				var message storage.Message
+				unmarshal(&message, v)
+				chunk = append(chunk, message)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for i := range chunk {
+			if !fn(&chunk[i]) {
+				return nil
+			}
+		}
+
+		if len(chunk) < streamChunkSize {
+			return nil
+		}
+		seek = []byte(chunk[len(chunk)-1].ID)
+		after = true
+	}
+}
+
+// ForEachMessage calls fn with every message, the buckets are named after
+// the server and channel which is where Server and To come from
+func (s *BoltStore) ForEachMessage(fn func(*storage.Message) error) error {
+	return s.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketMessages).ForEach(func(name, _ []byte) error {
+			b := tx.Bucket(bucketMessages).Bucket(name)
+			if b == nil {
+				return nil
+			}
+
+			sep := bytes.LastIndexByte(name, ':')
+			if sep < 0 {
+				return nil
+			}
+			server, channel := string(name[:sep]), string(name[sep+1:])
+
+			return b.ForEach(func(_, v []byte) error {
+				var message storage.Message
+				unmarshal(&message, v)
+				message.Server = server
+				message.To = channel
+				return fn(&message)
+			})
+		})
+	})
+}
+
+// RenameChannel moves the messages and topics of a channel to a new name,
+// they get merged with any that are already stored under the new name
+func (s *BoltStore) RenameChannel(server, from, to string) error {
+	if from == to {
+		return nil
+	}
+
+	return s.update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{bucketMessages, bucketTopics} {
+			parent := tx.Bucket(name)
+			old := parent.Bucket([]byte(server + ":" + from))
+			if old == nil {
+				continue
+			}
+
+			b, err := parent.CreateBucketIfNotExists([]byte(server + ":" + to))
+			if err != nil {
+				return err
+			}
+
+			err = old.ForEach(func(k, v []byte) error {
+				return b.Put(append([]byte{}, k...), append([]byte{}, v...))
+			})
+			if err != nil {
+				return err
+			}
+
+			err = parent.DeleteBucket([]byte(server + ":" + from))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// unmarshaler is implemented by the types generated from storage.schema
+type unmarshaler interface {
+	Size() uint64
+	Unmarshal([]byte) (uint64, error)
+}
+
+// unmarshal decodes a record into v, which has to be zeroed. Records written
+// before fields were appended to their schema are too short for the decoder,
+// since every field encodes its zero value as zero bytes the record gets
+// padded with as many as the zero value of v takes up, which leaves the
+// appended fields zeroed. Records that still fail to decode are corrupt and
+// get logged.
+func unmarshal(v unmarshaler, data []byte) {
+	padded := make([]byte, len(data)+int(v.Size()))
+	copy(padded, data)
+
+	defer func() {
+		if err := recover(); err != nil {
+			log.Println("[Bolt] Corrupt record:", err)
+		}
+	}()
+
+	v.Unmarshal(padded)
+}
+
+func (s *BoltStore) GetMessageContext(server, channel, id string, count int) ([]storage.Message, error) {
+	var messages []storage.Message
+
+	err := s.view(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketMessages).Bucket([]byte(server + ":" + channel))
+		if b == nil {
+			return storage.ErrNotFound
+		}
+
+		c := b.Cursor()
+		k, _ := c.Seek([]byte(id))
+		if k == nil || string(k) != id {
+			return storage.ErrNotFound
+		}
+
+		var before [][]byte
+		for k, v := c.Prev(); k != nil && len(before) < count; k, v = c.Prev() {
+			before = append(before, v)
+		}
+
+		messages = make([]storage.Message, len(before), len(before)+count+1)
+		for i, v := range before {
+			unmarshal(&messages[len(before)-i-1], v)
+		}
+
+		for k, v := c.Seek([]byte(id)); k != nil && len(messages) < cap(messages); k, v = c.Next() {
+			message := storage.Message{}
+			unmarshal(&message, v)
+			messages = append(messages, message)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// GetMessageIDAt seeks to t using the time every message ID starts with
+func (s *BoltStore) GetMessageIDAt(server, channel string, t time.Time) (string, error) {
+	var id string
+
+	err := s.view(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketMessages).Bucket([]byte(server + ":" + channel))
+		if b == nil {
+			return storage.ErrNotFound
+		}
+
+		k, _ := b.Cursor().Seek([]byte(storage.MessageIDPrefix(t)))
+		if k == nil {
+			return storage.ErrNotFound
+		}
+
+		id = string(k)
+		return nil
+	})
+
+	return id, err
+}
+
+func (s *BoltStore) LogTopic(topic *storage.Topic) error {
+	return s.batch(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket(bucketTopics).CreateBucketIfNotExists([]byte(topic.Server + ":" + topic.Channel))
+		if err != nil {
+			return err
+		}
+
+		data, err := topic.Marshal(nil)
+		if err != nil {
+			return err
+		}
+
+		return b.Put([]byte(topic.ID), data)
+	})
+}
+
+func (s *BoltStore) GetTopics(server, channel string, count int, fromID string) ([]storage.Topic, bool, error) {
+	topics := make([]storage.Topic, count)
+	hasMore := false
+
+	s.view(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketTopics).Bucket([]byte(server + ":" + channel))
+		if b == nil {
+			return nil
+		}
+
+		c := b.Cursor()
+
+		var k, v []byte
+		if fromID != "" {
+			c.Seek([]byte(fromID))
+			k, v = c.Prev()
+		} else {
+			k, v = c.Last()
+		}
+
+		for ; count > 0 && k != nil; k, v = c.Prev() {
+			count--
+			topics[count].Unmarshal(v)
+			topics[count].Server = server
+			topics[count].Channel = channel
+		}
+		hasMore = k != nil
+
+		return nil
+	})
+
+	return topics[count:], hasMore, nil
+}
+
 func (s *BoltStore) GetSessions() ([]*session.Session, error) {
 	var sessions []*session.Session
 
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.view(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketSessions)
 
 		return b.ForEach(func(_ []byte, v []byte) error {
@@ -389,7 +870,7 @@ func (s *BoltStore) GetSessions() ([]*session.Session, error) {
 }
 
 func (s *BoltStore) SaveSession(session *session.Session) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketSessions)
 
 		data, err := session.Marshal(nil)
@@ -402,7 +883,7 @@ func (s *BoltStore) SaveSession(session *session.Session) error {
 }
 
 func (s *BoltStore) DeleteSession(key string) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		return tx.Bucket(bucketSessions).Delete([]byte(key))
 	})
 }
@@ -437,6 +918,10 @@ func channelID(user *storage.User, server, channel string) []byte {
 	return id
 }
 
+func aliasID(user *storage.User, name string) []byte {
+	return serverID(user, name)
+}
+
 func idToBytes(i uint64) []byte {
 	b := make([]byte, 8)
 	binary.BigEndian.PutUint64(b, i)
@@ -0,0 +1,202 @@
+package boltdb
+
+import (
+	"os"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+var (
+	// compactTxSize is how many bytes get written to the compacted copy
+	// before committing, this keeps the memory use of large copies down
+	compactTxSize = 64 * 1024 * 1024
+	// compactAttempts is how many times a copy is made while writes go on
+	// before writes get blocked for the final one
+	compactAttempts = 3
+)
+
+// Compact rewrites the database into a new file that leaves out the free
+// pages. The copy is made while reads and writes go on, writes are only
+// blocked while the files get swapped, or for a final copy when every
+// attempt got written to while it was being made
+func (s *BoltStore) Compact() (storage.CompactStats, error) {
+	s.compactLock.Lock()
+	defer s.compactLock.Unlock()
+
+	start := time.Now()
+	path := s.db.Path()
+	tmpPath := path + ".compact"
+	defer os.Remove(tmpPath)
+
+	sizeBefore, err := fileSize(path)
+	if err != nil {
+		return storage.CompactStats{}, err
+	}
+
+	for attempt := 0; attempt < compactAttempts; attempt++ {
+		txID, err := compactTo(s.db, tmpPath)
+		if err != nil {
+			return storage.CompactStats{}, err
+		}
+
+		s.lock.Lock()
+		if currentTxID(s.db) == txID {
+			err = s.swap(tmpPath)
+			s.lock.Unlock()
+			return compactStats(start, sizeBefore, path, err)
+		}
+		s.lock.Unlock()
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, err = compactTo(s.db, tmpPath); err == nil {
+		err = s.swap(tmpPath)
+	}
+	return compactStats(start, sizeBefore, path, err)
+}
+
+func compactStats(start time.Time, sizeBefore int64, path string, err error) (storage.CompactStats, error) {
+	if err != nil {
+		return storage.CompactStats{}, err
+	}
+
+	sizeAfter, err := fileSize(path)
+	return storage.CompactStats{
+		Duration:   time.Since(start),
+		SizeBefore: sizeBefore,
+		SizeAfter:  sizeAfter,
+	}, err
+}
+
+// swap replaces the database with the compacted copy at tmpPath, s.lock
+// has to be held for writing
+func (s *BoltStore) swap(tmpPath string) error {
+	path := s.db.Path()
+	if err := s.db.Close(); err != nil {
+		return err
+	}
+
+	renameErr := os.Rename(tmpPath, path)
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return err
+	}
+	s.db = db
+	return renameErr
+}
+
+func currentTxID(db *bolt.DB) int {
+	var id int
+	db.View(func(tx *bolt.Tx) error {
+		id = tx.ID()
+		return nil
+	})
+	return id
+}
+
+// compactTo copies everything in src to a new database at path, it
+// returns the ID of the transaction the copy was made from
+func compactTo(src *bolt.DB, path string) (int, error) {
+	os.Remove(path)
+	dst, err := bolt.Open(path, 0600, &bolt.Options{NoSync: true})
+	if err != nil {
+		return 0, err
+	}
+
+	var txID int
+	err = src.View(func(tx *bolt.Tx) error {
+		txID = tx.ID()
+		return copyTx(tx, dst)
+	})
+	if err == nil {
+		err = dst.Sync()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	return txID, err
+}
+
+// copyTx writes the buckets of tx to dst, committing every compactTxSize
+// bytes
+func copyTx(tx *bolt.Tx, dst *bolt.DB) error {
+	dstTx, err := dst.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		dstTx.Rollback()
+	}()
+
+	size := 0
+	var walk func(src *bolt.Bucket, path [][]byte) error
+	walk = func(src *bolt.Bucket, path [][]byte) error {
+		return src.ForEach(func(k, v []byte) error {
+			if size+len(k)+len(v) > compactTxSize {
+				if err := dstTx.Commit(); err != nil {
+					return err
+				}
+				if dstTx, err = dst.Begin(true); err != nil {
+					return err
+				}
+				size = 0
+			}
+			size += len(k) + len(v)
+
+			b := dstBucket(dstTx, path)
+			// Fill the pages completely, the keys are written in order
+			b.FillPercent = 1
+			if v != nil {
+				return b.Put(k, v)
+			}
+
+			child := src.Bucket(k)
+			created, err := b.CreateBucket(k)
+			if err != nil {
+				return err
+			}
+			if err = created.SetSequence(child.Sequence()); err != nil {
+				return err
+			}
+			return walk(child, append(path[:len(path):len(path)], k))
+		})
+	}
+
+	err = tx.ForEach(func(name []byte, src *bolt.Bucket) error {
+		b, err := dstTx.CreateBucket(name)
+		if err != nil {
+			return err
+		}
+		if err = b.SetSequence(src.Sequence()); err != nil {
+			return err
+		}
+		return walk(src, [][]byte{name})
+	})
+	if err != nil {
+		return err
+	}
+	return dstTx.Commit()
+}
+
+// dstBucket returns the nested bucket at path, it gets looked up again in
+// every transaction since buckets are only valid in the one they came from
+func dstBucket(tx *bolt.Tx, path [][]byte) *bolt.Bucket {
+	b := tx.Bucket(path[0])
+	for _, name := range path[1:] {
+		b = b.Bucket(name)
+	}
+	return b
+}
+
+func fileSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
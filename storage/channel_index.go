@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"math"
 	"sort"
 	"strings"
 	"sync"
@@ -18,6 +19,7 @@ type ChannelListIndex interface {
 	Finish()
 	Search(q string) []*ChannelListItem
 	SearchN(q string, start, n int) []*ChannelListItem
+	SearchFuzzy(q string, start, n int) []*ChannelListItem
 	len() int
 }
 
@@ -32,24 +34,24 @@ func NewMapChannelListIndex() *MapChannelListIndex {
 	}
 }
 
+// Add indexes the item right away so that large LIST responses never
+// have to be buffered in full, Finish only has to sort the results
 func (idx *MapChannelListIndex) Add(item *ChannelListItem) {
 	idx.channels = append(idx.channels, item)
+
+	key := strings.TrimLeft(strings.ToLower(item.Name), "#")
+
+	for i := 1; i <= len(key); i++ {
+		k := key[:i]
+		idx.m[k] = append(idx.m[k], item)
+	}
 }
 
 func (idx *MapChannelListIndex) Finish() {
 	sort.Sort(idx.channels)
 
-	for _, ch := range idx.channels {
-		key := strings.TrimLeft(strings.ToLower(ch.Name), "#")
-
-		for i := 1; i <= len(key); i++ {
-			k := key[:i]
-			if _, ok := idx.m[k]; ok {
-				idx.m[k] = append(idx.m[k], ch)
-			} else {
-				idx.m[k] = chanList{ch}
-			}
-		}
+	for _, channels := range idx.m {
+		sort.Sort(chanList(channels))
 	}
 }
 
@@ -75,6 +77,87 @@ func (idx *MapChannelListIndex) SearchN(q string, start, n int) []*ChannelListIt
 	return res[start:min(start+n, len(res))]
 }
 
+// SearchFuzzy finds channels whose names contain q with a few typos allowed,
+// results are ranked by how well they match and then by user count
+func (idx *MapChannelListIndex) SearchFuzzy(q string, start, n int) []*ChannelListItem {
+	q = strings.TrimLeft(strings.ToLower(q), "#")
+	if q == "" {
+		return idx.SearchN(q, start, n)
+	}
+
+	maxDist := 0
+	if len(q) >= 7 {
+		maxDist = 2
+	} else if len(q) >= 3 {
+		maxDist = 1
+	}
+
+	var results []fuzzyResult
+	for _, ch := range idx.channels {
+		name := strings.TrimLeft(strings.ToLower(ch.Name), "#")
+
+		dist := substringDistance(q, name)
+		if dist > maxDist {
+			continue
+		}
+
+		score := 1 - float64(dist)/float64(len(q))
+		if strings.HasPrefix(name, q) {
+			score += 0.1
+		}
+		score += 0.05 * math.Log10(float64(ch.UserCount+1))
+
+		results = append(results, fuzzyResult{ch, score})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	if start >= len(results) {
+		return nil
+	}
+	results = results[start:min(start+n, len(results))]
+
+	channels := make([]*ChannelListItem, len(results))
+	for i, res := range results {
+		channels[i] = res.channel
+	}
+	return channels
+}
+
+type fuzzyResult struct {
+	channel *ChannelListItem
+	score   float64
+}
+
+// substringDistance returns the smallest edit distance between q and
+// any substring of s
+func substringDistance(q, s string) int {
+	prev := make([]int, len(s)+1)
+	cur := make([]int, len(s)+1)
+
+	for i := 1; i <= len(q); i++ {
+		cur[0] = i
+		for j := 1; j <= len(s); j++ {
+			cost := 1
+			if q[i-1] == s[j-1] {
+				cost = 0
+			}
+			cur[j] = min(min(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	best := prev[0]
+	for _, d := range prev {
+		if d < best {
+			best = d
+		}
+	}
+	return best
+}
+
 func (idx *MapChannelListIndex) len() int {
 	return len(idx.channels)
 }
@@ -42,3 +42,55 @@ func TestMapChannelListIndex(t *testing.T) {
 	assert.Len(t, i.Search("p"), 2)
 	assert.Equal(t, "#Pork", i.Search("p")[1].Name)
 }
+
+func TestMapChannelListIndexFuzzy(t *testing.T) {
+	i := NewMapChannelListIndex()
+	i.Add(&ChannelListItem{
+		Name:      "#python",
+		UserCount: 1500,
+	})
+	i.Add(&ChannelListItem{
+		Name:      "#python-dev",
+		UserCount: 300,
+	})
+	i.Add(&ChannelListItem{
+		Name:      "#pylons",
+		UserCount: 20,
+	})
+	i.Add(&ChannelListItem{
+		Name:      "#cython",
+		UserCount: 40,
+	})
+	i.Add(&ChannelListItem{
+		Name:      "#go-nuts",
+		UserCount: 2000,
+	})
+	i.Finish()
+
+	res := i.SearchFuzzy("pyton", 0, 10)
+	assert.Len(t, res, 3)
+	assert.Equal(t, "#python", res[0].Name)
+	assert.Equal(t, "#python-dev", res[1].Name)
+	assert.Equal(t, "#pylons", res[2].Name)
+
+	res = i.SearchFuzzy("#cyton", 0, 10)
+	assert.Len(t, res, 1)
+	assert.Equal(t, "#cython", res[0].Name)
+
+	res = i.SearchFuzzy("python", 0, 10)
+	assert.Equal(t, "#python", res[0].Name)
+	assert.Equal(t, "#python-dev", res[1].Name)
+	assert.Equal(t, "#cython", res[2].Name)
+
+	assert.Len(t, i.SearchFuzzy("python", 1, 1), 1)
+	assert.Len(t, i.SearchFuzzy("python", 10, 1), 0)
+	assert.Len(t, i.SearchFuzzy("", 0, 10), 5)
+	assert.Len(t, i.SearchFuzzy("xyz", 0, 10), 0)
+}
+
+func TestSubstringDistance(t *testing.T) {
+	assert.Equal(t, 0, substringDistance("thon", "python"))
+	assert.Equal(t, 1, substringDistance("pyton", "python-dev"))
+	assert.Equal(t, 2, substringDistance("pthn", "python"))
+	assert.Equal(t, 3, substringDistance("abc", "xyz"))
+}
@@ -0,0 +1,93 @@
+package storage_test
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/boltdb"
+	"github.com/kjk/betterguid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoltCompact(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+	defer db.Close()
+
+	var _ storage.Compacter = db
+
+	user := &storage.User{}
+	assert.Nil(t, db.SaveUser(user))
+
+	var messages []*storage.Message
+	for i := 0; i < 2000; i++ {
+		messages = append(messages, &storage.Message{
+			ID:      betterguid.New(),
+			Server:  "srv",
+			From:    "nick",
+			To:      "#old",
+			Content: strconv.Itoa(i) + strings.Repeat(" message", 32),
+		})
+	}
+	assert.Nil(t, db.LogMessages(messages))
+
+	// Moving the messages leaves their old pages free
+	assert.Nil(t, db.RenameChannel("srv", "#old", "#chan"))
+
+	// Writes made while compacting are kept
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			db.LogMessage(&storage.Message{
+				ID:      betterguid.New(),
+				Server:  "srv",
+				From:    "nick",
+				To:      "#other",
+				Content: "during" + strconv.Itoa(i),
+			})
+		}
+	}()
+
+	stats, err := db.Compact()
+	wg.Wait()
+	assert.Nil(t, err)
+	assert.True(t, stats.SizeAfter < stats.SizeBefore, stats)
+	assert.Equal(t, stats.SizeBefore-stats.SizeAfter, stats.Reclaimed())
+	assert.True(t, stats.Duration > 0)
+
+	res, hasMore, err := db.GetMessages("srv", "#chan", 5000, "")
+	assert.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Len(t, res, 2000)
+	assert.Equal(t, "1999"+strings.Repeat(" message", 32), res[1999].Content)
+
+	res, _, err = db.GetMessages("srv", "#other", 200, "")
+	assert.Nil(t, err)
+	assert.Len(t, res, 20)
+
+	res, _, err = db.GetMessages("srv", "#old", 10, "")
+	assert.Nil(t, err)
+	assert.Empty(t, res)
+
+	// Sequences carry over
+	next := &storage.User{}
+	assert.Nil(t, db.SaveUser(next))
+	assert.Equal(t, user.ID+1, next.ID)
+
+	assert.Nil(t, db.LogMessage(&storage.Message{
+		ID:      betterguid.New(),
+		Server:  "srv",
+		To:      "#chan",
+		Content: "after",
+	}))
+	res, _, err = db.GetMessages("srv", "#chan", 1, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "after", res[0].Content)
+}
@@ -52,6 +52,14 @@ func (d directory) Key(username string) string {
 	return filepath.Join(d.User(username), "key.pem")
 }
 
+func (d directory) EncryptionKey(username string) string {
+	return filepath.Join(d.User(username), "data.key")
+}
+
+func (d directory) EncryptedIndex(username string) string {
+	return filepath.Join(d.User(username), "index.hashed")
+}
+
 func (d directory) Downloads(username string) string {
 	return filepath.Join(d.User(username), "downloads")
 }
@@ -60,6 +68,22 @@ func (d directory) DownloadedFile(username string, file string) string {
 	return filepath.Join(d.Downloads(username), file)
 }
 
+func (d directory) RawLogs(username string) string {
+	return filepath.Join(d.User(username), "raw")
+}
+
+func (d directory) RawLog(username, server string) string {
+	return filepath.Join(d.RawLogs(username), server+".log")
+}
+
+func (d directory) Pastes(username string) string {
+	return filepath.Join(d.User(username), "pastes")
+}
+
+func (d directory) Paste(username, id string) string {
+	return filepath.Join(d.Pastes(username), id)
+}
+
 func (d directory) Config() string {
 	return filepath.Join(d.ConfigRoot(), "config.toml")
 }
@@ -0,0 +1,381 @@
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+var (
+	ErrWrongPassphrase = errors.New("Could not decrypt the data key, wrong passphrase")
+	ErrNoPassphrase    = errors.New("No encryption passphrase set")
+)
+
+const (
+	keySize        = 32
+	saltSize       = 16
+	kdfIterations  = 100000
+	wrappedKeySize = saltSize + 12 + keySize + 16
+)
+
+// LoadEncryptionKey returns the random key the users data is encrypted with,
+// the key is stored on disk wrapped with a key derived from passphrase. If it can
+// only be unwrapped with previousPassphrase it gets rewrapped with passphrase,
+// this makes rotating the passphrase cheap since no data has to be re-encrypted.
+func LoadEncryptionKey(username, passphrase, previousPassphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, ErrNoPassphrase
+	}
+
+	path := Path.EncryptionKey(username)
+
+	wrapped, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		key := make([]byte, keySize)
+		_, err = io.ReadFull(rand.Reader, key)
+		if err != nil {
+			return nil, err
+		}
+
+		return key, saveEncryptionKey(path, key, passphrase)
+	} else if err != nil {
+		return nil, err
+	}
+
+	key, err := unwrapKey(wrapped, passphrase)
+	if err == nil {
+		return key, nil
+	}
+
+	if previousPassphrase != "" {
+		key, err = unwrapKey(wrapped, previousPassphrase)
+		if err == nil {
+			return key, saveEncryptionKey(path, key, passphrase)
+		}
+	}
+
+	return nil, ErrWrongPassphrase
+}
+
+// IndexKey derives the key used for hashing search index terms from the data key
+func IndexKey(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("dispatch index"))
+	return mac.Sum(nil)
+}
+
+func saveEncryptionKey(path string, key []byte, passphrase string) error {
+	wrapped, err := wrapKey(key, passphrase)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	err = ioutil.WriteFile(tmp, wrapped, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func wrapKey(key []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	_, err := io.ReadFull(rand.Reader, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(deriveKey(passphrase, salt))
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := seal(aead, key)
+	if err != nil {
+		return nil, err
+	}
+	return append(salt, sealed...), nil
+}
+
+func unwrapKey(wrapped []byte, passphrase string) ([]byte, error) {
+	if len(wrapped) != wrappedKeySize {
+		return nil, ErrWrongPassphrase
+	}
+
+	aead, err := newAEAD(deriveKey(passphrase, wrapped[:saltSize]))
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, wrapped[saltSize:])
+}
+
+func deriveKey(passphrase string, salt []byte) []byte {
+	return pbkdf2.Key([]byte(passphrase), salt, kdfIterations, keySize, sha256.New)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	_, err := io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(aead cipher.AEAD, data []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+	return aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+}
+
+// EncryptedMessageStore encrypts the content of messages and topics before
+// they reach the underlying store, channel and server names are left as is
+// since they are used as keys
+type EncryptedMessageStore struct {
+	MessageStore
+	aead cipher.AEAD
+}
+
+func NewEncryptedMessageStore(store MessageStore, key []byte) (*EncryptedMessageStore, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EncryptedMessageStore{
+		MessageStore: store,
+		aead:         aead,
+	}, nil
+}
+
+func (s *EncryptedMessageStore) encrypt(v string) (string, error) {
+	if v == "" {
+		return v, nil
+	}
+
+	sealed, err := seal(s.aead, []byte(v))
+	if err != nil {
+		return "", err
+	}
+	return string(sealed), nil
+}
+
+// decrypt returns v as is if it can not be decrypted, this keeps data
+// logged before encryption was turned on readable
+func (s *EncryptedMessageStore) decrypt(v string) string {
+	if v == "" {
+		return v
+	}
+
+	plaintext, err := open(s.aead, []byte(v))
+	if err != nil {
+		return v
+	}
+	return string(plaintext)
+}
+
+// encryptStrings encrypts the values vs point to in place
+func (s *EncryptedMessageStore) encryptStrings(vs ...*string) error {
+	for _, v := range vs {
+		encrypted, err := s.encrypt(*v)
+		if err != nil {
+			return err
+		}
+		*v = encrypted
+	}
+	return nil
+}
+
+func (s *EncryptedMessageStore) encryptMessage(message *Message) (*Message, error) {
+	encrypted := *message
+	err := s.encryptStrings(&encrypted.From, &encrypted.Account, &encrypted.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(message.Events) > 0 {
+		encrypted.Events = make([]Event, len(message.Events))
+
+		for i, event := range message.Events {
+			encrypted.Events[i] = event
+			encrypted.Events[i].Params = make([]string, len(event.Params))
+
+			for j, param := range event.Params {
+				encrypted.Events[i].Params[j], err = s.encrypt(param)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return &encrypted, nil
+}
+
+func (s *EncryptedMessageStore) decryptMessages(messages []Message) []Message {
+	for i := range messages {
+		messages[i].From = s.decrypt(messages[i].From)
+		messages[i].Account = s.decrypt(messages[i].Account)
+		messages[i].Content = s.decrypt(messages[i].Content)
+
+		for _, event := range messages[i].Events {
+			for j, param := range event.Params {
+				event.Params[j] = s.decrypt(param)
+			}
+		}
+	}
+	return messages
+}
+
+// LogMessage fails without storing anything if the message can not be
+// encrypted, it is never stored in plaintext
+func (s *EncryptedMessageStore) LogMessage(message *Message) error {
+	encrypted, err := s.encryptMessage(message)
+	if err != nil {
+		return err
+	}
+	return s.MessageStore.LogMessage(encrypted)
+}
+
+func (s *EncryptedMessageStore) LogMessages(messages []*Message) error {
+	encrypted := make([]*Message, len(messages))
+	for i, message := range messages {
+		var err error
+		encrypted[i], err = s.encryptMessage(message)
+		if err != nil {
+			return err
+		}
+	}
+	return s.MessageStore.LogMessages(encrypted)
+}
+
+// ForEachMessage decrypts the messages of the underlying store,
+// it fails with ErrReindexUnsupported if that can not iterate
+func (s *EncryptedMessageStore) ForEachMessage(fn func(*Message) error) error {
+	iter, ok := s.MessageStore.(MessageIterator)
+	if !ok {
+		return ErrReindexUnsupported
+	}
+
+	return iter.ForEachMessage(func(message *Message) error {
+		decrypted := s.decryptMessages([]Message{*message})
+		return fn(&decrypted[0])
+	})
+}
+
+// StreamMessages decrypts the messages streamed from the underlying
+// store, a page at a time if that is not a MessageStreamer
+func (s *EncryptedMessageStore) StreamMessages(server, channel string, from time.Time, fn func(*Message) bool) error {
+	streamer, ok := s.MessageStore.(MessageStreamer)
+	if !ok {
+		return streamMessagePages(s, server, channel, from, fn)
+	}
+
+	return streamer.StreamMessages(server, channel, from, func(message *Message) bool {
+		decrypted := s.decryptMessages([]Message{*message})
+		return fn(&decrypted[0])
+	})
+}
+
+// RenameChannel renames the channel in the underlying store, channel
+// names are not encrypted
+func (s *EncryptedMessageStore) RenameChannel(server, from, to string) error {
+	renamer, ok := s.MessageStore.(ChannelRenamer)
+	if !ok {
+		return ErrRenameUnsupported
+	}
+	return renamer.RenameChannel(server, from, to)
+}
+
+// Compact compacts the underlying store
+func (s *EncryptedMessageStore) Compact() (CompactStats, error) {
+	compacter, ok := s.MessageStore.(Compacter)
+	if !ok {
+		return CompactStats{}, ErrCompactUnsupported
+	}
+	return compacter.Compact()
+}
+
+func (s *EncryptedMessageStore) GetMessages(server, channel string, count int, fromID string) ([]Message, bool, error) {
+	messages, hasMore, err := s.MessageStore.GetMessages(server, channel, count, fromID)
+	return s.decryptMessages(messages), hasMore, err
+}
+
+func (s *EncryptedMessageStore) GetMessagesByID(server, channel string, ids []string) ([]Message, error) {
+	messages, err := s.MessageStore.GetMessagesByID(server, channel, ids)
+	return s.decryptMessages(messages), err
+}
+
+func (s *EncryptedMessageStore) GetMessageContext(server, channel, id string, count int) ([]Message, error) {
+	messages, err := s.MessageStore.GetMessageContext(server, channel, id, count)
+	return s.decryptMessages(messages), err
+}
+
+func (s *EncryptedMessageStore) LogTopic(topic *Topic) error {
+	encrypted := *topic
+	if err := s.encryptStrings(&encrypted.Topic, &encrypted.Nick); err != nil {
+		return err
+	}
+	return s.MessageStore.LogTopic(&encrypted)
+}
+
+func (s *EncryptedMessageStore) GetTopics(server, channel string, count int, fromID string) ([]Topic, bool, error) {
+	topics, hasMore, err := s.MessageStore.GetTopics(server, channel, count, fromID)
+	for i := range topics {
+		topics[i].Topic = s.decrypt(topics[i].Topic)
+		topics[i].Nick = s.decrypt(topics[i].Nick)
+	}
+	return topics, hasMore, err
+}
+
+// encryption returns the store that encrypts the messages of the user, nil
+// if they are not encrypted. Other private data of the user, like the
+// command history, gets encrypted with the same key
+func (u *User) encryption() *EncryptedMessageStore {
+	enc, _ := u.messageLog.(*EncryptedMessageStore)
+	return enc
+}
+
+// encryptString returns v encrypted and base64 encoded, the raw ciphertext
+// could contain separators used by the stores
+func encryptString(enc *EncryptedMessageStore, v string) (string, error) {
+	encrypted, err := enc.encrypt(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString([]byte(encrypted)), nil
+}
+
+// decryptString returns v as is if it can not be decrypted, this keeps
+// data stored before encryption was turned on readable
+func decryptString(enc *EncryptedMessageStore, v string) string {
+	sealed, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return v
+	}
+
+	plaintext, err := open(enc.aead, sealed)
+	if err != nil {
+		return v
+	}
+	return string(plaintext)
+}
@@ -0,0 +1,150 @@
+package storage_test
+
+import (
+	"crypto/rand"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/bleve"
+	"github.com/khlieng/dispatch/storage/boltdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadEncryptionKey(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+	os.MkdirAll(storage.Path.User("1"), 0700)
+
+	_, err := storage.LoadEncryptionKey("1", "", "")
+	assert.Equal(t, storage.ErrNoPassphrase, err)
+
+	key, err := storage.LoadEncryptionKey("1", "hunter2", "")
+	assert.Nil(t, err)
+	assert.Len(t, key, 32)
+
+	loaded, err := storage.LoadEncryptionKey("1", "hunter2", "")
+	assert.Nil(t, err)
+	assert.Equal(t, key, loaded)
+
+	_, err = storage.LoadEncryptionKey("1", "wrong", "")
+	assert.Equal(t, storage.ErrWrongPassphrase, err)
+
+	rotated, err := storage.LoadEncryptionKey("1", "hunter3", "hunter2")
+	assert.Nil(t, err)
+	assert.Equal(t, key, rotated)
+
+	_, err = storage.LoadEncryptionKey("1", "hunter2", "")
+	assert.Equal(t, storage.ErrWrongPassphrase, err)
+
+	loaded, err = storage.LoadEncryptionKey("1", "hunter3", "")
+	assert.Nil(t, err)
+	assert.Equal(t, key, loaded)
+}
+
+func TestEncryptedMessageStore(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+	os.MkdirAll(storage.Path.User("1"), 0700)
+
+	key, err := storage.LoadEncryptionKey("1", "hunter2", "")
+	assert.Nil(t, err)
+
+	db, err := boltdb.New(storage.Path.Log("1"))
+	assert.Nil(t, err)
+	defer db.Close()
+
+	// Messages logged before encryption was enabled are still readable
+	db.LogMessage(&storage.Message{ID: "a", Server: "srv", To: "#chan", From: "nick", Content: "plain"})
+
+	store, err := storage.NewEncryptedMessageStore(db, key)
+	assert.Nil(t, err)
+
+	msg := &storage.Message{ID: "b", Server: "srv", To: "#chan", From: "nick", Content: "secret"}
+	assert.Nil(t, store.LogMessage(msg))
+	assert.Equal(t, "secret", msg.Content)
+
+	raw, _, err := db.GetMessages("srv", "#chan", 10, "")
+	assert.Nil(t, err)
+	assert.Len(t, raw, 2)
+	assert.NotEqual(t, "secret", raw[1].Content)
+	assert.NotEqual(t, "nick", raw[1].From)
+
+	messages, _, err := store.GetMessages("srv", "#chan", 10, "")
+	assert.Nil(t, err)
+	assert.Len(t, messages, 2)
+	assert.Equal(t, "plain", messages[0].Content)
+	assert.Equal(t, "secret", messages[1].Content)
+	assert.Equal(t, "nick", messages[1].From)
+
+	assert.Nil(t, store.LogTopic(&storage.Topic{ID: "c", Server: "srv", Channel: "#chan", Topic: "hidden", Nick: "nick"}))
+	topics, _, err := store.GetTopics("srv", "#chan", 10, "")
+	assert.Nil(t, err)
+	assert.Len(t, topics, 1)
+	assert.Equal(t, "hidden", topics[0].Topic)
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("no randomness")
+}
+
+func TestEncryptedMessageStoreSealFails(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+	os.MkdirAll(storage.Path.User("1"), 0700)
+
+	key, err := storage.LoadEncryptionKey("1", "hunter2", "")
+	assert.Nil(t, err)
+
+	db, err := boltdb.New(storage.Path.Log("1"))
+	assert.Nil(t, err)
+	defer db.Close()
+
+	store, err := storage.NewEncryptedMessageStore(db, key)
+	assert.Nil(t, err)
+
+	reader := rand.Reader
+	rand.Reader = failingReader{}
+	defer func() { rand.Reader = reader }()
+
+	// Nothing gets stored when encryption fails
+	msg := &storage.Message{ID: "a", Server: "srv", To: "#chan", From: "nick", Content: "secret"}
+	assert.NotNil(t, store.LogMessage(msg))
+	assert.NotNil(t, store.LogMessages([]*storage.Message{msg}))
+	assert.NotNil(t, store.LogTopic(&storage.Topic{ID: "b", Server: "srv", Channel: "#chan", Topic: "hidden"}))
+
+	raw, _, err := db.GetMessages("srv", "#chan", 10, "")
+	assert.Nil(t, err)
+	assert.Len(t, raw, 0)
+	topics, _, err := db.GetTopics("srv", "#chan", 10, "")
+	assert.Nil(t, err)
+	assert.Len(t, topics, 0)
+}
+
+func TestHashedSearch(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	index, err := bleve.NewHashed(storage.Path.EncryptedIndex("1"), []byte("key"))
+	assert.Nil(t, err)
+	defer index.Close()
+
+	index.Index("1", &storage.Message{Server: "srv", To: "#chan", Account: "bob", Content: "The quick brown fox"})
+	index.Index("2", &storage.Message{Server: "srv", To: "#chan", Account: "alice", Content: "foxes are quick"})
+	index.Index("3", &storage.Message{Server: "srv", To: "#other", Content: "quick fox"})
+
+	ids, _, err := index.SearchMessages("srv", "#chan", "quick fox", 0, 10)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"1", "2"}, ids)
+
+	ids, _, err = index.SearchMessages("srv", "#chan", "brown", 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1"}, ids)
+
+	ids, _, err = index.SearchMessages("srv", "#chan", "fox account:alice", 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"2"}, ids)
+
+	snippet := index.Highlight("foxes are quick", "fox account:alice")
+	assert.Equal(t, "foxes are quick", snippet.Text)
+	assert.Equal(t, []storage.Highlight{{Start: 0, End: 5}}, snippet.Highlights)
+}
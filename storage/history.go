@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"strings"
+)
+
+// CommandHistoryLimit is how many lines of input are kept for each
+// server and channel, the oldest lines get evicted first
+var CommandHistoryLimit = 100
+
+// GetCommandHistory returns the lines the user sent to a channel, oldest first
+func (u *User) GetCommandHistory(server, channel string) ([]string, error) {
+	history, err := u.store.GetCommandHistory(u, server, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	if enc := u.encryption(); enc != nil {
+		for i, line := range history {
+			history[i] = decryptString(enc, line)
+		}
+	}
+	return history, nil
+}
+
+// AddCommandHistory appends line to the command history of a channel,
+// repeats of the last line and lines containing passwords are not stored
+func (u *User) AddCommandHistory(server, channel, line string) error {
+	if strings.TrimSpace(line) == "" || IsSensitiveCommand(line) {
+		return nil
+	}
+
+	u.historyLock.Lock()
+	defer u.historyLock.Unlock()
+
+	history, err := u.GetCommandHistory(server, channel)
+	if err != nil {
+		return err
+	}
+
+	if len(history) > 0 && history[len(history)-1] == line {
+		return nil
+	}
+
+	history = append(history, line)
+	if len(history) > CommandHistoryLimit {
+		history = history[len(history)-CommandHistoryLimit:]
+	}
+
+	if enc := u.encryption(); enc != nil {
+		for i, line := range history {
+			history[i], err = encryptString(enc, line)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return u.store.SetCommandHistory(u, server, channel, history)
+}
+
+// sensitiveCommands send a password as one of their arguments
+var sensitiveCommands = map[string]bool{
+	"authenticate": true,
+	"identify":     true,
+	"login":        true,
+	"oper":         true,
+	"pass":         true,
+	"sasl":         true,
+}
+
+// sensitiveServiceCommands send a password when sent to NickServ
+var sensitiveServiceCommands = map[string]bool{
+	"ghost":    true,
+	"group":    true,
+	"id":       true,
+	"identify": true,
+	"login":    true,
+	"recover":  true,
+	"regain":   true,
+	"register": true,
+	"release":  true,
+}
+
+// IsSensitiveCommand reports whether line is a command that can contain a
+// password, like identifying with NickServ or becoming an operator
+func IsSensitiveCommand(line string) bool {
+	if !strings.HasPrefix(line, "/") {
+		return false
+	}
+
+	fields := strings.Fields(strings.ToLower(line[1:]))
+	if len(fields) == 0 {
+		return false
+	}
+
+	// Raw lines are written as is, PRIVMSG works the same as /msg
+	if fields[0] == "quote" || fields[0] == "raw" {
+		fields = fields[1:]
+		if len(fields) == 0 {
+			return false
+		}
+		if fields[0] == "privmsg" {
+			fields[0] = "msg"
+		}
+	}
+
+	if sensitiveCommands[fields[0]] {
+		return true
+	}
+
+	var args []string
+	switch fields[0] {
+	case "ns", "nickserv":
+		args = fields[1:]
+
+	case "msg", "query":
+		if len(fields) > 1 && isNickServ(fields[1]) {
+			args = fields[2:]
+		}
+	}
+
+	if len(args) == 0 {
+		return false
+	}
+
+	command := strings.TrimPrefix(args[0], ":")
+	if command == "set" && len(args) > 1 {
+		return args[1] == "password" || args[1] == "pass"
+	}
+	return sensitiveServiceCommands[command]
+}
+
+func isNickServ(target string) bool {
+	return target == "nickserv" || strings.HasPrefix(target, "nickserv@")
+}
@@ -0,0 +1,134 @@
+package storage_test
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/boltdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandHistory(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+	defer db.Close()
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return db, nil
+	}
+	storage.GetMessageSearchProvider = func(_ *storage.User) (storage.MessageSearchProvider, error) {
+		return nil, nil
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+
+	limit := storage.CommandHistoryLimit
+	storage.CommandHistoryLimit = 3
+	defer func() { storage.CommandHistoryLimit = limit }()
+
+	history, err := user.GetCommandHistory("srv", "#chan")
+	assert.Nil(t, err)
+	assert.Empty(t, history)
+
+	for i := 0; i < 5; i++ {
+		assert.Nil(t, user.AddCommandHistory("srv", "#chan", "line "+strconv.Itoa(i)))
+	}
+	assert.Nil(t, user.AddCommandHistory("srv", "#other", "other"))
+
+	history, err = user.GetCommandHistory("srv", "#chan")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"line 2", "line 3", "line 4"}, history)
+
+	// Repeats, blank lines and passwords are skipped
+	assert.Nil(t, user.AddCommandHistory("srv", "#chan", "line 4"))
+	assert.Nil(t, user.AddCommandHistory("srv", "#chan", " "))
+	assert.Nil(t, user.AddCommandHistory("srv", "#chan", "/msg NickServ IDENTIFY hunter2"))
+	assert.Nil(t, user.AddCommandHistory("srv", "#chan", "multi\nline"))
+
+	history, _ = user.GetCommandHistory("srv", "#chan")
+	assert.Equal(t, []string{"line 3", "line 4", "multi\nline"}, history)
+
+	history, _ = user.GetCommandHistory("srv", "#other")
+	assert.Equal(t, []string{"other"}, history)
+
+	assert.Nil(t, user.AddServer(&storage.Server{Host: "srv"}))
+	assert.Nil(t, user.RemoveServer("srv"))
+	history, _ = user.GetCommandHistory("srv", "#chan")
+	assert.Empty(t, history)
+}
+
+func TestEncryptedCommandHistory(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+	defer db.Close()
+
+	os.MkdirAll(storage.Path.User("history"), 0700)
+	key, err := storage.LoadEncryptionKey("history", "hunter2", "")
+	assert.Nil(t, err)
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return storage.NewEncryptedMessageStore(db, key)
+	}
+	storage.GetMessageSearchProvider = func(_ *storage.User) (storage.MessageSearchProvider, error) {
+		return nil, nil
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+
+	// Lines stored before encryption was turned on stay readable
+	assert.Nil(t, db.SetCommandHistory(user, "srv", "#chan", []string{"plain"}))
+	assert.Nil(t, user.AddCommandHistory("srv", "#chan", "secret"))
+	assert.Nil(t, user.AddCommandHistory("srv", "#chan", "secret"))
+
+	history, err := user.GetCommandHistory("srv", "#chan")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"plain", "secret"}, history)
+
+	raw, err := db.GetCommandHistory(user, "srv", "#chan")
+	assert.Nil(t, err)
+	assert.Len(t, raw, 2)
+	assert.NotContains(t, raw, "plain")
+	assert.NotContains(t, raw, "secret")
+}
+
+func TestIsSensitiveCommand(t *testing.T) {
+	sensitive := []string{
+		"/msg NickServ identify hunter2",
+		"/msg nickserv IDENTIFY nick hunter2",
+		"/msg NickServ@services.example.org identify hunter2",
+		"/query nickserv register hunter2 me@example.org",
+		"/ns id hunter2",
+		"/nickserv ghost nick hunter2",
+		"/ns set password hunter2",
+		"/oper admin hunter2",
+		"/pass hunter2",
+		"/quote PASS hunter2",
+		"/raw PRIVMSG NickServ :IDENTIFY hunter2",
+		"/identify hunter2",
+	}
+	for _, line := range sensitive {
+		assert.True(t, storage.IsSensitiveCommand(line), line)
+	}
+
+	safe := []string{
+		"identify hunter2",
+		"/msg nick identify hunter2",
+		"/msg nickserv",
+		"/ns info nick",
+		"/ns set email me@example.org",
+		"/join #chan",
+		"/quote",
+		"/",
+	}
+	for _, line := range safe {
+		assert.False(t, storage.IsSensitiveCommand(line), line)
+	}
+}
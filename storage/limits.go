@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrServerLimit  = errors.New("You have reached the maximum number of servers")
+	ErrChannelLimit = errors.New("You have reached the maximum number of channels")
+)
+
+// Limits caps how many servers and channels a user can have,
+// 0 means unlimited
+type Limits struct {
+	Servers  int
+	Channels int
+}
+
+// GetLimits returns the limits that apply to a user
+var GetLimits = func(user *User) Limits {
+	return Limits{}
+}
+
+// TruncatedMarker ends messages that were cut short by LimitMessage
+const TruncatedMarker = "…"
+
+// GetMessageLimit returns the maximum length in bytes of stored messages,
+// 0 means messages get stored in full
+var GetMessageLimit = func() int {
+	return 0
+}
+
+// LimitMessage truncates content to the message limit
+func LimitMessage(content string) string {
+	return truncateMessage(content, GetMessageLimit())
+}
+
+// truncateMessage cuts content down to at most max bytes including the
+// marker, without splitting a multibyte character
+func truncateMessage(content string, max int) string {
+	if max <= 0 || len(content) <= max {
+		return content
+	}
+
+	end := max - len(TruncatedMarker)
+	if end < 0 {
+		end = 0
+	}
+	for end > 0 && !utf8.RuneStart(content[end]) {
+		end--
+	}
+
+	return content[:end] + TruncatedMarker
+}
+
+func (u *User) checkServerLimit(host string) error {
+	limit := GetLimits(u).Servers
+	if limit <= 0 {
+		return nil
+	}
+
+	servers, err := u.GetServers()
+	if err != nil {
+		return err
+	}
+
+	for _, server := range servers {
+		if server.Host == host {
+			return nil
+		}
+	}
+	if len(servers) >= limit {
+		return ErrServerLimit
+	}
+	return nil
+}
+
+// checkChannelLimit returns the name the channel is stored with, channel
+// names are compared case-insensitively
+func (u *User) checkChannelLimit(server, name string) (string, error) {
+	limit := GetLimits(u).Channels
+	if limit <= 0 {
+		return name, nil
+	}
+
+	channels, err := u.GetChannels()
+	if err != nil {
+		return name, err
+	}
+
+	for _, channel := range channels {
+		if channel.Server == server && strings.EqualFold(channel.Name, name) {
+			return channel.Name, nil
+		}
+	}
+	if len(channels) >= limit {
+		return name, ErrChannelLimit
+	}
+	return name, nil
+}
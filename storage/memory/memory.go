@@ -0,0 +1,746 @@
+package memory
+
+import (
+	"encoding/binary"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/khlieng/dispatch/pkg/session"
+	"github.com/khlieng/dispatch/storage"
+)
+
+// MemoryStore implements storage.Store, storage.MessageStore and
+// storage.SessionStore without persisting anything, everything is gone
+// when the process exits
+type MemoryStore struct {
+	users    map[uint64][]byte
+	userSeq  uint64
+	data     map[uint64]*userData
+	messages map[string]*sortedLog
+	topics   map[string]*sortedLog
+	sessions map[string][]byte
+	lock     sync.Mutex
+}
+
+type userData struct {
+	servers  map[string][]byte
+	channels map[string][]byte
+	openDMs  map[string]storage.Tab
+	aliases  map[string]string
+	history  map[string][]string
+}
+
+func New() *MemoryStore {
+	return &MemoryStore{
+		users:    map[uint64][]byte{},
+		data:     map[uint64]*userData{},
+		messages: map[string]*sortedLog{},
+		topics:   map[string]*sortedLog{},
+		sessions: map[string][]byte{},
+	}
+}
+
+func (s *MemoryStore) userData(user *storage.User) *userData {
+	d, ok := s.data[user.ID]
+	if !ok {
+		d = &userData{
+			servers:  map[string][]byte{},
+			channels: map[string][]byte{},
+			openDMs:  map[string]storage.Tab{},
+			aliases:  map[string]string{},
+			history:  map[string][]string{},
+		}
+		s.data[user.ID] = d
+	}
+	return d
+}
+
+func (s *MemoryStore) Close() {}
+
+func (s *MemoryStore) GetUsers() ([]*storage.User, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	ids := make([]uint64, 0, len(s.users))
+	for id := range s.users {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var users []*storage.User
+	for _, id := range ids {
+		user := storage.User{}
+		unmarshal(&user, s.users[id])
+		user.IDBytes = idToBytes(id)
+		users = append(users, &user)
+	}
+
+	return users, nil
+}
+
+func (s *MemoryStore) SaveUser(user *storage.User) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if user.ID == 0 {
+		s.userSeq++
+		user.ID = s.userSeq
+		user.IDBytes = idToBytes(user.ID)
+	} else if user.ID > s.userSeq {
+		s.userSeq = user.ID
+	}
+	if user.Username == "" {
+		user.Username = strconv.FormatUint(user.ID, 10)
+	}
+
+	data, err := user.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	s.users[user.ID] = data
+	return nil
+}
+
+func (s *MemoryStore) DeleteUser(user *storage.User) error {
+	s.lock.Lock()
+	delete(s.users, user.ID)
+	delete(s.data, user.ID)
+	s.lock.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) GetServer(user *storage.User, address string) (*storage.Server, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	v, ok := s.userData(user).servers[address]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+
+	server := &storage.Server{}
+	unmarshal(server, v)
+	return server, nil
+}
+
+func (s *MemoryStore) GetServers(user *storage.User) ([]*storage.Server, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	d := s.userData(user)
+
+	var servers []*storage.Server
+	for _, key := range sortedKeys(d.servers) {
+		server := storage.Server{}
+		unmarshal(&server, d.servers[key])
+		servers = append(servers, &server)
+	}
+
+	return servers, nil
+}
+
+func (s *MemoryStore) SaveServer(user *storage.User, server *storage.Server) error {
+	data, err := server.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	s.userData(user).servers[server.Host] = data
+	s.lock.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) RemoveServer(user *storage.User, address string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	d := s.userData(user)
+
+	delete(d.servers, address)
+
+	prefix := address + "\x00"
+	for key := range d.channels {
+		if strings.HasPrefix(key, prefix) {
+			delete(d.channels, key)
+		}
+	}
+	for key := range d.openDMs {
+		if strings.HasPrefix(key, prefix) {
+			delete(d.openDMs, key)
+		}
+	}
+	for key := range d.history {
+		if strings.HasPrefix(key, prefix) {
+			delete(d.history, key)
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) GetChannels(user *storage.User) ([]*storage.Channel, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	d := s.userData(user)
+
+	var channels []*storage.Channel
+	for _, key := range sortedKeys(d.channels) {
+		channel := storage.Channel{}
+		unmarshal(&channel, d.channels[key])
+		channels = append(channels, &channel)
+	}
+
+	return channels, nil
+}
+
+func (s *MemoryStore) AddChannel(user *storage.User, channel *storage.Channel) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	channels := s.userData(user).channels
+	id := channelID(channel.Server, channel.Name)
+
+	// Rejoining a channel keeps its place in the sidebar
+	ch := *channel
+	if v, ok := channels[id]; ok {
+		existing := storage.Channel{}
+		unmarshal(&existing, v)
+		ch.Order = existing.Order
+		ch.Group = existing.Group
+		ch.Color = existing.Color
+		ch.Label = existing.Label
+		ch.Lazy = existing.Lazy
+	}
+
+	data, err := ch.Marshal(nil)
+	if err != nil {
+		return err
+	}
+	channels[id] = data
+	return nil
+}
+
+func (s *MemoryStore) RemoveChannel(user *storage.User, server, channel string) error {
+	s.lock.Lock()
+	delete(s.userData(user).channels, channelID(server, channel))
+	s.lock.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) SetOrder(user *storage.User, placements []storage.Placement) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	d := s.userData(user)
+
+	for _, p := range placements {
+		if p.Channel == "" {
+			v, ok := d.servers[p.Server]
+			if !ok {
+				continue
+			}
+
+			server := storage.Server{}
+			unmarshal(&server, v)
+			server.Order = p.Order
+			server.Group = p.Group
+
+			data, _ := server.Marshal(nil)
+			d.servers[p.Server] = data
+		} else {
+			id := channelID(p.Server, p.Channel)
+			v, ok := d.channels[id]
+			if !ok {
+				continue
+			}
+
+			channel := storage.Channel{}
+			unmarshal(&channel, v)
+			channel.Order = p.Order
+			channel.Group = p.Group
+
+			data, _ := channel.Marshal(nil)
+			d.channels[id] = data
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) SetAppearance(user *storage.User, appearance storage.Appearance) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	d := s.userData(user)
+
+	if appearance.Channel == "" {
+		v, ok := d.servers[appearance.Server]
+		if !ok {
+			return storage.ErrNotFound
+		}
+
+		server := storage.Server{}
+		unmarshal(&server, v)
+		server.Color = appearance.Color
+		server.Label = appearance.Label
+
+		data, _ := server.Marshal(nil)
+		d.servers[appearance.Server] = data
+		return nil
+	}
+
+	id := channelID(appearance.Server, appearance.Channel)
+	v, ok := d.channels[id]
+	if !ok {
+		return storage.ErrNotFound
+	}
+
+	channel := storage.Channel{}
+	unmarshal(&channel, v)
+	channel.Color = appearance.Color
+	channel.Label = appearance.Label
+
+	data, _ := channel.Marshal(nil)
+	d.channels[id] = data
+	return nil
+}
+
+func (s *MemoryStore) SetChannelLazy(user *storage.User, server, channel string, lazy bool) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	d := s.userData(user)
+	id := channelID(server, channel)
+
+	v, ok := d.channels[id]
+	if !ok {
+		return storage.ErrNotFound
+	}
+
+	ch := storage.Channel{}
+	unmarshal(&ch, v)
+	ch.Lazy = lazy
+
+	data, _ := ch.Marshal(nil)
+	d.channels[id] = data
+	return nil
+}
+
+func (s *MemoryStore) GetOpenDMs(user *storage.User) ([]storage.Tab, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var openDMs []storage.Tab
+	for _, tab := range s.userData(user).openDMs {
+		openDMs = append(openDMs, tab)
+	}
+	sort.Slice(openDMs, func(i, j int) bool {
+		return channelID(openDMs[i].Server, openDMs[i].Name) < channelID(openDMs[j].Server, openDMs[j].Name)
+	})
+
+	return openDMs, nil
+}
+
+func (s *MemoryStore) AddOpenDM(user *storage.User, server, nick string) error {
+	s.lock.Lock()
+	s.userData(user).openDMs[channelID(server, nick)] = storage.Tab{
+		Server: server,
+		Name:   nick,
+	}
+	s.lock.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) RemoveOpenDM(user *storage.User, server, nick string) error {
+	s.lock.Lock()
+	delete(s.userData(user).openDMs, channelID(server, nick))
+	s.lock.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) GetAliases(user *storage.User) (map[string]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	aliases := map[string]string{}
+	for name, expansion := range s.userData(user).aliases {
+		aliases[name] = expansion
+	}
+
+	return aliases, nil
+}
+
+func (s *MemoryStore) SetAlias(user *storage.User, name, expansion string) error {
+	s.lock.Lock()
+	s.userData(user).aliases[name] = expansion
+	s.lock.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) RemoveAlias(user *storage.User, name string) error {
+	s.lock.Lock()
+	delete(s.userData(user).aliases, name)
+	s.lock.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) GetCommandHistory(user *storage.User, server, channel string) ([]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	history := s.userData(user).history[channelID(server, channel)]
+	return append([]string(nil), history...), nil
+}
+
+func (s *MemoryStore) SetCommandHistory(user *storage.User, server, channel string, history []string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	d := s.userData(user)
+	if len(history) == 0 {
+		delete(d.history, channelID(server, channel))
+	} else {
+		d.history[channelID(server, channel)] = append([]string(nil), history...)
+	}
+	return nil
+}
+
+func (s *MemoryStore) logMessage(message *storage.Message) error {
+	data, err := message.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	key := message.Server + ":" + message.To
+	log, ok := s.messages[key]
+	if !ok {
+		log = &sortedLog{}
+		s.messages[key] = log
+	}
+	log.put(message.ID, data)
+	return nil
+}
+
+func (s *MemoryStore) LogMessage(message *storage.Message) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.logMessage(message)
+}
+
+func (s *MemoryStore) LogMessages(messages []*storage.Message) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, message := range messages {
+		err := s.logMessage(message)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ForEachMessage calls fn with every message
+func (s *MemoryStore) ForEachMessage(fn func(*storage.Message) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for key, log := range s.messages {
+		sep := strings.LastIndexByte(key, ':')
+		for _, data := range log.data {
+			var message storage.Message
+			unmarshal(&message, data)
+			message.Server = key[:sep]
+			message.To = key[sep+1:]
+
+			if err := fn(&message); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RenameChannel moves the messages and topics of a channel to a new name,
+// they get merged with any that are already stored under the new name
+func (s *MemoryStore) RenameChannel(server, from, to string) error {
+	if from == to {
+		return nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, logs := range []map[string]*sortedLog{s.messages, s.topics} {
+		old, ok := logs[server+":"+from]
+		if !ok {
+			continue
+		}
+		delete(logs, server+":"+from)
+
+		log, ok := logs[server+":"+to]
+		if !ok {
+			logs[server+":"+to] = old
+			continue
+		}
+		for i, id := range old.ids {
+			log.put(id, old.data[i])
+		}
+	}
+	return nil
+}
+
+// GetMessages returns up to count messages before fromID, or the latest
+// messages if fromID is empty, hasMore is set if there are older messages
+func (s *MemoryStore) GetMessages(server, channel string, count int, fromID string) ([]storage.Message, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	log, ok := s.messages[server+":"+channel]
+	if !ok {
+		return nil, false, nil
+	}
+
+	end := len(log.ids)
+	if fromID != "" {
+		end = log.search(fromID)
+	}
+
+	start := end - count
+	if start < 0 {
+		start = 0
+	}
+	if start == end {
+		return nil, false, nil
+	}
+
+	messages := make([]storage.Message, end-start)
+	for i := range messages {
+		unmarshal(&messages[i], log.data[start+i])
+	}
+
+	return messages, start > 0, nil
+}
+
+func (s *MemoryStore) GetMessagesByID(server, channel string, ids []string) ([]storage.Message, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	log := s.messages[server+":"+channel]
+	messages := make([]storage.Message, len(ids))
+
+	for i, id := range ids {
+		if data, ok := log.get(id); ok {
+			unmarshal(&messages[i], data)
+		}
+	}
+
+	return messages, nil
+}
+
+func (s *MemoryStore) GetMessageContext(server, channel, id string, count int) ([]storage.Message, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	log, ok := s.messages[server+":"+channel]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+
+	i := log.search(id)
+	if i == len(log.ids) || log.ids[i] != id {
+		return nil, storage.ErrNotFound
+	}
+
+	start := i - count
+	if start < 0 {
+		start = 0
+	}
+	end := i + count + 1
+	if end > len(log.ids) {
+		end = len(log.ids)
+	}
+
+	messages := make([]storage.Message, end-start)
+	for j := range messages {
+		unmarshal(&messages[j], log.data[start+j])
+	}
+
+	return messages, nil
+}
+
+// GetMessageIDAt seeks to t using the time every message ID starts with
+func (s *MemoryStore) GetMessageIDAt(server, channel string, t time.Time) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	log, ok := s.messages[server+":"+channel]
+	if !ok {
+		return "", storage.ErrNotFound
+	}
+
+	i := log.search(storage.MessageIDPrefix(t))
+	if i == len(log.ids) {
+		return "", storage.ErrNotFound
+	}
+
+	return log.ids[i], nil
+}
+
+func (s *MemoryStore) LogTopic(topic *storage.Topic) error {
+	data, err := topic.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := topic.Server + ":" + topic.Channel
+	log, ok := s.topics[key]
+	if !ok {
+		log = &sortedLog{}
+		s.topics[key] = log
+	}
+	log.put(topic.ID, data)
+	return nil
+}
+
+func (s *MemoryStore) GetTopics(server, channel string, count int, fromID string) ([]storage.Topic, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	log, ok := s.topics[server+":"+channel]
+	if !ok {
+		return []storage.Topic{}, false, nil
+	}
+
+	end := len(log.ids)
+	if fromID != "" {
+		end = log.search(fromID)
+	}
+
+	start := end - count
+	if start < 0 {
+		start = 0
+	}
+
+	topics := make([]storage.Topic, end-start)
+	for i := range topics {
+		topics[i].Unmarshal(log.data[start+i])
+		topics[i].Server = server
+		topics[i].Channel = channel
+	}
+
+	return topics, start > 0, nil
+}
+
+func (s *MemoryStore) GetSessions() ([]*session.Session, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var sessions []*session.Session
+	for _, key := range sortedKeys(s.sessions) {
+		session := session.Session{}
+		_, err := session.Unmarshal(s.sessions[key])
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &session)
+	}
+
+	return sessions, nil
+}
+
+func (s *MemoryStore) SaveSession(session *session.Session) error {
+	data, err := session.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	s.sessions[session.Key()] = data
+	s.lock.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) DeleteSession(key string) error {
+	s.lock.Lock()
+	delete(s.sessions, key)
+	s.lock.Unlock()
+	return nil
+}
+
+// sortedLog keeps records ordered by ID, like a bolt bucket
+type sortedLog struct {
+	ids  []string
+	data [][]byte
+}
+
+// search returns the index of the first ID that is not less than id
+func (l *sortedLog) search(id string) int {
+	return sort.SearchStrings(l.ids, id)
+}
+
+func (l *sortedLog) get(id string) ([]byte, bool) {
+	if l == nil {
+		return nil, false
+	}
+
+	i := l.search(id)
+	if i < len(l.ids) && l.ids[i] == id {
+		return l.data[i], true
+	}
+	return nil, false
+}
+
+func (l *sortedLog) put(id string, data []byte) {
+	i := l.search(id)
+	if i < len(l.ids) && l.ids[i] == id {
+		l.data[i] = data
+		return
+	}
+
+	l.ids = append(l.ids, "")
+	copy(l.ids[i+1:], l.ids[i:])
+	l.ids[i] = id
+
+	l.data = append(l.data, nil)
+	copy(l.data[i+1:], l.data[i:])
+	l.data[i] = data
+}
+
+type unmarshaler interface {
+	Unmarshal([]byte) (uint64, error)
+}
+
+func unmarshal(v unmarshaler, data []byte) {
+	v.Unmarshal(data)
+}
+
+func sortedKeys(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func channelID(server, channel string) string {
+	return server + "\x00" + channel
+}
+
+func idToBytes(i uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, i)
+	return b
+}
@@ -0,0 +1,362 @@
+package memory_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/pkg/session"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ storage.Store                 = &memory.MemoryStore{}
+	_ storage.SessionStore          = &memory.MemoryStore{}
+	_ storage.MessageStore          = &memory.MemoryStore{}
+	_ storage.MessageSearchProvider = &memory.Search{}
+	_ storage.MessageIterator       = &memory.MemoryStore{}
+)
+
+func TestMain(m *testing.M) {
+	dir, _ := ioutil.TempDir("", "dispatch-memory")
+	storage.Initialize(dir, "", "")
+	storage.GetMessageStore = func(*storage.User) (storage.MessageStore, error) {
+		return memory.New(), nil
+	}
+	storage.GetMessageSearchProvider = func(*storage.User) (storage.MessageSearchProvider, error) {
+		return memory.NewSearch(), nil
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestUsers(t *testing.T) {
+	s := memory.New()
+
+	user, err := storage.NewUser(s)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), user.ID)
+	assert.Equal(t, "1", user.Username)
+
+	other, err := storage.NewUser(s)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), other.ID)
+
+	users, err := s.GetUsers()
+	assert.Nil(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, uint64(1), users[0].ID)
+	assert.Equal(t, "1", users[0].Username)
+
+	srv := &storage.Server{Name: "Freenode", Host: "irc.freenode.net", Nick: "nick"}
+	assert.Nil(t, user.AddServer(srv))
+
+	server, err := user.GetServer("irc.freenode.net")
+	assert.Nil(t, err)
+	assert.Equal(t, srv, server)
+	_, err = other.GetServer("irc.freenode.net")
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	assert.Nil(t, user.AddChannel(&storage.Channel{Server: "irc.freenode.net", Name: "#go-nuts"}))
+	assert.Nil(t, user.AddChannel(&storage.Channel{Server: "irc.freenode.net", Name: "#dispatch"}))
+	assert.Nil(t, user.SetOrder([]storage.Placement{
+		{Server: "irc.freenode.net", Channel: "#go-nuts", Order: 2, Group: "go"},
+	}))
+	assert.Nil(t, user.SetAppearance(storage.Appearance{
+		Server: "irc.freenode.net", Channel: "#go-nuts", Color: "#fff", Label: "go",
+	}))
+	assert.Equal(t, storage.ErrNotFound, user.SetAppearance(storage.Appearance{
+		Server: "irc.freenode.net", Channel: "#nope", Color: "#fff",
+	}))
+
+	// Rejoining keeps the placement and appearance
+	assert.Nil(t, user.AddChannel(&storage.Channel{Server: "irc.freenode.net", Name: "#go-nuts"}))
+
+	channels, err := user.GetChannels()
+	assert.Nil(t, err)
+	assert.Len(t, channels, 2)
+	assert.Equal(t, "#dispatch", channels[0].Name)
+	assert.Equal(t, &storage.Channel{
+		Server: "irc.freenode.net",
+		Name:   "#go-nuts",
+		Order:  2,
+		Group:  "go",
+		Color:  "#fff",
+		Label:  "go",
+	}, channels[1])
+
+	assert.Nil(t, user.AddOpenDM("irc.freenode.net", "cake"))
+	assert.Nil(t, user.AddOpenDM("irc.freenode.net", "beer"))
+	openDMs, err := user.GetOpenDMs()
+	assert.Nil(t, err)
+	assert.Equal(t, []storage.Tab{
+		{Server: "irc.freenode.net", Name: "beer"},
+		{Server: "irc.freenode.net", Name: "cake"},
+	}, openDMs)
+
+	assert.Nil(t, s.SetAlias(user, "j", "/join"))
+	aliases, err := s.GetAliases(user)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"j": "/join"}, aliases)
+	aliases["k"] = "/kick"
+	aliases, _ = s.GetAliases(user)
+	assert.Len(t, aliases, 1)
+	assert.Nil(t, s.RemoveAlias(user, "j"))
+	aliases, _ = s.GetAliases(user)
+	assert.Empty(t, aliases)
+
+	assert.Nil(t, user.AddCommandHistory("irc.freenode.net", "#go-nuts", "hello"))
+	assert.Nil(t, user.AddCommandHistory("irc.freenode.net", "#go-nuts", "/ns identify pass"))
+	history, err := user.GetCommandHistory("irc.freenode.net", "#go-nuts")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"hello"}, history)
+	history[0] = "changed"
+	history, _ = user.GetCommandHistory("irc.freenode.net", "#go-nuts")
+	assert.Equal(t, []string{"hello"}, history)
+
+	assert.Nil(t, user.RemoveServer("irc.freenode.net"))
+	channels, _ = user.GetChannels()
+	assert.Empty(t, channels)
+	openDMs, _ = user.GetOpenDMs()
+	assert.Empty(t, openDMs)
+	history, _ = user.GetCommandHistory("irc.freenode.net", "#go-nuts")
+	assert.Empty(t, history)
+
+	assert.Nil(t, s.DeleteUser(other))
+	users, _ = s.GetUsers()
+	assert.Len(t, users, 1)
+}
+
+func TestMessages(t *testing.T) {
+	s := memory.New()
+
+	messages, hasMore, err := s.GetMessages("srv", "#chan", 10, "")
+	assert.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Empty(t, messages)
+
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	var ids []string
+	for i := 0; i < 5; i++ {
+		id := storage.MessageIDAt(start.Add(time.Duration(i) * time.Hour))
+		ids = append(ids, id)
+		assert.Nil(t, s.LogMessage(&storage.Message{
+			ID:      id,
+			Server:  "srv",
+			To:      "#chan",
+			From:    "nick",
+			Content: "message" + strconv.Itoa(i),
+		}))
+	}
+
+	messages, hasMore, err = s.GetMessages("srv", "#chan", 10, "")
+	assert.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Len(t, messages, 5)
+	assert.Equal(t, "message0", messages[0].Content)
+	assert.Equal(t, "message4", messages[4].Content)
+
+	messages, hasMore, err = s.GetMessages("srv", "#chan", 2, "")
+	assert.Nil(t, err)
+	assert.True(t, hasMore)
+	assert.Equal(t, "message3", messages[0].Content)
+	assert.Equal(t, "message4", messages[1].Content)
+
+	messages, hasMore, err = s.GetMessages("srv", "#chan", 2, messages[0].ID)
+	assert.Nil(t, err)
+	assert.True(t, hasMore)
+	assert.Equal(t, "message1", messages[0].Content)
+	assert.Equal(t, "message2", messages[1].Content)
+
+	messages, hasMore, err = s.GetMessages("srv", "#chan", 2, messages[0].ID)
+	assert.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "message0", messages[0].Content)
+
+	messages, hasMore, err = s.GetMessages("srv", "#chan", 10, ids[0])
+	assert.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Empty(t, messages)
+
+	messages, err = s.GetMessagesByID("srv", "#chan", []string{ids[3], ids[1]})
+	assert.Nil(t, err)
+	assert.Equal(t, "message3", messages[0].Content)
+	assert.Equal(t, "message1", messages[1].Content)
+
+	messages, err = s.GetMessageContext("srv", "#chan", ids[2], 1)
+	assert.Nil(t, err)
+	assert.Len(t, messages, 3)
+	assert.Equal(t, ids[1], messages[0].ID)
+	assert.Equal(t, ids[3], messages[2].ID)
+
+	messages, err = s.GetMessageContext("srv", "#chan", ids[0], 2)
+	assert.Nil(t, err)
+	assert.Len(t, messages, 3)
+
+	_, err = s.GetMessageContext("srv", "#chan", "nope", 2)
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	id, err := s.GetMessageIDAt("srv", "#chan", start.Add(90*time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, ids[2], id)
+
+	_, err = s.GetMessageIDAt("srv", "#chan", start.Add(24*time.Hour))
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	// Logging a message again replaces it
+	assert.Nil(t, s.LogMessages([]*storage.Message{
+		{ID: ids[4], Server: "srv", To: "#chan", Content: "edited"},
+	}))
+	messages, _, _ = s.GetMessages("srv", "#chan", 10, "")
+	assert.Len(t, messages, 5)
+	assert.Equal(t, "edited", messages[4].Content)
+}
+
+func TestTopics(t *testing.T) {
+	s := memory.New()
+
+	topics, hasMore, err := s.GetTopics("srv", "#chan", 10, "")
+	assert.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Empty(t, topics)
+
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, s.LogTopic(&storage.Topic{
+			ID:      storage.MessageIDAt(time.Unix(int64(i), 0)),
+			Server:  "srv",
+			Channel: "#chan",
+			Topic:   "topic" + strconv.Itoa(i),
+		}))
+	}
+
+	topics, hasMore, err = s.GetTopics("srv", "#chan", 2, "")
+	assert.Nil(t, err)
+	assert.True(t, hasMore)
+	assert.Equal(t, "topic1", topics[0].Topic)
+	assert.Equal(t, "srv", topics[0].Server)
+	assert.Equal(t, "#chan", topics[0].Channel)
+
+	topics, hasMore, err = s.GetTopics("srv", "#chan", 2, topics[0].ID)
+	assert.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Len(t, topics, 1)
+	assert.Equal(t, "topic0", topics[0].Topic)
+}
+
+func TestSessions(t *testing.T) {
+	s := memory.New()
+
+	sess, err := session.New(1)
+	assert.Nil(t, err)
+	assert.Nil(t, s.SaveSession(sess))
+
+	sessions, err := s.GetSessions()
+	assert.Nil(t, err)
+	assert.Len(t, sessions, 1)
+	assert.Equal(t, sess.Key(), sessions[0].Key())
+	assert.Equal(t, uint64(1), sessions[0].UserID)
+
+	assert.Nil(t, s.DeleteSession(sess.Key()))
+	sessions, err = s.GetSessions()
+	assert.Nil(t, err)
+	assert.Empty(t, sessions)
+}
+
+func TestSearch(t *testing.T) {
+	user, err := storage.NewUser(memory.New())
+	assert.Nil(t, err)
+
+	for i, content := range []string{"Hello world", "hello there", "bye world", "HELLO again"} {
+		assert.Nil(t, user.LogMessage(&storage.Message{
+			ID:      storage.MessageIDAt(time.Unix(int64(i), 0)),
+			Server:  "srv",
+			From:    "nick",
+			To:      "#chan",
+			Account: "acc" + strconv.Itoa(i%2),
+			Content: content,
+		}))
+	}
+
+	messages, total, err := user.SearchMessages("srv", "#chan", "hello", 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), total)
+	assert.Equal(t, "HELLO again", messages[0].Content)
+	assert.Equal(t, "Hello world", messages[2].Content)
+
+	messages, total, err = user.SearchMessages("srv", "#chan", "hello", 1, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), total)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "hello there", messages[0].Content)
+
+	messages, total, err = user.SearchMessages("srv", "#chan", "world hello", 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), total)
+	assert.Equal(t, "Hello world", messages[0].Content)
+
+	messages, total, err = user.SearchMessages("srv", "#chan", "account:acc1 hello", 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), total)
+	assert.Equal(t, "HELLO again", messages[0].Content)
+
+	messages, total, err = user.SearchMessages("srv", "#chan", "hello", 5, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), total)
+	assert.Empty(t, messages)
+
+	_, total, err = user.SearchMessages("srv", "#other", "hello", 0, 10)
+	assert.Nil(t, err)
+	assert.Zero(t, total)
+}
+
+func TestForEachMessage(t *testing.T) {
+	s := memory.New()
+	assert.Nil(t, s.LogMessages([]*storage.Message{
+		{ID: "a", Server: "srv", To: "#chan", Content: "one"},
+		{ID: "b", Server: "srv", To: "nick", Content: "two"},
+	}))
+
+	found := map[string]string{}
+	assert.Nil(t, s.ForEachMessage(func(msg *storage.Message) error {
+		found[msg.Server+" "+msg.To] = msg.Content
+		return nil
+	}))
+	assert.Equal(t, map[string]string{
+		"srv #chan": "one",
+		"srv nick":  "two",
+	}, found)
+}
+
+func TestRenameChannel(t *testing.T) {
+	s := memory.New()
+	assert.Nil(t, s.LogMessages([]*storage.Message{
+		{ID: "a", Server: "srv", To: "#old", Content: "one"},
+		{ID: "c", Server: "srv", To: "#old", Content: "three"},
+		{ID: "b", Server: "srv", To: "#new", Content: "two"},
+	}))
+	assert.Nil(t, s.LogTopic(&storage.Topic{ID: "a", Server: "srv", Channel: "#old", Topic: "topic"}))
+
+	assert.Nil(t, s.RenameChannel("srv", "#old", "#new"))
+
+	messages, _, err := s.GetMessages("srv", "#new", 10, "")
+	assert.Nil(t, err)
+	assert.Len(t, messages, 3)
+	for i, content := range []string{"one", "two", "three"} {
+		assert.Equal(t, content, messages[i].Content)
+	}
+
+	messages, _, err = s.GetMessages("srv", "#old", 10, "")
+	assert.Nil(t, err)
+	assert.Empty(t, messages)
+
+	topics, _, err := s.GetTopics("srv", "#new", 10, "")
+	assert.Nil(t, err)
+	assert.Len(t, topics, 1)
+}
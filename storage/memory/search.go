@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+// Search implements storage.MessageSearchProvider, it matches messages
+// containing every term in the query, ignoring case
+type Search struct {
+	messages map[string]map[string]indexedMessage
+	lock     sync.Mutex
+}
+
+type indexedMessage struct {
+	account string
+	content string
+	mention bool
+}
+
+func NewSearch() *Search {
+	return &Search{
+		messages: map[string]map[string]indexedMessage{},
+	}
+}
+
+func (s *Search) Index(id string, message *storage.Message) error {
+	key := message.Server + ":" + message.To
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.messages[key] == nil {
+		s.messages[key] = map[string]indexedMessage{}
+	}
+	s.messages[key][id] = indexedMessage{
+		account: message.Account,
+		content: strings.ToLower(message.Content),
+		mention: message.Mention,
+	}
+	return nil
+}
+
+// SearchMessages supports the same account:name term as the bleve provider
+func (s *Search) SearchMessages(server, channel, q string, offset, limit int) ([]string, uint64, error) {
+	account := ""
+	terms := []string{}
+	for _, term := range strings.Fields(q) {
+		if strings.HasPrefix(term, "account:") && len(term) > 8 {
+			account = term[8:]
+		} else {
+			terms = append(terms, strings.ToLower(term))
+		}
+	}
+
+	s.lock.Lock()
+	var ids []string
+	for id, message := range s.messages[server+":"+channel] {
+		if account != "" && message.account != account {
+			continue
+		}
+		if matchesAll(message.content, terms) {
+			ids = append(ids, id)
+		}
+	}
+	s.lock.Unlock()
+
+	// Newest first, message IDs are ordered by time
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+
+	total := uint64(len(ids))
+	if offset >= len(ids) {
+		return []string{}, total, nil
+	}
+	ids = ids[offset:]
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	return ids, total, nil
+}
+
+// SearchMentions returns the newest messages in channel that mentioned
+// the user
+func (s *Search) SearchMentions(server, channel string, limit int) ([]string, error) {
+	s.lock.Lock()
+	ids := []string{}
+	for id, message := range s.messages[server+":"+channel] {
+		if message.mention {
+			ids = append(ids, id)
+		}
+	}
+	s.lock.Unlock()
+
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+func (s *Search) Close() {}
+
+func matchesAll(content string, terms []string) bool {
+	for _, term := range terms {
+		if !strings.Contains(content, term) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// IsMention returns true if nick appears in content as a word of its own,
+// ignoring case. Nick characters next to it, such as in foo_ or [foo],
+// make it a different nick and do not count.
+func IsMention(content, nick string) bool {
+	if nick == "" {
+		return false
+	}
+
+	content = strings.ToLower(content)
+	nick = strings.ToLower(nick)
+
+	for start := 0; start < len(content); {
+		i := strings.Index(content[start:], nick)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(nick)
+
+		before, _ := utf8.DecodeLastRuneInString(content[:i])
+		after, _ := utf8.DecodeRuneInString(content[end:])
+		if (i == 0 || !isNickChar(before)) && (end == len(content) || !isNickChar(after)) {
+			return true
+		}
+
+		_, size := utf8.DecodeRuneInString(content[i:])
+		start = i + size
+	}
+	return false
+}
+
+func isNickChar(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+		strings.ContainsRune("[]\\`_^{|}-", r)
+}
@@ -0,0 +1,101 @@
+package storage_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/bleve"
+	"github.com/khlieng/dispatch/storage/boltdb"
+	"github.com/kjk/betterguid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsMention(t *testing.T) {
+	cases := []struct {
+		content string
+		nick    string
+		mention bool
+	}{
+		{"nick", "nick", true},
+		{"hey nick", "nick", true},
+		{"nick: hello", "nick", true},
+		{"hello, NICK!", "nick", true},
+		{"(nick)", "nick", true},
+		{"ping [nick]", "[nick]", true},
+		{"hæ nick", "nick", true},
+		{"nickname", "nick", false},
+		{"anick", "nick", false},
+		{"nick_ is here", "nick", false},
+		{"[nick] said", "nick", false},
+		{"nick- nick_", "nick", false},
+		{"nicknick nick", "nick", true},
+		{"", "nick", false},
+		{"anything", "", false},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.mention, storage.IsMention(tc.content, tc.nick), tc.content)
+	}
+}
+
+func TestGetMentions(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return db, nil
+	}
+	storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
+		return bleve.New(storage.Path.Index(user.Username))
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+	os.MkdirAll(storage.Path.User(user.Username), 0700)
+
+	assert.Nil(t, user.AddChannel(&storage.Channel{Server: "irc.freenode.net", Name: "#go-nuts"}))
+	assert.Nil(t, user.AddChannel(&storage.Channel{Server: "irc.libera.chat", Name: "#dispatch"}))
+
+	log := func(server, channel, content, nick string) string {
+		id := betterguid.New()
+		err := user.LogMessage(&storage.Message{
+			ID:      id,
+			Server:  server,
+			From:    "someone",
+			To:      channel,
+			Content: content,
+			Mention: storage.IsMention(content, nick),
+		})
+		assert.Nil(t, err)
+		return id
+	}
+
+	first := log("irc.freenode.net", "#go-nuts", "nick: are you there?", "nick")
+	log("irc.freenode.net", "#go-nuts", "nickname is not a mention", "nick")
+	// The nick changed, older messages keep their mention
+	second := log("irc.libera.chat", "#dispatch", "thanks newnick", "newnick")
+	log("irc.libera.chat", "#dispatch", "nick is the old one", "newnick")
+	third := log("irc.freenode.net", "#go-nuts", "hi NewNick", "newnick")
+
+	mentions, err := user.GetMentions(10)
+	assert.Nil(t, err)
+	assert.Len(t, mentions, 3)
+	assert.Equal(t, third, mentions[0].Message.ID)
+	assert.Equal(t, "irc.freenode.net", mentions[0].Server)
+	assert.Equal(t, "#go-nuts", mentions[0].Channel)
+	assert.Equal(t, "hi NewNick", mentions[0].Message.Content)
+	assert.True(t, mentions[0].Message.Mention)
+	assert.Equal(t, second, mentions[1].Message.ID)
+	assert.Equal(t, "irc.libera.chat", mentions[1].Server)
+	assert.Equal(t, "#dispatch", mentions[1].Channel)
+	assert.Equal(t, first, mentions[2].Message.ID)
+
+	mentions, err = user.GetMentions(2)
+	assert.Nil(t, err)
+	assert.Len(t, mentions, 2)
+	assert.Equal(t, third, mentions[0].Message.ID)
+	assert.Equal(t, second, mentions[1].Message.ID)
+}
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var pasteIDRegex = regexp.MustCompile(`^(\d+)-[A-Za-z0-9_-]{11}$`)
+
+// Paste is a piece of text shared through a link, the content is stored
+// next to it
+type Paste struct {
+	ID string
+	// Name is an optional label or filename hint, pastes are always
+	// served as plain text whatever its extension
+	Name    string
+	Size    int
+	Created int64
+	// Expires is a unix timestamp, 0 means never
+	Expires int64
+}
+
+// Expired returns true if the paste expired at or before now
+func (p *Paste) Expired(now time.Time) bool {
+	return p.Expires > 0 && p.Expires <= now.Unix()
+}
+
+// PasteOwner returns the ID of the user who created the paste with id
+func PasteOwner(id string) (uint64, bool) {
+	m := pasteIDRegex.FindStringSubmatch(id)
+	if m == nil {
+		return 0, false
+	}
+	userID, err := strconv.ParseUint(m[1], 10, 64)
+	return userID, err == nil
+}
+
+// CreatePaste stores content as a paste that expires after ttl, 0 keeps
+// it forever. The ID starts with the ID of the user followed by a random
+// part so a paste can only be found by the ones given a link to it.
+func (u *User) CreatePaste(name string, content []byte, ttl time.Duration) (*Paste, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	paste := &Paste{
+		ID:      strconv.FormatUint(u.ID, 10) + "-" + base64.RawURLEncoding.EncodeToString(buf),
+		Name:    filepath.Base("/" + name),
+		Size:    len(content),
+		Created: now.Unix(),
+	}
+	if paste.Name == "/" {
+		paste.Name = ""
+	}
+	if ttl > 0 {
+		paste.Expires = now.Add(ttl).Unix()
+	}
+
+	meta, err := paste.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(Path.Pastes(u.Username), 0700)
+	if err != nil {
+		return nil, err
+	}
+	err = ioutil.WriteFile(Path.Paste(u.Username, paste.ID), content, 0600)
+	if err != nil {
+		return nil, err
+	}
+	err = ioutil.WriteFile(Path.Paste(u.Username, paste.ID)+".json", meta, 0600)
+	if err != nil {
+		os.Remove(Path.Paste(u.Username, paste.ID))
+		return nil, err
+	}
+
+	return paste, nil
+}
+
+// GetPaste returns the paste with id along with its content, it fails
+// with ErrNotFound if there is no such paste or it has expired
+func (u *User) GetPaste(id string) (*Paste, []byte, error) {
+	if userID, ok := PasteOwner(id); !ok || userID != u.ID {
+		return nil, nil, ErrNotFound
+	}
+
+	paste, err := u.readPaste(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if paste.Expired(time.Now()) {
+		u.DeletePaste(id)
+		return nil, nil, ErrNotFound
+	}
+
+	content, err := ioutil.ReadFile(Path.Paste(u.Username, id))
+	if os.IsNotExist(err) {
+		return nil, nil, ErrNotFound
+	}
+	return paste, content, err
+}
+
+func (u *User) readPaste(id string) (*Paste, error) {
+	meta, err := ioutil.ReadFile(Path.Paste(u.Username, id) + ".json")
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	paste := &Paste{}
+	return paste, paste.UnmarshalJSON(meta)
+}
+
+// DeletePaste removes the paste with id
+func (u *User) DeletePaste(id string) error {
+	if userID, ok := PasteOwner(id); !ok || userID != u.ID {
+		return ErrNotFound
+	}
+
+	os.Remove(Path.Paste(u.Username, id) + ".json")
+	err := os.Remove(Path.Paste(u.Username, id))
+	if os.IsNotExist(err) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// DeleteExpiredPastes removes the pastes that expired at or before now
+// and returns how many there were
+func (u *User) DeleteExpiredPastes(now time.Time) (int, error) {
+	files, err := ioutil.ReadDir(Path.Pastes(u.Username))
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+
+		id := strings.TrimSuffix(file.Name(), ".json")
+		paste, err := u.readPaste(id)
+		if err != nil || !paste.Expired(now) {
+			continue
+		}
+		if u.DeletePaste(id) == nil {
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,90 @@
+package storage_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/boltdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPastes(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+	defer db.Close()
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+	other, err := storage.NewUser(db)
+	assert.Nil(t, err)
+
+	paste, err := user.CreatePaste("../../main.go", []byte("package main\n"), time.Hour)
+	assert.Nil(t, err)
+	assert.Equal(t, "main.go", paste.Name)
+	assert.Equal(t, 13, paste.Size)
+	assert.InDelta(t, time.Now().Add(time.Hour).Unix(), paste.Expires, 5)
+
+	owner, ok := storage.PasteOwner(paste.ID)
+	assert.True(t, ok)
+	assert.Equal(t, user.ID, owner)
+	_, ok = storage.PasteOwner("../" + paste.ID)
+	assert.False(t, ok)
+
+	got, content, err := user.GetPaste(paste.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, paste, got)
+	assert.Equal(t, "package main\n", string(content))
+
+	_, _, err = other.GetPaste(paste.ID)
+	assert.Equal(t, storage.ErrNotFound, err)
+	_, _, err = user.GetPaste(strconv.FormatUint(user.ID, 10) + "-AAAAAAAAAAA")
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	forever, err := user.CreatePaste("", []byte("forever"), 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "", forever.Name)
+	assert.Equal(t, int64(0), forever.Expires)
+
+	n, err := user.DeleteExpiredPastes(time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+
+	n, err = user.DeleteExpiredPastes(time.Now().Add(2 * time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	_, _, err = user.GetPaste(paste.ID)
+	assert.Equal(t, storage.ErrNotFound, err)
+	_, content, err = user.GetPaste(forever.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "forever", string(content))
+
+	assert.Nil(t, user.DeletePaste(forever.ID))
+	assert.Equal(t, storage.ErrNotFound, user.DeletePaste(forever.ID))
+}
+
+func TestPasteExpiredOnRead(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+	defer db.Close()
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+
+	paste, err := user.CreatePaste("", []byte("gone"), time.Nanosecond)
+	assert.Nil(t, err)
+	time.Sleep(time.Second)
+
+	_, _, err = user.GetPaste(paste.ID)
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	n, err := user.DeleteExpiredPastes(time.Now().Add(time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+}
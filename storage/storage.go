@@ -3,6 +3,7 @@ package storage
 import (
 	"errors"
 	"os"
+	"time"
 
 	"github.com/khlieng/dispatch/pkg/session"
 )
@@ -26,8 +27,18 @@ func Initialize(root, dataRoot, configRoot string) {
 	os.MkdirAll(Path.ConfigRoot(), 0700)
 }
 
+// SetDataRoot moves where user data is stored, the config stays where it is
+func SetDataRoot(dataRoot string) {
+	Path.dataRoot = dataRoot
+	os.MkdirAll(Path.DataRoot(), 0700)
+}
+
 var (
-	ErrNotFound = errors.New("no item found")
+	ErrNotFound            = errors.New("no item found")
+	ErrReindexUnsupported  = errors.New("The message store does not support rebuilding the search index")
+	ErrRenameUnsupported   = errors.New("The message store does not support renaming channels")
+	ErrCompactUnsupported  = errors.New("The store does not support compaction")
+	ErrMentionsUnsupported = errors.New("The search provider does not support finding mentions")
 )
 
 type Store interface {
@@ -43,10 +54,20 @@ type Store interface {
 	GetChannels(user *User) ([]*Channel, error)
 	AddChannel(user *User, channel *Channel) error
 	RemoveChannel(user *User, server, channel string) error
+	SetOrder(user *User, placements []Placement) error
+	SetAppearance(user *User, appearance Appearance) error
+	SetChannelLazy(user *User, server, channel string, lazy bool) error
 
 	GetOpenDMs(user *User) ([]Tab, error)
 	AddOpenDM(user *User, server, nick string) error
 	RemoveOpenDM(user *User, server, nick string) error
+
+	GetAliases(user *User) (map[string]string, error)
+	SetAlias(user *User, name, expansion string) error
+	RemoveAlias(user *User, name string) error
+
+	GetCommandHistory(user *User, server, channel string) ([]string, error)
+	SetCommandHistory(user *User, server, channel string, history []string) error
 }
 
 type SessionStore interface {
@@ -60,15 +81,90 @@ type MessageStore interface {
 	LogMessages(messages []*Message) error
 	GetMessages(server, channel string, count int, fromID string) ([]Message, bool, error)
 	GetMessagesByID(server, channel string, ids []string) ([]Message, error)
+	GetMessageContext(server, channel, id string, count int) ([]Message, error)
+	// GetMessageIDAt returns the ID of the first message sent at or after t
+	GetMessageIDAt(server, channel string, t time.Time) (string, error)
+	LogTopic(topic *Topic) error
+	GetTopics(server, channel string, count int, fromID string) ([]Topic, bool, error)
 	Close()
 }
 
 type MessageStoreCreator func(*User) (MessageStore, error)
 
+// MessageIterator is implemented by message stores that can go through
+// every message they hold, it is needed to rebuild the search index
+type MessageIterator interface {
+	ForEachMessage(fn func(*Message) error) error
+}
+
+// MessageStreamer is implemented by message stores that can go through
+// the messages of a channel without holding all of them in memory, it is
+// used for log downloads
+type MessageStreamer interface {
+	// StreamMessages calls fn with the messages of channel sent at or after
+	// from, oldest first, until it runs out of messages or fn returns false
+	StreamMessages(server, channel string, from time.Time, fn func(*Message) bool) error
+}
+
+// ChannelRenamer is implemented by message stores that can move the
+// messages and topics of a channel to a new name
+type ChannelRenamer interface {
+	RenameChannel(server, from, to string) error
+}
+
+// Compacter is implemented by stores that can rewrite their files to give
+// the space left behind by deleted data back to the filesystem
+type Compacter interface {
+	Compact() (CompactStats, error)
+}
+
+// CompactStats describes a finished compaction, the sizes are in bytes
+type CompactStats struct {
+	Duration   time.Duration
+	SizeBefore int64
+	SizeAfter  int64
+}
+
+// Reclaimed returns how many bytes the compaction freed up
+func (s CompactStats) Reclaimed() int64 {
+	return s.SizeBefore - s.SizeAfter
+}
+
 type MessageSearchProvider interface {
-	SearchMessages(server, channel, q string) ([]string, error)
+	// SearchMessages returns up to limit IDs of matching messages starting
+	// at offset, newest first, along with the total number of matches
+	SearchMessages(server, channel, q string, offset, limit int) ([]string, uint64, error)
 	Index(id string, message *Message) error
 	Close()
 }
 
 type MessageSearchProviderCreator func(*User) (MessageSearchProvider, error)
+
+// MessageHighlighter is implemented by search providers that can find
+// where a query matches in the content of a message
+type MessageHighlighter interface {
+	Highlight(content, q string) Snippet
+}
+
+// MentionSearcher is implemented by search providers that index whether a
+// message mentioned the user
+type MentionSearcher interface {
+	// SearchMentions returns the IDs of up to limit messages in channel
+	// that mentioned the user, newest first
+	SearchMentions(server, channel string, limit int) ([]string, error)
+}
+
+// Snippet is the part of the content of a message around the matches of a
+// search, Start is its byte offset into the content and the highlights are
+// relative to Text
+type Snippet struct {
+	Text       string
+	Start      int
+	Highlights []Highlight
+}
+
+// Highlight is the byte range of a match in the Text of a snippet
+type Highlight struct {
+	Start int
+	End   int
+}
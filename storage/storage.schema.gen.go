@@ -53,7 +53,82 @@ func (d *User) Size() (s uint64) {
 		}
 		s += l
 	}
-	s += 9
+	{
+		l := uint64(len(d.timezone))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.timeFormat))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.quitMessage))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.partMessage))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.showMOTD))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	s += 12
 	return
 }
 func (d *User) Marshal(buf []byte) ([]byte, error) {
@@ -126,7 +201,123 @@ func (d *User) Marshal(buf []byte) ([]byte, error) {
 		copy(buf[i+9:], d.lastIP)
 		i += l
 	}
-	return buf[:i+9], nil
+	{
+		l := uint64(len(d.timezone))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+9] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+9] = byte(t)
+			i++
+
+		}
+		copy(buf[i+9:], d.timezone)
+		i += l
+	}
+	{
+		l := uint64(len(d.timeFormat))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+9] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+9] = byte(t)
+			i++
+
+		}
+		copy(buf[i+9:], d.timeFormat)
+		i += l
+	}
+	{
+		if d.admin {
+			buf[i+9] = 1
+		} else {
+			buf[i+9] = 0
+		}
+	}
+	{
+		if d.alwaysOn {
+			buf[i+10] = 1
+		} else {
+			buf[i+10] = 0
+		}
+	}
+	{
+		l := uint64(len(d.quitMessage))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+11] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+11] = byte(t)
+			i++
+
+		}
+		copy(buf[i+11:], d.quitMessage)
+		i += l
+	}
+	{
+		l := uint64(len(d.partMessage))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+11] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+11] = byte(t)
+			i++
+
+		}
+		copy(buf[i+11:], d.partMessage)
+		i += l
+	}
+	{
+		if d.readReceipts {
+			buf[i+11] = 1
+		} else {
+			buf[i+11] = 0
+		}
+	}
+	{
+		l := uint64(len(d.showMOTD))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+12] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+12] = byte(t)
+			i++
+
+		}
+		copy(buf[i+12:], d.showMOTD)
+		i += l
+	}
+	return buf[:i+12], nil
 }
 
 func (d *User) Unmarshal(buf []byte) (uint64, error) {
@@ -200,7 +391,116 @@ func (d *User) Unmarshal(buf []byte) (uint64, error) {
 		copy(d.lastIP, buf[i+9:])
 		i += l
 	}
-	return i + 9, nil
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+9] & 0x7F)
+			for buf[i+9]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+9]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.timezone = string(buf[i+9 : i+9+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+9] & 0x7F)
+			for buf[i+9]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+9]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.timeFormat = string(buf[i+9 : i+9+l])
+		i += l
+	}
+	{
+		d.admin = buf[i+9] == 1
+	}
+	{
+		d.alwaysOn = buf[i+10] == 1
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+11] & 0x7F)
+			for buf[i+11]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+11]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.quitMessage = string(buf[i+11 : i+11+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+11] & 0x7F)
+			for buf[i+11]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+11]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.partMessage = string(buf[i+11 : i+11+l])
+		i += l
+	}
+	{
+		d.readReceipts = buf[i+11] == 1
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+12] & 0x7F)
+			for buf[i+12]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+12]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.showMOTD = string(buf[i+12 : i+12+l])
+		i += l
+	}
+	return i + 12, nil
 }
 
 func (d *ClientSettings) Size() (s uint64) {
@@ -375,214 +675,1147 @@ func (d *Server) Size() (s uint64) {
 		}
 		s += l
 	}
-	s += 1
+	{
+		l := uint64(len(d.Group))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Commands))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Commands {
+
+			{
+				l := uint64(len(d.Commands[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	{
+		l := uint64(len(d.Color))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Label))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.OperName))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.OperPassword))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.ForwardTags))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.ForwardTags {
+
+			{
+				l := uint64(len(d.ForwardTags[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	{
+		l := uint64(len(d.SendTags))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.SendTags {
+
+			{
+				l := uint64(len(d.SendTags[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	{
+		l := uint64(len(d.TLSServerName))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.ALPN))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.ALPN {
+
+			{
+				l := uint64(len(d.ALPN[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	{
+		l := uint64(len(d.MOTDHash))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.PinnedCerts))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.PinnedCerts {
+
+			{
+				l := uint64(len(d.PinnedCerts[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	s += 5
 	return
 }
 func (d *Server) Marshal(buf []byte) ([]byte, error) {
 	size := d.Size()
 	{
-		if uint64(cap(buf)) >= size {
-			buf = buf[:size]
-		} else {
-			buf = make([]byte, size)
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.Name))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Name)
+		i += l
+	}
+	{
+		l := uint64(len(d.Host))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Host)
+		i += l
+	}
+	{
+		l := uint64(len(d.Port))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Port)
+		i += l
+	}
+	{
+		if d.TLS {
+			buf[i+0] = 1
+		} else {
+			buf[i+0] = 0
+		}
+	}
+	{
+		l := uint64(len(d.ServerPassword))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+1] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+1] = byte(t)
+			i++
+
+		}
+		copy(buf[i+1:], d.ServerPassword)
+		i += l
+	}
+	{
+		l := uint64(len(d.Nick))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+1] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+1] = byte(t)
+			i++
+
+		}
+		copy(buf[i+1:], d.Nick)
+		i += l
+	}
+	{
+		l := uint64(len(d.Username))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+1] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+1] = byte(t)
+			i++
+
+		}
+		copy(buf[i+1:], d.Username)
+		i += l
+	}
+	{
+		l := uint64(len(d.Realname))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+1] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+1] = byte(t)
+			i++
+
+		}
+		copy(buf[i+1:], d.Realname)
+		i += l
+	}
+	{
+		l := uint64(len(d.Account))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+1] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+1] = byte(t)
+			i++
+
+		}
+		copy(buf[i+1:], d.Account)
+		i += l
+	}
+	{
+		l := uint64(len(d.Password))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+1] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+1] = byte(t)
+			i++
+
+		}
+		copy(buf[i+1:], d.Password)
+		i += l
+	}
+	{
+
+		*(*int32)(unsafe.Pointer(&buf[i+1])) = d.Order
+
+	}
+	{
+		l := uint64(len(d.Group))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+5] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+5] = byte(t)
+			i++
+
+		}
+		copy(buf[i+5:], d.Group)
+		i += l
+	}
+	{
+		l := uint64(len(d.Commands))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+5] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+5] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Commands {
+
+			{
+				l := uint64(len(d.Commands[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+5] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+5] = byte(t)
+					i++
+
+				}
+				copy(buf[i+5:], d.Commands[k0])
+				i += l
+			}
+
+		}
+	}
+	{
+		l := uint64(len(d.Color))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+5] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+5] = byte(t)
+			i++
+
+		}
+		copy(buf[i+5:], d.Color)
+		i += l
+	}
+	{
+		l := uint64(len(d.Label))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+5] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+5] = byte(t)
+			i++
+
+		}
+		copy(buf[i+5:], d.Label)
+		i += l
+	}
+	{
+		l := uint64(len(d.OperName))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+5] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+5] = byte(t)
+			i++
+
+		}
+		copy(buf[i+5:], d.OperName)
+		i += l
+	}
+	{
+		l := uint64(len(d.OperPassword))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+5] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+5] = byte(t)
+			i++
+
+		}
+		copy(buf[i+5:], d.OperPassword)
+		i += l
+	}
+	{
+		l := uint64(len(d.ForwardTags))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+5] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+5] = byte(t)
+			i++
+
+		}
+		for k0 := range d.ForwardTags {
+
+			{
+				l := uint64(len(d.ForwardTags[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+5] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+5] = byte(t)
+					i++
+
+				}
+				copy(buf[i+5:], d.ForwardTags[k0])
+				i += l
+			}
+
+		}
+	}
+	{
+		l := uint64(len(d.SendTags))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+5] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+5] = byte(t)
+			i++
+
+		}
+		for k0 := range d.SendTags {
+
+			{
+				l := uint64(len(d.SendTags[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+5] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+5] = byte(t)
+					i++
+
+				}
+				copy(buf[i+5:], d.SendTags[k0])
+				i += l
+			}
+
 		}
 	}
-	i := uint64(0)
-
 	{
-		l := uint64(len(d.Name))
+		l := uint64(len(d.TLSServerName))
 
 		{
 
 			t := uint64(l)
 
 			for t >= 0x80 {
-				buf[i+0] = byte(t) | 0x80
+				buf[i+5] = byte(t) | 0x80
 				t >>= 7
 				i++
 			}
-			buf[i+0] = byte(t)
+			buf[i+5] = byte(t)
 			i++
 
 		}
-		copy(buf[i+0:], d.Name)
+		copy(buf[i+5:], d.TLSServerName)
 		i += l
 	}
 	{
-		l := uint64(len(d.Host))
+		l := uint64(len(d.ALPN))
 
 		{
 
 			t := uint64(l)
 
 			for t >= 0x80 {
-				buf[i+0] = byte(t) | 0x80
+				buf[i+5] = byte(t) | 0x80
 				t >>= 7
 				i++
 			}
-			buf[i+0] = byte(t)
+			buf[i+5] = byte(t)
 			i++
 
 		}
-		copy(buf[i+0:], d.Host)
+		for k0 := range d.ALPN {
+
+			{
+				l := uint64(len(d.ALPN[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+5] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+5] = byte(t)
+					i++
+
+				}
+				copy(buf[i+5:], d.ALPN[k0])
+				i += l
+			}
+
+		}
+	}
+	{
+		l := uint64(len(d.MOTDHash))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+5] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+5] = byte(t)
+			i++
+
+		}
+		copy(buf[i+5:], d.MOTDHash)
 		i += l
 	}
 	{
-		l := uint64(len(d.Port))
+		l := uint64(len(d.PinnedCerts))
 
 		{
 
 			t := uint64(l)
 
 			for t >= 0x80 {
-				buf[i+0] = byte(t) | 0x80
+				buf[i+5] = byte(t) | 0x80
 				t >>= 7
 				i++
 			}
-			buf[i+0] = byte(t)
+			buf[i+5] = byte(t)
+			i++
+
+		}
+		for k0 := range d.PinnedCerts {
+
+			{
+				l := uint64(len(d.PinnedCerts[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+5] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+5] = byte(t)
+					i++
+
+				}
+				copy(buf[i+5:], d.PinnedCerts[k0])
+				i += l
+			}
+
+		}
+	}
+	return buf[:i+5], nil
+}
+
+func (d *Server) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Name = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Host = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Port = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		d.TLS = buf[i+0] == 1
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+1] & 0x7F)
+			for buf[i+1]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+1]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.ServerPassword = string(buf[i+1 : i+1+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+1] & 0x7F)
+			for buf[i+1]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+1]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Nick = string(buf[i+1 : i+1+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+1] & 0x7F)
+			for buf[i+1]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+1]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Username = string(buf[i+1 : i+1+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+1] & 0x7F)
+			for buf[i+1]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+1]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Realname = string(buf[i+1 : i+1+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+1] & 0x7F)
+			for buf[i+1]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+1]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Account = string(buf[i+1 : i+1+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+1] & 0x7F)
+			for buf[i+1]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+1]&0x7F) << bs
+				bs += 7
+			}
 			i++
 
+			l = t
+
 		}
-		copy(buf[i+0:], d.Port)
+		d.Password = string(buf[i+1 : i+1+l])
 		i += l
 	}
 	{
-		if d.TLS {
-			buf[i+0] = 1
-		} else {
-			buf[i+0] = 0
-		}
+
+		d.Order = *(*int32)(unsafe.Pointer(&buf[i+1]))
+
 	}
 	{
-		l := uint64(len(d.ServerPassword))
+		l := uint64(0)
 
 		{
 
-			t := uint64(l)
-
-			for t >= 0x80 {
-				buf[i+1] = byte(t) | 0x80
-				t >>= 7
+			bs := uint8(7)
+			t := uint64(buf[i+5] & 0x7F)
+			for buf[i+5]&0x80 == 0x80 {
 				i++
+				t |= uint64(buf[i+5]&0x7F) << bs
+				bs += 7
 			}
-			buf[i+1] = byte(t)
 			i++
 
+			l = t
+
 		}
-		copy(buf[i+1:], d.ServerPassword)
+		d.Group = string(buf[i+5 : i+5+l])
 		i += l
 	}
 	{
-		l := uint64(len(d.Nick))
+		l := uint64(0)
 
 		{
 
-			t := uint64(l)
-
-			for t >= 0x80 {
-				buf[i+1] = byte(t) | 0x80
-				t >>= 7
+			bs := uint8(7)
+			t := uint64(buf[i+5] & 0x7F)
+			for buf[i+5]&0x80 == 0x80 {
 				i++
+				t |= uint64(buf[i+5]&0x7F) << bs
+				bs += 7
 			}
-			buf[i+1] = byte(t)
 			i++
 
+			l = t
+
 		}
-		copy(buf[i+1:], d.Nick)
-		i += l
-	}
-	{
-		l := uint64(len(d.Username))
+		if uint64(cap(d.Commands)) >= l {
+			d.Commands = d.Commands[:l]
+		} else {
+			d.Commands = make([]string, l)
+		}
+		for k0 := range d.Commands {
 
-		{
+			{
+				l := uint64(0)
 
-			t := uint64(l)
+				{
 
-			for t >= 0x80 {
-				buf[i+1] = byte(t) | 0x80
-				t >>= 7
-				i++
+					bs := uint8(7)
+					t := uint64(buf[i+5] & 0x7F)
+					for buf[i+5]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+5]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				d.Commands[k0] = string(buf[i+5 : i+5+l])
+				i += l
 			}
-			buf[i+1] = byte(t)
-			i++
 
 		}
-		copy(buf[i+1:], d.Username)
-		i += l
 	}
 	{
-		l := uint64(len(d.Realname))
+		l := uint64(0)
 
 		{
 
-			t := uint64(l)
-
-			for t >= 0x80 {
-				buf[i+1] = byte(t) | 0x80
-				t >>= 7
+			bs := uint8(7)
+			t := uint64(buf[i+5] & 0x7F)
+			for buf[i+5]&0x80 == 0x80 {
 				i++
+				t |= uint64(buf[i+5]&0x7F) << bs
+				bs += 7
 			}
-			buf[i+1] = byte(t)
 			i++
 
+			l = t
+
 		}
-		copy(buf[i+1:], d.Realname)
+		d.Color = string(buf[i+5 : i+5+l])
 		i += l
 	}
 	{
-		l := uint64(len(d.Account))
+		l := uint64(0)
 
 		{
 
-			t := uint64(l)
-
-			for t >= 0x80 {
-				buf[i+1] = byte(t) | 0x80
-				t >>= 7
+			bs := uint8(7)
+			t := uint64(buf[i+5] & 0x7F)
+			for buf[i+5]&0x80 == 0x80 {
 				i++
+				t |= uint64(buf[i+5]&0x7F) << bs
+				bs += 7
 			}
-			buf[i+1] = byte(t)
 			i++
 
+			l = t
+
 		}
-		copy(buf[i+1:], d.Account)
+		d.Label = string(buf[i+5 : i+5+l])
 		i += l
 	}
 	{
-		l := uint64(len(d.Password))
+		l := uint64(0)
 
 		{
 
-			t := uint64(l)
-
-			for t >= 0x80 {
-				buf[i+1] = byte(t) | 0x80
-				t >>= 7
+			bs := uint8(7)
+			t := uint64(buf[i+5] & 0x7F)
+			for buf[i+5]&0x80 == 0x80 {
 				i++
+				t |= uint64(buf[i+5]&0x7F) << bs
+				bs += 7
 			}
-			buf[i+1] = byte(t)
 			i++
 
+			l = t
+
 		}
-		copy(buf[i+1:], d.Password)
+		d.OperName = string(buf[i+5 : i+5+l])
 		i += l
 	}
-	return buf[:i+1], nil
-}
-
-func (d *Server) Unmarshal(buf []byte) (uint64, error) {
-	i := uint64(0)
-
 	{
 		l := uint64(0)
 
 		{
 
 			bs := uint8(7)
-			t := uint64(buf[i+0] & 0x7F)
-			for buf[i+0]&0x80 == 0x80 {
+			t := uint64(buf[i+5] & 0x7F)
+			for buf[i+5]&0x80 == 0x80 {
 				i++
-				t |= uint64(buf[i+0]&0x7F) << bs
+				t |= uint64(buf[i+5]&0x7F) << bs
 				bs += 7
 			}
 			i++
@@ -590,7 +1823,7 @@ func (d *Server) Unmarshal(buf []byte) (uint64, error) {
 			l = t
 
 		}
-		d.Name = string(buf[i+0 : i+0+l])
+		d.OperPassword = string(buf[i+5 : i+5+l])
 		i += l
 	}
 	{
@@ -599,10 +1832,10 @@ func (d *Server) Unmarshal(buf []byte) (uint64, error) {
 		{
 
 			bs := uint8(7)
-			t := uint64(buf[i+0] & 0x7F)
-			for buf[i+0]&0x80 == 0x80 {
+			t := uint64(buf[i+5] & 0x7F)
+			for buf[i+5]&0x80 == 0x80 {
 				i++
-				t |= uint64(buf[i+0]&0x7F) << bs
+				t |= uint64(buf[i+5]&0x7F) << bs
 				bs += 7
 			}
 			i++
@@ -610,8 +1843,35 @@ func (d *Server) Unmarshal(buf []byte) (uint64, error) {
 			l = t
 
 		}
-		d.Host = string(buf[i+0 : i+0+l])
-		i += l
+		if uint64(cap(d.ForwardTags)) >= l {
+			d.ForwardTags = d.ForwardTags[:l]
+		} else {
+			d.ForwardTags = make([]string, l)
+		}
+		for k0 := range d.ForwardTags {
+
+			{
+				l := uint64(0)
+
+				{
+
+					bs := uint8(7)
+					t := uint64(buf[i+5] & 0x7F)
+					for buf[i+5]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+5]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				d.ForwardTags[k0] = string(buf[i+5 : i+5+l])
+				i += l
+			}
+
+		}
 	}
 	{
 		l := uint64(0)
@@ -619,10 +1879,10 @@ func (d *Server) Unmarshal(buf []byte) (uint64, error) {
 		{
 
 			bs := uint8(7)
-			t := uint64(buf[i+0] & 0x7F)
-			for buf[i+0]&0x80 == 0x80 {
+			t := uint64(buf[i+5] & 0x7F)
+			for buf[i+5]&0x80 == 0x80 {
 				i++
-				t |= uint64(buf[i+0]&0x7F) << bs
+				t |= uint64(buf[i+5]&0x7F) << bs
 				bs += 7
 			}
 			i++
@@ -630,11 +1890,35 @@ func (d *Server) Unmarshal(buf []byte) (uint64, error) {
 			l = t
 
 		}
-		d.Port = string(buf[i+0 : i+0+l])
-		i += l
-	}
-	{
-		d.TLS = buf[i+0] == 1
+		if uint64(cap(d.SendTags)) >= l {
+			d.SendTags = d.SendTags[:l]
+		} else {
+			d.SendTags = make([]string, l)
+		}
+		for k0 := range d.SendTags {
+
+			{
+				l := uint64(0)
+
+				{
+
+					bs := uint8(7)
+					t := uint64(buf[i+5] & 0x7F)
+					for buf[i+5]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+5]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				d.SendTags[k0] = string(buf[i+5 : i+5+l])
+				i += l
+			}
+
+		}
 	}
 	{
 		l := uint64(0)
@@ -642,10 +1926,10 @@ func (d *Server) Unmarshal(buf []byte) (uint64, error) {
 		{
 
 			bs := uint8(7)
-			t := uint64(buf[i+1] & 0x7F)
-			for buf[i+1]&0x80 == 0x80 {
+			t := uint64(buf[i+5] & 0x7F)
+			for buf[i+5]&0x80 == 0x80 {
 				i++
-				t |= uint64(buf[i+1]&0x7F) << bs
+				t |= uint64(buf[i+5]&0x7F) << bs
 				bs += 7
 			}
 			i++
@@ -653,7 +1937,7 @@ func (d *Server) Unmarshal(buf []byte) (uint64, error) {
 			l = t
 
 		}
-		d.ServerPassword = string(buf[i+1 : i+1+l])
+		d.TLSServerName = string(buf[i+5 : i+5+l])
 		i += l
 	}
 	{
@@ -662,10 +1946,10 @@ func (d *Server) Unmarshal(buf []byte) (uint64, error) {
 		{
 
 			bs := uint8(7)
-			t := uint64(buf[i+1] & 0x7F)
-			for buf[i+1]&0x80 == 0x80 {
+			t := uint64(buf[i+5] & 0x7F)
+			for buf[i+5]&0x80 == 0x80 {
 				i++
-				t |= uint64(buf[i+1]&0x7F) << bs
+				t |= uint64(buf[i+5]&0x7F) << bs
 				bs += 7
 			}
 			i++
@@ -673,8 +1957,35 @@ func (d *Server) Unmarshal(buf []byte) (uint64, error) {
 			l = t
 
 		}
-		d.Nick = string(buf[i+1 : i+1+l])
-		i += l
+		if uint64(cap(d.ALPN)) >= l {
+			d.ALPN = d.ALPN[:l]
+		} else {
+			d.ALPN = make([]string, l)
+		}
+		for k0 := range d.ALPN {
+
+			{
+				l := uint64(0)
+
+				{
+
+					bs := uint8(7)
+					t := uint64(buf[i+5] & 0x7F)
+					for buf[i+5]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+5]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				d.ALPN[k0] = string(buf[i+5 : i+5+l])
+				i += l
+			}
+
+		}
 	}
 	{
 		l := uint64(0)
@@ -682,10 +1993,10 @@ func (d *Server) Unmarshal(buf []byte) (uint64, error) {
 		{
 
 			bs := uint8(7)
-			t := uint64(buf[i+1] & 0x7F)
-			for buf[i+1]&0x80 == 0x80 {
+			t := uint64(buf[i+5] & 0x7F)
+			for buf[i+5]&0x80 == 0x80 {
 				i++
-				t |= uint64(buf[i+1]&0x7F) << bs
+				t |= uint64(buf[i+5]&0x7F) << bs
 				bs += 7
 			}
 			i++
@@ -693,7 +2004,7 @@ func (d *Server) Unmarshal(buf []byte) (uint64, error) {
 			l = t
 
 		}
-		d.Username = string(buf[i+1 : i+1+l])
+		d.MOTDHash = string(buf[i+5 : i+5+l])
 		i += l
 	}
 	{
@@ -702,67 +2013,99 @@ func (d *Server) Unmarshal(buf []byte) (uint64, error) {
 		{
 
 			bs := uint8(7)
-			t := uint64(buf[i+1] & 0x7F)
-			for buf[i+1]&0x80 == 0x80 {
+			t := uint64(buf[i+5] & 0x7F)
+			for buf[i+5]&0x80 == 0x80 {
 				i++
-				t |= uint64(buf[i+1]&0x7F) << bs
+				t |= uint64(buf[i+5]&0x7F) << bs
 				bs += 7
 			}
-			i++
-
-			l = t
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.PinnedCerts)) >= l {
+			d.PinnedCerts = d.PinnedCerts[:l]
+		} else {
+			d.PinnedCerts = make([]string, l)
+		}
+		for k0 := range d.PinnedCerts {
+
+			{
+				l := uint64(0)
+
+				{
+
+					bs := uint8(7)
+					t := uint64(buf[i+5] & 0x7F)
+					for buf[i+5]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+5]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				d.PinnedCerts[k0] = string(buf[i+5 : i+5+l])
+				i += l
+			}
+
+		}
+	}
+	return i + 5, nil
+}
+
+func (d *Channel) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Server))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
 
 		}
-		d.Realname = string(buf[i+1 : i+1+l])
-		i += l
+		s += l
 	}
 	{
-		l := uint64(0)
+		l := uint64(len(d.Name))
 
 		{
 
-			bs := uint8(7)
-			t := uint64(buf[i+1] & 0x7F)
-			for buf[i+1]&0x80 == 0x80 {
-				i++
-				t |= uint64(buf[i+1]&0x7F) << bs
-				bs += 7
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
 			}
-			i++
-
-			l = t
+			s++
 
 		}
-		d.Account = string(buf[i+1 : i+1+l])
-		i += l
+		s += l
 	}
 	{
-		l := uint64(0)
+		l := uint64(len(d.Group))
 
 		{
 
-			bs := uint8(7)
-			t := uint64(buf[i+1] & 0x7F)
-			for buf[i+1]&0x80 == 0x80 {
-				i++
-				t |= uint64(buf[i+1]&0x7F) << bs
-				bs += 7
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
 			}
-			i++
-
-			l = t
+			s++
 
 		}
-		d.Password = string(buf[i+1 : i+1+l])
-		i += l
+		s += l
 	}
-	return i + 1, nil
-}
-
-func (d *Channel) Size() (s uint64) {
-
 	{
-		l := uint64(len(d.Server))
+		l := uint64(len(d.Color))
 
 		{
 
@@ -777,7 +2120,7 @@ func (d *Channel) Size() (s uint64) {
 		s += l
 	}
 	{
-		l := uint64(len(d.Name))
+		l := uint64(len(d.Label))
 
 		{
 
@@ -791,6 +2134,7 @@ func (d *Channel) Size() (s uint64) {
 		}
 		s += l
 	}
+	s += 5
 	return
 }
 func (d *Channel) Marshal(buf []byte) ([]byte, error) {
@@ -842,7 +2186,76 @@ func (d *Channel) Marshal(buf []byte) ([]byte, error) {
 		copy(buf[i+0:], d.Name)
 		i += l
 	}
-	return buf[:i+0], nil
+	{
+
+		*(*int32)(unsafe.Pointer(&buf[i+0])) = d.Order
+
+	}
+	{
+		l := uint64(len(d.Group))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+4] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+4] = byte(t)
+			i++
+
+		}
+		copy(buf[i+4:], d.Group)
+		i += l
+	}
+	{
+		l := uint64(len(d.Color))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+4] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+4] = byte(t)
+			i++
+
+		}
+		copy(buf[i+4:], d.Color)
+		i += l
+	}
+	{
+		l := uint64(len(d.Label))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+4] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+4] = byte(t)
+			i++
+
+		}
+		copy(buf[i+4:], d.Label)
+		i += l
+	}
+	{
+		if d.Lazy {
+			buf[i+4] = 1
+		} else {
+			buf[i+4] = 0
+		}
+	}
+	return buf[:i+5], nil
 }
 
 func (d *Channel) Unmarshal(buf []byte) (uint64, error) {
@@ -888,7 +2301,75 @@ func (d *Channel) Unmarshal(buf []byte) (uint64, error) {
 		d.Name = string(buf[i+0 : i+0+l])
 		i += l
 	}
-	return i + 0, nil
+	{
+
+		d.Order = *(*int32)(unsafe.Pointer(&buf[i+0]))
+
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+4] & 0x7F)
+			for buf[i+4]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+4]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Group = string(buf[i+4 : i+4+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+4] & 0x7F)
+			for buf[i+4]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+4]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Color = string(buf[i+4 : i+4+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+4] & 0x7F)
+			for buf[i+4]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+4]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Label = string(buf[i+4 : i+4+l])
+		i += l
+	}
+	{
+		d.Lazy = buf[i+4] == 1
+	}
+	return i + 5, nil
 }
 
 func (d *Message) Size() (s uint64) {
@@ -961,7 +2442,52 @@ func (d *Message) Size() (s uint64) {
 		}
 
 	}
-	s += 8
+	{
+		l := uint64(len(d.Account))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.MsgID))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.ReplyTo))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	s += 9
 	return
 }
 func (d *Message) Marshal(buf []byte) ([]byte, error) {
@@ -1065,7 +2591,71 @@ func (d *Message) Marshal(buf []byte) ([]byte, error) {
 
 		}
 	}
-	return buf[:i+8], nil
+	{
+		l := uint64(len(d.Account))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+8] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+8] = byte(t)
+			i++
+
+		}
+		copy(buf[i+8:], d.Account)
+		i += l
+	}
+	{
+		l := uint64(len(d.MsgID))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+8] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+8] = byte(t)
+			i++
+
+		}
+		copy(buf[i+8:], d.MsgID)
+		i += l
+	}
+	{
+		l := uint64(len(d.ReplyTo))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+8] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+8] = byte(t)
+			i++
+
+		}
+		copy(buf[i+8:], d.ReplyTo)
+		i += l
+	}
+	{
+		if d.Mention {
+			buf[i+8] = 1
+		} else {
+			buf[i+8] = 0
+		}
+	}
+	return buf[:i+9], nil
 }
 
 func (d *Message) Unmarshal(buf []byte) (uint64, error) {
@@ -1170,7 +2760,70 @@ func (d *Message) Unmarshal(buf []byte) (uint64, error) {
 
 		}
 	}
-	return i + 8, nil
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+8] & 0x7F)
+			for buf[i+8]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+8]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Account = string(buf[i+8 : i+8+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+8] & 0x7F)
+			for buf[i+8]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+8]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.MsgID = string(buf[i+8 : i+8+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+8] & 0x7F)
+			for buf[i+8]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+8]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.ReplyTo = string(buf[i+8 : i+8+l])
+		i += l
+	}
+	{
+		d.Mention = buf[i+8] == 1
+	}
+	return i + 9, nil
 }
 
 func (d *Event) Size() (s uint64) {
@@ -1383,3 +3036,200 @@ func (d *Event) Unmarshal(buf []byte) (uint64, error) {
 	}
 	return i + 8, nil
 }
+
+func (d *Topic) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Topic))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Nick))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	s += 8
+	return
+}
+func (d *Topic) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.ID)
+		i += l
+	}
+	{
+		l := uint64(len(d.Topic))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Topic)
+		i += l
+	}
+	{
+		l := uint64(len(d.Nick))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Nick)
+		i += l
+	}
+	{
+
+		*(*int64)(unsafe.Pointer(&buf[i+0])) = d.Time
+
+	}
+	return buf[:i+8], nil
+}
+
+func (d *Topic) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.ID = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Topic = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Nick = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+
+		d.Time = *(*int64)(unsafe.Pointer(&buf[i+0]))
+
+	}
+	return i + 8, nil
+}
@@ -0,0 +1,107 @@
+package storage_test
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/boltdb"
+	"github.com/khlieng/dispatch/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ storage.MessageStreamer = &boltdb.BoltStore{}
+
+func streamAll(t *testing.T, user *storage.User, from time.Time) []storage.Message {
+	messages := []storage.Message{}
+	err := user.StreamMessages("irc.freenode.net", "#go-nuts", from, func(msg *storage.Message) bool {
+		messages = append(messages, *msg)
+		return true
+	})
+	assert.Nil(t, err)
+	return messages
+}
+
+func testStreamMessages(t *testing.T, user *storage.User) {
+	assert.Empty(t, streamAll(t, user, time.Time{}))
+
+	// More than two pages of messages
+	count := 1234
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	messages := make([]*storage.Message, count)
+	for i := range messages {
+		sent := start.Add(time.Duration(i) * time.Second)
+		messages[i] = &storage.Message{
+			ID:      storage.MessageIDAt(sent),
+			Server:  "irc.freenode.net",
+			From:    "nick",
+			To:      "#go-nuts",
+			Content: strconv.Itoa(i),
+			Time:    sent.Unix(),
+		}
+	}
+	assert.Nil(t, user.LogMessages(messages))
+
+	batched, hasMore, err := user.GetMessages("irc.freenode.net", "#go-nuts", count, "")
+	assert.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Len(t, batched, count)
+
+	streamed := streamAll(t, user, time.Time{})
+	assert.Equal(t, batched, streamed)
+
+	streamed = streamAll(t, user, start.Add(600*time.Second))
+	assert.Equal(t, batched[600:], streamed)
+
+	assert.Empty(t, streamAll(t, user, start.Add(time.Hour)))
+
+	// Stopping early
+	n := 0
+	err = user.StreamMessages("irc.freenode.net", "#go-nuts", time.Time{}, func(msg *storage.Message) bool {
+		n++
+		return n < 700
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 700, n)
+}
+
+func TestStreamMessagesBolt(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+	defer db.Close()
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return db, nil
+	}
+	storage.GetMessageSearchProvider = func(_ *storage.User) (storage.MessageSearchProvider, error) {
+		return memory.NewSearch(), nil
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+	os.MkdirAll(storage.Path.User(user.Username), 0700)
+
+	testStreamMessages(t, user)
+}
+
+// The memory store is not a MessageStreamer, it gets streamed a page
+// at a time
+func TestStreamMessagesPaged(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return memory.New(), nil
+	}
+	storage.GetMessageSearchProvider = func(_ *storage.User) (storage.MessageSearchProvider, error) {
+		return memory.NewSearch(), nil
+	}
+
+	user, err := storage.NewUser(memory.New())
+	assert.Nil(t, err)
+
+	testStreamMessages(t, user)
+}
@@ -0,0 +1,28 @@
+package storage
+
+import "time"
+
+// DefaultTimeFormat is ISO-8601
+const DefaultTimeFormat = time.RFC3339
+
+// Location returns the timezone of the settings, falling back to UTC
+func (s *ClientSettings) Location() *time.Location {
+	if loc, err := time.LoadLocation(s.Timezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// FormatTime formats t for presentation, timestamps are always stored in UTC
+func (s *ClientSettings) FormatTime(t time.Time) string {
+	layout := s.TimeFormat
+	if layout == "" {
+		layout = DefaultTimeFormat
+	}
+	return t.In(s.Location()).Format(layout)
+}
+
+// FormatTime formats t using the timezone and format of the users settings
+func (u *User) FormatTime(t time.Time) string {
+	return u.GetClientSettings().FormatTime(t)
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatTimeDefault(t *testing.T) {
+	s := DefaultClientSettings()
+	ts := time.Date(2021, 3, 14, 6, 59, 59, 0, time.UTC)
+
+	assert.Equal(t, "2021-03-14T06:59:59Z", s.FormatTime(ts))
+	assert.Equal(t, "2021-03-14T06:59:59Z", s.FormatTime(ts.In(time.FixedZone("X", 3600))))
+}
+
+func TestFormatTimeDST(t *testing.T) {
+	s := &ClientSettings{Timezone: "America/New_York"}
+
+	// Clocks spring forward from 02:00 EST to 03:00 EDT
+	assert.Equal(t, "2021-03-14T01:59:59-05:00",
+		s.FormatTime(time.Date(2021, 3, 14, 6, 59, 59, 0, time.UTC)))
+	assert.Equal(t, "2021-03-14T03:00:00-04:00",
+		s.FormatTime(time.Date(2021, 3, 14, 7, 0, 0, 0, time.UTC)))
+
+	// Clocks fall back from 02:00 EDT to 01:00 EST, 01:30 happens twice
+	s.TimeFormat = "2006-01-02 15:04 MST"
+	assert.Equal(t, "2021-11-07 01:30 EDT",
+		s.FormatTime(time.Date(2021, 11, 7, 5, 30, 0, 0, time.UTC)))
+	assert.Equal(t, "2021-11-07 01:30 EST",
+		s.FormatTime(time.Date(2021, 11, 7, 6, 30, 0, 0, time.UTC)))
+}
+
+func TestFormatTimeInvalidTimezone(t *testing.T) {
+	s := &ClientSettings{Timezone: "Not/AZone"}
+	assert.Equal(t, time.UTC, s.Location())
+	assert.Equal(t, "2021-03-14T07:00:00Z",
+		s.FormatTime(time.Date(2021, 3, 14, 7, 0, 0, 0, time.UTC)))
+}
@@ -2,9 +2,14 @@ package storage
 
 import (
 	"crypto/tls"
+	"errors"
 	"os"
+	"regexp"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/kjk/betterguid"
 )
@@ -18,10 +23,24 @@ type User struct {
 	messageLog     MessageStore
 	messageIndex   MessageSearchProvider
 	lastMessages   map[string]map[string]*Message
+	operPasswords  map[string]string
 	clientSettings *ClientSettings
 	lastIP         []byte
+	timezone       string
+	timeFormat     string
+	admin          bool
+	alwaysOn       bool
+	quitMessage    string
+	partMessage    string
+	readReceipts   bool
+	showMOTD       string
 	certificate    *tls.Certificate
+	removed        bool
 	lock           sync.Mutex
+	historyLock    sync.Mutex
+	// limitLock makes checking the limits and adding a server or channel
+	// one step, so concurrent JOINs can not all pass the check
+	limitLock sync.Mutex
 }
 
 func NewUser(store Store) (*User, error) {
@@ -40,7 +59,9 @@ func NewUser(store Store) (*User, error) {
 	if err != nil {
 		return nil, err
 	}
-	err = os.Mkdir(Path.Downloads(user.Username), 0700)
+	// IDs are reused when the users are not persisted, which leaves
+	// the directories of earlier users behind
+	err = os.MkdirAll(Path.Downloads(user.Username), 0700)
 	if err != nil {
 		return nil, err
 	}
@@ -94,15 +115,79 @@ func LoadUsers(store Store) ([]*User, error) {
 	return users, nil
 }
 
-func (u *User) Remove() {
-	u.store.DeleteUser(u)
-	if u.messageLog != nil {
-		u.messageLog.Close()
+// Remove deletes the user along with its servers, channels, open DMs,
+// aliases and history, and removes the user directory which holds the
+// message log, search index and downloads. Removing a user that is
+// already removed only removes whatever is left
+func (u *User) Remove() error {
+	err := u.store.DeleteUser(u)
+
+	u.lock.Lock()
+	if !u.removed {
+		u.removed = true
+		if u.messageLog != nil {
+			u.messageLog.Close()
+		}
+		if u.messageIndex != nil {
+			u.messageIndex.Close()
+		}
+	}
+	u.lock.Unlock()
+
+	if rmErr := os.RemoveAll(Path.User(u.Username)); err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// IsAdmin reports whether the user has access to the admin API
+func (u *User) IsAdmin() bool {
+	u.lock.Lock()
+	admin := u.admin
+	u.lock.Unlock()
+	return admin
+}
+
+func (u *User) SetAdmin(admin bool) error {
+	u.lock.Lock()
+	u.admin = admin
+	u.lock.Unlock()
+
+	return u.store.SaveUser(u)
+}
+
+// IsAlwaysOn reports whether the users IRC connections should stay up
+// when they have no sessions connected
+func (u *User) IsAlwaysOn() bool {
+	u.lock.Lock()
+	alwaysOn := u.alwaysOn
+	u.lock.Unlock()
+	return alwaysOn
+}
+
+func (u *User) SetAlwaysOn(alwaysOn bool) error {
+	u.lock.Lock()
+	u.alwaysOn = alwaysOn
+	u.lock.Unlock()
+
+	return u.store.SaveUser(u)
+}
+
+// SetUserAdmin changes the admin flag of a user that is not loaded,
+// for use while dispatch is not running
+func SetUserAdmin(store Store, username string, admin bool) error {
+	users, err := store.GetUsers()
+	if err != nil {
+		return err
 	}
-	if u.messageIndex != nil {
-		u.messageIndex.Close()
+
+	for _, user := range users {
+		if user.Username == username {
+			user.store = store
+			return user.SetAdmin(admin)
+		}
 	}
-	os.RemoveAll(Path.User(u.Username))
+	return ErrNotFound
 }
 
 func (u *User) GetLastIP() []byte {
@@ -123,8 +208,36 @@ func (u *User) SetLastIP(ip []byte) error {
 //easyjson:json
 type ClientSettings struct {
 	ColoredNicks bool
+
+	// Timezone is the IANA name of the timezone used for timestamps
+	// formatted by the server, UTC when empty
+	Timezone string
+	// TimeFormat is the Go time layout used for timestamps formatted
+	// by the server, ISO-8601 when empty
+	TimeFormat string
+
+	// QuitMessage and PartMessage are used when leaving without giving
+	// a message, {version} gets replaced by the version of dispatch
+	QuitMessage string
+	PartMessage string
+
+	// ReadReceipts sends a read receipt to the other side of a DM when
+	// it gets marked as read
+	ReadReceipts bool
+
+	// ShowMOTD is when the MOTD gets shown after connecting, one of
+	// MOTDAlways, MOTDChanged or MOTDNever, empty means MOTDAlways
+	ShowMOTD string
 }
 
+const (
+	MOTDAlways  = "always"
+	MOTDChanged = "changed"
+	MOTDNever   = "never"
+)
+
+var ErrInvalidShowMOTD = errors.New("MOTD has to be shown always, when changed or never")
+
 func DefaultClientSettings() *ClientSettings {
 	return &ClientSettings{
 		ColoredNicks: true,
@@ -134,28 +247,47 @@ func DefaultClientSettings() *ClientSettings {
 func (u *User) GetClientSettings() *ClientSettings {
 	u.lock.Lock()
 	settings := *u.clientSettings
+	settings.Timezone = u.timezone
+	settings.TimeFormat = u.timeFormat
+	settings.QuitMessage = u.quitMessage
+	settings.PartMessage = u.partMessage
+	settings.ReadReceipts = u.readReceipts
+	settings.ShowMOTD = u.showMOTD
 	u.lock.Unlock()
 	return &settings
 }
 
 func (u *User) SetClientSettings(settings *ClientSettings) error {
+	if _, err := time.LoadLocation(settings.Timezone); err != nil {
+		return err
+	}
+	switch settings.ShowMOTD {
+	case "", MOTDAlways, MOTDChanged, MOTDNever:
+	default:
+		return ErrInvalidShowMOTD
+	}
+
 	u.lock.Lock()
 	u.clientSettings = settings
+	u.timezone = settings.Timezone
+	u.timeFormat = settings.TimeFormat
+	u.quitMessage = settings.QuitMessage
+	u.partMessage = settings.PartMessage
+	u.readReceipts = settings.ReadReceipts
+	u.showMOTD = settings.ShowMOTD
 	u.lock.Unlock()
 
 	return u.store.SaveUser(u)
 }
 
 func (u *User) UnmarshalClientSettingsJSON(b []byte) error {
-	u.lock.Lock()
-	err := u.clientSettings.UnmarshalJSON(b)
-	u.lock.Unlock()
-
+	settings := u.GetClientSettings()
+	err := settings.UnmarshalJSON(b)
 	if err != nil {
 		return err
 	}
 
-	return u.store.SaveUser(u)
+	return u.SetClientSettings(settings)
 }
 
 type Server struct {
@@ -169,6 +301,35 @@ type Server struct {
 	Realname       string
 	Account        string
 	Password       string
+	// Order and Group are used to organize the servers in the client
+	Order int32
+	Group string
+	// Commands are raw IRC lines sent in order after registration
+	Commands []string
+	// Color and Label are set by the user to tell servers apart
+	Color string
+	Label string
+	// OperName and OperPassword are sent with OPER after registration
+	// when both are set
+	OperName     string
+	OperPassword string
+	// ForwardTags are the client-only tags, like +vendor/tag, passed on
+	// from incoming messages, a trailing * matches any tag with that prefix
+	ForwardTags []string
+	// SendTags are client-only tags in the key=value form that get added
+	// to outgoing messages
+	SendTags []string
+	// TLSServerName is sent with SNI instead of the host, for servers
+	// behind a proxy that routes on it
+	TLSServerName string
+	// ALPN are the protocols offered in the TLS handshake
+	ALPN []string
+	// MOTDHash identifies the last MOTD the server sent
+	MOTDHash string
+	// PinnedCerts are SHA-256 fingerprints of the certificates the server
+	// is trusted with instead of verifying it, the first is the current
+	// one and the rest are accepted while it gets rotated
+	PinnedCerts []string
 }
 
 func (u *User) GetServer(address string) (*Server, error) {
@@ -180,10 +341,160 @@ func (u *User) GetServers() ([]*Server, error) {
 }
 
 func (u *User) AddServer(server *Server) error {
+	u.limitLock.Lock()
+	defer u.limitLock.Unlock()
+
+	if err := u.checkServerLimit(server.Host); err != nil {
+		return err
+	}
+	return u.store.SaveServer(u, server)
+}
+
+func (u *User) SetServerCommands(address string, commands []string) error {
+	server, err := u.GetServer(address)
+	if err != nil {
+		return err
+	}
+	server.Commands = commands
+	return u.store.SaveServer(u, server)
+}
+
+// SetServerOper stores the credentials sent with OPER after connecting,
+// an empty name removes them. The password only gets persisted when the
+// data of the user is encrypted, it is encrypted with the same key.
+// Otherwise it is only kept in memory until dispatch restarts
+func (u *User) SetServerOper(address, name, password string) error {
+	server, err := u.GetServer(address)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		password = ""
+	}
+
+	server.OperName = name
+	server.OperPassword = ""
+
+	enc := u.encryption()
+	if enc != nil && password != "" {
+		server.OperPassword, err = encryptString(enc, password)
+		if err != nil {
+			return err
+		}
+	}
+
+	u.lock.Lock()
+	if enc == nil && password != "" {
+		if u.operPasswords == nil {
+			u.operPasswords = map[string]string{}
+		}
+		u.operPasswords[address] = password
+	} else {
+		delete(u.operPasswords, address)
+	}
+	u.lock.Unlock()
+
+	return u.store.SaveServer(u, server)
+}
+
+// GetServerOper returns the credentials sent with OPER after connecting,
+// the name is empty if there are none
+func (u *User) GetServerOper(address string) (string, string, error) {
+	server, err := u.GetServer(address)
+	if err != nil || server.OperName == "" {
+		return "", "", err
+	}
+
+	if enc := u.encryption(); enc != nil {
+		if server.OperPassword == "" {
+			return server.OperName, "", nil
+		}
+		return server.OperName, decryptString(enc, server.OperPassword), nil
+	}
+
+	u.lock.Lock()
+	password := u.operPasswords[address]
+	u.lock.Unlock()
+	return server.OperName, password, nil
+}
+
+// SetServerClientTags stores the client-only tags forwarded from incoming
+// messages and the ones added to outgoing messages
+func (u *User) SetServerClientTags(address string, forward, send []string) error {
+	for _, tag := range append(forward, send...) {
+		if !IsClientTag(tag) {
+			return ErrInvalidClientTag
+		}
+	}
+
+	server, err := u.GetServer(address)
+	if err != nil {
+		return err
+	}
+	server.ForwardTags = forward
+	server.SendTags = send
+	return u.store.SaveServer(u, server)
+}
+
+// SetServerTLS stores the server name sent with SNI, the host gets sent
+// when it is empty, and the ALPN protocols offered in the TLS handshake
+func (u *User) SetServerTLS(address, serverName string, alpn []string) error {
+	server, err := u.GetServer(address)
+	if err != nil {
+		return err
+	}
+	server.TLSServerName = serverName
+	server.ALPN = alpn
 	return u.store.SaveServer(u, server)
 }
 
+// SetServerPins stores the fingerprints of the certificates the server
+// is pinned to, they are hex encoded SHA-256 hashes and may contain
+// colons. No pins turns pinning off.
+func (u *User) SetServerPins(address string, pins []string) error {
+	normalized := make([]string, 0, len(pins))
+	for _, pin := range pins {
+		pin = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(pin), ":", ""))
+		if pin == "" {
+			continue
+		}
+		if !fingerprintRegex.MatchString(pin) {
+			return ErrInvalidPin
+		}
+		normalized = append(normalized, pin)
+	}
+
+	server, err := u.GetServer(address)
+	if err != nil {
+		return err
+	}
+	server.PinnedCerts = normalized
+	return u.store.SaveServer(u, server)
+}
+
+// SetServerMOTDHash stores the hash of the last MOTD the server sent
+func (u *User) SetServerMOTDHash(address, hash string) error {
+	server, err := u.GetServer(address)
+	if err != nil {
+		return err
+	}
+	server.MOTDHash = hash
+	return u.store.SaveServer(u, server)
+}
+
+// IsClientTag returns true if tag is a client-only tag name, optionally
+// followed by =value
+func IsClientTag(tag string) bool {
+	name := strings.SplitN(tag, "=", 2)[0]
+	return len(name) > 1 && name[0] == '+' &&
+		!strings.ContainsAny(name, " ;=") && !strings.ContainsAny(tag, "\r\n")
+}
+
 func (u *User) RemoveServer(address string) error {
+	u.lock.Lock()
+	delete(u.operPasswords, address)
+	u.lock.Unlock()
+
 	return u.store.RemoveServer(u, address)
 }
 
@@ -209,6 +520,13 @@ type Channel struct {
 	Server string
 	Name   string
 	Topic  string
+	Order  int32
+	Group  string
+	Color  string
+	Label  string
+	// Lazy channels do not have their topic and userlist requested on
+	// join, the client asks for them when they are needed
+	Lazy bool
 }
 
 func (u *User) GetChannels() ([]*Channel, error) {
@@ -216,13 +534,170 @@ func (u *User) GetChannels() ([]*Channel, error) {
 }
 
 func (u *User) AddChannel(channel *Channel) error {
+	u.limitLock.Lock()
+	defer u.limitLock.Unlock()
+
+	name, err := u.checkChannelLimit(channel.Server, channel.Name)
+	if err != nil {
+		return err
+	}
+
+	// A JOIN that only differs in case updates the stored channel
+	if name != channel.Name {
+		ch := *channel
+		ch.Name = name
+		channel = &ch
+	}
 	return u.store.AddChannel(u, channel)
 }
 
+// Placement is the position of a server, or a channel when Channel is set,
+// in the users sidebar
+type Placement struct {
+	Server  string
+	Channel string
+	Order   int32
+	Group   string
+}
+
+// SetOrder stores the order and grouping of servers and channels,
+// entries that no longer exist are ignored
+func (u *User) SetOrder(placements []Placement) error {
+	return u.store.SetOrder(u, placements)
+}
+
+// MaxLabelLength is the max number of characters in a label
+const MaxLabelLength = 16
+
+var (
+	ErrInvalidColor = errors.New("Colors have to be in the #rgb or #rrggbb format")
+	ErrLabelTooLong = errors.New("Labels can be at most 16 characters")
+	// ErrInvalidClientTag is returned for tags that are not client-only
+	ErrInvalidClientTag = errors.New("Client tags have to start with +")
+	// ErrInvalidPin is returned for pins that are not SHA-256 fingerprints
+	ErrInvalidPin = errors.New("Pins have to be hex encoded SHA-256 fingerprints")
+
+	colorRegex       = regexp.MustCompile("^#([0-9a-fA-F]{3}){1,2}$")
+	fingerprintRegex = regexp.MustCompile("^[0-9a-f]{64}$")
+)
+
+// Appearance is the color and label of a server, or a channel when
+// Channel is set, empty values clear them
+type Appearance struct {
+	Server  string
+	Channel string
+	Color   string
+	Label   string
+}
+
+func (a Appearance) Validate() error {
+	if a.Color != "" && !colorRegex.MatchString(a.Color) {
+		return ErrInvalidColor
+	}
+	if utf8.RuneCountInString(a.Label) > MaxLabelLength {
+		return ErrLabelTooLong
+	}
+	return nil
+}
+
+// SetAppearance stores the color and label of a server or channel,
+// ErrNotFound is returned if it does not exist
+func (u *User) SetAppearance(appearance Appearance) error {
+	if err := appearance.Validate(); err != nil {
+		return err
+	}
+	return u.store.SetAppearance(u, appearance)
+}
+
+// SetChannelLazy sets whether the topic and userlist of a channel are
+// only fetched on demand, ErrNotFound is returned if it does not exist
+func (u *User) SetChannelLazy(server, channel string, lazy bool) error {
+	return u.store.SetChannelLazy(u, server, channel, lazy)
+}
+
+// IsChannelLazy returns true if the channel is stored and set to be lazy
+func (u *User) IsChannelLazy(server, channel string) bool {
+	channels, err := u.GetChannels()
+	if err != nil {
+		return false
+	}
+
+	for _, ch := range channels {
+		if ch.Server == server && ch.Name == channel {
+			return ch.Lazy
+		}
+	}
+	return false
+}
+
 func (u *User) RemoveChannel(server, channel string) error {
 	return u.store.RemoveChannel(u, server, channel)
 }
 
+// RenameChannel moves a channel along with its messages, topics and
+// search index entries to a new name. The channel itself gets renamed
+// even when the message store is not a ChannelRenamer, the error is
+// ErrRenameUnsupported then
+func (u *User) RenameChannel(server, from, to string) error {
+	channels, err := u.GetChannels()
+	if err != nil {
+		return err
+	}
+
+	for _, channel := range channels {
+		if channel.Server == server && channel.Name == from {
+			renamed := *channel
+			renamed.Name = to
+
+			// Removing first keeps renames that only change case working
+			if err = u.store.RemoveChannel(u, server, from); err != nil {
+				return err
+			}
+			if err = u.store.AddChannel(u, &renamed); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	u.lock.Lock()
+	if last, ok := u.lastMessages[server][from]; ok {
+		delete(u.lastMessages[server], from)
+		u.lastMessages[server][to] = last
+	}
+	u.lock.Unlock()
+
+	renamer, ok := u.messageLog.(ChannelRenamer)
+	if !ok {
+		return ErrRenameUnsupported
+	}
+	if err = renamer.RenameChannel(server, from, to); err != nil {
+		return err
+	}
+
+	// The search index holds the channel of each message
+	iter, ok := u.messageLog.(MessageIterator)
+	if !ok || u.messageIndex == nil {
+		return nil
+	}
+	return iter.ForEachMessage(func(msg *Message) error {
+		if msg.Server != server || msg.To != to || msg.Content == "" {
+			return nil
+		}
+		return u.messageIndex.Index(msg.ID, msg)
+	})
+}
+
+// CompactMessages compacts the message log, it fails with
+// ErrCompactUnsupported if the message store can not do that
+func (u *User) CompactMessages() (CompactStats, error) {
+	compacter, ok := u.messageLog.(Compacter)
+	if !ok {
+		return CompactStats{}, ErrCompactUnsupported
+	}
+	return compacter.Compact()
+}
+
 type Tab struct {
 	Server string
 	Name   string
@@ -241,19 +716,64 @@ func (u *User) RemoveOpenDM(server, nick string) error {
 }
 
 type Message struct {
-	ID      string  `json:"-" bleve:"-"`
+	ID      string  `bleve:"-"`
 	Server  string  `json:"-" bleve:"server"`
 	From    string  `bleve:"-"`
+	Account string  `bleve:"account"`
 	To      string  `json:"-" bleve:"to"`
 	Content string  `bleve:"content"`
 	Time    int64   `bleve:"-"`
 	Events  []Event `bleve:"-"`
+	// MsgID is the msgid tag set by the IRC server
+	MsgID string `bleve:"-"`
+	// ReplyTo is the msgid of the message this is a reply to, it might
+	// not be known locally
+	ReplyTo string `bleve:"-"`
+	// Mention is set when the content mentioned the nick the user had
+	// when the message got logged
+	Mention bool `bleve:"mention"`
 }
 
 func (m Message) Type() string {
 	return "message"
 }
 
+const idChars = "-0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ_abcdefghijklmnopqrstuvwxyz"
+
+// MessageIDAt returns a new message ID that sorts as if it was created at t,
+// this keeps messages logged after the fact in order
+func MessageIDAt(t time.Time) string {
+	id := []byte(betterguid.New())
+	copy(id, MessageIDPrefix(t))
+	return string(id)
+}
+
+// MessageIDPrefix returns the time part of the IDs of messages created at t,
+// message IDs sort before or after it when they were created before or after t
+func MessageIDPrefix(t time.Time) string {
+	prefix := make([]byte, 8)
+	ms := t.UnixNano() / 1e6
+
+	for i := 7; i >= 0; i-- {
+		prefix[i] = idChars[ms%64]
+		ms /= 64
+	}
+	return string(prefix)
+}
+
+// GetAliases returns the users command aliases mapped to their expansions
+func (u *User) GetAliases() (map[string]string, error) {
+	return u.store.GetAliases(u)
+}
+
+func (u *User) SetAlias(name, expansion string) error {
+	return u.store.SetAlias(u, name, expansion)
+}
+
+func (u *User) RemoveAlias(name string) error {
+	return u.store.RemoveAlias(u, name)
+}
+
 func (u *User) LogMessage(msg *Message) error {
 	if msg.Time == 0 {
 		msg.Time = time.Now().Unix()
@@ -267,6 +787,8 @@ func (u *User) LogMessage(msg *Message) error {
 		msg.To = msg.From
 	}
 
+	msg.Content = LimitMessage(msg.Content)
+
 	u.setLastMessage(msg.Server, msg.To, msg)
 
 	err := u.messageLog.LogMessage(msg)
@@ -276,12 +798,92 @@ func (u *User) LogMessage(msg *Message) error {
 	return u.messageIndex.Index(msg.ID, msg)
 }
 
+// LogMessages logs a batch of messages, such as history replayed by
+// the IRC server, without affecting event collapsing
+func (u *User) LogMessages(messages []*Message) error {
+	for _, msg := range messages {
+		if msg.Time == 0 {
+			msg.Time = time.Now().Unix()
+		}
+
+		if msg.ID == "" {
+			msg.ID = MessageIDAt(time.Unix(msg.Time, 0))
+		}
+
+		msg.Content = LimitMessage(msg.Content)
+	}
+
+	err := u.messageLog.LogMessages(messages)
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range messages {
+		err = u.messageIndex.Index(msg.ID, msg)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reindex adds every stored message to the search index again, it
+// recovers an index that is missing messages and returns how many
+// messages got indexed
+func (u *User) Reindex() (int, error) {
+	iter, ok := u.messageLog.(MessageIterator)
+	if !ok {
+		return 0, ErrReindexUnsupported
+	}
+
+	count := 0
+	err := iter.ForEachMessage(func(msg *Message) error {
+		// Messages holding only events do not get indexed when logged
+		if msg.Content == "" {
+			return nil
+		}
+
+		count++
+		return u.messageIndex.Index(msg.ID, msg)
+	})
+	return count, err
+}
+
 type Event struct {
 	Type   string
 	Params []string
 	Time   int64
 }
 
+type Topic struct {
+	ID      string `json:"-"`
+	Server  string `json:"-"`
+	Channel string `json:"-"`
+	Topic   string
+	Nick    string
+	Time    int64
+}
+
+func (u *User) LogTopic(server, channel, nick, topic string) error {
+	return u.messageLog.LogTopic(&Topic{
+		ID:      betterguid.New(),
+		Server:  server,
+		Channel: channel,
+		Topic:   topic,
+		Nick:    nick,
+		Time:    time.Now().Unix(),
+	})
+}
+
+// GetTopics returns up to count topics set before fromID, oldest first
+func (u *User) GetTopics(server, channel string, count int, fromID string) ([]Topic, bool, error) {
+	return u.messageLog.GetTopics(server, channel, count, fromID)
+}
+
+func (u *User) GetLastTopics(server, channel string, count int) ([]Topic, bool, error) {
+	return u.GetTopics(server, channel, count, "")
+}
+
 func (u *User) LogEvent(server, name string, params []string, channels ...string) error {
 	now := time.Now().Unix()
 	event := Event{
@@ -373,11 +975,181 @@ func (u *User) GetLastMessages(server, channel string, count int) ([]Message, bo
 	return u.GetMessages(server, channel, count, "")
 }
 
-func (u *User) SearchMessages(server, channel, q string) ([]Message, error) {
-	ids, err := u.messageIndex.SearchMessages(server, channel, q)
+// GetMessageContext returns the message with the given ID surrounded by
+// up to count messages on either side of it
+func (u *User) GetMessageContext(server, channel, id string, count int) ([]Message, error) {
+	return u.messageLog.GetMessageContext(server, channel, id, count)
+}
+
+// GetMessagesAt returns the first message sent at or after t surrounded by
+// up to count messages on either side of it, along with its ID
+func (u *User) GetMessagesAt(server, channel string, t time.Time, count int) ([]Message, string, error) {
+	id, err := u.messageLog.GetMessageIDAt(server, channel, t)
+	if err != nil {
+		return nil, "", err
+	}
+
+	messages, err := u.messageLog.GetMessageContext(server, channel, id, count)
+	return messages, id, err
+}
+
+// streamPageSize is how many messages are read at a time when streaming
+// from a message store that is not a MessageStreamer
+const streamPageSize = 500
+
+// StreamMessages calls fn with the messages of channel sent at or after
+// from, oldest first, until it runs out of messages or fn returns false.
+// Unlike GetMessages it never holds more than a page of messages.
+func (u *User) StreamMessages(server, channel string, from time.Time, fn func(*Message) bool) error {
+	if from.IsZero() {
+		from = time.Unix(0, 0)
+	}
+
+	if streamer, ok := u.messageLog.(MessageStreamer); ok {
+		return streamer.StreamMessages(server, channel, from, fn)
+	}
+	return streamMessagePages(u.messageLog, server, channel, from, fn)
+}
+
+// streamMessagePages streams messages from any message store, a page
+// at a time
+func streamMessagePages(store MessageStore, server, channel string, from time.Time, fn func(*Message) bool) error {
+	id, err := store.GetMessageIDAt(server, channel, from)
+	if err == ErrNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// id has already been streamed for every page but the first
+	skip := 0
+	for {
+		messages, err := store.GetMessageContext(server, channel, id, streamPageSize)
+		if err != nil {
+			return err
+		}
+
+		// The context starts with up to a page of messages before id
+		start := -1
+		for i, msg := range messages {
+			if msg.ID == id {
+				start = i
+				break
+			}
+		}
+		if start < 0 {
+			return nil
+		}
+
+		for i := start + skip; i < len(messages); i++ {
+			if !fn(&messages[i]) {
+				return nil
+			}
+		}
+
+		if len(messages)-start-1 < streamPageSize {
+			return nil
+		}
+		id = messages[len(messages)-1].ID
+		skip = 1
+	}
+}
+
+// SearchMessages returns a page of up to limit messages matching q, newest
+// first, along with the total number of matches
+func (u *User) SearchMessages(server, channel, q string, offset, limit int) ([]Message, uint64, error) {
+	ids, total, err := u.messageIndex.SearchMessages(server, channel, q, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	messages, err := u.messageLog.GetMessagesByID(server, channel, ids)
+	return messages, total, err
+}
+
+// SearchMatch is a search result along with the part of its content
+// that matched
+type SearchMatch struct {
+	Message Message
+	Snippet Snippet
+}
+
+// SearchMessageSnippets is SearchMessages with a snippet of each result,
+// the snippets have no highlights if the search provider is not a
+// MessageHighlighter
+func (u *User) SearchMessageSnippets(server, channel, q string, offset, limit int) ([]SearchMatch, uint64, error) {
+	messages, total, err := u.SearchMessages(server, channel, q, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	highlighter, _ := u.messageIndex.(MessageHighlighter)
+	matches := make([]SearchMatch, len(messages))
+	for i, message := range messages {
+		matches[i].Message = message
+		if highlighter != nil {
+			matches[i].Snippet = highlighter.Highlight(message.Content, q)
+		} else {
+			matches[i].Snippet = Snippet{Text: message.Content}
+		}
+	}
+
+	return matches, total, nil
+}
+
+// Mention is a logged message that mentioned the user
+type Mention struct {
+	Server  string
+	Channel string
+	Message Message
+}
+
+// GetMentions returns up to limit messages across all channels that
+// mentioned the user, newest first. Whether a message is a mention is
+// decided when it gets logged, using the nick the user had at that time,
+// so changing nick does not affect older messages. It fails with
+// ErrMentionsUnsupported if the search provider can not find mentions.
+func (u *User) GetMentions(limit int) ([]Mention, error) {
+	searcher, ok := u.messageIndex.(MentionSearcher)
+	if !ok {
+		return nil, ErrMentionsUnsupported
+	}
+
+	channels, err := u.GetChannels()
 	if err != nil {
 		return nil, err
 	}
 
-	return u.messageLog.GetMessagesByID(server, channel, ids)
+	mentions := []Mention{}
+	for _, channel := range channels {
+		ids, err := searcher.SearchMentions(channel.Server, channel.Name, limit)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		messages, err := u.messageLog.GetMessagesByID(channel.Server, channel.Name, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, message := range messages {
+			mentions = append(mentions, Mention{
+				Server:  channel.Server,
+				Channel: channel.Name,
+				Message: message,
+			})
+		}
+	}
+
+	// Newest first, message IDs are ordered by time
+	sort.Slice(mentions, func(i, j int) bool {
+		return mentions[i].Message.ID > mentions[j].Message.ID
+	})
+	if len(mentions) > limit {
+		mentions = mentions[:limit]
+	}
+
+	return mentions, nil
 }
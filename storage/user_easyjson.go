@@ -38,6 +38,18 @@ func easyjson9e1087fdDecodeGithubComKhliengDispatchStorage(in *jlexer.Lexer, out
 		switch key {
 		case "coloredNicks":
 			out.ColoredNicks = bool(in.Bool())
+		case "timezone":
+			out.Timezone = string(in.String())
+		case "timeFormat":
+			out.TimeFormat = string(in.String())
+		case "quitMessage":
+			out.QuitMessage = string(in.String())
+		case "partMessage":
+			out.PartMessage = string(in.String())
+		case "readReceipts":
+			out.ReadReceipts = bool(in.Bool())
+		case "showMOTD":
+			out.ShowMOTD = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -58,6 +70,66 @@ func easyjson9e1087fdEncodeGithubComKhliengDispatchStorage(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.Bool(bool(in.ColoredNicks))
 	}
+	if in.Timezone != "" {
+		const prefix string = ",\"timezone\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Timezone))
+	}
+	if in.TimeFormat != "" {
+		const prefix string = ",\"timeFormat\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.TimeFormat))
+	}
+	if in.QuitMessage != "" {
+		const prefix string = ",\"quitMessage\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.QuitMessage))
+	}
+	if in.PartMessage != "" {
+		const prefix string = ",\"partMessage\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.PartMessage))
+	}
+	if in.ReadReceipts {
+		const prefix string = ",\"readReceipts\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.ReadReceipts))
+	}
+	if in.ShowMOTD != "" {
+		const prefix string = ",\"showMOTD\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ShowMOTD))
+	}
 	out.RawByte('}')
 }
 
@@ -4,13 +4,17 @@ import (
 	"io/ioutil"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/khlieng/dispatch/storage"
 	"github.com/khlieng/dispatch/storage/bleve"
 	"github.com/khlieng/dispatch/storage/boltdb"
 	"github.com/kjk/betterguid"
 	"github.com/stretchr/testify/assert"
+	bolt "go.etcd.io/bbolt"
 )
 
 func tempdir() string {
@@ -76,6 +80,44 @@ func TestUser(t *testing.T) {
 	servers, err = user.GetServers()
 	assert.Equal(t, "cake", servers[0].Name)
 
+	user.SetServerCommands(srv.Host, []string{"MODE bob +B", "JOIN #bots"})
+	servers, err = user.GetServers()
+	assert.Equal(t, []string{"MODE bob +B", "JOIN #bots"}, servers[0].Commands)
+	assert.Equal(t, "cake", servers[0].Name)
+
+	assert.Nil(t, user.SetServerOper(srv.Host, "admin", "hunter2"))
+	server, err := user.GetServer(srv.Host)
+	assert.Nil(t, err)
+	assert.Equal(t, "admin", server.OperName)
+	assert.Empty(t, server.OperPassword)
+	assert.Equal(t, []string{"MODE bob +B", "JOIN #bots"}, server.Commands)
+
+	// The password is only kept in memory without encryption
+	name, password, err := user.GetServerOper(srv.Host)
+	assert.Nil(t, err)
+	assert.Equal(t, "admin", name)
+	assert.Equal(t, "hunter2", password)
+
+	assert.Nil(t, user.SetServerOper(srv.Host, "", "hunter2"))
+	server, _ = user.GetServer(srv.Host)
+	assert.Empty(t, server.OperName)
+	assert.Empty(t, server.OperPassword)
+	name, password, _ = user.GetServerOper(srv.Host)
+	assert.Empty(t, name)
+	assert.Empty(t, password)
+
+	assert.Nil(t, user.SetServerClientTags(srv.Host, []string{"+vendor/*"}, []string{"+vendor/bot=1"}))
+	server, _ = user.GetServer(srv.Host)
+	assert.Equal(t, []string{"+vendor/*"}, server.ForwardTags)
+	assert.Equal(t, []string{"+vendor/bot=1"}, server.SendTags)
+	assert.Equal(t, storage.ErrInvalidClientTag, user.SetServerClientTags(srv.Host, []string{"account"}, nil))
+	assert.Equal(t, storage.ErrInvalidClientTag, user.SetServerClientTags(srv.Host, nil, []string{"+bad tag"}))
+
+	assert.Nil(t, user.SetServerTLS(srv.Host, "irc.example.com", []string{"irc"}))
+	server, _ = user.GetServer(srv.Host)
+	assert.Equal(t, "irc.example.com", server.TLSServerName)
+	assert.Equal(t, []string{"irc"}, server.ALPN)
+
 	user.RemoveChannel(srv.Host, chan1.Name)
 	channels, err = user.GetChannels()
 	assert.Len(t, channels, 1)
@@ -97,6 +139,18 @@ func TestUser(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Len(t, openDMs, 0)
 
+	err = user.SetAlias("j", "/join $1")
+	assert.Nil(t, err)
+	user.SetAlias("ns", "/msg NickServ $*")
+	aliases, err := user.GetAliases()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"j": "/join $1", "ns": "/msg NickServ $*"}, aliases)
+	err = user.RemoveAlias("ns")
+	assert.Nil(t, err)
+	aliases, err = user.GetAliases()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"j": "/join $1"}, aliases)
+
 	settings := user.GetClientSettings()
 	assert.NotNil(t, settings)
 	assert.Equal(t, storage.DefaultClientSettings(), settings)
@@ -107,9 +161,27 @@ func TestUser(t *testing.T) {
 	assert.Equal(t, settings, user.GetClientSettings())
 	assert.NotEqual(t, settings, storage.DefaultClientSettings())
 
+	err = user.UnmarshalClientSettingsJSON([]byte(`{"timezone":"Europe/Oslo","timeFormat":"15:04"}`))
+	assert.Nil(t, err)
+	settings = user.GetClientSettings()
+	assert.Equal(t, "Europe/Oslo", settings.Timezone)
+	assert.Equal(t, "15:04", settings.TimeFormat)
+	assert.Equal(t, "13:00", user.FormatTime(time.Date(2021, 6, 1, 11, 0, 0, 0, time.UTC)))
+
+	err = user.UnmarshalClientSettingsJSON([]byte(`{"quitMessage":"Dispatch {version}","partMessage":"bye"}`))
+	assert.Nil(t, err)
+	settings = user.GetClientSettings()
+	assert.Equal(t, "Dispatch {version}", settings.QuitMessage)
+	assert.Equal(t, "bye", settings.PartMessage)
+	assert.Equal(t, "Europe/Oslo", settings.Timezone)
+
+	err = user.UnmarshalClientSettingsJSON([]byte(`{"timezone":"Not/AZone"}`))
+	assert.NotNil(t, err)
+	assert.Equal(t, "Europe/Oslo", user.GetClientSettings().Timezone)
+
 	user.AddOpenDM(srv.Host, "cake")
 
-	user.Remove()
+	assert.Nil(t, user.Remove())
 	_, err = os.Stat(storage.Path.User(user.Username))
 	assert.True(t, os.IsNotExist(err))
 
@@ -117,6 +189,10 @@ func TestUser(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Len(t, openDMs, 0)
 
+	aliases, err = user.GetAliases()
+	assert.Nil(t, err)
+	assert.Len(t, aliases, 0)
+
 	users, err = storage.LoadUsers(db)
 	assert.Nil(t, err)
 
@@ -125,6 +201,101 @@ func TestUser(t *testing.T) {
 	}
 }
 
+func TestEncryptedServerOper(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+	defer db.Close()
+
+	os.MkdirAll(storage.Path.User("oper"), 0700)
+	key, err := storage.LoadEncryptionKey("oper", "hunter2", "")
+	assert.Nil(t, err)
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return storage.NewEncryptedMessageStore(db, key)
+	}
+	storage.GetMessageSearchProvider = func(_ *storage.User) (storage.MessageSearchProvider, error) {
+		return nil, nil
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+	assert.Nil(t, user.AddServer(&storage.Server{Host: "host.com"}))
+	assert.Nil(t, user.SetServerOper("host.com", "admin", "secret"))
+
+	server, err := db.GetServer(user, "host.com")
+	assert.Nil(t, err)
+	assert.Equal(t, "admin", server.OperName)
+	assert.NotEmpty(t, server.OperPassword)
+	assert.NotContains(t, server.OperPassword, "secret")
+
+	// Encrypted passwords are persisted
+	users, err := storage.LoadUsers(db)
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	name, password, err := users[0].GetServerOper("host.com")
+	assert.Nil(t, err)
+	assert.Equal(t, "admin", name)
+	assert.Equal(t, "secret", password)
+}
+
+func TestDatabaseLocked(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+
+	_, err = boltdb.New(storage.Path.Database())
+	assert.Equal(t, boltdb.ErrLocked, err)
+
+	db.Close()
+	db, err = boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+	db.Close()
+}
+
+func TestShortRecords(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+	db.Close()
+
+	raw, err := bolt.Open(storage.Path.Database(), 0600, nil)
+	assert.Nil(t, err)
+	err = raw.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte("Users"))
+
+		// A user saved before quit and part messages were added, it has an
+		// ID, username, no client settings, last IP, timezone, time format,
+		// and is an admin that is always on
+		old := []byte{1, 0, 0, 0, 0, 0, 0, 0, 1, '1', 0, 0, 0, 0, 1, 1}
+		if err := b.Put([]byte{0, 0, 0, 0, 0, 0, 0, 1}, old); err != nil {
+			return err
+		}
+
+		// The username claims to be longer than the record
+		corrupt := []byte{2, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0x7f, 'x'}
+		return b.Put([]byte{0, 0, 0, 0, 0, 0, 0, 2}, corrupt)
+	})
+	assert.Nil(t, err)
+	raw.Close()
+
+	db, err = boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+	defer db.Close()
+
+	users, err := db.GetUsers()
+	assert.Nil(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, uint64(1), users[0].ID)
+	assert.Equal(t, "1", users[0].Username)
+	assert.True(t, users[0].IsAdmin())
+	assert.True(t, users[0].IsAlwaysOn())
+	assert.Equal(t, uint64(2), users[1].ID)
+}
+
 func TestMessages(t *testing.T) {
 	storage.Initialize(tempdir(), "", "")
 
@@ -153,7 +324,7 @@ func TestMessages(t *testing.T) {
 	assert.False(t, hasMore)
 	assert.Len(t, messages, 0)
 
-	messages, err = user.SearchMessages("irc.freenode.net", "#go-nuts", "message")
+	messages, _, err = user.SearchMessages("irc.freenode.net", "#go-nuts", "message", 0, 10)
 	assert.Nil(t, err)
 	assert.Len(t, messages, 0)
 
@@ -199,6 +370,30 @@ func TestMessages(t *testing.T) {
 	assert.False(t, hasMore)
 	assert.Len(t, messages, 5)
 
+	messages, err = user.GetMessageContext("irc.freenode.net", "#go-nuts", ids[2], 1)
+	assert.Nil(t, err)
+	assert.Len(t, messages, 3)
+	assert.Equal(t, ids[1], messages[0].ID)
+	assert.Equal(t, ids[2], messages[1].ID)
+	assert.Equal(t, "message3", messages[2].Content)
+
+	messages, err = user.GetMessageContext("irc.freenode.net", "#go-nuts", ids[0], 2)
+	assert.Nil(t, err)
+	assert.Len(t, messages, 3)
+	assert.Equal(t, "message0", messages[0].Content)
+	assert.Equal(t, "message2", messages[2].Content)
+
+	messages, err = user.GetMessageContext("irc.freenode.net", "#go-nuts", ids[4], 10)
+	assert.Nil(t, err)
+	assert.Len(t, messages, 5)
+	assert.Equal(t, "message4", messages[4].Content)
+
+	_, err = user.GetMessageContext("irc.freenode.net", "#go-nuts", betterguid.New(), 10)
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	_, err = user.GetMessageContext("irc.freenode.net", "#nope", ids[0], 10)
+	assert.Equal(t, storage.ErrNotFound, err)
+
 	messages, hasMore, err = user.GetLastMessages("irc.freenode.net", "#go-nuts", 4)
 	assert.Equal(t, "message1", messages[0].Content)
 	assert.Equal(t, "message4", messages[3].Content)
@@ -206,10 +401,24 @@ func TestMessages(t *testing.T) {
 	assert.True(t, hasMore)
 	assert.Len(t, messages, 4)
 
-	messages, err = user.SearchMessages("irc.freenode.net", "#go-nuts", "message")
+	messages, _, err = user.SearchMessages("irc.freenode.net", "#go-nuts", "message", 0, 10)
 	assert.Nil(t, err)
 	assert.True(t, len(messages) > 0)
 
+	err = user.LogMessage(&storage.Message{
+		Server:  "irc.freenode.net",
+		From:    "nick2",
+		Account: "acc",
+		To:      "#go-nuts",
+		Content: "message from account",
+	})
+	assert.Nil(t, err)
+
+	messages, _, err = user.SearchMessages("irc.freenode.net", "#go-nuts", "account:acc message", 0, 10)
+	assert.Nil(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "acc", messages[0].Account)
+
 	user.LogEvent("irc.freenode.net", "join", []string{"bob"}, "#go-nuts")
 	messages, hasMore, err = user.GetLastMessages("irc.freenode.net", "#go-nuts", 1)
 	assert.Zero(t, messages[0].Content)
@@ -249,3 +458,772 @@ func TestMessages(t *testing.T) {
 
 	db.Close()
 }
+
+func TestTopics(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return db, nil
+	}
+	storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
+		return bleve.New(storage.Path.Index(user.Username))
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+
+	topics, hasMore, err := user.GetLastTopics("irc.freenode.net", "#go-nuts", 10)
+	assert.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Len(t, topics, 0)
+
+	for i := 0; i < 5; i++ {
+		err = user.LogTopic("irc.freenode.net", "#go-nuts", "nick"+strconv.Itoa(i), "topic"+strconv.Itoa(i))
+		assert.Nil(t, err)
+	}
+	user.LogTopic("irc.freenode.net", "#other", "nick", "other")
+
+	topics, hasMore, err = user.GetLastTopics("irc.freenode.net", "#go-nuts", 10)
+	assert.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Len(t, topics, 5)
+	assert.Equal(t, "topic0", topics[0].Topic)
+	assert.Equal(t, "nick0", topics[0].Nick)
+	assert.Equal(t, "topic4", topics[4].Topic)
+	assert.Equal(t, "#go-nuts", topics[4].Channel)
+	assert.NotZero(t, topics[4].Time)
+
+	topics, hasMore, err = user.GetLastTopics("irc.freenode.net", "#go-nuts", 2)
+	assert.Nil(t, err)
+	assert.True(t, hasMore)
+	assert.Len(t, topics, 2)
+	assert.Equal(t, "topic3", topics[0].Topic)
+	assert.Equal(t, "topic4", topics[1].Topic)
+
+	topics, hasMore, err = user.GetTopics("irc.freenode.net", "#go-nuts", 2, topics[0].ID)
+	assert.Nil(t, err)
+	assert.True(t, hasMore)
+	assert.Len(t, topics, 2)
+	assert.Equal(t, "topic1", topics[0].Topic)
+	assert.Equal(t, "topic2", topics[1].Topic)
+
+	topics, hasMore, err = user.GetTopics("irc.freenode.net", "#go-nuts", 2, topics[0].ID)
+	assert.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Len(t, topics, 1)
+	assert.Equal(t, "topic0", topics[0].Topic)
+
+	db.Close()
+}
+
+func TestLogReplayedMessages(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return db, nil
+	}
+	storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
+		return bleve.New(storage.Path.Index(user.Username))
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+
+	user.LogMessage(&storage.Message{
+		Server:  "irc.freenode.net",
+		From:    "nick",
+		To:      "#go-nuts",
+		Content: "live",
+	})
+
+	hourAgo := time.Now().Add(-time.Hour)
+	err = user.LogMessages([]*storage.Message{
+		{
+			ID:      storage.MessageIDAt(hourAgo),
+			Server:  "irc.freenode.net",
+			From:    "nick",
+			To:      "#go-nuts",
+			Content: "replayed",
+			Time:    hourAgo.Unix(),
+			MsgID:   "abc",
+			ReplyTo: "parent",
+		},
+	})
+	assert.Nil(t, err)
+
+	messages, _, err := user.GetLastMessages("irc.freenode.net", "#go-nuts", 10)
+	assert.Nil(t, err)
+	assert.Len(t, messages, 2)
+	assert.Equal(t, "replayed", messages[0].Content)
+	assert.Equal(t, "abc", messages[0].MsgID)
+	assert.Equal(t, "parent", messages[0].ReplyTo)
+	assert.Equal(t, "live", messages[1].Content)
+
+	messages, _, err = user.SearchMessages("irc.freenode.net", "#go-nuts", "replayed", 0, 10)
+	assert.Nil(t, err)
+	assert.Len(t, messages, 1)
+}
+
+func TestReindex(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return db, nil
+	}
+	storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
+		return bleve.New(storage.Path.Index(user.Username))
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+
+	// Imported straight into the message store, so not indexed
+	hourAgo := time.Now().Add(-time.Hour)
+	assert.Nil(t, db.LogMessages([]*storage.Message{
+		{
+			ID:      storage.MessageIDAt(hourAgo),
+			Server:  "irc.freenode.net",
+			From:    "nick",
+			To:      "#go-nuts",
+			Content: "imported message",
+			Time:    hourAgo.Unix(),
+		},
+		{
+			ID:      storage.MessageIDAt(hourAgo.Add(time.Minute)),
+			Server:  "irc.freenode.net",
+			From:    "nick",
+			To:      "other",
+			Content: "imported dm",
+			Time:    hourAgo.Unix(),
+		},
+		{
+			ID:     storage.MessageIDAt(hourAgo.Add(2 * time.Minute)),
+			Server: "irc.freenode.net",
+			To:     "#go-nuts",
+			Events: []storage.Event{{Type: "join", Params: []string{"nick"}}},
+		},
+	}))
+
+	messages, _, err := user.SearchMessages("irc.freenode.net", "#go-nuts", "imported", 0, 10)
+	assert.Nil(t, err)
+	assert.Empty(t, messages)
+
+	count, err := user.Reindex()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	messages, _, err = user.SearchMessages("irc.freenode.net", "#go-nuts", "imported", 0, 10)
+	assert.Nil(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "imported message", messages[0].Content)
+
+	messages, _, err = user.SearchMessages("irc.freenode.net", "other", "imported", 0, 10)
+	assert.Nil(t, err)
+	assert.Len(t, messages, 1)
+}
+
+func TestGetMessagesAt(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return db, nil
+	}
+	storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
+		return bleve.New(storage.Path.Index(user.Username))
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+
+	day := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	times := []time.Time{
+		day.Add(-time.Minute),                  // Feb 29 23:59
+		day,                                    // Mar 1 00:00
+		day.Add(23*time.Hour + 59*time.Minute), // Mar 1 23:59
+		day.Add(72*time.Hour + 12*time.Hour),   // Mar 4 12:00
+		day.Add(72*time.Hour + 13*time.Hour),   // Mar 4 13:00
+	}
+
+	var messages []*storage.Message
+	for i, t := range times {
+		messages = append(messages, &storage.Message{
+			ID:      storage.MessageIDAt(t),
+			Server:  "irc.freenode.net",
+			From:    "nick",
+			To:      "#go-nuts",
+			Content: strconv.Itoa(i),
+			Time:    t.Unix(),
+		})
+	}
+	assert.Nil(t, user.LogMessages(messages))
+
+	res, id, err := user.GetMessagesAt("irc.freenode.net", "#go-nuts", day, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, messages[1].ID, id)
+	assert.Equal(t, "0", res[0].Content)
+	assert.Equal(t, "1", res[1].Content)
+	assert.Equal(t, "2", res[2].Content)
+
+	res, id, err = user.GetMessagesAt("irc.freenode.net", "#go-nuts", day.Add(-24*time.Hour), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, messages[0].ID, id)
+	assert.Equal(t, "0", res[0].Content)
+
+	// Mar 2 has no messages, the first one on Mar 4 is used
+	res, id, err = user.GetMessagesAt("irc.freenode.net", "#go-nuts", day.Add(24*time.Hour), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, messages[3].ID, id)
+	assert.Equal(t, "2", res[0].Content)
+	assert.Equal(t, "3", res[1].Content)
+	assert.Equal(t, "4", res[2].Content)
+
+	_, _, err = user.GetMessagesAt("irc.freenode.net", "#go-nuts", day.Add(5*24*time.Hour), 1)
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	_, _, err = user.GetMessagesAt("irc.freenode.net", "#nope", day, 1)
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	db.Close()
+}
+
+func TestSearchSnippets(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return db, nil
+	}
+	storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
+		return bleve.New(storage.Path.Index(user.Username))
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+	os.MkdirAll(storage.Path.User(user.Username), 0700)
+
+	long := strings.Repeat("lorem ipsum ", 30) + "the quick brown fox"
+	for i, content := range []string{"The Quick brown fox jumps over the lazy cat", "no match here", long} {
+		err = user.LogMessage(&storage.Message{
+			ID:      betterguid.New(),
+			Server:  "irc.freenode.net",
+			From:    "nick",
+			To:      "#go-nuts",
+			Content: content,
+		})
+		assert.Nil(t, err, i)
+	}
+
+	matches, _, err := user.SearchMessageSnippets("irc.freenode.net", "#go-nuts", "quick foxes", 0, 10)
+	assert.Nil(t, err)
+	assert.Len(t, matches, 2)
+
+	for _, match := range matches {
+		content := match.Message.Content
+		snippet := match.Snippet
+		assert.Equal(t, content[snippet.Start:snippet.Start+len(snippet.Text)], snippet.Text)
+		assert.Len(t, snippet.Highlights, 2)
+
+		words := []string{}
+		for _, h := range snippet.Highlights {
+			words = append(words, snippet.Text[h.Start:h.End])
+		}
+
+		if content == long {
+			assert.Equal(t, []string{"quick", "fox"}, words)
+			assert.Equal(t, len(snippet.Text)-len("quick brown fox"), snippet.Highlights[0].Start)
+			assert.True(t, snippet.Start > 0)
+			assert.True(t, strings.HasSuffix(snippet.Text, "the quick brown fox"))
+		} else {
+			assert.Equal(t, []string{"Quick", "fox"}, words)
+			assert.Equal(t, []storage.Highlight{{Start: 4, End: 9}, {Start: 16, End: 19}}, snippet.Highlights)
+			assert.Equal(t, content, snippet.Text)
+		}
+	}
+
+	// Fuzzy matches get highlighted as well
+	matches, _, err = user.SearchMessageSnippets("irc.freenode.net", "#go-nuts", "lazzy", 0, 10)
+	assert.Nil(t, err)
+	assert.Len(t, matches, 1)
+	assert.Equal(t, []storage.Highlight{{Start: 35, End: 39}}, matches[0].Snippet.Highlights)
+
+	// The snippet is the fragment matching the most terms, matches outside
+	// of it are not highlighted
+	index, err := bleve.New(storage.Path.Index("highlight"))
+	assert.Nil(t, err)
+	defer index.Close()
+
+	content := "a fox " + strings.Repeat("lorem ipsum ", 30) + "the quick brown fox"
+	snippet := index.Highlight(content, "quick fox")
+	assert.True(t, strings.HasSuffix(snippet.Text, "the quick brown fox"))
+	assert.Equal(t, content[snippet.Start:], snippet.Text)
+	offset := len(snippet.Text) - len("quick brown fox")
+	assert.Equal(t, []storage.Highlight{
+		{Start: offset, End: offset + 5},
+		{Start: offset + 12, End: offset + 15},
+	}, snippet.Highlights)
+}
+
+func TestSearchPagination(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return db, nil
+	}
+	storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
+		return bleve.New(storage.Path.Index(user.Username))
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+	os.MkdirAll(storage.Path.User(user.Username), 0700)
+
+	ids := []string{}
+	for i := 0; i < 25; i++ {
+		id := betterguid.New()
+		ids = append([]string{id}, ids...)
+
+		// Repeating the term gives the messages different scores
+		err = user.LogMessage(&storage.Message{
+			ID:      id,
+			Server:  "irc.freenode.net",
+			From:    "nick",
+			To:      "#go-nuts",
+			Content: strings.Repeat("paged ", i%4+1) + strconv.Itoa(i),
+		})
+		assert.Nil(t, err)
+	}
+	user.LogMessage(&storage.Message{
+		ID:      betterguid.New(),
+		Server:  "irc.freenode.net",
+		From:    "nick",
+		To:      "#go-nuts",
+		Content: "unrelated",
+	})
+
+	got := []string{}
+	for offset := 0; offset < 30; offset += 10 {
+		messages, total, err := user.SearchMessages("irc.freenode.net", "#go-nuts", "paged", offset, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(25), total)
+
+		if offset < 20 {
+			assert.Len(t, messages, 10)
+		} else {
+			assert.Len(t, messages, 5)
+		}
+		for _, message := range messages {
+			got = append(got, message.ID)
+		}
+	}
+	assert.Equal(t, ids, got)
+
+	messages, total, err := user.SearchMessages("irc.freenode.net", "#go-nuts", "paged", 25, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(25), total)
+	assert.Len(t, messages, 0)
+
+	matches, total, err := user.SearchMessageSnippets("irc.freenode.net", "#go-nuts", "paged", 9, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(25), total)
+	assert.Len(t, matches, 2)
+	assert.Equal(t, ids[9], matches[0].Message.ID)
+	assert.Equal(t, ids[10], matches[1].Message.ID)
+}
+
+func TestOrder(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return db, nil
+	}
+	storage.GetMessageSearchProvider = func(_ *storage.User) (storage.MessageSearchProvider, error) {
+		return nil, nil
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+
+	user.AddServer(&storage.Server{Name: "freenode", Host: "irc.freenode.net"})
+	user.AddServer(&storage.Server{Name: "oftc", Host: "irc.oftc.net"})
+	user.AddChannel(&storage.Channel{Server: "irc.freenode.net", Name: "#go-nuts"})
+	user.AddChannel(&storage.Channel{Server: "irc.freenode.net", Name: "#dispatch"})
+
+	err = user.SetOrder([]storage.Placement{
+		{Server: "irc.oftc.net", Order: 0, Group: "work"},
+		{Server: "irc.freenode.net", Order: 1},
+		{Server: "irc.freenode.net", Channel: "#dispatch", Order: 0},
+		{Server: "irc.freenode.net", Channel: "#go-nuts", Order: 1, Group: "go"},
+		{Server: "irc.gone.net", Order: 2},
+	})
+	assert.Nil(t, err)
+
+	// Rejoining keeps the channels place
+	user.AddChannel(&storage.Channel{Server: "irc.freenode.net", Name: "#go-nuts"})
+
+	users, err := storage.LoadUsers(db)
+	assert.Nil(t, err)
+	user = users[0]
+
+	servers, err := user.GetServers()
+	assert.Nil(t, err)
+	assert.Len(t, servers, 2)
+	assert.Equal(t, int32(1), servers[0].Order)
+	assert.Equal(t, "", servers[0].Group)
+	assert.Equal(t, int32(0), servers[1].Order)
+	assert.Equal(t, "work", servers[1].Group)
+
+	channels, err := user.GetChannels()
+	assert.Nil(t, err)
+	assert.Len(t, channels, 2)
+	assert.Equal(t, "#dispatch", channels[0].Name)
+	assert.Equal(t, int32(0), channels[0].Order)
+	assert.Equal(t, "#go-nuts", channels[1].Name)
+	assert.Equal(t, int32(1), channels[1].Order)
+	assert.Equal(t, "go", channels[1].Group)
+}
+
+func TestAppearance(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return db, nil
+	}
+	storage.GetMessageSearchProvider = func(_ *storage.User) (storage.MessageSearchProvider, error) {
+		return nil, nil
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+
+	user.AddServer(&storage.Server{Name: "freenode", Host: "irc.freenode.net"})
+	user.AddChannel(&storage.Channel{Server: "irc.freenode.net", Name: "#go-nuts"})
+
+	assert.Nil(t, user.SetAppearance(storage.Appearance{
+		Server: "irc.freenode.net",
+		Color:  "#ff8800",
+		Label:  "FN",
+	}))
+	assert.Nil(t, user.SetAppearance(storage.Appearance{
+		Server:  "irc.freenode.net",
+		Channel: "#go-nuts",
+		Color:   "#0af",
+		Label:   "go",
+	}))
+	assert.Equal(t, storage.ErrNotFound, user.SetAppearance(storage.Appearance{
+		Server: "irc.gone.net",
+		Color:  "#fff",
+	}))
+	assert.Equal(t, storage.ErrNotFound, user.SetAppearance(storage.Appearance{
+		Server:  "irc.freenode.net",
+		Channel: "#gone",
+	}))
+
+	for _, color := range []string{"red", "#ff", "#ff88001", "#gggggg", "ff8800"} {
+		assert.Equal(t, storage.ErrInvalidColor, user.SetAppearance(storage.Appearance{
+			Server: "irc.freenode.net",
+			Color:  color,
+		}), color)
+	}
+	assert.Equal(t, storage.ErrLabelTooLong, user.SetAppearance(storage.Appearance{
+		Server: "irc.freenode.net",
+		Label:  "this label is way too long",
+	}))
+
+	// Rejoining keeps the channels appearance
+	user.AddChannel(&storage.Channel{Server: "irc.freenode.net", Name: "#go-nuts"})
+
+	users, err := storage.LoadUsers(db)
+	assert.Nil(t, err)
+	user = users[0]
+
+	server, err := user.GetServer("irc.freenode.net")
+	assert.Nil(t, err)
+	assert.Equal(t, "#ff8800", server.Color)
+	assert.Equal(t, "FN", server.Label)
+
+	channels, err := user.GetChannels()
+	assert.Nil(t, err)
+	assert.Len(t, channels, 1)
+	assert.Equal(t, "#0af", channels[0].Color)
+	assert.Equal(t, "go", channels[0].Label)
+
+	assert.Nil(t, user.SetAppearance(storage.Appearance{Server: "irc.freenode.net"}))
+	server, err = user.GetServer("irc.freenode.net")
+	assert.Nil(t, err)
+	assert.Equal(t, "", server.Color)
+	assert.Equal(t, "", server.Label)
+	assert.Equal(t, "freenode", server.Name)
+}
+
+func TestLimits(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return db, nil
+	}
+	storage.GetMessageSearchProvider = func(_ *storage.User) (storage.MessageSearchProvider, error) {
+		return nil, nil
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+
+	defer func(getLimits func(*storage.User) storage.Limits) {
+		storage.GetLimits = getLimits
+	}(storage.GetLimits)
+	storage.GetLimits = func(_ *storage.User) storage.Limits {
+		return storage.Limits{Servers: 1, Channels: 2}
+	}
+
+	assert.Nil(t, user.AddServer(&storage.Server{Host: "irc.freenode.net"}))
+	assert.Nil(t, user.AddServer(&storage.Server{Host: "irc.freenode.net", Nick: "changed"}))
+	assert.Equal(t, storage.ErrServerLimit, user.AddServer(&storage.Server{Host: "irc.oftc.net"}))
+
+	assert.Nil(t, user.AddChannel(&storage.Channel{Server: "irc.freenode.net", Name: "#go-nuts"}))
+	assert.Nil(t, user.AddChannel(&storage.Channel{Server: "irc.freenode.net", Name: "#dispatch"}))
+	assert.Nil(t, user.AddChannel(&storage.Channel{Server: "irc.freenode.net", Name: "#dispatch"}))
+	assert.Nil(t, user.AddChannel(&storage.Channel{Server: "irc.freenode.net", Name: "#Dispatch"}))
+	assert.Equal(t, storage.ErrChannelLimit, user.AddChannel(&storage.Channel{Server: "irc.freenode.net", Name: "#go"}))
+
+	servers, _ := user.GetServers()
+	assert.Len(t, servers, 1)
+	channels, _ := user.GetChannels()
+	assert.Len(t, channels, 2)
+
+	// Joining many channels at once can not go over the limit
+	other, err := storage.NewUser(db)
+	assert.Nil(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			other.AddServer(&storage.Server{Host: "irc" + strconv.Itoa(i) + ".net"})
+			wg.Done()
+		}(i)
+		go func(i int) {
+			other.AddChannel(&storage.Channel{Server: "irc.freenode.net", Name: "#" + strconv.Itoa(i)})
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	servers, _ = other.GetServers()
+	assert.Len(t, servers, 1)
+	channels, _ = other.GetChannels()
+	assert.Len(t, channels, 2)
+
+	storage.GetLimits = func(_ *storage.User) storage.Limits {
+		return storage.Limits{}
+	}
+	assert.Nil(t, user.AddServer(&storage.Server{Host: "irc.oftc.net"}))
+	assert.Nil(t, user.AddChannel(&storage.Channel{Server: "irc.freenode.net", Name: "#go"}))
+
+	db.Close()
+}
+
+func TestAdmin(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return db, nil
+	}
+	storage.GetMessageSearchProvider = func(_ *storage.User) (storage.MessageSearchProvider, error) {
+		return nil, nil
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+	assert.False(t, user.IsAdmin())
+
+	err = storage.SetUserAdmin(db, user.Username, true)
+	assert.Nil(t, err)
+
+	users, err := storage.LoadUsers(db)
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	assert.True(t, users[0].IsAdmin())
+
+	err = users[0].SetAdmin(false)
+	assert.Nil(t, err)
+	users, err = storage.LoadUsers(db)
+	assert.Nil(t, err)
+	assert.False(t, users[0].IsAdmin())
+
+	assert.Equal(t, storage.ErrNotFound, storage.SetUserAdmin(db, "nobody", true))
+
+	db.Close()
+}
+
+func TestMessageLimit(t *testing.T) {
+	defer func(getMessageLimit func() int) {
+		storage.GetMessageLimit = getMessageLimit
+	}(storage.GetMessageLimit)
+
+	storage.GetMessageLimit = func() int { return 0 }
+	assert.Equal(t, "unlimited message", storage.LimitMessage("unlimited message"))
+
+	storage.GetMessageLimit = func() int { return 10 }
+	assert.Equal(t, "0123456789", storage.LimitMessage("0123456789"))
+	assert.Equal(t, "0123456"+storage.TruncatedMarker, storage.LimitMessage("0123456789a"))
+	// "æ" is 2 bytes and would be split at byte 7
+	assert.Equal(t, "012345"+storage.TruncatedMarker, storage.LimitMessage("012345æ789a"))
+	// "日" is 3 bytes
+	assert.Equal(t, "0123日"+storage.TruncatedMarker, storage.LimitMessage("0123日本語"))
+	assert.Equal(t, "01234"+storage.TruncatedMarker, storage.LimitMessage("01234日本語"))
+	assert.Equal(t, "日本"+storage.TruncatedMarker, storage.LimitMessage("日本語日本語"))
+
+	storage.GetMessageLimit = func() int { return 2 }
+	assert.Equal(t, storage.TruncatedMarker, storage.LimitMessage("abc"))
+
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return db, nil
+	}
+	storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
+		return bleve.New(storage.Path.Index(user.Username))
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+
+	storage.GetMessageLimit = func() int { return 20 }
+	err = user.LogMessage(&storage.Message{
+		Server:  "irc.freenode.net",
+		From:    "nick",
+		To:      "#go-nuts",
+		Content: "short words then a hiddenword at the end",
+	})
+	assert.Nil(t, err)
+
+	messages, _, err := user.GetLastMessages("irc.freenode.net", "#go-nuts", 10)
+	assert.Nil(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "short words then "+storage.TruncatedMarker, messages[0].Content)
+
+	messages, _, err = user.SearchMessages("irc.freenode.net", "#go-nuts", "words", 0, 10)
+	assert.Nil(t, err)
+	assert.Len(t, messages, 1)
+	messages, _, err = user.SearchMessages("irc.freenode.net", "#go-nuts", "hiddenword", 0, 10)
+	assert.Nil(t, err)
+	assert.Len(t, messages, 0)
+
+	db.Close()
+}
+
+func TestAlwaysOn(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return db, nil
+	}
+	storage.GetMessageSearchProvider = func(_ *storage.User) (storage.MessageSearchProvider, error) {
+		return nil, nil
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+	assert.False(t, user.IsAlwaysOn())
+
+	assert.Nil(t, user.SetAlwaysOn(true))
+	users, err := storage.LoadUsers(db)
+	assert.Nil(t, err)
+	assert.True(t, users[0].IsAlwaysOn())
+
+	db.Close()
+}
+
+func TestRenameChannel(t *testing.T) {
+	storage.Initialize(tempdir(), "", "")
+
+	db, err := boltdb.New(storage.Path.Database())
+	assert.Nil(t, err)
+
+	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
+		return db, nil
+	}
+	storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
+		return bleve.New(storage.Path.Index(user.Username))
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+
+	assert.Nil(t, user.AddChannel(&storage.Channel{
+		Server: "irc.freenode.net",
+		Name:   "#go-nuts",
+		Color:  "blue",
+	}))
+	assert.Nil(t, user.LogMessage(&storage.Message{
+		Server:  "irc.freenode.net",
+		From:    "nick",
+		To:      "#go-nuts",
+		Content: "before the rename",
+	}))
+	assert.Nil(t, user.LogTopic("irc.freenode.net", "#go-nuts", "nick", "gophers"))
+
+	assert.Nil(t, user.RenameChannel("irc.freenode.net", "#go-nuts", "#golang"))
+
+	channels, err := user.GetChannels()
+	assert.Nil(t, err)
+	assert.Len(t, channels, 1)
+	assert.Equal(t, "#golang", channels[0].Name)
+	assert.Equal(t, "blue", channels[0].Color)
+
+	messages, _, err := user.GetMessages("irc.freenode.net", "#golang", 10, "")
+	assert.Nil(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "before the rename", messages[0].Content)
+
+	messages, _, err = user.GetMessages("irc.freenode.net", "#go-nuts", 10, "")
+	assert.Nil(t, err)
+	assert.Empty(t, messages)
+
+	topics, _, err := user.GetTopics("irc.freenode.net", "#golang", 10, "")
+	assert.Nil(t, err)
+	assert.Len(t, topics, 1)
+	assert.Equal(t, "gophers", topics[0].Topic)
+
+	messages, _, err = user.SearchMessages("irc.freenode.net", "#golang", "rename", 0, 10)
+	assert.Nil(t, err)
+	assert.Len(t, messages, 1)
+	messages, _, err = user.SearchMessages("irc.freenode.net", "#go-nuts", "rename", 0, 10)
+	assert.Nil(t, err)
+	assert.Empty(t, messages)
+}